@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/quality"
+)
+
+// runEval evaluates the search quality fixtures at the path given as the first argument, or in
+// testdata/quality, against the Manticore of the environment and returns the process exit code
+// (0 when every mode reached its minimum precision, 1 otherwise). The fixtures are indexed into
+// quality_ tables, which are dropped afterwards.
+func runEval(args []string) int {
+	path := "testdata/quality"
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	config, err := manticore.LoadHTTPConfigFromEnvironment()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "eval: %v\n", err)
+		return 1
+	}
+	config.TablePrefix = quality.TablePrefix
+	client := manticore.NewHTTPClient(*config)
+	defer client.Close()
+	if err := client.WaitForReady(30 * time.Second); err != nil {
+		fmt.Fprintf(os.Stderr, "eval: failed to connect to Manticore at %s: %v\n", config.BaseURL, err)
+		return 1
+	}
+
+	aiConfig, err := models.LoadAISearchConfigFromEnvironment()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "eval: %v\n", err)
+		return 1
+	}
+
+	reports, err := quality.Run(context.Background(), client, aiConfig, path)
+	passed := err == nil
+	for _, report := range reports {
+		report.Write(os.Stdout)
+		passed = passed && report.Passed()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "eval: %v\n", err)
+	}
+	if !passed {
+		return 1
+	}
+	return 0
+}
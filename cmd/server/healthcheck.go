@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// runHealthCheck probes the local /readyz endpoint and returns the process exit code (0 healthy, 1 unhealthy).
+// It is used as the container HEALTHCHECK so the runtime image does not need curl or wget.
+func runHealthCheck() int {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	url := fmt.Sprintf("http://127.0.0.1:%s/readyz", port)
+
+	// When serving on a Unix socket, dial it directly; the host part of the URL is ignored
+	if socketPath := os.Getenv("UNIX_SOCKET"); socketPath != "" {
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		}
+		url = "http://unix/readyz"
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "healthcheck: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "healthcheck: %s returned HTTP %d\n", url, resp.StatusCode)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+)
+
+// systemdListenFDsStart is the first file descriptor passed by systemd socket activation
+const systemdListenFDsStart = 3
+
+// createListener selects where the API server listens, in order of precedence:
+//   - a socket inherited from systemd (LISTEN_FDS/LISTEN_PID)
+//   - a Unix domain socket at UNIX_SOCKET (permissions from UNIX_SOCKET_MODE, default 0660)
+//   - TCP on PORT
+//
+// The returned description is used for startup logging.
+func createListener(port string) (net.Listener, string, error) {
+	if listener, err := systemdListener(); err != nil {
+		return nil, "", err
+	} else if listener != nil {
+		return listener, fmt.Sprintf("inherited systemd socket %s", listener.Addr()), nil
+	}
+
+	if socketPath := os.Getenv("UNIX_SOCKET"); socketPath != "" {
+		listener, err := unixSocketListener(socketPath)
+		if err != nil {
+			return nil, "", err
+		}
+		return listener, fmt.Sprintf("unix socket %s", socketPath), nil
+	}
+
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to listen on port %s: %v", port, err)
+	}
+	return listener, fmt.Sprintf("port %s", port), nil
+}
+
+// systemdListener returns the first socket passed via systemd socket activation, or nil if none was passed
+func systemdListener() (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, nil
+	}
+	if fds > 1 {
+		log.Printf("Warning: systemd passed %d sockets, only the first one is used", fds)
+	}
+
+	// Prevent child processes from inheriting the activation variables
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	file := os.NewFile(uintptr(systemdListenFDsStart), "systemd-socket")
+	defer file.Close()
+
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use systemd socket: %v", err)
+	}
+	return listener, nil
+}
+
+// unixSocketListener listens on a Unix domain socket, replacing a stale socket file left by a previous run
+func unixSocketListener(socketPath string) (net.Listener, error) {
+	mode := os.FileMode(0660)
+	if modeStr := os.Getenv("UNIX_SOCKET_MODE"); modeStr != "" {
+		parsed, err := strconv.ParseUint(modeStr, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid UNIX_SOCKET_MODE: %v", err)
+		}
+		mode = os.FileMode(parsed)
+	}
+
+	if info, err := os.Stat(socketPath); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("UNIX_SOCKET path %s exists and is not a socket", socketPath)
+		}
+		if conn, err := net.Dial("unix", socketPath); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("UNIX_SOCKET path %s is already in use", socketPath)
+		}
+		if err := os.Remove(socketPath); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %v", socketPath, err)
+		}
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on unix socket %s: %v", socketPath, err)
+	}
+
+	if err := os.Chmod(socketPath, mode); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to set permissions on %s: %v", socketPath, err)
+	}
+	return listener, nil
+}
@@ -1,20 +1,50 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/ad/manticoresearch-go/internal/analytics"
+	"github.com/ad/manticoresearch-go/internal/audit"
+	"github.com/ad/manticoresearch-go/internal/blocklist"
+	"github.com/ad/manticoresearch-go/internal/boost"
+	"github.com/ad/manticoresearch-go/internal/calibration"
+	"github.com/ad/manticoresearch-go/internal/curation"
+	"github.com/ad/manticoresearch-go/internal/deadletter"
+	"github.com/ad/manticoresearch-go/internal/docstore"
 	"github.com/ad/manticoresearch-go/internal/document"
 	"github.com/ad/manticoresearch-go/internal/handlers"
+	"github.com/ad/manticoresearch-go/internal/maintenance"
 	"github.com/ad/manticoresearch-go/internal/manticore"
 	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/querytemplate"
+	"github.com/ad/manticoresearch-go/internal/reconcile"
+	"github.com/ad/manticoresearch-go/internal/redact"
+	"github.com/ad/manticoresearch-go/internal/retention"
+	"github.com/ad/manticoresearch-go/internal/savedsearch"
+	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/internal/slo"
+	"github.com/ad/manticoresearch-go/internal/stopwords"
+	"github.com/ad/manticoresearch-go/internal/textnorm"
+	"github.com/ad/manticoresearch-go/internal/usage"
 	"github.com/ad/manticoresearch-go/internal/vectorizer"
+	"github.com/ad/manticoresearch-go/internal/webhook"
 )
 
 func main() {
+	// Health check runs before any output so container logs stay clean
+	if len(os.Args) > 1 && os.Args[1] == "healthcheck" {
+		os.Exit(runHealthCheck())
+	}
+
 	fmt.Println("Manticore Search Tester")
 
 	// Run API tests if requested
@@ -23,6 +53,11 @@ func main() {
 		return
 	}
 
+	// Evaluate search quality on the canned fixtures if requested
+	if len(os.Args) > 1 && os.Args[1] == "eval" {
+		os.Exit(runEval(os.Args[2:]))
+	}
+
 	// Load AI configuration first
 	aiConfig, err := models.LoadAISearchConfigFromEnvironment()
 	if err != nil {
@@ -34,6 +69,223 @@ func main() {
 	// Initialize application state with AI configuration
 	app := handlers.NewAppStateWithConfig(aiConfig)
 
+	// Initialize audit log for admin operations
+	auditLog, err := audit.NewFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to open audit log, falling back to in-memory audit log: %v", err)
+		auditLog, _ = audit.New("", audit.DefaultMaxRecent)
+	}
+	app.Audit = auditLog
+
+	// Optional cache of recent search results served while the circuit breaker is open
+	resultCache, err := search.NewResultCacheFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure search result cache: %v", err)
+	}
+	app.ResultCache = resultCache
+
+	// Documents kept in memory besides Manticore
+	documents, err := docstore.NewFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure document store, falling back to default limits: %v", err)
+		documents = docstore.New(docstore.DefaultMaxDocuments, docstore.DefaultMaxSize)
+	}
+	app.Documents = documents
+
+	// Candidates each hybrid search leg contributes before fusion
+	rescoreWindow, err := search.RescoreWindowFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure the rescore window, using the default: %v", err)
+	}
+	app.RescoreWindow = rescoreWindow
+
+	// Strategies retried when a full-text search matches nothing
+	relaxation, err := search.RelaxationFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure query relaxation, relaxation is disabled: %v", err)
+	}
+	app.Relaxation = relaxation
+
+	// Whether basic searches also match substrings and misspellings of indexed words
+	spellTolerance, err := search.SpellToleranceFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure spell tolerance, basic searches match exact words: %v", err)
+	}
+	app.SpellTolerance = spellTolerance
+
+	// Similarity above which dedup=true collapses near-duplicate results
+	dedupThreshold, err := search.DedupThresholdFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure the dedup threshold, using %.2f: %v", search.DefaultDedupThreshold, err)
+	}
+	app.DedupThreshold = dedupThreshold
+
+	// Stopwords removed from queries before full-text matching and vectorization
+	stopwordList, err := stopwords.FromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure stopwords, using %s: %v", strings.Join(stopwords.DefaultLanguages, ", "), err)
+		stopwordList, _ = stopwords.New(stopwords.DefaultLanguages...)
+	}
+	app.Stopwords = stopwordList
+
+	// Unicode normalization applied to queries and indexed documents alike
+	normalizer, err := textnorm.FromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure text normalization, using %s: %v", strings.Join(textnorm.DefaultSteps, ", "), err)
+		normalizer, _ = textnorm.New(textnorm.DefaultSteps...)
+	}
+	app.Normalizer = normalizer
+
+	// Score factors of documents by attribute, applied in every search mode
+	boosts, err := boost.FromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure search boosts, boosting nothing: %v", err)
+	}
+	if len(boosts) > 0 {
+		log.Printf("Search boosts: %s", boosts)
+	}
+	app.Boosts = boosts
+
+	// Score factors of documents by how they match the query, applied in every search mode
+	features, err := boost.FeaturesFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure scoring features, using %s: %v", boost.DefaultFeatures, err)
+		features = boost.DefaultFeatures
+	}
+	log.Printf("Scoring features: %s", features)
+	app.Features = features
+
+	// Documents never returned by searches; a broken list must not expose them, so it is fatal
+	blocked, err := blocklist.FromEnvironment()
+	if err != nil {
+		log.Fatalf("Invalid search blocklist configuration: %v", err)
+	}
+	if blocked != nil {
+		log.Printf("Search blocklist: %s", blocked)
+	}
+	app.Blocklist = blocked
+
+	// Score mappings by search mode, so scores are comparable across modes and pages
+	calibrated, err := calibration.FromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure score calibration, keeping raw scores: %v", err)
+	}
+	if calibrated != nil {
+		log.Printf("Score calibration: %s", calibrated)
+	}
+	app.Calibration = calibrated
+
+	// Personal data masked in documents before they are indexed or embedded; a mistyped pattern
+	// must not index the data it was meant to mask
+	redactor, err := redact.FromEnvironment()
+	if err != nil {
+		log.Fatalf("Invalid PII redaction configuration: %v", err)
+	}
+	if redactor != nil {
+		log.Printf("PII redaction enabled: %s", strings.Join(redactor.Names(), ", "))
+	}
+	app.Redactor = redactor
+
+	// Pending final rankings of progressive searches, fetched through /api/search/continue
+	app.Continuations = search.NewContinuations(search.DefaultMaxContinuations, search.DefaultContinuationTTL)
+
+	// Saved searches, run periodically to alert on new results
+	savedSearches, err := savedsearch.NewStoreFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to load saved searches, falling back to in-memory saved searches: %v", err)
+		savedSearches, _ = savedsearch.NewStore("")
+	}
+	app.SavedSearches = savedSearches
+
+	// Named query templates, run through the search endpoint's template parameter
+	templates, err := querytemplate.NewStoreFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to load query templates, falling back to in-memory templates: %v", err)
+		templates, _ = querytemplate.NewStore("")
+	}
+	app.Templates = templates
+
+	// Documents pinned to the top of, or hidden from, the results of matching queries
+	curations, err := curation.NewStoreFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to load curations, falling back to in-memory curations: %v", err)
+		curations, _ = curation.NewStore("")
+	}
+	app.Curations = curations
+
+	// Checks documents must pass before they are indexed
+	documentRules, err := document.RulesFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure document validation, using the default rules: %v", err)
+		documentRules = document.DefaultRules()
+	}
+	app.DocumentRules = documentRules
+
+	// Documents rejected by validation, listed through /api/admin/dead-letters
+	deadLetters, err := deadletter.NewFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to open dead-letter store, falling back to in-memory dead letters: %v", err)
+		deadLetters, _ = deadletter.New("", deadletter.DefaultMaxRecent)
+	}
+	app.DeadLetters = deadLetters
+
+	// Limits on the age and number of indexed documents, applied by the retention task
+	retentionPolicy, err := retention.PolicyFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure the retention policy, keeping every document: %v", err)
+	}
+	if retentionPolicy != nil {
+		log.Printf("Retention policy: %s", retentionPolicy)
+	}
+	app.Retention = retentionPolicy
+
+	// Outbound webhooks for reindexing and saved search events
+	webhooks, err := webhook.NewFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure webhooks, webhook events are disabled: %v", err)
+	}
+	app.Webhooks = webhooks
+
+	// Latencies of the search modes, alerting through webhooks while an objective is persistently over budget
+	sloConfig, err := slo.LoadConfigFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure search latency objectives, latencies are tracked without objectives: %v", err)
+		sloConfig = slo.Config{}
+	}
+	for _, objective := range sloConfig.Objectives {
+		log.Printf("Search latency objective: %s", objective)
+	}
+	app.SLO = slo.NewTracker(sloConfig, func(alert slo.Alert) {
+		event := webhook.EventSLOBurning
+		if !alert.Burning {
+			event = webhook.EventSLORecovered
+		}
+		app.Webhooks.Publish(event, alert)
+	})
+	app.SLO.Start()
+
+	// Bearer token of the admin endpoints, which are disabled without it
+	app.AdminToken = os.Getenv("ADMIN_TOKEN")
+
+	// API keys and their daily usage quotas
+	apiKeys, err := handlers.ParseAPIKeys(os.Getenv("API_KEYS"))
+	if err != nil {
+		log.Fatalf("Invalid API_KEYS: %v", err)
+	}
+	app.APIKeys = apiKeys
+	usageTracker, err := usage.NewTrackerFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure usage quotas, usage is unlimited: %v", err)
+	}
+	app.Usage = usageTracker
+
+	// Queries searched with results, suggested by GET /api/suggest/popular
+	queryAnalytics, err := analytics.NewStoreFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure query analytics, keeping %d queries: %v", analytics.DefaultMaxQueries, err)
+	}
+	app.Analytics = queryAnalytics
+
 	// Initialize Manticore HTTP client from environment
 	client, err := manticore.NewClientFromEnvironment()
 	if err != nil {
@@ -43,15 +295,65 @@ func main() {
 		app.Manticore = client
 	}
 
-	// Wait for Manticore to be ready and connect
-	log.Println("Waiting for Manticore Search to be ready...")
-	if err := app.Manticore.WaitForReady(60 * time.Second); err != nil {
-		log.Printf("Warning: Failed to connect to Manticore: %v", err)
-		log.Println("API will still start, but search functionality may be limited")
-	} else {
-		// Initialize database and index documents
-		if err := initializeDatabase(app); err != nil {
-			log.Printf("Warning: Failed to initialize database: %v", err)
+	// Wait for Manticore to be ready and connect, blocking startup unless STARTUP_MODE=background
+	startup, err := loadStartupConfig()
+	if err != nil {
+		log.Fatalf("Invalid startup configuration: %v", err)
+	}
+	orphanCleanupInterval, err := reconcile.CleanupIntervalFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure orphan cleanup: %v", err)
+		log.Println("Periodic orphan cleanup disabled")
+	}
+	maintenanceEntries, err := maintenance.EntriesFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure scheduled maintenance: %v", err)
+		log.Println("Scheduled maintenance disabled")
+	}
+	startManticore(app, startup)
+	startSavedSearches(app)
+	startOrphanCleanup(app, orphanCleanupInterval)
+	startMaintenance(app, maintenanceEntries)
+
+	// Tenants get their own tables, data directory and saved searches
+	tenants, err := handlers.ParseTenants(os.Getenv("TENANTS"))
+	if err != nil {
+		log.Fatalf("Invalid TENANTS: %v", err)
+	}
+	// Only API keys named after a tenant or granted cross-tenant access reach the tenants
+	crossTenantKeys, err := handlers.ParseCrossTenantKeys(os.Getenv("TENANT_CROSS_ACCESS_KEYS"), app.APIKeys)
+	if err != nil {
+		log.Fatalf("Invalid TENANT_CROSS_ACCESS_KEYS: %v", err)
+	}
+	app.CrossTenantKeys = crossTenantKeys
+	if len(tenants) > 0 && len(app.APIKeys) == 0 {
+		log.Printf("Warning: TENANTS is set without API_KEYS, so every /api/ request is refused; configure an API key per tenant")
+	}
+	// Collections (tenants) may use AI configurations of their own, such as another embedding model
+	collectionAIConfigs, err := models.LoadCollectionAIConfigsFromEnvironment(app.AIConfig)
+	if err != nil {
+		log.Printf("Warning: Failed to load AI collections, every tenant uses the global AI configuration: %v", err)
+	}
+	for collection := range collectionAIConfigs {
+		if !slices.Contains(tenants, collection) {
+			log.Printf("Warning: AI_COLLECTIONS_FILE configures %s, which is not in TENANTS", collection)
+		}
+	}
+	if len(tenants) > 0 {
+		app.Tenants = make(map[string]*handlers.AppState, len(tenants))
+		for _, tenant := range tenants {
+			log.Printf("Starting tenant %s", tenant)
+			aiConfig := app.AIConfig
+			if config, ok := collectionAIConfigs[tenant]; ok {
+				log.Printf("Tenant %s uses AI model %s", tenant, config.Model)
+				aiConfig = config
+			}
+			tenantApp := newTenantApp(app, tenant, aiConfig)
+			startManticore(tenantApp, startup)
+			startSavedSearches(tenantApp)
+			startOrphanCleanup(tenantApp, orphanCleanupInterval)
+			startMaintenance(tenantApp, maintenanceEntries)
+			app.Tenants[tenant] = tenantApp
 		}
 	}
 
@@ -61,62 +363,212 @@ func main() {
 		port = "8080"
 	}
 
-	// Setup routes
+	// Serve static files for web interface
+	staticDir := "./static"
+	hasStatic := true
+	if _, err := os.Stat(staticDir); os.IsNotExist(err) {
+		log.Printf("Warning: Static directory '%s' not found, creating basic API response", staticDir)
+		hasStatic = false
+	} else {
+		log.Printf("Web interface available at http://localhost:%s", port)
+	}
+
+	// Every tenant is served by routes of its own state
+	router := app.RouteTenants(func(app *handlers.AppState) http.Handler {
+		return newRouter(app, staticDir, hasStatic)
+	})
+
+	listener, listenDescription, err := createListener(port)
+	if err != nil {
+		log.Fatalf("Failed to create listener: %v", err)
+	}
+
+	log.Printf("Server starting on %s", listenDescription)
+	log.Printf("API endpoints available at:")
+	log.Printf("  - GET  /api/search")
+	log.Printf("  - GET  /api/search/continue")
+	log.Printf("  - GET  /api/status")
+	log.Printf("  - GET  /api/status/resilience")
+	log.Printf("  - GET  /api/stats/index")
+	log.Printf("  - GET  /api/stats/terms")
+	log.Printf("  - POST /api/reindex")
+	log.Printf("  - GET /api/documents/{id}")
+	log.Printf("  - POST /api/documents/{archive,restore,delete}")
+	log.Printf("  - POST /api/documents/tags")
+	log.Printf("  - GET|POST|DELETE /api/saved-searches")
+	log.Printf("  - GET|POST|DELETE /api/templates")
+	log.Printf("  - GET  /api/usage")
+	log.Printf("  - GET  /api/ws (WebSocket)")
+	log.Printf("  - GET  /api/admin/audit")
+	log.Printf("  - GET  /api/admin/dead-letters")
+	log.Printf("  - GET  /api/admin/recordings")
+	log.Printf("  - POST /api/admin/backup")
+	log.Printf("  - POST /api/admin/restore")
+	log.Printf("  - POST /api/admin/reset, /api/admin/truncate, /api/admin/optimize")
+	log.Printf("  - GET|POST /api/admin/reembed")
+
+	// Optional gRPC API alongside REST
+	if grpcPort := os.Getenv("GRPC_PORT"); grpcPort != "" {
+		grpcListener, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			log.Fatalf("Failed to listen on gRPC port %s: %v", grpcPort, err)
+		}
+		log.Printf("gRPC API (manticoresearch.v1.SearchService) starting on port %s", grpcPort)
+		go func() {
+			if err := app.NewGRPCServer().Serve(grpcListener); err != nil {
+				log.Printf("gRPC server stopped: %v", err)
+			}
+		}()
+	}
+
+	// Bound request bodies so a client can't make a handler buffer arbitrary amounts of data
+	maxBodySize, err := handlers.MaxRequestBodySizeFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: %v, using the default of %d bytes", err, maxBodySize)
+	}
+
+	log.Fatal(http.Serve(listener, app.LimitRequestBody(app.RequireAPIKey(router), maxBodySize)))
+}
+
+// startSavedSearches starts the scheduler that reruns the saved searches of app and alerts on new results
+func startSavedSearches(app *handlers.AppState) {
+	savedSearchNotifier := append(savedsearch.NewNotifierFromEnvironment(), savedsearch.NotifierFunc(func(ctx context.Context, alert savedsearch.Alert) error {
+		app.Webhooks.Publish(webhook.EventSavedSearchMatched, alert)
+		return nil
+	}))
+	savedSearchScheduler := savedsearch.NewScheduler(app.SavedSearches, app.RunSavedSearch, savedSearchNotifier, savedsearch.IntervalFromEnvironment())
+	savedSearchScheduler.Start()
+}
+
+// startOrphanCleanup starts the scheduler that deletes orphaned vector rows of app and reindexes
+// documents without one every interval; a zero interval disables it
+func startOrphanCleanup(app *handlers.AppState, interval time.Duration) {
+	if interval <= 0 || app.Manticore == nil {
+		return
+	}
+	scheduler := reconcile.NewScheduler(func() {
+		if app.Connection != nil && !app.Connection.IsReady() {
+			return
+		}
+		cleanup, err := app.CleanOrphans(false)
+		if err != nil {
+			log.Printf("Warning: Orphan cleanup failed: %v", err)
+			return
+		}
+		if cleanup.Error != "" {
+			log.Printf("Warning: Orphan cleanup failed: %s", cleanup.Error)
+		}
+	}, interval)
+	scheduler.Start()
+}
+
+// startMaintenance starts the scheduler running the maintenance tasks of app on the schedules of
+// entries. Runs of the tasks using Manticore are skipped, and reported as failed in /api/status,
+// until it is ready.
+func startMaintenance(app *handlers.AppState, entries []maintenance.Entry) {
+	if len(entries) == 0 {
+		return
+	}
+	tasks := app.MaintenanceTasks()
+	for name, task := range tasks {
+		if name == maintenance.TaskCacheEviction {
+			continue
+		}
+		tasks[name] = func(ctx context.Context) error {
+			if app.Connection != nil && !app.Connection.IsReady() {
+				return fmt.Errorf("skipped, Manticore is not ready")
+			}
+			return task(ctx)
+		}
+	}
+	app.Maintenance = maintenance.NewScheduler(entries, tasks)
+	app.Maintenance.Start()
+}
+
+// newRouter registers the endpoints served with app, and the web interface from staticDir when hasStatic is set
+func newRouter(app *handlers.AppState, staticDir string, hasStatic bool) http.Handler {
 	mux := http.NewServeMux()
 
 	// API endpoints
 	mux.HandleFunc("/api/search", app.SearchHandler)
+	mux.HandleFunc("/api/search/continue", app.SearchContinueHandler)
+	mux.HandleFunc("/api/count", app.CountHandler)
 	mux.HandleFunc("/api/status", app.StatusHandler)
+	mux.HandleFunc("/api/status/resilience", app.ResilienceHandler)
+	mux.HandleFunc("/api/stats/index", app.IndexStatsHandler)
+	mux.HandleFunc("/api/stats/terms", app.TermStatsHandler)
+	mux.HandleFunc("/api/suggest/popular", app.PopularSuggestionsHandler)
+	mux.HandleFunc("/api/ai/models", app.AIModelsHandler)
 	mux.HandleFunc("/api/reindex", app.ReindexHandler)
+	mux.HandleFunc("/api/documents/{id}", app.DocumentHandler)
+	mux.HandleFunc("/api/documents/archive", app.ArchiveDocumentHandler)
+	mux.HandleFunc("/api/documents/restore", app.RestoreDocumentHandler)
+	mux.HandleFunc("/api/documents/delete", app.DeleteDocumentHandler)
+	mux.HandleFunc("/api/documents/tags", app.DocumentTagsHandler)
+	mux.HandleFunc("/api/saved-searches", app.SavedSearchesHandler)
+	mux.HandleFunc("/api/templates", app.TemplatesHandler)
+	mux.HandleFunc("/api/usage", app.UsageHandler)
+	mux.HandleFunc("/api/ws", app.WebSocketHandler)
+	mux.HandleFunc("/api/admin/audit", app.AuditHandler)
+	mux.HandleFunc("/api/admin/dead-letters", app.DeadLettersHandler)
+	mux.HandleFunc("/api/admin/recordings", app.RecordingsHandler)
+	mux.HandleFunc("/api/admin/backup", app.BackupHandler)
+	mux.HandleFunc("/api/admin/restore", app.RestoreHandler)
+	mux.HandleFunc("/api/admin/reset", app.ResetTablesHandler)
+	mux.HandleFunc("/api/admin/truncate", app.TruncateTablesHandler)
+	mux.HandleFunc("/api/admin/optimize", app.OptimizeTablesHandler)
+	mux.HandleFunc("/api/admin/orphans", app.OrphansHandler)
+	mux.HandleFunc("/api/admin/retention", app.RetentionHandler)
+	mux.HandleFunc("/api/admin/reembed", app.ReembedHandler)
+	mux.HandleFunc("/api/admin/curations", app.CurationsHandler)
+
+	// Probe endpoints for container orchestration
+	mux.HandleFunc("/healthz", app.LivenessHandler)
+	mux.HandleFunc("/readyz", app.ReadinessHandler)
+	mux.HandleFunc("/metrics", app.MetricsHandler)
 
 	// Serve static files for web interface
-	staticDir := "./static"
-	if _, err := os.Stat(staticDir); os.IsNotExist(err) {
-		log.Printf("Warning: Static directory '%s' not found, creating basic API response", staticDir)
+	if !hasStatic {
 		// Fallback to API-only mode
 		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 			if r.URL.Path == "/" {
-				fmt.Fprintf(w, "Manticore Search Tester API\n\nAvailable endpoints:\n- GET /api/search?query=<query>&mode=<mode>&page=<page>&limit=<limit>\n- GET /api/status\n- POST /api/reindex\n\nNote: Web interface files not found in ./static directory")
+				fmt.Fprintf(w, "Manticore Search Tester API\n\nAvailable endpoints:\n- GET /api/search?query=<query>&mode=<mode>&page=<page>&limit=<limit>&sort=<relevance|updated_at>&since=<time>&tags=<tags>&tags_mode=<any|all>&progressive=<true|false>&rescore_window=<candidates>&knn_k=<neighbours>&knn_ef=<candidates>\n- GET /api/search?template=<name>&params=<json>\n- GET /api/search/continue?token=<token>&wait=<duration>\n- GET /api/count?query=<query>&filter=<field>:<value>\n- GET /api/status\n- GET /api/stats/index\n- GET /api/stats/terms?prefix=<prefix>&limit=<limit>\n- POST /api/reindex\n- GET /api/documents/<id>\n- POST /api/documents/{archive,restore,delete}?id=<id>\n- POST /api/documents/tags?id=<id>&tags=<tags>\n- GET|POST|DELETE /api/saved-searches\n- GET|POST|DELETE /api/templates\n- GET /api/ws (WebSocket streaming search)\n- POST /api/admin/backup\n- POST /api/admin/restore?name=<name>\n- POST /api/admin/{reset,truncate,optimize}?tables=<tables>&dry_run=<true|false>\n\nNote: Web interface files not found in ./static directory")
 			} else {
 				http.NotFound(w, r)
 			}
 		})
 	} else {
-		// Serve index.html for root path
-		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-			if r.URL.Path == "/" {
-				http.ServeFile(w, r, staticDir+"/index.html")
-			} else {
-				// Try to serve static file, fallback to 404
-				filePath := staticDir + r.URL.Path
-				if _, err := os.Stat(filePath); err == nil {
-					http.ServeFile(w, r, filePath)
-				} else {
-					http.NotFound(w, r)
-				}
-			}
-		})
-		log.Printf("Web interface available at http://localhost:%s", port)
+		// Serve static files with path cleaning, cache headers and SPA fallback
+		mux.Handle("/", handlers.NewStaticHandler(staticDir))
 	}
 
-	log.Printf("Server starting on port %s", port)
-	log.Printf("API endpoints available at:")
-	log.Printf("  - GET  /api/search")
-	log.Printf("  - GET  /api/status")
-	log.Printf("  - POST /api/reindex")
-
-	log.Fatal(http.ListenAndServe(":"+port, mux))
+	return mux
 }
 
-// initializeDatabase sets up the database schema and indexes documents
-func initializeDatabase(app *handlers.AppState) error {
+// initializeDatabase migrates the database schema and indexes documents according to the indexing policy.
+// The vectorizer is always trained from the data directory so vector search works when indexing is skipped.
+func initializeDatabase(app *handlers.AppState, indexingPolicy, consistencyMode string) error {
 	log.Println("Initializing database and indexing documents...")
 
-	// Get data directory
-	dataDir := os.Getenv("DATA_DIR")
-	if dataDir == "" {
-		dataDir = "./data"
+	// Upgrade the schema in place so existing documents survive the restart
+	migrateStart := time.Now()
+	migration, migrateErr := app.Manticore.MigrateSchema(app.CurrentAIConfig())
+	if migrateErr != nil || len(migration.Applied) > 0 {
+		params := map[string]interface{}{"reason": "startup", "target_version": manticore.SchemaVersion}
+		if migration != nil {
+			params["from_version"] = migration.FromVersion
+			params["to_version"] = migration.ToVersion
+			params["applied"] = migration.Applied
+		}
+		recordStartupAudit(app, "schema_migrate", params, migrateErr, migrateStart)
 	}
+	if migrateErr != nil {
+		return fmt.Errorf("failed to migrate schema: %v", migrateErr)
+	}
+	app.RestoreReembeddedAIConfig(migration.Generation)
+
+	// Get data directory
+	dataDir := app.DataDirectory()
 
 	// Load documents from data directory
 	documents, err := document.ScanDataDirectory(dataDir)
@@ -124,42 +576,144 @@ func initializeDatabase(app *handlers.AppState) error {
 		return fmt.Errorf("failed to scan data directory: %v", err)
 	}
 
+	// Documents failing validation go to the dead-letter store instead of the index
+	app.NormalizeDocuments(documents)
+	documents, _ = app.ValidateDocuments("startup", documents)
+	documents, _ = app.RetainDocuments(documents)
+
 	if len(documents) == 0 {
 		log.Println("Warning: No documents found in data directory")
+		checkConsistency(app, consistencyMode, documents, nil)
 		return nil
 	}
 
 	log.Printf("Found %d documents to index", len(documents))
+	app.EmbeddingTruncations(documents)
 
 	// Create and train vectorizer
 	vec := vectorizer.NewTFIDFVectorizer()
+	vec.SetNormalizer(app.Normalizer)
 	vectors := vec.FitTransform(documents)
 
-	// Clear existing data and create fresh schema
-	log.Println("Clearing existing data and creating fresh schema...")
-	if err := app.Manticore.ResetDatabase(); err != nil {
-		log.Printf("Warning: Failed to reset database (this is normal for first run): %v", err)
+	rebuild, err := shouldRebuildIndex(app, indexingPolicy)
+	if err != nil {
+		return err
 	}
+	if !rebuild {
+		app.Documents.Replace(documents, vectors)
+		app.Vectorizer = vec
 
-	// Create database schema using AI configuration from app state
-	if err := app.Manticore.CreateSchema(app.AIConfig); err != nil {
-		return fmt.Errorf("failed to create schema: %v", err)
+		log.Printf("Keeping existing index (STARTUP_INDEXING=%s), loaded %d documents for the vectorizer", indexingPolicy, len(documents))
+		checkConsistency(app, consistencyMode, documents, vectors)
+		return nil
+	}
+
+	// Clear existing data but keep the migrated tables
+	log.Println("Clearing existing data before reindexing...")
+	truncateStart := time.Now()
+	truncateErr := app.Manticore.TruncateTables()
+	recordStartupAudit(app, "truncate", map[string]interface{}{"reason": "startup"}, truncateErr, truncateStart)
+	if truncateErr != nil {
+		return fmt.Errorf("failed to clear existing data: %v", truncateErr)
 	}
 
 	// Index documents using new client
-	if err := app.Manticore.IndexDocuments(documents, vectors); err != nil {
-		return fmt.Errorf("failed to index documents: %v", err)
+	indexStart := time.Now()
+	indexErr := app.Manticore.IndexDocuments(documents, vectors)
+	app.IndexChanged()
+	recordStartupAudit(app, "reindex", map[string]interface{}{"reason": "startup", "data_dir": dataDir, "documents": len(documents)}, indexErr, indexStart)
+	app.PublishReindex("startup", map[string]interface{}{"data_dir": dataDir, "documents": len(documents)}, indexErr, indexStart)
+	if indexErr != nil {
+		return fmt.Errorf("failed to index documents: %v", indexErr)
 	}
 
 	// Update application state
-	app.Documents = documents
+	app.Documents.Replace(documents, vectors)
 	app.Vectorizer = vec
-	app.Vectors = vectors
+	app.LastReindex = time.Now()
 
 	log.Printf("Successfully initialized database with %d documents", len(documents))
+	checkConsistency(app, consistencyMode, documents, vectors)
 	return nil
 }
 
+// checkConsistency compares the data directory with the index, logs the differences and keeps
+// the report for the status endpoint. In repair mode missing documents are reindexed and
+// orphaned vector rows deleted. A failed check does not fail startup.
+func checkConsistency(app *handlers.AppState, mode string, documents []*models.Document, vectors [][]float64) {
+	if mode == reconcile.ModeOff {
+		return
+	}
+
+	var report *reconcile.Report
+	var err error
+	if mode == reconcile.ModeRepair {
+		start := time.Now()
+		report, err = reconcile.Repair(app.Manticore, documents, vectors)
+		if err == nil && (report.Repaired > 0 || report.RepairError != "") {
+			var repairErr error
+			if report.RepairError != "" {
+				repairErr = errors.New(report.RepairError)
+			}
+			app.IndexChanged()
+			recordStartupAudit(app, "consistency_repair", map[string]interface{}{"reason": "startup", "repaired": report.Repaired}, repairErr, start)
+		}
+	} else {
+		report, err = reconcile.Check(app.Manticore, documents)
+	}
+	if err != nil {
+		log.Printf("Warning: Failed to check index consistency: %v", err)
+		return
+	}
+
+	report.Log()
+	app.Consistency = report
+}
+
+// shouldRebuildIndex decides whether startup clears the tables and reindexes the data directory.
+// With the if-empty policy an existing non-empty documents table is kept, so documents added at
+// runtime survive restarts.
+func shouldRebuildIndex(app *handlers.AppState, indexingPolicy string) (bool, error) {
+	switch indexingPolicy {
+	case indexingPolicyAlways:
+		return true, nil
+	case indexingPolicyNever:
+		return false, nil
+	}
+
+	count, err := app.Manticore.CountDocuments("documents")
+	if err != nil {
+		// Do not risk wiping existing data because the count is temporarily unavailable
+		return false, fmt.Errorf("failed to check existing documents: %v", err)
+	}
+	if count > 0 {
+		log.Printf("Found %d documents already indexed, skipping startup reindex", count)
+		return false, nil
+	}
+
+	log.Println("Documents table is empty or missing, rebuilding index")
+	return true, nil
+}
+
+// recordStartupAudit records an admin operation performed by the server itself
+func recordStartupAudit(app *handlers.AppState, action string, params map[string]interface{}, err error, startTime time.Time) {
+	if app.Tenant != "" {
+		params["tenant"] = app.Tenant
+	}
+	entry := audit.Entry{
+		Actor:      "system",
+		Action:     action,
+		Parameters: params,
+		Outcome:    audit.OutcomeSuccess,
+		Duration:   time.Since(startTime),
+	}
+	if err != nil {
+		entry.Outcome = audit.OutcomeFailure
+		entry.Error = err.Error()
+	}
+	app.Audit.Record(entry)
+}
+
 // runAPITests runs basic API tests for debugging
 func runAPITests() {
 	fmt.Println("Running API endpoint tests...")
@@ -181,11 +735,10 @@ func runAPITests() {
 		fmt.Printf("Warning: Could not load test documents: %v\n", err)
 	} else {
 		if len(documents) > 5 {
-			app.Documents = documents[:5]
-		} else {
-			app.Documents = documents
+			documents = documents[:5]
 		}
-		fmt.Printf("Loaded %d test documents\n", len(app.Documents))
+		app.Documents.Replace(documents, nil)
+		fmt.Printf("Loaded %d test documents\n", app.Documents.Count())
 	}
 
 	fmt.Println("\nAPI tests completed!")
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/handlers"
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/reconcile"
+	"github.com/ad/manticoresearch-go/internal/warmup"
+)
+
+// Startup modes selected by STARTUP_MODE
+const (
+	startupModeBlocking   = "blocking"
+	startupModeBackground = "background"
+)
+
+// Startup indexing policies selected by STARTUP_INDEXING
+const (
+	indexingPolicyNever   = "never"
+	indexingPolicyIfEmpty = "if-empty"
+	indexingPolicyAlways  = "always"
+)
+
+// startupConfig controls how the server waits for Manticore before serving requests
+type startupConfig struct {
+	Mode          string
+	Indexing      string         // whether startup rebuilds the index: never, if-empty or always
+	WaitTimeout   time.Duration  // how long blocking mode delays serving; zero waits until the backend is ready
+	CheckInterval time.Duration  // health check interval once connected
+	Warmup        *warmup.Config // searches run after indexing, before ready; nil disables them
+	Consistency   string         // startup consistency check: off, report or repair
+}
+
+// loadStartupConfig reads STARTUP_MODE (blocking or background, default blocking),
+// STARTUP_INDEXING (never, if-empty or always, default if-empty),
+// STARTUP_WAIT_TIMEOUT (default 60s; 0 waits indefinitely), MANTICORE_HEALTH_CHECK_INTERVAL (default 15s)
+// the warm-up searches of WARMUP_QUERIES, see warmup.FromEnvironment, and
+// STARTUP_CONSISTENCY_CHECK, see reconcile.ModeFromEnvironment
+func loadStartupConfig() (startupConfig, error) {
+	config := startupConfig{
+		Mode:          startupModeBlocking,
+		Indexing:      indexingPolicyIfEmpty,
+		Consistency:   reconcile.ModeReport,
+		WaitTimeout:   60 * time.Second,
+		CheckInterval: 15 * time.Second,
+	}
+
+	if mode := os.Getenv("STARTUP_MODE"); mode != "" {
+		if mode != startupModeBlocking && mode != startupModeBackground {
+			return config, fmt.Errorf("invalid STARTUP_MODE %q, expected %q or %q", mode, startupModeBlocking, startupModeBackground)
+		}
+		config.Mode = mode
+	}
+
+	if policy := os.Getenv("STARTUP_INDEXING"); policy != "" {
+		if policy != indexingPolicyNever && policy != indexingPolicyIfEmpty && policy != indexingPolicyAlways {
+			return config, fmt.Errorf("invalid STARTUP_INDEXING %q, expected %q, %q or %q", policy, indexingPolicyNever, indexingPolicyIfEmpty, indexingPolicyAlways)
+		}
+		config.Indexing = policy
+	}
+
+	if timeoutStr := os.Getenv("STARTUP_WAIT_TIMEOUT"); timeoutStr != "" {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil || timeout < 0 {
+			return config, fmt.Errorf("invalid STARTUP_WAIT_TIMEOUT: %q", timeoutStr)
+		}
+		config.WaitTimeout = timeout
+	}
+
+	if intervalStr := os.Getenv("MANTICORE_HEALTH_CHECK_INTERVAL"); intervalStr != "" {
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil || interval <= 0 {
+			return config, fmt.Errorf("invalid MANTICORE_HEALTH_CHECK_INTERVAL: %q", intervalStr)
+		}
+		config.CheckInterval = interval
+	}
+
+	warmupConfig, err := warmup.FromEnvironment()
+	if err != nil {
+		return config, err
+	}
+	config.Warmup = warmupConfig
+
+	config.Consistency, err = reconcile.ModeFromEnvironment()
+	if err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
+
+// startManticore starts the background connection manager, which keeps probing Manticore and runs
+// database initialization once the backend is reachable. Blocking mode additionally waits up to
+// WaitTimeout for that to finish before the server starts; /readyz reports readiness either way.
+func startManticore(app *handlers.AppState, config startupConfig) {
+	if app.Manticore == nil {
+		return
+	}
+
+	app.Connection = manticore.NewConnectionManager(app.Manticore, manticore.ConnectionManagerConfig{
+		CheckInterval: config.CheckInterval,
+		ReadyOptions:  manticore.DefaultReadyOptions(),
+		OnReady: func() error {
+			// Initialize database and index documents
+			if err := initializeDatabase(app, config.Indexing, config.Consistency); err != nil {
+				return err
+			}
+			// Warm up before the connection is reported ready, so /readyz waits for it
+			if config.Warmup != nil {
+				app.WarmUp(context.Background(), config.Warmup)
+			}
+			return nil
+		},
+	})
+	app.Connection.Start()
+
+	if config.Mode == startupModeBackground {
+		log.Println("Waiting for Manticore Search in the background, server starts immediately")
+		return
+	}
+
+	log.Println("Waiting for Manticore Search to be ready...")
+	ctx := context.Background()
+	if config.WaitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.WaitTimeout)
+		defer cancel()
+	}
+
+	if err := app.Connection.WaitUntilReady(ctx); err != nil {
+		log.Printf("Warning: Manticore is not ready after %v, continuing to connect in the background", config.WaitTimeout)
+		log.Println("API will still start, but search functionality may be limited")
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ad/manticoresearch-go/internal/analytics"
+	"github.com/ad/manticoresearch-go/internal/backup"
+	"github.com/ad/manticoresearch-go/internal/curation"
+	"github.com/ad/manticoresearch-go/internal/deadletter"
+	"github.com/ad/manticoresearch-go/internal/docstore"
+	"github.com/ad/manticoresearch-go/internal/handlers"
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/querytemplate"
+	"github.com/ad/manticoresearch-go/internal/savedsearch"
+	"github.com/ad/manticoresearch-go/internal/search"
+)
+
+// newTenantApp creates the state of a tenant. It shares the audit log, webhooks, API keys and
+// usage quotas of app, and has its own Manticore tables (prefixed with the tenant name), data
+// directory (DATA_DIR/<tenant>), backups, caches, query analytics, saved searches, query templates
+// and dead letters. Its AI searches use aiConfig, which creates its tables as well.
+func newTenantApp(app *handlers.AppState, tenant string, aiConfig *models.AISearchConfig) *handlers.AppState {
+	tenantApp := handlers.NewAppStateWithConfig(aiConfig)
+	tenantApp.Tenant = tenant
+	tenantApp.Audit = app.Audit
+	tenantApp.Webhooks = app.Webhooks
+	tenantApp.AdminToken = app.AdminToken
+	tenantApp.APIKeys = app.APIKeys
+	tenantApp.Usage = app.Usage
+	tenantApp.SLO = app.SLO
+	tenantApp.RescoreWindow = app.RescoreWindow
+	tenantApp.Relaxation = app.Relaxation
+	tenantApp.SpellTolerance = app.SpellTolerance
+	tenantApp.DedupThreshold = app.DedupThreshold
+	tenantApp.Stopwords = app.Stopwords
+	tenantApp.Normalizer = app.Normalizer
+	tenantApp.Boosts = app.Boosts
+	tenantApp.Features = app.Features
+	tenantApp.Blocklist = app.Blocklist
+	tenantApp.Calibration = app.Calibration
+	tenantApp.Redactor = app.Redactor
+	tenantApp.DocumentRules = app.DocumentRules
+	tenantApp.Retention = app.Retention
+	tenantApp.DataDir = filepath.Join(app.DataDirectory(), tenant)
+	tenantApp.BackupDir = filepath.Join(backup.DirectoryFromEnvironment(), tenant)
+	tenantApp.Continuations = search.NewContinuations(search.DefaultMaxContinuations, search.DefaultContinuationTTL)
+	tenantApp.Analytics, _ = analytics.NewStoreFromEnvironment()
+
+	// Each tenant keeps documents up to the same limits
+	stats := app.Documents.Stats()
+	tenantApp.Documents = docstore.New(stats.MaxDocuments, stats.MaxSize)
+
+	resultCache, err := search.NewResultCacheFromEnvironment()
+	if err != nil {
+		log.Printf("Warning: Failed to configure search result cache of tenant %s: %v", tenant, err)
+	}
+	tenantApp.ResultCache = resultCache
+
+	savedSearches, err := savedsearch.NewStore(tenantPath(os.Getenv("SAVED_SEARCHES_FILE"), tenant))
+	if err != nil {
+		log.Printf("Warning: Failed to load saved searches of tenant %s, falling back to in-memory saved searches: %v", tenant, err)
+		savedSearches, _ = savedsearch.NewStore("")
+	}
+	tenantApp.SavedSearches = savedSearches
+
+	templates, err := querytemplate.NewStore(tenantPath(os.Getenv("QUERY_TEMPLATES_FILE"), tenant))
+	if err != nil {
+		log.Printf("Warning: Failed to load query templates of tenant %s, falling back to in-memory templates: %v", tenant, err)
+		templates, _ = querytemplate.NewStore("")
+	}
+	tenantApp.Templates = templates
+
+	curations, err := curation.NewStore(tenantPath(os.Getenv("CURATIONS_FILE"), tenant))
+	if err != nil {
+		log.Printf("Warning: Failed to load curations of tenant %s, falling back to in-memory curations: %v", tenant, err)
+		curations, _ = curation.NewStore("")
+	}
+	tenantApp.Curations = curations
+
+	deadLetters, err := deadletter.New(tenantPath(os.Getenv("DEAD_LETTER_FILE"), tenant), deadletter.DefaultMaxRecent)
+	if err != nil {
+		log.Printf("Warning: Failed to open dead-letter store of tenant %s, falling back to in-memory dead letters: %v", tenant, err)
+		deadLetters, _ = deadletter.New("", deadletter.DefaultMaxRecent)
+	}
+	tenantApp.DeadLetters = deadLetters
+
+	if app.Manticore != nil {
+		config, err := manticore.LoadHTTPConfigFromEnvironment()
+		if err != nil {
+			log.Printf("Warning: Failed to create Manticore client of tenant %s: %v", tenant, err)
+		} else {
+			config.TablePrefix = handlers.TablePrefix(tenant)
+			tenantApp.Manticore = manticore.NewHTTPClient(*config)
+		}
+	}
+
+	return tenantApp
+}
+
+// tenantPath returns the file of a tenant next to path, such as searches.acme.json for
+// searches.json, or "" when path is empty
+func tenantPath(path, tenant string) string {
+	if path == "" {
+		return ""
+	}
+	ext := filepath.Ext(path)
+	return path[:len(path)-len(ext)] + "." + tenant + ext
+}
@@ -0,0 +1,130 @@
+package analytics
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxQueries is the number of distinct queries a store keeps when SEARCH_ANALYTICS_MAX_QUERIES
+// is unset
+const DefaultMaxQueries = 10000
+
+// Query is a search query and how often it found results
+type Query struct {
+	Text         string    // As last searched
+	Count        int64     // Searches that found results
+	LastSearched time.Time // Time of the last of them
+}
+
+// Store counts successful search queries, so popular ones can be suggested. Queries differing
+// only in case and spacing are counted together. Counts are kept in memory and start over when the
+// service restarts; when the store is full, the least searched query makes room for a new one.
+// A nil *Store is valid, records nothing and suggests nothing.
+type Store struct {
+	mutex      sync.Mutex
+	maxQueries int
+	now        func() time.Time
+	queries    map[string]*Query
+}
+
+// NewStore creates a store keeping up to maxQueries distinct queries
+func NewStore(maxQueries int) *Store {
+	return &Store{maxQueries: maxQueries, now: time.Now, queries: make(map[string]*Query)}
+}
+
+// NewStoreFromEnvironment creates a store keeping SEARCH_ANALYTICS_MAX_QUERIES distinct queries
+// (default DefaultMaxQueries). It returns nil, which disables query analytics, when it is 0.
+func NewStoreFromEnvironment() (*Store, error) {
+	value := os.Getenv("SEARCH_ANALYTICS_MAX_QUERIES")
+	if value == "" {
+		return NewStore(DefaultMaxQueries), nil
+	}
+	maxQueries, err := strconv.Atoi(value)
+	if err != nil || maxQueries < 0 {
+		return NewStore(DefaultMaxQueries), fmt.Errorf("invalid SEARCH_ANALYTICS_MAX_QUERIES: %q", value)
+	}
+	if maxQueries == 0 {
+		return nil, nil
+	}
+	return NewStore(maxQueries), nil
+}
+
+// key returns the form of query searches are counted under
+func key(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// Record counts a search for query that found results
+func (s *Store) Record(query string) {
+	if s == nil {
+		return
+	}
+	k := key(query)
+	if k == "" {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, ok := s.queries[k]
+	if !ok {
+		if len(s.queries) >= s.maxQueries {
+			s.evict()
+		}
+		entry = &Query{}
+		s.queries[k] = entry
+	}
+	entry.Text = strings.Join(strings.Fields(query), " ")
+	entry.Count++
+	entry.LastSearched = s.now()
+}
+
+// evict removes the least searched query, the least recent of equally searched ones
+func (s *Store) evict() {
+	var victim string
+	var least *Query
+	for k, entry := range s.queries {
+		if least == nil || entry.Count < least.Count || (entry.Count == least.Count && entry.LastSearched.Before(least.LastSearched)) {
+			victim, least = k, entry
+		}
+	}
+	delete(s.queries, victim)
+}
+
+// Popular returns up to limit queries starting with prefix, ignoring case, the most searched
+// first and the most recent of equally searched ones first. An empty prefix matches every query.
+func (s *Store) Popular(prefix string, limit int) []Query {
+	result := []Query{}
+	if s == nil || limit <= 0 {
+		return result
+	}
+	prefix = key(prefix)
+
+	s.mutex.Lock()
+	for k, entry := range s.queries {
+		if strings.HasPrefix(k, prefix) {
+			result = append(result, *entry)
+		}
+	}
+	s.mutex.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		if !result[i].LastSearched.Equal(result[j].LastSearched) {
+			return result[i].LastSearched.After(result[j].LastSearched)
+		}
+		return result[i].Text < result[j].Text
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStorePopular(t *testing.T) {
+	store := NewStore(10)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+
+	for _, query := range []string{"golang http", "Golang  HTTP", "golang generics", "rust", "golang http server", "golang generics"} {
+		store.Record(query)
+	}
+	store.Record("   ")
+
+	popular := store.Popular(" GOLANG ", 10)
+	if len(popular) != 3 {
+		t.Fatalf("Expected 3 queries, got %+v", popular)
+	}
+	// Equally searched queries are ordered by the last search, and the last spelling is kept
+	if popular[0].Text != "golang generics" || popular[0].Count != 2 || popular[1].Text != "Golang HTTP" || popular[2].Text != "golang http server" {
+		t.Errorf("Unexpected order: %+v", popular)
+	}
+	if all := store.Popular("", 2); len(all) != 2 {
+		t.Errorf("Expected the limit to apply, got %+v", all)
+	}
+	if none := store.Popular("python", 5); none == nil || len(none) != 0 {
+		t.Errorf("Expected no queries, got %+v", none)
+	}
+}
+
+func TestStoreEviction(t *testing.T) {
+	store := NewStore(2)
+	store.Record("first")
+	store.Record("first")
+	store.Record("second")
+	store.Record("third")
+
+	popular := store.Popular("", 10)
+	if len(popular) != 2 || popular[0].Text != "first" || popular[1].Text != "third" {
+		t.Errorf("Expected the least searched query to be evicted, got %+v", popular)
+	}
+}
+
+func TestNewStoreFromEnvironment(t *testing.T) {
+	t.Setenv("SEARCH_ANALYTICS_MAX_QUERIES", "")
+	if store, err := NewStoreFromEnvironment(); err != nil || store.maxQueries != DefaultMaxQueries {
+		t.Errorf("Expected the default store, got %+v, %v", store, err)
+	}
+	t.Setenv("SEARCH_ANALYTICS_MAX_QUERIES", "0")
+	if store, err := NewStoreFromEnvironment(); err != nil || store != nil {
+		t.Errorf("Expected analytics to be disabled, got %+v, %v", store, err)
+	}
+	t.Setenv("SEARCH_ANALYTICS_MAX_QUERIES", "many")
+	if _, err := NewStoreFromEnvironment(); err == nil {
+		t.Error("Expected an error for an invalid value")
+	}
+
+	// A nil store records and suggests nothing
+	var store *Store
+	store.Record("golang")
+	if popular := store.Popular("", 5); len(popular) != 0 {
+		t.Errorf("Expected no queries, got %+v", popular)
+	}
+}
@@ -0,0 +1,193 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Outcomes recorded for audited operations
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// DefaultMaxRecent is the number of entries kept in memory for queries
+const DefaultMaxRecent = 1000
+
+// Entry is a single audit record
+type Entry struct {
+	ID         int64                  `json:"id"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Actor      string                 `json:"actor"`
+	RemoteAddr string                 `json:"remote_addr,omitempty"`
+	Action     string                 `json:"action"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Outcome    string                 `json:"outcome"`
+	Error      string                 `json:"error,omitempty"`
+	Duration   time.Duration          `json:"duration"`
+}
+
+// Log is an append-only audit log. Entries are written as JSON lines to an optional file
+// and the most recent ones are kept in memory for the query endpoint.
+// A nil *Log is valid and records nothing.
+type Log struct {
+	mutex     sync.RWMutex
+	file      *os.File
+	recent    []Entry
+	maxRecent int
+	nextID    int64
+}
+
+// New creates an audit log. When path is empty entries are kept in memory only;
+// otherwise existing entries are loaded from the file and new ones are appended to it.
+func New(path string, maxRecent int) (*Log, error) {
+	if maxRecent <= 0 {
+		maxRecent = DefaultMaxRecent
+	}
+
+	l := &Log{
+		maxRecent: maxRecent,
+		nextID:    1,
+	}
+
+	if path == "" {
+		return l, nil
+	}
+
+	if err := l.load(path); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %v", path, err)
+	}
+	l.file = file
+
+	return l, nil
+}
+
+// NewFromEnvironment creates an audit log configured by AUDIT_LOG_FILE and AUDIT_LOG_MAX_RECENT
+func NewFromEnvironment() (*Log, error) {
+	maxRecent := DefaultMaxRecent
+	if maxRecentStr := os.Getenv("AUDIT_LOG_MAX_RECENT"); maxRecentStr != "" {
+		parsed, err := strconv.Atoi(maxRecentStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AUDIT_LOG_MAX_RECENT: %w", err)
+		}
+		maxRecent = parsed
+	}
+
+	return New(os.Getenv("AUDIT_LOG_FILE"), maxRecent)
+}
+
+// load reads existing entries so IDs keep increasing and recent history survives restarts
+func (l *Log) load(path string) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read audit log %s: %v", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			log.Printf("[AUDIT] Skipping malformed audit log line: %v", err)
+			continue
+		}
+		l.append(entry)
+		if entry.ID >= l.nextID {
+			l.nextID = entry.ID + 1
+		}
+	}
+	return scanner.Err()
+}
+
+// Record stores an entry, assigning its ID and timestamp
+func (l *Log) Record(entry Entry) {
+	if l == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entry.ID = l.nextID
+	l.nextID++
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	l.append(entry)
+
+	log.Printf("[AUDIT] %s by %s: %s", entry.Action, entry.Actor, entry.Outcome)
+
+	if l.file == nil {
+		return
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("[AUDIT] Failed to marshal audit entry: %v", err)
+		return
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		log.Printf("[AUDIT] Failed to write audit entry: %v", err)
+	}
+}
+
+// append adds an entry to the in-memory window (caller holds the lock)
+func (l *Log) append(entry Entry) {
+	l.recent = append(l.recent, entry)
+	if len(l.recent) > l.maxRecent {
+		l.recent = l.recent[len(l.recent)-l.maxRecent:]
+	}
+}
+
+// Recent returns up to limit entries, newest first, optionally filtered by action
+func (l *Log) Recent(limit int, action string) []Entry {
+	if l == nil {
+		return nil
+	}
+
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	result := make([]Entry, 0)
+	for i := len(l.recent) - 1; i >= 0; i-- {
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+		if action != "" && l.recent[i].Action != action {
+			continue
+		}
+		result = append(result, l.recent[i])
+	}
+	return result
+}
+
+// Close closes the underlying file
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLogRecordAndRecent(t *testing.T) {
+	l, err := New("", 3)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for _, action := range []string{"reindex", "schema_reset", "reindex", "reindex"} {
+		l.Record(Entry{Action: action, Actor: "admin", Outcome: OutcomeSuccess})
+	}
+
+	recent := l.Recent(0, "")
+	if len(recent) != 3 {
+		t.Fatalf("Expected 3 entries kept in memory, got %d", len(recent))
+	}
+	if recent[0].ID != 4 {
+		t.Errorf("Expected newest entry first, got ID %d", recent[0].ID)
+	}
+
+	if filtered := l.Recent(0, "schema_reset"); len(filtered) != 1 {
+		t.Errorf("Expected 1 schema_reset entry, got %d", len(filtered))
+	}
+	if limited := l.Recent(1, ""); len(limited) != 1 {
+		t.Errorf("Expected limit to apply, got %d entries", len(limited))
+	}
+}
+
+func TestLogPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, err := New(path, 10)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	l.Record(Entry{Action: "reindex", Actor: "admin", Outcome: OutcomeSuccess, Parameters: map[string]interface{}{"documents": 3}})
+	l.Record(Entry{Action: "schema_reset", Actor: "system", Outcome: OutcomeFailure, Error: "boom"})
+	l.Close()
+
+	reopened, err := New(path, 10)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer reopened.Close()
+
+	recent := reopened.Recent(0, "")
+	if len(recent) != 2 || recent[0].Error != "boom" {
+		t.Fatalf("Expected entries to be loaded from file, got %+v", recent)
+	}
+
+	reopened.Record(Entry{Action: "reindex", Actor: "admin", Outcome: OutcomeSuccess})
+	if id := reopened.Recent(1, "")[0].ID; id != 3 {
+		t.Errorf("Expected IDs to continue after reload, got %d", id)
+	}
+}
+
+func TestNilLog(t *testing.T) {
+	var l *Log
+	l.Record(Entry{Action: "reindex"})
+	if entries := l.Recent(10, ""); entries != nil {
+		t.Errorf("Expected no entries from nil log, got %v", entries)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("Expected nil Close error, got %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+package backup
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+// FormatVersion is the artifact layout written by Save
+const FormatVersion = 1
+
+// Files inside an artifact directory
+const (
+	manifestFile   = "manifest.json"
+	vectorizerFile = "vectorizer.json"
+	documentsFile  = "documents.jsonl"
+)
+
+// validName restricts artifact names to a single safe path component
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// Manifest describes a backup artifact
+type Manifest struct {
+	FormatVersion   int                    `json:"format_version"`
+	Name            string                 `json:"name"`
+	CreatedAt       time.Time              `json:"created_at"`
+	SchemaVersion   int                    `json:"schema_version"`
+	Documents       int                    `json:"documents"`
+	ManticoreBackup string                 `json:"manticore_backup,omitempty"` // BACKUP directory on the Manticore host, if one was taken
+	AIConfig        *models.AISearchConfig `json:"ai_config,omitempty"`
+}
+
+// Artifact is everything needed to restore the search index and the application state around it
+type Artifact struct {
+	Manifest   Manifest
+	Vectorizer *vectorizer.Model // nil when no vectorizer was trained
+	Documents  []*models.Document
+	Vectors    [][]float64 // TF-IDF vector per document, parallel to Documents; empty without a vectorizer
+}
+
+// documentRecord is one line of the documents file
+type documentRecord struct {
+	Document *models.Document `json:"document"`
+	Vector   []float64        `json:"vector,omitempty"`
+}
+
+// DirectoryFromEnvironment returns BACKUP_DIR, defaulting to ./backups
+func DirectoryFromEnvironment() string {
+	if dir := os.Getenv("BACKUP_DIR"); dir != "" {
+		return dir
+	}
+	return "./backups"
+}
+
+// NewName returns the default artifact name for a backup taken at t
+func NewName(t time.Time) string {
+	return "backup-" + t.UTC().Format("20060102-150405")
+}
+
+// ValidateName rejects names that are not a single safe path component
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid backup name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// Exists reports whether an artifact called name is stored in dir
+func Exists(dir, name string) bool {
+	_, err := os.Stat(filepath.Join(dir, name, manifestFile))
+	return err == nil
+}
+
+// Save writes the artifact to dir/<name>, filling in the format version and document count.
+// Files are written to a temporary directory first, so a failed backup never leaves a partial
+// artifact under the final name.
+func Save(dir string, artifact *Artifact) (string, error) {
+	name := artifact.Manifest.Name
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	if len(artifact.Vectors) > 0 && len(artifact.Vectors) != len(artifact.Documents) {
+		return "", fmt.Errorf("got %d vectors for %d documents", len(artifact.Vectors), len(artifact.Documents))
+	}
+
+	target := filepath.Join(dir, name)
+	if _, err := os.Stat(target); err == nil {
+		return "", fmt.Errorf("backup %s already exists", name)
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create backup directory %s: %v", dir, err)
+	}
+	tmp, err := os.MkdirTemp(dir, "."+name+"-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary backup directory: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	artifact.Manifest.FormatVersion = FormatVersion
+	artifact.Manifest.Documents = len(artifact.Documents)
+	if err := writeJSON(filepath.Join(tmp, manifestFile), artifact.Manifest); err != nil {
+		return "", err
+	}
+	if artifact.Vectorizer != nil {
+		if err := writeJSON(filepath.Join(tmp, vectorizerFile), artifact.Vectorizer); err != nil {
+			return "", err
+		}
+	}
+	if err := writeDocuments(filepath.Join(tmp, documentsFile), artifact); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp, target); err != nil {
+		return "", fmt.Errorf("failed to finalize backup %s: %v", name, err)
+	}
+	return target, nil
+}
+
+// Load reads the artifact stored in dir/<name>
+func Load(dir, name string) (*Artifact, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, name)
+
+	artifact := &Artifact{}
+	if err := readJSON(filepath.Join(path, manifestFile), &artifact.Manifest); err != nil {
+		return nil, err
+	}
+	if artifact.Manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported backup format version %d", artifact.Manifest.FormatVersion)
+	}
+
+	var model vectorizer.Model
+	if err := readJSON(filepath.Join(path, vectorizerFile), &model); err == nil {
+		artifact.Vectorizer = &model
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err := readDocuments(filepath.Join(path, documentsFile), artifact); err != nil {
+		return nil, err
+	}
+	if len(artifact.Documents) != artifact.Manifest.Documents {
+		return nil, fmt.Errorf("backup %s is incomplete: expected %d documents, found %d", name, artifact.Manifest.Documents, len(artifact.Documents))
+	}
+	return artifact, nil
+}
+
+// writeJSON writes value as indented JSON
+func writeJSON(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %v", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, data, 0640); err != nil {
+		return fmt.Errorf("failed to write %s: %v", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// readJSON decodes a JSON file, returning os.IsNotExist-compatible errors for missing files
+func readJSON(path string, value interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// writeDocuments writes one JSON record per document
+func writeDocuments(path string, artifact *Artifact) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0640)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", documentsFile, err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for i, doc := range artifact.Documents {
+		record := documentRecord{Document: doc}
+		if len(artifact.Vectors) > 0 {
+			record.Vector = artifact.Vectors[i]
+		}
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("failed to write document %d: %v", doc.ID, err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s: %v", documentsFile, err)
+	}
+	return file.Sync()
+}
+
+// readDocuments loads documents and, when every record has one, their vectors
+func readDocuments(path string, artifact *Artifact) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", documentsFile, err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	withVectors := true
+	for decoder.More() {
+		var record documentRecord
+		if err := decoder.Decode(&record); err != nil {
+			return fmt.Errorf("failed to decode %s: %v", documentsFile, err)
+		}
+		if record.Document == nil {
+			return fmt.Errorf("%s contains a record without a document", documentsFile)
+		}
+		artifact.Documents = append(artifact.Documents, record.Document)
+		artifact.Vectors = append(artifact.Vectors, record.Vector)
+		withVectors = withVectors && record.Vector != nil
+	}
+
+	if !withVectors {
+		artifact.Vectors = nil
+	}
+	return nil
+}
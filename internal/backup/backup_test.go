@@ -0,0 +1,133 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+func testArtifact(name string) *Artifact {
+	documents := []*models.Document{
+		{ID: 1, Title: "Contact form", URL: "/contact", Content: "Send us a message using the contact form"},
+		{ID: 2, Title: "Pricing", URL: "/pricing", Content: "Plans and prices for every team size"},
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	vectors := vec.FitTransform(documents)
+	model := vec.Model()
+
+	return &Artifact{
+		Manifest: Manifest{
+			Name:          name,
+			CreatedAt:     time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+			SchemaVersion: 2,
+			AIConfig:      &models.AISearchConfig{Model: "test-model", Enabled: true, Timeout: time.Second},
+		},
+		Vectorizer: &model,
+		Documents:  documents,
+		Vectors:    vectors,
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	original := testArtifact("nightly")
+
+	path, err := Save(dir, original)
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if path != filepath.Join(dir, "nightly") || !Exists(dir, "nightly") {
+		t.Fatalf("Unexpected backup path %s", path)
+	}
+
+	restored, err := Load(dir, "nightly")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if restored.Manifest.FormatVersion != FormatVersion || restored.Manifest.Documents != 2 || restored.Manifest.AIConfig.Model != "test-model" {
+		t.Errorf("Unexpected manifest: %+v", restored.Manifest)
+	}
+	if len(restored.Documents) != 2 || restored.Documents[1].Content != original.Documents[1].Content {
+		t.Errorf("Unexpected documents: %+v", restored.Documents)
+	}
+	if len(restored.Vectors) != 2 || len(restored.Vectors[0]) != len(original.Vectors[0]) {
+		t.Fatalf("Unexpected vectors: %d", len(restored.Vectors))
+	}
+
+	// The restored vectorizer has to reproduce the stored document vectors
+	vec, err := vectorizer.NewTFIDFVectorizerFromModel(*restored.Vectorizer)
+	if err != nil {
+		t.Fatalf("Failed to restore vectorizer: %v", err)
+	}
+	doc := restored.Documents[0]
+	vector := vec.TransformQuery(doc.Title + " " + doc.Content)
+	for i := range vector {
+		if vector[i] != restored.Vectors[0][i] {
+			t.Fatalf("Restored vectorizer produced a different vector at %d", i)
+		}
+	}
+}
+
+func TestSaveWithoutVectorizer(t *testing.T) {
+	dir := t.TempDir()
+	artifact := testArtifact("plain")
+	artifact.Vectorizer = nil
+	artifact.Vectors = nil
+
+	if _, err := Save(dir, artifact); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	restored, err := Load(dir, "plain")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if restored.Vectorizer != nil || restored.Vectors != nil || len(restored.Documents) != 2 {
+		t.Errorf("Unexpected artifact: %+v", restored)
+	}
+}
+
+func TestSaveRejectsExistingAndInvalidNames(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Save(dir, testArtifact("taken")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := Save(dir, testArtifact("taken")); err == nil {
+		t.Error("Expected error when overwriting an existing backup")
+	}
+
+	for _, name := range []string{"", "../escape", ".hidden", "a/b"} {
+		if _, err := Save(dir, testArtifact(name)); err == nil {
+			t.Errorf("Expected error for name %q", name)
+		}
+		if _, err := Load(dir, name); err == nil {
+			t.Errorf("Expected load error for name %q", name)
+		}
+	}
+
+	// Only the finished artifact is left behind, no temporary directories
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "taken" {
+		t.Errorf("Unexpected directory contents: %v", entries)
+	}
+}
+
+func TestLoadIncompleteBackup(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Save(dir, testArtifact("cut")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cut", documentsFile), nil, 0640); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir, "cut"); err == nil {
+		t.Error("Expected error for a backup with missing documents")
+	}
+}
@@ -0,0 +1,102 @@
+package blocklist
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// Blocklist lists documents that are never returned by searches, such as internal drafts, by id
+// or by URL prefix. A nil *Blocklist blocks nothing.
+type Blocklist struct {
+	IDs  map[int]bool
+	URLs []string // Prefixes of the blocked URLs
+}
+
+// Parse parses comma-separated document ids and URL prefixes. It returns nil when both are
+// empty.
+func Parse(ids, urls string) (*Blocklist, error) {
+	b := &Blocklist{IDs: make(map[int]bool)}
+	for _, value := range strings.Split(ids, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil || id < 1 {
+			return nil, fmt.Errorf("invalid document id %q, expected a number above 0", value)
+		}
+		b.IDs[id] = true
+	}
+	for _, prefix := range strings.Split(urls, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			b.URLs = append(b.URLs, prefix)
+		}
+	}
+
+	if len(b.IDs) == 0 && len(b.URLs) == 0 {
+		return nil, nil
+	}
+	return b, nil
+}
+
+// FromEnvironment parses SEARCH_BLOCKED_IDS and SEARCH_BLOCKED_URLS, see Parse; unset blocks
+// nothing
+func FromEnvironment() (*Blocklist, error) {
+	b, err := Parse(os.Getenv("SEARCH_BLOCKED_IDS"), os.Getenv("SEARCH_BLOCKED_URLS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SEARCH_BLOCKED_IDS: %w", err)
+	}
+	return b, nil
+}
+
+// Blocks reports whether doc is blocked by its id or URL
+func (b *Blocklist) Blocks(doc *models.Document) bool {
+	if b == nil || doc == nil {
+		return false
+	}
+	if b.IDs[doc.ID] {
+		return true
+	}
+	for _, prefix := range b.URLs {
+		if strings.HasPrefix(doc.URL, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter removes the blocked documents from results, keeping the order of the others, and
+// returns how many were removed
+func (b *Blocklist) Filter(results []models.SearchResult) ([]models.SearchResult, int) {
+	if b == nil {
+		return results, 0
+	}
+	kept := make([]models.SearchResult, 0, len(results))
+	for _, result := range results {
+		if !b.Blocks(result.Document) {
+			kept = append(kept, result)
+		}
+	}
+	return kept, len(results) - len(kept)
+}
+
+// SourceFields returns the stored fields the blocklist reads, which searches returning selected
+// fields still have to fetch
+func (b *Blocklist) SourceFields() []string {
+	if b == nil || len(b.URLs) == 0 {
+		return nil
+	}
+	return []string{"url"}
+}
+
+// String describes the blocklist for logs
+func (b *Blocklist) String() string {
+	if b == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%d document ids, %d URL prefixes", len(b.IDs), len(b.URLs))
+}
@@ -0,0 +1,68 @@
+package blocklist
+
+import (
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	b, err := Parse(" 3, 7,,3", "https://wiki.example.com/drafts/, ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(b.IDs) != 2 || !b.IDs[3] || !b.IDs[7] || len(b.URLs) != 1 {
+		t.Errorf("Unexpected blocklist: %+v", b)
+	}
+	if b.String() != "2 document ids, 1 URL prefixes" {
+		t.Errorf("Unexpected description: %q", b.String())
+	}
+
+	if b, err := Parse(" ", ""); b != nil || err != nil {
+		t.Errorf("Expected no blocklist, got %v, %v", b, err)
+	}
+	for _, ids := range []string{"0", "-2", "draft"} {
+		if _, err := Parse(ids, ""); err == nil {
+			t.Errorf("Expected an error for %q", ids)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	b, _ := Parse("2", "https://wiki.example.com/drafts/")
+	results := []models.SearchResult{
+		{Document: &models.Document{ID: 1, URL: "https://wiki.example.com/drafts/plan"}},
+		{Document: &models.Document{ID: 2}},
+		{Document: &models.Document{ID: 3, URL: "https://wiki.example.com/guide"}},
+		{Document: nil},
+	}
+
+	kept, blocked := b.Filter(results)
+	if blocked != 2 || len(kept) != 2 || kept[0].Document.ID != 3 || kept[1].Document != nil {
+		t.Errorf("Expected documents 1 and 2 to be removed, got %+v", kept)
+	}
+	if len(results) != 4 || results[0].Document.ID != 1 {
+		t.Error("Expected the results to be left unchanged")
+	}
+
+	var none *Blocklist
+	if kept, blocked := none.Filter(results); blocked != 0 || len(kept) != 4 {
+		t.Error("Expected a nil blocklist to keep every result")
+	}
+	if none.SourceFields() != nil || b.SourceFields()[0] != "url" {
+		t.Error("Unexpected source fields")
+	}
+}
+
+func TestFromEnvironment(t *testing.T) {
+	t.Setenv("SEARCH_BLOCKED_IDS", "")
+	t.Setenv("SEARCH_BLOCKED_URLS", "")
+	if b, err := FromEnvironment(); b != nil || err != nil {
+		t.Errorf("Expected no blocklist, got %v, %v", b, err)
+	}
+
+	t.Setenv("SEARCH_BLOCKED_IDS", "x")
+	if _, err := FromEnvironment(); err == nil {
+		t.Error("Expected an error for an invalid id")
+	}
+}
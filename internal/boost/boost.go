@@ -0,0 +1,160 @@
+package boost
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// Document attributes a rule can match
+const (
+	FieldTag    = "tag"    // The document has the tag
+	FieldStatus = "status" // The document has the status
+	FieldURL    = "url"    // The document URL starts with the value
+)
+
+// Fields lists every attribute a rule can match
+var Fields = []string{FieldTag, FieldStatus, FieldURL}
+
+// Rule multiplies the score of the documents matching an attribute value by Factor: above 1 it
+// boosts them, below 1 it deboosts them
+type Rule struct {
+	Field  string
+	Value  string
+	Factor float64
+}
+
+// Matches reports whether doc has the attribute value of the rule
+func (r Rule) Matches(doc *models.Document) bool {
+	switch r.Field {
+	case FieldTag:
+		for _, tag := range doc.Tags {
+			if tag == r.Value {
+				return true
+			}
+		}
+	case FieldStatus:
+		return string(doc.Status) == r.Value
+	case FieldURL:
+		return strings.HasPrefix(doc.URL, r.Value)
+	}
+	return false
+}
+
+// String returns the rule as written in SEARCH_BOOSTS
+func (r Rule) String() string {
+	return fmt.Sprintf("%s=%s:%s", r.Field, r.Value, strconv.FormatFloat(r.Factor, 'g', -1, 64))
+}
+
+// Rules are applied together, multiplying the factors of every rule a document matches. Nil
+// Rules leave scores unchanged.
+type Rules []Rule
+
+// Parse parses comma-separated field=value:factor rules, such as
+// "tag=handbook:1.5,tag=deprecated:0.5,url=https://wiki.example.com/:1.2". The factor follows the
+// last colon, so URL values may contain colons.
+func Parse(value string) (Rules, error) {
+	var rules Rules
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		field, rest, ok := strings.Cut(item, "=")
+		separator := strings.LastIndex(rest, ":")
+		if !ok || separator < 0 {
+			return nil, fmt.Errorf("invalid boost rule %q, expected <field>=<value>:<factor>", item)
+		}
+		rule := Rule{Field: strings.ToLower(strings.TrimSpace(field)), Value: strings.TrimSpace(rest[:separator])}
+
+		factor, err := strconv.ParseFloat(strings.TrimSpace(rest[separator+1:]), 64)
+		if err != nil || factor <= 0 {
+			return nil, fmt.Errorf("invalid factor in boost rule %q, expected a number above 0", item)
+		}
+		rule.Factor = factor
+
+		switch rule.Field {
+		case FieldTag:
+			if tags := models.NormalizeTags([]string{rule.Value}); len(tags) == 1 {
+				rule.Value = tags[0]
+			}
+		case FieldStatus:
+			if _, err := models.ParseDocumentStatus(rule.Value); err != nil {
+				return nil, fmt.Errorf("invalid boost rule %q: %w", item, err)
+			}
+		case FieldURL:
+		default:
+			return nil, fmt.Errorf("unknown field in boost rule %q (use %s)", item, strings.Join(Fields, ", "))
+		}
+		if rule.Value == "" {
+			return nil, fmt.Errorf("invalid boost rule %q: the value is empty", item)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// FromEnvironment parses SEARCH_BOOSTS, see Parse; unset boosts nothing
+func FromEnvironment() (Rules, error) {
+	rules, err := Parse(os.Getenv("SEARCH_BOOSTS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SEARCH_BOOSTS: %w", err)
+	}
+	return rules, nil
+}
+
+// Factor returns the product of the factors of the rules doc matches, 1 when it matches none
+func (rs Rules) Factor(doc *models.Document) float64 {
+	factor := 1.0
+	if doc == nil {
+		return factor
+	}
+	for _, rule := range rs {
+		if rule.Matches(doc) {
+			factor *= rule.Factor
+		}
+	}
+	return factor
+}
+
+// Apply multiplies the score of each result by its factor and orders the results by the boosted
+// score, keeping the order of equal scores
+func (rs Rules) Apply(results []models.SearchResult) {
+	if len(rs) == 0 {
+		return
+	}
+	for i := range results {
+		results[i].Score *= rs.Factor(results[i].Document)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+}
+
+// SourceFields returns the stored fields the rules read, which searches returning selected
+// fields still have to fetch
+func (rs Rules) SourceFields() []string {
+	var fields []string
+	for _, field := range []struct{ rule, source string }{{FieldTag, "tags"}, {FieldStatus, "status"}, {FieldURL, "url"}} {
+		for _, rule := range rs {
+			if rule.Field == field.rule {
+				fields = append(fields, field.source)
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// String returns the rules as written in SEARCH_BOOSTS
+func (rs Rules) String() string {
+	items := make([]string, len(rs))
+	for i, rule := range rs {
+		items[i] = rule.String()
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,86 @@
+package boost
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	rules, err := Parse(" tag=Handbook:1.5, status=archived:0.8,url=https://wiki.example.com/:1.2 ,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rules.String() != "tag=handbook:1.5,status=archived:0.8,url=https://wiki.example.com/:1.2" {
+		t.Errorf("Unexpected rules: %s", rules)
+	}
+
+	for _, value := range []string{
+		"tag=handbook",
+		"handbook:1.5",
+		"tag=handbook:0",
+		"tag=handbook:-1",
+		"tag=handbook:much",
+		"source=handbook:1.5",
+		"status=hidden:0.5",
+		"tag=:1.5",
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	rules, err := Parse("tag=handbook:1.5,tag=deprecated:0.5,url=https://wiki.example.com/:2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	results := []models.SearchResult{
+		{Document: &models.Document{ID: 1, Tags: []string{"deprecated"}}, Score: 1.0},
+		{Document: &models.Document{ID: 2}, Score: 0.8},
+		{Document: &models.Document{ID: 3, Tags: []string{"handbook"}}, Score: 0.6},
+		{Document: &models.Document{ID: 4, URL: "https://wiki.example.com/page", Tags: []string{"deprecated"}}, Score: 0.8},
+		{Document: nil, Score: 0.7},
+	}
+	rules.Apply(results)
+
+	expected := []float64{0.9, 0.8, 0.8, 0.7, 0.5}
+	for i, result := range results {
+		if math.Abs(result.Score-expected[i]) > 1e-9 {
+			t.Errorf("Result %d: expected score %v, got %v", i, expected[i], result.Score)
+		}
+	}
+	// Equal boosted scores keep their order
+	if results[0].Document.ID != 3 || results[1].Document.ID != 2 || results[2].Document.ID != 4 || results[4].Document.ID != 1 {
+		t.Errorf("Unexpected order: %+v", results)
+	}
+
+	var none Rules
+	unchanged := []models.SearchResult{{Document: &models.Document{ID: 1}, Score: 0.3}}
+	none.Apply(unchanged)
+	if unchanged[0].Score != 0.3 {
+		t.Error("Expected no rules to leave scores unchanged")
+	}
+}
+
+func TestSourceFields(t *testing.T) {
+	rules, _ := Parse("tag=a:2,tag=b:2,status=archived:0.5")
+	if fields := rules.SourceFields(); len(fields) != 2 || fields[0] != "tags" || fields[1] != "status" {
+		t.Errorf("Unexpected source fields: %v", fields)
+	}
+}
+
+func TestFromEnvironment(t *testing.T) {
+	t.Setenv("SEARCH_BOOSTS", "")
+	if rules, err := FromEnvironment(); rules != nil || err != nil {
+		t.Errorf("Expected no rules, got %v, %v", rules, err)
+	}
+
+	t.Setenv("SEARCH_BOOSTS", "tag=handbook")
+	if _, err := FromEnvironment(); err == nil {
+		t.Error("Expected an error for a rule without a factor")
+	}
+}
@@ -0,0 +1,174 @@
+package boost
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Scoring features comparing a document with the query
+const (
+	FeatureExactTitle = "exact_title" // The title is the query, word for word
+	FeatureTitleTerms = "title_terms" // The title contains every query word
+	FeatureURLSlug    = "url_slug"    // The last URL path segment contains every query word
+)
+
+// FeatureNames lists every scoring feature
+var FeatureNames = []string{FeatureExactTitle, FeatureTitleTerms, FeatureURLSlug}
+
+// DefaultFeatures are the feature weights used when SEARCH_FEATURES is unset
+var DefaultFeatures = Features{FeatureExactTitle: 1.5, FeatureTitleTerms: 1.2, FeatureURLSlug: 1.1}
+
+// Features are the weights of the scoring features, multiplying the score of the documents that
+// have them. A title matching the query exactly only counts as exact_title, not title_terms as
+// well. Nil Features leave scores unchanged.
+type Features map[string]float64
+
+// ParseFeatures parses comma-separated feature:weight pairs, such as "exact_title:2,url_slug:1.2".
+// Features left out are not applied; "none" disables every feature.
+func ParseFeatures(value string) (Features, error) {
+	if strings.EqualFold(strings.TrimSpace(value), "none") {
+		return Features{}, nil
+	}
+	features := Features{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, weightText, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid scoring feature %q, expected <feature>:<weight>", item)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isFeature(name) {
+			return nil, fmt.Errorf("unknown scoring feature %q (use %s)", name, strings.Join(FeatureNames, ", "))
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightText), 64)
+		if err != nil || weight <= 0 {
+			return nil, fmt.Errorf("invalid weight in scoring feature %q, expected a number above 0", item)
+		}
+		features[name] = weight
+	}
+	return features, nil
+}
+
+// FeaturesFromEnvironment parses SEARCH_FEATURES, see ParseFeatures; unset uses DefaultFeatures
+func FeaturesFromEnvironment() (Features, error) {
+	value, ok := os.LookupEnv("SEARCH_FEATURES")
+	if !ok || strings.TrimSpace(value) == "" {
+		return DefaultFeatures, nil
+	}
+	features, err := ParseFeatures(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SEARCH_FEATURES: %w", err)
+	}
+	return features, nil
+}
+
+func isFeature(name string) bool {
+	for _, feature := range FeatureNames {
+		if feature == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the weighted features a document with title and url has for the query words,
+// in FeatureNames order. Words are compared as given, so callers normalize all three alike.
+func (f Features) Match(query []string, title, url string) []string {
+	if len(f) == 0 || len(query) == 0 {
+		return nil
+	}
+	var matched []string
+	titleWords := Words(title)
+	if _, ok := f[FeatureExactTitle]; ok && equalWords(titleWords, query) {
+		matched = append(matched, FeatureExactTitle)
+	} else if _, ok := f[FeatureTitleTerms]; ok && containsWords(titleWords, query) {
+		matched = append(matched, FeatureTitleTerms)
+	}
+	if _, ok := f[FeatureURLSlug]; ok && containsWords(Words(urlSlug(url)), query) {
+		matched = append(matched, FeatureURLSlug)
+	}
+	return matched
+}
+
+// Factor returns the product of the weights of matched, 1 when there are none
+func (f Features) Factor(matched []string) float64 {
+	factor := 1.0
+	for _, name := range matched {
+		if weight, ok := f[name]; ok {
+			factor *= weight
+		}
+	}
+	return factor
+}
+
+// String returns the features as written in SEARCH_FEATURES
+func (f Features) String() string {
+	if len(f) == 0 {
+		return "none"
+	}
+	var items []string
+	for _, name := range FeatureNames {
+		if weight, ok := f[name]; ok {
+			items = append(items, name+":"+strconv.FormatFloat(weight, 'g', -1, 64))
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// Words splits text into lower-case words of letters and digits
+func Words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// urlSlug returns the last path segment of a URL without its extension, query or fragment
+func urlSlug(url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+	if _, rest, ok := strings.Cut(url, "://"); ok {
+		_, url, _ = strings.Cut(rest, "/")
+	}
+	url = strings.TrimRight(url, "/")
+	slug := url[strings.LastIndex(url, "/")+1:]
+	if i := strings.LastIndex(slug, "."); i > 0 {
+		slug = slug[:i]
+	}
+	return slug
+}
+
+func equalWords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// containsWords reports whether words contains every one of query
+func containsWords(words, query []string) bool {
+	for _, term := range query {
+		found := false
+		for _, word := range words {
+			if word == term {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
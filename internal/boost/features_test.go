@@ -0,0 +1,70 @@
+package boost
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFeatures(t *testing.T) {
+	features, err := ParseFeatures(" URL_SLUG:1.2, exact_title:2 ,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if features.String() != "exact_title:2,url_slug:1.2" {
+		t.Errorf("Unexpected features: %s", features)
+	}
+	if features, err := ParseFeatures("none"); err != nil || len(features) != 0 || features.String() != "none" {
+		t.Errorf("Expected no features, got %v, %v", features, err)
+	}
+
+	for _, value := range []string{"exact_title", "exact_title:0", "exact_title:high", "title_words:1.5"} {
+		if _, err := ParseFeatures(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestFeaturesFromEnvironment(t *testing.T) {
+	t.Setenv("SEARCH_FEATURES", "")
+	if features, err := FeaturesFromEnvironment(); err != nil || !reflect.DeepEqual(features, DefaultFeatures) {
+		t.Errorf("Expected the default features, got %v, %v", features, err)
+	}
+	t.Setenv("SEARCH_FEATURES", "title_terms:1.5")
+	if features, err := FeaturesFromEnvironment(); err != nil || features.String() != "title_terms:1.5" {
+		t.Errorf("Expected title_terms:1.5, got %v, %v", features, err)
+	}
+	t.Setenv("SEARCH_FEATURES", "title:1.5")
+	if _, err := FeaturesFromEnvironment(); err == nil {
+		t.Error("Expected an error for an unknown feature")
+	}
+}
+
+func TestFeaturesMatch(t *testing.T) {
+	features := Features{FeatureExactTitle: 2, FeatureTitleTerms: 1.5, FeatureURLSlug: 1.2}
+	query := []string{"goroutine", "scheduler"}
+	tests := []struct {
+		title, url string
+		expected   []string
+	}{
+		{"Goroutine Scheduler", "https://go.dev/doc/", []string{FeatureExactTitle}},
+		{"The scheduler of a goroutine", "https://go.dev/doc/", []string{FeatureTitleTerms}},
+		{"Goroutines", "https://go.dev/blog/goroutine-scheduler.html?lang=en", []string{FeatureURLSlug}},
+		{"Goroutine scheduler", "https://go.dev/runtime/scheduler-goroutine/", []string{FeatureExactTitle, FeatureURLSlug}},
+		{"Channels", "https://go.dev/goroutine-scheduler/channels", nil},
+	}
+	for _, test := range tests {
+		matched := features.Match(query, test.title, test.url)
+		if !reflect.DeepEqual(matched, test.expected) {
+			t.Errorf("Match(%q, %q) = %v, expected %v", test.title, test.url, matched, test.expected)
+		}
+	}
+
+	// Only weighted features are matched, and an exact title then counts as containing the terms
+	titleTerms := Features{FeatureTitleTerms: 1.5}
+	if matched := titleTerms.Match(query, "Goroutine scheduler", ""); !reflect.DeepEqual(matched, []string{FeatureTitleTerms}) {
+		t.Errorf("Expected title_terms, got %v", matched)
+	}
+	if factor := features.Factor([]string{FeatureExactTitle, FeatureURLSlug}); factor != 2.4 {
+		t.Errorf("Expected factor 2.4, got %v", factor)
+	}
+}
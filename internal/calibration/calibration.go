@@ -0,0 +1,250 @@
+package calibration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// Mapping maps the raw scores of a search mode to the probability that a result is relevant. It
+// is monotonic, so the order of the results is kept.
+type Mapping interface {
+	Map(score float64) float64
+	String() string
+}
+
+// Sigmoid maps scores to 1 / (1 + e^(-Slope × (score - Midpoint))): Midpoint scores 0.5 and Slope
+// sets how fast scores around it approach 0 and 1
+type Sigmoid struct {
+	Slope    float64 `json:"slope"`
+	Midpoint float64 `json:"midpoint"`
+}
+
+// Map returns the calibrated score
+func (s Sigmoid) Map(score float64) float64 {
+	return 1 / (1 + math.Exp(-s.Slope*(score-s.Midpoint)))
+}
+
+func (s Sigmoid) String() string {
+	return fmt.Sprintf("sigmoid(slope=%g, midpoint=%g)", s.Slope, s.Midpoint)
+}
+
+// Percentiles are the raw scores at evenly spaced quantiles of a reference set of results, from
+// the lowest to the highest. Scores map to their interpolated quantile, 0 below the first
+// percentile and 1 above the last.
+type Percentiles []float64
+
+// Map returns the calibrated score
+func (p Percentiles) Map(score float64) float64 {
+	if score <= p[0] {
+		return 0
+	}
+	last := len(p) - 1
+	if score >= p[last] {
+		return 1
+	}
+	i := sort.SearchFloat64s(p, score) // p[i-1] < score <= p[i]
+	return (float64(i-1) + (score-p[i-1])/(p[i]-p[i-1])) / float64(last)
+}
+
+func (p Percentiles) String() string {
+	return fmt.Sprintf("percentiles(%d)", len(p))
+}
+
+// Sample is a result score with feedback on whether the result was relevant
+type Sample struct {
+	Score    float64 `json:"score"`
+	Relevant bool    `json:"relevant"`
+}
+
+// FitSigmoid fits a sigmoid to feedback by logistic regression (Platt scaling). Both relevant and
+// irrelevant samples are needed, and relevant ones have to score higher on the whole.
+func FitSigmoid(samples []Sample) (Sigmoid, error) {
+	var relevant, irrelevant, mean float64
+	for _, sample := range samples {
+		if sample.Relevant {
+			relevant++
+		} else {
+			irrelevant++
+		}
+		mean += sample.Score
+	}
+	if relevant == 0 || irrelevant == 0 {
+		return Sigmoid{}, errors.New("feedback needs both relevant and irrelevant samples")
+	}
+	mean /= float64(len(samples))
+	var variance float64
+	for _, sample := range samples {
+		variance += (sample.Score - mean) * (sample.Score - mean)
+	}
+	std := math.Sqrt(variance / float64(len(samples)))
+	if std == 0 {
+		return Sigmoid{}, errors.New("feedback samples all have the same score")
+	}
+
+	// Platt's targets keep separable feedback from fitting an infinitely steep sigmoid
+	positive, negative := (relevant+1)/(relevant+2), 1/(irrelevant+2)
+
+	// Newton's method on standardized scores, fitting p = σ(a×z + b)
+	var a, b float64
+	for iteration := 0; iteration < 100; iteration++ {
+		var gradA, gradB, hessAA, hessAB, hessBB float64
+		for _, sample := range samples {
+			z := (sample.Score - mean) / std
+			p := 1 / (1 + math.Exp(-(a*z + b)))
+			target := negative
+			if sample.Relevant {
+				target = positive
+			}
+			w := p * (1 - p)
+			gradA += (p - target) * z
+			gradB += p - target
+			hessAA += w * z * z
+			hessAB += w * z
+			hessBB += w
+		}
+		// A little damping keeps the system solvable when every p saturates
+		hessAA += 1e-9
+		hessBB += 1e-9
+		det := hessAA*hessBB - hessAB*hessAB
+		stepA := (hessBB*gradA - hessAB*gradB) / det
+		stepB := (hessAA*gradB - hessAB*gradA) / det
+		a -= stepA
+		b -= stepB
+		if math.Abs(stepA) < 1e-9 && math.Abs(stepB) < 1e-9 {
+			break
+		}
+	}
+	if a <= 0 || math.IsNaN(a) || math.IsNaN(b) {
+		return Sigmoid{}, errors.New("feedback does not score relevant results higher")
+	}
+
+	// σ(a×(s - mean)/std + b) = σ(a/std × (s - (mean - b×std/a)))
+	return Sigmoid{Slope: a / std, Midpoint: mean - b*std/a}, nil
+}
+
+// curve is the calibration of a mode as written in the calibration file: exactly one of its fields
+type curve struct {
+	Sigmoid     *Sigmoid  `json:"sigmoid,omitempty"`
+	Percentiles []float64 `json:"percentiles,omitempty"`
+	Feedback    []Sample  `json:"feedback,omitempty"`
+}
+
+// mapping validates c and returns its mapping, fitting feedback to a sigmoid
+func (c curve) mapping() (Mapping, error) {
+	set := 0
+	if c.Sigmoid != nil {
+		set++
+	}
+	if c.Percentiles != nil {
+		set++
+	}
+	if c.Feedback != nil {
+		set++
+	}
+	if set != 1 {
+		return nil, errors.New("expected one of sigmoid, percentiles or feedback")
+	}
+
+	switch {
+	case c.Sigmoid != nil:
+		if c.Sigmoid.Slope <= 0 {
+			return nil, errors.New("sigmoid slope must be a number above 0")
+		}
+		return *c.Sigmoid, nil
+	case c.Percentiles != nil:
+		if len(c.Percentiles) < 2 {
+			return nil, errors.New("expected at least 2 percentiles")
+		}
+		for i := 1; i < len(c.Percentiles); i++ {
+			if c.Percentiles[i] <= c.Percentiles[i-1] {
+				return nil, errors.New("percentiles must increase")
+			}
+		}
+		return Percentiles(c.Percentiles), nil
+	default:
+		return FitSigmoid(c.Feedback)
+	}
+}
+
+// Calibration maps the scores of each search mode to probabilities of relevance, so scores are
+// comparable across modes and pages. Modes without a mapping keep their raw scores; a nil
+// Calibration leaves every score unchanged.
+type Calibration map[models.SearchMode]Mapping
+
+// Parse parses a calibration file: a JSON object whose keys are search modes and whose values
+// have one of "sigmoid" ({"slope": 8, "midpoint": 0.6}), "percentiles" (raw scores at evenly
+// spaced quantiles, lowest first) or "feedback" ([{"score": 0.7, "relevant": true}, ...], fitted
+// to a sigmoid). It returns nil for an empty object.
+func Parse(data []byte) (Calibration, error) {
+	var curves map[string]curve
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&curves); err != nil {
+		return nil, fmt.Errorf("invalid calibration: %w", err)
+	}
+
+	var c Calibration
+	for mode, curve := range curves {
+		if err := models.ValidateSearchMode(mode); err != nil {
+			return nil, fmt.Errorf("invalid calibration: %w", err)
+		}
+		mapping, err := curve.mapping()
+		if err != nil {
+			return nil, fmt.Errorf("invalid calibration of %s: %w", mode, err)
+		}
+		if c == nil {
+			c = make(Calibration)
+		}
+		c[models.SearchMode(mode)] = mapping
+	}
+	return c, nil
+}
+
+// FromEnvironment reads the calibration file SEARCH_CALIBRATION_FILE, see Parse; unset
+// calibrates nothing
+func FromEnvironment() (Calibration, error) {
+	path := os.Getenv("SEARCH_CALIBRATION_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SEARCH_CALIBRATION_FILE: %w", err)
+	}
+	return Parse(data)
+}
+
+// Has reports whether the scores of mode are calibrated
+func (c Calibration) Has(mode models.SearchMode) bool {
+	_, ok := c[mode]
+	return ok
+}
+
+// Apply maps the scores of results found in mode, keeping their order
+func (c Calibration) Apply(mode models.SearchMode, results []models.SearchResult) {
+	mapping, ok := c[mode]
+	if !ok {
+		return
+	}
+	for i := range results {
+		results[i].Score = mapping.Map(results[i].Score)
+	}
+}
+
+// String lists the calibrated modes with their mappings, such as "ai=sigmoid(slope=8, midpoint=0.6)"
+func (c Calibration) String() string {
+	items := make([]string, 0, len(c))
+	for mode, mapping := range c {
+		items = append(items, fmt.Sprintf("%s=%s", mode, mapping))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ", ")
+}
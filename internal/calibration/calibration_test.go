@@ -0,0 +1,127 @@
+package calibration
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestSigmoid(t *testing.T) {
+	s := Sigmoid{Slope: 10, Midpoint: 0.5}
+	if score := s.Map(0.5); score != 0.5 {
+		t.Errorf("Expected the midpoint to map to 0.5, got %v", score)
+	}
+	if low, high := s.Map(0.2), s.Map(0.8); low >= 0.5 || high <= 0.5 || math.Abs(low+high-1) > 1e-9 {
+		t.Errorf("Expected symmetric scores around 0.5, got %v and %v", low, high)
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	p := Percentiles{1, 2, 4, 8, 16}
+	tests := map[float64]float64{0: 0, 1: 0, 1.5: 0.125, 4: 0.5, 6: 0.625, 16: 1, 100: 1}
+	for score, expected := range tests {
+		if actual := p.Map(score); math.Abs(actual-expected) > 1e-9 {
+			t.Errorf("Map(%v) = %v, expected %v", score, actual, expected)
+		}
+	}
+}
+
+func TestFitSigmoid(t *testing.T) {
+	var samples []Sample
+	for i := 0; i < 100; i++ {
+		score := float64(i) / 100
+		// Relevance grows with the score, with noise around 0.6
+		relevant := score > 0.6 || (score > 0.5 && i%2 == 0)
+		samples = append(samples, Sample{Score: score, Relevant: relevant})
+	}
+
+	s, err := FitSigmoid(samples)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.Slope <= 0 || s.Midpoint < 0.5 || s.Midpoint > 0.65 {
+		t.Errorf("Expected a rising sigmoid with its midpoint around 0.57, got %s", s)
+	}
+	if s.Map(0.9) < 0.9 || s.Map(0.2) > 0.1 {
+		t.Errorf("Expected confident scores far from the midpoint, got %v and %v", s.Map(0.9), s.Map(0.2))
+	}
+
+	for name, samples := range map[string][]Sample{
+		"only relevant": {{Score: 0.1, Relevant: true}, {Score: 0.9, Relevant: true}},
+		"same score":    {{Score: 0.5, Relevant: true}, {Score: 0.5}},
+		"inverted":      {{Score: 0.9}, {Score: 0.8}, {Score: 0.2, Relevant: true}, {Score: 0.1, Relevant: true}},
+	} {
+		if _, err := FitSigmoid(samples); err == nil {
+			t.Errorf("Expected an error for %s feedback", name)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	c, err := Parse([]byte(`{
+		"ai": {"sigmoid": {"slope": 8, "midpoint": 0.6}},
+		"fulltext": {"percentiles": [0.5, 1.5, 3]},
+		"vector": {"feedback": [{"score": 0.1}, {"score": 0.3, "relevant": true}, {"score": 0.2, "relevant": true}, {"score": 0.15}]}
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !c.Has(models.SearchModeAI) || !c.Has(models.SearchModeFullText) || !c.Has(models.SearchModeVector) || c.Has(models.SearchModeHybrid) {
+		t.Errorf("Unexpected calibrated modes: %s", c)
+	}
+
+	results := []models.SearchResult{{Score: 3}, {Score: 1.5}, {Score: 0.1}}
+	c.Apply(models.SearchModeFullText, results)
+	if results[0].Score != 1 || results[1].Score != 0.5 || results[2].Score != 0 {
+		t.Errorf("Unexpected calibrated scores: %v", results)
+	}
+	c.Apply(models.SearchModeHybrid, results)
+	if results[0].Score != 1 {
+		t.Errorf("Expected uncalibrated modes to keep their scores, got %v", results[0].Score)
+	}
+
+	if c, err := Parse([]byte(`{}`)); err != nil || c != nil {
+		t.Errorf("Expected no calibration, got %v, %v", c, err)
+	}
+
+	for _, data := range []string{
+		`{"fast": {"sigmoid": {"slope": 1}}}`,
+		`{"ai": {}}`,
+		`{"ai": {"sigmoid": {"slope": 1}, "percentiles": [1, 2]}}`,
+		`{"ai": {"sigmoid": {"slope": -1}}}`,
+		`{"ai": {"percentiles": [1]}}`,
+		`{"ai": {"percentiles": [2, 1]}}`,
+		`{"ai": {"curve": "linear"}}`,
+		`[]`,
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Expected an error for %s", data)
+		}
+	}
+}
+
+func TestFromEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calibration.json")
+	if err := os.WriteFile(path, []byte(`{"ai": {"sigmoid": {"slope": 8, "midpoint": 0.6}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SEARCH_CALIBRATION_FILE", path)
+	c, err := FromEnvironment()
+	if err != nil || c.String() != "ai=sigmoid(slope=8, midpoint=0.6)" {
+		t.Errorf("Unexpected calibration: %v, %v", c, err)
+	}
+
+	t.Setenv("SEARCH_CALIBRATION_FILE", "")
+	if c, err := FromEnvironment(); err != nil || c != nil {
+		t.Errorf("Expected no calibration, got %v, %v", c, err)
+	}
+
+	t.Setenv("SEARCH_CALIBRATION_FILE", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := FromEnvironment(); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
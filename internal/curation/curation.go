@@ -0,0 +1,117 @@
+package curation
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// namePattern restricts curation names to characters that need no escaping in a URL
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// How the pattern of a curation matches queries
+const (
+	MatchExact  = "exact"  // The query is the pattern
+	MatchPhrase = "phrase" // The query contains the words of the pattern in order
+)
+
+// maxDocuments limits the documents a curation pins or hides
+const maxDocuments = 50
+
+// Curation pins documents to the top of the results of the queries matching its pattern, in
+// order, and hides others from them ("best bets"). Patterns and queries are compared
+// case-insensitively with their whitespace collapsed, see Normalize.
+type Curation struct {
+	Name      string    `json:"name"`
+	Pattern   string    `json:"pattern"`
+	Match     string    `json:"match,omitempty"`  // MatchExact (the default) or MatchPhrase
+	Pinned    []int     `json:"pinned,omitempty"` // Documents placed first on the first page, in order
+	Hidden    []int     `json:"hidden,omitempty"` // Documents removed from the results
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Normalize lowercases query and collapses its whitespace, the form patterns are compared in
+func Normalize(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// Validate checks the name, the pattern, the match type and the document ids, which must be
+// positive and not both pinned and hidden
+func (c Curation) Validate() error {
+	var errs validation.Errors
+	if !namePattern.MatchString(c.Name) {
+		errs.Add("name", validation.InvalidFormat("name", c.Name, "curation_name"))
+	}
+	if Normalize(c.Pattern) == "" {
+		errs.Add("pattern", validation.Required("pattern"))
+	}
+	if c.Match != "" && c.Match != MatchExact && c.Match != MatchPhrase {
+		errs.Add("match", validation.InvalidValue("match", c.Match, MatchExact, MatchPhrase))
+	}
+	if len(c.Pinned) == 0 && len(c.Hidden) == 0 {
+		errs.Add("pinned", validation.Required("pinned"))
+	}
+
+	for _, list := range []struct {
+		field string
+		ids   []int
+	}{{"pinned", c.Pinned}, {"hidden", c.Hidden}} {
+		if len(list.ids) > maxDocuments {
+			errs.Add(list.field, validation.OutOfRange(list.field, strconv.Itoa(len(list.ids)), 0, maxDocuments))
+		}
+		for _, id := range list.ids {
+			if id <= 0 {
+				errs.Add(list.field, validation.TooSmall(list.field, strconv.Itoa(id), 1))
+			}
+		}
+	}
+	for _, id := range c.Hidden {
+		if containsID(c.Pinned, id) {
+			errs.Add("hidden", validation.InvalidValue("hidden", strconv.Itoa(id)))
+		}
+	}
+	return errs.Err()
+}
+
+// Matches reports whether the curation applies to query
+func (c Curation) Matches(query string) bool {
+	query, pattern := Normalize(query), Normalize(c.Pattern)
+	if pattern == "" {
+		return false
+	}
+	if c.Match != MatchPhrase {
+		return query == pattern
+	}
+	return strings.Contains(" "+query+" ", " "+pattern+" ")
+}
+
+// Hides reports whether the curation removes the document from the results, which it does with
+// both its hidden and its pinned documents so pinned ones aren't listed twice
+func (c Curation) Hides(id int) bool {
+	return containsID(c.Pinned, id) || containsID(c.Hidden, id)
+}
+
+// containsID reports whether ids lists id
+func containsID(ids []int, id int) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+// precedes reports whether c wins over other when both match a query
+func (c *Curation) precedes(other *Curation) bool {
+	if exact, otherExact := c.Match != MatchPhrase, other.Match != MatchPhrase; exact != otherExact {
+		return exact
+	}
+	if length, otherLength := len(Normalize(c.Pattern)), len(Normalize(other.Pattern)); length != otherLength {
+		return length > otherLength
+	}
+	return c.Name < other.Name
+}
@@ -0,0 +1,119 @@
+package curation
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	valid := Curation{Name: "pricing", Pattern: "pricing", Pinned: []int{4}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	tests := map[string]Curation{
+		"name":           {Name: "Pricing page", Pattern: "pricing", Pinned: []int{4}},
+		"pattern":        {Name: "pricing", Pattern: "  ", Pinned: []int{4}},
+		"match":          {Name: "pricing", Pattern: "pricing", Match: "prefix", Pinned: []int{4}},
+		"no documents":   {Name: "pricing", Pattern: "pricing"},
+		"id":             {Name: "pricing", Pattern: "pricing", Pinned: []int{0}},
+		"pinned, hidden": {Name: "pricing", Pattern: "pricing", Pinned: []int{4}, Hidden: []int{4}},
+	}
+	for name, curation := range tests {
+		if err := curation.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	exact := Curation{Pattern: "Pricing  Plans"}
+	phrase := Curation{Pattern: "pricing plans", Match: MatchPhrase}
+
+	tests := []struct {
+		query         string
+		exact, phrase bool
+	}{
+		{"pricing plans", true, true},
+		{" PRICING\tplans ", true, true},
+		{"enterprise pricing plans 2025", false, true},
+		{"pricing plansx", false, false},
+		{"plans pricing", false, false},
+	}
+	for _, tt := range tests {
+		if got := exact.Matches(tt.query); got != tt.exact {
+			t.Errorf("Exact %q: expected %v, got %v", tt.query, tt.exact, got)
+		}
+		if got := phrase.Matches(tt.query); got != tt.phrase {
+			t.Errorf("Phrase %q: expected %v, got %v", tt.query, tt.phrase, got)
+		}
+	}
+}
+
+func TestStoreMatch(t *testing.T) {
+	s, _ := NewStore("")
+	for _, curation := range []Curation{
+		{Name: "plans", Pattern: "plans", Match: MatchPhrase, Pinned: []int{1}},
+		{Name: "pricing-plans", Pattern: "pricing plans", Match: MatchPhrase, Pinned: []int{2}},
+		{Name: "exact", Pattern: "pricing plans", Pinned: []int{3}},
+	} {
+		if _, err := s.Put(curation); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	for query, expected := range map[string]string{
+		"pricing plans":      "exact",
+		"team pricing plans": "pricing-plans",
+		"plans":              "plans",
+	} {
+		if curation, ok := s.Match(query); !ok || curation.Name != expected {
+			t.Errorf("%q: expected %s, got %q", query, expected, curation.Name)
+		}
+	}
+	if _, ok := s.Match("pricing"); ok {
+		t.Error("Expected no curation to match")
+	}
+}
+
+func TestStorePutGetDelete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "curations.json")
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	created, err := s.Put(Curation{Name: "pricing", Pattern: "pricing", Pinned: []int{4}})
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	replaced, err := s.Put(Curation{Name: "pricing", Pattern: "pricing", Pinned: []int{4}, Hidden: []int{7}})
+	if err != nil || !replaced.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("Expected the replacement to keep the creation time, got %+v, %v", replaced, err)
+	}
+
+	reopened, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	if loaded, ok := reopened.Get("pricing"); !ok || len(loaded.Hidden) != 1 || loaded.Hidden[0] != 7 {
+		t.Errorf("Unexpected reloaded curation: %+v", loaded)
+	}
+
+	if deleted, err := reopened.Delete("pricing"); !deleted || err != nil {
+		t.Errorf("Expected the curation to be deleted, got %v, %v", deleted, err)
+	}
+	if _, ok := reopened.Match("pricing"); ok {
+		t.Error("Expected the curation to be gone")
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	var s *Store
+	if _, err := s.Put(Curation{Name: "pricing"}); err == nil {
+		t.Error("Expected Put on a nil store to fail")
+	}
+	if _, ok := s.Match("pricing"); ok || s.List() != nil {
+		t.Error("Expected a nil store to hold nothing")
+	}
+}
@@ -0,0 +1,176 @@
+package curation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/jsonfile"
+)
+
+// Store keeps curations in memory and, when it has a path, rewrites them to a JSON file after
+// every change so they survive restarts. A nil *Store is valid and holds nothing.
+type Store struct {
+	mutex     sync.RWMutex
+	path      string
+	curations map[string]*Curation
+}
+
+// NewStore creates a store. When path is empty curations are kept in memory only; otherwise
+// existing ones are loaded from the file.
+func NewStore(path string) (*Store, error) {
+	s := &Store{
+		path:      path,
+		curations: make(map[string]*Curation),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read curations %s: %v", path, err)
+	}
+
+	var curations []*Curation
+	if err := json.Unmarshal(data, &curations); err != nil {
+		return nil, fmt.Errorf("failed to parse curations %s: %v", path, err)
+	}
+	for _, curation := range curations {
+		s.curations[curation.Name] = curation
+	}
+	return s, nil
+}
+
+// NewStoreFromEnvironment creates a store persisted to CURATIONS_FILE
+func NewStoreFromEnvironment() (*Store, error) {
+	return NewStore(os.Getenv("CURATIONS_FILE"))
+}
+
+// Put stores a curation, replacing the one with the same name but keeping its creation time
+func (s *Store) Put(curation Curation) (Curation, error) {
+	if s == nil {
+		return Curation{}, fmt.Errorf("curations are not available")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	curation.UpdatedAt = time.Now()
+	curation.CreatedAt = curation.UpdatedAt
+	previous, replaced := s.curations[curation.Name]
+	if replaced {
+		curation.CreatedAt = previous.CreatedAt
+	}
+	s.curations[curation.Name] = &curation
+
+	if err := s.save(); err != nil {
+		if replaced {
+			s.curations[curation.Name] = previous
+		} else {
+			delete(s.curations, curation.Name)
+		}
+		return Curation{}, err
+	}
+	return curation, nil
+}
+
+// Get returns the curation with the given name
+func (s *Store) Get(name string) (Curation, bool) {
+	if s == nil {
+		return Curation{}, false
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	curation, ok := s.curations[name]
+	if !ok {
+		return Curation{}, false
+	}
+	return *curation, true
+}
+
+// List returns every curation ordered by name
+func (s *Store) List() []Curation {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]Curation, 0, len(s.curations))
+	for _, curation := range s.curations {
+		result = append(result, *curation)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Match returns the curation applying to query. When several do, exact curations win over
+// phrase ones, then the longest pattern, then the first name.
+func (s *Store) Match(query string) (Curation, bool) {
+	if s == nil {
+		return Curation{}, false
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var best *Curation
+	for _, curation := range s.curations {
+		if curation.Matches(query) && (best == nil || curation.precedes(best)) {
+			best = curation
+		}
+	}
+	if best == nil {
+		return Curation{}, false
+	}
+	return *best, true
+}
+
+// Delete removes a curation and reports whether it existed
+func (s *Store) Delete(name string) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	curation, ok := s.curations[name]
+	if !ok {
+		return false, nil
+	}
+	delete(s.curations, name)
+	if err := s.save(); err != nil {
+		s.curations[name] = curation
+		return false, err
+	}
+	return true, nil
+}
+
+// save rewrites the file atomically (caller holds the lock)
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	curations := make([]*Curation, 0, len(s.curations))
+	for _, curation := range s.curations {
+		curations = append(curations, curation)
+	}
+	sort.Slice(curations, func(i, j int) bool { return curations[i].Name < curations[j].Name })
+
+	if err := jsonfile.WriteAtomic(s.path, curations); err != nil {
+		return fmt.Errorf("failed to write curations: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package deadletter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// DefaultMaxRecent is the number of entries kept in memory for queries
+const DefaultMaxRecent = 1000
+
+// Entry is a document that was not indexed, with the reasons it was rejected
+type Entry struct {
+	ID        int64           `json:"id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Source    string          `json:"source"` // the ingestion that rejected the document, such as startup or reindex
+	Document  models.Document `json:"document"`
+	Reasons   []string        `json:"reasons"`
+}
+
+// Store keeps the documents rejected by ingestion. Entries are written as JSON lines to an
+// optional file and the most recent ones are kept in memory for the query endpoint.
+// A nil *Store is valid and keeps nothing.
+type Store struct {
+	mutex     sync.RWMutex
+	file      *os.File
+	recent    []Entry
+	maxRecent int
+	nextID    int64
+}
+
+// New creates a dead-letter store. When path is empty entries are kept in memory only;
+// otherwise existing entries are loaded from the file and new ones are appended to it.
+func New(path string, maxRecent int) (*Store, error) {
+	if maxRecent <= 0 {
+		maxRecent = DefaultMaxRecent
+	}
+
+	s := &Store{
+		maxRecent: maxRecent,
+		nextID:    1,
+	}
+
+	if path == "" {
+		return s, nil
+	}
+
+	if err := s.load(path); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dead-letter file %s: %v", path, err)
+	}
+	s.file = file
+
+	return s, nil
+}
+
+// NewFromEnvironment creates a dead-letter store persisted to DEAD_LETTER_FILE
+func NewFromEnvironment() (*Store, error) {
+	return New(os.Getenv("DEAD_LETTER_FILE"), DefaultMaxRecent)
+}
+
+// load reads existing entries so IDs keep increasing and recent rejections survive restarts
+func (s *Store) load(path string) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read dead-letter file %s: %v", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			log.Printf("[DEAD_LETTER] Skipping malformed dead-letter line: %v", err)
+			continue
+		}
+		s.append(entry)
+		if entry.ID >= s.nextID {
+			s.nextID = entry.ID + 1
+		}
+	}
+	return scanner.Err()
+}
+
+// Add stores a rejected document, assigning the entry's ID and timestamp
+func (s *Store) Add(source string, doc *models.Document, reasons []string) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry := Entry{
+		ID:        s.nextID,
+		Timestamp: time.Now(),
+		Source:    source,
+		Document:  *doc,
+		Reasons:   reasons,
+	}
+	s.nextID++
+	s.append(entry)
+
+	if s.file == nil {
+		return
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("[DEAD_LETTER] Failed to marshal dead-letter entry: %v", err)
+		return
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		log.Printf("[DEAD_LETTER] Failed to write dead-letter entry: %v", err)
+	}
+}
+
+// append adds an entry to the in-memory window (caller holds the lock)
+func (s *Store) append(entry Entry) {
+	s.recent = append(s.recent, entry)
+	if len(s.recent) > s.maxRecent {
+		s.recent = s.recent[len(s.recent)-s.maxRecent:]
+	}
+}
+
+// Recent returns up to limit entries, newest first, optionally filtered by source
+func (s *Store) Recent(limit int, source string) []Entry {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]Entry, 0)
+	for i := len(s.recent) - 1; i >= 0; i-- {
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+		if source != "" && s.recent[i].Source != source {
+			continue
+		}
+		result = append(result, s.recent[i])
+	}
+	return result
+}
+
+// Close closes the underlying file
+func (s *Store) Close() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
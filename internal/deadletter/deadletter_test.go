@@ -0,0 +1,71 @@
+package deadletter
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestStoreAddAndRecent(t *testing.T) {
+	s, err := New("", 3)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for i, source := range []string{"startup", "reindex", "startup", "startup"} {
+		s.Add(source, &models.Document{ID: i + 1, Title: "Doc"}, []string{"url is required"})
+	}
+
+	recent := s.Recent(0, "")
+	if len(recent) != 3 {
+		t.Fatalf("Expected 3 entries kept in memory, got %d", len(recent))
+	}
+	if recent[0].ID != 4 || recent[0].Document.ID != 4 {
+		t.Errorf("Expected newest entry first, got %+v", recent[0])
+	}
+	if filtered := s.Recent(0, "reindex"); len(filtered) != 1 || filtered[0].Reasons[0] != "url is required" {
+		t.Errorf("Expected 1 reindex entry, got %+v", filtered)
+	}
+	if limited := s.Recent(1, ""); len(limited) != 1 {
+		t.Errorf("Expected limit to apply, got %d entries", len(limited))
+	}
+}
+
+func TestStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letters.jsonl")
+
+	s, err := New(path, 10)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	s.Add("startup", &models.Document{ID: 7, Title: "Seven", Content: "text"}, []string{"content is 4 characters, more than 2"})
+	s.Close()
+
+	reopened, err := New(path, 10)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer reopened.Close()
+
+	recent := reopened.Recent(0, "")
+	if len(recent) != 1 || recent[0].Document.Title != "Seven" || len(recent[0].Reasons) != 1 {
+		t.Fatalf("Expected entries to be loaded from file, got %+v", recent)
+	}
+
+	reopened.Add("reindex", &models.Document{ID: 8}, []string{"title is required"})
+	if id := reopened.Recent(1, "")[0].ID; id != 2 {
+		t.Errorf("Expected IDs to continue after reload, got %d", id)
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	var s *Store
+	s.Add("startup", &models.Document{ID: 1}, []string{"title is required"})
+	if recent := s.Recent(10, ""); recent != nil {
+		t.Errorf("Expected no entries, got %+v", recent)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+package docstore
+
+import (
+	"container/list"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// Default limits used when DOCUMENT_STORE_MAX_DOCUMENTS and DOCUMENT_STORE_MAX_SIZE are unset
+const (
+	DefaultMaxDocuments       = 10000
+	DefaultMaxSize      int64 = 64 << 20
+)
+
+// documentOverhead approximates the memory of a document besides its strings
+const documentOverhead = 128
+
+// Store keeps a bounded set of the indexed documents in memory. Manticore holds the whole corpus:
+// documents beyond the limits are evicted, least recently used first, and loaded again on demand.
+// TF-IDF vectors are not kept, vector search reads them from Manticore. A nil *Store is valid,
+// keeps nothing and loads every document.
+type Store struct {
+	mutex        sync.Mutex
+	maxDocuments int   // 0 for no limit
+	maxSize      int64 // 0 for no limit
+	order        *list.List
+	entries      map[int64]*list.Element
+	size         int64
+
+	documents       int
+	averageLength   float64
+	vectorDimension int
+	hits            uint64
+	misses          uint64
+	evictions       uint64
+}
+
+type entry struct {
+	id   int64
+	doc  *models.Document
+	size int64
+}
+
+// Stats describes the documents of a Store and how well it serves them from memory
+type Stats struct {
+	Documents             int     // documents indexed by the last Replace
+	AverageDocumentLength float64 // average characters of title and content of those documents
+	VectorDimension       int     // dimension of their TF-IDF vectors
+	Cached                int     // documents kept in memory
+	Size                  int64   // approximate bytes of the cached documents
+	MaxDocuments          int
+	MaxSize               int64
+	Hits                  uint64
+	Misses                uint64 // documents loaded on demand
+	Evictions             uint64
+}
+
+// New creates a store keeping up to maxDocuments documents of up to maxSize bytes in total; 0
+// disables a limit
+func New(maxDocuments int, maxSize int64) *Store {
+	return &Store{
+		maxDocuments: maxDocuments,
+		maxSize:      maxSize,
+		order:        list.New(),
+		entries:      make(map[int64]*list.Element),
+	}
+}
+
+// NewFromEnvironment creates a store limited by DOCUMENT_STORE_MAX_DOCUMENTS (default
+// DefaultMaxDocuments) and DOCUMENT_STORE_MAX_SIZE in bytes (default DefaultMaxSize). 0 disables
+// a limit.
+func NewFromEnvironment() (*Store, error) {
+	maxDocuments := DefaultMaxDocuments
+	if value := os.Getenv("DOCUMENT_STORE_MAX_DOCUMENTS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid DOCUMENT_STORE_MAX_DOCUMENTS: %q", value)
+		}
+		maxDocuments = parsed
+	}
+
+	maxSize := DefaultMaxSize
+	if value := os.Getenv("DOCUMENT_STORE_MAX_SIZE"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid DOCUMENT_STORE_MAX_SIZE: %q", value)
+		}
+		maxSize = parsed
+	}
+
+	return New(maxDocuments, maxSize), nil
+}
+
+// Replace forgets the kept documents and keeps the newly indexed ones, as far as the limits
+// allow. Documents without a status are kept as active, the status Manticore reports for them.
+// Only the dimension of vectors is recorded.
+func (s *Store) Replace(documents []*models.Document, vectors [][]float64) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.order.Init()
+	s.entries = make(map[int64]*list.Element)
+	s.size = 0
+
+	var length int
+	for _, doc := range documents {
+		length += utf8.RuneCountInString(doc.Title) + utf8.RuneCountInString(doc.Content)
+
+		kept := *doc
+		if kept.Status == "" {
+			kept.Status = models.DocumentStatusActive
+		}
+		s.put(&kept)
+	}
+
+	s.documents = len(documents)
+	s.averageLength = 0
+	if len(documents) > 0 {
+		s.averageLength = float64(length) / float64(len(documents))
+	}
+	s.vectorDimension = 0
+	if len(vectors) > 0 {
+		s.vectorDimension = len(vectors[0])
+	}
+}
+
+// Get returns the document with id, loading it with load and keeping it when it is not in
+// memory. The returned document must not be modified.
+func (s *Store) Get(id int64, load func(id int64) (*models.Document, error)) (*models.Document, error) {
+	if s == nil {
+		return load(id)
+	}
+
+	s.mutex.Lock()
+	if element, ok := s.entries[id]; ok {
+		s.order.MoveToFront(element)
+		s.hits++
+		doc := element.Value.(*entry).doc
+		s.mutex.Unlock()
+		return doc, nil
+	}
+	s.misses++
+	s.mutex.Unlock()
+
+	// Load without holding the lock, so a slow backend does not block other documents
+	doc, err := load(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.put(doc)
+	return doc, nil
+}
+
+// Invalidate forgets the document with id, so the next Get loads it again
+func (s *Store) Invalidate(id int64) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if element, ok := s.entries[id]; ok {
+		s.remove(element)
+	}
+}
+
+// Documents returns the documents kept in memory, the most recently used first. They must not
+// be modified.
+func (s *Store) Documents() []*models.Document {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	documents := make([]*models.Document, 0, s.order.Len())
+	for element := s.order.Front(); element != nil; element = element.Next() {
+		documents = append(documents, element.Value.(*entry).doc)
+	}
+	return documents
+}
+
+// Count returns the number of documents indexed by the last Replace
+func (s *Store) Count() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.documents
+}
+
+// Stats returns a snapshot of the store's contents and counters
+func (s *Store) Stats() Stats {
+	if s == nil {
+		return Stats{}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return Stats{
+		Documents:             s.documents,
+		AverageDocumentLength: s.averageLength,
+		VectorDimension:       s.vectorDimension,
+		Cached:                s.order.Len(),
+		Size:                  s.size,
+		MaxDocuments:          s.maxDocuments,
+		MaxSize:               s.maxSize,
+		Hits:                  s.hits,
+		Misses:                s.misses,
+		Evictions:             s.evictions,
+	}
+}
+
+// put keeps doc as the most recently used document and evicts the least recently used ones
+// beyond the limits. A document larger than maxSize on its own is not kept.
+func (s *Store) put(doc *models.Document) {
+	id := int64(doc.ID)
+	if element, ok := s.entries[id]; ok {
+		s.remove(element)
+	}
+
+	size := documentSize(doc)
+	if s.maxSize > 0 && size > s.maxSize {
+		return
+	}
+	s.entries[id] = s.order.PushFront(&entry{id: id, doc: doc, size: size})
+	s.size += size
+
+	for (s.maxDocuments > 0 && s.order.Len() > s.maxDocuments) || (s.maxSize > 0 && s.size > s.maxSize) {
+		s.remove(s.order.Back())
+		s.evictions++
+	}
+}
+
+func (s *Store) remove(element *list.Element) {
+	e := s.order.Remove(element).(*entry)
+	delete(s.entries, e.id)
+	s.size -= e.size
+}
+
+// documentSize approximates the memory held by doc
+func documentSize(doc *models.Document) int64 {
+	size := documentOverhead + len(doc.Title) + len(doc.URL) + len(doc.Content) + len(doc.Snippet) + len(doc.Status)
+	for _, tag := range doc.Tags {
+		size += len(tag) + 16
+	}
+	return int64(size)
+}
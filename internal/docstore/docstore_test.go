@@ -0,0 +1,142 @@
+package docstore
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// countingLoader loads documents titled by their id and counts the loads
+type countingLoader struct {
+	loads int
+}
+
+func (l *countingLoader) load(id int64) (*models.Document, error) {
+	l.loads++
+	if id > 100 {
+		return nil, errors.New("not found")
+	}
+	return &models.Document{ID: int(id), Title: "loaded"}, nil
+}
+
+func TestReplaceAndGet(t *testing.T) {
+	store := New(2, 0)
+	store.Replace([]*models.Document{
+		{ID: 1, Title: "Форма", Content: "обратной связи"},
+		{ID: 2, Title: "Pricing", Content: "plans"},
+		{ID: 3, Title: "Search", Content: "tips"},
+	}, [][]float64{{0, 1}, {1, 0}, {1, 1}})
+
+	stats := store.Stats()
+	if stats.Documents != 3 || stats.Cached != 2 || stats.Evictions != 1 || stats.VectorDimension != 2 {
+		t.Errorf("Unexpected stats after replace: %+v", stats)
+	}
+	if stats.AverageDocumentLength != float64(5+14+7+5+6+4)/3 {
+		t.Errorf("Unexpected average document length %v", stats.AverageDocumentLength)
+	}
+
+	loader := &countingLoader{}
+	doc, err := store.Get(3, loader.load)
+	if err != nil || doc.Title != "Search" || doc.Status != models.DocumentStatusActive {
+		t.Errorf("Expected the kept document as active, got %+v, %v", doc, err)
+	}
+
+	// The first document was evicted, so it is loaded and evicts the least recently used one
+	doc, err = store.Get(1, loader.load)
+	if err != nil || doc.Title != "loaded" || loader.loads != 1 {
+		t.Errorf("Expected the evicted document to be loaded, got %+v, %v after %d loads", doc, err, loader.loads)
+	}
+	if _, err := store.Get(1, loader.load); err != nil || loader.loads != 1 {
+		t.Errorf("Expected the loaded document to be kept, got %v after %d loads", err, loader.loads)
+	}
+	if _, err := store.Get(2, loader.load); err != nil || loader.loads != 2 {
+		t.Errorf("Expected document 2 to have been evicted, got %v after %d loads", err, loader.loads)
+	}
+
+	stats = store.Stats()
+	if stats.Hits != 2 || stats.Misses != 2 || stats.Cached != 2 {
+		t.Errorf("Unexpected stats after gets: %+v", stats)
+	}
+}
+
+func TestGetErrorIsNotKept(t *testing.T) {
+	store := New(0, 0)
+	loader := &countingLoader{}
+
+	if _, err := store.Get(101, loader.load); err == nil {
+		t.Fatal("Expected the load error")
+	}
+	if store.Stats().Cached != 0 {
+		t.Error("Expected a failed load to keep nothing")
+	}
+}
+
+func TestSizeLimit(t *testing.T) {
+	store := New(0, 2*documentOverhead+100)
+	store.Replace([]*models.Document{
+		{ID: 1, Content: strings.Repeat("a", 40)},
+		{ID: 2, Content: strings.Repeat("b", 40)},
+		{ID: 3, Content: strings.Repeat("c", 40)},
+		{ID: 4, Content: strings.Repeat("d", 1000)},
+	}, nil)
+
+	stats := store.Stats()
+	if stats.Cached != 2 || stats.Size > stats.MaxSize {
+		t.Errorf("Expected the size limit to keep 2 documents, got %+v", stats)
+	}
+	ids := []int{}
+	for _, doc := range store.Documents() {
+		ids = append(ids, doc.ID)
+	}
+	if len(ids) != 2 || ids[0] != 3 || ids[1] != 2 {
+		t.Errorf("Expected the last documents within the limit, most recent first, got %v", ids)
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	store := New(0, 0)
+	store.Replace([]*models.Document{{ID: 1, Title: "Old"}}, nil)
+	store.Invalidate(1)
+
+	loader := &countingLoader{}
+	if doc, _ := store.Get(1, loader.load); doc.Title != "loaded" || loader.loads != 1 {
+		t.Errorf("Expected an invalidated document to be loaded again, got %+v", doc)
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	var store *Store
+	store.Replace([]*models.Document{{ID: 1}}, nil)
+	store.Invalidate(1)
+
+	loader := &countingLoader{}
+	if doc, err := store.Get(1, loader.load); err != nil || doc.Title != "loaded" {
+		t.Errorf("Expected a nil store to load documents, got %+v, %v", doc, err)
+	}
+	if store.Count() != 0 || store.Documents() != nil || store.Stats() != (Stats{}) {
+		t.Error("Expected a nil store to keep nothing")
+	}
+}
+
+func TestNewFromEnvironment(t *testing.T) {
+	t.Setenv("DOCUMENT_STORE_MAX_DOCUMENTS", "")
+	t.Setenv("DOCUMENT_STORE_MAX_SIZE", "")
+	store, err := NewFromEnvironment()
+	if err != nil || store.maxDocuments != DefaultMaxDocuments || store.maxSize != DefaultMaxSize {
+		t.Errorf("Expected the default limits, got %+v, %v", store, err)
+	}
+
+	t.Setenv("DOCUMENT_STORE_MAX_DOCUMENTS", "0")
+	t.Setenv("DOCUMENT_STORE_MAX_SIZE", "1048576")
+	store, err = NewFromEnvironment()
+	if err != nil || store.maxDocuments != 0 || store.maxSize != 1<<20 {
+		t.Errorf("Expected the configured limits, got %+v, %v", store, err)
+	}
+
+	t.Setenv("DOCUMENT_STORE_MAX_SIZE", "-1")
+	if _, err := NewFromEnvironment(); err == nil {
+		t.Error("Expected an error for a negative size")
+	}
+}
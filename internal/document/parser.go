@@ -23,7 +23,50 @@ func generateDocumentID(filePath string) int {
 	return int(id & 0x7FFFFFFF)
 }
 
-// ParseMarkdownFile parses a single markdown file and extracts title, URL, and content
+// frontmatterDelimiter opens and closes the frontmatter block at the top of a file
+const frontmatterDelimiter = "---"
+
+// tagKeys are the frontmatter keys whose values become document tags
+var tagKeys = map[string]bool{"tags": true, "categories": true, "category": true}
+
+// parseFrontmatterTags extracts tags from frontmatter lines. Values may be inline lists
+// ("tags: [go, search]"), comma-separated ("tags: go, search") or "- item" lines below the key.
+func parseFrontmatterTags(lines []string) []string {
+	var tags []string
+	inTagList := false
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if inTagList && strings.HasPrefix(trimmed, "- ") {
+			tags = append(tags, unquote(strings.TrimPrefix(trimmed, "- ")))
+			continue
+		}
+		inTagList = false
+
+		key, value, found := strings.Cut(trimmed, ":")
+		if !found || !tagKeys[strings.ToLower(strings.TrimSpace(key))] {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			inTagList = true
+			continue
+		}
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+		for _, tag := range strings.Split(value, ",") {
+			tags = append(tags, unquote(tag))
+		}
+	}
+	return models.NormalizeTags(tags)
+}
+
+// unquote trims whitespace and surrounding quotes from a frontmatter value
+func unquote(value string) string {
+	return strings.Trim(strings.TrimSpace(value), `"'`)
+}
+
+// ParseMarkdownFile parses a single markdown file and extracts title, URL, tags from an optional
+// frontmatter block, and content
 func ParseMarkdownFile(filePath string) (*models.Document, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -36,10 +79,31 @@ func ParseMarkdownFile(filePath string) (*models.Document, error) {
 	var contentLines []string
 	titleFound := false
 	urlFound := false
+	started := false
+	inFrontmatter := false
+	var frontmatter []string
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 
+		// Collect the frontmatter block if the file starts with one
+		if !started && line != "" {
+			started = true
+			if line == frontmatterDelimiter {
+				inFrontmatter = true
+				continue
+			}
+		}
+		if inFrontmatter {
+			if line == frontmatterDelimiter {
+				inFrontmatter = false
+				doc.Tags = parseFrontmatterTags(frontmatter)
+			} else {
+				frontmatter = append(frontmatter, scanner.Text())
+			}
+			continue
+		}
+
 		// Extract title from first # line
 		if !titleFound && strings.HasPrefix(line, "#") {
 			doc.Title = strings.TrimSpace(strings.TrimPrefix(line, "#"))
@@ -122,6 +186,11 @@ func ScanDataDirectory(dataDir string) ([]*models.Document, error) {
 		// Generate unique ID based on file path hash for consistency
 		doc.ID = generateDocumentID(path)
 
+		// The file modification time tells when the document last changed
+		if info, infoErr := d.Info(); infoErr == nil {
+			doc.UpdatedAt = info.ModTime().Unix()
+		}
+
 		// Use file path as URL if not already set from document content
 		if doc.URL == "" {
 			doc.URL = path
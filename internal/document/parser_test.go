@@ -0,0 +1,48 @@
+package document
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseFrontmatterTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		expected []string
+	}{
+		{"inline list", []string{"title: Guide", "tags: [Go, 'search']"}, []string{"go", "search"}},
+		{"comma separated", []string{"tags: go, search", "categories: docs"}, []string{"go", "search", "docs"}},
+		{"block list", []string{"tags:", "  - go", `  - "search"`, "author: someone"}, []string{"go", "search"}},
+		{"no tags", []string{"title: Guide"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tags := parseFrontmatterTags(tt.lines); !reflect.DeepEqual(tags, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, tags)
+			}
+		})
+	}
+}
+
+func TestParseMarkdownFileFrontmatter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "guide.md")
+	content := "---\ntags: [go, search]\n---\n# Guide\n**URL:** https://example.com/guide\n\nHow to search.\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	doc, err := ParseMarkdownFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if doc.Title != "Guide" || doc.URL != "https://example.com/guide" || doc.Content != "How to search." {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+	if !reflect.DeepEqual(doc.Tags, []string{"go", "search"}) {
+		t.Errorf("Expected frontmatter tags, got %v", doc.Tags)
+	}
+}
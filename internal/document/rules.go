@@ -0,0 +1,166 @@
+package document
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/stopwords"
+)
+
+// URL formats a document URL is validated against
+const (
+	URLFormatAny  = "any"  // any non-empty URL, such as the file path used for documents without one
+	URLFormatHTTP = "http" // an absolute http or https URL with a host
+)
+
+// Fields that can be required to be non-empty
+var requirableFields = []string{"title", "url", "content", "tags"}
+
+// DefaultRequiredFields are the fields required unless DOCUMENT_REQUIRED_FIELDS is set
+var DefaultRequiredFields = []string{"title", "url", "content"}
+
+// Rules are the checks a document must pass before it is indexed. A nil *Rules accepts every
+// document.
+type Rules struct {
+	Required         []string // fields that must be non-empty
+	MaxContentLength int      // maximum content length in characters, 0 for no limit
+	URLFormat        string   // URLFormatAny or URLFormatHTTP
+	Languages        []string // languages the content must be detected in, empty for any language
+}
+
+// Rejection is a document that failed the rules, with the reasons it failed
+type Rejection struct {
+	Document *models.Document
+	Reasons  []string
+}
+
+// DefaultRules returns the rules used when no DOCUMENT_* variable is set
+func DefaultRules() *Rules {
+	return &Rules{
+		Required:  append([]string(nil), DefaultRequiredFields...),
+		URLFormat: URLFormatAny,
+	}
+}
+
+// RulesFromEnvironment returns the rules configured by DOCUMENT_REQUIRED_FIELDS (comma-separated
+// fields, or "none"), DOCUMENT_MAX_CONTENT_LENGTH, DOCUMENT_URL_FORMAT (any or http) and
+// DOCUMENT_LANGUAGES (comma-separated languages with built-in stopwords)
+func RulesFromEnvironment() (*Rules, error) {
+	rules := DefaultRules()
+
+	if fields := strings.TrimSpace(os.Getenv("DOCUMENT_REQUIRED_FIELDS")); fields != "" {
+		rules.Required = nil
+		if fields != "none" {
+			for _, field := range strings.Split(fields, ",") {
+				field = strings.ToLower(strings.TrimSpace(field))
+				if !slices.Contains(requirableFields, field) {
+					return nil, fmt.Errorf("invalid DOCUMENT_REQUIRED_FIELDS: unknown field %q (use %s)", field, strings.Join(requirableFields, ", "))
+				}
+				rules.Required = append(rules.Required, field)
+			}
+		}
+	}
+
+	if lengthStr := os.Getenv("DOCUMENT_MAX_CONTENT_LENGTH"); lengthStr != "" {
+		length, err := strconv.Atoi(lengthStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DOCUMENT_MAX_CONTENT_LENGTH: %w", err)
+		}
+		if length < 0 {
+			return nil, fmt.Errorf("DOCUMENT_MAX_CONTENT_LENGTH must not be negative, got: %d", length)
+		}
+		rules.MaxContentLength = length
+	}
+
+	if format := os.Getenv("DOCUMENT_URL_FORMAT"); format != "" {
+		if format != URLFormatAny && format != URLFormatHTTP {
+			return nil, fmt.Errorf("invalid DOCUMENT_URL_FORMAT %q (use %s or %s)", format, URLFormatAny, URLFormatHTTP)
+		}
+		rules.URLFormat = format
+	}
+
+	if languages := os.Getenv("DOCUMENT_LANGUAGES"); languages != "" {
+		for _, language := range strings.Split(languages, ",") {
+			language = strings.ToLower(strings.TrimSpace(language))
+			if !slices.Contains(stopwords.Languages(), language) {
+				return nil, fmt.Errorf("invalid DOCUMENT_LANGUAGES: unknown language %q (use %s)", language, strings.Join(stopwords.Languages(), ", "))
+			}
+			rules.Languages = append(rules.Languages, language)
+		}
+	}
+
+	return rules, nil
+}
+
+// Validate returns the reasons doc fails the rules, or nil when it passes them
+func (r *Rules) Validate(doc *models.Document) []string {
+	if r == nil {
+		return nil
+	}
+
+	var reasons []string
+	for _, field := range r.Required {
+		if fieldEmpty(doc, field) {
+			reasons = append(reasons, fmt.Sprintf("%s is required", field))
+		}
+	}
+
+	if r.MaxContentLength > 0 {
+		if length := utf8.RuneCountInString(doc.Content); length > r.MaxContentLength {
+			reasons = append(reasons, fmt.Sprintf("content is %d characters, more than %d", length, r.MaxContentLength))
+		}
+	}
+
+	if r.URLFormat == URLFormatHTTP && doc.URL != "" {
+		if parsed, err := url.Parse(doc.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			reasons = append(reasons, fmt.Sprintf("url %q is not an absolute http or https URL", doc.URL))
+		}
+	}
+
+	if len(r.Languages) > 0 {
+		detected := stopwords.Detect(doc.Title + " " + doc.Content).Language
+		switch {
+		case detected == "":
+			reasons = append(reasons, fmt.Sprintf("language could not be detected (allowed: %s)", strings.Join(r.Languages, ", ")))
+		case !slices.Contains(r.Languages, detected):
+			reasons = append(reasons, fmt.Sprintf("language %s is not allowed (allowed: %s)", detected, strings.Join(r.Languages, ", ")))
+		}
+	}
+
+	return reasons
+}
+
+// Filter splits documents into those passing the rules and the rejected ones, keeping their order
+func (r *Rules) Filter(documents []*models.Document) ([]*models.Document, []Rejection) {
+	valid := make([]*models.Document, 0, len(documents))
+	var rejected []Rejection
+	for _, doc := range documents {
+		if reasons := r.Validate(doc); len(reasons) > 0 {
+			rejected = append(rejected, Rejection{Document: doc, Reasons: reasons})
+			continue
+		}
+		valid = append(valid, doc)
+	}
+	return valid, rejected
+}
+
+// fieldEmpty reports whether field of doc is empty or only whitespace
+func fieldEmpty(doc *models.Document, field string) bool {
+	switch field {
+	case "title":
+		return strings.TrimSpace(doc.Title) == ""
+	case "url":
+		return strings.TrimSpace(doc.URL) == ""
+	case "content":
+		return strings.TrimSpace(doc.Content) == ""
+	case "tags":
+		return len(doc.Tags) == 0
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package document
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestRulesValidate(t *testing.T) {
+	rules := &Rules{
+		Required:         []string{"title", "url", "content", "tags"},
+		MaxContentLength: 20,
+		URLFormat:        URLFormatHTTP,
+		Languages:        []string{"en"},
+	}
+
+	tests := []struct {
+		name    string
+		doc     models.Document
+		reasons []string
+	}{
+		{
+			name: "valid",
+			doc:  models.Document{Title: "Guide", URL: "https://example.com/guide", Content: "the search guide", Tags: []string{"go"}},
+		},
+		{
+			name:    "missing fields",
+			doc:     models.Document{Title: " ", URL: "https://example.com", Content: "the guide"},
+			reasons: []string{"title is required", "tags is required"},
+		},
+		{
+			name:    "content too long",
+			doc:     models.Document{Title: "Guide", URL: "https://example.com", Content: strings.Repeat("word ", 10), Tags: []string{"go"}},
+			reasons: []string{"content is 50 characters, more than 20"},
+		},
+		{
+			name:    "relative url",
+			doc:     models.Document{Title: "Guide", URL: "data/guide.md", Content: "the guide", Tags: []string{"go"}},
+			reasons: []string{`url "data/guide.md" is not an absolute http or https URL`},
+		},
+		{
+			name:    "language not allowed",
+			doc:     models.Document{Title: "Руководство", URL: "http://example.com", Content: "это поиск", Tags: []string{"go"}},
+			reasons: []string{"language ru is not allowed (allowed: en)"},
+		},
+		{
+			name:    "language not detected",
+			doc:     models.Document{Title: "42", URL: "http://example.com", Content: "1234", Tags: []string{"go"}},
+			reasons: []string{"language could not be detected (allowed: en)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reasons := rules.Validate(&tt.doc)
+			if strings.Join(reasons, "; ") != strings.Join(tt.reasons, "; ") {
+				t.Errorf("Expected reasons %q, got %q", tt.reasons, reasons)
+			}
+		})
+	}
+}
+
+func TestRulesFilter(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, Title: "One", URL: "one.md", Content: "first"},
+		{ID: 2, URL: "two.md", Content: "second"},
+		{ID: 3, Title: "Three", URL: "three.md", Content: "third"},
+	}
+
+	valid, rejected := DefaultRules().Filter(documents)
+	if len(valid) != 2 || valid[0].ID != 1 || valid[1].ID != 3 {
+		t.Errorf("Expected documents 1 and 3 to be valid, got %v", valid)
+	}
+	if len(rejected) != 1 || rejected[0].Document.ID != 2 || rejected[0].Reasons[0] != "title is required" {
+		t.Errorf("Expected document 2 to be rejected, got %+v", rejected)
+	}
+
+	var nilRules *Rules
+	if valid, rejected := nilRules.Filter(documents); len(valid) != 3 || len(rejected) != 0 {
+		t.Errorf("Expected nil rules to accept every document, got %d valid and %d rejected", len(valid), len(rejected))
+	}
+}
+
+func TestRulesFromEnvironment(t *testing.T) {
+	t.Setenv("DOCUMENT_REQUIRED_FIELDS", "Title, tags")
+	t.Setenv("DOCUMENT_MAX_CONTENT_LENGTH", "5000")
+	t.Setenv("DOCUMENT_URL_FORMAT", "http")
+	t.Setenv("DOCUMENT_LANGUAGES", "en,ru")
+
+	rules, err := RulesFromEnvironment()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(rules.Required, ",") != "title,tags" || rules.MaxContentLength != 5000 || rules.URLFormat != URLFormatHTTP || len(rules.Languages) != 2 {
+		t.Errorf("Unexpected rules: %+v", rules)
+	}
+
+	t.Setenv("DOCUMENT_REQUIRED_FIELDS", "none")
+	if rules, err := RulesFromEnvironment(); err != nil || len(rules.Required) != 0 {
+		t.Errorf("Expected no required fields, got %+v, %v", rules, err)
+	}
+
+	for name, value := range map[string]string{
+		"DOCUMENT_REQUIRED_FIELDS":    "author",
+		"DOCUMENT_MAX_CONTENT_LENGTH": "-1",
+		"DOCUMENT_URL_FORMAT":         "ftp",
+		"DOCUMENT_LANGUAGES":          "xx",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, err := RulesFromEnvironment(); err == nil {
+				t.Errorf("Expected an error for %s=%s", name, value)
+			}
+		})
+	}
+}
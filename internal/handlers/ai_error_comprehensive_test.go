@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -25,16 +28,65 @@ type MockAIErrorClient struct {
 	simulateTimeout      bool
 	simulateNetworkError bool
 	simulateModelError   bool
-	callCount            int
+
+	mu        sync.Mutex
+	callCount int
+}
+
+// countCall counts a search request, AI searches and their vector fallbacks alike
+func (m *MockAIErrorClient) countCall() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callCount++
+}
+
+// calls returns the number of search requests made
+func (m *MockAIErrorClient) calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.callCount
 }
 
 func (m *MockAIErrorClient) WaitForReady(timeout time.Duration) error           { return nil }
 func (m *MockAIErrorClient) HealthCheck() error                                 { return m.healthCheckError }
 func (m *MockAIErrorClient) Close() error                                       { return nil }
 func (m *MockAIErrorClient) IsConnected() bool                                  { return m.isConnected }
+func (m *MockAIErrorClient) GetCapabilities() *manticore.Capabilities           { return nil }
 func (m *MockAIErrorClient) CreateSchema(aiConfig *models.AISearchConfig) error { return nil }
-func (m *MockAIErrorClient) ResetDatabase() error                               { return nil }
-func (m *MockAIErrorClient) TruncateTables() error                              { return nil }
+func (m *MockAIErrorClient) MigrateSchema(aiConfig *models.AISearchConfig) (*manticore.MigrationResult, error) {
+	return &manticore.MigrationResult{}, nil
+}
+func (m *MockAIErrorClient) Backup(path string) (*manticore.BackupResult, error) {
+	return &manticore.BackupResult{Path: path}, nil
+}
+func (m *MockAIErrorClient) ReembedDocuments(ctx context.Context, aiConfig *models.AISearchConfig, progress func(manticore.ReembedProgress)) (*manticore.ReembedResult, error) {
+	return &manticore.ReembedResult{Model: aiConfig.Model}, nil
+}
+func (m *MockAIErrorClient) DropStaleDocumentsTables() ([]manticore.DroppedTable, error) {
+	return nil, nil
+}
+func (m *MockAIErrorClient) SetDocumentStatus(id int64, status models.DocumentStatus) error {
+	return nil
+}
+func (m *MockAIErrorClient) SetDocumentTags(id int64, tags []string) error {
+	return nil
+}
+func (m *MockAIErrorClient) TruncateTable(table string) error {
+	return nil
+}
+func (m *MockAIErrorClient) OptimizeTable(table string) error {
+	return nil
+}
+func (m *MockAIErrorClient) DocumentIDs(table string) ([]int64, error)       { return nil, nil }
+func (m *MockAIErrorClient) GetDocumentHashes() (map[int64]string, error)    { return nil, nil }
+func (m *MockAIErrorClient) GetDocumentTimes() (map[int64]int64, error)      { return nil, nil }
+func (m *MockAIErrorClient) DeleteDocuments(table string, ids []int64) error { return nil }
+func (m *MockAIErrorClient) ResetTable(table string, aiConfig *models.AISearchConfig) error {
+	return nil
+}
+func (m *MockAIErrorClient) ResetDatabase() error                       { return nil }
+func (m *MockAIErrorClient) TruncateTables() error                      { return nil }
+func (m *MockAIErrorClient) CountDocuments(table string) (int64, error) { return 0, nil }
 func (m *MockAIErrorClient) IndexDocument(doc *models.Document, vector []float64) error {
 	return nil
 }
@@ -42,12 +94,72 @@ func (m *MockAIErrorClient) IndexDocuments(documents []*models.Document, vectors
 	return nil
 }
 func (m *MockAIErrorClient) GetAllDocuments() ([]*models.Document, error) { return nil, nil }
+func (m *MockAIErrorClient) GetDocument(id int64) (*models.Document, error) {
+	return nil, manticore.ErrDocumentNotFound
+}
+
+// GetAllDocumentsWithVectors serves the vector search that failed AI searches fall back to
+func (m *MockAIErrorClient) GetAllDocumentsWithVectors() ([]*models.Document, [][]float64, error) {
+	m.countCall()
+	return nil, nil, m.searchError
+}
 func (m *MockAIErrorClient) SearchWithRequest(request manticore.SearchRequest) (*manticore.SearchResponse, error) {
 	return nil, nil
 }
 
+func (m *MockAIErrorClient) SearchWithContext(ctx context.Context, request manticore.SearchRequest) (*manticore.SearchResponse, error) {
+	return m.SearchWithRequest(request)
+}
+
+func (m *MockAIErrorClient) GetAllDocumentsWithVectorsContext(ctx context.Context) ([]*models.Document, [][]float64, error) {
+	return m.GetAllDocumentsWithVectors()
+}
+
+func (m *MockAIErrorClient) GetOperationTimeouts() manticore.OperationTimeouts {
+	return manticore.DefaultOperationTimeouts()
+}
+
+func (m *MockAIErrorClient) CheckHealth() manticore.HealthReport {
+	if m.healthCheckError != nil {
+		return manticore.HealthReport{State: manticore.HealthStateDown, Error: m.healthCheckError.Error()}
+	}
+	return manticore.HealthReport{State: manticore.HealthStateReady, ServerUp: true, SchemaPresent: true}
+}
+
+func (m *MockAIErrorClient) Count(query string, filters map[string]interface{}) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockAIErrorClient) AISearchWithContext(ctx context.Context, query string, model string, limit, offset int) (*manticore.SearchResponse, error) {
+	return m.AISearch(query, model, limit, offset)
+}
+
+func (m *MockAIErrorClient) GetIndexStats(table string) (*manticore.IndexStats, error) {
+	return &manticore.IndexStats{Table: table}, nil
+}
+
+func (m *MockAIErrorClient) EmbeddingModels(table string) ([]manticore.EmbeddingModel, error) {
+	return nil, nil
+}
+
+func (m *MockAIErrorClient) WaitForReadyContext(ctx context.Context, opts manticore.ReadyOptions) error {
+	return nil
+}
+
+func (m *MockAIErrorClient) GetCircuitBreakerTransitions() []manticore.CircuitBreakerTransition {
+	return nil
+}
+
+func (m *MockAIErrorClient) GetResilienceStats() manticore.ResilienceStats {
+	return manticore.ResilienceStats{}
+}
+
+func (m *MockAIErrorClient) GetRecordings() manticore.Recordings {
+	return manticore.Recordings{}
+}
+
 func (m *MockAIErrorClient) Search(query string, mode models.SearchMode, page, pageSize int) (*models.SearchResponse, error) {
-	m.callCount++
+	m.countCall()
 
 	if m.simulateTimeout {
 		time.Sleep(100 * time.Millisecond)
@@ -62,7 +174,7 @@ func (m *MockAIErrorClient) Search(query string, mode models.SearchMode, page, p
 }
 
 func (m *MockAIErrorClient) AISearch(query string, model string, limit, offset int) (*manticore.SearchResponse, error) {
-	m.callCount++
+	m.countCall()
 
 	if m.simulateTimeout {
 		time.Sleep(100 * time.Millisecond)
@@ -110,31 +222,27 @@ func TestAISearchErrorHandlingComprehensive(t *testing.T) {
 	})
 }
 
+// testAISearchUnavailableScenarios checks that AI searches degrade to hybrid search while AI search
+// is unavailable, and are rejected only without a search backend
 func testAISearchUnavailableScenarios(t *testing.T) {
 	tests := []struct {
-		name                string
-		aiConfig            *models.AISearchConfig
-		clientConnected     bool
-		clientHealthError   error
-		expectedStatusCode  int
-		expectedErrorType   string
-		expectedSuggestions []string
+		name            string
+		aiConfig        *models.AISearchConfig
+		clientConnected bool
+		noClient        bool
+		expectedMode    string
 	}{
 		{
-			name:                "AI search disabled in config",
-			aiConfig:            &models.AISearchConfig{Enabled: false},
-			clientConnected:     true,
-			expectedStatusCode:  http.StatusServiceUnavailable,
-			expectedErrorType:   "ai_search_unavailable",
-			expectedSuggestions: []string{"hybrid", "fulltext", "vector"},
+			name:            "AI search disabled in config",
+			aiConfig:        &models.AISearchConfig{Enabled: false},
+			clientConnected: true,
+			expectedMode:    "hybrid (AI degraded)",
 		},
 		{
-			name:                "nil AI config",
-			aiConfig:            nil,
-			clientConnected:     true,
-			expectedStatusCode:  http.StatusServiceUnavailable,
-			expectedErrorType:   "ai_search_unavailable",
-			expectedSuggestions: []string{"hybrid", "fulltext", "vector"},
+			name:            "nil AI config",
+			aiConfig:        nil,
+			clientConnected: true,
+			expectedMode:    "hybrid (AI degraded)",
 		},
 		{
 			name: "client not connected",
@@ -143,91 +251,74 @@ func testAISearchUnavailableScenarios(t *testing.T) {
 				Enabled: true,
 				Timeout: 30 * time.Second,
 			},
-			clientConnected:     false,
-			expectedStatusCode:  http.StatusServiceUnavailable,
-			expectedErrorType:   "ai_search_unavailable",
-			expectedSuggestions: []string{"hybrid", "fulltext", "vector"},
+			clientConnected: false,
+			expectedMode:    "hybrid (AI degraded)",
 		},
 		{
-			name: "client health check failed",
+			name: "no Manticore client",
 			aiConfig: &models.AISearchConfig{
 				Model:   "test-model",
 				Enabled: true,
 				Timeout: 30 * time.Second,
 			},
-			clientConnected:     true,
-			clientHealthError:   errors.New("health check failed"),
-			expectedStatusCode:  http.StatusServiceUnavailable,
-			expectedErrorType:   "ai_search_unavailable",
-			expectedSuggestions: []string{"hybrid", "fulltext", "vector"},
+			noClient: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Create mock client
-			mockClient := &MockAIErrorClient{
-				isConnected:      tt.clientConnected,
-				healthCheckError: tt.clientHealthError,
-			}
-
-			// Create app state
 			app := &AppState{
-				Documents:  []*models.Document{},
 				Vectorizer: nil,
-				Manticore:  mockClient,
-				Vectors:    [][]float64{},
 				AIConfig:   tt.aiConfig,
 			}
+			if !tt.noClient {
+				app.Manticore = &MockAIErrorClient{isConnected: tt.clientConnected}
+			}
 
-			// Create request
 			req := httptest.NewRequest("GET", "/api/search?query=test&mode=ai", nil)
 			w := httptest.NewRecorder()
 
-			// Handle request
 			app.SearchHandler(w, req)
 
-			// Verify response
-			if w.Code != tt.expectedStatusCode {
-				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
-			}
-
 			var response api.APIResponse
 			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 				t.Fatalf("Failed to unmarshal response: %v", err)
 			}
 
-			if response.Success {
-				t.Errorf("Expected unsuccessful response")
-			}
-
-			// Check error type in response data
-			if data, ok := response.Data.(map[string]interface{}); ok {
-				if errorType, exists := data["error_type"]; exists {
-					if errorType != tt.expectedErrorType {
-						t.Errorf("Expected error type %s, got %v", tt.expectedErrorType, errorType)
-					}
-				} else {
-					t.Errorf("Expected error_type in response data")
+			if tt.noClient {
+				if w.Code != http.StatusServiceUnavailable {
+					t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, w.Code)
 				}
-
-				// Check suggested modes
-				if suggestions, exists := data["suggested_modes"]; exists {
-					if suggestionsSlice, ok := suggestions.([]interface{}); ok {
-						for _, expectedSuggestion := range tt.expectedSuggestions {
-							found := false
-							for _, suggestion := range suggestionsSlice {
-								if suggestion == expectedSuggestion {
-									found = true
-									break
-								}
-							}
-							if !found {
-								t.Errorf("Expected suggestion %s not found in %v", expectedSuggestion, suggestionsSlice)
-							}
-						}
+				if response.Success {
+					t.Errorf("Expected unsuccessful response")
+				}
+				data, err := api.DecodeData[api.AISearchUnavailableData](response)
+				if err != nil {
+					t.Fatalf("Expected AI search unavailable data: %v", err)
+				}
+				if data.ErrorType != api.ErrorTypeAISearchUnavailable {
+					t.Errorf("Expected error type %s, got %s", api.ErrorTypeAISearchUnavailable, data.ErrorType)
+				}
+				for _, mode := range []string{"hybrid", "fulltext", "vector"} {
+					if !slices.Contains(data.SuggestedModes, mode) {
+						t.Errorf("Expected suggestion %s not found in %v", mode, data.SuggestedModes)
 					}
 				}
+				return
+			}
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
+			}
+			if !response.Success {
+				t.Errorf("Expected successful degraded response")
+			}
+			searchResponse, err := api.DecodeData[api.SearchResponse](response)
+			if err != nil {
+				t.Fatalf("Expected SearchResponse in degraded response: %v", err)
+			}
+			if searchResponse.Mode != tt.expectedMode {
+				t.Errorf("Expected mode %s, got %s", tt.expectedMode, searchResponse.Mode)
 			}
 		})
 	}
@@ -319,10 +410,8 @@ func testAISearchFailureWithFallback(t *testing.T) {
 
 			// Create app state with AI enabled
 			app := &AppState{
-				Documents:  []*models.Document{},
 				Vectorizer: nil,
 				Manticore:  mockClient,
-				Vectors:    [][]float64{},
 				AIConfig: &models.AISearchConfig{
 					Model:   "test-model",
 					Enabled: true,
@@ -353,7 +442,7 @@ func testAISearchFailureWithFallback(t *testing.T) {
 				}
 
 				// Check that the response contains fallback data
-				if searchResponse, ok := response.Data.(*models.SearchResponse); ok {
+				if searchResponse, err := api.DecodeData[api.SearchResponse](response); err == nil {
 					if searchResponse.Mode != tt.expectedMode {
 						t.Errorf("Expected mode %s, got %s", tt.expectedMode, searchResponse.Mode)
 					}
@@ -363,8 +452,8 @@ func testAISearchFailureWithFallback(t *testing.T) {
 			}
 
 			// Verify that both AI search and fallback were attempted
-			if mockClient.callCount < 2 {
-				t.Errorf("Expected at least 2 calls (AI + fallback), got %d", mockClient.callCount)
+			if calls := mockClient.calls(); calls < 2 {
+				t.Errorf("Expected at least 2 calls (AI + fallback), got %d", calls)
 			}
 		})
 	}
@@ -429,10 +518,8 @@ func testAISearchCompleteFailure(t *testing.T) {
 
 			// Create app state with AI enabled
 			app := &AppState{
-				Documents:  []*models.Document{},
 				Vectorizer: nil,
 				Manticore:  mockClient,
-				Vectors:    [][]float64{},
 				AIConfig: &models.AISearchConfig{
 					Model:   "test-model",
 					Enabled: true,
@@ -596,7 +683,7 @@ func testAISearchStatusAndHealthChecks(t *testing.T) {
 			t.Errorf("Expected successful status response")
 		}
 
-		if statusResp, ok := response.Data.(api.StatusResponse); ok {
+		if statusResp, err := api.DecodeData[api.StatusResponse](response); err == nil {
 			if !statusResp.AISearchEnabled {
 				t.Errorf("Expected AI search to be enabled")
 			}
@@ -639,7 +726,7 @@ func testAISearchStatusAndHealthChecks(t *testing.T) {
 			t.Fatalf("Failed to unmarshal response: %v", err)
 		}
 
-		if statusResp, ok := response.Data.(api.StatusResponse); ok {
+		if statusResp, err := api.DecodeData[api.StatusResponse](response); err == nil {
 			if statusResp.AISearchHealthy {
 				t.Errorf("Expected AI search to be unhealthy when client not connected")
 			}
@@ -670,7 +757,7 @@ func testAISearchStatusAndHealthChecks(t *testing.T) {
 			t.Fatalf("Failed to unmarshal response: %v", err)
 		}
 
-		if statusResp, ok := response.Data.(api.StatusResponse); ok {
+		if statusResp, err := api.DecodeData[api.StatusResponse](response); err == nil {
 			if statusResp.AISearchEnabled {
 				t.Errorf("Expected AI search to be disabled")
 			}
@@ -825,7 +912,7 @@ func TestAISearchConcurrentErrorHandling(t *testing.T) {
 	}
 
 	// Verify that all requests were handled (AI search + fallback for each)
-	if mockClient.callCount != numRequests*2 {
-		t.Errorf("Expected %d total calls, got %d", numRequests*2, mockClient.callCount)
+	if calls := mockClient.calls(); calls != numRequests*2 {
+		t.Errorf("Expected %d total calls, got %d", numRequests*2, calls)
 	}
 }
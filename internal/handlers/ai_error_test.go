@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -29,9 +28,9 @@ func TestAISearchErrorHandling(t *testing.T) {
 			t.Errorf("Expected status 503, got %d", w.Code)
 		}
 
-		var response api.APIResponse
-		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
-			t.Fatalf("Failed to unmarshal response: %v", err)
+		response, err := api.Decode[api.AISearchUnavailableData](w.Body)
+		if err != nil {
+			t.Fatal(err)
 		}
 
 		if response.Success {
@@ -43,13 +42,11 @@ func TestAISearchErrorHandling(t *testing.T) {
 		}
 
 		// Check error data
-		data, ok := response.Data.(map[string]interface{})
-		if !ok {
-			t.Fatal("Expected data to be a map")
+		if response.Data.ErrorType != api.ErrorTypeAISearchUnavailable {
+			t.Errorf("Expected error_type to be ai_search_unavailable, got: %v", response.Data.ErrorType)
 		}
-
-		if data["error_type"] != "ai_search_unavailable" {
-			t.Errorf("Expected error_type to be ai_search_unavailable, got: %v", data["error_type"])
+		if response.Data.AIEnabled {
+			t.Error("Expected ai_enabled to be false")
 		}
 	})
 
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// aiModelsProvider names who computes the embeddings of AI search
+const aiModelsProvider = "manticore_auto_embeddings"
+
+// AIModelsHandler handles GET /api/ai/models requests, listing the embedding models of the
+// documents table and checking them with a test AI search
+func (app *AppState) AIModelsHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow GET requests
+	if r.Method != "GET" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	app.sendSuccessResponse(w, app.aiModels(r.Context()))
+}
+
+// aiModels reports the embedding models Manticore uses for AI search. Manticore embeds queries
+// with the model of the documents table, so the configured model only applies once the table is
+// created again. The test AI search is bounded by the AI timeout.
+func (app *AppState) aiModels(ctx context.Context) api.AIModelsResponse {
+	response := api.AIModelsResponse{Provider: aiModelsProvider, Models: []api.AIModel{}}
+	aiConfig := app.CurrentAIConfig()
+	if aiConfig != nil {
+		response.Enabled = aiConfig.Enabled
+		response.ConfiguredModel = aiConfig.Model
+	}
+
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		response.Error = "Manticore Search is not available"
+		return response
+	}
+	if caps := app.Manticore.GetCapabilities(); caps != nil {
+		response.AutoEmbeddings = &caps.AutoEmbeddings
+	}
+	if stats, ok := app.Manticore.GetResilienceStats().CircuitBreakerByClass[manticore.OperationClassEmbedding]; ok {
+		response.CircuitBreaker = stats.State.String()
+	}
+
+	models, err := app.Manticore.EmbeddingModels("documents")
+	if err != nil {
+		response.Error = fmt.Sprintf("Failed to read the embedding models: %v", err)
+		return response
+	}
+	for _, model := range models {
+		configured := model.Model == response.ConfiguredModel
+		response.Models = append(response.Models, api.AIModel{
+			Model:      model.Model,
+			Table:      model.Table,
+			Column:     model.Column,
+			Dimension:  model.Dimension,
+			Source:     model.Source,
+			Similarity: model.Similarity,
+			Configured: configured,
+		})
+		if !configured && response.ConfiguredModel != "" {
+			response.Warnings = append(response.Warnings, fmt.Sprintf(
+				"Table %s embeds with %s, not the configured %s; reset it with POST /api/admin/reset to use the configured model",
+				model.Table, model.Model, response.ConfiguredModel))
+		}
+		if similarity := manticore.HNSWSimilarity(aiConfig); model.Similarity != "" && !strings.EqualFold(model.Similarity, similarity) {
+			response.Warnings = append(response.Warnings, fmt.Sprintf(
+				"Table %s compares embeddings by %s, not the configured %s; reset it with POST /api/admin/reset to use the configured similarity",
+				model.Table, strings.ToLower(model.Similarity), similarity))
+		}
+	}
+
+	if err := app.validateAISearchAvailability(); err != nil {
+		response.Error = err.Error()
+		return response
+	}
+	if len(response.Models) == 0 {
+		response.Error = "The documents table has no Auto Embeddings column; reset it with POST /api/admin/reset to create one"
+		return response
+	}
+
+	if aiConfig.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, aiConfig.Timeout)
+		defer cancel()
+	}
+	// A cached query embedding would hide whether Manticore can still embed
+	ctx = manticore.WithoutEmbeddingCache(ctx)
+	start := time.Now()
+	_, err = app.Manticore.AISearchWithContext(ctx, "test", app.getAIModel(), 1, 0)
+	response.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		response.Error = fmt.Sprintf("Test AI search failed: %v", err)
+		return response
+	}
+	response.Healthy = true
+	return response
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// failingAIClient fails every AI search
+type failingAIClient struct {
+	MockManticoreClient
+}
+
+func (c *failingAIClient) AISearchWithContext(ctx context.Context, query, model string, limit, offset int) (*manticore.SearchResponse, error) {
+	return nil, errors.New("embedding model not loaded")
+}
+
+func getAIModels(t *testing.T, app *AppState) api.AIModelsResponse {
+	t.Helper()
+	w := httptest.NewRecorder()
+	app.AIModelsHandler(w, httptest.NewRequest("GET", "/api/ai/models", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data api.AIModelsResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return response.Data
+}
+
+func TestAIModelsHandler(t *testing.T) {
+	aiConfig := &models.AISearchConfig{Model: "sentence-transformers/all-MiniLM-L6-v2", Enabled: true, Timeout: time.Second}
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}, AIConfig: aiConfig}
+
+	response := getAIModels(t, app)
+	if !response.Healthy || response.Error != "" || response.Provider != aiModelsProvider {
+		t.Errorf("Expected a healthy provider, got %+v", response)
+	}
+	if len(response.Models) != 1 || response.Models[0].Dimension != 384 || !response.Models[0].Configured {
+		t.Fatalf("Expected the configured model of the documents table, got %+v", response.Models)
+	}
+	if len(response.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", response.Warnings)
+	}
+
+	// A configured model the table wasn't created with is reported
+	aiConfig.Model = "custom/model"
+	response = getAIModels(t, app)
+	if response.Models[0].Configured || len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], "custom/model") {
+		t.Errorf("Expected a model mismatch warning, got %+v", response)
+	}
+
+	// A failing test search makes the provider unhealthy
+	app.Manticore = &failingAIClient{MockManticoreClient{connected: true, healthy: true}}
+	response = getAIModels(t, app)
+	if response.Healthy || !strings.Contains(response.Error, "embedding model not loaded") {
+		t.Errorf("Expected the test search error, got %+v", response)
+	}
+
+	// Disabled AI search still lists the models
+	aiConfig.Enabled = false
+	response = getAIModels(t, app)
+	if response.Healthy || len(response.Models) != 1 || !strings.Contains(response.Error, "disabled") {
+		t.Errorf("Expected the models of disabled AI search, got %+v", response)
+	}
+}
+
+func TestAIModelsHandler_ManticoreUnavailable(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: false}}
+
+	response := getAIModels(t, app)
+	if response.Healthy || response.Error == "" || len(response.Models) != 0 {
+		t.Errorf("Expected an unavailable provider, got %+v", response)
+	}
+
+	w := httptest.NewRecorder()
+	app.AIModelsHandler(w, httptest.NewRequest("POST", "/api/ai/models", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/audit"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// AuditHandler handles GET /api/admin/audit requests. Requires the admin token.
+func (app *AppState) AuditHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow GET requests
+	if r.Method != "GET" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Entries hold the parameters of admin operations
+	if !app.authorizeAdmin(w, r) {
+		return
+	}
+
+	limit, err := parseIntRangeParam(r.URL.Query().Get("limit"), "limit", 50, 1, audit.DefaultMaxRecent)
+	if err != nil {
+		app.sendValidationError(w, r, err)
+		return
+	}
+
+	entries := app.Audit.Recent(limit, r.URL.Query().Get("action"))
+
+	response := api.AuditLogResponse{
+		Entries: make([]api.AuditEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, api.AuditEntry{
+			ID:         entry.ID,
+			Timestamp:  entry.Timestamp,
+			Actor:      entry.Actor,
+			RemoteAddr: entry.RemoteAddr,
+			Action:     entry.Action,
+			Parameters: entry.Parameters,
+			Outcome:    entry.Outcome,
+			Error:      entry.Error,
+			Duration:   entry.Duration.String(),
+		})
+	}
+	response.Count = len(response.Entries)
+
+	app.sendSuccessResponse(w, response)
+}
+
+// recordAudit records an admin operation performed by the request's caller
+func (app *AppState) recordAudit(r *http.Request, action string, params map[string]interface{}, err error, startTime time.Time) {
+	app.recordAuditAs(requestActor(r), requestRemoteAddr(r), action, params, err, startTime)
+}
+
+// recordAuditAs records an admin operation performed by actor from remoteAddr
+func (app *AppState) recordAuditAs(actor, remoteAddr, action string, params map[string]interface{}, err error, startTime time.Time) {
+	if app.Audit == nil {
+		return
+	}
+	if app.Tenant != "" && params != nil {
+		params["tenant"] = app.Tenant
+	}
+
+	entry := audit.Entry{
+		Actor:      actor,
+		RemoteAddr: remoteAddr,
+		Action:     action,
+		Parameters: params,
+		Outcome:    audit.OutcomeSuccess,
+		Duration:   time.Since(startTime),
+	}
+	if err != nil {
+		entry.Outcome = audit.OutcomeFailure
+		entry.Error = err.Error()
+	}
+
+	app.Audit.Record(entry)
+}
+
+// requestActor identifies the caller from headers set by an authenticating reverse proxy
+func requestActor(r *http.Request) string {
+	for _, header := range []string{"X-Forwarded-User", "X-Remote-User"} {
+		if user := strings.TrimSpace(r.Header.Get(header)); user != "" {
+			return user
+		}
+	}
+	return "anonymous"
+}
+
+// requestRemoteAddr returns the originating client address, preferring X-Forwarded-For
+func requestRemoteAddr(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/audit"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+func TestRecordAuditAndQuery(t *testing.T) {
+	auditLog, _ := audit.New("", 10)
+	app := &AppState{Audit: auditLog, AdminToken: "secret"}
+
+	req := httptest.NewRequest("POST", "/api/reindex", nil)
+	req.Header.Set("X-Forwarded-User", "alice")
+	req.Header.Set("X-Forwarded-For", "10.0.0.5, 10.0.0.1")
+	app.recordAudit(req, "reindex", map[string]interface{}{"documents": 3}, nil, time.Now())
+	app.recordAudit(httptest.NewRequest("POST", "/api/reindex", nil), "reindex", nil, errors.New("schema failed"), time.Now())
+
+	req = httptest.NewRequest("GET", "/api/admin/audit?limit=10&action=reindex", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	app.AuditHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response struct {
+		Success bool                 `json:"success"`
+		Data    api.AuditLogResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.Count != 2 {
+		t.Fatalf("Expected 2 entries, got %d", response.Data.Count)
+	}
+
+	newest, oldest := response.Data.Entries[0], response.Data.Entries[1]
+	if newest.Outcome != audit.OutcomeFailure || newest.Error != "schema failed" || newest.Actor != "anonymous" {
+		t.Errorf("Unexpected failure entry: %+v", newest)
+	}
+	if oldest.Actor != "alice" || oldest.RemoteAddr != "10.0.0.5" || oldest.Outcome != audit.OutcomeSuccess {
+		t.Errorf("Unexpected success entry: %+v", oldest)
+	}
+}
+
+func TestAuditHandlerInvalidLimit(t *testing.T) {
+	app := &AppState{AdminToken: "secret"}
+
+	req := httptest.NewRequest("GET", "/api/admin/audit?limit=0", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	app.AuditHandler(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestAuditHandlerRequiresAdminToken(t *testing.T) {
+	auditLog, _ := audit.New("", 10)
+	app := &AppState{Audit: auditLog, AdminToken: "secret"}
+
+	w := httptest.NewRecorder()
+	app.AuditHandler(w, httptest.NewRequest("GET", "/api/admin/audit", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+
+	app.AdminToken = ""
+	w = httptest.NewRecorder()
+	app.AuditHandler(w, httptest.NewRequest("GET", "/api/admin/audit", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 without ADMIN_TOKEN, got %d", w.Code)
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/usage"
+)
+
+// authorizeAdmin checks the bearer token of a request to an admin endpoint, sending a 401 response
+// when it does not match AdminToken. Without an AdminToken the endpoints are disabled and answer 403.
+func (app *AppState) authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if app.AdminToken == "" {
+		app.sendErrorResponse(w, http.StatusForbidden, "Admin endpoint is disabled (set ADMIN_TOKEN to enable it)")
+		return false
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(app.AdminToken)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+		app.sendErrorResponse(w, http.StatusUnauthorized, "Invalid or missing admin token")
+		return false
+	}
+	return true
+}
+
+// apiKeyContextKey is the context key of the name of the caller's API key
+type apiKeyContextKey struct{}
+
+// ParseAPIKeys parses API_KEYS, a comma-separated list of name:key pairs, into keys by name
+func ParseAPIKeys(value string) (map[string]string, error) {
+	keys := make(map[string]string)
+	for i, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, key, ok := strings.Cut(pair, ":")
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if !ok || name == "" || key == "" {
+			// Don't repeat the entry, it may be a secret
+			return nil, fmt.Errorf("invalid API_KEYS entry %d, expected <name>:<key>", i+1)
+		}
+		if _, exists := keys[name]; exists {
+			return nil, fmt.Errorf("duplicate API key name %q", name)
+		}
+		keys[name] = key
+	}
+	return keys, nil
+}
+
+// RequireAPIKey authenticates requests to /api/ with the X-API-Key header, or the api_key
+// parameter for clients that can't set headers such as browser WebSockets, when APIKeys are
+// configured. The name of the key is what usage is accounted to.
+func (app *AppState) RequireAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(app.APIKeys) == 0 || !strings.HasPrefix(r.URL.Path, "/api/") || r.Method == "OPTIONS" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		secret := r.Header.Get("X-API-Key")
+		if secret == "" {
+			secret = r.URL.Query().Get("api_key")
+		}
+		name, ok := app.authenticateAPIKey(secret)
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			app.sendErrorResponse(w, http.StatusUnauthorized, "Invalid or missing API key")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, name)))
+	})
+}
+
+// authenticateAPIKey returns the name of the API key secret, comparing every key in constant time
+func (app *AppState) authenticateAPIKey(secret string) (string, bool) {
+	if secret == "" {
+		return "", false
+	}
+	match := ""
+	for name, key := range app.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(key)) == 1 {
+			match = name
+		}
+	}
+	return match, match != ""
+}
+
+// contextAPIKey returns the name of the API key a request was authenticated with, or
+// usage.AnonymousKey when API keys are not required
+func contextAPIKey(ctx context.Context) string {
+	if name, ok := ctx.Value(apiKeyContextKey{}).(string); ok {
+		return name
+	}
+	return usage.AnonymousKey
+}
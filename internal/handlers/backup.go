@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/backup"
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/textnorm"
+	"github.com/ad/manticoresearch-go/internal/validation"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// BackupHandler handles POST /api/admin/backup requests. It writes the indexed documents, their
+// TF-IDF vectors, the vectorizer model and the AI configuration to BACKUP_DIR, and additionally
+// asks Manticore for a native backup when MANTICORE_BACKUP_DIR is set.
+func (app *AppState) BackupHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow POST requests
+	if r.Method != "POST" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !app.authorizeAdmin(w, r) {
+		return
+	}
+
+	startTime := time.Now()
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		name = backup.NewName(startTime)
+	}
+	if err := backup.ValidateName(name); err != nil {
+		app.sendValidationError(w, r, validation.InvalidCharacters("name"))
+		return
+	}
+
+	dir := app.backupDirectory()
+	if backup.Exists(dir, name) {
+		app.sendErrorResponse(w, http.StatusConflict, fmt.Sprintf("Backup %s already exists", name))
+		return
+	}
+
+	// Check if Manticore is available
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Manticore Search is not available")
+		return
+	}
+
+	auditParams := map[string]interface{}{"name": name, "backup_dir": dir}
+	var auditErr error
+	defer func() {
+		app.recordAudit(r, "backup", auditParams, auditErr, startTime)
+	}()
+
+	documents, err := app.Manticore.GetAllDocuments()
+	if err != nil {
+		log.Printf("Failed to read documents for backup: %v", err)
+		auditErr = err
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to read documents: %v", err))
+		return
+	}
+
+	artifact := &backup.Artifact{
+		Manifest: backup.Manifest{
+			Name:          name,
+			CreatedAt:     startTime.UTC(),
+			SchemaVersion: manticore.SchemaVersion,
+			AIConfig:      app.CurrentAIConfig(),
+		},
+		Documents: documents,
+	}
+	if app.Vectorizer != nil {
+		model := app.Vectorizer.Model()
+		artifact.Vectorizer = &model
+		artifact.Vectors = make([][]float64, len(documents))
+		for i, doc := range documents {
+			artifact.Vectors[i] = app.Vectorizer.TransformQuery(documentText(doc))
+		}
+	}
+
+	if manticoreDir := os.Getenv("MANTICORE_BACKUP_DIR"); manticoreDir != "" {
+		result, err := app.Manticore.Backup(manticoreDir)
+		if err != nil {
+			auditErr = err
+			app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to back up Manticore tables: %v", err))
+			return
+		}
+		artifact.Manifest.ManticoreBackup = result.Path
+		auditParams["manticore_backup"] = result.Path
+	}
+
+	path, err := backup.Save(dir, artifact)
+	if err != nil {
+		log.Printf("Failed to save backup %s: %v", name, err)
+		auditErr = err
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to save backup: %v", err))
+		return
+	}
+
+	backupDuration := time.Since(startTime)
+	log.Printf("Backup %s completed: %d documents written to %s in %v", name, len(documents), path, backupDuration)
+	auditParams["documents"] = len(documents)
+
+	app.sendSuccessResponse(w, api.BackupResponse{
+		Name:            name,
+		Path:            path,
+		CreatedAt:       artifact.Manifest.CreatedAt,
+		Documents:       len(documents),
+		SchemaVersion:   artifact.Manifest.SchemaVersion,
+		ManticoreBackup: artifact.Manifest.ManticoreBackup,
+		BackupTime:      backupDuration.String(),
+	})
+}
+
+// RestoreHandler handles POST /api/admin/restore?name=... requests. It recreates the schema, reindexes
+// the documents from the backup and restores the vectorizer and AI configuration stored with them.
+func (app *AppState) RestoreHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow POST requests
+	if r.Method != "POST" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !app.authorizeAdmin(w, r) {
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		app.sendValidationError(w, r, validation.Required("name"))
+		return
+	}
+	if err := backup.ValidateName(name); err != nil {
+		app.sendValidationError(w, r, validation.InvalidCharacters("name"))
+		return
+	}
+
+	dir := app.backupDirectory()
+	if !backup.Exists(dir, name) {
+		app.sendErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Backup %s not found", name))
+		return
+	}
+
+	// Check if Manticore is available
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Manticore Search is not available")
+		return
+	}
+
+	startTime := time.Now()
+	auditParams := map[string]interface{}{"name": name, "backup_dir": dir, "schema_reset": true}
+	var auditErr error
+	defer func() {
+		app.recordAudit(r, "restore", auditParams, auditErr, startTime)
+	}()
+
+	artifact, err := backup.Load(dir, name)
+	if err != nil {
+		log.Printf("Failed to load backup %s: %v", name, err)
+		auditErr = err
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to load backup: %v", err))
+		return
+	}
+	if artifact.Manifest.SchemaVersion > manticore.SchemaVersion {
+		auditErr = fmt.Errorf("backup schema version %d is newer than supported version %d", artifact.Manifest.SchemaVersion, manticore.SchemaVersion)
+		app.sendErrorResponse(w, http.StatusConflict, auditErr.Error())
+		return
+	}
+
+	app.NormalizeDocuments(artifact.Documents)
+	vec, vectors, err := restoreVectorizer(artifact, app.Normalizer)
+	if err != nil {
+		auditErr = err
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to restore vectorizer: %v", err))
+		return
+	}
+
+	aiConfig := app.CurrentAIConfig()
+	if artifact.Manifest.AIConfig != nil {
+		aiConfig = artifact.Manifest.AIConfig
+	}
+
+	if err := app.Manticore.CreateSchema(aiConfig); err != nil {
+		log.Printf("Failed to create schema: %v", err)
+		auditErr = err
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create database schema: %v", err))
+		return
+	}
+
+	var indexErr error
+	if len(artifact.Documents) > 0 {
+		indexErr = app.Manticore.IndexDocuments(artifact.Documents, vectors)
+	}
+	// The schema was reset, so the index changed even if indexing failed
+	app.IndexChanged()
+	if indexErr != nil {
+		log.Printf("Failed to index documents: %v", indexErr)
+		auditErr = indexErr
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to index documents: %v", indexErr))
+		return
+	}
+
+	// Update application state
+	app.Documents.Replace(artifact.Documents, vectors)
+	app.Vectorizer = vec
+	app.SetAIConfig(aiConfig)
+	app.LastReindex = time.Now()
+
+	restoreDuration := time.Since(startTime)
+	log.Printf("Restored backup %s: %d documents indexed in %v", name, len(artifact.Documents), restoreDuration)
+	auditParams["documents"] = len(artifact.Documents)
+
+	app.sendSuccessResponse(w, api.RestoreResponse{
+		Name:        name,
+		Documents:   len(artifact.Documents),
+		RestoreTime: restoreDuration.String(),
+	})
+}
+
+// restoreVectorizer returns the backed up vectorizer and document vectors, normalizing queries with
+// normalizer. Backups taken before a vectorizer was trained get a new one fitted on the restored
+// documents.
+func restoreVectorizer(artifact *backup.Artifact, normalizer *textnorm.Normalizer) (*vectorizer.TFIDFVectorizer, [][]float64, error) {
+	if artifact.Vectorizer == nil {
+		vec := vectorizer.NewTFIDFVectorizer()
+		vec.SetNormalizer(normalizer)
+		return vec, vec.FitTransform(artifact.Documents), nil
+	}
+
+	vec, err := vectorizer.NewTFIDFVectorizerFromModel(*artifact.Vectorizer)
+	if err != nil {
+		return nil, nil, err
+	}
+	vec.SetNormalizer(normalizer)
+
+	vectors := artifact.Vectors
+	if vectors == nil {
+		vectors = make([][]float64, len(artifact.Documents))
+		for i, doc := range artifact.Documents {
+			vectors[i] = vec.TransformQuery(documentText(doc))
+		}
+	}
+	return vec, vectors, nil
+}
+
+// documentText is the text the TF-IDF vectorizer is fitted on
+func documentText(doc *models.Document) string {
+	return doc.Title + " " + doc.Content
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// backupClient serves a fixed document set and records what restore reindexes
+type backupClient struct {
+	MockManticoreClient
+	documents      []*models.Document
+	schemaCreated  bool
+	indexed        []*models.Document
+	indexedVectors [][]float64
+}
+
+func (c *backupClient) GetAllDocuments() ([]*models.Document, error) {
+	return c.documents, nil
+}
+
+func (c *backupClient) CreateSchema(aiConfig *models.AISearchConfig) error {
+	c.schemaCreated = true
+	return nil
+}
+
+func (c *backupClient) IndexDocuments(documents []*models.Document, vectors [][]float64) error {
+	c.indexed, c.indexedVectors = documents, vectors
+	return nil
+}
+
+func TestBackupAndRestoreHandlers(t *testing.T) {
+	t.Setenv("BACKUP_DIR", t.TempDir())
+	t.Setenv("MANTICORE_BACKUP_DIR", "/var/lib/manticore/backups")
+
+	documents := []*models.Document{
+		{ID: 1, Title: "Contact form", URL: "/contact", Content: "Send us a message using the contact form"},
+		{ID: 2, Title: "Pricing", URL: "/pricing", Content: "Plans and prices for every team size"},
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	vectors := vec.FitTransform(documents)
+
+	client := &backupClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}, documents: documents}
+	app := &AppState{Manticore: client, Vectorizer: vec, AIConfig: &models.AISearchConfig{Model: "backup-model"}, AdminToken: "secret"}
+
+	w := httptest.NewRecorder()
+	app.BackupHandler(w, adminRequest("/api/admin/backup?name=before-upgrade", "secret"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var backupResponse struct {
+		Data api.BackupResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&backupResponse); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if backupResponse.Data.Documents != 2 || backupResponse.Data.ManticoreBackup != "/var/lib/manticore/backups" {
+		t.Errorf("Unexpected backup response: %+v", backupResponse.Data)
+	}
+
+	// A second backup under the same name is refused
+	w = httptest.NewRecorder()
+	app.BackupHandler(w, adminRequest("/api/admin/backup?name=before-upgrade", "secret"))
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", w.Code)
+	}
+
+	// Restore into a fresh application state
+	restored := &AppState{Manticore: client, AdminToken: "secret"}
+	w = httptest.NewRecorder()
+	restored.RestoreHandler(w, adminRequest("/api/admin/restore?name=before-upgrade", "secret"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if !client.schemaCreated || len(client.indexed) != 2 || len(client.indexedVectors) != 2 {
+		t.Fatalf("Expected schema creation and 2 reindexed documents, got %v and %d", client.schemaCreated, len(client.indexed))
+	}
+	if restored.Vectorizer == nil || restored.Vectorizer.VocabularySize() != vec.VocabularySize() {
+		t.Error("Expected the vectorizer to be restored")
+	}
+	if aiConfig := restored.CurrentAIConfig(); aiConfig == nil || aiConfig.Model != "backup-model" {
+		t.Errorf("Expected the AI configuration to be restored, got %+v", aiConfig)
+	}
+	for i := range vectors[0] {
+		if client.indexedVectors[0][i] != vectors[0][i] {
+			t.Fatalf("Restored vector differs from the indexed one at %d", i)
+		}
+	}
+}
+
+func TestRestoreHandlerErrors(t *testing.T) {
+	t.Setenv("BACKUP_DIR", t.TempDir())
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}, AdminToken: "secret"}
+
+	tests := []struct {
+		url          string
+		expectedCode int
+	}{
+		{"/api/admin/restore", http.StatusBadRequest},
+		{"/api/admin/restore?name=../etc", http.StatusBadRequest},
+		{"/api/admin/restore?name=missing", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		app.RestoreHandler(w, adminRequest(tt.url, "secret"))
+		if w.Code != tt.expectedCode {
+			t.Errorf("%s: expected status %d, got %d", tt.url, tt.expectedCode, w.Code)
+		}
+	}
+}
+
+func TestBackupAndRestoreHandlersRequireAdminToken(t *testing.T) {
+	t.Setenv("BACKUP_DIR", t.TempDir())
+	client := &backupClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+
+	tests := []struct {
+		name         string
+		adminToken   string
+		token        string
+		expectedCode int
+	}{
+		{"wrong token", "secret", "guess", http.StatusUnauthorized},
+		{"missing token", "secret", "", http.StatusUnauthorized},
+		{"admin endpoints disabled", "", "secret", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &AppState{Manticore: client, AdminToken: tt.adminToken}
+
+			w := httptest.NewRecorder()
+			app.BackupHandler(w, adminRequest("/api/admin/backup?name=nightly", tt.token))
+			if w.Code != tt.expectedCode {
+				t.Errorf("Backup: expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+
+			w = httptest.NewRecorder()
+			app.RestoreHandler(w, adminRequest("/api/admin/restore?name=nightly", tt.token))
+			if w.Code != tt.expectedCode || client.schemaCreated {
+				t.Errorf("Restore: expected status %d without restoring, got %d", tt.expectedCode, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// circuitOpenClient fails vector lookups with a circuit breaker rejection when open is set
+type circuitOpenClient struct {
+	MockManticoreClient
+	open       bool
+	retryAfter time.Duration
+}
+
+func (c *circuitOpenClient) GetAllDocumentsWithVectors() ([]*models.Document, [][]float64, error) {
+	if c.open {
+		return nil, nil, fmt.Errorf("max retry attempts (5) exceeded, last error: circuit breaker is OPEN: too many failures")
+	}
+	return nil, nil, nil
+}
+
+func (c *circuitOpenClient) GetAllDocumentsWithVectorsContext(ctx context.Context) ([]*models.Document, [][]float64, error) {
+	return c.GetAllDocumentsWithVectors()
+}
+
+func (c *circuitOpenClient) GetResilienceStats() manticore.ResilienceStats {
+	return manticore.ResilienceStats{
+		CircuitBreaker: manticore.CircuitBreakerStats{State: manticore.CircuitBreakerOpen, RetryAfter: c.retryAfter},
+	}
+}
+
+func TestSearchHandlerCircuitOpen(t *testing.T) {
+	client := &circuitOpenClient{
+		MockManticoreClient: MockManticoreClient{connected: true, healthy: true},
+		retryAfter:          12500 * time.Millisecond,
+	}
+	app := &AppState{
+		Manticore:   client,
+		ResultCache: search.NewResultCache(10, time.Minute),
+	}
+
+	// Populate the cache while the backend is healthy
+	req := httptest.NewRequest("GET", "/api/search?query=golang&mode=vector", nil)
+	w := httptest.NewRecorder()
+	app.SearchHandler(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	client.open = true
+
+	t.Run("cached query is served stale", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/search?query=golang&mode=vector", nil)
+		w := httptest.NewRecorder()
+		app.SearchHandler(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		if w.Header().Get("X-Cache") != "STALE" {
+			t.Errorf("Expected X-Cache STALE header, got %q", w.Header().Get("X-Cache"))
+		}
+
+		response, err := api.Decode[api.SearchResponse](w.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !response.Data.Stale {
+			t.Error("Expected stale flag in response")
+		}
+	})
+
+	t.Run("uncached query gets 503 with Retry-After", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/search?query=rust&mode=vector", nil)
+		w := httptest.NewRecorder()
+		app.SearchHandler(w, req)
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("Expected status 503, got %d", w.Code)
+		}
+		if w.Header().Get("Retry-After") != "13" {
+			t.Errorf("Expected Retry-After 13, got %q", w.Header().Get("Retry-After"))
+		}
+
+		response, err := api.Decode[api.CircuitOpenData](w.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response.Data.ErrorType != api.ErrorTypeCircuitOpen || response.Data.RetryAfterSeconds != 13 {
+			t.Errorf("Expected circuit_open error data, got %+v", response.Data)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/internal/validation"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// CountHandler handles GET /api/count requests. It returns the exact number of documents matching
+// the optional query and filter=<field>:<value> parameters. Like searches, only active documents
+// are counted unless the status parameter selects others, and tags/tags_mode filter by tag as in searches.
+func (app *AppState) CountHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow GET requests
+	if r.Method != "GET" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var errs validation.Errors
+	query := strings.TrimSpace(r.URL.Query().Get("query"))
+	if query != "" {
+		errs.Add("query", search.ValidateQuery(query))
+	}
+
+	filters, displayFilters, err := parseCountFilters(r.URL.Query()["filter"])
+	errs.Add("filter", err)
+
+	statuses, err := search.ParseStatuses(r.URL.Query().Get("status"))
+	errs.Add("status", err)
+
+	tags, err := search.ParseTags(r.URL.Query().Get("tags"), r.URL.Query().Get("tags_mode"))
+	errs.Add("tags_mode", err)
+
+	if len(errs) > 0 {
+		app.sendValidationError(w, r, errs)
+		return
+	}
+
+	if len(statuses) == 0 {
+		statuses = manticore.DefaultSearchStatuses
+	}
+	if filters == nil {
+		filters, displayFilters = map[string]interface{}{}, map[string]string{}
+	}
+	filters["status"] = statuses
+	displayFilters["status"] = joinStatuses(statuses)
+
+	if len(tags.Tags) > 0 {
+		filters["tags"] = tags
+		displayFilters["tags"] = strings.Join(tags.Tags, ",")
+		if tags.MatchAll {
+			displayFilters["tags_mode"] = search.TagsModeAll
+		}
+	}
+
+	if app.Manticore == nil {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Search service is not available")
+		return
+	}
+
+	normalized := app.Normalizer.String(query)
+	count, err := app.Manticore.Count(search.FullTextQuery(normalized, search.QueryStopwords(normalized, app.Stopwords)), filters)
+	if err != nil {
+		log.Printf("Count error: %v", err)
+		if manticore.IsCircuitOpenError(err) {
+			app.sendErrorResponse(w, http.StatusServiceUnavailable, "Search backend is temporarily unavailable")
+			return
+		}
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Count failed: %v", err))
+		return
+	}
+
+	app.sendSuccessResponse(w, api.CountResponse{
+		Query:   query,
+		Filters: displayFilters,
+		Count:   count,
+	})
+}
+
+// parseCountFilters parses filter=<field>:<value> parameters. Values of the id field are document ids;
+// REGEX(<pattern>) values match the fields of manticore.RegexFields against an RE2 pattern, and other
+// values are phrases matched against that field.
+func parseCountFilters(params []string) (map[string]interface{}, map[string]string, error) {
+	if len(params) == 0 {
+		return nil, nil, nil
+	}
+
+	filters := make(map[string]interface{}, len(params))
+	display := make(map[string]string, len(params))
+	for _, param := range params {
+		field, value, ok := strings.Cut(param, ":")
+		field, value = strings.TrimSpace(field), strings.TrimSpace(value)
+		if !ok || field == "" || value == "" {
+			return nil, nil, validation.InvalidFormat("filter", param, "<field>:<value>")
+		}
+
+		if field == "status" || field == "tags" {
+			// Statuses and tags have their own parameters
+			return nil, nil, validation.InvalidValue("filter", param)
+		}
+
+		if pattern, ok := regexFilterValue(value); ok {
+			filter, err := search.NewRegexFilter("filter", field, pattern)
+			if err != nil {
+				return nil, nil, err
+			}
+			filters[manticore.RegexFields[filter.Field]] = filter
+		} else if field == "id" {
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, nil, validation.InvalidFormat("filter", param, "id:<integer>")
+			}
+			filters[field] = id
+		} else {
+			filters[field] = value
+		}
+		display[field] = value
+	}
+	return filters, display, nil
+}
+
+// regexFilterValue returns the pattern of a REGEX(<pattern>) filter value
+func regexFilterValue(value string) (string, bool) {
+	if len(value) < len("REGEX()") || !strings.EqualFold(value[:len("REGEX(")], "REGEX(") || !strings.HasSuffix(value, ")") {
+		return "", false
+	}
+	return value[len("REGEX(") : len(value)-1], true
+}
+
+// joinStatuses formats document statuses as a comma-separated list
+func joinStatuses(statuses []models.DocumentStatus) string {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	return strings.Join(names, ",")
+}
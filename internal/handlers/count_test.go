@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// countClient records the arguments of Count
+type countClient struct {
+	MockManticoreClient
+	query   string
+	filters map[string]interface{}
+}
+
+func (c *countClient) Count(query string, filters map[string]interface{}) (int64, error) {
+	c.query, c.filters = query, filters
+	return 1234, nil
+}
+
+func TestCountHandler(t *testing.T) {
+	client := &countClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+	app := &AppState{Manticore: client}
+
+	req := httptest.NewRequest("GET", "/api/count?query=form&filter=title:contact+us&filter=id:5", nil)
+	w := httptest.NewRecorder()
+	app.CountHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data api.CountResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.Count != 1234 || response.Data.Query != "form" {
+		t.Errorf("Unexpected response: %+v", response.Data)
+	}
+	if client.filters["title"] != "contact us" || client.filters["id"] != int64(5) {
+		t.Errorf("Unexpected filters passed to Count: %v", client.filters)
+	}
+	if statuses, ok := client.filters["status"].([]models.DocumentStatus); !ok || len(statuses) != 1 || statuses[0] != models.DocumentStatusActive {
+		t.Errorf("Expected only active documents to be counted, got %v", client.filters["status"])
+	}
+}
+
+func TestCountHandlerStatus(t *testing.T) {
+	client := &countClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+	app := &AppState{Manticore: client}
+
+	w := httptest.NewRecorder()
+	app.CountHandler(w, httptest.NewRequest("GET", "/api/count?status=all", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if statuses, ok := client.filters["status"].([]models.DocumentStatus); !ok || len(statuses) != 3 {
+		t.Errorf("Expected every status, got %v", client.filters["status"])
+	}
+
+	for _, url := range []string{"/api/count?status=hidden", "/api/count?filter=status:archived"} {
+		w := httptest.NewRecorder()
+		app.CountHandler(w, httptest.NewRequest("GET", url, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", url, w.Code)
+		}
+	}
+}
+
+func TestCountHandlerRegexFilter(t *testing.T) {
+	client := &countClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+	app := &AppState{Manticore: client}
+
+	req := httptest.NewRequest("GET", "/api/count?filter="+url.QueryEscape(`url:REGEX(^https://go\.dev/)`), nil)
+	w := httptest.NewRecorder()
+	app.CountHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	filter, ok := client.filters[manticore.URLAttribute].(manticore.RegexFilter)
+	if !ok || filter.Field != "url" || filter.Pattern != `^https://go\.dev/` {
+		t.Errorf("Expected a regex filter on url, got %v", client.filters)
+	}
+	if _, ok := client.filters["url"]; ok {
+		t.Errorf("Expected no phrase filter on url, got %v", client.filters)
+	}
+}
+
+func TestCountHandlerInvalidFilter(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+
+	for _, filter := range []string{"title", "id:abc", ":value", "title:REGEX(go)", "url:REGEX((go)"} {
+		req := httptest.NewRequest("GET", "/api/count?filter="+url.QueryEscape(filter), nil)
+		w := httptest.NewRecorder()
+		app.CountHandler(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for filter %q, got %d", filter, w.Code)
+		}
+	}
+}
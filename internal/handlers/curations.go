@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/curation"
+	"github.com/ad/manticoresearch-go/internal/validation"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// CurationsHandler handles /api/admin/curations requests: GET lists curations, POST creates or
+// replaces the one given as a JSON body, and DELETE removes the one given by the name parameter.
+// Curations change the results of every caller, so POST and DELETE require the admin token.
+func (app *AppState) CurationsHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if app.Curations == nil {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Curations are not available")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		app.listCurations(w)
+	case "POST":
+		app.putCuration(w, r)
+	case "DELETE":
+		app.deleteCuration(w, r)
+	default:
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (app *AppState) listCurations(w http.ResponseWriter) {
+	curations := app.Curations.List()
+
+	response := api.CurationListResponse{
+		Curations: make([]api.Curation, 0, len(curations)),
+	}
+	for _, c := range curations {
+		response.Curations = append(response.Curations, curationResponse(c))
+	}
+	response.Count = len(response.Curations)
+
+	app.sendSuccessResponse(w, response)
+}
+
+func (app *AppState) putCuration(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	if !app.authorizeAdmin(w, r) {
+		app.recordAudit(r, "curation_put", nil, fmt.Errorf("unauthorized"), startTime)
+		return
+	}
+
+	var request api.Curation
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			app.sendErrorResponse(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body too large (at most %d bytes)", maxBytesErr.Limit))
+			return
+		}
+		app.sendErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid curation: %v", err))
+		return
+	}
+
+	c := curation.Curation{
+		Name:    request.Name,
+		Pattern: request.Pattern,
+		Match:   request.Match,
+		Pinned:  request.Pinned,
+		Hidden:  request.Hidden,
+	}
+	if err := c.Validate(); err != nil {
+		app.sendValidationError(w, r, err)
+		return
+	}
+
+	stored, err := app.Curations.Put(c)
+	app.recordAudit(r, "curation_put", map[string]interface{}{"name": c.Name, "pattern": c.Pattern, "pinned": c.Pinned, "hidden": c.Hidden}, err, startTime)
+	if err != nil {
+		log.Printf("Failed to store curation %s: %v", c.Name, err)
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to store curation: %v", err))
+		return
+	}
+	// Curated results differ from those issued before, so their ETags must not match
+	app.IndexChanged()
+
+	app.sendSuccessResponse(w, curationResponse(stored))
+}
+
+func (app *AppState) deleteCuration(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	name := r.URL.Query().Get("name")
+	if !app.authorizeAdmin(w, r) {
+		app.recordAudit(r, "curation_delete", map[string]interface{}{"name": name}, fmt.Errorf("unauthorized"), startTime)
+		return
+	}
+	if name == "" {
+		app.sendValidationError(w, r, validation.Required("name"))
+		return
+	}
+
+	deleted, err := app.Curations.Delete(name)
+	app.recordAudit(r, "curation_delete", map[string]interface{}{"name": name}, err, startTime)
+	if err != nil {
+		log.Printf("Failed to delete curation %s: %v", name, err)
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to delete curation: %v", err))
+		return
+	}
+	if !deleted {
+		app.sendErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Curation %q not found", name))
+		return
+	}
+	app.IndexChanged()
+
+	app.sendSuccessResponse(w, api.CurationDeleteResponse{Name: name, Deleted: true})
+}
+
+// curationResponse converts a curation to its API form
+func curationResponse(c curation.Curation) api.Curation {
+	return api.Curation{
+		Name:      c.Name,
+		Pattern:   c.Pattern,
+		Match:     c.Match,
+		Pinned:    c.Pinned,
+		Hidden:    c.Hidden,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/audit"
+	"github.com/ad/manticoresearch-go/internal/curation"
+)
+
+// curationRequest builds a curation request with the admin token
+func curationRequest(method, url, body string) *http.Request {
+	r := httptest.NewRequest(method, url, strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer secret")
+	return r
+}
+
+func TestCurationsHandler(t *testing.T) {
+	store, _ := curation.NewStore("")
+	auditLog, _ := audit.New("", 10)
+	app := &AppState{Curations: store, AdminToken: "secret", Audit: auditLog}
+
+	w := httptest.NewRecorder()
+	app.CurationsHandler(w, curationRequest("POST", "/api/admin/curations", `{"name": "pricing", "pattern": "Pricing", "pinned": [4, 2], "hidden": [9]}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if c, ok := store.Get("pricing"); !ok || len(c.Pinned) != 2 || c.Hidden[0] != 9 {
+		t.Errorf("Unexpected curation: %+v", c)
+	}
+	if app.IndexGeneration() != 1 {
+		t.Error("Expected storing a curation to advance the index generation")
+	}
+	if entries := auditLog.Recent(10, "curation_put"); len(entries) != 1 {
+		t.Errorf("Expected the curation to be audited, got %+v", entries)
+	}
+
+	w = httptest.NewRecorder()
+	app.CurationsHandler(w, httptest.NewRequest("GET", "/api/admin/curations", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"count":1`) {
+		t.Errorf("Unexpected list response %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	app.CurationsHandler(w, curationRequest("DELETE", "/api/admin/curations?name=pricing", ""))
+	if w.Code != http.StatusOK || len(store.List()) != 0 {
+		t.Errorf("Expected the curation to be deleted, got %d: %s", w.Code, w.Body.String())
+	}
+
+	errorTests := []struct {
+		method, url, body string
+		token             bool
+		expectedCode      int
+	}{
+		{"POST", "/api/admin/curations", `{"name": "x", "pattern": "x", "pinned": [1]}`, false, http.StatusUnauthorized},
+		{"DELETE", "/api/admin/curations?name=x", "", false, http.StatusUnauthorized},
+		{"POST", "/api/admin/curations", `{"name":`, true, http.StatusBadRequest},
+		{"POST", "/api/admin/curations", `{"name": "x", "pattern": "x", "pinned": [1], "boost": 2}`, true, http.StatusBadRequest},
+		{"POST", "/api/admin/curations", `{"name": "x", "pattern": "x", "match": "prefix", "pinned": [1]}`, true, http.StatusBadRequest},
+		{"POST", "/api/admin/curations", `{"name": "x", "pattern": "x", "pinned": [1], "hidden": [1]}`, true, http.StatusBadRequest},
+		{"DELETE", "/api/admin/curations", "", true, http.StatusBadRequest},
+		{"DELETE", "/api/admin/curations?name=missing", "", true, http.StatusNotFound},
+		{"PUT", "/api/admin/curations", "", true, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range errorTests {
+		r := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+		if tt.token {
+			r = curationRequest(tt.method, tt.url, tt.body)
+		}
+		w := httptest.NewRecorder()
+		app.CurationsHandler(w, r)
+		if w.Code != tt.expectedCode {
+			t.Errorf("%s %s %s: expected status %d, got %d", tt.method, tt.url, tt.body, tt.expectedCode, w.Code)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/ad/manticoresearch-go/internal/deadletter"
+	"github.com/ad/manticoresearch-go/internal/document"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// maxListedRejections is how many rejected documents an indexing response lists
+const maxListedRejections = 100
+
+// DeadLettersHandler handles GET /api/admin/dead-letters requests, listing the documents rejected
+// by validation, newest first, optionally filtered by source (startup or reindex). Requires the
+// admin token.
+func (app *AppState) DeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow GET requests
+	if r.Method != "GET" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Entries hold the full rejected documents
+	if !app.authorizeAdmin(w, r) {
+		return
+	}
+
+	limit, err := parseIntRangeParam(r.URL.Query().Get("limit"), "limit", 50, 1, deadletter.DefaultMaxRecent)
+	if err != nil {
+		app.sendValidationError(w, r, err)
+		return
+	}
+
+	entries := app.DeadLetters.Recent(limit, r.URL.Query().Get("source"))
+
+	response := api.DeadLetterResponse{
+		Entries: make([]api.DeadLetterEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		doc := entry.Document
+		response.Entries = append(response.Entries, api.DeadLetterEntry{
+			ID:        entry.ID,
+			Timestamp: entry.Timestamp,
+			Source:    entry.Source,
+			Document: api.Document{
+				ID:        doc.ID,
+				Title:     doc.Title,
+				URL:       doc.URL,
+				Content:   doc.Content,
+				UpdatedAt: doc.UpdatedAt,
+				Tags:      doc.Tags,
+			},
+			Reasons: entry.Reasons,
+		})
+	}
+	response.Count = len(response.Entries)
+
+	app.sendSuccessResponse(w, response)
+}
+
+// ValidateDocuments checks documents against the document rules before they are indexed. It
+// returns the documents passing them and the rejected ones, which are added to the dead-letter
+// store under source.
+func (app *AppState) ValidateDocuments(source string, documents []*models.Document) ([]*models.Document, []document.Rejection) {
+	valid, rejected := app.DocumentRules.Filter(documents)
+	for _, rejection := range rejected {
+		log.Printf("Warning: Document %d (%s) rejected: %v", rejection.Document.ID, rejection.Document.URL, rejection.Reasons)
+		app.DeadLetters.Add(source, rejection.Document, rejection.Reasons)
+	}
+	if len(rejected) > 0 {
+		log.Printf("%d of %d documents failed validation and were not indexed", len(rejected), len(documents))
+	}
+	return valid, rejected
+}
+
+// convertRejections converts up to maxListedRejections rejected documents to their API form
+func convertRejections(rejected []document.Rejection) []api.DocumentRejection {
+	if len(rejected) == 0 {
+		return nil
+	}
+	result := make([]api.DocumentRejection, 0, min(len(rejected), maxListedRejections))
+	for _, rejection := range rejected[:min(len(rejected), maxListedRejections)] {
+		result = append(result, api.DocumentRejection{
+			ID:      rejection.Document.ID,
+			Title:   rejection.Document.Title,
+			URL:     rejection.Document.URL,
+			Reasons: rejection.Reasons,
+		})
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/deadletter"
+	"github.com/ad/manticoresearch-go/internal/document"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/redact"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+func TestValidateDocumentsAndDeadLetters(t *testing.T) {
+	deadLetters, _ := deadletter.New("", 10)
+	app := &AppState{
+		DocumentRules: &document.Rules{Required: []string{"title"}, MaxContentLength: 10},
+		DeadLetters:   deadLetters,
+		AdminToken:    "secret",
+	}
+
+	documents := []*models.Document{
+		{ID: 1, Title: "Short", URL: "one.md", Content: "short"},
+		{ID: 2, URL: "two.md", Content: "no title"},
+		{ID: 3, Title: "Long", URL: "three.md", Content: "content longer than ten"},
+	}
+	valid, rejected := app.ValidateDocuments("reindex", documents)
+	if len(valid) != 1 || valid[0].ID != 1 {
+		t.Fatalf("Expected only document 1 to be valid, got %v", valid)
+	}
+	if listed := convertRejections(rejected); len(listed) != 2 || listed[0].ID != 2 || listed[1].URL != "three.md" {
+		t.Errorf("Unexpected rejections: %+v", listed)
+	}
+
+	req := httptest.NewRequest("GET", "/api/admin/dead-letters?source=reindex", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	app.DeadLettersHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response struct {
+		Success bool                   `json:"success"`
+		Data    api.DeadLetterResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.Count != 2 {
+		t.Fatalf("Expected 2 entries, got %d", response.Data.Count)
+	}
+	newest := response.Data.Entries[0]
+	if newest.Source != "reindex" || newest.Document.ID != 3 || newest.Reasons[0] != "content is 23 characters, more than 10" {
+		t.Errorf("Unexpected newest entry: %+v", newest)
+	}
+
+	req = httptest.NewRequest("GET", "/api/admin/dead-letters?source=startup", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w = httptest.NewRecorder()
+	app.DeadLettersHandler(w, req)
+	response.Data = api.DeadLetterResponse{}
+	json.NewDecoder(w.Body).Decode(&response)
+	if response.Data.Count != 0 {
+		t.Errorf("Expected no startup entries, got %d", response.Data.Count)
+	}
+}
+
+func TestDeadLettersHandlerUnauthorized(t *testing.T) {
+	deadLetters, _ := deadletter.New("", 10)
+	deadLetters.Add("reindex", &models.Document{ID: 1, Content: "private draft"}, []string{"title is required"})
+	app := &AppState{DeadLetters: deadLetters, AdminToken: "secret"}
+
+	req := httptest.NewRequest("GET", "/api/admin/dead-letters", nil)
+	req.Header.Set("Authorization", "Bearer guess")
+	w := httptest.NewRecorder()
+	app.DeadLettersHandler(w, req)
+
+	if w.Code != http.StatusUnauthorized || strings.Contains(w.Body.String(), "private draft") {
+		t.Errorf("Expected status 401 without entries, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestNormalizeDocumentsRedacts(t *testing.T) {
+	redactor, err := redact.New([]string{redact.DetectorEmail}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	app := &AppState{Redactor: redactor}
+
+	documents := []*models.Document{{ID: 1, Title: "Ticket from a@example.com", Content: "Reply to a@example.com"}}
+	app.NormalizeDocuments(documents)
+	if documents[0].Title != "Ticket from [EMAIL]" || documents[0].Content != "Reply to [EMAIL]" {
+		t.Errorf("Expected personal data to be masked, got %+v", documents[0])
+	}
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/validation"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// DocumentHandler handles GET /api/documents/{id} requests, returning a document whatever its
+// status. Responses carry a strong ETag of the document for revalidation with If-None-Match.
+func (app *AppState) DocumentHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow GET requests
+	if r.Method != "GET" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		app.sendValidationError(w, r, validation.TooSmall("id", r.PathValue("id"), 1))
+		return
+	}
+
+	// Check if Manticore is available
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Manticore Search is not available")
+		return
+	}
+
+	doc, err := app.Documents.Get(id, app.Manticore.GetDocument)
+	if err != nil {
+		if errors.Is(err, manticore.ErrDocumentNotFound) {
+			app.sendErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Document %d not found", id))
+			return
+		}
+		log.Printf("Failed to get document %d: %v", id, err)
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get document: %v", err))
+		return
+	}
+
+	etag := documentETag(doc)
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	app.sendSuccessResponse(w, doc)
+}
+
+// ArchiveDocumentHandler handles POST /api/documents/archive?id=... requests
+func (app *AppState) ArchiveDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	app.handleDocumentStatus(w, r, models.DocumentStatusArchived)
+}
+
+// RestoreDocumentHandler handles POST /api/documents/restore?id=... requests, making an archived
+// or deleted document searchable again
+func (app *AppState) RestoreDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	app.handleDocumentStatus(w, r, models.DocumentStatusActive)
+}
+
+// DeleteDocumentHandler handles POST /api/documents/delete?id=... requests. The document is only
+// marked as deleted, so it can still be restored.
+func (app *AppState) DeleteDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	app.handleDocumentStatus(w, r, models.DocumentStatusDeleted)
+}
+
+// handleDocumentStatus sets the status of the document given by the id parameter
+func (app *AppState) handleDocumentStatus(w http.ResponseWriter, r *http.Request, status models.DocumentStatus) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow POST requests
+	if r.Method != "POST" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, ok := app.documentIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	// Check if Manticore is available
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Manticore Search is not available")
+		return
+	}
+
+	startTime := time.Now()
+	err := app.Manticore.SetDocumentStatus(id, status)
+	// The kept copy may be stale even when the update failed part way
+	app.Documents.Invalidate(id)
+	app.recordAudit(r, "document_status", map[string]interface{}{"id": id, "status": string(status)}, err, startTime)
+	if err != nil {
+		log.Printf("Failed to set status of document %d to %s: %v", id, status, err)
+		if errors.Is(err, manticore.ErrDocumentNotFound) {
+			app.sendErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Document %d not found", id))
+			return
+		}
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update document status: %v", err))
+		return
+	}
+
+	app.IndexChanged()
+	app.sendSuccessResponse(w, api.DocumentStatusResponse{ID: id, Status: string(status)})
+}
+
+// DocumentTagsHandler handles POST /api/documents/tags?id=...&tags=... requests, replacing the tags
+// of a document with the comma-separated tags parameter. An empty parameter removes every tag.
+func (app *AppState) DocumentTagsHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow POST requests
+	if r.Method != "POST" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, ok := app.documentIDParam(w, r)
+	if !ok {
+		return
+	}
+	tags := models.NormalizeTags(strings.Split(r.URL.Query().Get("tags"), ","))
+
+	// Check if Manticore is available
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Manticore Search is not available")
+		return
+	}
+
+	startTime := time.Now()
+	err := app.Manticore.SetDocumentTags(id, tags)
+	app.Documents.Invalidate(id)
+	app.recordAudit(r, "document_tags", map[string]interface{}{"id": id, "tags": tags}, err, startTime)
+	if err != nil {
+		log.Printf("Failed to set tags of document %d: %v", id, err)
+		if errors.Is(err, manticore.ErrDocumentNotFound) {
+			app.sendErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Document %d not found", id))
+			return
+		}
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update document tags: %v", err))
+		return
+	}
+
+	app.IndexChanged()
+	app.sendSuccessResponse(w, api.DocumentTagsResponse{ID: id, Tags: tags})
+}
+
+// documentIDParam parses the id parameter, sending a 400 response when it is not a positive document id
+func (app *AppState) documentIDParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil || id <= 0 {
+		app.sendValidationError(w, r, validation.TooSmall("id", r.URL.Query().Get("id"), 1))
+		return 0, false
+	}
+	return id, true
+}
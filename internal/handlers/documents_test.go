@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// statusClient records status and tag changes and knows a single document
+type statusClient struct {
+	MockManticoreClient
+	id     int64
+	status models.DocumentStatus
+	tags   []string
+}
+
+func (c *statusClient) SetDocumentTags(id int64, tags []string) error {
+	if id != 7 {
+		return fmt.Errorf("%w: %d", manticore.ErrDocumentNotFound, id)
+	}
+	c.id, c.tags = id, tags
+	return nil
+}
+
+func (c *statusClient) SetDocumentStatus(id int64, status models.DocumentStatus) error {
+	if id != 7 {
+		return fmt.Errorf("%w: %d", manticore.ErrDocumentNotFound, id)
+	}
+	c.id, c.status = id, status
+	return nil
+}
+
+func (c *statusClient) GetDocument(id int64) (*models.Document, error) {
+	if id != 7 {
+		return nil, fmt.Errorf("%w: %d", manticore.ErrDocumentNotFound, id)
+	}
+	return &models.Document{ID: 7, Title: "Contact form", Status: c.status, Tags: c.tags}, nil
+}
+
+func TestDocumentHandler(t *testing.T) {
+	client := &statusClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+	app := &AppState{Manticore: client}
+
+	get := func(id, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/api/documents/"+id, nil)
+		r.SetPathValue("id", id)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		app.DocumentHandler(w, r)
+		return w
+	}
+
+	w := get("7", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" || etag[0] != '"' {
+		t.Fatalf("Expected a strong ETag, got %q", etag)
+	}
+
+	if w := get("7", etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("Expected 304 without a body for a matching ETag, got %d", w.Code)
+	}
+
+	// Changing the tags changes the document and its ETag
+	w = httptest.NewRecorder()
+	app.DocumentTagsHandler(w, httptest.NewRequest("POST", "/api/documents/tags?id=7&tags=forms", nil))
+	if w := get("7", etag); w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("Expected a new ETag after a tag change, got %d %s", w.Code, w.Header().Get("ETag"))
+	}
+
+	if w := get("404", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+	if w := get("abc", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestDocumentStatusHandlers(t *testing.T) {
+	client := &statusClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+	app := &AppState{Manticore: client}
+
+	tests := []struct {
+		handler  http.HandlerFunc
+		expected models.DocumentStatus
+	}{
+		{app.ArchiveDocumentHandler, models.DocumentStatusArchived},
+		{app.DeleteDocumentHandler, models.DocumentStatusDeleted},
+		{app.RestoreDocumentHandler, models.DocumentStatusActive},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		tt.handler(w, httptest.NewRequest("POST", "/api/documents/x?id=7", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if client.id != 7 || client.status != tt.expected {
+			t.Errorf("Expected document 7 to be %s, got %d %s", tt.expected, client.id, client.status)
+		}
+	}
+
+	errorTests := []struct {
+		method, url  string
+		expectedCode int
+	}{
+		{"POST", "/api/documents/archive?id=404", http.StatusNotFound},
+		{"POST", "/api/documents/archive?id=abc", http.StatusBadRequest},
+		{"POST", "/api/documents/archive", http.StatusBadRequest},
+		{"GET", "/api/documents/archive?id=7", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range errorTests {
+		w := httptest.NewRecorder()
+		app.ArchiveDocumentHandler(w, httptest.NewRequest(tt.method, tt.url, nil))
+		if w.Code != tt.expectedCode {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.url, tt.expectedCode, w.Code)
+		}
+	}
+}
+
+func TestSearchHandler_InvalidStatus(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+
+	w := httptest.NewRecorder()
+	app.SearchHandler(w, httptest.NewRequest("GET", "/api/search?query=test&status=hidden", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestDocumentTagsHandler(t *testing.T) {
+	client := &statusClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+	app := &AppState{Manticore: client}
+
+	w := httptest.NewRecorder()
+	app.DocumentTagsHandler(w, httptest.NewRequest("POST", "/api/documents/tags?id=7&tags=Go,search,,go", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if client.id != 7 || !reflect.DeepEqual(client.tags, []string{"go", "search"}) {
+		t.Errorf("Expected normalized tags for document 7, got %d %v", client.id, client.tags)
+	}
+
+	errorTests := []struct {
+		method, url  string
+		expectedCode int
+	}{
+		{"POST", "/api/documents/tags?id=404&tags=go", http.StatusNotFound},
+		{"POST", "/api/documents/tags?tags=go", http.StatusBadRequest},
+		{"GET", "/api/documents/tags?id=7", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range errorTests {
+		w := httptest.NewRecorder()
+		app.DocumentTagsHandler(w, httptest.NewRequest(tt.method, tt.url, nil))
+		if w.Code != tt.expectedCode {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.url, tt.expectedCode, w.Code)
+		}
+	}
+}
+
+func TestSearchHandler_InvalidTagsMode(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+
+	w := httptest.NewRecorder()
+	app.SearchHandler(w, httptest.NewRequest("GET", "/api/search?query=test&tags=go&tags_mode=some", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
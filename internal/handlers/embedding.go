@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/tokens"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// maxListedTruncations bounds the documents a reindex response lists as embedded truncated
+const maxListedTruncations = 100
+
+// EmbeddingTruncations returns the documents whose content is estimated above the input limit of
+// the AI model, see models.AISearchConfig.MaxTokens. Manticore embeds the content of a document
+// as a whole, so the model only sees the beginning of these. They are logged; there are none
+// while AI search is disabled.
+func (app *AppState) EmbeddingTruncations(documents []*models.Document) []api.EmbeddingTruncation {
+	aiConfig := app.CurrentAIConfig()
+	if aiConfig == nil || !aiConfig.Enabled || aiConfig.MaxTokens <= 0 {
+		return nil
+	}
+	var truncations []api.EmbeddingTruncation
+	for _, doc := range documents {
+		count := tokens.Estimate(doc.Content)
+		if count <= aiConfig.MaxTokens {
+			continue
+		}
+		log.Printf("Warning: Document %d (%s) has ~%d tokens, AI search only embeds the first %d", doc.ID, doc.URL, count, aiConfig.MaxTokens)
+		truncations = append(truncations, api.EmbeddingTruncation{ID: doc.ID, Title: doc.Title, URL: doc.URL, Tokens: count})
+	}
+	if len(truncations) > 0 {
+		log.Printf("%d of %d documents exceed the input limit of the AI model (%d tokens) and are embedded truncated", len(truncations), len(documents), aiConfig.MaxTokens)
+	}
+	return truncations
+}
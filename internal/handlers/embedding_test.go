@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestEmbeddingTruncations(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, Title: "Short", Content: "a few words"},
+		{ID: 2, Title: "Long", URL: "/long", Content: strings.Repeat("word ", 20)},
+	}
+	app := &AppState{AIConfig: &models.AISearchConfig{Enabled: true, MaxTokens: 10}}
+
+	truncations := app.EmbeddingTruncations(documents)
+	if len(truncations) != 1 || truncations[0].ID != 2 || truncations[0].Tokens != 20 || truncations[0].URL != "/long" {
+		t.Fatalf("Expected the long document, got %+v", truncations)
+	}
+
+	// Without a limit or with AI search disabled nothing is truncated
+	app.AIConfig.MaxTokens = 0
+	if truncations := app.EmbeddingTruncations(documents); len(truncations) != 0 {
+		t.Errorf("Expected no truncations without a limit, got %+v", truncations)
+	}
+	app.AIConfig = &models.AISearchConfig{Enabled: false, MaxTokens: 10}
+	if truncations := app.EmbeddingTruncations(documents); len(truncations) != 0 {
+		t.Errorf("Expected no truncations with AI search disabled, got %+v", truncations)
+	}
+}
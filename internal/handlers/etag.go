@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// etagEpoch distinguishes the index generations of different server runs, which all start at zero
+var etagEpoch = strconv.FormatInt(time.Now().UnixNano(), 36)
+
+// IndexChanged advances the index generation after documents were indexed or modified, so search
+// ETags issued before no longer match
+func (app *AppState) IndexChanged() {
+	app.indexGeneration.Add(1)
+}
+
+// IndexGeneration returns the number of index changes since the server started
+func (app *AppState) IndexGeneration() uint64 {
+	return app.indexGeneration.Load()
+}
+
+// searchVary lists the request headers search results depend on besides the URL: the response
+// format, and the tenant selected by the X-Tenant header or the API key
+const searchVary = "Accept, X-Tenant, X-API-Key"
+
+// searchETag returns a weak ETag for search results, derived from the search parameters, the
+// response format, the tenant and the index generation. It is weak because the same ETag promises
+// the same results, not a byte-identical body.
+func searchETag(key, format, tenant string, generation uint64) string {
+	sum := sha256.Sum256([]byte(key + "|format=" + format + "|tenant=" + tenant))
+	return fmt.Sprintf(`W/"%s-%s-%d"`, hex.EncodeToString(sum[:8]), etagEpoch, generation)
+}
+
+// documentETag returns a strong ETag of a document, changing with any of its fields
+func documentETag(doc *models.Document) string {
+	encoded, _ := json.Marshal(doc)
+	sum := sha256.Sum256(encoded)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, comparing weakly as RFC 9110
+// requires for If-None-Match
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" || etag == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		ifNoneMatch, etag string
+		expected          bool
+	}{
+		{"", `"a"`, false},
+		{`"a"`, `"a"`, true},
+		{`W/"a"`, `"a"`, true},
+		{`"a"`, `W/"a"`, true},
+		{`"b", W/"a"`, `W/"a"`, true},
+		{`"b"`, `"a"`, false},
+		{"*", `"a"`, true},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.ifNoneMatch, tt.etag); got != tt.expected {
+			t.Errorf("etagMatches(%q, %q) = %t, expected %t", tt.ifNoneMatch, tt.etag, got, tt.expected)
+		}
+	}
+}
+
+func TestSearchHandler_ETag(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+
+	search := func(url, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", url, nil)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		app.SearchHandler(w, r)
+		return w
+	}
+
+	w := search("/api/search?query=form", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	etag := w.Header().Get("ETag")
+	if len(etag) < 2 || etag[:2] != "W/" {
+		t.Fatalf("Expected a weak ETag, got %q", etag)
+	}
+
+	if w := search("/api/search?query=form", etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("Expected 304 without a body, got %d", w.Code)
+	}
+	if w := search("/api/search?query=form&page=2", etag); w.Code != http.StatusOK {
+		t.Errorf("Expected another page not to match, got %d", w.Code)
+	}
+
+	app.IndexChanged()
+	w = search("/api/search?query=form", etag)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("Expected fresh results with a new ETag after an index change, got %d %s", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+func TestSearchHandler_ETagPerTenant(t *testing.T) {
+	search := func(app *AppState, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/api/search?query=form", nil)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		app.SearchHandler(w, r)
+		return w
+	}
+
+	// Both tenants are at the same index generation
+	acme := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}, Tenant: "acme"}
+	globex := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}, Tenant: "globex"}
+
+	w := search(acme, "")
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("Expected results with an ETag, got %d %q", w.Code, etag)
+	}
+	if got := w.Header().Get("Vary"); got != searchVary {
+		t.Errorf("Expected Vary: %s, got %q", searchVary, got)
+	}
+	if w := search(globex, etag); w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("Expected another tenant's ETag not to match, got %d %s", w.Code, w.Header().Get("ETag"))
+	}
+	if w := search(acme, etag); w.Code != http.StatusNotModified {
+		t.Errorf("Expected the tenant's own ETag to match, got %d", w.Code)
+	}
+}
+
+func TestIndexGeneration(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+	app.IndexChanged()
+	app.IndexChanged()
+
+	if generation := app.status().IndexGeneration; generation != 2 {
+		t.Errorf("Expected generation 2 in the status, got %d", generation)
+	}
+
+	w := httptest.NewRecorder()
+	app.SearchHandler(w, httptest.NewRequest("GET", "/api/search?query=form", nil))
+	response, err := api.Decode[api.SearchResponse](w.Body)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.IndexGeneration != 2 {
+		t.Errorf("Expected results of generation 2, got %d", response.Data.IndexGeneration)
+	}
+}
@@ -0,0 +1,407 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/internal/usage"
+	"github.com/ad/manticoresearch-go/internal/validation"
+	"github.com/ad/manticoresearch-go/pkg/api"
+	"github.com/ad/manticoresearch-go/pkg/api/searchpb"
+)
+
+// NewGRPCServer creates a gRPC server exposing searchpb.SearchService. It shares the Manticore
+// client, vectorizer and search engine with the REST handlers.
+func (app *AppState) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(app.grpcUnaryAPIKey),
+		grpc.ChainStreamInterceptor(app.grpcStreamAPIKey))
+	server := grpc.NewServer(opts...)
+	searchpb.RegisterSearchServiceServer(server, &grpcService{app: app})
+	return server
+}
+
+// grpcAuthenticate authenticates the x-api-key metadata of a call when APIKeys are configured and
+// selects its tenant like RouteTenants, with x-tenant metadata. The returned context carries the
+// name of the key and the state of the tenant.
+func (app *AppState) grpcAuthenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(app.APIKeys) > 0 {
+		secret := ""
+		if values := md.Get("x-api-key"); len(values) > 0 {
+			secret = values[0]
+		}
+		name, ok := app.authenticateAPIKey(secret)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "invalid or missing API key")
+		}
+		ctx = context.WithValue(ctx, apiKeyContextKey{}, name)
+	}
+
+	requested := ""
+	if values := md.Get("x-tenant"); len(values) > 0 {
+		requested = values[0]
+	}
+	tenant, err := app.tenantFor(contextAPIKey(ctx), requested)
+	switch {
+	case errors.Is(err, errTenantForbidden):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return context.WithValue(ctx, tenantContextKey{}, tenant), nil
+}
+
+// tenantContextKey is the context key of the *AppState of a gRPC caller's tenant
+type tenantContextKey struct{}
+
+func (app *AppState) grpcUnaryAPIKey(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := app.grpcAuthenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (app *AppState) grpcStreamAPIKey(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := app.grpcAuthenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticatedStream is a grpc.ServerStream with the context of its authenticated caller
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcService implements searchpb.SearchServiceServer
+type grpcService struct {
+	searchpb.UnimplementedSearchServiceServer
+	app *AppState
+}
+
+// forTenant returns the service of the caller's tenant, selected by grpcAuthenticate
+func (s *grpcService) forTenant(ctx context.Context) *grpcService {
+	if tenant, ok := ctx.Value(tenantContextKey{}).(*AppState); ok && tenant != s.app {
+		return &grpcService{app: tenant}
+	}
+	return s
+}
+
+// Search implements searchpb.SearchServiceServer
+func (s *grpcService) Search(ctx context.Context, request *searchpb.SearchRequest) (*searchpb.SearchResponse, error) {
+	s = s.forTenant(ctx)
+	engine, mode, page, limit, err := s.prepareSearch(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.app.Usage.Reserve(contextAPIKey(ctx), searchUses(mode, request.GetQuery())); err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	generation := s.app.IndexGeneration()
+	result, err := engine.SearchContext(ctx, strings.TrimSpace(request.GetQuery()), mode, page, limit)
+	if err != nil {
+		log.Printf("gRPC search error (mode: %s): %v", mode, err)
+		return nil, searchErrorStatus(err)
+	}
+	result.IndexGeneration = generation
+	return searchResponseProto(result), nil
+}
+
+// SearchStream implements searchpb.SearchServiceServer
+func (s *grpcService) SearchStream(request *searchpb.SearchRequest, stream grpc.ServerStreamingServer[searchpb.SearchStage]) error {
+	s = s.forTenant(stream.Context())
+	engine, mode, page, limit, err := s.prepareSearch(request)
+	if err != nil {
+		return err
+	}
+	if err := s.app.Usage.Reserve(contextAPIKey(stream.Context()), searchUses(mode, request.GetQuery())); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	generation := s.app.IndexGeneration()
+	err = engine.SearchStream(stream.Context(), strings.TrimSpace(request.GetQuery()), mode, page, limit, func(stage string, response *models.SearchResponse, final bool) error {
+		response.IndexGeneration = generation
+		return stream.Send(&searchpb.SearchStage{Stage: stage, Final: final, Results: searchResponseProto(response)})
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			// Send failures already carry the status of the broken stream
+			return err
+		}
+		log.Printf("gRPC search stream error (mode: %s): %v", mode, err)
+		return searchErrorStatus(err)
+	}
+	return nil
+}
+
+// invalidArgumentStatus is the InvalidArgument status of a request with invalid fields, listing
+// them as BadRequest field violations
+func invalidArgumentStatus(errs validation.Errors) error {
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(errs))
+	for _, fieldErr := range errs {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: fieldErr.Field, Description: fieldErr.Error()})
+	}
+
+	st := status.New(codes.InvalidArgument, errs.Error())
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// prepareSearch validates a search request like SearchHandler and builds its search engine
+func (s *grpcService) prepareSearch(request *searchpb.SearchRequest) (*search.SearchEngine, models.SearchMode, int, int, error) {
+	var errs validation.Errors
+	query := strings.TrimSpace(request.GetQuery())
+	if query == "" {
+		errs.Add("query", validation.Required("query"))
+	} else {
+		errs.Add("query", search.ValidateQuery(query))
+	}
+
+	modeStr := strings.TrimSpace(request.GetMode())
+	if modeStr == "" {
+		modeStr = "basic"
+	}
+	mode, err := search.ValidateSearchMode(modeStr)
+	errs.Add("mode", err)
+
+	page, limit := int(request.GetPage()), int(request.GetLimit())
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = 10
+	}
+	if page < 1 || page > search.MaxPage {
+		errs.Add("page", validation.OutOfRange("page", strconv.Itoa(page), 1, search.MaxPage))
+	}
+	if limit < 1 || limit > 100 {
+		errs.Add("limit", validation.OutOfRange("limit", strconv.Itoa(limit), 1, 100))
+	}
+
+	fields, err := search.ParseFields(strings.Join(request.GetFields(), ","))
+	errs.Add("fields", err)
+	statuses, err := search.ParseStatuses(strings.Join(request.GetStatuses(), ","))
+	errs.Add("statuses", err)
+	recency, err := search.ParseRecency(request.GetSort(), request.GetSince())
+	errs.Add("sort", err)
+	tags, err := search.ParseTags(strings.Join(request.GetTags(), ","), request.GetTagsMode())
+	errs.Add("tags_mode", err)
+
+	if len(errs) > 0 {
+		return nil, "", 0, 0, invalidArgumentStatus(errs)
+	}
+
+	if s.app.Manticore == nil || !s.app.Manticore.IsConnected() {
+		return nil, "", 0, 0, status.Error(codes.Unavailable, "Search service is not available")
+	}
+	if mode.UsesAI() {
+		if err := s.app.validateAISearchAvailability(); err != nil {
+			log.Printf("AI search not available: %v, degrading to hybrid search", err)
+			mode = models.SearchModeHybrid
+		}
+	}
+
+	engine := search.NewSearchEngine(s.app.Manticore, s.app.Vectorizer, s.app.CurrentAIConfig()).WithFields(fields).WithStatuses(statuses).WithRecency(recency).WithTags(tags).WithRescoreWindow(s.app.RescoreWindow).WithRelaxation(s.app.Relaxation).WithSpellTolerance(s.app.SpellTolerance).WithStopwords(s.app.Stopwords).WithNormalizer(s.app.Normalizer).WithBoosts(s.app.Boosts).WithFeatures(s.app.Features).WithBlocklist(s.app.Blocklist).WithCalibration(s.app.Calibration).WithCurations(s.app.Curations)
+	return engine, mode, page, limit, nil
+}
+
+// Reindex implements searchpb.SearchServiceServer
+func (s *grpcService) Reindex(ctx context.Context, request *searchpb.ReindexRequest) (*searchpb.ReindexResponse, error) {
+	s = s.forTenant(ctx)
+	if s.app.Manticore == nil || !s.app.Manticore.IsConnected() {
+		return nil, status.Error(codes.Unavailable, "Manticore Search is not available")
+	}
+
+	startTime := time.Now()
+	log.Println("Manual reindexing requested over gRPC")
+
+	auditParams := map[string]interface{}{"data_dir": s.app.DataDirectory(), "schema_reset": true}
+	response, err := s.app.reindex(contextAPIKey(ctx), auditParams, startTime)
+	s.app.recordAuditAs(grpcActor(ctx), grpcRemoteAddr(ctx), "reindex", auditParams, err, startTime)
+	s.app.PublishReindex("grpc", auditParams, err, startTime)
+	if err != nil {
+		var quotaErr *usage.QuotaError
+		if errors.As(err, &quotaErr) {
+			return nil, status.Error(codes.ResourceExhausted, quotaErr.Error())
+		}
+		var failure *reindexError
+		if errors.As(err, &failure) {
+			code := codes.Internal
+			if failure.status == http.StatusBadRequest {
+				code = codes.FailedPrecondition
+			}
+			return nil, status.Error(code, failure.message)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &searchpb.ReindexResponse{
+		Message:        response.Message,
+		DocumentsCount: int64(response.DocumentsCount),
+		IndexingTime:   response.IndexingTime,
+	}, nil
+}
+
+// Status implements searchpb.SearchServiceServer
+func (s *grpcService) Status(ctx context.Context, request *searchpb.StatusRequest) (*searchpb.StatusResponse, error) {
+	return statusResponseProto(s.forTenant(ctx).app.status()), nil
+}
+
+// searchErrorStatus maps a search error to a gRPC status
+func searchErrorStatus(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	switch manticore.CategorizeError(err) {
+	case manticore.ErrorCategoryCircuitOpen:
+		return status.Error(codes.Unavailable, "Search backend is temporarily unavailable")
+	case manticore.ErrorCategoryTimeout:
+		return status.Error(codes.DeadlineExceeded, "Search timed out")
+	case manticore.ErrorCategoryNetwork:
+		return status.Errorf(codes.Unavailable, "Search backend is unreachable: %v", err)
+	case manticore.ErrorCategoryUnsupported:
+		return status.Errorf(codes.Unimplemented, "Search failed: %v", err)
+	case manticore.ErrorCategoryClient, manticore.ErrorCategorySchema:
+		return status.Errorf(codes.FailedPrecondition, "Search failed: %v", err)
+	default:
+		return status.Errorf(codes.Internal, "Search failed: %v", err)
+	}
+}
+
+// grpcActor identifies the caller from metadata set by an authenticating proxy, like requestActor
+func grpcActor(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range []string{"x-forwarded-user", "x-remote-user"} {
+		if values := md.Get(key); len(values) > 0 && strings.TrimSpace(values[0]) != "" {
+			return strings.TrimSpace(values[0])
+		}
+	}
+	return "anonymous"
+}
+
+// grpcRemoteAddr returns the address of the caller's connection
+func grpcRemoteAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// searchResponseProto converts search results to their protobuf form
+func searchResponseProto(response *models.SearchResponse) *searchpb.SearchResponse {
+	if response == nil {
+		return nil
+	}
+
+	result := &searchpb.SearchResponse{
+		Documents:     make([]*searchpb.SearchResult, 0, len(response.Documents)),
+		Total:         int64(response.Total),
+		TotalRelation: response.TotalRelation,
+		Page:          int32(response.Page),
+		Mode:          response.Mode,
+		Pagination:    response.Pagination,
+		Stale:         response.Stale,
+		Partial:       response.Partial,
+		Continuation:  response.Continuation,
+
+		IndexGeneration: response.IndexGeneration,
+	}
+	for _, item := range response.Documents {
+		result.Documents = append(result.Documents, &searchpb.SearchResult{Document: documentProto(item.Document), Score: item.Score})
+	}
+	if len(response.Facets) > 0 {
+		result.Facets = make(map[string]*searchpb.Facet, len(response.Facets))
+		for field, values := range response.Facets {
+			facet := &searchpb.Facet{Values: make([]*searchpb.FacetValue, 0, len(values))}
+			for _, value := range values {
+				facet.Values = append(facet.Values, &searchpb.FacetValue{Value: value.Value, Count: int64(value.Count)})
+			}
+			result.Facets[field] = facet
+		}
+	}
+	return result
+}
+
+func documentProto(doc *models.Document) *searchpb.Document {
+	if doc == nil {
+		return nil
+	}
+	return &searchpb.Document{
+		Id:        int64(doc.ID),
+		Title:     doc.Title,
+		Url:       doc.URL,
+		Content:   doc.Content,
+		Snippet:   doc.Snippet,
+		Status:    string(doc.Status),
+		IndexedAt: unixTimestampProto(doc.IndexedAt),
+		UpdatedAt: unixTimestampProto(doc.UpdatedAt),
+		Tags:      doc.Tags,
+	}
+}
+
+// unixTimestampProto converts Unix seconds, leaving unset times nil
+func unixTimestampProto(seconds int64) *timestamppb.Timestamp {
+	if seconds == 0 {
+		return nil
+	}
+	return timestamppb.New(time.Unix(seconds, 0))
+}
+
+func statusResponseProto(s api.StatusResponse) *searchpb.StatusResponse {
+	result := &searchpb.StatusResponse{
+		Status:           s.Status,
+		ManticoreHealthy: s.ManticoreHealthy,
+		ManticoreState:   s.ManticoreState,
+		ManticoreVersion: s.ManticoreVersion,
+		DocumentsLoaded:  int64(s.DocumentsLoaded),
+		VectorizerReady:  s.VectorizerReady,
+		AiSearchEnabled:  s.AISearchEnabled,
+		AiModel:          s.AIModel,
+		AiSearchHealthy:  s.AISearchHealthy,
+		ConnectionState:  s.ConnectionState,
+		SchemaPresent:    s.SchemaPresent,
+		MissingTables:    s.MissingTables,
+		VocabularySize:   int64(s.VocabularySize),
+		IndexGeneration:  s.IndexGeneration,
+	}
+	for _, table := range s.Tables {
+		result.Tables = append(result.Tables, &searchpb.TableStatus{
+			Name:      table.Name,
+			Exists:    table.Exists,
+			Documents: table.Documents,
+			DiskBytes: table.DiskBytes,
+			RamBytes:  table.RAMBytes,
+		})
+	}
+	if s.LastReindex != nil {
+		result.LastReindex = timestamppb.New(*s.LastReindex)
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/pkg/api/searchpb"
+)
+
+func dialTestGRPC(t *testing.T, app *AppState) searchpb.SearchServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := app.NewGRPCServer()
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return searchpb.NewSearchServiceClient(conn)
+}
+
+func TestGRPCSearch(t *testing.T) {
+	client := dialTestGRPC(t, &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}})
+
+	response, err := client.Search(context.Background(), &searchpb.SearchRequest{Query: "form", Mode: "fulltext"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if response.GetMode() != "fulltext" || response.GetPage() != 1 {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+
+	_, err = client.Search(context.Background(), &searchpb.SearchRequest{Query: "form", Limit: 500})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an out of range limit, got %v", err)
+	}
+	details := status.Convert(err).Details()
+	if len(details) != 1 {
+		t.Fatalf("Expected BadRequest details, got %v", details)
+	}
+	if badRequest, ok := details[0].(*errdetails.BadRequest); !ok || len(badRequest.GetFieldViolations()) != 1 || badRequest.GetFieldViolations()[0].GetField() != "limit" {
+		t.Errorf("Expected a violation of the limit field, got %v", details[0])
+	}
+	_, err = client.Search(context.Background(), &searchpb.SearchRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an empty query, got %v", err)
+	}
+}
+
+func TestGRPCSearchUnavailable(t *testing.T) {
+	client := dialTestGRPC(t, &AppState{})
+
+	if _, err := client.Search(context.Background(), &searchpb.SearchRequest{Query: "form"}); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable without Manticore, got %v", err)
+	}
+	if _, err := client.Reindex(context.Background(), &searchpb.ReindexRequest{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable without Manticore, got %v", err)
+	}
+}
+
+func TestGRPCSearchStream(t *testing.T) {
+	client := dialTestGRPC(t, &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}})
+
+	stream, err := client.SearchStream(context.Background(), &searchpb.SearchRequest{Query: "form", Mode: "hybrid"})
+	if err != nil {
+		t.Fatalf("SearchStream failed: %v", err)
+	}
+
+	var stages []string
+	for {
+		stage, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		stages = append(stages, stage.GetStage())
+		if stage.GetFinal() != (stage.GetStage() == "final") {
+			t.Errorf("Unexpected final flag on %s stage", stage.GetStage())
+		}
+	}
+	if len(stages) != 2 || stages[0] != "fulltext" || stages[1] != "final" {
+		t.Errorf("Expected a full-text preview and the final ranking, got %v", stages)
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	client := dialTestGRPC(t, &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}})
+
+	response, err := client.Status(context.Background(), &searchpb.StatusRequest{})
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if response.GetStatus() != "ok" || !response.GetManticoreHealthy() {
+		t.Errorf("Unexpected status: %+v", response)
+	}
+}
+
+func TestSearchErrorStatus(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected codes.Code
+	}{
+		{context.Canceled, codes.Canceled},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{errors.New("dial tcp 127.0.0.1:9308: connection refused"), codes.Unavailable},
+		{errors.New("circuit breaker is OPEN: too many failures"), codes.Unavailable},
+		{errors.New("unknown local table 'documents'"), codes.FailedPrecondition},
+		{(&manticore.Capabilities{Major: 6}).RequireAISearch(), codes.Unimplemented},
+		{errors.New("something odd"), codes.Internal},
+	}
+	for _, tt := range tests {
+		if code := status.Code(searchErrorStatus(tt.err)); code != tt.expected {
+			t.Errorf("Expected %v for %q, got %v", tt.expected, tt.err, code)
+		}
+	}
+}
@@ -2,29 +2,135 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ad/manticoresearch-go/internal/analytics"
+	"github.com/ad/manticoresearch-go/internal/audit"
+	"github.com/ad/manticoresearch-go/internal/backup"
+	"github.com/ad/manticoresearch-go/internal/blocklist"
+	"github.com/ad/manticoresearch-go/internal/boost"
+	"github.com/ad/manticoresearch-go/internal/calibration"
+	"github.com/ad/manticoresearch-go/internal/curation"
+	"github.com/ad/manticoresearch-go/internal/deadletter"
+	"github.com/ad/manticoresearch-go/internal/docstore"
 	"github.com/ad/manticoresearch-go/internal/document"
+	"github.com/ad/manticoresearch-go/internal/maintenance"
 	"github.com/ad/manticoresearch-go/internal/manticore"
 	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/querytemplate"
+	"github.com/ad/manticoresearch-go/internal/reconcile"
+	"github.com/ad/manticoresearch-go/internal/redact"
+	"github.com/ad/manticoresearch-go/internal/retention"
+	"github.com/ad/manticoresearch-go/internal/savedsearch"
 	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/internal/slo"
+	"github.com/ad/manticoresearch-go/internal/stopwords"
+	"github.com/ad/manticoresearch-go/internal/textnorm"
+	"github.com/ad/manticoresearch-go/internal/usage"
+	"github.com/ad/manticoresearch-go/internal/validation"
 	"github.com/ad/manticoresearch-go/internal/vectorizer"
+	"github.com/ad/manticoresearch-go/internal/webhook"
 	"github.com/ad/manticoresearch-go/pkg/api"
 )
 
 // AppState holds the application state including loaded documents and services
 type AppState struct {
-	Documents  []*models.Document
-	Vectorizer *vectorizer.TFIDFVectorizer
-	Manticore  manticore.ClientInterface // Client interface for both official and HTTP clients
-	Vectors    [][]float64
-	AIConfig   *models.AISearchConfig
+	// Documents keeps a bounded set of the indexed documents in memory; nil loads each from Manticore
+	Documents   *docstore.Store
+	Vectorizer  *vectorizer.TFIDFVectorizer
+	Manticore   manticore.ClientInterface    // Client interface for both official and HTTP clients
+	AIConfig    *models.AISearchConfig       // AI configuration at startup; read the current one with CurrentAIConfig
+	Audit       *audit.Log                   // Records admin operations; nil disables auditing
+	ResultCache *search.ResultCache          // Recent results served while the circuit breaker is open; nil disables it
+	Connection  *manticore.ConnectionManager // Background connection and startup state; nil when not managed
+	LastReindex time.Time                    // When documents were last indexed from the data directory
+	// Consistency is the outcome of the startup consistency check; nil until it ran
+	Consistency *reconcile.Report
+	// Maintenance runs the scheduled maintenance tasks; nil when none are scheduled
+	Maintenance *maintenance.Scheduler
+	// SavedSearches holds the searches run by the alert scheduler; nil disables saved searches
+	SavedSearches *savedsearch.Store
+	// RescoreWindow is the default number of candidates each hybrid search leg contributes before
+	// fusion; 0 takes twice the results up to the requested page
+	RescoreWindow int
+	// Relaxation lists the strategies retried when a basic or full-text search matches nothing;
+	// empty disables relaxation
+	Relaxation []string
+	// SpellTolerance makes basic searches also match substrings and misspellings of indexed words
+	// by default; the tolerant parameter overrides it per request
+	SpellTolerance bool
+	// DedupThreshold is the similarity of stored vectors above which dedup=true collapses results;
+	// 0 uses search.DefaultDedupThreshold
+	DedupThreshold float64
+	// Stopwords are removed from basic, full-text and vector queries; nil keeps every word
+	Stopwords *stopwords.List
+	// Boosts multiply the scores of documents matching attribute values in every search mode; nil boosts nothing
+	Boosts boost.Rules
+	// Features multiply the scores of documents by how they match the query, such as an exact title; nil weights nothing
+	Features boost.Features
+	// Blocklist lists the documents never returned by searches, such as internal drafts; nil blocks nothing
+	Blocklist *blocklist.Blocklist
+	// Calibration maps the scores of each search mode to probabilities of relevance; nil keeps raw scores
+	Calibration calibration.Calibration
+	// Normalizer rewrites queries and the text of indexed documents into the same form; nil keeps them as written
+	Normalizer *textnorm.Normalizer
+	// Redactor masks personal data in documents before they are indexed or embedded; nil indexes them as written
+	Redactor *redact.Redactor
+	// DocumentRules are checked before documents are indexed; nil accepts every document
+	DocumentRules *document.Rules
+	// DeadLetters keeps the documents rejected by DocumentRules; nil only logs them
+	DeadLetters *deadletter.Store
+	// Retention expires indexed documents by age and count; nil keeps every document
+	Retention *retention.Policy
+	// Templates holds the named queries run by the search endpoint's template parameter; nil disables them
+	Templates *querytemplate.Store
+	// Curations pin and hide documents in the results of matching queries; nil disables curation
+	Curations *curation.Store
+	// Webhooks delivers events such as reindex completion to external systems; nil disables them
+	Webhooks *webhook.Dispatcher
+	// Continuations holds the final rankings of progressive searches; nil disables progressive responses
+	Continuations *search.Continuations
+	// AdminToken is the bearer token required by admin endpoints; empty disables them
+	AdminToken string
+	// APIKeys are the secret keys of the API by name; when empty, API keys are not required
+	APIKeys map[string]string
+	// CrossTenantKeys names the API keys allowed to select any tenant; other keys not bound to a
+	// tenant only use the default one
+	CrossTenantKeys map[string]bool
+	// Usage accounts searches, indexed documents and embedding calls per API key; nil disables quotas
+	Usage *usage.Tracker
+	// Analytics counts the queries searched with results for popular suggestions; nil records nothing
+	Analytics *analytics.Store
+	// SLO tracks the latencies of searches by mode against the latency objectives; nil tracks nothing
+	SLO *slo.Tracker
+	// Tenant names the tenant whose tables and documents this state serves; empty for the default one
+	Tenant string
+	// Tenants holds the state of every tenant by name; nil disables multi-tenancy
+	Tenants map[string]*AppState
+	// DataDir is the directory documents are indexed from; empty uses DATA_DIR
+	DataDir string
+	// BackupDir is the directory of backup artifacts; empty uses BACKUP_DIR
+	BackupDir string
+	// aiConfig replaces AIConfig once re-embedding or a restore switches models, see SetAIConfig
+	aiConfig atomic.Pointer[models.AISearchConfig]
+	// indexGeneration counts index changes for search ETags, see IndexChanged
+	indexGeneration atomic.Uint64
+	// lastOptimize records when each table was last optimized through the admin endpoint
+	lastOptimize   map[string]time.Time
+	lastOptimizeMu sync.Mutex
+	// reembed is the progress of the last re-embedding, see ReembedHandler
+	reembed   api.ReembedStatus
+	reembedMu sync.Mutex
 }
 
 // NewAppState creates a new application state
@@ -43,10 +149,9 @@ func NewAppState() *AppState {
 // NewAppStateWithConfig creates a new application state with the provided AI configuration
 func NewAppStateWithConfig(aiConfig *models.AISearchConfig) *AppState {
 	return &AppState{
-		Documents:  make([]*models.Document, 0),
+		Documents:  docstore.New(docstore.DefaultMaxDocuments, docstore.DefaultMaxSize),
 		Vectorizer: nil,
 		Manticore:  nil,
-		Vectors:    make([][]float64, 0),
 		AIConfig:   aiConfig,
 	}
 }
@@ -56,8 +161,11 @@ func (app *AppState) SearchHandler(w http.ResponseWriter, r *http.Request) {
 	// Set CORS headers
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
 	w.Header().Set("Content-Type", "application/json")
+	// Results may also be sent as MessagePack or protobuf, see sendSearchResponse, and come from
+	// the tenant of the request
+	w.Header().Set("Vary", searchVary)
 
 	// Handle preflight requests
 	if r.Method == "OPTIONS" {
@@ -71,11 +179,19 @@ func (app *AppState) SearchHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse query parameters
+	// Replace the search parameters with the ones of a named query template
+	templated := r.URL.Query().Get("template") != ""
+	if templated && !app.applyTemplate(w, r) {
+		return
+	}
+
+	// Parse query parameters, collecting every invalid one
+	var errs validation.Errors
 	query := strings.TrimSpace(r.URL.Query().Get("query"))
 	if query == "" {
-		app.sendErrorResponse(w, http.StatusBadRequest, "Query parameter is required")
-		return
+		errs.Add("query", validation.Required("query"))
+	} else {
+		errs.Add("query", search.ValidateQuery(query))
 	}
 
 	// Parse search mode
@@ -85,27 +201,80 @@ func (app *AppState) SearchHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	mode, err := search.ValidateSearchMode(modeStr)
-	if err != nil {
-		app.sendErrorResponse(w, http.StatusBadRequest, err.Error())
-		return
-	}
+	errs.Add("mode", err)
 
 	// Parse pagination parameters
-	page, err := parseIntParam(r.URL.Query().Get("page"), 1)
-	if err != nil || page < 1 {
-		app.sendErrorResponse(w, http.StatusBadRequest, "Invalid page parameter")
-		return
-	}
+	page, err := parseIntRangeParam(r.URL.Query().Get("page"), "page", 1, 1, search.MaxPage)
+	errs.Add("page", err)
+
+	limit, err := parseIntRangeParam(r.URL.Query().Get("limit"), "limit", 10, 1, 100)
+	errs.Add("limit", err)
+
+	// Parse result field selection
+	fields, err := search.ParseFields(r.URL.Query().Get("fields"))
+	errs.Add("fields", err)
+
+	// Parse document statuses, active only unless others are requested
+	statuses, err := search.ParseStatuses(r.URL.Query().Get("status"))
+	errs.Add("status", err)
+
+	// Parse ordering and the since filter for recently updated views
+	recency, err := search.ParseRecency(r.URL.Query().Get("sort"), r.URL.Query().Get("since"))
+	errs.Add("sort", err)
+
+	// Parse tag filters
+	tags, err := search.ParseTags(r.URL.Query().Get("tags"), r.URL.Query().Get("tags_mode"))
+	errs.Add("tags_mode", err)
+
+	// Parse the documents to restrict results to and the ones to hide
+	var ids manticore.IDFilter
+	ids.IDs, err = search.ParseIDs("ids", r.URL.Query().Get("ids"))
+	errs.Add("ids", err)
+	ids.Exclude, err = search.ParseIDs("exclude_ids", r.URL.Query().Get("exclude_ids"))
+	errs.Add("exclude_ids", err)
+
+	// Parse the pattern a field of the results must match
+	regex, err := search.ParseRegex(r.URL.Query().Get("regex"))
+	errs.Add("regex", err)
+
+	// Parse the numeric attributes to count in histogram facets
+	histograms, err := search.ParseHistograms(r.URL.Query().Get("histogram"))
+	errs.Add("histogram", err)
+
+	// Parse the number of candidates each hybrid leg contributes before fusion
+	rescoreWindow, err := search.ParseRescoreWindow(r.URL.Query().Get("rescore_window"), app.RescoreWindow)
+	errs.Add("rescore_window", err)
+
+	// Parse the KNN tuning of AI searches, overriding the configured one
+	knn, err := search.ParseKNN(r.URL.Query().Get("knn_k"), r.URL.Query().Get("knn_ef"))
+	errs.Add("knn", err)
+
+	// Parse the language whose stopwords are used instead of the detected one
+	language, err := search.ParseLanguage(r.URL.Query().Get("language"))
+	errs.Add("language", err)
+
+	// Parse whether basic searches also match substrings and misspellings
+	tolerant, err := search.ParseSpellTolerance(r.URL.Query().Get("tolerant"), app.SpellTolerance)
+	errs.Add("tolerant", err)
 
-	limit, err := parseIntParam(r.URL.Query().Get("limit"), 10)
-	if err != nil || limit < 1 || limit > 100 {
-		app.sendErrorResponse(w, http.StatusBadRequest, "Invalid limit parameter (must be between 1 and 100)")
+	// Parse whether near-duplicate results are collapsed
+	dedup, err := search.ParseDedup(r.URL.Query().Get("dedup"))
+	errs.Add("dedup", err)
+
+	// Debug responses explain how the query was processed
+	debug := r.URL.Query().Get("debug") == "true"
+
+	if len(errs) > 0 {
+		app.sendValidationError(w, r, errs)
 		return
 	}
 
+	// Progressive responses return full-text results first for hybrid and AI searches
+	progressive := r.URL.Query().Get("progressive") == "true" && app.Continuations != nil
+
 	// Handle AI search mode with graceful degradation
 	originalMode := mode
-	if mode == models.SearchModeAI {
+	if mode.UsesAI() {
 		if err := app.validateAISearchAvailability(); err != nil {
 			log.Printf("AI search not available: %v, degrading to hybrid search", err)
 			// Log AI search fallback for monitoring
@@ -121,12 +290,76 @@ func (app *AppState) SearchHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Perform search using official client
 	var result *models.SearchResponse
+	var aiFallback bool
 	searchStartTime := time.Now()
+	cacheKey := search.ResultCacheKey(query, originalMode, page, limit)
+	if len(fields) > 0 {
+		cacheKey += "|fields=" + strings.Join(fields, ",")
+	}
+	if len(statuses) > 0 {
+		cacheKey += "|status=" + joinStatuses(statuses)
+	}
+	if !recency.IsZero() {
+		cacheKey += fmt.Sprintf("|sort=%s|since=%d", recency.Sort, recency.Since)
+	}
+	if len(tags.Tags) > 0 {
+		cacheKey += fmt.Sprintf("|tags=%s|tags_all=%t", strings.Join(tags.Tags, ","), tags.MatchAll)
+	}
+	if !ids.IsZero() {
+		cacheKey += fmt.Sprintf("|ids=%v|exclude_ids=%v", ids.IDs, ids.Exclude)
+	}
+	if !regex.IsZero() {
+		cacheKey += fmt.Sprintf("|regex=%s:%q", regex.Field, regex.Pattern)
+	}
+	if len(histograms) > 0 {
+		cacheKey += fmt.Sprintf("|histogram=%v", histograms)
+	}
+	if rescoreWindow > 0 {
+		cacheKey += fmt.Sprintf("|rescore_window=%d", rescoreWindow)
+	}
+	if !knn.IsZero() {
+		cacheKey += fmt.Sprintf("|knn=%d:%d", knn.K, knn.Ef)
+	}
+	if language != "" {
+		cacheKey += "|language=" + language
+	}
+	if tolerant {
+		cacheKey += "|tolerant"
+	}
+	if dedup {
+		cacheKey += "|dedup"
+	}
+	if debug {
+		cacheKey += "|debug"
+	}
+
+	// Results only change with the index, so polling clients can revalidate them with If-None-Match
+	generation := app.IndexGeneration()
+	etag := searchETag(cacheKey+"|mode="+string(mode), negotiateSearchFormat(r), app.Tenant, generation)
 
 	if app.Manticore != nil {
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		// Count the search against the caller's daily quota
+		if err := app.Usage.Reserve(contextAPIKey(r.Context()), searchUses(mode, query)); err != nil {
+			app.sendQuotaExceededResponse(w, err)
+			return
+		}
+
 		// Use search engine with official client
-		searchEngine := search.NewSearchEngine(app.Manticore, app.Vectorizer, app.AIConfig)
-		result, err = searchEngine.Search(query, mode, page, limit)
+		searchEngine := search.NewSearchEngine(app.Manticore, app.Vectorizer, app.CurrentAIConfig()).WithFields(fields).WithStatuses(statuses).WithRecency(recency).WithTags(tags).WithIDs(ids).WithRegex(regex).WithHistogramFacets(histograms).WithRescoreWindow(rescoreWindow).WithKNN(knn).WithRelaxation(app.Relaxation).WithSpellTolerance(tolerant).WithStopwords(app.Stopwords).WithLanguage(language).WithDebug(debug).WithNormalizer(app.Normalizer).WithBoosts(app.Boosts).WithFeatures(app.Features).WithBlocklist(app.Blocklist).WithCalibration(app.Calibration).WithCurations(app.Curations)
+		if dedup {
+			searchEngine = searchEngine.WithDeduplication(app.dedupThreshold())
+		}
+		if progressive && search.IsProgressiveMode(mode) {
+			app.sendProgressiveSearch(w, r, searchEngine, query, mode, page, limit, cacheKey)
+			return
+		}
+		result, err = searchEngine.SearchContext(r.Context(), query, mode, page, limit)
 		searchDuration := time.Since(searchStartTime)
 
 		if err != nil {
@@ -146,12 +379,17 @@ func (app *AppState) SearchHandler(w http.ResponseWriter, r *http.Request) {
 				})
 
 				fallbackStartTime := time.Now()
-				fallbackResult, fallbackErr := searchEngine.Search(query, models.SearchModeVector, page, limit)
+				fallbackResult, fallbackErr := searchEngine.SearchContext(r.Context(), query, models.SearchModeVector, page, limit)
 				fallbackDuration := time.Since(fallbackStartTime)
 
 				if fallbackErr != nil {
 					log.Printf("Fallback search also failed: %v", fallbackErr)
 
+					if manticore.IsCircuitOpenError(fallbackErr) {
+						app.sendCircuitOpenResponse(w, r, cacheKey)
+						return
+					}
+
 					// Log complete failure for monitoring
 					app.logAISearchOperation("AI_SEARCH_COMPLETE_FAILURE", searchDuration+fallbackDuration, false, map[string]interface{}{
 						"query":          query,
@@ -175,8 +413,11 @@ func (app *AppState) SearchHandler(w http.ResponseWriter, r *http.Request) {
 
 				// Add fallback metadata to response
 				result = app.addAISearchFallbackMetadata(fallbackResult, err.Error())
+				aiFallback = true
+				// Fallback results are not what the ETag promises
+				etag = ""
 			} else {
-				app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Search failed: %v", err))
+				app.sendSearchError(w, r, err, mode, cacheKey)
 				return
 			}
 		} else {
@@ -194,7 +435,7 @@ func (app *AppState) SearchHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		// No Manticore client available
-		if originalMode == models.SearchModeAI {
+		if originalMode.UsesAI() {
 			app.logAISearchOperation("AI_SEARCH_UNAVAILABLE", time.Duration(0), false, map[string]interface{}{
 				"query":  query,
 				"reason": "Manticore Search service is not available",
@@ -206,13 +447,30 @@ func (app *AppState) SearchHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Add AI search metadata to response if applicable
-	if originalMode == models.SearchModeAI {
+	// Track the latency of the requested mode, fallbacks included
+	app.SLO.Record(string(originalMode), time.Since(searchStartTime))
+
+	// Add AI search metadata to response if applicable; fallback results keep their fallback mode
+	if originalMode == models.SearchModeAI && !aiFallback {
 		result = app.addAISearchMetadata(result, originalMode != mode)
 	}
 
+	// Remember the response so it can be served if the backend becomes unavailable; the generation
+	// tells clients of stale responses which index state they reflect
+	result.IndexGeneration = generation
+	app.ResultCache.Put(cacheKey, result)
+
+	// Count queries that found results for popular suggestions; template searches are counted by
+	// their template, not by the values filled into it
+	if result.Total > 0 && !templated {
+		app.Analytics.Record(query)
+	}
+
 	// Send successful response
-	app.sendSuccessResponse(w, result)
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	app.sendSearchResponse(w, r, result)
 }
 
 // StatusHandler handles GET /api/status requests
@@ -220,7 +478,7 @@ func (app *AppState) StatusHandler(w http.ResponseWriter, r *http.Request) {
 	// Set CORS headers
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
 	w.Header().Set("Content-Type", "application/json")
 
 	// Handle preflight requests
@@ -235,23 +493,28 @@ func (app *AppState) StatusHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check Manticore health
-	manticoreHealthy := false
+	app.sendSuccessResponse(w, app.status())
+}
+
+// status reports the health of the service and of Manticore
+func (app *AppState) status() api.StatusResponse {
+	// Check Manticore health, keeping server availability and schema presence apart
+	health := manticore.HealthReport{State: manticore.HealthStateDown}
 	if app.Manticore != nil && app.Manticore.IsConnected() {
-		if err := app.Manticore.HealthCheck(); err == nil {
-			manticoreHealthy = true
-		}
+		health = app.Manticore.CheckHealth()
 	}
+	manticoreHealthy := health.ServerUp
 
 	// Check AI search health with detailed logging
 	healthCheckStartTime := time.Now()
 	aiSearchHealthy := app.checkAISearchHealth()
 	healthCheckDuration := time.Since(healthCheckStartTime)
 
-	aiSearchEnabled := app.AIConfig != nil && app.AIConfig.Enabled
+	aiConfig := app.CurrentAIConfig()
+	aiSearchEnabled := aiConfig != nil && aiConfig.Enabled
 	aiModel := ""
-	if app.AIConfig != nil {
-		aiModel = app.AIConfig.Model
+	if aiConfig != nil {
+		aiModel = aiConfig.Model
 	}
 
 	// Log AI search health check results for monitoring
@@ -261,19 +524,106 @@ func (app *AppState) StatusHandler(w http.ResponseWriter, r *http.Request) {
 		"manticore_healthy": manticoreHealthy,
 	})
 
-	// Prepare status response
-	status := api.StatusResponse{
+	manticoreVersion := ""
+	var transitions []api.CircuitBreakerTransition
+	if app.Manticore != nil {
+		if caps := app.Manticore.GetCapabilities(); caps != nil {
+			manticoreVersion = caps.Version
+		}
+		transitions = convertTransitions(app.Manticore.GetCircuitBreakerTransitions())
+	}
+
+	var tables []api.TableStatus
+	if manticoreHealthy {
+		tables = app.collectTableStatus()
+	}
+
+	vocabularySize := 0
+	if app.Vectorizer != nil {
+		vocabularySize = app.Vectorizer.VocabularySize()
+	}
+
+	var lastReindex *time.Time
+	if !app.LastReindex.IsZero() {
+		lastReindex = &app.LastReindex
+	}
+
+	connectionState := ""
+	if app.Connection != nil {
+		connectionState = string(app.Connection.Status().State)
+	}
+
+	return api.StatusResponse{
 		Status:           "ok",
 		ManticoreHealthy: manticoreHealthy,
-		DocumentsLoaded:  len(app.Documents),
+		ManticoreState:   string(health.State),
+		ManticoreVersion: manticoreVersion,
+		DocumentsLoaded:  app.Documents.Count(),
 		VectorizerReady:  app.Vectorizer != nil,
 		AISearchEnabled:  aiSearchEnabled,
 		AIModel:          aiModel,
 		AISearchHealthy:  aiSearchHealthy,
+		ConnectionState:  connectionState,
+		SchemaPresent:    health.SchemaPresent,
+		MissingTables:    health.MissingTables,
+		Tables:           tables,
+		LastReindex:      lastReindex,
+		VocabularySize:   vocabularySize,
+		IndexGeneration:  app.IndexGeneration(),
+
+		CircuitBreakerTransitions: transitions,
+		LiveCircuitBreakers:       manticore.LiveCircuitBreakers(),
+		Consistency:               convertConsistencyReport(app.Consistency),
+		Maintenance:               convertMaintenance(app.Maintenance.Status()),
+		SearchLatency:             convertSearchLatency(app.SLO.Status()),
 	}
+}
 
-	// Send response
-	app.sendSuccessResponse(w, status)
+// convertConsistencyReport converts a consistency check outcome to its API form
+func convertConsistencyReport(report *reconcile.Report) *api.ConsistencyReport {
+	if report == nil {
+		return nil
+	}
+	return &api.ConsistencyReport{
+		CheckedAt:        report.CheckedAt,
+		Consistent:       report.Consistent(),
+		SourceDocuments:  report.SourceDocuments,
+		IndexedDocuments: report.IndexedDocuments,
+		IndexedVectors:   report.IndexedVectors,
+		Missing:          convertConsistencyIDs(report.Missing),
+		MissingVectors:   convertConsistencyIDs(report.MissingVectors),
+		Orphaned:         convertConsistencyIDs(report.Orphaned),
+		Extra:            convertConsistencyIDs(report.Extra),
+		Repaired:         report.Repaired,
+		RepairError:      report.RepairError,
+	}
+}
+
+func convertConsistencyIDs(ids reconcile.IDs) api.ConsistencyIDs {
+	return api.ConsistencyIDs{Count: ids.Count, IDs: ids.IDs}
+}
+
+// statusTables lists the Manticore tables reported by the status endpoint
+var statusTables = []string{"documents", "documents_vector"}
+
+// collectTableStatus queries document counts and storage size for each status table
+func (app *AppState) collectTableStatus() []api.TableStatus {
+	tables := make([]api.TableStatus, 0, len(statusTables))
+	for _, table := range statusTables {
+		stats, err := app.Manticore.GetIndexStats(table)
+		if err != nil {
+			log.Printf("Warning: Failed to get index stats for %s: %v", table, err)
+			continue
+		}
+		tables = append(tables, api.TableStatus{
+			Name:      stats.Table,
+			Exists:    stats.Exists,
+			Documents: stats.IndexedDocuments,
+			DiskBytes: stats.DiskBytes,
+			RAMBytes:  stats.RAMBytes,
+		})
+	}
+	return tables
 }
 
 // ReindexHandler handles POST /api/reindex requests
@@ -281,7 +631,7 @@ func (app *AppState) ReindexHandler(w http.ResponseWriter, r *http.Request) {
 	// Set CORS headers
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
 	w.Header().Set("Content-Type", "application/json")
 
 	// Handle preflight requests
@@ -306,54 +656,133 @@ func (app *AppState) ReindexHandler(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
 	log.Println("Manual reindexing requested")
 
+	auditParams := map[string]interface{}{"data_dir": app.DataDirectory(), "schema_reset": true}
+	response, err := app.reindex(contextAPIKey(r.Context()), auditParams, startTime)
+	app.recordAudit(r, "reindex", auditParams, err, startTime)
+	app.PublishReindex("api", auditParams, err, startTime)
+	if err != nil {
+		var quotaErr *usage.QuotaError
+		if errors.As(err, &quotaErr) {
+			app.sendQuotaExceededResponse(w, quotaErr)
+			return
+		}
+		var failure *reindexError
+		if errors.As(err, &failure) {
+			app.sendErrorResponse(w, failure.status, failure.message)
+			return
+		}
+		app.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	app.sendSuccessResponse(w, response)
+}
+
+// reindexError is a failed reindex with the HTTP status and message reported to the caller
+type reindexError struct {
+	status  int
+	message string
+	err     error
+}
+
+func (e *reindexError) Error() string {
+	return e.err.Error()
+}
+
+// NormalizeDocuments composes the title and content of documents about to be indexed with the
+// normalizer's Unicode form and masks the personal data the redactor detects in them. They keep
+// their case and accents, which Manticore folds itself.
+func (app *AppState) NormalizeDocuments(documents []*models.Document) {
+	redacted, redactedDocuments := 0, 0
+	for _, doc := range documents {
+		var titleCount, contentCount int
+		doc.Title, titleCount = app.Redactor.Redact(app.Normalizer.Compose(doc.Title))
+		doc.Content, contentCount = app.Redactor.Redact(app.Normalizer.Compose(doc.Content))
+		if titleCount+contentCount > 0 {
+			redacted += titleCount + contentCount
+			redactedDocuments++
+		}
+	}
+	if redacted > 0 {
+		log.Printf("Redacted %d matches of personal data in %d of %d documents", redacted, redactedDocuments, len(documents))
+	}
+}
+
+// reindex reloads documents from the data directory, recreates the schema and indexes them,
+// adding the number of indexed documents to auditParams. The documents are accounted to key and
+// nothing is changed when that exceeds its quota.
+func (app *AppState) reindex(key string, auditParams map[string]interface{}, startTime time.Time) (api.ReindexResponse, error) {
 	// Load documents from data directory
-	dataDir := getDataDirectory()
+	dataDir := app.DataDirectory()
 	documents, err := document.ScanDataDirectory(dataDir)
 	if err != nil {
 		log.Printf("Failed to scan data directory: %v", err)
-		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to load documents: %v", err))
-		return
+		return api.ReindexResponse{}, &reindexError{http.StatusInternalServerError, fmt.Sprintf("Failed to load documents: %v", err), err}
 	}
 
 	if len(documents) == 0 {
-		app.sendErrorResponse(w, http.StatusBadRequest, "No documents found in data directory")
-		return
+		return api.ReindexResponse{}, &reindexError{http.StatusBadRequest, "No documents found in data directory", fmt.Errorf("no documents found in data directory")}
+	}
+
+	// Documents failing validation go to the dead-letter store instead of the index
+	app.NormalizeDocuments(documents)
+	documents, rejected := app.ValidateDocuments("reindex", documents)
+	auditParams["rejected"] = len(rejected)
+	if len(documents) == 0 {
+		return api.ReindexResponse{}, &reindexError{http.StatusBadRequest, "No valid documents found in data directory", fmt.Errorf("all %d documents failed validation", len(rejected))}
+	}
+	documents, expired := app.RetainDocuments(documents)
+	auditParams["expired"] = expired
+	if len(documents) == 0 {
+		return api.ReindexResponse{}, &reindexError{http.StatusBadRequest, "No documents within the retention policy found in data directory", fmt.Errorf("all %d documents expired", expired)}
+	}
+
+	if err := app.Usage.Reserve(key, app.indexingUses(len(documents))); err != nil {
+		return api.ReindexResponse{}, err
 	}
 
+	truncated := app.EmbeddingTruncations(documents)
+	auditParams["embedding_truncated"] = len(truncated)
+
 	// Create and train vectorizer
 	vec := vectorizer.NewTFIDFVectorizer()
+	vec.SetNormalizer(app.Normalizer)
 	vectors := vec.FitTransform(documents)
 
 	// Reset and recreate database schema with AI configuration from app state
-	if err := app.Manticore.CreateSchema(app.AIConfig); err != nil {
+	if err := app.Manticore.CreateSchema(app.CurrentAIConfig()); err != nil {
 		log.Printf("Failed to create schema: %v", err)
-		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create database schema: %v", err))
-		return
+		return api.ReindexResponse{}, &reindexError{http.StatusInternalServerError, fmt.Sprintf("Failed to create database schema: %v", err), err}
 	}
 
-	// Index documents
-	if err := app.Manticore.IndexDocuments(documents, vectors); err != nil {
+	// Index documents; the index changed even if indexing fails part way
+	err = app.Manticore.IndexDocuments(documents, vectors)
+	app.IndexChanged()
+	if err != nil {
 		log.Printf("Failed to index documents: %v", err)
-		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to index documents: %v", err))
-		return
+		return api.ReindexResponse{}, &reindexError{http.StatusInternalServerError, fmt.Sprintf("Failed to index documents: %v", err), err}
 	}
 
 	// Update application state
-	app.Documents = documents
+	app.Documents.Replace(documents, vectors)
 	app.Vectorizer = vec
-	app.Vectors = vectors
+	app.LastReindex = time.Now()
 
 	indexingDuration := time.Since(startTime)
 	log.Printf("Manual reindexing completed: %d documents indexed in %v", len(documents), indexingDuration)
+	auditParams["documents"] = len(documents)
 
-	// Prepare response
-	response := api.ReindexResponse{
+	return api.ReindexResponse{
 		Message:        "Reindexing completed successfully",
 		DocumentsCount: len(documents),
 		IndexingTime:   indexingDuration.String(),
-	}
+		RejectedCount:  len(rejected),
+		Rejected:       convertRejections(rejected),
+		ExpiredCount:   expired,
 
-	app.sendSuccessResponse(w, response)
+		EmbeddingTruncatedCount: len(truncated),
+		EmbeddingTruncated:      truncated[:min(len(truncated), maxListedTruncations)],
+	}, nil
 }
 
 // sendSuccessResponse sends a successful JSON response
@@ -382,6 +811,43 @@ func (app *AppState) sendErrorResponse(w http.ResponseWriter, statusCode int, me
 	}
 }
 
+// sendValidationError sends a 400 response listing the invalid parameters in err, with messages in
+// the language negotiated from the Accept-Language header
+func (app *AppState) sendValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	var errs validation.Errors
+	errs.Add("", err)
+	language := validation.NegotiateLanguage(r.Header.Get("Accept-Language"))
+
+	data := api.ValidationErrorData{ErrorType: api.ErrorTypeValidation, Errors: make([]api.FieldError, 0, len(errs))}
+	messages := make([]string, 0, len(errs))
+	for _, fieldErr := range errs {
+		message := fieldErr.Message(language)
+		messages = append(messages, message)
+		data.Errors = append(data.Errors, api.FieldError{
+			Field:   fieldErr.Field,
+			Code:    fieldErr.Code,
+			Message: message,
+			Value:   fieldErr.Value,
+			Min:     fieldErr.Min,
+			Max:     fieldErr.Max,
+			Allowed: fieldErr.Allowed,
+		})
+	}
+
+	response := api.APIResponse{
+		Success: false,
+		Error:   validation.Summary(language) + ": " + strings.Join(messages, "; "),
+		Data:    data,
+	}
+
+	w.Header().Set("Content-Language", language)
+	w.Header().Add("Vary", "Accept-Language")
+	w.WriteHeader(http.StatusBadRequest)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Failed to encode validation error response: %v", err)
+	}
+}
+
 // parseIntParam parses an integer parameter with a default value
 func parseIntParam(param string, defaultValue int) (int, error) {
 	if param == "" {
@@ -390,8 +856,21 @@ func parseIntParam(param string, defaultValue int) (int, error) {
 	return strconv.Atoi(param)
 }
 
-// getDataDirectory returns the data directory path from environment or default
-func getDataDirectory() string {
+// parseIntRangeParam parses an integer parameter with a default value, failing with an out_of_range
+// validation error when it isn't a number between min and max
+func parseIntRangeParam(param, field string, defaultValue, min, max int) (int, error) {
+	value, err := parseIntParam(param, defaultValue)
+	if err != nil || value < min || value > max {
+		return 0, validation.OutOfRange(field, param, min, max)
+	}
+	return value, nil
+}
+
+// DataDirectory returns the directory documents are indexed from: DataDir, or DATA_DIR by default
+func (app *AppState) DataDirectory() string {
+	if app.DataDir != "" {
+		return app.DataDir
+	}
 	dataDir := os.Getenv("DATA_DIR")
 	if dataDir == "" {
 		dataDir = "./data"
@@ -399,15 +878,24 @@ func getDataDirectory() string {
 	return dataDir
 }
 
+// backupDirectory returns the directory of backup artifacts: BackupDir, or BACKUP_DIR by default
+func (app *AppState) backupDirectory() string {
+	if app.BackupDir != "" {
+		return app.BackupDir
+	}
+	return backup.DirectoryFromEnvironment()
+}
+
 // validateAISearchAvailability validates if AI search is available and properly configured
 func (app *AppState) validateAISearchAvailability() error {
 	// Check if AI configuration is available
-	if app.AIConfig == nil {
+	aiConfig := app.CurrentAIConfig()
+	if aiConfig == nil {
 		return fmt.Errorf("AI search configuration is not loaded")
 	}
 
 	// Check if AI search is enabled
-	if !app.AIConfig.Enabled {
+	if !aiConfig.Enabled {
 		return fmt.Errorf("AI search is disabled in configuration")
 	}
 
@@ -421,7 +909,8 @@ func (app *AppState) validateAISearchAvailability() error {
 		return fmt.Errorf("Manticore search client is not connected")
 	}
 
-	return nil
+	// Check if the server supports KNN search and Auto Embeddings
+	return app.Manticore.GetCapabilities().RequireAISearch()
 }
 
 // addAISearchMetadata adds AI search specific metadata to the search response
@@ -438,11 +927,11 @@ func (app *AppState) addAISearchMetadata(response *models.SearchResponse, fallba
 	}
 
 	// Log AI search metadata for monitoring
-	if app.AIConfig != nil {
+	if aiConfig := app.CurrentAIConfig(); aiConfig != nil {
 		if fallbackUsed {
-			log.Printf("AI search degraded to hybrid mode, using model: %s", app.AIConfig.Model)
+			log.Printf("AI search degraded to hybrid mode, using model: %s", aiConfig.Model)
 		} else {
-			log.Printf("AI search completed successfully using model: %s", app.AIConfig.Model)
+			log.Printf("AI search completed successfully using model: %s", aiConfig.Model)
 		}
 	}
 
@@ -465,18 +954,57 @@ func (app *AppState) addAISearchFallbackMetadata(response *models.SearchResponse
 	return response
 }
 
+// sendCircuitOpenResponse serves a cached response for the query if one is available,
+// otherwise a 503 with Retry-After derived from the circuit breaker recovery timeout
+func (app *AppState) sendCircuitOpenResponse(w http.ResponseWriter, r *http.Request, cacheKey string) {
+	if cached, age, ok := app.ResultCache.Get(cacheKey); ok {
+		log.Printf("Circuit breaker open, serving cached results (age: %v)", age.Round(time.Second))
+		cached.Stale = true
+		w.Header().Set("X-Cache", "STALE")
+		w.Header().Set("Age", strconv.Itoa(int(age.Seconds())))
+		app.sendSearchResponse(w, r, cached)
+		return
+	}
+
+	retryAfter := 1
+	if app.Manticore != nil {
+		remaining := app.Manticore.GetResilienceStats().CircuitBreaker.RetryAfter
+		if seconds := int(math.Ceil(remaining.Seconds())); seconds > retryAfter {
+			retryAfter = seconds
+		}
+	}
+
+	log.Printf("Circuit breaker open, rejecting search (Retry-After: %ds)", retryAfter)
+
+	response := api.APIResponse{
+		Success: false,
+		Error:   fmt.Sprintf("Search backend is temporarily unavailable. Please retry in %d seconds.", retryAfter),
+		Data: api.CircuitOpenData{
+			ErrorType:         api.ErrorTypeCircuitOpen,
+			RetryAfterSeconds: retryAfter,
+		},
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.WriteHeader(http.StatusServiceUnavailable)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Failed to encode circuit open response: %v", err)
+	}
+}
+
 // sendAISearchUnavailableResponse sends a response when AI search is completely unavailable
 func (app *AppState) sendAISearchUnavailableResponse(w http.ResponseWriter, reason string) {
 	log.Printf("AI search unavailable: %s", reason)
 
+	aiConfig := app.CurrentAIConfig()
 	response := api.APIResponse{
 		Success: false,
 		Error:   fmt.Sprintf("AI search is currently unavailable: %s. Please try hybrid or fulltext search instead.", reason),
-		Data: map[string]interface{}{
-			"error_type":      "ai_search_unavailable",
-			"reason":          reason,
-			"suggested_modes": []string{"hybrid", "fulltext", "vector"},
-			"ai_enabled":      app.AIConfig != nil && app.AIConfig.Enabled,
+		Data: api.AISearchUnavailableData{
+			ErrorType:      api.ErrorTypeAISearchUnavailable,
+			Reason:         reason,
+			SuggestedModes: []string{"hybrid", "fulltext", "vector"},
+			AIEnabled:      aiConfig != nil && aiConfig.Enabled,
 		},
 	}
 
@@ -501,13 +1029,13 @@ func (app *AppState) sendAISearchErrorResponse(w http.ResponseWriter, aiError, f
 	response := api.APIResponse{
 		Success: false,
 		Error:   errorMsg,
-		Data: map[string]interface{}{
-			"error_type":      "ai_search_failure",
-			"error_category":  errorCategory,
-			"ai_error":        aiError.Error(),
-			"fallback_error":  fallbackError.Error(),
-			"suggested_modes": []string{"hybrid", "fulltext"},
-			"retry_suggested": errorCategory == "timeout" || errorCategory == "network",
+		Data: api.AISearchFailureData{
+			ErrorType:      api.ErrorTypeAISearchFailure,
+			ErrorCategory:  errorCategory,
+			AIError:        aiError.Error(),
+			FallbackError:  fallbackError.Error(),
+			SuggestedModes: []string{"hybrid", "fulltext"},
+			RetrySuggested: errorCategory == string(manticore.ErrorCategoryTimeout) || errorCategory == string(manticore.ErrorCategoryNetwork),
 		},
 	}
 
@@ -517,34 +1045,34 @@ func (app *AppState) sendAISearchErrorResponse(w http.ResponseWriter, aiError, f
 	}
 }
 
-// categorizeAISearchError categorizes AI search errors for better user feedback
-func (app *AppState) categorizeAISearchError(err error) string {
-	if err == nil {
-		return "unknown"
+// sendSearchFailureResponse sends a 500 response for a failed search, categorizing the error so
+// clients can tell a Manticore outage from a bad query
+func (app *AppState) sendSearchFailureResponse(w http.ResponseWriter, err error) {
+	category := manticore.CategorizeError(err)
+	response := api.APIResponse{
+		Success: false,
+		Error:   fmt.Sprintf("Search failed: %v", err),
+		Data: api.SearchFailureData{
+			ErrorType:      api.ErrorTypeSearchFailure,
+			ErrorCategory:  string(category),
+			RetrySuggested: category == manticore.ErrorCategoryTimeout || category == manticore.ErrorCategoryNetwork,
+		},
 	}
 
-	errorStr := strings.ToLower(err.Error())
-
-	if strings.Contains(errorStr, "timeout") || strings.Contains(errorStr, "deadline exceeded") {
-		return "timeout"
-	}
-	if strings.Contains(errorStr, "connection") || strings.Contains(errorStr, "network") {
-		return "network"
-	}
-	if strings.Contains(errorStr, "embedding") {
-		return "embedding"
+	// A server lacking the feature won't support it on retry either
+	statusCode := http.StatusInternalServerError
+	if category == manticore.ErrorCategoryUnsupported {
+		statusCode = http.StatusNotImplemented
 	}
-	if strings.Contains(errorStr, "model") {
-		return "model"
-	}
-	if strings.Contains(errorStr, "http 4") {
-		return "client_error"
-	}
-	if strings.Contains(errorStr, "http 5") {
-		return "server_error"
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Failed to encode search error response: %v", err)
 	}
+}
 
-	return "unknown"
+// categorizeAISearchError categorizes AI search errors for better user feedback
+func (app *AppState) categorizeAISearchError(err error) string {
+	return string(manticore.CategorizeError(err))
 }
 
 // checkAISearchHealth performs a health check for AI search functionality
@@ -552,18 +1080,19 @@ func (app *AppState) checkAISearchHealth() bool {
 	log.Printf("[AI_SEARCH] [HEALTH_CHECK] Starting AI search health check")
 
 	// Check if AI configuration is available and enabled
-	if app.AIConfig == nil {
+	aiConfig := app.CurrentAIConfig()
+	if aiConfig == nil {
 		log.Printf("[AI_SEARCH] [HEALTH_CHECK] AI configuration is not available")
 		return false
 	}
 
-	if !app.AIConfig.Enabled {
+	if !aiConfig.Enabled {
 		log.Printf("[AI_SEARCH] [HEALTH_CHECK] AI search is disabled in configuration")
 		return false
 	}
 
 	log.Printf("[AI_SEARCH] [HEALTH_CHECK] AI configuration valid - Model: %s, Timeout: %v",
-		app.AIConfig.Model, app.AIConfig.Timeout)
+		aiConfig.Model, aiConfig.Timeout)
 
 	// Check if Manticore client is available and connected
 	if app.Manticore == nil {
@@ -613,10 +1142,33 @@ func (app *AppState) logAISearchOperation(operation string, duration time.Durati
 	// - Track error rates and patterns
 }
 
+// CurrentAIConfig returns the AI configuration searches use: AIConfig until re-embedding or a
+// restore switched models, and the configuration they switched to since
+func (app *AppState) CurrentAIConfig() *models.AISearchConfig {
+	if aiConfig := app.aiConfig.Load(); aiConfig != nil {
+		return aiConfig
+	}
+	return app.AIConfig
+}
+
+// SetAIConfig makes aiConfig the AI configuration of searches started from now on; it is safe to
+// call while searches run
+func (app *AppState) SetAIConfig(aiConfig *models.AISearchConfig) {
+	app.aiConfig.Store(aiConfig)
+}
+
 // getAIModel returns the currently configured AI model
 func (app *AppState) getAIModel() string {
-	if app.AIConfig != nil && app.AIConfig.Model != "" {
-		return app.AIConfig.Model
+	if aiConfig := app.CurrentAIConfig(); aiConfig != nil && aiConfig.Model != "" {
+		return aiConfig.Model
 	}
 	return "sentence-transformers/all-MiniLM-L6-v2" // Default model
 }
+
+// dedupThreshold returns the similarity above which dedup=true collapses results
+func (app *AppState) dedupThreshold() float64 {
+	if app.DedupThreshold <= 0 {
+		return search.DefaultDedupThreshold
+	}
+	return app.DedupThreshold
+}
@@ -1,26 +1,49 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ad/manticoresearch-go/internal/manticore"
 	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/reconcile"
+	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/pkg/api"
 )
 
 // MockManticoreClient for testing
 type MockManticoreClient struct {
-	connected bool
-	healthy   bool
+	connected  bool
+	healthy    bool
+	recordings manticore.Recordings
 }
 
 func (m *MockManticoreClient) IsConnected() bool {
 	return m.connected
 }
 
+func (m *MockManticoreClient) GetCapabilities() *manticore.Capabilities {
+	return nil
+}
+
+func (m *MockManticoreClient) GetCircuitBreakerTransitions() []manticore.CircuitBreakerTransition {
+	return nil
+}
+
+func (m *MockManticoreClient) GetResilienceStats() manticore.ResilienceStats {
+	return manticore.ResilienceStats{}
+}
+
+func (m *MockManticoreClient) GetRecordings() manticore.Recordings {
+	return m.recordings
+}
+
 func (m *MockManticoreClient) HealthCheck() error {
 	if !m.healthy {
 		return fmt.Errorf("health check failed")
@@ -32,6 +55,10 @@ func (m *MockManticoreClient) WaitForReady(timeout time.Duration) error {
 	return nil
 }
 
+func (m *MockManticoreClient) WaitForReadyContext(ctx context.Context, opts manticore.ReadyOptions) error {
+	return nil
+}
+
 func (m *MockManticoreClient) Close() error {
 	return nil
 }
@@ -40,6 +67,31 @@ func (m *MockManticoreClient) CreateSchema(aiConfig *models.AISearchConfig) erro
 	return nil
 }
 
+func (m *MockManticoreClient) MigrateSchema(aiConfig *models.AISearchConfig) (*manticore.MigrationResult, error) {
+	return &manticore.MigrationResult{FromVersion: manticore.SchemaVersion, ToVersion: manticore.SchemaVersion}, nil
+}
+
+func (m *MockManticoreClient) Backup(path string) (*manticore.BackupResult, error) {
+	return &manticore.BackupResult{Path: path, Tables: []string{"documents", "documents_vector"}}, nil
+}
+
+func (m *MockManticoreClient) ReembedDocuments(ctx context.Context, aiConfig *models.AISearchConfig, progress func(manticore.ReembedProgress)) (*manticore.ReembedResult, error) {
+	progress(manticore.ReembedProgress{Copied: 2, Total: 2})
+	return &manticore.ReembedResult{Model: aiConfig.Model, Table: "documents_g1", Documents: 2}, nil
+}
+
+func (m *MockManticoreClient) DropStaleDocumentsTables() ([]manticore.DroppedTable, error) {
+	return nil, nil
+}
+
+func (m *MockManticoreClient) SetDocumentStatus(id int64, status models.DocumentStatus) error {
+	return nil
+}
+
+func (m *MockManticoreClient) SetDocumentTags(id int64, tags []string) error {
+	return nil
+}
+
 func (m *MockManticoreClient) ResetDatabase() error {
 	return nil
 }
@@ -48,6 +100,73 @@ func (m *MockManticoreClient) TruncateTables() error {
 	return nil
 }
 
+func (m *MockManticoreClient) TruncateTable(table string) error {
+	return nil
+}
+
+func (m *MockManticoreClient) OptimizeTable(table string) error {
+	return nil
+}
+
+func (m *MockManticoreClient) DocumentIDs(table string) ([]int64, error) {
+	return nil, nil
+}
+
+func (m *MockManticoreClient) GetDocumentHashes() (map[int64]string, error) {
+	return nil, nil
+}
+
+func (m *MockManticoreClient) GetDocumentTimes() (map[int64]int64, error) {
+	return nil, nil
+}
+
+func (m *MockManticoreClient) DeleteDocuments(table string, ids []int64) error {
+	return nil
+}
+
+func (m *MockManticoreClient) ResetTable(table string, aiConfig *models.AISearchConfig) error {
+	return nil
+}
+
+func (m *MockManticoreClient) CountDocuments(table string) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockManticoreClient) SearchWithContext(ctx context.Context, request manticore.SearchRequest) (*manticore.SearchResponse, error) {
+	return m.SearchWithRequest(request)
+}
+
+func (m *MockManticoreClient) GetAllDocumentsWithVectorsContext(ctx context.Context) ([]*models.Document, [][]float64, error) {
+	return m.GetAllDocumentsWithVectors()
+}
+
+func (m *MockManticoreClient) GetOperationTimeouts() manticore.OperationTimeouts {
+	return manticore.DefaultOperationTimeouts()
+}
+
+func (m *MockManticoreClient) CheckHealth() manticore.HealthReport {
+	if !m.healthy {
+		return manticore.HealthReport{State: manticore.HealthStateDown, Error: "health check failed"}
+	}
+	return manticore.HealthReport{State: manticore.HealthStateReady, ServerUp: true, SchemaPresent: true}
+}
+
+func (m *MockManticoreClient) Count(query string, filters map[string]interface{}) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockManticoreClient) AISearchWithContext(ctx context.Context, query, model string, limit, offset int) (*manticore.SearchResponse, error) {
+	return m.AISearch(query, model, limit, offset)
+}
+
+func (m *MockManticoreClient) GetIndexStats(table string) (*manticore.IndexStats, error) {
+	return &manticore.IndexStats{Table: table, Exists: true, IndexedDocuments: 3, DiskBytes: 1024}, nil
+}
+
+func (m *MockManticoreClient) EmbeddingModels(table string) ([]manticore.EmbeddingModel, error) {
+	return []manticore.EmbeddingModel{{Table: table, Column: "content_vector", Model: "sentence-transformers/all-MiniLM-L6-v2", Dimension: 384, Source: "content", Similarity: "cosine"}}, nil
+}
+
 func (m *MockManticoreClient) IndexDocument(doc *models.Document, vector []float64) error {
 	return nil
 }
@@ -69,29 +188,23 @@ func (m *MockManticoreClient) GetAllDocuments() ([]*models.Document, error) {
 	return []*models.Document{}, nil
 }
 
+func (m *MockManticoreClient) GetDocument(id int64) (*models.Document, error) {
+	return nil, fmt.Errorf("%w: %d", manticore.ErrDocumentNotFound, id)
+}
+
+func (m *MockManticoreClient) GetAllDocumentsWithVectors() ([]*models.Document, [][]float64, error) {
+	return []*models.Document{}, [][]float64{}, nil
+}
+
 func (m *MockManticoreClient) SearchWithRequest(request manticore.SearchRequest) (*manticore.SearchResponse, error) {
 	return &manticore.SearchResponse{}, nil
 }
 
 func (m *MockManticoreClient) AISearch(query, model string, limit, offset int) (*manticore.SearchResponse, error) {
 	return &manticore.SearchResponse{
-		Hits: struct {
-			Total         int32  `json:"total"`
-			TotalRelation string `json:"total_relation"`
-			Hits          []struct {
-				Index  string                 `json:"_index"`
-				ID     int64                  `json:"_id"`
-				Score  float32                `json:"_score"`
-				Source map[string]interface{} `json:"_source"`
-			} `json:"hits"`
-		}{
+		Hits: manticore.SearchHits{
 			Total: 0,
-			Hits: []struct {
-				Index  string                 `json:"_index"`
-				ID     int64                  `json:"_id"`
-				Score  float32                `json:"_score"`
-				Source map[string]interface{} `json:"_source"`
-			}{},
+			Hits:  []manticore.SearchHit{},
 		},
 	}, nil
 }
@@ -101,7 +214,7 @@ func (m *MockManticoreClient) GenerateEmbedding(text string, model string) ([]fl
 }
 
 func TestSearchHandler_AISearchValidation(t *testing.T) {
-	// Test AI search validation when AI is disabled
+	// AI searches degrade to hybrid search when AI is disabled
 	app := &AppState{
 		AIConfig: &models.AISearchConfig{
 			Model:   "test-model",
@@ -116,8 +229,20 @@ func TestSearchHandler_AISearchValidation(t *testing.T) {
 
 	app.SearchHandler(w, req)
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response api.APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	searchResponse, err := api.DecodeData[api.SearchResponse](response)
+	if err != nil {
+		t.Fatalf("Expected SearchResponse: %v", err)
+	}
+	if searchResponse.Mode != "hybrid (AI degraded)" {
+		t.Errorf("Expected mode %q, got %q", "hybrid (AI degraded)", searchResponse.Mode)
 	}
 }
 
@@ -169,6 +294,73 @@ func TestStatusHandler_AISearchInfo(t *testing.T) {
 	}
 }
 
+func TestStatusHandler_IndexStats(t *testing.T) {
+	lastReindex := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	app := &AppState{
+		Manticore:   &MockManticoreClient{connected: true, healthy: true},
+		LastReindex: lastReindex,
+	}
+
+	req := httptest.NewRequest("GET", "/api/status", nil)
+	w := httptest.NewRecorder()
+	app.StatusHandler(w, req)
+
+	var response struct {
+		Data api.StatusResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(response.Data.Tables) != 2 {
+		t.Fatalf("Expected stats for 2 tables, got %+v", response.Data.Tables)
+	}
+	documents := response.Data.Tables[0]
+	if documents.Name != "documents" || !documents.Exists || documents.Documents != 3 || documents.DiskBytes != 1024 {
+		t.Errorf("Unexpected documents table stats: %+v", documents)
+	}
+	if response.Data.LastReindex == nil || !response.Data.LastReindex.Equal(lastReindex) {
+		t.Errorf("Expected last reindex %v, got %v", lastReindex, response.Data.LastReindex)
+	}
+}
+
+func TestStatusHandler_Consistency(t *testing.T) {
+	app := &AppState{
+		Manticore: &MockManticoreClient{connected: true, healthy: true},
+		Consistency: &reconcile.Report{
+			SourceDocuments:  3,
+			IndexedDocuments: 2,
+			IndexedVectors:   3,
+			Missing:          reconcile.IDs{Count: 1, IDs: []int64{3}},
+			Orphaned:         reconcile.IDs{Count: 1, IDs: []int64{7}},
+		},
+	}
+
+	req := httptest.NewRequest("GET", "/api/status", nil)
+	w := httptest.NewRecorder()
+	app.StatusHandler(w, req)
+
+	var response struct {
+		Data api.StatusResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	consistency := response.Data.Consistency
+	if consistency == nil || consistency.Consistent || consistency.IndexedDocuments != 2 {
+		t.Fatalf("Expected an inconsistent report, got %+v", consistency)
+	}
+	if consistency.Missing.Count != 1 || consistency.Missing.IDs[0] != 3 || consistency.Orphaned.IDs[0] != 7 {
+		t.Errorf("Unexpected differences: %+v", consistency)
+	}
+
+	app.Consistency = nil
+	if app.status().Consistency != nil {
+		t.Error("Expected no consistency report before the check ran")
+	}
+}
+
 func TestValidateAISearchAvailability(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -260,3 +452,112 @@ func TestCheckAISearchHealth(t *testing.T) {
 		})
 	}
 }
+
+// slowVectorClient blocks vector lookups until the search context is done
+type slowVectorClient struct {
+	MockManticoreClient
+}
+
+func (c *slowVectorClient) GetAllDocumentsWithVectorsContext(ctx context.Context) ([]*models.Document, [][]float64, error) {
+	<-ctx.Done()
+	return nil, nil, fmt.Errorf("failed to get all documents with vectors: %v", ctx.Err())
+}
+
+func (c *slowVectorClient) GetOperationTimeouts() manticore.OperationTimeouts {
+	timeouts := manticore.DefaultOperationTimeouts()
+	timeouts.Vector = 50 * time.Millisecond
+	return timeouts
+}
+
+func TestSearchHandler_ModeTimeout(t *testing.T) {
+	app := &AppState{Manticore: &slowVectorClient{MockManticoreClient{connected: true, healthy: true}}}
+
+	req := httptest.NewRequest("GET", "/api/search?query=test&mode=vector", nil)
+	w := httptest.NewRecorder()
+
+	start := time.Now()
+	app.SearchHandler(w, req)
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status %d, got %d", http.StatusGatewayTimeout, w.Code)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Handler was not bounded by the vector timeout, took %v", elapsed)
+	}
+}
+
+func TestSearchHandler_InvalidFields(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+
+	req := httptest.NewRequest("GET", "/api/search?query=test&mode=vector&fields=id,body", nil)
+	w := httptest.NewRecorder()
+	app.SearchHandler(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestSearchHandler_InvalidRecency(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+
+	for _, url := range []string{"/api/search?query=test&sort=title", "/api/search?query=test&since=yesterday"} {
+		w := httptest.NewRecorder()
+		app.SearchHandler(w, httptest.NewRequest("GET", url, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", url, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+func TestSearchHandler_InvalidQueryAndPage(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+
+	urls := []string{
+		"/api/search?query=" + strings.Repeat("a", search.MaxQueryLength+1),
+		"/api/search?query=%22%28%29%22%7C",
+		fmt.Sprintf("/api/search?query=test&page=%d", search.MaxPage+1),
+	}
+	for _, url := range urls {
+		w := httptest.NewRecorder()
+		app.SearchHandler(w, httptest.NewRequest("GET", url, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%.60s: expected status %d, got %d", url, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+// schemaMissingClient answers health probes but has no tables
+type schemaMissingClient struct {
+	MockManticoreClient
+}
+
+func (c *schemaMissingClient) CheckHealth() manticore.HealthReport {
+	return manticore.HealthReport{
+		State:         manticore.HealthStateSchemaMissing,
+		ServerUp:      true,
+		MissingTables: []string{"documents", "documents_vector"},
+	}
+}
+
+func TestStatusHandler_SchemaMissing(t *testing.T) {
+	app := &AppState{Manticore: &schemaMissingClient{MockManticoreClient{connected: true, healthy: true}}}
+
+	req := httptest.NewRequest("GET", "/api/status", nil)
+	w := httptest.NewRecorder()
+	app.StatusHandler(w, req)
+
+	var response struct {
+		Data api.StatusResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if !response.Data.ManticoreHealthy || response.Data.SchemaPresent {
+		t.Errorf("Expected a healthy server without schema, got %+v", response.Data)
+	}
+	if response.Data.ManticoreState != "schema_missing" || len(response.Data.MissingTables) != 2 {
+		t.Errorf("Expected missing tables to be reported, got %q %v", response.Data.ManticoreState, response.Data.MissingTables)
+	}
+}
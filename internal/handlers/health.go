@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// LivenessHandler handles GET /healthz requests. It only reports that the process is serving HTTP.
+func (app *AppState) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeHealthResponse(w, http.StatusOK, api.HealthResponse{Status: "ok"})
+}
+
+// ReadinessHandler handles GET /readyz requests. It reports ready only when Manticore is connected and healthy.
+func (app *AppState) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	checks := map[string]string{}
+	ready := true
+
+	switch {
+	case app.Manticore == nil:
+		checks["manticore"] = "client not initialized"
+		ready = false
+	case !app.Manticore.IsConnected():
+		checks["manticore"] = "not connected"
+		ready = false
+	case app.Connection != nil && !app.Connection.IsReady():
+		checks["manticore"] = string(app.Connection.Status().State)
+		ready = false
+	default:
+		if err := app.Manticore.HealthCheck(); err != nil {
+			checks["manticore"] = err.Error()
+			ready = false
+		} else {
+			checks["manticore"] = "ok"
+		}
+	}
+
+	if !ready {
+		writeHealthResponse(w, http.StatusServiceUnavailable, api.HealthResponse{Status: "not_ready", Checks: checks})
+		return
+	}
+	writeHealthResponse(w, http.StatusOK, api.HealthResponse{Status: "ready", Checks: checks})
+}
+
+// writeHealthResponse writes a probe response without caching
+func writeHealthResponse(w http.ResponseWriter, statusCode int, response api.HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Failed to encode health response: %v", err)
+	}
+}
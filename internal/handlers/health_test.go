@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+func TestLivenessHandler(t *testing.T) {
+	app := &AppState{}
+
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	w := httptest.NewRecorder()
+	app.LivenessHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		client         manticore.ClientInterface
+		expectedStatus int
+		expectedState  string
+	}{
+		{"ready", &MockManticoreClient{connected: true, healthy: true}, http.StatusOK, "ready"},
+		{"no client", nil, http.StatusServiceUnavailable, "not_ready"},
+		{"not connected", &MockManticoreClient{connected: false, healthy: true}, http.StatusServiceUnavailable, "not_ready"},
+		{"unhealthy", &MockManticoreClient{connected: true, healthy: false}, http.StatusServiceUnavailable, "not_ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &AppState{Manticore: tt.client}
+
+			req := httptest.NewRequest("GET", "/readyz", nil)
+			w := httptest.NewRecorder()
+			app.ReadinessHandler(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response api.HealthResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Status != tt.expectedState {
+				t.Errorf("Expected status %q, got %q", tt.expectedState, response.Status)
+			}
+		})
+	}
+}
+
+func TestReadinessHandlerWaitsForStartup(t *testing.T) {
+	client := &MockManticoreClient{connected: true, healthy: true}
+	app := &AppState{
+		Manticore:  client,
+		Connection: manticore.NewConnectionManager(client, manticore.ConnectionManagerConfig{}),
+	}
+
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	w := httptest.NewRecorder()
+	app.ReadinessHandler(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 before startup completes, got %d", w.Code)
+	}
+
+	var response api.HealthResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Checks["manticore"] != string(manticore.ConnectionStateDisconnected) {
+		t.Errorf("Expected connection state in checks, got %v", response.Checks)
+	}
+}
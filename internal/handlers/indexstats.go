@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// IndexStatsHandler handles GET /api/stats/index requests
+func (app *AppState) IndexStatsHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow GET requests
+	if r.Method != "GET" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	app.sendSuccessResponse(w, app.indexStats())
+}
+
+// TermStatsHandler handles GET /api/stats/terms requests, listing the vocabulary of the TF-IDF
+// vectorizer, the most common words first
+func (app *AppState) TermStatsHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow GET requests
+	if r.Method != "GET" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	limit, err := parseIntRangeParam(r.URL.Query().Get("limit"), "limit", 50, 1, maxTermStats)
+	if err != nil {
+		app.sendValidationError(w, r, err)
+		return
+	}
+	prefix := r.URL.Query().Get("prefix")
+
+	response := api.TermStatsResponse{Prefix: prefix, Terms: []api.TermStats{}}
+	// Before the first indexing the vocabulary is empty
+	if app.Vectorizer != nil {
+		terms, total := app.Vectorizer.Terms(prefix, limit)
+		response.VocabularySize = app.Vectorizer.VocabularySize()
+		response.Total = total
+		for _, term := range terms {
+			response.Terms = append(response.Terms, api.TermStats{
+				Term:              term.Word,
+				DocumentFrequency: term.DocumentFrequency,
+				IDF:               term.IDF,
+			})
+		}
+	}
+
+	app.sendSuccessResponse(w, response)
+}
+
+// maxTermStats is the largest limit of the vocabulary endpoint
+const maxTermStats = 1000
+
+// indexStats combines SHOW INDEX STATUS of the data tables with the state of the document store
+// and the vectorizer. Tables are left out while Manticore is unavailable.
+func (app *AppState) indexStats() api.IndexStatsResponse {
+	store := app.Documents.Stats()
+	response := api.IndexStatsResponse{
+		Documents:             store.Documents,
+		AverageDocumentLength: store.AverageDocumentLength,
+		VectorDimension:       store.VectorDimension,
+		DocumentStore: api.DocumentStoreStats{
+			Cached:       store.Cached,
+			SizeBytes:    store.Size,
+			MaxDocuments: store.MaxDocuments,
+			MaxSizeBytes: store.MaxSize,
+			Hits:         store.Hits,
+			Misses:       store.Misses,
+			Evictions:    store.Evictions,
+		},
+		Tables: make([]api.IndexTableStats, 0, len(statusTables)),
+	}
+
+	if app.Vectorizer != nil {
+		response.VocabularySize = app.Vectorizer.VocabularySize()
+	}
+
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		return response
+	}
+	for _, table := range statusTables {
+		stats, err := app.Manticore.GetIndexStats(table)
+		if err != nil {
+			log.Printf("Warning: Failed to get index stats for %s: %v", table, err)
+			continue
+		}
+		response.Tables = append(response.Tables, api.IndexTableStats{
+			Name:         stats.Table,
+			Exists:       stats.Exists,
+			Documents:    stats.IndexedDocuments,
+			IndexedBytes: stats.IndexedBytes,
+			DiskBytes:    stats.DiskBytes,
+			RAMBytes:     stats.RAMBytes,
+			DiskChunks:   stats.DiskChunks,
+			Optimizing:   stats.Optimizing,
+			LastOptimize: app.lastOptimized(table),
+		})
+	}
+	return response
+}
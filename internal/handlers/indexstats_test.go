@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/docstore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+func TestIndexStatsHandler(t *testing.T) {
+	store := docstore.New(1, 0)
+	store.Replace([]*models.Document{
+		{ID: 1, Title: "Привет", Content: "мир"},
+		{ID: 2, Title: "Hello", Content: "world!"},
+	}, [][]float64{{0, 1, 0}, {1, 0, 0}})
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}, Documents: store}
+	optimizedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	app.recordOptimize("documents", optimizedAt)
+
+	w := httptest.NewRecorder()
+	app.IndexStatsHandler(w, httptest.NewRequest("GET", "/api/stats/index", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data api.IndexStatsResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	stats := response.Data
+	if stats.Documents != 2 || stats.AverageDocumentLength != 10 || stats.VectorDimension != 3 {
+		t.Errorf("Unexpected document statistics: %+v", stats)
+	}
+	if stats.DocumentStore.Cached != 1 || stats.DocumentStore.MaxDocuments != 1 || stats.DocumentStore.Evictions != 1 {
+		t.Errorf("Unexpected document store statistics: %+v", stats.DocumentStore)
+	}
+	if len(stats.Tables) != 2 {
+		t.Fatalf("Expected both tables, got %+v", stats.Tables)
+	}
+	if stats.Tables[0].Name != "documents" || stats.Tables[0].Documents != 3 || stats.Tables[0].DiskBytes != 1024 {
+		t.Errorf("Unexpected documents table: %+v", stats.Tables[0])
+	}
+	if stats.Tables[0].LastOptimize == nil || !stats.Tables[0].LastOptimize.Equal(optimizedAt) {
+		t.Errorf("Expected the optimize time of documents, got %v", stats.Tables[0].LastOptimize)
+	}
+	if stats.Tables[1].LastOptimize != nil {
+		t.Errorf("Expected no optimize time for documents_vector, got %v", stats.Tables[1].LastOptimize)
+	}
+}
+
+func TestIndexStatsHandler_ManticoreUnavailable(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: false}}
+
+	w := httptest.NewRecorder()
+	app.IndexStatsHandler(w, httptest.NewRequest("GET", "/api/stats/index", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var response struct {
+		Data api.IndexStatsResponse `json:"data"`
+	}
+	json.NewDecoder(w.Body).Decode(&response)
+	if response.Data.Documents != 0 || len(response.Data.Tables) != 0 {
+		t.Errorf("Expected empty statistics, got %+v", response.Data)
+	}
+
+	w = httptest.NewRecorder()
+	app.IndexStatsHandler(w, httptest.NewRequest("POST", "/api/stats/index", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+}
+
+func TestTermStatsHandler(t *testing.T) {
+	vec := vectorizer.NewTFIDFVectorizer()
+	vec.FitTransform([]*models.Document{
+		{ID: 1, Title: "Форма", Content: "форма обратной связи"},
+		{ID: 2, Title: "Формат", Content: "формат даты"},
+		{ID: 3, Title: "Фото", Content: "форма загрузки"},
+		{ID: 4, Title: "Поиск", Content: "поиск по сайту"},
+	})
+	app := &AppState{Vectorizer: vec}
+
+	tests := []struct {
+		name          string
+		url           string
+		expectedTotal int
+		expectedTerms []api.TermStats
+	}{
+		{"prefix", "/api/stats/terms?prefix=ФОРМ", 2, []api.TermStats{
+			{Term: "форма", DocumentFrequency: 2, IDF: 0.6931471805599453},
+			{Term: "формат", DocumentFrequency: 1, IDF: 1.3862943611198906},
+		}},
+		{"limit", "/api/stats/terms?prefix=форм&limit=1", 2, []api.TermStats{
+			{Term: "форма", DocumentFrequency: 2, IDF: 0.6931471805599453},
+		}},
+		{"no match", "/api/stats/terms?prefix=xyz", 0, []api.TermStats{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			app.TermStatsHandler(w, httptest.NewRequest("GET", tt.url, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var response struct {
+				Data api.TermStatsResponse `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Data.Total != tt.expectedTotal || response.Data.VocabularySize != vec.VocabularySize() {
+				t.Errorf("Unexpected totals: %+v", response.Data)
+			}
+			if !reflect.DeepEqual(response.Data.Terms, tt.expectedTerms) {
+				t.Errorf("Expected terms %+v, got %+v", tt.expectedTerms, response.Data.Terms)
+			}
+		})
+	}
+
+	// Document frequencies survive a backup of the vocabulary
+	restored, err := vectorizer.NewTFIDFVectorizerFromModel(vec.Model())
+	if err != nil {
+		t.Fatalf("Failed to restore the vectorizer: %v", err)
+	}
+	if terms, _ := restored.Terms("форма", 1); len(terms) != 1 || terms[0].DocumentFrequency != 2 {
+		t.Errorf("Expected the restored document frequency, got %+v", terms)
+	}
+}
+
+func TestTermStatsHandler_Validation(t *testing.T) {
+	app := &AppState{}
+
+	w := httptest.NewRecorder()
+	app.TermStatsHandler(w, httptest.NewRequest("GET", "/api/stats/terms?limit=5000", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a limit out of range, got %d", w.Code)
+	}
+
+	// Before indexing the vocabulary is empty
+	w = httptest.NewRecorder()
+	app.TermStatsHandler(w, httptest.NewRequest("GET", "/api/stats/terms", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"terms":[]`) {
+		t.Errorf("Expected an empty vocabulary, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// DefaultMaxRequestBodySize is the largest accepted request body unless MAX_REQUEST_BODY_SIZE is set
+const DefaultMaxRequestBodySize int64 = 1 << 20
+
+// MaxRequestBodySizeFromEnvironment reads MAX_REQUEST_BODY_SIZE in bytes. It returns
+// DefaultMaxRequestBodySize when the variable is unset or invalid.
+func MaxRequestBodySizeFromEnvironment() (int64, error) {
+	valueStr := os.Getenv("MAX_REQUEST_BODY_SIZE")
+	if valueStr == "" {
+		return DefaultMaxRequestBodySize, nil
+	}
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil || value <= 0 {
+		return DefaultMaxRequestBodySize, fmt.Errorf("invalid MAX_REQUEST_BODY_SIZE: %q", valueStr)
+	}
+	return value, nil
+}
+
+// LimitRequestBody rejects requests whose declared body is larger than maxBytes with 413 and
+// bounds the rest with http.MaxBytesReader, so a handler reading a body fails with
+// *http.MaxBytesError instead of buffering it whole
+func (app *AppState) LimitRequestBody(next http.Handler, maxBytes int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > maxBytes {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Connection", "close")
+			app.sendErrorResponse(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body too large (at most %d bytes)", maxBytes))
+			return
+		}
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
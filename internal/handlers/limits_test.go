@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+func TestLimitRequestBody(t *testing.T) {
+	app := &AppState{}
+	var readErr error
+	handler := app.LimitRequestBody(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}), 8)
+
+	t.Run("small body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/reindex", strings.NewReader("12345678")))
+		if w.Code != http.StatusOK || readErr != nil {
+			t.Errorf("Expected the body to be read, got status %d and error %v", w.Code, readErr)
+		}
+	})
+
+	t.Run("declared length too large", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/reindex", strings.NewReader("123456789")))
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("Expected status 413, got %d", w.Code)
+		}
+		var response api.APIResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Success || response.Error == "" {
+			t.Errorf("Expected a JSON error response, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("chunked body too large", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/api/reindex", strings.NewReader("123456789"))
+		request.ContentLength = -1
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+		var maxBytesErr *http.MaxBytesError
+		if !errors.As(readErr, &maxBytesErr) {
+			t.Errorf("Expected *http.MaxBytesError reading the body, got %v", readErr)
+		}
+	})
+}
+
+func TestMaxRequestBodySizeFromEnvironment(t *testing.T) {
+	t.Setenv("MAX_REQUEST_BODY_SIZE", "")
+	if size, err := MaxRequestBodySizeFromEnvironment(); err != nil || size != DefaultMaxRequestBodySize {
+		t.Errorf("Expected the default size, got %d and %v", size, err)
+	}
+
+	t.Setenv("MAX_REQUEST_BODY_SIZE", "2048")
+	if size, err := MaxRequestBodySizeFromEnvironment(); err != nil || size != 2048 {
+		t.Errorf("Expected 2048, got %d and %v", size, err)
+	}
+
+	t.Setenv("MAX_REQUEST_BODY_SIZE", "-1")
+	if size, err := MaxRequestBodySizeFromEnvironment(); err == nil || size != DefaultMaxRequestBodySize {
+		t.Errorf("Expected an error and the default size, got %d and %v", size, err)
+	}
+}
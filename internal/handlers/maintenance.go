@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/document"
+	"github.com/ad/manticoresearch-go/internal/maintenance"
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// IncrementalReindex is the outcome of an incremental reindex
+type IncrementalReindex struct {
+	Documents int // valid documents in the data directory
+	Indexed   int // new or changed documents that were indexed
+	Unchanged int
+	Removed   int // indexed documents no longer in the data directory
+	Rejected  int // documents that failed validation
+	Expired   int // valid documents the retention policy keeps out of the index
+
+	EmbeddingTruncated int // indexed documents longer than the input limit of the AI model
+}
+
+// Changed reports whether the reindex changed the index
+func (r *IncrementalReindex) Changed() bool {
+	return r.Indexed > 0 || r.Removed > 0
+}
+
+// IncrementalReindex indexes the documents of the data directory whose content hash differs from
+// the one stored in Manticore, see manticore.ClientInterface.GetDocumentHashes, and removes the
+// indexed documents no longer in it. Unlike a full reindex the schema is kept and the TF-IDF
+// vectorizer is not refitted: changed documents get vectors of the current vocabulary, so a full
+// reindex is still due once the corpus has drifted.
+func (app *AppState) IncrementalReindex(ctx context.Context) (*IncrementalReindex, error) {
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		return nil, fmt.Errorf("Manticore Search is not available")
+	}
+	vec := app.Vectorizer
+	if vec == nil {
+		return nil, fmt.Errorf("no vectorizer is trained yet, a full reindex is required first")
+	}
+
+	documents, err := document.ScanDataDirectory(app.DataDirectory())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load documents: %v", err)
+	}
+	app.NormalizeDocuments(documents)
+	documents, rejected := app.ValidateDocuments("schedule", documents)
+	// Expired documents count as removed, so they are deleted like the others gone from the directory
+	documents, expired := app.RetainDocuments(documents)
+
+	hashes, err := app.Manticore.GetDocumentHashes()
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &IncrementalReindex{Documents: len(documents), Rejected: len(rejected), Expired: expired}
+	vectors := make([][]float64, len(documents))
+	var changed []*models.Document
+	var changedVectors [][]float64
+	current := make(map[int64]bool, len(documents))
+	for i, doc := range documents {
+		vectors[i] = vec.TransformQuery(documentText(doc))
+		current[int64(doc.ID)] = true
+		if hash, ok := hashes[int64(doc.ID)]; ok && hash == doc.ContentHash() {
+			result.Unchanged++
+			continue
+		}
+		changed = append(changed, doc)
+		changedVectors = append(changedVectors, vectors[i])
+	}
+
+	var removed []int64
+	for id := range hashes {
+		if !current[id] {
+			removed = append(removed, id)
+		}
+	}
+
+	if len(changed) > 0 {
+		err = app.Manticore.IndexDocuments(changed, changedVectors)
+		app.IndexChanged()
+		if err != nil {
+			return nil, fmt.Errorf("failed to index changed documents: %v", err)
+		}
+		result.Indexed = len(changed)
+		result.EmbeddingTruncated = len(app.EmbeddingTruncations(changed))
+	}
+	if len(removed) > 0 {
+		for _, table := range manticore.DataTables {
+			if err := app.Manticore.DeleteDocuments(table, removed); err != nil {
+				app.IndexChanged()
+				return nil, err
+			}
+		}
+		app.IndexChanged()
+		result.Removed = len(removed)
+	}
+
+	if result.Changed() {
+		app.Documents.Replace(documents, vectors)
+		app.LastReindex = time.Now()
+	}
+	return result, nil
+}
+
+// MaintenanceTasks returns the maintenance tasks of app by name, for the maintenance scheduler.
+// Each is recorded in the audit log as performed by the system.
+func (app *AppState) MaintenanceTasks() map[string]maintenance.Task {
+	return map[string]maintenance.Task{
+		maintenance.TaskReindex:       app.scheduledReindex,
+		maintenance.TaskOptimize:      app.scheduledOptimize,
+		maintenance.TaskCacheEviction: app.scheduledCacheEviction,
+		maintenance.TaskRetention:     app.scheduledRetention,
+		maintenance.TaskVectorGC:      app.scheduledVectorGC,
+	}
+}
+
+// scheduledReindex runs an incremental reindex, publishing a webhook event when it changed the
+// index or failed
+func (app *AppState) scheduledReindex(ctx context.Context) error {
+	startTime := time.Now()
+	params := map[string]interface{}{"reason": "schedule", "data_dir": app.DataDirectory(), "incremental": true}
+
+	result, err := app.IncrementalReindex(ctx)
+	if result != nil {
+		params["documents"] = result.Documents
+		params["indexed"] = result.Indexed
+		params["unchanged"] = result.Unchanged
+		params["removed"] = result.Removed
+		params["rejected"] = result.Rejected
+		params["expired"] = result.Expired
+		params["embedding_truncated"] = result.EmbeddingTruncated
+	}
+	app.recordAuditAs("system", "", "reindex", params, err, startTime)
+	if err != nil || result.Changed() {
+		app.PublishReindex("schedule", params, err, startTime)
+	}
+	if result != nil {
+		log.Printf("Scheduled reindex: %d indexed, %d unchanged, %d removed, %d rejected", result.Indexed, result.Unchanged, result.Removed, result.Rejected)
+	}
+	return err
+}
+
+// scheduledOptimize starts merging the disk chunks of every data table
+func (app *AppState) scheduledOptimize(ctx context.Context) error {
+	startTime := time.Now()
+	params := map[string]interface{}{"reason": "schedule", "tables": manticore.DataTables}
+
+	var err error
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		err = fmt.Errorf("Manticore Search is not available")
+	}
+	for _, table := range manticore.DataTables {
+		if err != nil {
+			break
+		}
+		if err = app.Manticore.OptimizeTable(table); err == nil {
+			app.recordOptimize(table, time.Now())
+		}
+	}
+	app.recordAuditAs("system", "", "optimize", params, err, startTime)
+	return err
+}
+
+// scheduledCacheEviction drops expired cached search results and progressive search continuations
+func (app *AppState) scheduledCacheEviction(ctx context.Context) error {
+	results := app.ResultCache.EvictExpired()
+	continuations := app.Continuations.EvictExpired()
+	if results > 0 || continuations > 0 {
+		log.Printf("Evicted %d expired cached search results and %d expired continuations", results, continuations)
+	}
+	return nil
+}
+
+// convertMaintenance converts the status of the scheduled maintenance tasks to their API form
+func convertMaintenance(statuses []maintenance.JobStatus) []api.MaintenanceTask {
+	if len(statuses) == 0 {
+		return nil
+	}
+	tasks := make([]api.MaintenanceTask, 0, len(statuses))
+	for _, status := range statuses {
+		task := api.MaintenanceTask{
+			Task:      status.Task,
+			Schedule:  status.Schedule,
+			Runs:      status.Runs,
+			Running:   status.Running,
+			LastError: status.LastError,
+		}
+		if !status.NextRun.IsZero() {
+			nextRun := status.NextRun
+			task.NextRun = &nextRun
+		}
+		if !status.LastRun.IsZero() {
+			lastRun := status.LastRun
+			task.LastRun = &lastRun
+			task.LastDuration = status.LastDuration.String()
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/audit"
+	"github.com/ad/manticoresearch-go/internal/document"
+	"github.com/ad/manticoresearch-go/internal/maintenance"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+// hashClient serves stored content hashes and records what is indexed and deleted
+type hashClient struct {
+	MockManticoreClient
+	hashes  map[int64]string
+	indexed []*models.Document
+	deleted map[string][]int64
+}
+
+func (c *hashClient) GetDocumentHashes() (map[int64]string, error) {
+	return c.hashes, nil
+}
+
+func (c *hashClient) IndexDocuments(docs []*models.Document, vectors [][]float64) error {
+	c.indexed = append(c.indexed, docs...)
+	return nil
+}
+
+func (c *hashClient) DeleteDocuments(table string, ids []int64) error {
+	c.deleted[table] = append(c.deleted[table], ids...)
+	return nil
+}
+
+func TestIncrementalReindex(t *testing.T) {
+	dataDir := t.TempDir()
+	for name, content := range map[string]string{
+		"kept.md":    "# Kept\n\n**URL:** https://example.com/kept\n\nThis document did not change.",
+		"changed.md": "# Changed\n\n**URL:** https://example.com/changed\n\nThis document was edited.",
+	} {
+		if err := os.WriteFile(filepath.Join(dataDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write document: %v", err)
+		}
+	}
+
+	documents, err := document.ScanDataDirectory(dataDir)
+	if err != nil || len(documents) != 2 {
+		t.Fatalf("Failed to scan documents: %v", err)
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	vec.FitTransform(documents)
+
+	client := &hashClient{
+		MockManticoreClient: MockManticoreClient{connected: true, healthy: true},
+		hashes:              map[int64]string{999: "gone"},
+		deleted:             make(map[string][]int64),
+	}
+	var changedID int64
+	for _, doc := range documents {
+		if doc.Title == "Kept" {
+			client.hashes[int64(doc.ID)] = doc.ContentHash()
+		} else {
+			changedID = int64(doc.ID)
+			client.hashes[changedID] = "stale"
+		}
+	}
+
+	auditLog, _ := audit.New("", 10)
+	app := &AppState{Manticore: client, Vectorizer: vec, DataDir: dataDir, Audit: auditLog}
+
+	if err := app.MaintenanceTasks()[maintenance.TaskReindex](context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(client.indexed) != 1 || int64(client.indexed[0].ID) != changedID {
+		t.Errorf("Expected only the changed document to be indexed, got %+v", client.indexed)
+	}
+	if ids := client.deleted["documents"]; len(ids) != 1 || ids[0] != 999 {
+		t.Errorf("Expected the removed document to be deleted, got %v", client.deleted)
+	}
+	if app.IndexGeneration() == 0 || app.LastReindex.IsZero() {
+		t.Error("Expected the index change to be recorded")
+	}
+
+	entries := auditLog.Recent(10, "reindex")
+	if len(entries) != 1 || entries[0].Actor != "system" || entries[0].Outcome != "success" {
+		t.Fatalf("Expected a successful system audit entry, got %+v", entries)
+	}
+	if entries[0].Parameters["indexed"] != 1 || entries[0].Parameters["unchanged"] != 1 || entries[0].Parameters["removed"] != 1 {
+		t.Errorf("Unexpected audit params: %+v", entries[0].Parameters)
+	}
+}
+
+func TestIncrementalReindex_NoVectorizer(t *testing.T) {
+	client := &hashClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+	app := &AppState{Manticore: client, DataDir: t.TempDir()}
+
+	if _, err := app.IncrementalReindex(context.Background()); err == nil {
+		t.Error("Expected an error without a trained vectorizer")
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/slo"
+)
+
+// MetricsHandler handles GET /metrics requests with the search latencies by mode and the retry
+// outcomes and connection pool of the Manticore client in the Prometheus text format. Operations
+// without a retry policy class of their own, such as SQL statements, are reported with class "default".
+func (app *AppState) MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeSearchLatencyMetrics(w, app.SLO.Status())
+	if app.Manticore == nil {
+		return
+	}
+	stats := app.Manticore.GetResilienceStats()
+	writeRetryMetrics(w, stats)
+	writePoolMetrics(w, stats.ConnectionPool)
+}
+
+// writeRetryMetrics writes the retry outcomes and retries of every retry policy class of stats
+func writeRetryMetrics(w io.Writer, stats manticore.ResilienceStats) {
+	classes := map[string]manticore.RetryStats{"default": stats.Retry}
+	for class, retry := range stats.RetryByClass {
+		classes[string(class)] = retry
+	}
+	names := make([]string, 0, len(classes))
+	for class := range classes {
+		names = append(names, class)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "# HELP manticore_retry_outcomes_total Operations of the Manticore client by retry policy class, endpoint, last error type and outcome.")
+	fmt.Fprintln(w, "# TYPE manticore_retry_outcomes_total counter")
+	for _, class := range names {
+		for _, outcome := range classes[class].Outcomes {
+			fmt.Fprintf(w, "manticore_retry_outcomes_total{class=%s,endpoint=%s,error_type=%s,outcome=%s} %d\n",
+				labelValue(class), labelValue(outcome.Endpoint), labelValue(outcome.ErrorType), labelValue(string(outcome.Outcome)), outcome.Count)
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP manticore_retries_total Retries of the Manticore client by retry policy class and error type.")
+	fmt.Fprintln(w, "# TYPE manticore_retries_total counter")
+	for _, class := range names {
+		errorTypes := make([]string, 0, len(classes[class].RetriesByErrorType))
+		for errorType := range classes[class].RetriesByErrorType {
+			errorTypes = append(errorTypes, errorType)
+		}
+		sort.Strings(errorTypes)
+		for _, errorType := range errorTypes {
+			fmt.Fprintf(w, "manticore_retries_total{class=%s,error_type=%s} %d\n",
+				labelValue(class), labelValue(errorType), classes[class].RetriesByErrorType[errorType])
+		}
+	}
+}
+
+// writePoolMetrics writes the connections of the HTTP client to Manticore
+func writePoolMetrics(w io.Writer, pool manticore.PoolStats) {
+	fmt.Fprintln(w, "# HELP manticore_http_connections Open connections to Manticore by state.")
+	fmt.Fprintln(w, "# TYPE manticore_http_connections gauge")
+	fmt.Fprintf(w, "manticore_http_connections{state=\"active\"} %d\n", pool.Active)
+	fmt.Fprintf(w, "manticore_http_connections{state=\"idle\"} %d\n", pool.Idle)
+
+	counters := []struct {
+		name, help string
+		value      int64
+	}{
+		{"manticore_http_connection_dials_total", "Connections opened to Manticore.", pool.Dials},
+		{"manticore_http_connection_reuses_total", "Requests to Manticore sent over an idle connection.", pool.Reused},
+		{"manticore_http_connection_waits_total", "Requests to Manticore that waited for a connection because MANTICORE_HTTP_MAX_CONNS_PER_HOST were active.", pool.Waits},
+		{"manticore_http_dns_address_changes_total", "Re-resolutions of the Manticore host that found new addresses and recycled idle connections.", pool.AddressChanges},
+		{"manticore_http_transport_rebuilds_total", "Transports rebuilt after a stale address of the Manticore host refused a connection.", pool.Rebuilds},
+	}
+	for _, counter := range counters {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", counter.name, counter.help, counter.name, counter.name, counter.value)
+	}
+	fmt.Fprintln(w, "# HELP manticore_http_connection_wait_seconds_total Time requests to Manticore waited for a connection.")
+	fmt.Fprintln(w, "# TYPE manticore_http_connection_wait_seconds_total counter")
+	fmt.Fprintf(w, "manticore_http_connection_wait_seconds_total %g\n", pool.WaitTime.Seconds())
+}
+
+// writeSearchLatencyMetrics writes the latency percentiles of the search modes within the SLO
+// window and whether their objectives are burning
+func writeSearchLatencyMetrics(w io.Writer, statuses []slo.ModeStatus) {
+	fmt.Fprintln(w, "# HELP search_latency_seconds Latency of the searches of a mode within SEARCH_SLO_WINDOW.")
+	fmt.Fprintln(w, "# TYPE search_latency_seconds gauge")
+	for _, status := range statuses {
+		latencies := status.Latencies
+		fmt.Fprintf(w, "search_latency_seconds{mode=%s,quantile=\"0.5\"} %g\n", labelValue(status.Mode), latencies.P50.Seconds())
+		fmt.Fprintf(w, "search_latency_seconds{mode=%s,quantile=\"0.95\"} %g\n", labelValue(status.Mode), latencies.P95.Seconds())
+		fmt.Fprintf(w, "search_latency_seconds{mode=%s,quantile=\"0.99\"} %g\n", labelValue(status.Mode), latencies.P99.Seconds())
+	}
+
+	fmt.Fprintln(w, "# HELP search_latency_searches Searches of a mode within SEARCH_SLO_WINDOW.")
+	fmt.Fprintln(w, "# TYPE search_latency_searches gauge")
+	for _, status := range statuses {
+		fmt.Fprintf(w, "search_latency_searches{mode=%s} %d\n", labelValue(status.Mode), status.Latencies.Count)
+	}
+
+	fmt.Fprintln(w, "# HELP search_slo_burning Whether a latency objective of SEARCH_SLO is persistently over budget.")
+	fmt.Fprintln(w, "# TYPE search_slo_burning gauge")
+	for _, status := range statuses {
+		for _, objective := range status.Objectives {
+			burning := 0
+			if objective.State == slo.StateBurning {
+				burning = 1
+			}
+			fmt.Fprintf(w, "search_slo_burning{mode=%s,percentile=\"%d\"} %d\n", labelValue(status.Mode), objective.Percentile, burning)
+		}
+	}
+}
+
+// labelEscaper escapes the characters Prometheus label values can't contain as is
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelValue quotes a Prometheus label value
+func labelValue(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/slo"
+)
+
+func TestWriteRetryMetrics(t *testing.T) {
+	var out strings.Builder
+	writeRetryMetrics(&out, manticore.ResilienceStats{
+		Retry: manticore.RetryStats{Outcomes: []manticore.RetryOutcomeCount{
+			{Endpoint: "/sql", ErrorType: "none", Outcome: manticore.RetryOutcomeFirstTry, Count: 7},
+		}},
+		RetryByClass: map[manticore.OperationClass]manticore.RetryStats{
+			manticore.OperationClassSearch: {
+				RetriesByErrorType: map[string]int64{"timeout": 4},
+				Outcomes: []manticore.RetryOutcomeCount{
+					{Endpoint: "/search", ErrorType: "timeout", Outcome: manticore.RetryOutcomeAfterRetry, Count: 3},
+				},
+			},
+		},
+	})
+
+	for _, expected := range []string{
+		"# TYPE manticore_retry_outcomes_total counter\n",
+		`manticore_retry_outcomes_total{class="default",endpoint="/sql",error_type="none",outcome="success_first_try"} 7` + "\n",
+		`manticore_retry_outcomes_total{class="search",endpoint="/search",error_type="timeout",outcome="success_after_retry"} 3` + "\n",
+		`manticore_retries_total{class="search",error_type="timeout"} 4` + "\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestWritePoolMetrics(t *testing.T) {
+	var out strings.Builder
+	writePoolMetrics(&out, manticore.PoolStats{Open: 5, Active: 2, Idle: 3, Dials: 6, Reused: 40, Waits: 4, WaitTime: 1500 * time.Millisecond, AddressChanges: 2, Rebuilds: 1})
+
+	for _, expected := range []string{
+		"# TYPE manticore_http_connections gauge\n",
+		`manticore_http_connections{state="active"} 2` + "\n",
+		`manticore_http_connections{state="idle"} 3` + "\n",
+		"manticore_http_connection_dials_total 6\n",
+		"manticore_http_connection_reuses_total 40\n",
+		"manticore_http_connection_waits_total 4\n",
+		"manticore_http_connection_wait_seconds_total 1.5\n",
+		"manticore_http_dns_address_changes_total 2\n",
+		"manticore_http_transport_rebuilds_total 1\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestWriteSearchLatencyMetrics(t *testing.T) {
+	var out strings.Builder
+	writeSearchLatencyMetrics(&out, []slo.ModeStatus{{
+		Mode:      "hybrid",
+		Latencies: slo.Latencies{Count: 12, P50: 40 * time.Millisecond, P95: 250 * time.Millisecond, P99: 500 * time.Millisecond},
+		Objectives: []slo.ObjectiveStatus{{
+			Objective: slo.Objective{Mode: "hybrid", Percentile: 95, Threshold: 200 * time.Millisecond},
+			State:     slo.StateBurning,
+		}},
+	}})
+
+	for _, expected := range []string{
+		"# TYPE search_latency_seconds gauge\n",
+		`search_latency_seconds{mode="hybrid",quantile="0.5"} 0.04` + "\n",
+		`search_latency_seconds{mode="hybrid",quantile="0.95"} 0.25` + "\n",
+		`search_latency_searches{mode="hybrid"} 12` + "\n",
+		`search_slo_burning{mode="hybrid",percentile="95"} 1` + "\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+	w := httptest.NewRecorder()
+	app.MetricsHandler(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("Expected Prometheus text, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	w = httptest.NewRecorder()
+	app.MetricsHandler(w, httptest.NewRequest("POST", "/metrics", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+}
+
+func TestLabelValue(t *testing.T) {
+	if got := labelValue("a\"b\\c\nd"); got != `"a\"b\\c\nd"` {
+		t.Errorf("Unexpected label value %s", got)
+	}
+}
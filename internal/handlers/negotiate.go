@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/msgpack"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// Search response formats, negotiated from the Accept header
+const (
+	formatJSON     = "json"
+	formatMsgPack  = "msgpack"
+	formatProtobuf = "protobuf"
+)
+
+// contentTypeProtobuf is the media type of protobuf search responses, which are encoded
+// searchpb.SearchResponse messages
+const contentTypeProtobuf = "application/x-protobuf"
+
+// searchFormats maps the accepted media types to response formats
+var searchFormats = map[string]string{
+	"application/json":                formatJSON,
+	msgpack.ContentType:               formatMsgPack,
+	"application/msgpack":             formatMsgPack,
+	"application/vnd.msgpack":         formatMsgPack,
+	contentTypeProtobuf:               formatProtobuf,
+	"application/protobuf":            formatProtobuf,
+	"application/vnd.google.protobuf": formatProtobuf,
+}
+
+// negotiateSearchFormat picks the response format of a search from the Accept header, preferring
+// the media type with the highest quality and JSON when none is supported
+func negotiateSearchFormat(r *http.Request) string {
+	format, best := formatJSON, 0.0
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		candidate, ok := searchFormats[mediaType]
+		if !ok {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > best {
+			format, best = candidate, quality
+		}
+	}
+	return format
+}
+
+// sendSearchResponse sends search results as JSON, MessagePack or protobuf, as negotiated with
+// the client. MessagePack bodies carry the same envelope as JSON ones, protobuf bodies are bare
+// searchpb.SearchResponse messages. Errors are always sent as JSON.
+func (app *AppState) sendSearchResponse(w http.ResponseWriter, r *http.Request, result *models.SearchResponse) {
+	var (
+		body        []byte
+		contentType string
+		err         error
+	)
+	switch negotiateSearchFormat(r) {
+	case formatMsgPack:
+		contentType = msgpack.ContentType
+		body, err = msgpack.Marshal(api.APIResponse{Success: true, Data: result})
+	case formatProtobuf:
+		contentType = contentTypeProtobuf
+		body, err = proto.Marshal(searchResponseProto(result))
+	default:
+		app.sendSuccessResponse(w, result)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to encode %s response, falling back to JSON: %v", contentType, err)
+		app.sendSuccessResponse(w, result)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		log.Printf("Failed to write %s response: %v", contentType, err)
+	}
+}
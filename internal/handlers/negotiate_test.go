@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ad/manticoresearch-go/internal/msgpack"
+	"github.com/ad/manticoresearch-go/pkg/api/searchpb"
+)
+
+func TestNegotiateSearchFormat(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", formatJSON},
+		{"*/*", formatJSON},
+		{"text/html", formatJSON},
+		{"application/json", formatJSON},
+		{"application/x-msgpack", formatMsgPack},
+		{"application/vnd.msgpack", formatMsgPack},
+		{"application/x-protobuf", formatProtobuf},
+		{"application/json;q=0.5, application/x-protobuf", formatProtobuf},
+		{"application/x-msgpack;q=0.2, application/json;q=0.9", formatJSON},
+		{"application/x-msgpack;q=0", formatJSON},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/api/search?query=form", nil)
+		r.Header.Set("Accept", tt.accept)
+		if got := negotiateSearchFormat(r); got != tt.want {
+			t.Errorf("negotiateSearchFormat(%q) = %s, want %s", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestSearchHandler_MessagePack(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+
+	r := httptest.NewRequest("GET", "/api/search?query=form", nil)
+	r.Header.Set("Accept", msgpack.ContentType)
+	w := httptest.NewRecorder()
+	app.SearchHandler(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != msgpack.ContentType {
+		t.Errorf("Expected Content-Type %s, got %s", msgpack.ContentType, got)
+	}
+	if got := w.Header().Get("Vary"); got != "Accept, X-Tenant, X-API-Key" {
+		t.Errorf("Expected Vary: Accept, X-Tenant, X-API-Key, got %q", got)
+	}
+	// A fixmap with the success and data keys of the envelope
+	if body := w.Body.Bytes(); len(body) == 0 || body[0] != 0x82 {
+		t.Errorf("Expected a MessagePack envelope, got %x", body)
+	}
+}
+
+func TestSearchHandler_Protobuf(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+
+	r := httptest.NewRequest("GET", "/api/search?query=form&mode=fulltext", nil)
+	r.Header.Set("Accept", "application/x-protobuf")
+	w := httptest.NewRecorder()
+	app.SearchHandler(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != contentTypeProtobuf {
+		t.Errorf("Expected Content-Type %s, got %s", contentTypeProtobuf, got)
+	}
+
+	var response searchpb.SearchResponse
+	if err := proto.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode protobuf response: %v", err)
+	}
+	if response.GetMode() != "fulltext" {
+		t.Errorf("Expected mode fulltext, got %q", response.GetMode())
+	}
+}
+
+func TestSearchHandler_NegotiatedErrorsStayJSON(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+
+	r := httptest.NewRequest("GET", "/api/search", nil)
+	r.Header.Set("Accept", "application/x-protobuf")
+	w := httptest.NewRecorder()
+	app.SearchHandler(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected a JSON error, got Content-Type %s", got)
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/reconcile"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// OrphansHandler handles POST /api/admin/orphans requests, deleting the vector rows without a
+// document and reindexing the documents without a vector row, or only reporting both when
+// dry_run=true. It requires the admin token.
+func (app *AppState) OrphansHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow POST requests
+	if r.Method != "POST" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	startTime := time.Now()
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	auditParams := map[string]interface{}{"dry_run": dryRun}
+	if !app.authorizeAdmin(w, r) {
+		app.recordAudit(r, "orphan_cleanup", auditParams, fmt.Errorf("unauthorized"), startTime)
+		return
+	}
+
+	// Check if Manticore is available
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Manticore Search is not available")
+		return
+	}
+
+	cleanup, err := app.CleanOrphans(dryRun)
+	if err == nil && cleanup.Error != "" {
+		err = fmt.Errorf("%s", cleanup.Error)
+	}
+	if cleanup != nil {
+		auditParams["orphaned_vectors"] = cleanup.OrphanedVectors.Count
+		auditParams["missing_vectors"] = cleanup.MissingVectors.Count
+		auditParams["deleted_vectors"] = cleanup.DeletedVectors
+		auditParams["reindexed_documents"] = cleanup.ReindexedDocuments
+	}
+	app.recordAudit(r, "orphan_cleanup", auditParams, err, startTime)
+	if err != nil {
+		log.Printf("Orphan cleanup failed: %v", err)
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to clean up orphans: %v", err))
+		return
+	}
+
+	app.sendSuccessResponse(w, convertCleanup(cleanup))
+}
+
+// CleanOrphans runs an orphan cleanup of the documents and documents_vector tables, see
+// reconcile.CleanOrphans. Documents without a vector row are reindexed with vectors of the current
+// TF-IDF vectorizer, and only reported while there is none. A cleanup that changed rows advances
+// the index generation.
+func (app *AppState) CleanOrphans(dryRun bool) (*reconcile.Cleanup, error) {
+	var vectorize func(doc *models.Document) []float64
+	if vec := app.Vectorizer; vec != nil {
+		vectorize = func(doc *models.Document) []float64 {
+			return vec.TransformQuery(documentText(doc))
+		}
+	}
+
+	cleanup, err := reconcile.CleanOrphans(app.Manticore, app.Manticore.GetDocument, vectorize, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	if cleanup.DeletedVectors > 0 || cleanup.ReindexedDocuments > 0 {
+		app.IndexChanged()
+	}
+	return cleanup, nil
+}
+
+// convertCleanup converts an orphan cleanup outcome to its API form
+func convertCleanup(cleanup *reconcile.Cleanup) api.OrphanCleanupResponse {
+	return api.OrphanCleanupResponse{
+		CheckedAt:          cleanup.CheckedAt,
+		DryRun:             cleanup.DryRun,
+		OrphanedVectors:    convertConsistencyIDs(cleanup.OrphanedVectors),
+		MissingVectors:     convertConsistencyIDs(cleanup.MissingVectors),
+		DeletedVectors:     cleanup.DeletedVectors,
+		ReindexedDocuments: cleanup.ReindexedDocuments,
+	}
+}
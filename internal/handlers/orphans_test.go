@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/audit"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// orphanClient has an orphaned vector row 3 and a document 2 without a vector row
+type orphanClient struct {
+	MockManticoreClient
+	deleted []int64
+}
+
+func (c *orphanClient) DocumentIDs(table string) ([]int64, error) {
+	if table == "documents" {
+		return []int64{1, 2}, nil
+	}
+	return []int64{1, 3}, nil
+}
+
+func (c *orphanClient) DeleteDocuments(table string, ids []int64) error {
+	c.deleted = append(c.deleted, ids...)
+	return nil
+}
+
+func TestOrphansHandler(t *testing.T) {
+	client := &orphanClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+	auditLog, _ := audit.New("", 10)
+	app := &AppState{Manticore: client, AdminToken: "secret", Audit: auditLog}
+
+	w := httptest.NewRecorder()
+	app.OrphansHandler(w, adminRequest("/api/admin/orphans?dry_run=true", "secret"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data api.OrphanCleanupResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !response.Data.DryRun || response.Data.OrphanedVectors.Count != 1 || response.Data.MissingVectors.Count != 1 {
+		t.Errorf("Unexpected dry run response: %+v", response.Data)
+	}
+	if len(client.deleted) != 0 || app.IndexGeneration() != 0 {
+		t.Error("Expected a dry run to change nothing")
+	}
+
+	// Without a vectorizer the document without a vector row is only reported
+	w = httptest.NewRecorder()
+	app.OrphansHandler(w, adminRequest("/api/admin/orphans", "secret"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(client.deleted) != 1 || client.deleted[0] != 3 || app.IndexGeneration() != 1 {
+		t.Errorf("Expected the orphaned vector row to be deleted, got %v", client.deleted)
+	}
+	if entries := auditLog.Recent(10, "orphan_cleanup"); len(entries) != 2 || entries[0].Outcome != "success" {
+		t.Errorf("Expected two successful audit entries, got %+v", entries)
+	}
+}
+
+func TestOrphansHandler_Unauthorized(t *testing.T) {
+	client := &orphanClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+	app := &AppState{Manticore: client, AdminToken: "secret"}
+
+	w := httptest.NewRecorder()
+	app.OrphansHandler(w, adminRequest("/api/admin/orphans", "guess"))
+	if w.Code != http.StatusUnauthorized || len(client.deleted) != 0 {
+		t.Errorf("Expected status 401 without deleting, got %d", w.Code)
+	}
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/internal/validation"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// Bounds of the wait parameter of the continue endpoint
+const (
+	defaultContinuationWait = 10 * time.Second
+	maxContinuationWait     = 30 * time.Second
+)
+
+// sendProgressiveSearch answers a progressive hybrid or AI search with full-text results and a
+// continuation token for the final ranking, which keeps being computed after the response is sent
+func (app *AppState) sendProgressiveSearch(w http.ResponseWriter, r *http.Request, engine *search.SearchEngine, query string, mode models.SearchMode, page, limit int, cacheKey string) {
+	generation := app.IndexGeneration()
+	result, refinement, err := engine.SearchProgressive(r.Context(), query, mode, page, limit)
+	if err != nil {
+		log.Printf("Progressive search error (mode: %s): %v", mode, err)
+		app.sendSearchError(w, r, err, mode, cacheKey)
+		return
+	}
+
+	result.IndexGeneration = generation
+
+	if refinement == nil {
+		// The preview failed and the final results were awaited instead
+		if mode == models.SearchModeAI {
+			result = app.addAISearchMetadata(result, false)
+		}
+		app.ResultCache.Put(cacheKey, result)
+		app.sendSearchResponse(w, r, result)
+		return
+	}
+
+	result.Partial = true
+	result.Continuation = app.Continuations.Put(refinement)
+	app.sendSearchResponse(w, r, result)
+}
+
+// SearchContinueHandler handles GET /api/search/continue?token=... requests, returning the final
+// ranking of a progressive search. It waits for up to the wait parameter (default 10s, at most 30s)
+// and answers 202 with the token when the ranking is still being computed.
+func (app *AppState) SearchContinueHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+	// Results may also be sent as MessagePack or protobuf, see sendSearchResponse, and come from
+	// the tenant of the request
+	w.Header().Set("Vary", searchVary)
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow GET requests
+	if r.Method != "GET" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		app.sendValidationError(w, r, validation.Required("token"))
+		return
+	}
+
+	wait := defaultContinuationWait
+	if waitStr := r.URL.Query().Get("wait"); waitStr != "" {
+		parsed, err := time.ParseDuration(waitStr)
+		if err != nil || parsed < 0 || parsed > maxContinuationWait {
+			app.sendValidationError(w, r, validation.OutOfRange("wait", waitStr, 0, int(maxContinuationWait/time.Second)))
+			return
+		}
+		wait = parsed
+	}
+
+	refinement, ok := app.Continuations.Get(token)
+	if !ok {
+		app.sendErrorResponse(w, http.StatusNotFound, "Continuation not found or expired")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), wait)
+	defer cancel()
+	result, err := refinement.Wait(ctx)
+	if !refinement.Done() {
+		w.WriteHeader(http.StatusAccepted)
+		if err := json.NewEncoder(w).Encode(api.APIResponse{
+			Success: true,
+			Data:    api.ContinuationPendingResponse{Continuation: token, Pending: true},
+		}); err != nil {
+			log.Printf("Failed to encode JSON response: %v", err)
+		}
+		return
+	}
+	app.Continuations.Delete(token)
+
+	if err != nil {
+		log.Printf("Progressive search refinement failed: %v", err)
+		app.sendSearchError(w, r, err, "", "")
+		return
+	}
+
+	if result.Mode == string(models.SearchModeAI) {
+		result = app.addAISearchMetadata(result, false)
+	}
+	app.sendSearchResponse(w, r, result)
+}
+
+// sendSearchError maps a search error to a response, serving cached results when the circuit is open
+func (app *AppState) sendSearchError(w http.ResponseWriter, r *http.Request, err error, mode models.SearchMode, cacheKey string) {
+	switch {
+	case manticore.IsCircuitOpenError(err):
+		app.sendCircuitOpenResponse(w, r, cacheKey)
+	case manticore.IsTimeoutError(err):
+		if mode == "" {
+			app.sendErrorResponse(w, http.StatusGatewayTimeout, "Search timed out")
+			return
+		}
+		app.sendErrorResponse(w, http.StatusGatewayTimeout, fmt.Sprintf("Search timed out (mode: %s)", mode))
+	default:
+		app.sendSearchFailureResponse(w, err)
+	}
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// pendingVectorClient holds vector lookups until the search is cancelled or times out
+type pendingVectorClient struct {
+	MockManticoreClient
+}
+
+func (c *pendingVectorClient) GetAllDocumentsWithVectorsContext(ctx context.Context) ([]*models.Document, [][]float64, error) {
+	<-ctx.Done()
+	return nil, nil, ctx.Err()
+}
+
+func TestSearchHandler_Progressive(t *testing.T) {
+	app := &AppState{
+		Manticore:     &MockManticoreClient{connected: true, healthy: true},
+		Continuations: search.NewContinuations(10, search.DefaultContinuationTTL),
+	}
+
+	w := httptest.NewRecorder()
+	app.SearchHandler(w, httptest.NewRequest("GET", "/api/search?query=form&mode=hybrid&progressive=true", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var preview struct {
+		Data struct {
+			Mode         string `json:"mode"`
+			Partial      bool   `json:"partial"`
+			Continuation string `json:"continuation"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if preview.Data.Mode != "fulltext" || !preview.Data.Partial || preview.Data.Continuation == "" {
+		t.Fatalf("Expected a partial full-text preview with a continuation, got %+v", preview.Data)
+	}
+
+	w = httptest.NewRecorder()
+	app.SearchContinueHandler(w, httptest.NewRequest("GET", "/api/search/continue?token="+preview.Data.Continuation+"&wait=5s", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var final struct {
+		Data struct {
+			Mode    string `json:"mode"`
+			Partial bool   `json:"partial"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &final); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if final.Data.Mode != "hybrid" || final.Data.Partial {
+		t.Errorf("Expected final hybrid results, got %+v", final.Data)
+	}
+
+	// Collected continuations are forgotten
+	w = httptest.NewRecorder()
+	app.SearchContinueHandler(w, httptest.NewRequest("GET", "/api/search/continue?token="+preview.Data.Continuation, nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a collected continuation, got %d", w.Code)
+	}
+}
+
+func TestSearchContinueHandler_Pending(t *testing.T) {
+	app := &AppState{
+		Manticore:     &pendingVectorClient{MockManticoreClient{connected: true, healthy: true}},
+		Continuations: search.NewContinuations(10, search.DefaultContinuationTTL),
+	}
+	engine := search.NewSearchEngine(app.Manticore, nil, nil)
+	_, refinement, err := engine.SearchProgressive(context.Background(), "form", models.SearchModeHybrid, 1, 10)
+	if err != nil || refinement == nil {
+		t.Fatalf("Expected a pending refinement, got %v", err)
+	}
+	defer refinement.Cancel()
+	token := app.Continuations.Put(refinement)
+
+	w := httptest.NewRecorder()
+	app.SearchContinueHandler(w, httptest.NewRequest("GET", "/api/search/continue?token="+token+"&wait=0s", nil))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data api.ContinuationPendingResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !response.Data.Pending || response.Data.Continuation != token {
+		t.Errorf("Unexpected pending response: %+v", response.Data)
+	}
+
+	w = httptest.NewRecorder()
+	app.SearchContinueHandler(w, httptest.NewRequest("GET", "/api/search/continue?token="+token+"&wait=1h", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a wait above the maximum, got %d", w.Code)
+	}
+}
+
+func TestSendSearchErrorCategory(t *testing.T) {
+	app := &AppState{}
+	w := httptest.NewRecorder()
+	app.sendSearchError(w, httptest.NewRequest("GET", "/api/search", nil), errors.New("dial tcp 127.0.0.1:9308: connection refused"), models.SearchModeBasic, "")
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status 500, got %d", w.Code)
+	}
+	var response struct {
+		Data api.SearchFailureData `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.ErrorType != api.ErrorTypeSearchFailure || response.Data.ErrorCategory != "network" || !response.Data.RetrySuggested {
+		t.Errorf("Expected a retryable network failure, got %+v", response.Data)
+	}
+}
+
+func TestSendSearchErrorUnsupported(t *testing.T) {
+	app := &AppState{}
+	w := httptest.NewRecorder()
+	err := (&manticore.Capabilities{Major: 6, KNN: true}).RequireAISearch()
+	app.sendSearchError(w, httptest.NewRequest("GET", "/api/search", nil), err, models.SearchModeAI, "")
+	if w.Code != http.StatusNotImplemented {
+		t.Fatalf("Expected status 501, got %d", w.Code)
+	}
+	var response struct {
+		Data api.SearchFailureData `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.ErrorCategory != "unsupported" || response.Data.RetrySuggested {
+		t.Errorf("Expected an unsupported failure that is not retried, got %+v", response.Data)
+	}
+}
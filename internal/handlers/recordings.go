@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// RecordingsHandler handles GET /api/admin/recordings requests, listing the Manticore requests and
+// responses recorded while MANTICORE_DEBUG_RECORD_SAMPLE_RATE is set, newest first. With
+// download=true the response is sent as an attachment. Requires the admin token.
+func (app *AppState) RecordingsHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow GET requests
+	if r.Method != "GET" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Recorded bodies hold the documents and queries of every tenant
+	if !app.authorizeAdmin(w, r) {
+		return
+	}
+
+	if app.Manticore == nil {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Manticore client is not initialized")
+		return
+	}
+
+	recordings := app.Manticore.GetRecordings()
+	response := api.RecordingsResponse{
+		Enabled:    recordings.Config.Enabled(),
+		SampleRate: recordings.Config.SampleRate,
+		Capacity:   recordings.Config.Capacity,
+		Recorded:   recordings.Recorded,
+		Exchanges:  make([]api.RecordedExchange, 0, len(recordings.Exchanges)),
+	}
+	for _, exchange := range recordings.Exchanges {
+		response.Exchanges = append(response.Exchanges, api.RecordedExchange{
+			ID:                exchange.ID,
+			Time:              exchange.Time,
+			Method:            exchange.Method,
+			URL:               exchange.URL,
+			RequestBody:       exchange.RequestBody,
+			RequestTruncated:  exchange.RequestTruncated,
+			StatusCode:        exchange.StatusCode,
+			ResponseBody:      exchange.ResponseBody,
+			ResponseTruncated: exchange.ResponseTruncated,
+			Duration:          exchange.Duration.String(),
+			Error:             exchange.Error,
+		})
+	}
+	response.Count = len(response.Exchanges)
+
+	if r.URL.Query().Get("download") == "true" {
+		w.Header().Set("Content-Disposition", `attachment; filename="manticore-recordings.json"`)
+	}
+	app.sendSuccessResponse(w, response)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+func TestRecordingsHandler(t *testing.T) {
+	client := &MockManticoreClient{connected: true, healthy: true, recordings: manticore.Recordings{
+		Config:   manticore.RecordingConfig{SampleRate: 0.5, Capacity: 10},
+		Recorded: 3,
+		Exchanges: []manticore.RecordedExchange{{
+			ID:           3,
+			Method:       "POST",
+			URL:          "http://localhost:9308/search",
+			RequestBody:  `{"table":"documents"}`,
+			StatusCode:   200,
+			ResponseBody: `{"hits":{"total":0}}`,
+			Duration:     15 * time.Millisecond,
+		}},
+	}}
+	app := &AppState{Manticore: client, AdminToken: "secret"}
+
+	req := httptest.NewRequest("GET", "/api/admin/recordings?download=true", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	app.RecordingsHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if w.Header().Get("Content-Disposition") == "" {
+		t.Error("Expected the download to be sent as an attachment")
+	}
+
+	var response struct {
+		Data api.RecordingsResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	data := response.Data
+	if !data.Enabled || data.SampleRate != 0.5 || data.Recorded != 3 || data.Count != 1 {
+		t.Errorf("Unexpected recordings summary: %+v", data)
+	}
+	if exchange := data.Exchanges[0]; exchange.RequestBody != `{"table":"documents"}` || exchange.Duration != "15ms" {
+		t.Errorf("Unexpected exchange: %+v", exchange)
+	}
+}
+
+func TestRecordingsHandlerDisabled(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}, AdminToken: "secret"}
+
+	req := httptest.NewRequest("GET", "/api/admin/recordings", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	app.RecordingsHandler(w, req)
+
+	var response struct {
+		Data api.RecordingsResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.Enabled || response.Data.Exchanges == nil {
+		t.Errorf("Expected recording disabled with an empty list, got %+v", response.Data)
+	}
+	if w.Header().Get("Content-Disposition") != "" {
+		t.Error("Expected no attachment without download=true")
+	}
+}
+
+func TestRecordingsHandlerRequiresAdminToken(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}, AdminToken: "secret"}
+
+	req := httptest.NewRequest("GET", "/api/admin/recordings", nil)
+	w := httptest.NewRecorder()
+	app.RecordingsHandler(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// ReembedHandler handles /api/admin/reembed requests. POST with {"model": ..., "similarity": ...}
+// starts copying the documents into a table embedded by that model in the background, answering
+// with the progress; searches keep using the current table until the copy completes and then
+// switch to the new one. GET reports the progress of the last run. It requires the admin token.
+func (app *AppState) ReembedHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		if !app.authorizeAdmin(w, r) {
+			return
+		}
+		app.sendSuccessResponse(w, app.reembedStatus())
+	case "POST":
+		app.startReembed(w, r)
+	default:
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (app *AppState) startReembed(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	if !app.authorizeAdmin(w, r) {
+		app.recordAudit(r, "reembed", nil, fmt.Errorf("unauthorized"), startTime)
+		return
+	}
+
+	var request api.ReembedRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			app.sendErrorResponse(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body too large (at most %d bytes)", maxBytesErr.Limit))
+			return
+		}
+		app.sendErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid reembed request: %v", err))
+		return
+	}
+	if err := models.ValidateAIModel(request.Model); err != nil {
+		app.sendErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid model: %v", err))
+		return
+	}
+	if request.Similarity != "" {
+		if err := models.ValidateSimilarity(request.Similarity); err != nil {
+			app.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// Check if Manticore is available
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Manticore Search is not available")
+		return
+	}
+
+	aiConfig := models.DefaultAISearchConfig()
+	if current := app.CurrentAIConfig(); current != nil {
+		config := *current
+		aiConfig = &config
+	}
+	aiConfig.Model = request.Model
+	if request.Similarity != "" {
+		aiConfig.Similarity = request.Similarity
+	}
+
+	app.reembedMu.Lock()
+	if app.reembed.State == "running" {
+		app.reembedMu.Unlock()
+		app.sendErrorResponse(w, http.StatusConflict, fmt.Sprintf("Re-embedding with %s is already running", app.reembed.Model))
+		return
+	}
+	app.reembed = api.ReembedStatus{State: "running", Model: aiConfig.Model, StartedAt: &startTime}
+	status := app.reembed
+	app.reembedMu.Unlock()
+
+	// The copy outlives the request, so it is audited once it ends
+	actor, remoteAddr := requestActor(r), requestRemoteAddr(r)
+	go app.runReembed(aiConfig, actor, remoteAddr, startTime)
+
+	app.sendSuccessResponse(w, status)
+}
+
+// runReembed moves the documents to a table embedded as aiConfig says and, once they are, makes
+// aiConfig the AI configuration
+func (app *AppState) runReembed(aiConfig *models.AISearchConfig, actor, remoteAddr string, startTime time.Time) {
+	result, err := app.Manticore.ReembedDocuments(context.Background(), aiConfig, func(progress manticore.ReembedProgress) {
+		app.reembedMu.Lock()
+		app.reembed.Copied, app.reembed.Total = progress.Copied, progress.Total
+		app.reembedMu.Unlock()
+	})
+
+	// Searches use the new model before the run is reported completed
+	if err == nil {
+		app.SetAIConfig(aiConfig)
+		app.IndexChanged()
+		log.Printf("Re-embedded %d documents with %s in %v", result.Documents, aiConfig.Model, time.Since(startTime))
+	} else {
+		log.Printf("Re-embedding with %s failed: %v", aiConfig.Model, err)
+	}
+
+	params := map[string]interface{}{"model": aiConfig.Model, "similarity": aiConfig.Similarity}
+	if result != nil {
+		params["table"] = result.Table
+		params["documents"] = result.Documents
+	}
+	app.recordAuditAs(actor, remoteAddr, "reembed", params, err, startTime)
+
+	finishedAt := time.Now()
+	app.reembedMu.Lock()
+	defer app.reembedMu.Unlock()
+	app.reembed.FinishedAt = &finishedAt
+	if err != nil {
+		app.reembed.State = "failed"
+		app.reembed.Error = err.Error()
+		return
+	}
+	app.reembed.State = "completed"
+	app.reembed.Table = result.Table
+	app.reembed.Copied = result.Documents
+}
+
+// RestoreReembeddedAIConfig makes the model and similarity of the documents table the AI
+// configuration when re-embedding created the table, given its generation from MigrateSchema.
+// Re-embedding only switches the configuration in memory, so without this a restart would go back
+// to the configured model while the table embeds with another one.
+func (app *AppState) RestoreReembeddedAIConfig(generation int64) {
+	if generation == 0 || app.Manticore == nil {
+		return
+	}
+	embeddingModels, err := app.Manticore.EmbeddingModels("documents")
+	if err != nil {
+		log.Printf("Warning: Failed to read the embedding model of the re-embedded documents table: %v", err)
+		return
+	}
+
+	for _, model := range embeddingModels {
+		if model.Column != "content_vector" {
+			continue
+		}
+		aiConfig := models.DefaultAISearchConfig()
+		if current := app.CurrentAIConfig(); current != nil {
+			config := *current
+			aiConfig = &config
+		}
+		aiConfig.Model = model.Model
+		switch model.Similarity {
+		case "l2":
+			aiConfig.Similarity = models.SimilarityL2
+		case "ip":
+			aiConfig.Similarity = models.SimilarityDot
+		case "cosine":
+			aiConfig.Similarity = models.SimilarityCosine
+		}
+		app.SetAIConfig(aiConfig)
+		log.Printf("Using AI model %s of the re-embedded documents table", aiConfig.Model)
+		return
+	}
+}
+
+// reembedStatus returns the progress of the last re-embedding
+func (app *AppState) reembedStatus() api.ReembedStatus {
+	app.reembedMu.Lock()
+	defer app.reembedMu.Unlock()
+	if app.reembed.State == "" {
+		return api.ReembedStatus{State: "idle"}
+	}
+	return app.reembed
+}
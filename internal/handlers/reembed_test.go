@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/audit"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+func reembedRequest(method, body, token string) *http.Request {
+	r := httptest.NewRequest(method, "/api/admin/reembed", strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r
+}
+
+func TestReembedHandler(t *testing.T) {
+	auditLog, _ := audit.New("", 10)
+	app := &AppState{
+		Manticore:  &MockManticoreClient{connected: true, healthy: true},
+		AdminToken: "secret",
+		Audit:      auditLog,
+		AIConfig:   models.DefaultAISearchConfig(),
+	}
+
+	w := httptest.NewRecorder()
+	app.ReembedHandler(w, reembedRequest("GET", "", "secret"))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"state":"idle"`) {
+		t.Fatalf("Expected an idle status, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	app.ReembedHandler(w, reembedRequest("POST", `{"model": "sentence-transformers/all-mpnet-base-v2", "similarity": "cosine"}`, "secret"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var status api.ReembedStatus
+	deadline := time.Now().Add(2 * time.Second)
+	for status = app.reembedStatus(); status.State == "running" && time.Now().Before(deadline); status = app.reembedStatus() {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status.State != "completed" || status.Copied != 2 || status.Total != 2 || status.Table != "documents_g1" || status.FinishedAt == nil {
+		t.Fatalf("Unexpected status after re-embedding: %+v", status)
+	}
+	if aiConfig := app.CurrentAIConfig(); aiConfig.Model != "sentence-transformers/all-mpnet-base-v2" || aiConfig.Similarity != "cosine" || app.IndexGeneration() != 1 {
+		t.Errorf("Expected the new model to be configured, got %+v", aiConfig)
+	}
+
+	w = httptest.NewRecorder()
+	app.ReembedHandler(w, reembedRequest("GET", "", "secret"))
+	var response struct {
+		Data api.ReembedStatus `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.State != "completed" || response.Data.Model != "sentence-transformers/all-mpnet-base-v2" {
+		t.Errorf("Unexpected status response: %+v", response.Data)
+	}
+
+	entries := auditLog.Recent(10, "reembed")
+	if len(entries) != 1 || entries[0].Outcome != "success" || entries[0].Parameters["table"] != "documents_g1" {
+		t.Errorf("Expected a successful audit entry, got %+v", entries)
+	}
+}
+
+func TestReembedHandler_Invalid(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}, AdminToken: "secret", AIConfig: models.DefaultAISearchConfig()}
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		token  string
+		status int
+	}{
+		{"unauthorized", "POST", `{"model": "sentence-transformers/all-MiniLM-L6-v2"}`, "guess", http.StatusUnauthorized},
+		{"unauthorized status", "GET", "", "", http.StatusUnauthorized},
+		{"missing model", "POST", `{}`, "secret", http.StatusBadRequest},
+		{"unknown field", "POST", `{"model": "sentence-transformers/all-MiniLM-L6-v2", "tables": "all"}`, "secret", http.StatusBadRequest},
+		{"invalid similarity", "POST", `{"model": "sentence-transformers/all-MiniLM-L6-v2", "similarity": "manhattan"}`, "secret", http.StatusBadRequest},
+		{"method", "DELETE", "", "secret", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			app.ReembedHandler(w, reembedRequest(tt.method, tt.body, tt.token))
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+	if status := app.reembedStatus(); status.State != "idle" {
+		t.Errorf("Expected invalid requests to start nothing, got %+v", status)
+	}
+}
+
+func TestReembedHandler_Running(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}, AdminToken: "secret"}
+	app.reembed = api.ReembedStatus{State: "running", Model: "sentence-transformers/all-MiniLM-L6-v2"}
+
+	w := httptest.NewRecorder()
+	app.ReembedHandler(w, reembedRequest("POST", `{"model": "sentence-transformers/all-mpnet-base-v2"}`, "secret"))
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 while re-embedding, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestReembedHandler_SearchesDuringReembedding(t *testing.T) {
+	app := &AppState{
+		Manticore:  &MockManticoreClient{connected: true, healthy: true},
+		AdminToken: "secret",
+		AIConfig:   models.DefaultAISearchConfig(),
+	}
+
+	w := httptest.NewRecorder()
+	app.ReembedHandler(w, reembedRequest("POST", `{"model": "sentence-transformers/all-mpnet-base-v2"}`, "secret"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	// Searches read the AI configuration while re-embedding switches it, which -race checks
+	for status := app.reembedStatus(); status.State == "running"; status = app.reembedStatus() {
+		if app.getAIModel() == "" {
+			t.Fatal("Expected an AI model")
+		}
+	}
+	if model := app.getAIModel(); model != "sentence-transformers/all-mpnet-base-v2" {
+		t.Errorf("Expected searches to use the new model, got %s", model)
+	}
+	if app.AIConfig.Model != models.DefaultAISearchConfig().Model {
+		t.Errorf("Expected the startup configuration to be left alone, got %+v", app.AIConfig)
+	}
+}
+
+func TestRestoreReembeddedAIConfig(t *testing.T) {
+	startup := &models.AISearchConfig{Model: "configured-model", Similarity: models.SimilarityDot, Enabled: true, Timeout: time.Second}
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}, AIConfig: startup}
+
+	// The first generation was created with the configured model, which stays
+	app.RestoreReembeddedAIConfig(0)
+	if app.CurrentAIConfig() != startup {
+		t.Fatalf("Expected the configured model for the first generation, got %+v", app.CurrentAIConfig())
+	}
+
+	app.RestoreReembeddedAIConfig(1)
+	aiConfig := app.CurrentAIConfig()
+	if aiConfig.Model != "sentence-transformers/all-MiniLM-L6-v2" || aiConfig.Similarity != models.SimilarityCosine {
+		t.Errorf("Expected the model and similarity of the re-embedded table, got %+v", aiConfig)
+	}
+	if !aiConfig.Enabled || aiConfig.Timeout != time.Second || startup.Model != "configured-model" {
+		t.Errorf("Expected the rest of the configuration to be kept, got %+v and %+v", aiConfig, startup)
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// ResilienceHandler handles GET /api/status/resilience requests
+func (app *AppState) ResilienceHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow GET requests
+	if r.Method != "GET" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if app.Manticore == nil {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Manticore client is not initialized")
+		return
+	}
+
+	app.sendSuccessResponse(w, buildResilienceResponse(app.Manticore.GetResilienceStats()))
+}
+
+// buildResilienceResponse converts client statistics into the API representation
+func buildResilienceResponse(stats manticore.ResilienceStats) api.ResilienceResponse {
+	response := api.ResilienceResponse{
+		CircuitBreaker: convertCircuitBreakerStats(stats.CircuitBreaker),
+		Retry:          convertRetryStats(stats.Retry),
+		Transitions:    convertTransitions(stats.Transitions),
+		ConnectionPool: api.ConnectionPoolStatus{
+			MaxIdleConnsPerHost: stats.ConnectionPool.MaxIdleConnsPerHost,
+			MaxConnsPerHost:     stats.ConnectionPool.MaxConnsPerHost,
+			IdleConnTimeout:     stats.ConnectionPool.IdleConnTimeout.String(),
+			Open:                stats.ConnectionPool.Open,
+			Active:              stats.ConnectionPool.Active,
+			Idle:                stats.ConnectionPool.Idle,
+			Dials:               stats.ConnectionPool.Dials,
+			Reused:              stats.ConnectionPool.Reused,
+			Waits:               stats.ConnectionPool.Waits,
+			WaitTime:            stats.ConnectionPool.WaitTime.String(),
+			Addresses:           stats.ConnectionPool.Addresses,
+			DNSRefreshes:        stats.ConnectionPool.DNSRefreshes,
+			AddressChanges:      stats.ConnectionPool.AddressChanges,
+			Rebuilds:            stats.ConnectionPool.Rebuilds,
+		},
+	}
+
+	if len(stats.CircuitBreakerByClass) > 0 {
+		response.CircuitBreakerByClass = make(map[string]api.CircuitBreakerStatus, len(stats.CircuitBreakerByClass))
+		for class, classStats := range stats.CircuitBreakerByClass {
+			response.CircuitBreakerByClass[string(class)] = convertCircuitBreakerStats(classStats)
+		}
+	}
+
+	if len(stats.RetryByClass) > 0 {
+		response.RetryByClass = make(map[string]api.RetryStatus, len(stats.RetryByClass))
+		for class, classStats := range stats.RetryByClass {
+			response.RetryByClass[string(class)] = convertRetryStats(classStats)
+		}
+	}
+	if stats.FaultInjection != nil {
+		response.FaultInjection = &api.FaultInjectionStatus{
+			Requests:  stats.FaultInjection.Requests,
+			Latencies: stats.FaultInjection.Latencies,
+			Errors:    stats.FaultInjection.Errors,
+			Resets:    stats.FaultInjection.Resets,
+		}
+	}
+	if response.Transitions == nil {
+		response.Transitions = []api.CircuitBreakerTransition{}
+	}
+	return response
+}
+
+// convertCircuitBreakerStats converts circuit breaker statistics into the API representation
+func convertCircuitBreakerStats(cb manticore.CircuitBreakerStats) api.CircuitBreakerStatus {
+	return api.CircuitBreakerStatus{
+		State:                cb.State.String(),
+		FailureRate:          cb.CurrentFailureRate,
+		ConsecutiveFailures:  cb.ConsecutiveFailures,
+		ConsecutiveSuccesses: cb.ConsecutiveSuccesses,
+		TotalRequests:        cb.TotalRequests,
+		TotalFailures:        cb.TotalFailures,
+		TotalSuccesses:       cb.TotalSuccesses,
+		TotalRejections:      cb.TotalRejections,
+		StateChanges:         cb.StateChanges,
+		LastStateChange:      cb.LastStateChange,
+		LastFailureTime:      cb.LastFailureTime,
+	}
+}
+
+// convertTransitions converts circuit breaker transitions into the API representation
+func convertTransitions(transitions []manticore.CircuitBreakerTransition) []api.CircuitBreakerTransition {
+	var result []api.CircuitBreakerTransition
+	for _, t := range transitions {
+		result = append(result, api.CircuitBreakerTransition{
+			From:                t.From,
+			To:                  t.To,
+			Reason:              t.Reason,
+			Endpoint:            t.Endpoint,
+			Timestamp:           t.Timestamp,
+			ConsecutiveFailures: t.ConsecutiveFailures,
+			FailureRate:         t.FailureRate,
+		})
+	}
+	return result
+}
+
+// convertRetryStats converts retry statistics into the API representation
+func convertRetryStats(retry manticore.RetryStats) api.RetryStatus {
+	status := api.RetryStatus{
+		MaxAttempts:          retry.MaxAttempts,
+		BaseDelay:            retry.BaseDelay.String(),
+		MaxDelay:             retry.MaxDelay.String(),
+		RetryableErrors:      retry.RetryableErrors,
+		TotalOperations:      retry.TotalOperations,
+		TotalRetries:         retry.TotalRetries,
+		SucceededAfterRetry:  retry.SucceededAfterRetry,
+		RetriesExhausted:     retry.RetriesExhausted,
+		NonRetryableFailures: retry.NonRetryableFailures,
+		RetriesByErrorType:   retry.RetriesByErrorType,
+		LastRetryTime:        retry.LastRetryTime,
+		LastRetryError:       retry.LastRetryError,
+	}
+	if status.RetriesByErrorType == nil {
+		status.RetriesByErrorType = map[string]int64{}
+	}
+	status.Outcomes = make([]api.RetryOutcome, 0, len(retry.Outcomes))
+	for _, outcome := range retry.Outcomes {
+		status.Outcomes = append(status.Outcomes, api.RetryOutcome{
+			Endpoint:  outcome.Endpoint,
+			ErrorType: outcome.ErrorType,
+			Outcome:   string(outcome.Outcome),
+			Count:     outcome.Count,
+		})
+	}
+	return status
+}
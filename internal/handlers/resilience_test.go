@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+)
+
+func TestResilienceHandler(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+
+	req := httptest.NewRequest("GET", "/api/status/resilience", nil)
+	w := httptest.NewRecorder()
+	app.ResilienceHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response struct {
+		Success bool `json:"success"`
+		Data    struct {
+			CircuitBreaker struct {
+				State string `json:"state"`
+			} `json:"circuit_breaker"`
+			Retry struct {
+				RetriesByErrorType map[string]int64 `json:"retries_by_error_type"`
+			} `json:"retry"`
+			Transitions []interface{} `json:"transitions"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.CircuitBreaker.State != "CLOSED" {
+		t.Errorf("Expected CLOSED state, got %q", response.Data.CircuitBreaker.State)
+	}
+	if response.Data.Retry.RetriesByErrorType == nil || response.Data.Transitions == nil {
+		t.Errorf("Expected empty collections instead of null")
+	}
+}
+
+func TestResilienceHandlerWithoutClient(t *testing.T) {
+	app := &AppState{}
+
+	req := httptest.NewRequest("GET", "/api/status/resilience", nil)
+	w := httptest.NewRecorder()
+	app.ResilienceHandler(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", w.Code)
+	}
+}
+
+func TestBuildResilienceResponse(t *testing.T) {
+	now := time.Now()
+	response := buildResilienceResponse(manticore.ResilienceStats{
+		CircuitBreaker: manticore.CircuitBreakerStats{State: manticore.CircuitBreakerOpen, TotalRejections: 4},
+		Retry: manticore.RetryStats{
+			BaseDelay:          500 * time.Millisecond,
+			RetriesByErrorType: map[string]int64{"timeout": 3},
+		},
+		Transitions:    []manticore.CircuitBreakerTransition{{From: "CLOSED", To: "OPEN", Timestamp: now}},
+		FaultInjection: &manticore.FaultStats{Requests: 10, Errors: 2},
+	})
+
+	if response.CircuitBreaker.State != "OPEN" || response.CircuitBreaker.TotalRejections != 4 {
+		t.Errorf("Unexpected circuit breaker status: %+v", response.CircuitBreaker)
+	}
+	if response.Retry.BaseDelay != "500ms" || response.Retry.RetriesByErrorType["timeout"] != 3 {
+		t.Errorf("Unexpected retry status: %+v", response.Retry)
+	}
+	if len(response.Transitions) != 1 || response.Transitions[0].To != "OPEN" {
+		t.Errorf("Unexpected transitions: %+v", response.Transitions)
+	}
+	if response.FaultInjection == nil || response.FaultInjection.Requests != 10 || response.FaultInjection.Errors != 2 {
+		t.Errorf("Unexpected fault injection status: %+v", response.FaultInjection)
+	}
+	if buildResilienceResponse(manticore.ResilienceStats{}).FaultInjection != nil {
+		t.Error("Expected no fault injection status without injected faults")
+	}
+}
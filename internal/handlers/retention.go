@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/retention"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// RetentionHandler handles POST /api/admin/retention requests, deleting the indexed documents the
+// retention policy expires, or only reporting them when dry_run=true or the policy is a dry run.
+// It requires the admin token.
+func (app *AppState) RetentionHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow POST requests
+	if r.Method != "POST" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	startTime := time.Now()
+	dryRun := r.URL.Query().Get("dry_run") == "true" || (app.Retention != nil && app.Retention.DryRun)
+	auditParams := map[string]interface{}{"dry_run": dryRun, "policy": app.Retention.String()}
+	if !app.authorizeAdmin(w, r) {
+		app.recordAudit(r, "retention", auditParams, fmt.Errorf("unauthorized"), startTime)
+		return
+	}
+
+	// Check if Manticore is available
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Manticore Search is not available")
+		return
+	}
+
+	outcome, err := app.ApplyRetention(dryRun)
+	addRetentionParams(auditParams, outcome)
+	app.recordAudit(r, "retention", auditParams, err, startTime)
+	if err != nil {
+		log.Printf("Retention run failed: %v", err)
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to apply the retention policy: %v", err))
+		return
+	}
+
+	app.sendSuccessResponse(w, app.convertRetention(outcome))
+}
+
+// ApplyRetention deletes the indexed documents the retention policy expires from every data
+// table, or only reports them on a dry run, see retention.Apply. A run that deleted documents,
+// or failed part way through deleting them, advances the index generation.
+func (app *AppState) ApplyRetention(dryRun bool) (*retention.Outcome, error) {
+	outcome, err := retention.Apply(app.Manticore, manticore.DataTables, app.Retention, dryRun)
+	if outcome != nil && (outcome.Deleted > 0 || err != nil) {
+		app.IndexChanged()
+	}
+	return outcome, err
+}
+
+// RetainDocuments drops the documents the retention policy expires before they are indexed, so
+// a reindex does not add back what a retention run deleted. It returns the kept documents and
+// how many were dropped.
+func (app *AppState) RetainDocuments(documents []*models.Document) ([]*models.Document, int) {
+	kept, expired := app.Retention.Filter(documents, time.Now())
+	if expired > 0 {
+		log.Printf("Retention policy (%s) kept %d of %d documents from being indexed", app.Retention, expired, len(documents))
+	}
+	return kept, expired
+}
+
+// scheduledRetention applies the retention policy, only reporting the expired documents when
+// the policy is a dry run
+func (app *AppState) scheduledRetention(ctx context.Context) error {
+	startTime := time.Now()
+	dryRun := app.Retention != nil && app.Retention.DryRun
+	params := map[string]interface{}{"reason": "schedule", "dry_run": dryRun, "policy": app.Retention.String()}
+
+	var outcome *retention.Outcome
+	var err error
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		err = fmt.Errorf("Manticore Search is not available")
+	} else {
+		outcome, err = app.ApplyRetention(dryRun)
+	}
+	addRetentionParams(params, outcome)
+	app.recordAuditAs("system", "", "retention", params, err, startTime)
+	if outcome != nil && dryRun && len(outcome.Expired)+len(outcome.Excess) > 0 {
+		log.Printf("Retention dry run: %d expired and %d excess documents would be deleted", len(outcome.Expired), len(outcome.Excess))
+	}
+	return err
+}
+
+// addRetentionParams adds the outcome of a retention run to audit parameters, listing up to
+// maxListedRejections of the deleted ids
+func addRetentionParams(params map[string]interface{}, outcome *retention.Outcome) {
+	if outcome == nil {
+		return
+	}
+	params["documents"] = outcome.Documents
+	params["expired"] = len(outcome.Expired)
+	params["excess"] = len(outcome.Excess)
+	params["deleted"] = outcome.Deleted
+	if outcome.Deleted > 0 {
+		ids := append(append([]int64{}, outcome.Expired...), outcome.Excess...)
+		params["deleted_ids"] = ids[:min(len(ids), maxListedRejections)]
+	}
+}
+
+// convertRetention converts the outcome of a retention run to its API form
+func (app *AppState) convertRetention(outcome *retention.Outcome) api.RetentionResponse {
+	return api.RetentionResponse{
+		CheckedAt: outcome.CheckedAt,
+		DryRun:    outcome.DryRun,
+		Policy:    app.Retention.String(),
+		Documents: outcome.Documents,
+		Expired:   listRetentionIDs(outcome.Expired),
+		Excess:    listRetentionIDs(outcome.Excess),
+		Deleted:   outcome.Deleted,
+	}
+}
+
+// listRetentionIDs counts ids, listing up to maxListedRejections of the lowest
+func listRetentionIDs(ids []int64) api.ConsistencyIDs {
+	return api.ConsistencyIDs{Count: len(ids), IDs: ids[:min(len(ids), maxListedRejections)]}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/audit"
+	"github.com/ad/manticoresearch-go/internal/maintenance"
+	"github.com/ad/manticoresearch-go/internal/retention"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// retentionClient has a document 1 updated long ago and two recent documents 2 and 3
+type retentionClient struct {
+	MockManticoreClient
+	deleted map[string][]int64
+}
+
+func (c *retentionClient) GetDocumentTimes() (map[int64]int64, error) {
+	now := time.Now()
+	return map[int64]int64{1: now.Add(-400 * 24 * time.Hour).Unix(), 2: now.Unix(), 3: now.Add(-time.Hour).Unix()}, nil
+}
+
+func (c *retentionClient) DeleteDocuments(table string, ids []int64) error {
+	c.deleted[table] = append(c.deleted[table], ids...)
+	return nil
+}
+
+func TestRetentionHandler(t *testing.T) {
+	client := &retentionClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}, deleted: make(map[string][]int64)}
+	auditLog, _ := audit.New("", 10)
+	app := &AppState{
+		Manticore:  client,
+		AdminToken: "secret",
+		Audit:      auditLog,
+		Retention:  &retention.Policy{MaxAge: 365 * 24 * time.Hour, MaxDocuments: 1},
+	}
+
+	w := httptest.NewRecorder()
+	app.RetentionHandler(w, adminRequest("/api/admin/retention?dry_run=true", "secret"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data api.RetentionResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !response.Data.DryRun || response.Data.Documents != 3 || response.Data.Expired.Count != 1 || response.Data.Excess.Count != 1 || response.Data.Excess.IDs[0] != 3 {
+		t.Errorf("Unexpected dry run response: %+v", response.Data)
+	}
+	if len(client.deleted) != 0 || app.IndexGeneration() != 0 {
+		t.Error("Expected a dry run to change nothing")
+	}
+
+	w = httptest.NewRecorder()
+	app.RetentionHandler(w, adminRequest("/api/admin/retention", "secret"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ids := client.deleted["documents_vector"]; len(ids) != 2 || ids[0] != 1 || ids[1] != 3 || app.IndexGeneration() != 1 {
+		t.Errorf("Expected documents 1 and 3 to be deleted, got %v", client.deleted)
+	}
+
+	entries := auditLog.Recent(10, "retention")
+	if len(entries) != 2 || entries[0].Outcome != "success" || entries[0].Parameters["deleted"] != 2 {
+		t.Errorf("Expected two successful audit entries, got %+v", entries)
+	}
+}
+
+func TestRetentionHandler_Unauthorized(t *testing.T) {
+	client := &retentionClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}, deleted: make(map[string][]int64)}
+	app := &AppState{Manticore: client, AdminToken: "secret", Retention: &retention.Policy{MaxDocuments: 1}}
+
+	w := httptest.NewRecorder()
+	app.RetentionHandler(w, adminRequest("/api/admin/retention", "guess"))
+	if w.Code != http.StatusUnauthorized || len(client.deleted) != 0 {
+		t.Errorf("Expected status 401 without deleting, got %d", w.Code)
+	}
+}
+
+func TestScheduledRetentionDryRun(t *testing.T) {
+	client := &retentionClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}, deleted: make(map[string][]int64)}
+	auditLog, _ := audit.New("", 10)
+	app := &AppState{Manticore: client, Audit: auditLog, Retention: &retention.Policy{MaxDocuments: 2, DryRun: true}}
+
+	if err := app.MaintenanceTasks()[maintenance.TaskRetention](context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(client.deleted) != 0 {
+		t.Errorf("Expected a dry-run policy to delete nothing, got %v", client.deleted)
+	}
+	entries := auditLog.Recent(10, "retention")
+	if len(entries) != 1 || entries[0].Actor != "system" || entries[0].Parameters["excess"] != 1 || entries[0].Parameters["dry_run"] != true {
+		t.Errorf("Expected a system dry-run audit entry, got %+v", entries)
+	}
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/savedsearch"
+	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/internal/validation"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// SavedSearchesHandler handles /api/saved-searches requests: GET lists saved searches, POST creates
+// one from the name, query, mode, status, tags, tags_mode, webhook and email parameters, and
+// DELETE removes the one given by the id parameter. Setting webhook or email requires the admin
+// token, since the scheduler delivers alerts to them.
+func (app *AppState) SavedSearchesHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if app.SavedSearches == nil {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Saved searches are not available")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		app.listSavedSearches(w)
+	case "POST":
+		app.createSavedSearch(w, r)
+	case "DELETE":
+		app.deleteSavedSearch(w, r)
+	default:
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (app *AppState) listSavedSearches(w http.ResponseWriter) {
+	searches := app.SavedSearches.List()
+
+	response := api.SavedSearchListResponse{
+		SavedSearches: make([]api.SavedSearch, 0, len(searches)),
+	}
+	for _, s := range searches {
+		response.SavedSearches = append(response.SavedSearches, savedSearchResponse(s))
+	}
+	response.Count = len(response.SavedSearches)
+
+	app.sendSuccessResponse(w, response)
+}
+
+func (app *AppState) createSavedSearch(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	var errs validation.Errors
+	query := strings.TrimSpace(params.Get("query"))
+	if query == "" {
+		errs.Add("query", validation.Required("query"))
+	} else {
+		errs.Add("query", search.ValidateQuery(query))
+	}
+
+	name := strings.TrimSpace(params.Get("name"))
+	if name == "" {
+		name = query
+	}
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		errs.Add("name", validation.InvalidCharacters("name"))
+	}
+
+	modeStr := strings.TrimSpace(params.Get("mode"))
+	if modeStr == "" {
+		modeStr = "basic"
+	}
+	mode, err := search.ValidateSearchMode(modeStr)
+	errs.Add("mode", err)
+
+	// Filters are validated now and parsed again on every run
+	_, err = search.ParseStatuses(params.Get("status"))
+	errs.Add("status", err)
+	_, err = search.ParseTags(params.Get("tags"), params.Get("tags_mode"))
+	errs.Add("tags_mode", err)
+
+	webhook := strings.TrimSpace(params.Get("webhook"))
+	if webhook != "" {
+		parsed, err := url.Parse(webhook)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errs.Add("webhook", validation.InvalidFormat("webhook", webhook, "http_url", "https_url"))
+		}
+	}
+
+	email := strings.TrimSpace(params.Get("email"))
+	if email != "" {
+		if address, err := mail.ParseAddress(email); err != nil {
+			errs.Add("email", validation.InvalidFormat("email", email, "email_address"))
+		} else {
+			email = address.Address
+		}
+	}
+
+	if len(errs) > 0 {
+		app.sendValidationError(w, r, errs)
+		return
+	}
+
+	// The server posts and mails alerts to these, so callers could otherwise reach internal
+	// addresses or relay mail through it
+	if (webhook != "" || email != "") && !app.authorizeAdmin(w, r) {
+		return
+	}
+
+	startTime := time.Now()
+	created, err := app.SavedSearches.Create(savedsearch.SavedSearch{
+		Name:     name,
+		Query:    query,
+		Mode:     string(mode),
+		Status:   params.Get("status"),
+		Tags:     params.Get("tags"),
+		TagsMode: params.Get("tags_mode"),
+		Webhook:  webhook,
+		Email:    email,
+	})
+	app.recordAudit(r, "saved_search_create", map[string]interface{}{"id": created.ID, "name": name, "query": query, "mode": string(mode)}, err, startTime)
+	if errors.Is(err, savedsearch.ErrTooManySearches) {
+		app.sendErrorResponse(w, http.StatusConflict, fmt.Sprintf("At most %d saved searches can be stored; delete one first", savedsearch.MaxSearches))
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to create saved search: %v", err)
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create saved search: %v", err))
+		return
+	}
+
+	app.sendSuccessResponse(w, savedSearchResponse(created))
+}
+
+func (app *AppState) deleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil || id <= 0 {
+		app.sendValidationError(w, r, validation.TooSmall("id", r.URL.Query().Get("id"), 1))
+		return
+	}
+
+	startTime := time.Now()
+	deleted, err := app.SavedSearches.Delete(id)
+	app.recordAudit(r, "saved_search_delete", map[string]interface{}{"id": id}, err, startTime)
+	if err != nil {
+		log.Printf("Failed to delete saved search %d: %v", id, err)
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to delete saved search: %v", err))
+		return
+	}
+	if !deleted {
+		app.sendErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Saved search %d not found", id))
+		return
+	}
+
+	app.sendSuccessResponse(w, api.SavedSearchDeleteResponse{ID: id, Deleted: true})
+}
+
+// RunSavedSearch is the savedsearch.Runner used by the scheduler. It searches like the search
+// endpoint, degrading AI searches to hybrid when AI search is unavailable.
+func (app *AppState) RunSavedSearch(ctx context.Context, s savedsearch.SavedSearch) ([]models.SearchResult, error) {
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		return nil, fmt.Errorf("Manticore Search is not available")
+	}
+
+	mode, err := search.ValidateSearchMode(s.Mode)
+	if err != nil {
+		return nil, err
+	}
+	if mode.UsesAI() {
+		if err := app.validateAISearchAvailability(); err != nil {
+			mode = models.SearchModeHybrid
+		}
+	}
+
+	statuses, err := search.ParseStatuses(s.Status)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := search.ParseTags(s.Tags, s.TagsMode)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := search.NewSearchEngine(app.Manticore, app.Vectorizer, app.CurrentAIConfig()).WithStatuses(statuses).WithTags(tags).WithRescoreWindow(app.RescoreWindow).WithSpellTolerance(app.SpellTolerance).WithStopwords(app.Stopwords).WithNormalizer(app.Normalizer).WithBoosts(app.Boosts).WithFeatures(app.Features).WithBlocklist(app.Blocklist).WithCalibration(app.Calibration)
+	result, err := engine.SearchContext(ctx, s.Query, mode, 1, savedsearch.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	return result.Documents, nil
+}
+
+// savedSearchResponse converts a stored saved search for the API
+func savedSearchResponse(s savedsearch.SavedSearch) api.SavedSearch {
+	response := api.SavedSearch{
+		ID:          s.ID,
+		Name:        s.Name,
+		Query:       s.Query,
+		Mode:        s.Mode,
+		Status:      s.Status,
+		Tags:        s.Tags,
+		TagsMode:    s.TagsMode,
+		Webhook:     s.Webhook,
+		Email:       s.Email,
+		CreatedAt:   s.CreatedAt,
+		LastError:   s.LastError,
+		LastNew:     s.LastNew,
+		KnownCount:  len(s.SeenIDs),
+		AlertsTotal: s.AlertsTotal,
+	}
+	if !s.LastRunAt.IsZero() {
+		lastRunAt := s.LastRunAt
+		response.LastRunAt = &lastRunAt
+	}
+	return response
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/savedsearch"
+)
+
+func TestSavedSearchesHandler(t *testing.T) {
+	store, _ := savedsearch.NewStore("")
+	app := &AppState{SavedSearches: store, AdminToken: "secret"}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/saved-searches?name=Forms&query=form&mode=hybrid&tags=go&email=Team+%3Cteam@example.com%3E", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	app.SavedSearchesHandler(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	searches := store.List()
+	if len(searches) != 1 {
+		t.Fatalf("Expected 1 saved search, got %d", len(searches))
+	}
+	if s := searches[0]; s.Name != "Forms" || s.Query != "form" || s.Mode != "hybrid" || s.Tags != "go" || s.Email != "team@example.com" {
+		t.Errorf("Unexpected saved search: %+v", s)
+	}
+
+	w = httptest.NewRecorder()
+	app.SavedSearchesHandler(w, httptest.NewRequest("GET", "/api/saved-searches", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"count":1`) {
+		t.Errorf("Unexpected list response %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	app.SavedSearchesHandler(w, httptest.NewRequest("DELETE", "/api/saved-searches?id=1", nil))
+	if w.Code != http.StatusOK || len(store.List()) != 0 {
+		t.Errorf("Expected saved search to be deleted, got %d: %s", w.Code, w.Body.String())
+	}
+
+	errorTests := []struct {
+		method, url  string
+		expectedCode int
+	}{
+		{"POST", "/api/saved-searches", http.StatusBadRequest},
+		{"POST", "/api/saved-searches?query=form&mode=unknown", http.StatusBadRequest},
+		{"POST", "/api/saved-searches?query=form&status=gone", http.StatusBadRequest},
+		{"POST", "/api/saved-searches?query=form&tags_mode=some", http.StatusBadRequest},
+		{"POST", "/api/saved-searches?query=form&webhook=ftp://example.com", http.StatusBadRequest},
+		{"POST", "/api/saved-searches?query=form&email=nobody", http.StatusBadRequest},
+		{"POST", "/api/saved-searches?query=form&name=a%0D%0ABcc:x", http.StatusBadRequest},
+		{"POST", "/api/saved-searches?query=form&webhook=http://169.254.169.254/latest", http.StatusUnauthorized},
+		{"POST", "/api/saved-searches?query=form&email=someone@example.com", http.StatusUnauthorized},
+		{"DELETE", "/api/saved-searches?id=1", http.StatusNotFound},
+		{"DELETE", "/api/saved-searches?id=abc", http.StatusBadRequest},
+		{"PUT", "/api/saved-searches", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range errorTests {
+		w := httptest.NewRecorder()
+		app.SavedSearchesHandler(w, httptest.NewRequest(tt.method, tt.url, nil))
+		if w.Code != tt.expectedCode {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.url, tt.expectedCode, w.Code)
+		}
+	}
+}
+
+func TestSavedSearchesHandler_Targets(t *testing.T) {
+	store, _ := savedsearch.NewStore("")
+	app := &AppState{SavedSearches: store}
+
+	// Without an admin token nobody can set alert targets, but default alerts still work
+	w := httptest.NewRecorder()
+	app.SavedSearchesHandler(w, httptest.NewRequest("POST", "/api/saved-searches?query=form&webhook=http://localhost:8080/hook", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a webhook without an admin token, got %d: %s", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	app.SavedSearchesHandler(w, httptest.NewRequest("POST", "/api/saved-searches?query=form", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 without targets, got %d: %s", w.Code, w.Body.String())
+	}
+	if searches := store.List(); len(searches) != 1 || searches[0].Webhook != "" {
+		t.Errorf("Expected only the saved search without targets, got %+v", searches)
+	}
+}
+
+func TestSavedSearchesHandler_Limit(t *testing.T) {
+	store, _ := savedsearch.NewStore("")
+	app := &AppState{SavedSearches: store}
+
+	for i := 0; i < savedsearch.MaxSearches; i++ {
+		if _, err := store.Create(savedsearch.SavedSearch{Query: "form", Mode: "basic"}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	app.SavedSearchesHandler(w, httptest.NewRequest("POST", "/api/saved-searches?query=form", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 once the store is full, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSavedSearchesHandler_Unavailable(t *testing.T) {
+	app := &AppState{}
+
+	w := httptest.NewRecorder()
+	app.SavedSearchesHandler(w, httptest.NewRequest("GET", "/api/saved-searches", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 without a store, got %d", w.Code)
+	}
+}
+
+func TestRunSavedSearch_ManticoreUnavailable(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: false}}
+
+	if _, err := app.RunSavedSearch(context.Background(), savedsearch.SavedSearch{Query: "form", Mode: "basic"}); err == nil {
+		t.Error("Expected an error when Manticore is not connected")
+	}
+}
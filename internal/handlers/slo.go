@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"github.com/ad/manticoresearch-go/internal/slo"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// convertSearchLatency converts the latencies and objectives of the search modes to their API form
+func convertSearchLatency(statuses []slo.ModeStatus) []api.SearchLatencyStatus {
+	if len(statuses) == 0 {
+		return nil
+	}
+	result := make([]api.SearchLatencyStatus, 0, len(statuses))
+	for _, status := range statuses {
+		mode := api.SearchLatencyStatus{
+			Mode:     status.Mode,
+			Searches: status.Latencies.Count,
+			P50:      status.Latencies.P50.String(),
+			P95:      status.Latencies.P95.String(),
+			P99:      status.Latencies.P99.String(),
+		}
+		for _, objective := range status.Objectives {
+			converted := api.SLOObjectiveStatus{
+				Percentile:      objective.Percentile,
+				Threshold:       objective.Threshold.String(),
+				Latency:         objective.Latency.String(),
+				State:           string(objective.State),
+				ConsecutiveOver: objective.ConsecutiveOver,
+			}
+			if !objective.BurningSince.IsZero() {
+				burningSince := objective.BurningSince
+				converted.BurningSince = &burningSince
+			}
+			mode.Objectives = append(mode.Objectives, converted)
+		}
+		result = append(result, mode)
+	}
+	return result
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/slo"
+)
+
+func TestStatusSearchLatency(t *testing.T) {
+	app := NewAppStateWithConfig(nil)
+	app.SLO = slo.NewTracker(slo.Config{Objectives: []slo.Objective{{Mode: "vector", Percentile: 99, Threshold: time.Second}}}, nil)
+	app.SLO.Record("basic", 30*time.Millisecond)
+	app.SLO.Check()
+
+	latency := app.status().SearchLatency
+	if len(latency) != 2 || latency[0].Mode != "basic" || latency[0].Searches != 1 || latency[0].P95 != "30ms" {
+		t.Fatalf("Unexpected search latency: %+v", latency)
+	}
+	if objectives := latency[1].Objectives; len(objectives) != 1 || objectives[0].State != "ok" || objectives[0].BurningSince != nil {
+		t.Errorf("Expected the vector objective within budget, got %+v", objectives)
+	}
+}
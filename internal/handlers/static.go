@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// StaticHandler serves files from a static directory with path cleaning,
+// cache headers, ETags and single-page-application fallback to index.html
+type StaticHandler struct {
+	root         string
+	indexFile    string
+	spaFallback  bool
+	cacheControl string
+}
+
+// NewStaticHandler creates a new static file handler rooted at dir
+func NewStaticHandler(dir string) *StaticHandler {
+	return &StaticHandler{
+		root:         dir,
+		indexFile:    "index.html",
+		spaFallback:  true,
+		cacheControl: "public, max-age=3600",
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *StaticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filePath, ok := h.resolve(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	info, err := os.Stat(filePath)
+	if err == nil && info.IsDir() {
+		filePath = filepath.Join(filePath, h.indexFile)
+		info, err = os.Stat(filePath)
+	}
+
+	if err != nil {
+		// Unknown paths without an extension are client-side routes, serve the app shell
+		if h.spaFallback && path.Ext(r.URL.Path) == "" && !strings.HasPrefix(r.URL.Path, "/api/") {
+			h.serveFile(w, r, filepath.Join(h.root, h.indexFile), true)
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	h.serveFile(w, r, filePath, filepath.Base(filePath) == h.indexFile)
+}
+
+// resolve maps a URL path to a file path inside the static root, rejecting traversal attempts
+func (h *StaticHandler) resolve(urlPath string) (string, bool) {
+	if strings.Contains(urlPath, "\x00") || strings.Contains(urlPath, "\\") {
+		return "", false
+	}
+
+	// path.Clean on a rooted path removes every ".." element that would escape the root
+	cleaned := path.Clean("/" + urlPath)
+	for _, segment := range strings.Split(cleaned, "/") {
+		// Hidden files (.env, .gitkeep) are never served
+		if strings.HasPrefix(segment, ".") {
+			return "", false
+		}
+	}
+
+	root, err := filepath.Abs(h.root)
+	if err != nil {
+		return "", false
+	}
+
+	full := filepath.Join(root, filepath.FromSlash(cleaned))
+	if full != root && !strings.HasPrefix(full, root+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return full, true
+}
+
+// serveFile writes a file with Content-Type, Cache-Control and ETag headers
+func (h *StaticHandler) serveFile(w http.ResponseWriter, r *http.Request, filePath string, isIndex bool) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	if contentType := mime.TypeByExtension(filepath.Ext(filePath)); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+
+	// The app shell must be revalidated so new deployments are picked up immediately
+	if isIndex {
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Cache-Control", h.cacheControl)
+	}
+	w.Header().Set("ETag", staticETag(filePath, info))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	// ServeContent handles If-None-Match, If-Modified-Since and Range requests
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+}
+
+// staticETag derives a strong ETag from file name, size and modification time
+func staticETag(filePath string, info os.FileInfo) string {
+	hash := sha1.Sum([]byte(fmt.Sprintf("%s|%d|%d", filePath, info.Size(), info.ModTime().UnixNano())))
+	return `"` + hex.EncodeToString(hash[:8]) + `"`
+}
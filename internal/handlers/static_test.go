@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupStaticDir(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	staticDir := filepath.Join(root, "static")
+	if err := os.MkdirAll(filepath.Join(staticDir, "css"), 0755); err != nil {
+		t.Fatalf("Failed to create static dir: %v", err)
+	}
+
+	files := map[string]string{
+		filepath.Join(staticDir, "index.html"):     "<html>app</html>",
+		filepath.Join(staticDir, "css", "app.css"): "body {}",
+		filepath.Join(staticDir, ".env"):           "SECRET=1",
+		filepath.Join(root, "secret.txt"):          "outside",
+		filepath.Join(staticDir, "script.js"):      "console.log(1)",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	return staticDir
+}
+
+func TestStaticHandler(t *testing.T) {
+	handler := NewStaticHandler(setupStaticDir(t))
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+		expectedType   string
+		expectedCache  string
+	}{
+		{"root serves index", "/", http.StatusOK, "<html>app</html>", "text/html", "no-cache"},
+		{"asset with content type", "/css/app.css", http.StatusOK, "body {}", "text/css", "public, max-age=3600"},
+		{"javascript asset", "/script.js", http.StatusOK, "console.log(1)", "javascript", "public, max-age=3600"},
+		{"spa fallback", "/search/results", http.StatusOK, "<html>app</html>", "text/html", "no-cache"},
+		{"missing asset is 404", "/missing.js", http.StatusNotFound, "", "", ""},
+		{"api paths never fall back", "/api/unknown", http.StatusNotFound, "", "", ""},
+		{"traversal is contained", "/../secret.txt", http.StatusNotFound, "", "", ""},
+		{"encoded traversal is contained", "/css/..%2f..%2fsecret.txt", http.StatusNotFound, "", "", ""},
+		{"hidden files are not served", "/.env", http.StatusNotFound, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.URL.Path = tt.path
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if w.Body.String() != tt.expectedBody {
+				t.Errorf("Expected body %q, got %q", tt.expectedBody, w.Body.String())
+			}
+			if !strings.Contains(w.Header().Get("Content-Type"), tt.expectedType) {
+				t.Errorf("Expected Content-Type containing %q, got %q", tt.expectedType, w.Header().Get("Content-Type"))
+			}
+			if w.Header().Get("Cache-Control") != tt.expectedCache {
+				t.Errorf("Expected Cache-Control %q, got %q", tt.expectedCache, w.Header().Get("Cache-Control"))
+			}
+			if w.Header().Get("ETag") == "" {
+				t.Errorf("Expected ETag header to be set")
+			}
+		})
+	}
+}
+
+func TestStaticHandlerConditionalRequest(t *testing.T) {
+	handler := NewStaticHandler(setupStaticDir(t))
+
+	req := httptest.NewRequest("GET", "/css/app.css", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected ETag header")
+	}
+
+	req = httptest.NewRequest("GET", "/css/app.css", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected status 304, got %d", w.Code)
+	}
+}
+
+func TestStaticHandlerMethodNotAllowed(t *testing.T) {
+	handler := NewStaticHandler(setupStaticDir(t))
+
+	req := httptest.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/internal/validation"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// PopularSuggestionsHandler handles GET /api/suggest/popular, listing the queries most often
+// searched with results that start with prefix
+func (app *AppState) PopularSuggestionsHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow GET requests
+	if r.Method != "GET" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var errs validation.Errors
+	prefix := strings.TrimSpace(r.URL.Query().Get("prefix"))
+	if utf8.RuneCountInString(prefix) > search.MaxQueryLength {
+		errs.Add("prefix", validation.TooLong("prefix", search.MaxQueryLength))
+	}
+	limit, err := parseIntRangeParam(r.URL.Query().Get("limit"), "limit", search.DefaultSuggestionLimit, 1, search.MaxSuggestionLimit)
+	errs.Add("limit", err)
+	if len(errs) > 0 {
+		app.sendValidationError(w, r, errs)
+		return
+	}
+
+	response := api.PopularSuggestionsResponse{Prefix: prefix, Suggestions: []api.PopularQuery{}}
+	for _, query := range app.Analytics.Popular(prefix, limit) {
+		response.Suggestions = append(response.Suggestions, api.PopularQuery{Query: query.Text, Count: query.Count, LastSearched: query.LastSearched})
+	}
+	response.Count = len(response.Suggestions)
+
+	app.sendSuccessResponse(w, response)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/analytics"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+func TestPopularSuggestionsHandler(t *testing.T) {
+	store := analytics.NewStore(100)
+	for _, query := range []string{"форма входа", "Форма входа", "формат даты", "поиск"} {
+		store.Record(query)
+	}
+	app := &AppState{Analytics: store}
+
+	tests := []struct {
+		name     string
+		url      string
+		expected []string
+	}{
+		{"prefix", "/api/suggest/popular?prefix=ФОРМ", []string{"Форма входа", "формат даты"}},
+		{"limit", "/api/suggest/popular?prefix=форм&limit=1", []string{"Форма входа"}},
+		{"no prefix", "/api/suggest/popular?limit=5", []string{"Форма входа", "поиск", "формат даты"}},
+		{"no match", "/api/suggest/popular?prefix=xyz", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			app.PopularSuggestionsHandler(w, httptest.NewRequest("GET", tt.url, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var response struct {
+				Data api.PopularSuggestionsResponse `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Data.Count != len(tt.expected) || len(response.Data.Suggestions) != len(tt.expected) {
+				t.Fatalf("Expected %d suggestions, got %+v", len(tt.expected), response.Data)
+			}
+			for i, suggestion := range response.Data.Suggestions {
+				if suggestion.Query != tt.expected[i] {
+					t.Errorf("Suggestion %d: expected %q, got %q", i, tt.expected[i], suggestion.Query)
+				}
+			}
+		})
+	}
+
+	// Without analytics there is nothing to suggest
+	w := httptest.NewRecorder()
+	(&AppState{}).PopularSuggestionsHandler(w, httptest.NewRequest("GET", "/api/suggest/popular?prefix=a", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 without analytics, got %d", w.Code)
+	}
+}
+
+func TestPopularSuggestionsHandler_Validation(t *testing.T) {
+	app := &AppState{}
+
+	for _, url := range []string{"/api/suggest/popular?limit=0", "/api/suggest/popular?limit=500"} {
+		w := httptest.NewRecorder()
+		app.PopularSuggestionsHandler(w, httptest.NewRequest("GET", url, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", url, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	app.PopularSuggestionsHandler(w, httptest.NewRequest("POST", "/api/suggest/popular", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+}
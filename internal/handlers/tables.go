@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/validation"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// ResetTablesHandler handles POST /api/admin/reset?tables=... requests, dropping the given tables
+// and creating them again with the current schema
+func (app *AppState) ResetTablesHandler(w http.ResponseWriter, r *http.Request) {
+	app.handleTableOperation(w, r, "reset", true, func(table string) error {
+		err := app.Manticore.ResetTable(table, app.CurrentAIConfig())
+		app.forgetDocuments(table)
+		return err
+	})
+}
+
+// TruncateTablesHandler handles POST /api/admin/truncate?tables=... requests, removing every
+// document from the given tables
+func (app *AppState) TruncateTablesHandler(w http.ResponseWriter, r *http.Request) {
+	app.handleTableOperation(w, r, "truncate", true, func(table string) error {
+		err := app.Manticore.TruncateTable(table)
+		app.forgetDocuments(table)
+		return err
+	})
+}
+
+// forgetDocuments empties the document store when the documents table was emptied
+func (app *AppState) forgetDocuments(table string) {
+	if table == "documents" {
+		app.Documents.Replace(nil, nil)
+	}
+}
+
+// OptimizeTablesHandler handles POST /api/admin/optimize?tables=... requests, starting the merge
+// of the disk chunks of the given tables
+func (app *AppState) OptimizeTablesHandler(w http.ResponseWriter, r *http.Request) {
+	app.handleTableOperation(w, r, "optimize", false, func(table string) error {
+		if err := app.Manticore.OptimizeTable(table); err != nil {
+			return err
+		}
+		app.recordOptimize(table, time.Now())
+		return nil
+	})
+}
+
+// recordOptimize remembers when table was last optimized
+func (app *AppState) recordOptimize(table string, at time.Time) {
+	app.lastOptimizeMu.Lock()
+	defer app.lastOptimizeMu.Unlock()
+	if app.lastOptimize == nil {
+		app.lastOptimize = make(map[string]time.Time)
+	}
+	app.lastOptimize[table] = at
+}
+
+// lastOptimized returns when table was last optimized through the admin endpoint, or nil if it
+// has not been since the server started
+func (app *AppState) lastOptimized(table string) *time.Time {
+	app.lastOptimizeMu.Lock()
+	defer app.lastOptimizeMu.Unlock()
+	at, ok := app.lastOptimize[table]
+	if !ok {
+		return nil
+	}
+	return &at
+}
+
+// handleTableOperation applies a reset, truncate or optimize to the tables parameter, or only
+// reports the documents of the tables when dry_run=true. Operations that change documents
+// advance the index generation. It requires the admin token.
+func (app *AppState) handleTableOperation(w http.ResponseWriter, r *http.Request, action string, changesDocuments bool, apply func(table string) error) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow POST requests
+	if r.Method != "POST" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	startTime := time.Now()
+	if !app.authorizeAdmin(w, r) {
+		app.recordAudit(r, action, map[string]interface{}{"tables": r.URL.Query().Get("tables")}, fmt.Errorf("unauthorized"), startTime)
+		return
+	}
+
+	tables, err := parseTables(r.URL.Query().Get("tables"))
+	if err != nil {
+		app.sendValidationError(w, r, err)
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	// Check if Manticore is available
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Manticore Search is not available")
+		return
+	}
+
+	auditParams := map[string]interface{}{"tables": tables, "dry_run": dryRun}
+	var auditErr error
+	defer func() {
+		app.recordAudit(r, action, auditParams, auditErr, startTime)
+	}()
+
+	response := api.TableOperationResponse{Action: action, DryRun: dryRun, Tables: make([]api.TableOperation, 0, len(tables))}
+	for _, table := range tables {
+		operation := api.TableOperation{Name: table}
+		if stats, err := app.Manticore.GetIndexStats(table); err != nil {
+			log.Printf("Warning: Failed to get index stats for %s: %v", table, err)
+		} else {
+			operation.Exists = stats.Exists
+			operation.Documents = stats.IndexedDocuments
+		}
+		response.Tables = append(response.Tables, operation)
+	}
+	if dryRun {
+		app.sendSuccessResponse(w, response)
+		return
+	}
+
+	for _, table := range tables {
+		if auditErr = apply(table); auditErr != nil {
+			break
+		}
+	}
+	// Earlier tables changed even if a later one failed
+	if changesDocuments {
+		app.IndexChanged()
+	}
+	if auditErr != nil {
+		log.Printf("Admin %s failed: %v", action, auditErr)
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to %s tables: %v", action, auditErr))
+		return
+	}
+	log.Printf("Admin %s of tables %s completed", action, strings.Join(tables, ", "))
+
+	app.sendSuccessResponse(w, response)
+}
+
+// parseTables parses a comma-separated list of data tables, requiring at least one
+func parseTables(value string) ([]string, error) {
+	var tables []string
+	seen := make(map[string]bool)
+	for _, table := range strings.Split(value, ",") {
+		table = strings.TrimSpace(table)
+		if table == "" || seen[table] {
+			continue
+		}
+		if !manticore.IsDataTable(table) {
+			return nil, validation.InvalidValue("tables", table, manticore.DataTables...)
+		}
+		seen[table] = true
+		tables = append(tables, table)
+	}
+	if len(tables) == 0 {
+		return nil, validation.Required("tables")
+	}
+	return tables, nil
+}
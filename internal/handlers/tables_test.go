@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/audit"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// tableClient records truncated, reset and optimized tables
+type tableClient struct {
+	MockManticoreClient
+	truncated []string
+	reset     []string
+	optimized []string
+}
+
+func (c *tableClient) TruncateTable(table string) error {
+	c.truncated = append(c.truncated, table)
+	return nil
+}
+
+func (c *tableClient) ResetTable(table string, aiConfig *models.AISearchConfig) error {
+	c.reset = append(c.reset, table)
+	return nil
+}
+
+func (c *tableClient) OptimizeTable(table string) error {
+	c.optimized = append(c.optimized, table)
+	return nil
+}
+
+func adminRequest(url, token string) *http.Request {
+	r := httptest.NewRequest("POST", url, nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r
+}
+
+func TestTruncateTablesHandler(t *testing.T) {
+	client := &tableClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+	auditLog, _ := audit.New("", 10)
+	app := &AppState{Manticore: client, AdminToken: "secret", Audit: auditLog}
+
+	w := httptest.NewRecorder()
+	app.TruncateTablesHandler(w, adminRequest("/api/admin/truncate?tables=documents,documents_vector,documents", "secret"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !reflect.DeepEqual(client.truncated, []string{"documents", "documents_vector"}) {
+		t.Errorf("Unexpected truncated tables: %v", client.truncated)
+	}
+	if app.IndexGeneration() != 1 {
+		t.Errorf("Expected the index generation to advance, got %d", app.IndexGeneration())
+	}
+	if entries := auditLog.Recent(10, "truncate"); len(entries) != 1 || entries[0].Outcome != "success" {
+		t.Errorf("Expected a successful audit entry, got %+v", entries)
+	}
+}
+
+func TestOptimizeTablesHandler(t *testing.T) {
+	client := &tableClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+	app := &AppState{Manticore: client, AdminToken: "secret"}
+
+	if app.lastOptimized("documents") != nil {
+		t.Fatal("Expected no optimize time before optimizing")
+	}
+	w := httptest.NewRecorder()
+	app.OptimizeTablesHandler(w, adminRequest("/api/admin/optimize?tables=documents", "secret"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !reflect.DeepEqual(client.optimized, []string{"documents"}) {
+		t.Errorf("Unexpected optimized tables: %v", client.optimized)
+	}
+	if app.lastOptimized("documents") == nil || app.lastOptimized("documents_vector") != nil {
+		t.Errorf("Expected only documents to record an optimize time")
+	}
+	if app.IndexGeneration() != 0 {
+		t.Errorf("Expected optimizing to keep the index generation, got %d", app.IndexGeneration())
+	}
+}
+
+func TestResetTablesHandler_DryRun(t *testing.T) {
+	client := &tableClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+	app := &AppState{Manticore: client, AdminToken: "secret"}
+
+	w := httptest.NewRecorder()
+	app.ResetTablesHandler(w, adminRequest("/api/admin/reset?tables=documents&dry_run=true", "secret"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(client.reset) != 0 || app.IndexGeneration() != 0 {
+		t.Errorf("Expected a dry run to change nothing, reset %v", client.reset)
+	}
+
+	w = httptest.NewRecorder()
+	app.ResetTablesHandler(w, adminRequest("/api/admin/reset?tables=documents", "secret"))
+	if w.Code != http.StatusOK || !reflect.DeepEqual(client.reset, []string{"documents"}) {
+		t.Errorf("Expected documents to be reset, got %d %v", w.Code, client.reset)
+	}
+}
+
+func TestTableOperationErrors(t *testing.T) {
+	client := &tableClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}}
+
+	tests := []struct {
+		name         string
+		app          *AppState
+		method, url  string
+		token        string
+		expectedCode int
+	}{
+		{"disabled without a token", &AppState{Manticore: client}, "POST", "/api/admin/truncate?tables=documents", "secret", http.StatusForbidden},
+		{"missing token", &AppState{Manticore: client, AdminToken: "secret"}, "POST", "/api/admin/truncate?tables=documents", "", http.StatusUnauthorized},
+		{"wrong token", &AppState{Manticore: client, AdminToken: "secret"}, "POST", "/api/admin/truncate?tables=documents", "guess", http.StatusUnauthorized},
+		{"missing tables", &AppState{Manticore: client, AdminToken: "secret"}, "POST", "/api/admin/truncate", "secret", http.StatusBadRequest},
+		{"unknown table", &AppState{Manticore: client, AdminToken: "secret"}, "POST", "/api/admin/truncate?tables=schema_meta", "secret", http.StatusBadRequest},
+		{"wrong method", &AppState{Manticore: client, AdminToken: "secret"}, "GET", "/api/admin/truncate?tables=documents", "secret", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := adminRequest(tt.url, tt.token)
+			r.Method = tt.method
+			w := httptest.NewRecorder()
+			tt.app.TruncateTablesHandler(w, r)
+			if w.Code != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+		})
+	}
+	if len(client.truncated) != 0 {
+		t.Errorf("Expected no table to be truncated, got %v", client.truncated)
+	}
+}
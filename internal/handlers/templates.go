@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/querytemplate"
+	"github.com/ad/manticoresearch-go/internal/validation"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// templateSearchParams are the search parameters a template governs. Callers of a template can
+// only set the others, such as page, progressive and, when the template doesn't fix it, limit.
+var templateSearchParams = []string{"query", "mode", "fields", "status", "sort", "since", "tags", "tags_mode"}
+
+// TemplatesHandler handles /api/templates requests: GET lists query templates, POST creates or
+// replaces the one given as a JSON body, and DELETE removes the one given by the name parameter.
+func (app *AppState) TemplatesHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if app.Templates == nil {
+		app.sendErrorResponse(w, http.StatusServiceUnavailable, "Query templates are not available")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		app.listTemplates(w)
+	case "POST":
+		app.putTemplate(w, r)
+	case "DELETE":
+		app.deleteTemplate(w, r)
+	default:
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (app *AppState) listTemplates(w http.ResponseWriter) {
+	templates := app.Templates.List()
+
+	response := api.QueryTemplateListResponse{
+		Templates: make([]api.QueryTemplate, 0, len(templates)),
+	}
+	for _, t := range templates {
+		response.Templates = append(response.Templates, templateResponse(t))
+	}
+	response.Count = len(response.Templates)
+
+	app.sendSuccessResponse(w, response)
+}
+
+func (app *AppState) putTemplate(w http.ResponseWriter, r *http.Request) {
+	var request api.QueryTemplate
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			app.sendErrorResponse(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body too large (at most %d bytes)", maxBytesErr.Limit))
+			return
+		}
+		app.sendErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid template: %v", err))
+		return
+	}
+
+	template := querytemplate.Template{
+		Name:        request.Name,
+		Description: request.Description,
+		Query:       request.Query,
+		Mode:        request.Mode,
+		Fields:      request.Fields,
+		Status:      request.Status,
+		Sort:        request.Sort,
+		Since:       request.Since,
+		Tags:        request.Tags,
+		TagsMode:    request.TagsMode,
+		Limit:       request.Limit,
+	}
+	if len(request.Params) > 0 {
+		template.Params = make(map[string]querytemplate.Param, len(request.Params))
+		for name, param := range request.Params {
+			template.Params[name] = querytemplate.Param{Description: param.Description, Default: param.Default}
+		}
+	}
+	if err := template.Validate(); err != nil {
+		app.sendValidationError(w, r, err)
+		return
+	}
+
+	startTime := time.Now()
+	stored, err := app.Templates.Put(template)
+	app.recordAudit(r, "template_put", map[string]interface{}{"name": template.Name, "query": template.Query, "mode": template.Mode}, err, startTime)
+	if err != nil {
+		log.Printf("Failed to store query template %s: %v", template.Name, err)
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to store query template: %v", err))
+		return
+	}
+
+	app.sendSuccessResponse(w, templateResponse(stored))
+}
+
+func (app *AppState) deleteTemplate(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		app.sendValidationError(w, r, validation.Required("name"))
+		return
+	}
+
+	startTime := time.Now()
+	deleted, err := app.Templates.Delete(name)
+	app.recordAudit(r, "template_delete", map[string]interface{}{"name": name}, err, startTime)
+	if err != nil {
+		log.Printf("Failed to delete query template %s: %v", name, err)
+		app.sendErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to delete query template: %v", err))
+		return
+	}
+	if !deleted {
+		app.sendErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Query template %q not found", name))
+		return
+	}
+
+	app.sendSuccessResponse(w, api.QueryTemplateDeleteResponse{Name: name, Deleted: true})
+}
+
+// applyTemplate rewrites the parameters of a search request using the template parameter with the
+// search parameters of the named template, expanded with the JSON object in the params parameter.
+// It sends an error response and returns false when the template can't be run.
+func (app *AppState) applyTemplate(w http.ResponseWriter, r *http.Request) bool {
+	params := r.URL.Query()
+	name := params.Get("template")
+	template, ok := app.Templates.Get(name)
+	if !ok {
+		app.sendErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Query template %q not found", name))
+		return false
+	}
+
+	var values map[string]string
+	if raw := params.Get("params"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &values); err != nil {
+			app.sendValidationError(w, r, validation.InvalidFormat("params", raw, "json_object"))
+			return false
+		}
+	}
+	expanded, err := template.Expand(values)
+	if err != nil {
+		app.sendValidationError(w, r, err)
+		return false
+	}
+
+	params.Del("template")
+	params.Del("params")
+	for _, param := range templateSearchParams {
+		params.Del(param)
+	}
+	for param, value := range expanded {
+		params[param] = value
+	}
+	r.URL.RawQuery = params.Encode()
+	return true
+}
+
+func templateResponse(t querytemplate.Template) api.QueryTemplate {
+	response := api.QueryTemplate{
+		Name:        t.Name,
+		Description: t.Description,
+		Query:       t.Query,
+		Mode:        t.Mode,
+		Fields:      t.Fields,
+		Status:      t.Status,
+		Sort:        t.Sort,
+		Since:       t.Since,
+		Tags:        t.Tags,
+		TagsMode:    t.TagsMode,
+		Limit:       t.Limit,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+	if len(t.Params) > 0 {
+		response.Params = make(map[string]api.QueryTemplateParam, len(t.Params))
+		for name, param := range t.Params {
+			response.Params[name] = api.QueryTemplateParam{Description: param.Description, Default: param.Default}
+		}
+	}
+	return response
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/querytemplate"
+)
+
+const testTemplateBody = `{
+	"name": "recent-by-tag",
+	"query": "@title {{terms}}",
+	"mode": "hybrid",
+	"tags": "{{tag}}",
+	"limit": 20,
+	"params": {"terms": {}, "tag": {"default": "go"}}
+}`
+
+func TestTemplatesHandler(t *testing.T) {
+	store, _ := querytemplate.NewStore("")
+	app := &AppState{Templates: store}
+
+	w := httptest.NewRecorder()
+	app.TemplatesHandler(w, httptest.NewRequest("POST", "/api/templates", strings.NewReader(testTemplateBody)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if template, ok := store.Get("recent-by-tag"); !ok || template.Mode != "hybrid" || template.Params["tag"].Default == nil {
+		t.Errorf("Unexpected template: %+v", template)
+	}
+
+	w = httptest.NewRecorder()
+	app.TemplatesHandler(w, httptest.NewRequest("GET", "/api/templates", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"count":1`) {
+		t.Errorf("Unexpected list response %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	app.TemplatesHandler(w, httptest.NewRequest("DELETE", "/api/templates?name=recent-by-tag", nil))
+	if w.Code != http.StatusOK || len(store.List()) != 0 {
+		t.Errorf("Expected template to be deleted, got %d: %s", w.Code, w.Body.String())
+	}
+
+	errorTests := []struct {
+		method, url, body string
+		expectedCode      int
+	}{
+		{"POST", "/api/templates", `{"name":`, http.StatusBadRequest},
+		{"POST", "/api/templates", `{"name": "x", "query": "go", "unknown": 1}`, http.StatusBadRequest},
+		{"POST", "/api/templates", `{"name": "x", "query": "{{terms}}"}`, http.StatusBadRequest},
+		{"DELETE", "/api/templates", "", http.StatusBadRequest},
+		{"DELETE", "/api/templates?name=missing", "", http.StatusNotFound},
+		{"PUT", "/api/templates", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range errorTests {
+		w := httptest.NewRecorder()
+		app.TemplatesHandler(w, httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body)))
+		if w.Code != tt.expectedCode {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.url, tt.expectedCode, w.Code)
+		}
+	}
+}
+
+func TestApplyTemplate(t *testing.T) {
+	store, _ := querytemplate.NewStore("")
+	app := &AppState{Templates: store}
+	w := httptest.NewRecorder()
+	app.TemplatesHandler(w, httptest.NewRequest("POST", "/api/templates", strings.NewReader(testTemplateBody)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	params := url.Values{
+		"template": {"recent-by-tag"},
+		"params":   {`{"terms": "forms | -java"}`},
+		"query":    {"ignored"},
+		"status":   {"deleted"},
+		"page":     {"2"},
+		"limit":    {"50"},
+	}
+	r := httptest.NewRequest("GET", "/api/search?"+params.Encode(), nil)
+	w = httptest.NewRecorder()
+	if !app.applyTemplate(w, r) {
+		t.Fatalf("Expected the template to apply, got %d: %s", w.Code, w.Body.String())
+	}
+	got := r.URL.Query()
+	expected := url.Values{
+		"query": {"@title forms java"},
+		"mode":  {"hybrid"},
+		"tags":  {"go"},
+		"limit": {"20"},
+		"page":  {"2"},
+	}
+	if got.Encode() != expected.Encode() {
+		t.Errorf("Expected parameters %s, got %s", expected.Encode(), got.Encode())
+	}
+
+	errorTests := []struct {
+		url          string
+		expectedCode int
+	}{
+		{"/api/search?template=missing", http.StatusNotFound},
+		{"/api/search?template=recent-by-tag", http.StatusBadRequest},
+		{"/api/search?template=recent-by-tag&params=%5B1%5D", http.StatusBadRequest},
+	}
+	for _, tt := range errorTests {
+		w := httptest.NewRecorder()
+		if app.applyTemplate(w, httptest.NewRequest("GET", tt.url, nil)) || w.Code != tt.expectedCode {
+			t.Errorf("%s: expected status %d, got %d", tt.url, tt.expectedCode, w.Code)
+		}
+	}
+}
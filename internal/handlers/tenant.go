@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/usage"
+)
+
+// TenantHeader selects the tenant of a request whose API key may access every tenant
+const TenantHeader = "X-Tenant"
+
+// tenantNamePattern restricts tenant names to characters valid in Manticore table names
+var tenantNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+var (
+	errUnknownTenant   = errors.New("unknown tenant")
+	errTenantForbidden = errors.New("API key is not allowed to access this tenant")
+)
+
+// ParseTenants parses TENANTS, a comma-separated list of tenant names
+func ParseTenants(value string) ([]string, error) {
+	var tenants []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !tenantNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid tenant name %q: use up to 32 lowercase letters, digits and underscores, starting with a letter", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate tenant %q", name)
+		}
+		seen[name] = true
+		tenants = append(tenants, name)
+	}
+	return tenants, nil
+}
+
+// TablePrefix returns the prefix of the Manticore tables of a tenant
+func TablePrefix(tenant string) string {
+	return tenant + "_"
+}
+
+// ParseCrossTenantKeys parses TENANT_CROSS_ACCESS_KEYS, a comma-separated list of the names of API
+// keys allowed to select any tenant, each of which has to be one of apiKeys
+func ParseCrossTenantKeys(value string, apiKeys map[string]string) (map[string]bool, error) {
+	keys := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := apiKeys[name]; !ok {
+			return nil, fmt.Errorf("%q is not an API key of API_KEYS", name)
+		}
+		keys[name] = true
+	}
+	return keys, nil
+}
+
+// tenantFor returns the state of the tenant a caller may access. An API key named after a tenant
+// is bound to it and can't select another one. Keys of CrossTenantKeys select a tenant by name, or
+// use the default tenant when requested is empty. Other keys only use the default tenant, and
+// anonymous callers are refused, so no caller reads a tenant it was not granted. Without Tenants
+// every caller uses the default tenant.
+func (app *AppState) tenantFor(key, requested string) (*AppState, error) {
+	if len(app.Tenants) == 0 {
+		return app, nil
+	}
+
+	if bound, ok := app.Tenants[key]; ok {
+		if requested != "" && requested != key {
+			return nil, errTenantForbidden
+		}
+		return bound, nil
+	}
+	if key == usage.AnonymousKey {
+		return nil, errTenantForbidden
+	}
+	if requested == "" {
+		return app, nil
+	}
+	if !app.CrossTenantKeys[key] {
+		return nil, errTenantForbidden
+	}
+	tenant, ok := app.Tenants[requested]
+	if !ok {
+		return nil, errUnknownTenant
+	}
+	return tenant, nil
+}
+
+// RouteTenants serves each /api/ request with the handler routes builds for its tenant, selected
+// by the API key, the X-Tenant header or the tenant (or collection) parameter for clients that
+// can't set headers. Other paths, such as the web interface and the probes, are served by the
+// default tenant. It has to wrap the handler after RequireAPIKey, which identifies the key.
+func (app *AppState) RouteTenants(routes func(*AppState) http.Handler) http.Handler {
+	fallback := routes(app)
+	if len(app.Tenants) == 0 {
+		return fallback
+	}
+	handlers := make(map[*AppState]http.Handler, len(app.Tenants)+1)
+	handlers[app] = fallback
+	for _, tenant := range app.Tenants {
+		handlers[tenant] = routes(tenant)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") || r.Method == "OPTIONS" {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+
+		requested := r.Header.Get(TenantHeader)
+		if requested == "" {
+			requested = r.URL.Query().Get("tenant")
+		}
+		if requested == "" {
+			requested = r.URL.Query().Get("collection")
+		}
+		tenant, err := app.tenantFor(contextAPIKey(r.Context()), requested)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			status := http.StatusNotFound
+			if errors.Is(err, errTenantForbidden) {
+				status = http.StatusForbidden
+			}
+			app.sendErrorResponse(w, status, err.Error())
+			return
+		}
+		handlers[tenant].ServeHTTP(w, r)
+	})
+}
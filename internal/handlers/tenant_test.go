@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseTenants(t *testing.T) {
+	tenants, err := ParseTenants(" acme, globex_2 ,")
+	if err != nil || len(tenants) != 2 || tenants[0] != "acme" || tenants[1] != "globex_2" {
+		t.Errorf("Unexpected tenants %v, %v", tenants, err)
+	}
+
+	for _, value := range []string{"Acme", "2fast", "a-b", "acme,acme"} {
+		if _, err := ParseTenants(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestRouteTenants(t *testing.T) {
+	app := &AppState{
+		APIKeys:         map[string]string{"acme": "acme-secret", "globex": "globex-secret", "ops": "ops-secret", "reports": "reports-secret"},
+		CrossTenantKeys: map[string]bool{"ops": true},
+	}
+	app.Tenants = map[string]*AppState{
+		"acme":   {Tenant: "acme"},
+		"globex": {Tenant: "globex"},
+	}
+	handler := app.RequireAPIKey(app.RouteTenants(func(tenant *AppState) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Served-By", tenant.Tenant)
+		})
+	}))
+
+	tests := []struct {
+		name       string
+		key        string
+		tenant     string
+		wantStatus int
+		wantTenant string
+	}{
+		{"bound key", "acme-secret", "", http.StatusOK, "acme"},
+		{"bound key with its tenant", "acme-secret", "acme", http.StatusOK, "acme"},
+		{"bound key with another tenant", "acme-secret", "globex", http.StatusForbidden, ""},
+		{"cross-tenant key selects a tenant", "ops-secret", "globex", http.StatusOK, "globex"},
+		{"cross-tenant key without a tenant", "ops-secret", "", http.StatusOK, ""},
+		{"unknown tenant", "ops-secret", "initech", http.StatusNotFound, ""},
+		{"unbound key with a tenant", "reports-secret", "acme", http.StatusForbidden, ""},
+		{"unbound key with an unknown tenant", "reports-secret", "initech", http.StatusForbidden, ""},
+		{"unbound key without a tenant", "reports-secret", "", http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/api/search?query=test", nil)
+			request.Header.Set("X-API-Key", tt.key)
+			if tt.tenant != "" {
+				request.Header.Set(TenantHeader, tt.tenant)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, request)
+			if w.Code != tt.wantStatus || w.Header().Get("X-Served-By") != tt.wantTenant {
+				t.Errorf("Expected status %d served by %q, got %d served by %q", tt.wantStatus, tt.wantTenant, w.Code, w.Header().Get("X-Served-By"))
+			}
+		})
+	}
+
+	// The collection parameter selects a tenant as well
+	request := httptest.NewRequest(http.MethodGet, "/api/search?query=test&collection=globex", nil)
+	request.Header.Set("X-API-Key", "ops-secret")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, request)
+	if served := w.Header().Get("X-Served-By"); served != "globex" {
+		t.Errorf("Expected the collection to be served by globex, got %q", served)
+	}
+
+	// An unbound key can't select a tenant through the parameters either
+	request = httptest.NewRequest(http.MethodGet, "/api/search?query=test&tenant=globex", nil)
+	request.Header.Set("X-API-Key", "reports-secret")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, request)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for an unbound key selecting a tenant, got %d", w.Code)
+	}
+}
+
+func TestRouteTenantsAnonymous(t *testing.T) {
+	app := &AppState{Tenants: map[string]*AppState{"acme": {Tenant: "acme"}}}
+	handler := app.RequireAPIKey(app.RouteTenants(func(tenant *AppState) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Served-By", tenant.Tenant)
+		})
+	}))
+
+	for _, requested := range []string{"acme", ""} {
+		request := httptest.NewRequest(http.MethodGet, "/api/search?query=test", nil)
+		if requested != "" {
+			request.Header.Set(TenantHeader, requested)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, request)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected anonymous callers to be refused with tenant %q, got %d", requested, w.Code)
+		}
+	}
+}
+
+func TestParseCrossTenantKeys(t *testing.T) {
+	apiKeys := map[string]string{"ops": "ops-secret", "acme": "acme-secret"}
+	keys, err := ParseCrossTenantKeys(" ops ,", apiKeys)
+	if err != nil || len(keys) != 1 || !keys["ops"] {
+		t.Errorf("Unexpected keys %v, %v", keys, err)
+	}
+	if _, err := ParseCrossTenantKeys("ops,unknown", apiKeys); err == nil {
+		t.Error("Expected an error for a key missing from API_KEYS")
+	}
+}
+
+func TestRouteTenantsDisabled(t *testing.T) {
+	app := &AppState{}
+	handler := app.RouteTenants(func(tenant *AppState) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/api/search?query=test&tenant=acme", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, request)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected the tenant parameter to be ignored without tenants, got %d", w.Code)
+	}
+}
+
+func TestGRPCAuthenticateTenant(t *testing.T) {
+	acme := &AppState{Tenant: "acme"}
+	app := &AppState{APIKeys: map[string]string{"acme": "acme-secret"}, Tenants: map[string]*AppState{"acme": acme}}
+
+	ctx, err := app.grpcAuthenticate(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "acme-secret")))
+	if err != nil {
+		t.Fatalf("grpcAuthenticate failed: %v", err)
+	}
+	if service := (&grpcService{app: app}).forTenant(ctx); service.app != acme {
+		t.Errorf("Expected the service of tenant acme, got %q", service.app.Tenant)
+	}
+
+	_, err = app.grpcAuthenticate(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "acme-secret", "x-tenant", "globex")))
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied, got %v", err)
+	}
+}
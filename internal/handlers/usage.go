@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/internal/usage"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// UsageHandler handles GET /api/usage requests, reporting the caller's usage of the day. With
+// all=true and the admin token it reports every key.
+func (app *AppState) UsageHandler(w http.ResponseWriter, r *http.Request) {
+	// Set CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, X-Tenant")
+	w.Header().Set("Content-Type", "application/json")
+
+	// Handle preflight requests
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Only allow GET requests
+	if r.Method != "GET" {
+		app.sendErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if r.URL.Query().Get("all") != "true" {
+		app.sendSuccessResponse(w, usageResponse(app.Usage.Usage(contextAPIKey(r.Context()))))
+		return
+	}
+
+	if !app.authorizeAdmin(w, r) {
+		return
+	}
+	reports := app.Usage.All()
+	response := api.UsageListResponse{Keys: make([]api.UsageResponse, 0, len(reports))}
+	for _, report := range reports {
+		response.Keys = append(response.Keys, usageResponse(report))
+	}
+	response.Count = len(response.Keys)
+
+	app.sendSuccessResponse(w, response)
+}
+
+// usageResponse converts a usage report to the API type
+func usageResponse(report usage.Report) api.UsageResponse {
+	metric := func(metric usage.Metric) api.MetricUsage {
+		result := api.MetricUsage{Used: report.Used[metric], Limit: report.Quotas[metric]}
+		if result.Limit > 0 {
+			remaining := max(result.Limit-result.Used, 0)
+			result.Remaining = &remaining
+		}
+		return result
+	}
+
+	response := api.UsageResponse{
+		Key:              report.Key,
+		Searches:         metric(usage.Searches),
+		IndexedDocuments: metric(usage.IndexedDocuments),
+		EmbeddingCalls:   metric(usage.EmbeddingCalls),
+	}
+	if !report.Day.IsZero() {
+		response.Day = report.Day.Format(time.DateOnly)
+		response.ResetsAt = report.ResetsAt
+	}
+	return response
+}
+
+// searchUses is the usage accounted for a search of query in mode
+func searchUses(mode models.SearchMode, query string) usage.Uses {
+	uses := usage.Uses{usage.Searches: 1}
+	// mode=auto routes natural language to AI hybrid search while AI search is available
+	if mode == models.SearchModeAuto {
+		if class, _ := search.ClassifyQuery(query); class == search.QueryClassNaturalLanguage {
+			mode = models.SearchModeAIHybrid
+		}
+	}
+	if mode.UsesAI() {
+		// Manticore embeds the query
+		uses[usage.EmbeddingCalls] = 1
+	}
+	return uses
+}
+
+// indexingUses is the usage accounted for indexing count documents
+func (app *AppState) indexingUses(count int) usage.Uses {
+	uses := usage.Uses{usage.IndexedDocuments: int64(count)}
+	if aiConfig := app.CurrentAIConfig(); aiConfig != nil && aiConfig.Enabled {
+		// Manticore embeds every document of the vector table
+		uses[usage.EmbeddingCalls] = int64(count)
+	}
+	return uses
+}
+
+// sendQuotaExceededResponse sends a 429 response for a *usage.QuotaError, or a 500 response for
+// any other error
+func (app *AppState) sendQuotaExceededResponse(w http.ResponseWriter, err error) {
+	var quotaErr *usage.QuotaError
+	if !errors.As(err, &quotaErr) {
+		app.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := api.APIResponse{
+		Success: false,
+		Error:   "Daily " + string(quotaErr.Metric) + " quota exceeded",
+		Data: api.QuotaExceededData{
+			ErrorType: api.ErrorTypeQuotaExceeded,
+			Key:       quotaErr.Key,
+			Metric:    string(quotaErr.Metric),
+			Limit:     quotaErr.Limit,
+			Used:      quotaErr.Used,
+			ResetsAt:  quotaErr.ResetsAt,
+		},
+	}
+
+	retryAfter := int(math.Ceil(time.Until(quotaErr.ResetsAt).Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+	w.WriteHeader(http.StatusTooManyRequests)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Failed to encode quota exceeded response: %v", err)
+	}
+}
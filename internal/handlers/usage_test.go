@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/usage"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+func TestParseAPIKeys(t *testing.T) {
+	keys, err := ParseAPIKeys(" ci:abc , web:def,")
+	if err != nil || len(keys) != 2 || keys["ci"] != "abc" || keys["web"] != "def" {
+		t.Errorf("Unexpected keys %v, %v", keys, err)
+	}
+
+	for _, value := range []string{"ci", "ci:", ":abc", "ci:abc,ci:def"} {
+		if _, err := ParseAPIKeys(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestRequireAPIKey(t *testing.T) {
+	app := &AppState{APIKeys: map[string]string{"ci": "secret"}}
+	var key string
+	handler := app.RequireAPIKey(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = contextAPIKey(r.Context())
+	}))
+
+	tests := []struct {
+		name       string
+		target     string
+		header     string
+		wantStatus int
+		wantKey    string
+	}{
+		{"header", "/api/search", "secret", http.StatusOK, "ci"},
+		{"parameter", "/api/ws?api_key=secret", "", http.StatusOK, "ci"},
+		{"missing", "/api/search", "", http.StatusUnauthorized, ""},
+		{"wrong", "/api/search", "wrong", http.StatusUnauthorized, ""},
+		{"outside the API", "/index.html", "", http.StatusOK, usage.AnonymousKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key = ""
+			request := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				request.Header.Set("X-API-Key", tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, request)
+			if w.Code != tt.wantStatus || key != tt.wantKey {
+				t.Errorf("Expected status %d and key %q, got %d and %q", tt.wantStatus, tt.wantKey, w.Code, key)
+			}
+		})
+	}
+}
+
+func TestUsageHandler(t *testing.T) {
+	app := &AppState{Usage: usage.NewTracker(usage.Quotas{usage.Searches: 10}), AdminToken: "admin"}
+	if err := app.Usage.Reserve(usage.AnonymousKey, searchUses("ai", "query")); err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	app.UsageHandler(w, httptest.NewRequest(http.MethodGet, "/api/usage", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var response api.Response[api.UsageResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	searches := response.Data.Searches
+	if searches.Used != 1 || searches.Limit != 10 || searches.Remaining == nil || *searches.Remaining != 9 {
+		t.Errorf("Unexpected search usage: %+v", searches)
+	}
+	if response.Data.EmbeddingCalls.Used != 1 || response.Data.EmbeddingCalls.Remaining != nil {
+		t.Errorf("Unexpected embedding call usage: %+v", response.Data.EmbeddingCalls)
+	}
+
+	w = httptest.NewRecorder()
+	app.UsageHandler(w, httptest.NewRequest(http.MethodGet, "/api/usage?all=true", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 listing every key without the admin token, got %d", w.Code)
+	}
+}
+
+func TestSearchUses(t *testing.T) {
+	tests := []struct {
+		mode       models.SearchMode
+		query      string
+		embeddings int64
+	}{
+		{models.SearchModeFullText, "how to configure the server", 0},
+		{models.SearchModeAI, "server", 1},
+		{models.SearchModeAuto, "server config", 0},
+		{models.SearchModeAuto, "how to configure the server", 1},
+	}
+	for _, tt := range tests {
+		uses := searchUses(tt.mode, tt.query)
+		if uses[usage.Searches] != 1 || uses[usage.EmbeddingCalls] != tt.embeddings {
+			t.Errorf("searchUses(%s, %q) = %v, expected %d embedding calls", tt.mode, tt.query, uses, tt.embeddings)
+		}
+	}
+}
+
+func TestSendQuotaExceededResponse(t *testing.T) {
+	app := &AppState{Usage: usage.NewTracker(usage.Quotas{usage.IndexedDocuments: 5})}
+	err := app.Usage.Reserve("ci", app.indexingUses(6))
+	if err == nil {
+		t.Fatal("Expected the quota to be exceeded")
+	}
+
+	w := httptest.NewRecorder()
+	app.sendQuotaExceededResponse(w, err)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("Expected status 429 with Retry-After, got %d and %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	var response api.Response[api.QuotaExceededData]
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if data := response.Data; data.ErrorType != api.ErrorTypeQuotaExceeded || data.Key != "ci" || data.Metric != "indexed_documents" || data.Limit != 5 {
+		t.Errorf("Unexpected quota data: %+v", data)
+	}
+}
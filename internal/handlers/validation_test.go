@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/validation"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+func TestSearchHandler_ValidationErrors(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+
+	req := httptest.NewRequest("GET", "/api/search?mode=fast&limit=500&sort=title&rescore_window=0&language=de&ids=1,x", nil)
+	w := httptest.NewRecorder()
+	app.SearchHandler(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if language := w.Header().Get("Content-Language"); language != "en" {
+		t.Errorf("Expected Content-Language en, got %q", language)
+	}
+
+	response, err := api.Decode[api.ValidationErrorData](w.Body)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.ErrorType != api.ErrorTypeValidation {
+		t.Errorf("Expected error_type %s, got %q", api.ErrorTypeValidation, response.Data.ErrorType)
+	}
+
+	expected := []struct{ field, code string }{
+		{"query", validation.CodeRequired},
+		{"mode", validation.CodeInvalidValue},
+		{"limit", validation.CodeOutOfRange},
+		{"sort", validation.CodeInvalidValue},
+		{"ids", validation.CodeInvalidFormat},
+		{"rescore_window", validation.CodeOutOfRange},
+		{"language", validation.CodeInvalidValue},
+	}
+	if len(response.Data.Errors) != len(expected) {
+		t.Fatalf("Expected %d field errors, got %+v", len(expected), response.Data.Errors)
+	}
+	for i, want := range expected {
+		got := response.Data.Errors[i]
+		if got.Field != want.field || got.Code != want.code || got.Message == "" {
+			t.Errorf("Error %d: expected %s/%s, got %+v", i, want.field, want.code, got)
+		}
+	}
+
+	limit := response.Data.Errors[2]
+	if limit.Min == nil || *limit.Min != 1 || limit.Max == nil || *limit.Max != 100 || limit.Value != "500" {
+		t.Errorf("Expected the allowed range of limit, got %+v", limit)
+	}
+	if len(response.Data.Errors[1].Allowed) == 0 {
+		t.Error("Expected the allowed modes")
+	}
+}
+
+func TestSearchHandler_ValidationErrorLanguage(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+
+	req := httptest.NewRequest("GET", "/api/search?query=test&page=0", nil)
+	req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9,en;q=0.8")
+	w := httptest.NewRecorder()
+	app.SearchHandler(w, req)
+
+	if language := w.Header().Get("Content-Language"); language != "ru" {
+		t.Errorf("Expected Content-Language ru, got %q", language)
+	}
+	var response api.APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !strings.Contains(response.Error, "page должен быть от 1 до 1000") {
+		t.Errorf("Expected a Russian message, got %q", response.Error)
+	}
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/reconcile"
+)
+
+// vectorGCOptimizeWait bounds how long a vector garbage collection waits for the merge of the
+// vector table to measure the space it reclaimed
+const vectorGCOptimizeWait = 10 * time.Minute
+
+// vectorGCPollInterval is how often a vector garbage collection checks whether the merge completed
+var vectorGCPollInterval = 5 * time.Second
+
+// vectorGC is the outcome of a vector garbage collection
+type vectorGC struct {
+	DroppedTables     []manticore.DroppedTable
+	DeletedVectors    int
+	VectorBytesBefore int64
+	VectorBytesAfter  int64
+	Merged            bool // whether the merge of the vector table completed in vectorGCOptimizeWait
+}
+
+// Reclaimed returns the bytes freed by dropping tables and, once merged, by the vector table
+func (gc *vectorGC) Reclaimed() int64 {
+	var reclaimed int64
+	for _, table := range gc.DroppedTables {
+		reclaimed += table.Bytes
+	}
+	if gc.Merged && gc.VectorBytesBefore > gc.VectorBytesAfter {
+		reclaimed += gc.VectorBytesBefore - gc.VectorBytesAfter
+	}
+	return reclaimed
+}
+
+// collectVectorGarbage drops the documents tables left behind by re-embedding, deletes the
+// vector rows whose document is gone and merges the disk chunks of the vector table, which
+// purges the deleted rows from disk. It waits up to vectorGCOptimizeWait for the merge to
+// measure the vector table again. Deleting vector rows advances the index generation.
+func (app *AppState) collectVectorGarbage(ctx context.Context) (*vectorGC, error) {
+	gc := &vectorGC{}
+	before, err := app.Manticore.GetIndexStats("documents_vector")
+	if err != nil {
+		return gc, err
+	}
+	gc.VectorBytesBefore = before.DiskBytes + before.RAMBytes
+
+	if gc.DroppedTables, err = app.Manticore.DropStaleDocumentsTables(); err != nil {
+		return gc, err
+	}
+
+	// Documents missing their vectors are left to the orphan cleanup, which reindexes them
+	cleanup, err := reconcile.CleanOrphans(app.Manticore, app.Manticore.GetDocument, nil, false)
+	if err != nil {
+		return gc, err
+	}
+	gc.DeletedVectors = cleanup.DeletedVectors
+	if gc.DeletedVectors > 0 {
+		app.IndexChanged()
+	}
+	if cleanup.Error != "" {
+		return gc, fmt.Errorf("%s", cleanup.Error)
+	}
+
+	if err := app.Manticore.OptimizeTable("documents_vector"); err != nil {
+		return gc, err
+	}
+	app.recordOptimize("documents_vector", time.Now())
+
+	deadline := time.NewTimer(vectorGCOptimizeWait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(vectorGCPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return gc, nil
+		case <-deadline.C:
+			return gc, nil
+		case <-ticker.C:
+		}
+		after, err := app.Manticore.GetIndexStats("documents_vector")
+		if err != nil {
+			return gc, err
+		}
+		if !after.Optimizing {
+			gc.VectorBytesAfter = after.DiskBytes + after.RAMBytes
+			gc.Merged = true
+			return gc, nil
+		}
+	}
+}
+
+// scheduledVectorGC collects the garbage of the vector table, reporting the reclaimed space
+func (app *AppState) scheduledVectorGC(ctx context.Context) error {
+	startTime := time.Now()
+	params := map[string]interface{}{"reason": "schedule"}
+
+	var gc *vectorGC
+	var err error
+	if app.Manticore == nil || !app.Manticore.IsConnected() {
+		err = fmt.Errorf("Manticore Search is not available")
+	} else {
+		gc, err = app.collectVectorGarbage(ctx)
+	}
+	if gc != nil {
+		dropped := make([]string, 0, len(gc.DroppedTables))
+		for _, table := range gc.DroppedTables {
+			dropped = append(dropped, table.Table)
+		}
+		params["dropped_tables"] = dropped
+		params["deleted_vectors"] = gc.DeletedVectors
+		params["vector_bytes_before"] = gc.VectorBytesBefore
+		params["merged"] = gc.Merged
+		if gc.Merged {
+			params["vector_bytes_after"] = gc.VectorBytesAfter
+		}
+		params["reclaimed_bytes"] = gc.Reclaimed()
+		log.Printf("Vector garbage collection: dropped %d tables, deleted %d orphaned vectors, reclaimed %d bytes", len(gc.DroppedTables), gc.DeletedVectors, gc.Reclaimed())
+	}
+	app.recordAuditAs("system", "", "vector_gc", params, err, startTime)
+	return err
+}
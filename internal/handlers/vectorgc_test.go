@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/audit"
+	"github.com/ad/manticoresearch-go/internal/maintenance"
+	"github.com/ad/manticoresearch-go/internal/manticore"
+)
+
+// vectorGCClient has documents 1 and 2 and vector rows 1, 2 and 5, a stale documents table, and a
+// vector table that shrinks once merged
+type vectorGCClient struct {
+	MockManticoreClient
+	deleted   map[string][]int64
+	optimized []string
+	polls     int
+}
+
+func (c *vectorGCClient) DocumentIDs(table string) ([]int64, error) {
+	if table == "documents_vector" {
+		return []int64{1, 2, 5}, nil
+	}
+	return []int64{1, 2}, nil
+}
+
+func (c *vectorGCClient) DeleteDocuments(table string, ids []int64) error {
+	c.deleted[table] = append(c.deleted[table], ids...)
+	return nil
+}
+
+func (c *vectorGCClient) DropStaleDocumentsTables() ([]manticore.DroppedTable, error) {
+	return []manticore.DroppedTable{{Table: "documents", Bytes: 4096}}, nil
+}
+
+func (c *vectorGCClient) OptimizeTable(table string) error {
+	c.optimized = append(c.optimized, table)
+	return nil
+}
+
+func (c *vectorGCClient) GetIndexStats(table string) (*manticore.IndexStats, error) {
+	if len(c.optimized) == 0 {
+		return &manticore.IndexStats{Table: table, Exists: true, DiskBytes: 3000, RAMBytes: 200}, nil
+	}
+	c.polls++
+	return &manticore.IndexStats{Table: table, Exists: true, DiskBytes: 2000, Optimizing: c.polls < 2}, nil
+}
+
+func TestScheduledVectorGC(t *testing.T) {
+	defer func(interval time.Duration) { vectorGCPollInterval = interval }(vectorGCPollInterval)
+	vectorGCPollInterval = time.Millisecond
+
+	client := &vectorGCClient{MockManticoreClient: MockManticoreClient{connected: true, healthy: true}, deleted: make(map[string][]int64)}
+	auditLog, _ := audit.New("", 10)
+	app := &AppState{Manticore: client, Audit: auditLog}
+
+	if err := app.MaintenanceTasks()[maintenance.TaskVectorGC](context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ids := client.deleted["documents_vector"]; len(ids) != 1 || ids[0] != 5 || app.IndexGeneration() != 1 {
+		t.Errorf("Expected the orphaned vector row 5 to be deleted, got %v", client.deleted)
+	}
+	if len(client.optimized) != 1 || client.optimized[0] != "documents_vector" || app.lastOptimized("documents_vector") == nil {
+		t.Errorf("Expected the vector table to be optimized, got %v", client.optimized)
+	}
+
+	entries := auditLog.Recent(10, "vector_gc")
+	if len(entries) != 1 || entries[0].Actor != "system" || entries[0].Outcome != "success" {
+		t.Fatalf("Expected a successful system audit entry, got %+v", entries)
+	}
+	params := entries[0].Parameters
+	if params["deleted_vectors"] != 1 || params["merged"] != true || params["vector_bytes_after"] != int64(2000) || params["reclaimed_bytes"] != int64(4096+1200) {
+		t.Errorf("Unexpected audit parameters: %+v", params)
+	}
+}
+
+func TestScheduledVectorGC_Unavailable(t *testing.T) {
+	auditLog, _ := audit.New("", 10)
+	app := &AppState{Manticore: &MockManticoreClient{connected: false}, Audit: auditLog}
+
+	if err := app.MaintenanceTasks()[maintenance.TaskVectorGC](context.Background()); err == nil {
+		t.Error("Expected an error while Manticore is unavailable")
+	}
+	if entries := auditLog.Recent(10, "vector_gc"); len(entries) != 1 || entries[0].Outcome == "success" {
+		t.Errorf("Expected a failed audit entry, got %+v", entries)
+	}
+}
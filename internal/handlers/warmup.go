@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/internal/warmup"
+)
+
+// warmupPageSize is the number of results each warm-up search asks for
+const warmupPageSize = 10
+
+// WarmUp runs the warm-up queries of config through the search engine, so the first searches of
+// users find Manticore's tables, the in-memory vectors and the embedding model loaded. AI searches
+// are skipped while AI search is disabled.
+func (app *AppState) WarmUp(ctx context.Context, config *warmup.Config) warmup.Result {
+	aiConfig := app.CurrentAIConfig()
+	enabled := *config
+	enabled.Modes = nil
+	for _, mode := range config.Modes {
+		if mode.UsesAI() && (aiConfig == nil || !aiConfig.Enabled) {
+			continue
+		}
+		enabled.Modes = append(enabled.Modes, mode)
+	}
+
+	engine := search.NewSearchEngine(app.Manticore, app.Vectorizer, aiConfig).WithRescoreWindow(app.RescoreWindow).WithRelaxation(app.Relaxation).WithSpellTolerance(app.SpellTolerance).WithStopwords(app.Stopwords).WithNormalizer(app.Normalizer).WithBoosts(app.Boosts).WithFeatures(app.Features).WithBlocklist(app.Blocklist).WithCalibration(app.Calibration)
+	result := warmup.Run(ctx, &enabled, func(ctx context.Context, query string, mode models.SearchMode) error {
+		_, err := engine.SearchContext(ctx, query, mode, 1, warmupPageSize)
+		return err
+	})
+	log.Printf("[WARMUP] Ran %d searches in %v, %d failed", result.Queries, result.Duration, result.Failures)
+	return result
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/warmup"
+)
+
+func TestWarmUp_SkipsDisabledAISearch(t *testing.T) {
+	config := &warmup.Config{Queries: []string{"форма", "поиск"}, Modes: []models.SearchMode{models.SearchModeBasic, models.SearchModeAI}}
+
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}, AIConfig: &models.AISearchConfig{Enabled: false}}
+	result := app.WarmUp(context.Background(), config)
+	if result.Queries != 2 || result.Failures != 0 {
+		t.Errorf("Expected only the basic searches to run, got %+v", result)
+	}
+	if len(config.Modes) != 2 {
+		t.Errorf("Expected the configuration to be left unchanged, got %v", config.Modes)
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/webhook"
+)
+
+// PublishReindex publishes the outcome of a reindex as a reindex.completed or indexing.failed
+// webhook event. Reason tells receivers what triggered it, such as "api" or "startup".
+func (app *AppState) PublishReindex(reason string, params map[string]interface{}, err error, startTime time.Time) {
+	data := map[string]interface{}{
+		"reason":   reason,
+		"duration": time.Since(startTime).String(),
+	}
+	for key, value := range params {
+		data[key] = value
+	}
+	if app.Tenant != "" {
+		data["tenant"] = app.Tenant
+	}
+
+	if err != nil {
+		data["error"] = err.Error()
+		app.Webhooks.Publish(webhook.EventIndexingFailed, data)
+		return
+	}
+	app.Webhooks.Publish(webhook.EventReindexCompleted, data)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/webhook"
+)
+
+func TestPublishReindex(t *testing.T) {
+	events := make(chan webhook.Event, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	defer server.Close()
+
+	app := &AppState{Webhooks: webhook.New(webhook.Config{Endpoints: []webhook.Endpoint{{URL: server.URL}}})}
+	app.PublishReindex("api", map[string]interface{}{"documents": 3}, nil, time.Now())
+	app.PublishReindex("startup", nil, errors.New("index failed"), time.Now())
+	app.Webhooks.Close()
+
+	completed, failed := <-events, <-events
+	if data := completed.Data.(map[string]interface{}); completed.Type != webhook.EventReindexCompleted || data["reason"] != "api" || data["documents"] != float64(3) {
+		t.Errorf("Unexpected completion event: %+v", completed)
+	}
+	if data := failed.Data.(map[string]interface{}); failed.Type != webhook.EventIndexingFailed || data["error"] != "index failed" {
+		t.Errorf("Unexpected failure event: %+v", failed)
+	}
+}
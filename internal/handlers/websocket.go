@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/internal/validation"
+	"github.com/ad/manticoresearch-go/internal/websocket"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// maxStreamSearches bounds the searches a single WebSocket connection runs at once
+const maxStreamSearches = 4
+
+// WebSocketHandler handles /api/ws connections. Clients send search, suggest and cancel
+// messages (api.StreamRequest); hybrid and AI searches reply with full-text results first and
+// the final ranking once it is ready.
+func (app *AppState) WebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		log.Printf("WebSocket upgrade failed: %v", err)
+		return
+	}
+
+	session := &streamSession{app: app, conn: conn, key: contextAPIKey(r.Context()), searches: make(map[string]context.CancelFunc)}
+	session.serve()
+}
+
+// streamSession is the state of a WebSocket connection
+type streamSession struct {
+	app  *AppState
+	conn *websocket.Conn
+	key  string // API key usage is accounted to
+
+	mutex    sync.Mutex
+	searches map[string]context.CancelFunc // Cancels the running search of each request id
+	wg       sync.WaitGroup
+}
+
+func (s *streamSession) serve() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		s.wg.Wait()
+		s.conn.Close()
+	}()
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if !errors.Is(err, websocket.ErrClosed) {
+				log.Printf("WebSocket read failed: %v", err)
+			}
+			return
+		}
+
+		var request api.StreamRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			s.send(api.StreamMessage{Type: "error", Error: "Invalid message: expected a JSON object"})
+			continue
+		}
+
+		switch request.Type {
+		case "search":
+			s.search(ctx, request)
+		case "suggest":
+			limit := request.Limit
+			if limit == 0 {
+				limit = search.DefaultSuggestionLimit
+			}
+			if limit < 1 || limit > search.MaxSuggestionLimit {
+				s.send(api.StreamMessage{Type: "error", ID: request.ID, Error: validation.OutOfRange("limit", strconv.Itoa(limit), 1, search.MaxSuggestionLimit).Error()})
+				continue
+			}
+			if utf8.RuneCountInString(request.Query) > search.MaxQueryLength {
+				s.send(api.StreamMessage{Type: "error", ID: request.ID, Error: validation.TooLong("query", search.MaxQueryLength).Error()})
+				continue
+			}
+			s.send(api.StreamMessage{Type: "suggestions", ID: request.ID, Suggestions: search.Suggest(s.app.Documents.Documents(), request.Query, limit)})
+		case "cancel":
+			if s.cancel(request.ID) {
+				s.send(api.StreamMessage{Type: "cancelled", ID: request.ID})
+			} else {
+				s.send(api.StreamMessage{Type: "error", ID: request.ID, Error: fmt.Sprintf("No running search with id %q", request.ID)})
+			}
+		default:
+			s.send(api.StreamMessage{Type: "error", ID: request.ID, Error: fmt.Sprintf("Unknown message type %q. Valid types are: search, suggest, cancel", request.Type)})
+		}
+	}
+}
+
+// search validates a search request and runs it in the background, replacing a running search with the same id
+func (s *streamSession) search(ctx context.Context, request api.StreamRequest) {
+	fail := func(message string) {
+		s.send(api.StreamMessage{Type: "error", ID: request.ID, Error: message})
+	}
+
+	query := strings.TrimSpace(request.Query)
+	if query == "" {
+		fail(validation.Required("query").Error())
+		return
+	}
+	if err := search.ValidateQuery(query); err != nil {
+		fail(err.Error())
+		return
+	}
+
+	modeStr := strings.TrimSpace(request.Mode)
+	if modeStr == "" {
+		modeStr = "basic"
+	}
+	mode, err := search.ValidateSearchMode(modeStr)
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+
+	page, limit := request.Page, request.Limit
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = 10
+	}
+	if page < 1 || page > search.MaxPage {
+		fail(validation.OutOfRange("page", strconv.Itoa(page), 1, search.MaxPage).Error())
+		return
+	}
+	if limit < 1 || limit > 100 {
+		fail(validation.OutOfRange("limit", strconv.Itoa(limit), 1, 100).Error())
+		return
+	}
+
+	statuses, err := search.ParseStatuses(request.Status)
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+	tags, err := search.ParseTags(request.Tags, request.TagsMode)
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+	var ids manticore.IDFilter
+	if ids.IDs, err = search.ParseIDs("ids", request.IDs); err != nil {
+		fail(err.Error())
+		return
+	}
+	if ids.Exclude, err = search.ParseIDs("exclude_ids", request.ExcludeIDs); err != nil {
+		fail(err.Error())
+		return
+	}
+	regex, err := search.ParseRegex(request.Regex)
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+	histograms, err := search.ParseHistograms(request.Histogram)
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+	rescoreWindow := s.app.RescoreWindow
+	if request.RescoreWindow != 0 {
+		if rescoreWindow, err = search.ParseRescoreWindow(strconv.Itoa(request.RescoreWindow), 0); err != nil {
+			fail(err.Error())
+			return
+		}
+	}
+	knn, err := search.ParseKNN(intParam(request.KNNK), intParam(request.KNNEf))
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+	language, err := search.ParseLanguage(request.Language)
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+
+	if s.app.Manticore == nil || !s.app.Manticore.IsConnected() {
+		fail("Search service is not available")
+		return
+	}
+	if err := s.app.Usage.Reserve(s.key, searchUses(mode, query)); err != nil {
+		fail(err.Error())
+		return
+	}
+	if mode.UsesAI() {
+		if err := s.app.validateAISearchAvailability(); err != nil {
+			log.Printf("AI search not available: %v, degrading to hybrid search", err)
+			mode = models.SearchModeHybrid
+		}
+	}
+
+	searchCtx, cancel := context.WithCancel(ctx)
+	s.mutex.Lock()
+	if previous, ok := s.searches[request.ID]; ok {
+		previous()
+	} else if len(s.searches) >= maxStreamSearches {
+		s.mutex.Unlock()
+		cancel()
+		fail(fmt.Sprintf("Too many concurrent searches (at most %d per connection)", maxStreamSearches))
+		return
+	}
+	s.searches[request.ID] = cancel
+	s.mutex.Unlock()
+
+	engine := search.NewSearchEngine(s.app.Manticore, s.app.Vectorizer, s.app.CurrentAIConfig()).WithStatuses(statuses).WithTags(tags).WithIDs(ids).WithRegex(regex).WithHistogramFacets(histograms).WithRescoreWindow(rescoreWindow).WithKNN(knn).WithRelaxation(s.app.Relaxation).WithSpellTolerance(s.app.SpellTolerance).WithStopwords(s.app.Stopwords).WithNormalizer(s.app.Normalizer).WithBoosts(s.app.Boosts).WithFeatures(s.app.Features).WithBlocklist(s.app.Blocklist).WithCalibration(s.app.Calibration).WithCurations(s.app.Curations).WithLanguage(language).WithDebug(request.Debug)
+	if request.Dedup {
+		engine = engine.WithDeduplication(s.app.dedupThreshold())
+	}
+
+	generation := s.app.IndexGeneration()
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.finish(searchCtx, request.ID)
+
+		err := engine.SearchStream(searchCtx, query, mode, page, limit, func(stage string, response *models.SearchResponse, final bool) error {
+			if searchCtx.Err() != nil {
+				return searchCtx.Err()
+			}
+			response.IndexGeneration = generation
+			return s.send(api.StreamMessage{Type: "results", ID: request.ID, Stage: stage, Final: final, Results: searchResponseAPI(response)})
+		})
+		if err != nil && searchCtx.Err() == nil {
+			log.Printf("WebSocket search error (mode: %s): %v", mode, err)
+			fail(fmt.Sprintf("Search failed: %v", err))
+		}
+	}()
+}
+
+// cancel stops the running search with id and reports whether there was one
+func (s *streamSession) cancel(id string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cancel, ok := s.searches[id]
+	if ok {
+		cancel()
+		delete(s.searches, id)
+	}
+	return ok
+}
+
+// finish forgets a completed search unless it was already replaced by a newer one with the same id
+func (s *streamSession) finish(ctx context.Context, id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if cancel, ok := s.searches[id]; ok && ctx.Err() == nil {
+		cancel()
+		delete(s.searches, id)
+	}
+}
+
+func (s *streamSession) send(message api.StreamMessage) error {
+	err := s.conn.WriteJSON(message)
+	if err != nil {
+		log.Printf("WebSocket write failed: %v", err)
+	}
+	return err
+}
+
+// searchResponseAPI converts search results to the API type
+func searchResponseAPI(response *models.SearchResponse) *api.SearchResponse {
+	if response == nil {
+		return nil
+	}
+
+	result := &api.SearchResponse{
+		Documents:     make([]api.SearchResult, 0, len(response.Documents)),
+		Total:         response.Total,
+		TotalRelation: response.TotalRelation,
+		Page:          response.Page,
+		Mode:          response.Mode,
+		Pagination:    response.Pagination,
+		Stale:         response.Stale,
+		Partial:       response.Partial,
+		Continuation:  response.Continuation,
+		Relaxation:    response.Relaxation,
+		RelaxedQuery:  response.RelaxedQuery,
+		Curation:      response.Curation,
+		Collapsed:     response.Collapsed,
+		QueryClass:    response.QueryClass,
+
+		IndexGeneration: response.IndexGeneration,
+	}
+	if debug := response.Debug; debug != nil {
+		result.Debug = &api.SearchDebug{
+			Language:           debug.Language,
+			LanguageConfidence: debug.LanguageConfidence,
+			LanguageSource:     debug.LanguageSource,
+			FullTextQuery:      debug.FullTextQuery,
+			Routing:            debug.Routing,
+		}
+		for _, match := range debug.Features {
+			result.Debug.Features = append(result.Debug.Features, api.FeatureMatch{ID: match.ID, Features: match.Features, Factor: match.Factor})
+		}
+	}
+	for _, item := range response.Documents {
+		var document api.Document
+		if doc := item.Document; doc != nil {
+			document = api.Document{
+				ID:        doc.ID,
+				Title:     doc.Title,
+				URL:       doc.URL,
+				Content:   doc.Content,
+				Snippet:   doc.Snippet,
+				Status:    string(doc.Status),
+				IndexedAt: doc.IndexedAt,
+				UpdatedAt: doc.UpdatedAt,
+				Tags:      doc.Tags,
+			}
+		}
+		result.Documents = append(result.Documents, api.SearchResult{Document: document, Score: item.Score, Pinned: item.Pinned})
+	}
+	if len(response.Facets) > 0 {
+		result.Facets = make(map[string][]api.FacetValue, len(response.Facets))
+		for field, values := range response.Facets {
+			facet := make([]api.FacetValue, 0, len(values))
+			for _, value := range values {
+				facet = append(facet, api.FacetValue{Value: value.Value, Count: value.Count})
+			}
+			result.Facets[field] = facet
+		}
+	}
+	return result
+}
+
+// intParam formats a numeric field of a message as a query parameter, empty when it is 0
+func intParam(value int) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/docstore"
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/websocket"
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+func dialTestWebSocket(t *testing.T, app *AppState) *websocket.Conn {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(app.WebSocketHandler))
+	t.Cleanup(server.Close)
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/ws", time.Second)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func exchange(t *testing.T, conn *websocket.Conn, request api.StreamRequest) api.StreamMessage {
+	t.Helper()
+	if err := conn.WriteJSON(request); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	var message api.StreamMessage
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	return message
+}
+
+func TestWebSocketHandler_Search(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+	conn := dialTestWebSocket(t, app)
+
+	// Full-text searches have a single stage
+	message := exchange(t, conn, api.StreamRequest{Type: "search", ID: "q1", Query: "form", Mode: "fulltext"})
+	if message.Type != "results" || message.ID != "q1" || message.Stage != "final" || !message.Final {
+		t.Fatalf("Unexpected full-text reply: %+v", message)
+	}
+
+	// Hybrid searches send the full-text preview first
+	message = exchange(t, conn, api.StreamRequest{Type: "search", ID: "q2", Query: "form", Mode: "hybrid"})
+	if message.Type != "results" || message.Stage != "fulltext" || message.Final {
+		t.Fatalf("Expected a full-text preview, got %+v", message)
+	}
+	var final api.StreamMessage
+	if err := conn.ReadJSON(&final); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if final.ID != "q2" || final.Stage != "final" || !final.Final {
+		t.Errorf("Expected the final hybrid results, got %+v", final)
+	}
+}
+
+func TestWebSocketHandler_Suggest(t *testing.T) {
+	store := docstore.New(0, 0)
+	store.Replace([]*models.Document{{ID: 1, Title: "Contact form"}, {ID: 2, Title: "Search tips"}}, nil)
+	app := &AppState{Documents: store}
+	conn := dialTestWebSocket(t, app)
+
+	message := exchange(t, conn, api.StreamRequest{Type: "suggest", ID: "s1", Query: "for"})
+	if message.Type != "suggestions" || message.ID != "s1" || len(message.Suggestions) != 1 || message.Suggestions[0] != "Contact form" {
+		t.Errorf("Unexpected suggestions: %+v", message)
+	}
+}
+
+func TestWebSocketHandler_Errors(t *testing.T) {
+	app := &AppState{Manticore: &MockManticoreClient{connected: true, healthy: true}}
+	conn := dialTestWebSocket(t, app)
+
+	requests := []api.StreamRequest{
+		{Type: "search", ID: "e1"},
+		{Type: "search", ID: "e2", Query: "form", Mode: "unknown"},
+		{Type: "search", ID: "e3", Query: "form", Limit: 500},
+		{Type: "search", ID: "e4", Query: "form", TagsMode: "some"},
+		{Type: "cancel", ID: "missing"},
+		{Type: "unknown", ID: "e5"},
+	}
+	for _, request := range requests {
+		if message := exchange(t, conn, request); message.Type != "error" || message.ID != request.ID || message.Error == "" {
+			t.Errorf("Expected an error for %+v, got %+v", request, message)
+		}
+	}
+
+	if err := conn.WriteMessage(websocket.OpText, []byte("not json")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	var message api.StreamMessage
+	if err := conn.ReadJSON(&message); err != nil || message.Type != "error" {
+		t.Errorf("Expected an error for invalid JSON, got %+v %v", message, err)
+	}
+}
+
+func TestWebSocketHandler_RejectsPlainRequests(t *testing.T) {
+	app := &AppState{}
+	w := httptest.NewRecorder()
+	app.WebSocketHandler(w, httptest.NewRequest("GET", "/api/ws", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without an upgrade, got %d", w.Code)
+	}
+}
+
+// blockingClient holds searches until they are cancelled
+type blockingClient struct {
+	MockManticoreClient
+}
+
+func (c *blockingClient) SearchWithContext(ctx context.Context, request manticore.SearchRequest) (*manticore.SearchResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestWebSocketHandler_Cancel(t *testing.T) {
+	app := &AppState{Manticore: &blockingClient{MockManticoreClient{connected: true, healthy: true}}}
+	conn := dialTestWebSocket(t, app)
+
+	if err := conn.WriteJSON(api.StreamRequest{Type: "search", ID: "slow", Query: "form", Mode: "fulltext"}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if message := exchange(t, conn, api.StreamRequest{Type: "cancel", ID: "slow"}); message.Type != "cancelled" || message.ID != "slow" {
+		t.Fatalf("Expected the search to be cancelled, got %+v", message)
+	}
+
+	// The cancelled search does not report an error
+	if message := exchange(t, conn, api.StreamRequest{Type: "suggest", ID: "next", Query: "x"}); message.Type != "suggestions" {
+		t.Errorf("Expected no reply for the cancelled search, got %+v", message)
+	}
+}
@@ -1,10 +1,12 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
@@ -57,6 +59,11 @@ func (c *IntegrationTestClient) WaitForReady(timeout time.Duration) error {
 	return nil
 }
 
+func (c *IntegrationTestClient) WaitForReadyContext(ctx context.Context, opts manticore.ReadyOptions) error {
+	c.logCall("WaitForReadyContext")
+	return nil
+}
+
 func (c *IntegrationTestClient) HealthCheck() error {
 	c.logCall("HealthCheck")
 	return c.healthCheckError
@@ -72,11 +79,61 @@ func (c *IntegrationTestClient) IsConnected() bool {
 	return c.isConnected
 }
 
+func (c *IntegrationTestClient) GetCapabilities() *manticore.Capabilities {
+	c.logCall("GetCapabilities")
+	return nil
+}
+
+func (c *IntegrationTestClient) GetCircuitBreakerTransitions() []manticore.CircuitBreakerTransition {
+	c.logCall("GetCircuitBreakerTransitions")
+	return nil
+}
+
+func (c *IntegrationTestClient) GetResilienceStats() manticore.ResilienceStats {
+	c.logCall("GetResilienceStats")
+	return manticore.ResilienceStats{}
+}
+
+func (c *IntegrationTestClient) GetRecordings() manticore.Recordings {
+	c.logCall("GetRecordings")
+	return manticore.Recordings{}
+}
+
 func (c *IntegrationTestClient) CreateSchema(aiConfig *models.AISearchConfig) error {
 	c.logCall("CreateSchema")
 	return nil
 }
 
+func (c *IntegrationTestClient) MigrateSchema(aiConfig *models.AISearchConfig) (*manticore.MigrationResult, error) {
+	c.logCall("MigrateSchema")
+	return &manticore.MigrationResult{}, nil
+}
+
+func (c *IntegrationTestClient) Backup(path string) (*manticore.BackupResult, error) {
+	c.logCall("Backup")
+	return &manticore.BackupResult{Path: path}, nil
+}
+
+func (c *IntegrationTestClient) ReembedDocuments(ctx context.Context, aiConfig *models.AISearchConfig, progress func(manticore.ReembedProgress)) (*manticore.ReembedResult, error) {
+	c.logCall("ReembedDocuments")
+	return &manticore.ReembedResult{Model: aiConfig.Model}, nil
+}
+
+func (c *IntegrationTestClient) DropStaleDocumentsTables() ([]manticore.DroppedTable, error) {
+	c.logCall("DropStaleDocumentsTables")
+	return nil, nil
+}
+
+func (c *IntegrationTestClient) SetDocumentStatus(id int64, status models.DocumentStatus) error {
+	c.logCall("SetDocumentStatus")
+	return nil
+}
+
+func (c *IntegrationTestClient) SetDocumentTags(id int64, tags []string) error {
+	c.logCall("SetDocumentTags")
+	return nil
+}
+
 func (c *IntegrationTestClient) ResetDatabase() error {
 	c.logCall("ResetDatabase")
 	return nil
@@ -87,6 +144,85 @@ func (c *IntegrationTestClient) TruncateTables() error {
 	return nil
 }
 
+func (c *IntegrationTestClient) TruncateTable(table string) error {
+	c.logCall("TruncateTable")
+	return nil
+}
+
+func (c *IntegrationTestClient) OptimizeTable(table string) error {
+	c.logCall("OptimizeTable")
+	return nil
+}
+
+func (c *IntegrationTestClient) DocumentIDs(table string) ([]int64, error) {
+	c.logCall("DocumentIDs")
+	return nil, nil
+}
+
+func (c *IntegrationTestClient) GetDocumentHashes() (map[int64]string, error) {
+	c.logCall("GetDocumentHashes")
+	return nil, nil
+}
+
+func (c *IntegrationTestClient) GetDocumentTimes() (map[int64]int64, error) {
+	c.logCall("GetDocumentTimes")
+	return nil, nil
+}
+
+func (c *IntegrationTestClient) DeleteDocuments(table string, ids []int64) error {
+	c.logCall("DeleteDocuments")
+	return nil
+}
+
+func (c *IntegrationTestClient) ResetTable(table string, aiConfig *models.AISearchConfig) error {
+	c.logCall("ResetTable")
+	return nil
+}
+
+func (c *IntegrationTestClient) CountDocuments(table string) (int64, error) {
+	c.logCall("CountDocuments", table)
+	return 0, nil
+}
+
+func (c *IntegrationTestClient) SearchWithContext(ctx context.Context, request manticore.SearchRequest) (*manticore.SearchResponse, error) {
+	return c.SearchWithRequest(request)
+}
+
+func (c *IntegrationTestClient) GetAllDocumentsWithVectorsContext(ctx context.Context) ([]*models.Document, [][]float64, error) {
+	return c.GetAllDocumentsWithVectors()
+}
+
+func (c *IntegrationTestClient) GetOperationTimeouts() manticore.OperationTimeouts {
+	return manticore.DefaultOperationTimeouts()
+}
+
+func (c *IntegrationTestClient) CheckHealth() manticore.HealthReport {
+	c.logCall("CheckHealth")
+	if c.healthCheckError != nil {
+		return manticore.HealthReport{State: manticore.HealthStateDown, Error: c.healthCheckError.Error()}
+	}
+	return manticore.HealthReport{State: manticore.HealthStateReady, ServerUp: true, SchemaPresent: true}
+}
+
+func (c *IntegrationTestClient) Count(query string, filters map[string]interface{}) (int64, error) {
+	c.logCall("Count", query, filters)
+	return 0, nil
+}
+
+func (c *IntegrationTestClient) AISearchWithContext(ctx context.Context, query string, model string, limit, offset int) (*manticore.SearchResponse, error) {
+	return c.AISearch(query, model, limit, offset)
+}
+
+func (c *IntegrationTestClient) GetIndexStats(table string) (*manticore.IndexStats, error) {
+	c.logCall("GetIndexStats", table)
+	return &manticore.IndexStats{Table: table}, nil
+}
+
+func (c *IntegrationTestClient) EmbeddingModels(table string) ([]manticore.EmbeddingModel, error) {
+	c.logCall("EmbeddingModels", table)
+	return nil, nil
+}
+
 func (c *IntegrationTestClient) IndexDocument(doc *models.Document, vector []float64) error {
 	c.logCall("IndexDocument", doc.ID, len(vector))
 	return nil
@@ -103,6 +239,25 @@ func (c *IntegrationTestClient) GetAllDocuments() ([]*models.Document, error) {
 	return c.documents, nil
 }
 
+func (c *IntegrationTestClient) GetDocument(id int64) (*models.Document, error) {
+	c.logCall("GetDocument")
+	for _, doc := range c.documents {
+		if int64(doc.ID) == id {
+			return doc, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", manticore.ErrDocumentNotFound, id)
+}
+
+// GetAllDocumentsWithVectors serves the vector search that failed AI searches fall back to
+func (c *IntegrationTestClient) GetAllDocumentsWithVectors() ([]*models.Document, [][]float64, error) {
+	c.logCall("GetAllDocumentsWithVectors")
+	if c.searchError != nil {
+		return nil, nil, c.searchError
+	}
+	return c.documents, nil, nil
+}
+
 func (c *IntegrationTestClient) SearchWithRequest(request manticore.SearchRequest) (*manticore.SearchResponse, error) {
 	c.logCall("SearchWithRequest", request.Index)
 	return nil, nil
@@ -195,24 +350,10 @@ func testEndToEndAISearchFlow(t *testing.T) {
 				client.aiSearchResponse = &manticore.SearchResponse{
 					Took:     5,
 					TimedOut: false,
-					Hits: struct {
-						Total         int32  `json:"total"`
-						TotalRelation string `json:"total_relation"`
-						Hits          []struct {
-							Index  string                 `json:"_index"`
-							ID     int64                  `json:"_id"`
-							Score  float32                `json:"_score"`
-							Source map[string]interface{} `json:"_source"`
-						} `json:"hits"`
-					}{
+					Hits: manticore.SearchHits{
 						Total:         2,
 						TotalRelation: "eq",
-						Hits: []struct {
-							Index  string                 `json:"_index"`
-							ID     int64                  `json:"_id"`
-							Score  float32                `json:"_score"`
-							Source map[string]interface{} `json:"_source"`
-						}{
+						Hits: []manticore.SearchHit{
 							{
 								Index: "documents",
 								ID:    1,
@@ -242,7 +383,7 @@ func testEndToEndAISearchFlow(t *testing.T) {
 			expectedResultCount: 2,
 			expectedMode:        "ai",
 			validateResponse: func(t *testing.T, response *api.APIResponse) {
-				if searchResp, ok := response.Data.(*models.SearchResponse); ok {
+				if searchResp, err := api.DecodeData[api.SearchResponse](*response); err == nil {
 					if searchResp.Mode != string(models.SearchModeAI) {
 						t.Errorf("Expected mode %s, got %s", models.SearchModeAI, searchResp.Mode)
 					}
@@ -285,7 +426,7 @@ func testEndToEndAISearchFlow(t *testing.T) {
 			expectedResultCount: 1,
 			expectedMode:        "hybrid (AI fallback)",
 			validateResponse: func(t *testing.T, response *api.APIResponse) {
-				if searchResp, ok := response.Data.(*models.SearchResponse); ok {
+				if searchResp, err := api.DecodeData[api.SearchResponse](*response); err == nil {
 					if !strings.Contains(searchResp.Mode, "fallback") {
 						t.Errorf("Expected fallback mode, got %s", searchResp.Mode)
 					}
@@ -315,21 +456,21 @@ func testEndToEndAISearchFlow(t *testing.T) {
 			},
 		},
 		{
-			name:  "AI search unavailable",
+			name:  "AI search unavailable degrades to hybrid",
 			query: "unavailable test",
 			mode:  "ai",
 			setupClient: func(client *IntegrationTestClient) {
 				client.aiSearchEnabled = false
 			},
-			expectedStatusCode: http.StatusServiceUnavailable,
-			expectedSuccess:    false,
+			expectedStatusCode: http.StatusOK,
+			expectedSuccess:    true,
 			validateResponse: func(t *testing.T, response *api.APIResponse) {
-				if data, ok := response.Data.(map[string]interface{}); ok {
-					if errorType, exists := data["error_type"]; exists {
-						if errorType != "ai_search_unavailable" {
-							t.Errorf("Expected error_type 'ai_search_unavailable', got %v", errorType)
-						}
+				if searchResp, err := api.DecodeData[api.SearchResponse](*response); err == nil {
+					if searchResp.Mode != "hybrid (AI degraded)" {
+						t.Errorf("Expected degraded mode, got %s", searchResp.Mode)
 					}
+				} else {
+					t.Errorf("Expected SearchResponse in response data")
 				}
 			},
 		},
@@ -343,10 +484,8 @@ func testEndToEndAISearchFlow(t *testing.T) {
 
 			// Create app state
 			app := &handlers.AppState{
-				Documents:  []*models.Document{},
 				Vectorizer: nil,
 				Manticore:  client,
-				Vectors:    [][]float64{},
 				AIConfig: &models.AISearchConfig{
 					Model:   "test-model",
 					Enabled: client.aiSearchEnabled,
@@ -355,8 +494,8 @@ func testEndToEndAISearchFlow(t *testing.T) {
 			}
 
 			// Create request
-			url := fmt.Sprintf("/api/search?query=%s&mode=%s", tt.query, tt.mode)
-			req := httptest.NewRequest("GET", url, nil)
+			target := fmt.Sprintf("/api/search?query=%s&mode=%s", url.QueryEscape(tt.query), tt.mode)
+			req := httptest.NewRequest("GET", target, nil)
 			w := httptest.NewRecorder()
 
 			// Handle request
@@ -505,7 +644,7 @@ func testAISearchErrorHandlingIntegration(t *testing.T) {
 					if strings.Contains(entry, "AISearch") {
 						hasAISearch = true
 					}
-					if strings.Contains(entry, "Search") && !strings.Contains(entry, "AISearch") {
+					if strings.Contains(entry, "GetAllDocumentsWithVectors") {
 						hasFallbackSearch = true
 					}
 				}
@@ -568,10 +707,8 @@ func testAISearchErrorHandlingIntegration(t *testing.T) {
 
 			// Create app state
 			app := &handlers.AppState{
-				Documents:  []*models.Document{},
 				Vectorizer: nil,
 				Manticore:  client,
-				Vectors:    [][]float64{},
 				AIConfig: &models.AISearchConfig{
 					Model:   "test-model",
 					Enabled: true,
@@ -580,8 +717,8 @@ func testAISearchErrorHandlingIntegration(t *testing.T) {
 			}
 
 			// Create request
-			url := fmt.Sprintf("/api/search?query=%s&mode=ai", tt.query)
-			req := httptest.NewRequest("GET", url, nil)
+			target := fmt.Sprintf("/api/search?query=%s&mode=ai", url.QueryEscape(tt.query))
+			req := httptest.NewRequest("GET", target, nil)
 			w := httptest.NewRecorder()
 
 			// Handle request
@@ -704,10 +841,8 @@ func testAISearchStatusIntegration(t *testing.T) {
 
 			// Create app state
 			app := &handlers.AppState{
-				Documents:  []*models.Document{},
 				Vectorizer: nil,
 				Manticore:  client,
-				Vectors:    [][]float64{},
 				AIConfig:   aiConfig,
 			}
 
@@ -732,7 +867,7 @@ func testAISearchStatusIntegration(t *testing.T) {
 				t.Errorf("Expected successful status response")
 			}
 
-			if statusResp, ok := response.Data.(api.StatusResponse); ok {
+			if statusResp, err := api.DecodeData[api.StatusResponse](response); err == nil {
 				if statusResp.AISearchEnabled != tt.expectedEnabled {
 					t.Errorf("Expected AI search enabled %v, got %v", tt.expectedEnabled, statusResp.AISearchEnabled)
 				}
@@ -758,26 +893,15 @@ func testAISearchPerformanceIntegration(t *testing.T) {
 		client.aiSearchResponse = &manticore.SearchResponse{
 			Took:     5,
 			TimedOut: false,
-			Hits: struct {
-				Total         int32  `json:"total"`
-				TotalRelation string `json:"total_relation"`
-				Hits          []struct {
-					Index  string                 `json:"_index"`
-					ID     int64                  `json:"_id"`
-					Score  float32                `json:"_score"`
-					Source map[string]interface{} `json:"_source"`
-				} `json:"hits"`
-			}{
+			Hits: manticore.SearchHits{
 				Total: 10,
 			},
 		}
 
 		// Create app state
 		app := &handlers.AppState{
-			Documents:  []*models.Document{},
 			Vectorizer: nil,
 			Manticore:  client,
-			Vectors:    [][]float64{},
 			AIConfig: &models.AISearchConfig{
 				Model:   "performance-test-model",
 				Enabled: true,
@@ -885,24 +1009,13 @@ func testAISearchPerformanceIntegration(t *testing.T) {
 		// Create test client
 		client := NewIntegrationTestClient()
 		client.aiSearchResponse = &manticore.SearchResponse{
-			Hits: struct {
-				Total         int32  `json:"total"`
-				TotalRelation string `json:"total_relation"`
-				Hits          []struct {
-					Index  string                 `json:"_index"`
-					ID     int64                  `json:"_id"`
-					Score  float32                `json:"_score"`
-					Source map[string]interface{} `json:"_source"`
-				} `json:"hits"`
-			}{Total: 1},
+			Hits: manticore.SearchHits{Total: 1},
 		}
 
 		// Create app state
 		app := &handlers.AppState{
-			Documents:  []*models.Document{},
 			Vectorizer: nil,
 			Manticore:  client,
-			Vectors:    [][]float64{},
 			AIConfig: &models.AISearchConfig{
 				Model:   "memory-test-model",
 				Enabled: true,
@@ -942,24 +1055,13 @@ func BenchmarkAISearchIntegration(b *testing.B) {
 	// Create test client
 	client := NewIntegrationTestClient()
 	client.aiSearchResponse = &manticore.SearchResponse{
-		Hits: struct {
-			Total         int32  `json:"total"`
-			TotalRelation string `json:"total_relation"`
-			Hits          []struct {
-				Index  string                 `json:"_index"`
-				ID     int64                  `json:"_id"`
-				Score  float32                `json:"_score"`
-				Source map[string]interface{} `json:"_source"`
-			} `json:"hits"`
-		}{Total: 5},
+		Hits: manticore.SearchHits{Total: 5},
 	}
 
 	// Create app state
 	app := &handlers.AppState{
-		Documents:  []*models.Document{},
 		Vectorizer: nil,
 		Manticore:  client,
-		Vectors:    [][]float64{},
 		AIConfig: &models.AISearchConfig{
 			Model:   "benchmark-model",
 			Enabled: true,
@@ -0,0 +1,70 @@
+package jsonfile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "items.json")
+
+	if err := WriteAtomic(path, []string{"old"}); err != nil {
+		t.Fatalf("WriteAtomic failed: %v", err)
+	}
+	if err := WriteAtomic(path, []string{"a", "b"}); err != nil {
+		t.Fatalf("WriteAtomic failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	var items []string
+	if err := json.Unmarshal(data, &items); err != nil {
+		t.Fatalf("Failed to decode file: %v", err)
+	}
+	if len(items) != 2 || items[0] != "a" || items[1] != "b" {
+		t.Errorf("Expected the last write, got %v", items)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to list directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only the written file, got %d entries", len(entries))
+	}
+}
+
+func TestWriteAtomicEncodeError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "items.json")
+	if err := os.WriteFile(path, []byte(`["kept"]`), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if err := WriteAtomic(path, make(chan int)); err == nil {
+		t.Fatal("Expected an error encoding a channel")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(data) != `["kept"]` {
+		t.Errorf("Expected the file to be left alone, got %s", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected no temporary file left behind, got %d entries", len(entries))
+	}
+}
+
+func TestWriteAtomicMissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "items.json")
+	if err := WriteAtomic(path, []string{"a"}); err == nil {
+		t.Fatal("Expected an error writing into a missing directory")
+	}
+}
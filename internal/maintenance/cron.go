@@ -0,0 +1,185 @@
+package maintenance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// descriptors are the shorthands accepted in place of five cron fields
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField is the range of one of the five cron fields
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// maxScheduleSearch bounds the search for the next run of a schedule that can never fire, such
+// as February 30th
+const maxScheduleSearch = 5 * 366 * 24 * time.Hour
+
+// Schedule tells when a job runs: either a standard five-field cron expression (minute, hour,
+// day of month, month, day of week) in the server's local time, one of the @hourly, @daily,
+// @weekly, @monthly and @yearly shorthands, or "@every <duration>" for a fixed interval.
+type Schedule struct {
+	expr  string
+	every time.Duration
+
+	// Allowed values of each cron field, indexed by value
+	fields [5][]bool
+	// When both day fields are restricted a day matching either runs, as in cron
+	domRestricted, dowRestricted bool
+}
+
+// ParseSchedule parses a cron expression, shorthand or @every interval
+func ParseSchedule(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	s := &Schedule{expr: expr}
+
+	if rest, ok := strings.CutPrefix(expr, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", expr, err)
+		}
+		if every < time.Minute {
+			return nil, fmt.Errorf("invalid schedule %q: the interval must be at least 1m", expr)
+		}
+		s.every = every
+		return s, nil
+	}
+
+	spec := expr
+	if strings.HasPrefix(expr, "@") {
+		var ok bool
+		if spec, ok = descriptors[expr]; !ok {
+			return nil, fmt.Errorf("unknown schedule %q", expr)
+		}
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields (minute hour day-of-month month day-of-week), got %d", expr, len(parts))
+	}
+	for i, part := range parts {
+		allowed, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", expr, err)
+		}
+		s.fields[i] = allowed
+	}
+	s.domRestricted = !strings.HasPrefix(parts[2], "*")
+	s.dowRestricted = !strings.HasPrefix(parts[4], "*")
+	return s, nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges (a-b) and steps (*/n, a-b/n)
+func parseCronField(part string, field cronField) ([]bool, error) {
+	allowed := make([]bool, field.max+1)
+	for _, item := range strings.Split(part, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			parsed, err := strconv.Atoi(stepPart)
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid step %q in %s", stepPart, field.name)
+			}
+			step = parsed
+		}
+
+		low, high := field.min, field.max
+		if rangePart != "*" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = parseCronValue(lowPart, field); err != nil {
+				return nil, err
+			}
+			high = low
+			if isRange {
+				if high, err = parseCronValue(highPart, field); err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				// "a/n" runs from a to the end of the range
+				high = field.max
+			}
+			if low > high {
+				return nil, fmt.Errorf("invalid range %q in %s", rangePart, field.name)
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			allowed[value] = true
+		}
+	}
+	return allowed, nil
+}
+
+// parseCronValue parses one value of field, accepting 7 for Sunday in the day of week
+func parseCronValue(value string, field cronField) (int, error) {
+	parsed, err := strconv.Atoi(value)
+	if err == nil && field.name == "day of week" && parsed == 7 {
+		return 0, nil
+	}
+	if err != nil || parsed < field.min || parsed > field.max {
+		return 0, fmt.Errorf("invalid %s %q (use %d-%d)", field.name, value, field.min, field.max)
+	}
+	return parsed, nil
+}
+
+// Next returns the first time after t the schedule fires, or the zero time if it never does
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every)
+	}
+
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxScheduleSearch)
+	for next.Before(limit) {
+		switch {
+		case !s.fields[3][int(next.Month())]:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !s.dayMatches(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case !s.fields[1][next.Hour()]:
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+		case !s.fields[0][next.Minute()]:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches reports whether the day of t is allowed by the day of month and day of week fields
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.fields[2][t.Day()]
+	dow := s.fields[4][int(t.Weekday())]
+	if s.domRestricted && s.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// String returns the expression the schedule was parsed from
+func (s *Schedule) String() string {
+	return s.expr
+}
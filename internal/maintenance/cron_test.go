@@ -0,0 +1,69 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2025, 6, 4, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2025, 6, 4, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 6, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2025, 6, 5, 3, 0, 0, 0, time.UTC)},
+		{"30 2-4,22 * * *", time.Date(2025, 6, 4, 22, 30, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2025, 6, 5, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 */3 *", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 15th or any Monday
+		{"0 0 15 * 1", time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 6, 4, 11, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90m", from.Add(90 * time.Minute)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if next := schedule.Next(from); !next.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, next)
+			}
+			if schedule.String() != tt.expr {
+				t.Errorf("Expected the expression back, got %q", schedule.String())
+			}
+		})
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@sometimes",
+		"@every soon",
+		"@every 10s",
+	} {
+		if _, err := ParseSchedule(expr); err == nil {
+			t.Errorf("Expected an error for %q", expr)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Maintenance tasks that can be scheduled
+const (
+	TaskReindex       = "reindex"        // Index the documents of the data directory that changed since they were indexed
+	TaskOptimize      = "optimize"       // Merge the disk chunks of the data tables
+	TaskCacheEviction = "cache_eviction" // Drop expired cached search results and progressive search continuations
+	TaskRetention     = "retention"      // Delete the indexed documents the retention policy expires
+	TaskVectorGC      = "vector_gc"      // Drop stale vector rows and documents tables, then merge the vector table
+)
+
+// Tasks lists every task that can be scheduled
+var Tasks = []string{TaskReindex, TaskOptimize, TaskCacheEviction, TaskRetention, TaskVectorGC}
+
+// Task runs one maintenance task; ctx is cancelled when the scheduler closes
+type Task func(ctx context.Context) error
+
+// Entry schedules a task
+type Entry struct {
+	Task     string
+	Schedule *Schedule
+}
+
+// ParseEntries parses semicolon-separated task=schedule entries, such as
+// "reindex=*/30 * * * *;optimize=@daily". Semicolons separate the entries since cron
+// expressions contain spaces and commas.
+func ParseEntries(value string) ([]Entry, error) {
+	var entries []Entry
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		task, expr, ok := strings.Cut(item, "=")
+		task = strings.TrimSpace(task)
+		if !ok {
+			return nil, fmt.Errorf("invalid schedule entry %q, expected <task>=<schedule>", item)
+		}
+		if !slices.Contains(Tasks, task) {
+			return nil, fmt.Errorf("unknown maintenance task %q (use %s)", task, strings.Join(Tasks, ", "))
+		}
+		if seen[task] {
+			return nil, fmt.Errorf("duplicate maintenance task %q", task)
+		}
+		seen[task] = true
+
+		schedule, err := ParseSchedule(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule of %s: %w", task, err)
+		}
+		entries = append(entries, Entry{Task: task, Schedule: schedule})
+	}
+	return entries, nil
+}
+
+// EntriesFromEnvironment parses MAINTENANCE_SCHEDULE, see ParseEntries; unset schedules nothing
+func EntriesFromEnvironment() ([]Entry, error) {
+	entries, err := ParseEntries(os.Getenv("MAINTENANCE_SCHEDULE"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAINTENANCE_SCHEDULE: %w", err)
+	}
+	return entries, nil
+}
+
+// JobStatus describes a scheduled task and its last run
+type JobStatus struct {
+	Task         string
+	Schedule     string
+	NextRun      time.Time // zero while running or when the schedule never fires again
+	LastRun      time.Time // zero until the first run
+	LastDuration time.Duration
+	LastError    string
+	Runs         int
+	Running      bool
+}
+
+// job is a scheduled task and its status
+type job struct {
+	task     Task
+	schedule *Schedule
+
+	mutex  sync.Mutex
+	status JobStatus
+}
+
+// Scheduler runs maintenance tasks on their schedules. Each task runs in its own goroutine, so a
+// slow task delays only its own next run; runs missed while a task was busy are skipped. A nil
+// *Scheduler is valid and runs nothing.
+type Scheduler struct {
+	jobs []*job
+	now  func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler running the task of each entry; entries without a task in
+// tasks are skipped. Call Start to begin.
+func NewScheduler(entries []Entry, tasks map[string]Task) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{now: time.Now, ctx: ctx, cancel: cancel}
+	for _, entry := range entries {
+		task, ok := tasks[entry.Task]
+		if !ok {
+			log.Printf("[MAINTENANCE] Task %s is not available, skipping it", entry.Task)
+			continue
+		}
+		s.jobs = append(s.jobs, &job{
+			task:     task,
+			schedule: entry.Schedule,
+			status:   JobStatus{Task: entry.Task, Schedule: entry.Schedule.String()},
+		})
+	}
+	return s
+}
+
+// Start runs every task in a background goroutine on its schedule
+func (s *Scheduler) Start() {
+	if s == nil {
+		return
+	}
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go func(j *job) {
+			defer s.wg.Done()
+			s.loop(j)
+		}(j)
+	}
+}
+
+// loop waits for each scheduled time of j and runs its task until the scheduler closes
+func (s *Scheduler) loop(j *job) {
+	for {
+		next := j.schedule.Next(s.now())
+		j.mutex.Lock()
+		j.status.NextRun = next
+		j.mutex.Unlock()
+		if next.IsZero() {
+			log.Printf("[MAINTENANCE] Schedule %q of %s never fires again", j.schedule, j.status.Task)
+			return
+		}
+
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-timer.C:
+			s.run(j)
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// run runs the task of j once and records the outcome
+func (s *Scheduler) run(j *job) {
+	j.mutex.Lock()
+	j.status.Running = true
+	j.status.NextRun = time.Time{}
+	j.mutex.Unlock()
+
+	start := s.now()
+	err := j.task(s.ctx)
+	duration := s.now().Sub(start)
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.status.Running = false
+	j.status.LastRun = start
+	j.status.LastDuration = duration
+	j.status.Runs++
+	j.status.LastError = ""
+	if err != nil {
+		j.status.LastError = err.Error()
+		log.Printf("[MAINTENANCE] Task %s failed after %v: %v", j.status.Task, duration, err)
+		return
+	}
+	log.Printf("[MAINTENANCE] Task %s completed in %v", j.status.Task, duration)
+}
+
+// Status returns the status of every scheduled task
+func (s *Scheduler) Status() []JobStatus {
+	if s == nil {
+		return nil
+	}
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		j.mutex.Lock()
+		statuses = append(statuses, j.status)
+		j.mutex.Unlock()
+	}
+	return statuses
+}
+
+// Close stops scheduling, cancels the context of running tasks and waits for them to return
+func (s *Scheduler) Close() {
+	if s == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
@@ -0,0 +1,81 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseEntries(t *testing.T) {
+	entries, err := ParseEntries(" reindex=*/30 * * * * ; optimize=@daily;")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Task != TaskReindex || entries[0].Schedule.String() != "*/30 * * * *" || entries[1].Task != TaskOptimize {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+
+	if entries, err := ParseEntries(""); err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries, got %v, %v", entries, err)
+	}
+
+	for _, value := range []string{
+		"reindex",
+		"backup=@daily",
+		"reindex=@daily;reindex=@hourly",
+		"optimize=61 * * * *",
+	} {
+		if _, err := ParseEntries(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestSchedulerRun(t *testing.T) {
+	entries, err := ParseEntries("reindex=@hourly;optimize=@daily;cache_eviction=@every 5m")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	calls := 0
+	failure := errors.New("optimize failed")
+	s := NewScheduler(entries, map[string]Task{
+		TaskReindex:  func(ctx context.Context) error { calls++; return nil },
+		TaskOptimize: func(ctx context.Context) error { return failure },
+	})
+	defer s.Close()
+
+	// Tasks without an implementation are skipped
+	if len(s.jobs) != 2 {
+		t.Fatalf("Expected 2 jobs, got %d", len(s.jobs))
+	}
+
+	s.run(s.jobs[0])
+	s.run(s.jobs[1])
+
+	statuses := s.Status()
+	if calls != 1 || statuses[0].Runs != 1 || statuses[0].LastError != "" || statuses[0].LastRun.IsZero() || statuses[0].Running {
+		t.Errorf("Unexpected reindex status: %+v", statuses[0])
+	}
+	if statuses[1].LastError != failure.Error() || statuses[1].Schedule != "@daily" {
+		t.Errorf("Unexpected optimize status: %+v", statuses[1])
+	}
+}
+
+func TestSchedulerClose(t *testing.T) {
+	entries, _ := ParseEntries("reindex=@every 1h")
+	s := NewScheduler(entries, map[string]Task{TaskReindex: func(ctx context.Context) error { return nil }})
+	s.Start()
+	s.Close()
+
+	if status := s.Status()[0]; status.Runs != 0 {
+		t.Errorf("Expected no runs, got %+v", status)
+	}
+
+	var nilScheduler *Scheduler
+	nilScheduler.Start()
+	nilScheduler.Close()
+	if nilScheduler.Status() != nil {
+		t.Error("Expected a nil scheduler to have no status")
+	}
+}
@@ -0,0 +1,70 @@
+package manticore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// maxPooledBufferSize keeps buffers grown by unusually large payloads from pinning memory in the pool
+const maxPooledBufferSize = 1 << 20
+
+// maxLoggedBodySize bounds how much of an error response body is written to the log
+const maxLoggedBodySize = 1000
+
+// bufferPool holds serialization buffers reused across requests
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer returns an empty buffer from the pool. Return it with putBuffer once nothing references its bytes.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns buf to the pool
+func putBuffer(buf *bytes.Buffer) {
+	if buf == nil || buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// encodeJSON appends v to buf as JSON followed by a newline, which is also one NDJSON line
+func encodeJSON(buf *bytes.Buffer, v interface{}) error {
+	return json.NewEncoder(buf).Encode(v)
+}
+
+// pooledBody is a request body that returns its buffer to the pool when the transport closes it.
+// The transport may still be reading the body after Do returns, so the buffer cannot be released earlier.
+type pooledBody struct {
+	*bytes.Reader
+	buf  *bytes.Buffer
+	once sync.Once
+}
+
+// Close returns the buffer to the pool
+func (b *pooledBody) Close() error {
+	b.once.Do(func() {
+		putBuffer(b.buf)
+	})
+	return nil
+}
+
+// newPooledRequest creates a request that sends buf as its body and takes ownership of buf
+func newPooledRequest(ctx context.Context, method, url string, buf *bytes.Buffer) (*http.Request, error) {
+	body := &pooledBody{Reader: bytes.NewReader(buf.Bytes()), buf: buf}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	req.ContentLength = int64(buf.Len())
+	return req, nil
+}
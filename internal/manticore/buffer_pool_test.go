@@ -0,0 +1,162 @@
+package manticore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGetBufferReturnsEmptyBuffer(t *testing.T) {
+	buf := getBuffer()
+	buf.WriteString("leftover payload")
+	putBuffer(buf)
+
+	if reused := getBuffer(); reused.Len() != 0 {
+		t.Errorf("Expected an empty buffer from the pool, got %q", reused.String())
+	}
+}
+
+func TestNewPooledRequestSendsBuffer(t *testing.T) {
+	buf := getBuffer()
+	if err := encodeJSON(buf, map[string]string{"index": "documents"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req, err := newPooledRequest(context.Background(), "POST", "http://localhost:9308/search", buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if req.ContentLength != int64(buf.Len()) {
+		t.Errorf("Expected content length %d, got %d", buf.Len(), req.ContentLength)
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != "{\"index\":\"documents\"}\n" {
+		t.Errorf("Unexpected request body %q", body)
+	}
+
+	// The transport may close the body more than once
+	req.Body.Close()
+	req.Body.Close()
+}
+
+func benchmarkDocuments(n int) []map[string]interface{} {
+	docs := make([]map[string]interface{}, n)
+	for i := range docs {
+		docs[i] = map[string]interface{}{
+			"replace": map[string]interface{}{
+				"index": "documents",
+				"id":    i,
+				"doc": map[string]interface{}{
+					"title":   fmt.Sprintf("Document %d", i),
+					"content": strings.Repeat("benchmark content ", 50),
+					"url":     fmt.Sprintf("http://example.com/%d", i),
+				},
+			},
+		}
+	}
+	return docs
+}
+
+func benchmarkSearchResponse(hits int) []byte {
+	var b strings.Builder
+	b.WriteString(`{"took":3,"timed_out":false,"hits":{"total":`)
+	fmt.Fprintf(&b, "%d", hits)
+	b.WriteString(`,"hits":[`)
+	for i := 0; i < hits; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"_id":%d,"_score":1.5,"_source":{"title":"Document %d","content":"%s","url":"http://example.com/%d"}}`,
+			i, i, strings.Repeat("content ", 40), i)
+	}
+	b.WriteString(`]}}`)
+	return []byte(b.String())
+}
+
+// BenchmarkBulkPayloadBuilder measures the previous NDJSON encoding: json.Marshal per line into a strings.Builder
+func BenchmarkBulkPayloadBuilder(b *testing.B) {
+	docs := benchmarkDocuments(20)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		var builder strings.Builder
+		for _, doc := range docs {
+			line, err := json.Marshal(doc)
+			if err != nil {
+				b.Fatal(err)
+			}
+			builder.Write(line)
+			builder.WriteByte('\n')
+		}
+		_ = strings.NewReader(builder.String())
+	}
+}
+
+// BenchmarkBulkPayloadPooled measures streaming each line into a pooled buffer
+func BenchmarkBulkPayloadPooled(b *testing.B) {
+	docs := benchmarkDocuments(20)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf := getBuffer()
+		for _, doc := range docs {
+			if err := encodeJSON(buf, doc); err != nil {
+				b.Fatal(err)
+			}
+		}
+		_ = bytes.NewReader(buf.Bytes())
+		putBuffer(buf)
+	}
+}
+
+// BenchmarkSearchResponseReadAll measures the previous response handling: io.ReadAll then json.Unmarshal
+func BenchmarkSearchResponseReadAll(b *testing.B) {
+	payload := benchmarkSearchResponse(50)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		body, err := io.ReadAll(bytes.NewReader(payload))
+		if err != nil {
+			b.Fatal(err)
+		}
+		var response SearchResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSearchResponsePooled measures reading the response into a pooled buffer before decoding
+func BenchmarkSearchResponsePooled(b *testing.B) {
+	payload := benchmarkSearchResponse(50)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf := getBuffer()
+		if _, err := buf.ReadFrom(bytes.NewReader(payload)); err != nil {
+			b.Fatal(err)
+		}
+		var response SearchResponse
+		if err := json.Unmarshal(buf.Bytes(), &response); err != nil {
+			b.Fatal(err)
+		}
+		putBuffer(buf)
+	}
+}
+
+// BenchmarkSearchResponseStreaming measures decoding the response as it streams in, as search does
+func BenchmarkSearchResponseStreaming(b *testing.B) {
+	payload := benchmarkSearchResponse(50)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := decodeSearchResponse(bytes.NewReader(payload), 10); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
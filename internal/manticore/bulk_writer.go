@@ -0,0 +1,103 @@
+package manticore
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// bulkWriteBufferSize is how much NDJSON is buffered before it is written to the request body
+const bulkWriteBufferSize = 32 << 10
+
+// bulkSampleSize is how much of a bulk payload is kept for logging
+const bulkSampleSize = 500
+
+// bulkBody is the body of a bulk request, encoded while the transport sends it, so a batch of
+// large documents is never held in memory as a whole
+type bulkBody struct {
+	pipe *io.PipeReader
+	done chan struct{}
+
+	// Valid once done is closed
+	size   int64  // bytes of NDJSON
+	sent   int64  // bytes sent, less than size when compressed
+	sample []byte // the first bulkSampleSize bytes of NDJSON
+	err    error
+}
+
+// newBulkRequest creates a POST /bulk request whose NDJSON body is produced by lines, one line per
+// encoded value. The body is sent with chunked transfer encoding, gzip-compressed when the bulk
+// configuration enables compression. Call wait on the returned body once the request is done.
+func (mc *manticoreHTTPClient) newBulkRequest(ctx context.Context, lines func(encode func(v interface{}) error) error) (*http.Request, *bulkBody, error) {
+	reader, writer := io.Pipe()
+	body := &bulkBody{pipe: reader, done: make(chan struct{})}
+	compress := mc.bulkConfig.Compression
+
+	go func() {
+		defer close(body.done)
+
+		sent := &countingWriter{w: writer}
+		var out io.Writer = sent
+		var gz *gzip.Writer
+		if compress {
+			gz = gzip.NewWriter(sent)
+			out = gz
+		}
+		counted := &countingWriter{w: out, sample: make([]byte, 0, bulkSampleSize)}
+		buffered := bufio.NewWriterSize(counted, bulkWriteBufferSize)
+
+		err := lines(json.NewEncoder(buffered).Encode)
+		if err == nil {
+			err = buffered.Flush()
+		}
+		if err == nil && gz != nil {
+			err = gz.Close()
+		}
+
+		body.size, body.sent, body.sample, body.err = counted.n, sent.n, counted.sample, err
+		writer.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", mc.baseURL+"/bulk", reader)
+	if err != nil {
+		body.wait()
+		return nil, nil, err
+	}
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	return req, body, nil
+}
+
+// wait stops sending the body, if the transport has not read all of it, and returns the error of
+// encoding it. A body the transport stopped reading is not an encoding error.
+func (b *bulkBody) wait() error {
+	b.pipe.Close()
+	<-b.done
+	if errors.Is(b.err, io.ErrClosedPipe) {
+		return nil
+	}
+	return b.err
+}
+
+// countingWriter counts the bytes written to w and keeps the first cap(sample) of them
+type countingWriter struct {
+	w      io.Writer
+	n      int64
+	sample []byte
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if room := cap(c.sample) - len(c.sample); room > 0 {
+		c.sample = append(c.sample, p[:min(room, len(p))]...)
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
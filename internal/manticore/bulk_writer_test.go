@@ -0,0 +1,143 @@
+package manticore
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// bulkLines reads the NDJSON lines of a bulk request, decompressing a gzip body
+func bulkLines(t *testing.T, r *http.Request) []map[string]interface{} {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("Failed to read gzip body: %v", err)
+			return nil
+		}
+		body = gz
+	}
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Errorf("Invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestBulkRequestStreaming(t *testing.T) {
+	for _, compression := range []bool{false, true} {
+		var lines []map[string]interface{}
+		var encoding string
+		var transferEncoding []string
+		server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			encoding = r.Header.Get("Content-Encoding")
+			transferEncoding = r.TransferEncoding
+			lines = bulkLines(t, r)
+			w.Write([]byte(`{"items":[],"errors":false}`))
+		})
+
+		config := DefaultHTTPClientConfig(server.URL)
+		config.BulkConfig.Compression = compression
+		client := NewHTTPClient(config).(*manticoreHTTPClient)
+
+		content := strings.Repeat("large document ", 10000)
+		documents := []*models.Document{{ID: 1, Title: "One", Content: content}, {ID: 2, Title: "Two", Content: content}}
+		if err := client.bulkIndexUnified(documents); err != nil {
+			t.Fatalf("Unexpected error with compression %v: %v", compression, err)
+		}
+		server.Close()
+
+		if len(transferEncoding) != 1 || transferEncoding[0] != "chunked" {
+			t.Errorf("Expected a chunked body, got %v", transferEncoding)
+		}
+		if compression != (encoding == "gzip") {
+			t.Errorf("Expected gzip encoding %v, got %q", compression, encoding)
+		}
+		if len(lines) != 2 {
+			t.Fatalf("Expected 2 NDJSON lines, got %d", len(lines))
+		}
+		doc := lines[1]["replace"].(map[string]interface{})["doc"].(map[string]interface{})
+		if doc["title"] != "Two" || doc["content"] != content {
+			t.Errorf("Unexpected second document: %v", doc["title"])
+		}
+	}
+}
+
+func TestBulkRequestSizes(t *testing.T) {
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	})
+	defer server.Close()
+
+	config := DefaultHTTPClientConfig(server.URL)
+	config.BulkConfig.Compression = true
+	client := NewHTTPClient(config).(*manticoreHTTPClient)
+
+	req, payload, err := client.newBulkRequest(context.Background(), func(encode func(v interface{}) error) error {
+		for i := 0; i < 100; i++ {
+			if err := encode(map[string]string{"text": strings.Repeat("a", 100)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if err := payload.wait(); err != nil {
+		t.Fatalf("Unexpected payload error: %v", err)
+	}
+
+	line := len(`{"text":""}`) + 100 + 1
+	if payload.size != int64(100*line) || payload.sent >= payload.size || payload.sent == 0 {
+		t.Errorf("Expected %d bytes compressed, got size %d and %d sent", 100*line, payload.size, payload.sent)
+	}
+	if len(payload.sample) != bulkSampleSize {
+		t.Errorf("Expected a sample of %d bytes, got %d", bulkSampleSize, len(payload.sample))
+	}
+}
+
+func TestBulkRequestEncodingError(t *testing.T) {
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL)).(*manticoreHTTPClient)
+	encodeErr := errors.New("failed to marshal bulk request")
+	req, payload, err := client.newBulkRequest(context.Background(), func(encode func(v interface{}) error) error {
+		if err := encode(map[string]int{"id": 1}); err != nil {
+			return err
+		}
+		return encodeErr
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.httpClient.Do(req); err == nil {
+		t.Error("Expected the request to fail with an incomplete body")
+	}
+	if err := payload.wait(); !errors.Is(err, encodeErr) {
+		t.Errorf("Expected the encoding error, got %v", err)
+	}
+}
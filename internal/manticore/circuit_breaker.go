@@ -46,6 +46,8 @@ type CircuitBreakerConfig struct {
 	FailureRateThreshold float64       `json:"failure_rate_threshold"` // Failure rate (0.0-1.0) to trigger opening
 	SlidingWindowSize    int           `json:"sliding_window_size"`    // Size of sliding window for failure rate calculation
 	MonitoringInterval   time.Duration `json:"monitoring_interval"`    // Interval for monitoring and state transitions
+
+	Clock Clock `json:"-"` // Time source; nil uses SystemClock
 }
 
 // CircuitBreakerCallback defines callbacks for circuit breaker state changes
@@ -70,6 +72,7 @@ func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
 // CircuitBreaker implements the circuit breaker pattern with enhanced features
 type CircuitBreaker struct {
 	config CircuitBreakerConfig
+	clock  Clock
 
 	// State management
 	state           CircuitBreakerState
@@ -91,9 +94,9 @@ type CircuitBreaker struct {
 	// Thread safety
 	mutex sync.RWMutex
 
-	// Monitoring
-	stopMonitoring chan struct{}
-	monitoring     bool
+	// Monitoring runs until cancelMonitoring is called or the context of the breaker is done
+	cancelMonitoring context.CancelFunc
+	monitoringDone   chan struct{}
 
 	// Callback for state changes
 	callback CircuitBreakerCallback
@@ -115,24 +118,35 @@ type CircuitBreakerStats struct {
 	TotalRequests        int64               `json:"total_requests"`
 	TotalFailures        int64               `json:"total_failures"`
 	TotalSuccesses       int64               `json:"total_successes"`
+	TotalRejections      int64               `json:"total_rejections"`
 	CurrentFailureRate   float64             `json:"current_failure_rate"`
 	LastStateChange      time.Time           `json:"last_state_change"`
 	LastFailureTime      time.Time           `json:"last_failure_time"`
 	StateChanges         int64               `json:"state_changes"`
+	RetryAfter           time.Duration       `json:"retry_after"` // Time until an open circuit allows a recovery attempt
 }
 
-// NewCircuitBreaker creates a new circuit breaker with enhanced features
+// NewCircuitBreaker creates a new circuit breaker with enhanced features. Its monitoring goroutine
+// runs until Close is called.
 func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	return NewCircuitBreakerContext(context.Background(), config)
+}
+
+// NewCircuitBreakerContext is like NewCircuitBreaker, but its monitoring goroutine also stops
+// when ctx is done
+func NewCircuitBreakerContext(ctx context.Context, config CircuitBreakerConfig) *CircuitBreaker {
+	clock := clockOrSystem(config.Clock)
 	cb := &CircuitBreaker{
 		config:          config,
+		clock:           clock,
 		state:           CircuitBreakerClosed,
-		lastStateChange: time.Now(),
+		lastStateChange: clock.Now(),
 		requestWindow:   make([]RequestResult, config.SlidingWindowSize),
-		stopMonitoring:  make(chan struct{}),
+		monitoringDone:  make(chan struct{}),
 	}
 
 	// Start monitoring goroutine
-	cb.startMonitoring()
+	cb.startMonitoring(ctx)
 
 	return cb
 }
@@ -160,8 +174,8 @@ func (cb *CircuitBreaker) Execute(ctx context.Context, operation func(ctx contex
 	// Execute the operation
 	err := operation(ctx)
 
-	// Record the result
-	if err != nil {
+	// Record the result; errors a healthy Manticore answers with don't count against it
+	if err != nil && CategorizeError(err).BackendFailure() {
 		cb.recordFailure(err)
 	} else {
 		cb.recordSuccess()
@@ -181,7 +195,7 @@ func (cb *CircuitBreaker) shouldAllowRequest() bool {
 
 	case CircuitBreakerOpen:
 		// Check if recovery timeout has passed
-		if time.Since(cb.lastFailureTime) >= cb.config.RecoveryTimeout {
+		if cb.clock.Now().Sub(cb.lastFailureTime) >= cb.config.RecoveryTimeout {
 			cb.transitionToHalfOpen()
 			return true
 		}
@@ -211,7 +225,7 @@ func (cb *CircuitBreaker) recordSuccess() {
 
 	// Add to sliding window
 	cb.addToWindow(RequestResult{
-		Timestamp: time.Now(),
+		Timestamp: cb.clock.Now(),
 		Success:   true,
 	})
 
@@ -244,21 +258,13 @@ func (cb *CircuitBreaker) recordFailure(err error) {
 	// Update statistics
 	cb.stats.TotalRequests++
 	cb.stats.TotalFailures++
-	cb.lastFailureTime = time.Now()
-
-	// Classify error type
-	errorType := ErrorTypeUnknown
-	if manticoreErr, ok := err.(*ManticoreError); ok {
-		errorType = manticoreErr.ErrorType
-	} else if connErr, ok := err.(*ConnectionError); ok {
-		errorType = connErr.ErrorType
-	}
+	cb.lastFailureTime = cb.clock.Now()
 
 	// Add to sliding window
 	cb.addToWindow(RequestResult{
-		Timestamp: time.Now(),
+		Timestamp: cb.clock.Now(),
 		Success:   false,
-		ErrorType: errorType,
+		ErrorType: GetErrorType(err),
 	})
 
 	cb.consecutiveFailures++
@@ -284,6 +290,7 @@ func (cb *CircuitBreaker) recordRejection() {
 	defer cb.mutex.Unlock()
 
 	cb.stats.TotalRequests++
+	cb.stats.TotalRejections++
 	// Note: rejections are not counted as failures in statistics
 }
 
@@ -317,7 +324,7 @@ func (cb *CircuitBreaker) calculateCurrentFailureRate() float64 {
 
 	totalRequests := 0
 	failures := 0
-	cutoff := time.Now().Add(-cb.config.MonitoringInterval * 5) // Consider last 5 monitoring intervals
+	cutoff := cb.clock.Now().Add(-cb.config.MonitoringInterval * 5) // Consider last 5 monitoring intervals
 
 	for _, result := range cb.requestWindow {
 		if !result.Timestamp.IsZero() && result.Timestamp.After(cutoff) {
@@ -349,7 +356,7 @@ func (cb *CircuitBreaker) transitionToClosed() {
 		oldState := cb.state
 		log.Printf("Circuit breaker: transitioning from %s to CLOSED", cb.state)
 		cb.state = CircuitBreakerClosed
-		cb.lastStateChange = time.Now()
+		cb.lastStateChange = cb.clock.Now()
 		cb.consecutiveFailures = 0
 		cb.consecutiveSuccesses = 0
 		cb.halfOpenCalls = 0
@@ -369,7 +376,7 @@ func (cb *CircuitBreaker) transitionToOpen() {
 		log.Printf("Circuit breaker: transitioning from %s to OPEN after %d consecutive failures",
 			cb.state, cb.consecutiveFailures)
 		cb.state = CircuitBreakerOpen
-		cb.lastStateChange = time.Now()
+		cb.lastStateChange = cb.clock.Now()
 		cb.halfOpenCalls = 0
 		cb.consecutiveSuccesses = 0
 		cb.stats.StateChanges++
@@ -387,7 +394,7 @@ func (cb *CircuitBreaker) transitionToHalfOpen() {
 		oldState := cb.state
 		log.Printf("Circuit breaker: transitioning from %s to HALF-OPEN for recovery test", cb.state)
 		cb.state = CircuitBreakerHalfOpen
-		cb.lastStateChange = time.Now()
+		cb.lastStateChange = cb.clock.Now()
 		cb.halfOpenCalls = 0
 		cb.consecutiveSuccesses = 0
 		cb.stats.StateChanges++
@@ -426,6 +433,11 @@ func (cb *CircuitBreaker) GetStats() CircuitBreakerStats {
 	stats.CurrentFailureRate = cb.calculateCurrentFailureRate()
 	stats.LastStateChange = cb.lastStateChange
 	stats.LastFailureTime = cb.lastFailureTime
+	if cb.state == CircuitBreakerOpen {
+		if remaining := cb.lastStateChange.Add(cb.config.RecoveryTimeout).Sub(cb.clock.Now()); remaining > 0 {
+			stats.RetryAfter = remaining
+		}
+	}
 
 	return stats
 }
@@ -465,36 +477,26 @@ func (cb *CircuitBreaker) ForceOpen() {
 
 // Monitoring
 
-// startMonitoring starts the background monitoring goroutine
-func (cb *CircuitBreaker) startMonitoring() {
-	if cb.monitoring {
-		return
-	}
-
-	cb.monitoring = true
-	go cb.monitoringLoop()
+// startMonitoring starts the background monitoring goroutine, registered as live until it stops
+func (cb *CircuitBreaker) startMonitoring(ctx context.Context) {
+	ctx, cb.cancelMonitoring = context.WithCancel(ctx)
+	circuitBreakers.register(cb)
+	go cb.monitoringLoop(ctx)
 }
 
-// stopMonitoringLoop stops the background monitoring goroutine
-func (cb *CircuitBreaker) stopMonitoringLoop() {
-	if !cb.monitoring {
-		return
-	}
-
-	close(cb.stopMonitoring)
-	cb.monitoring = false
-}
+// monitoringLoop runs the background monitoring until ctx is done
+func (cb *CircuitBreaker) monitoringLoop(ctx context.Context) {
+	defer close(cb.monitoringDone)
+	defer circuitBreakers.unregister(cb)
 
-// monitoringLoop runs the background monitoring
-func (cb *CircuitBreaker) monitoringLoop() {
-	ticker := time.NewTicker(cb.config.MonitoringInterval)
-	defer ticker.Stop()
+	ticks, stop := cb.clock.NewTicker(cb.config.MonitoringInterval)
+	defer stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticks:
 			cb.performMonitoringCheck()
-		case <-cb.stopMonitoring:
+		case <-ctx.Done():
 			return
 		}
 	}
@@ -515,28 +517,108 @@ func (cb *CircuitBreaker) performMonitoringCheck() {
 	// }
 }
 
-// Close gracefully shuts down the circuit breaker
+// Close stops the monitoring goroutine and waits for it to exit. It is safe to call more than
+// once and from several goroutines.
 func (cb *CircuitBreaker) Close() {
-	cb.stopMonitoringLoop()
+	cb.cancelMonitoring()
+	<-cb.monitoringDone
+}
+
+// circuitBreakers registers the circuit breakers whose monitoring goroutine is running
+var circuitBreakers = &circuitBreakerRegistry{breakers: make(map[*CircuitBreaker]struct{})}
+
+type circuitBreakerRegistry struct {
+	mutex    sync.Mutex
+	breakers map[*CircuitBreaker]struct{}
+}
+
+func (r *circuitBreakerRegistry) register(cb *CircuitBreaker) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.breakers[cb] = struct{}{}
+}
+
+func (r *circuitBreakerRegistry) unregister(cb *CircuitBreaker) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.breakers, cb)
+}
+
+// LiveCircuitBreakers returns the number of circuit breakers that have not been closed. A number
+// that keeps growing means clients are created without being closed.
+func LiveCircuitBreakers() int {
+	circuitBreakers.mutex.Lock()
+	defer circuitBreakers.mutex.Unlock()
+	return len(circuitBreakers.breakers)
 }
 
 // CircuitBreakerWithRetry combines circuit breaker with retry mechanism
 type CircuitBreakerWithRetry struct {
+	ctx            context.Context // ends the monitoring of every circuit breaker
 	circuitBreaker *CircuitBreaker
 	retryManager   *RetryManager
+	classManagers  map[OperationClass]*RetryManager   // per-class retry policies
+	classBreakers  map[OperationClass]*CircuitBreaker // classes that don't share the circuit breaker
 }
 
 // NewCircuitBreakerWithRetry creates a new circuit breaker integrated with retry mechanism
 func NewCircuitBreakerWithRetry(cbConfig CircuitBreakerConfig, retryConfig RetryConfig) *CircuitBreakerWithRetry {
+	return NewCircuitBreakerWithRetryContext(context.Background(), cbConfig, retryConfig)
+}
+
+// NewCircuitBreakerWithRetryContext is like NewCircuitBreakerWithRetry, but the monitoring of its
+// circuit breakers also stops when ctx is done
+func NewCircuitBreakerWithRetryContext(ctx context.Context, cbConfig CircuitBreakerConfig, retryConfig RetryConfig) *CircuitBreakerWithRetry {
 	return &CircuitBreakerWithRetry{
-		circuitBreaker: NewCircuitBreaker(cbConfig),
+		ctx:            ctx,
+		circuitBreaker: NewCircuitBreakerContext(ctx, cbConfig),
 		retryManager:   NewRetryManager(retryConfig),
+		classManagers:  make(map[OperationClass]*RetryManager),
+		classBreakers:  make(map[OperationClass]*CircuitBreaker),
 	}
 }
 
-// SetCallback sets the callback for circuit breaker state changes
+// SetRetryPolicy sets the retry policy used by ExecuteClass for an operation class.
+// It must be called before the breaker is shared between goroutines.
+func (cbr *CircuitBreakerWithRetry) SetRetryPolicy(class OperationClass, retryConfig RetryConfig) {
+	cbr.classManagers[class] = NewRetryManager(retryConfig)
+}
+
+// SetCircuitBreaker gives an operation class its own circuit breaker, so its failures don't open
+// the breaker of other operations and theirs don't reject it. It must be called before SetCallback
+// and before the breaker is shared between goroutines.
+func (cbr *CircuitBreakerWithRetry) SetCircuitBreaker(class OperationClass, cbConfig CircuitBreakerConfig) {
+	if breaker, ok := cbr.classBreakers[class]; ok {
+		breaker.Close()
+	}
+	cbr.classBreakers[class] = NewCircuitBreakerContext(cbr.ctx, cbConfig)
+}
+
+// SetCallback sets the callback for state changes of every circuit breaker. Changes of a class
+// breaker are reported with the class before the reason.
 func (cbr *CircuitBreakerWithRetry) SetCallback(callback CircuitBreakerCallback) {
 	cbr.circuitBreaker.SetCallback(callback)
+	for class, breaker := range cbr.classBreakers {
+		breaker.SetCallback(classCallback{class: class, callback: callback})
+	}
+}
+
+// classCallback reports the state changes of the circuit breaker of an operation class
+type classCallback struct {
+	class    OperationClass
+	callback CircuitBreakerCallback
+}
+
+func (c classCallback) OnStateChange(oldState, newState CircuitBreakerState, reason string) {
+	c.callback.OnStateChange(oldState, newState, fmt.Sprintf("%s: %s", c.class, reason))
+}
+
+// breaker returns the circuit breaker of an operation class
+func (cbr *CircuitBreakerWithRetry) breaker(class OperationClass) *CircuitBreaker {
+	if breaker, ok := cbr.classBreakers[class]; ok {
+		return breaker
+	}
+	return cbr.circuitBreaker
 }
 
 // Execute executes an operation with both circuit breaker protection and retry logic
@@ -550,17 +632,54 @@ func (cbr *CircuitBreakerWithRetry) Execute(ctx context.Context, endpoint, metho
 	return cbr.retryManager.Execute(ctx, endpoint, method, circuitBreakerOperation)
 }
 
+// ExecuteClass is like Execute but retries with the policy of the operation class.
+// Classes without a policy use the default retry configuration.
+func (cbr *CircuitBreakerWithRetry) ExecuteClass(ctx context.Context, class OperationClass, endpoint, method string, operation func(ctx context.Context) error) error {
+	retryManager, ok := cbr.classManagers[class]
+	if !ok {
+		retryManager = cbr.retryManager
+	}
+
+	breaker := cbr.breaker(class)
+	circuitBreakerOperation := func(ctx context.Context, retryCtx *RetryContext) error {
+		return breaker.Execute(ctx, operation)
+	}
+	return retryManager.Execute(ctx, endpoint, method, circuitBreakerOperation)
+}
+
 // GetCircuitBreakerStats returns circuit breaker statistics
 func (cbr *CircuitBreakerWithRetry) GetCircuitBreakerStats() CircuitBreakerStats {
 	return cbr.circuitBreaker.GetStats()
 }
 
+// GetCircuitBreakerStatsByClass returns the statistics of each operation class with its own
+// circuit breaker
+func (cbr *CircuitBreakerWithRetry) GetCircuitBreakerStatsByClass() map[OperationClass]CircuitBreakerStats {
+	stats := make(map[OperationClass]CircuitBreakerStats, len(cbr.classBreakers))
+	for class, breaker := range cbr.classBreakers {
+		stats[class] = breaker.GetStats()
+	}
+	return stats
+}
+
 // GetRetryStats returns retry manager statistics
 func (cbr *CircuitBreakerWithRetry) GetRetryStats() RetryStats {
 	return cbr.retryManager.GetRetryStats()
 }
 
-// Close gracefully shuts down both circuit breaker and retry manager
+// GetRetryStatsByClass returns retry statistics for each operation class with its own policy
+func (cbr *CircuitBreakerWithRetry) GetRetryStatsByClass() map[OperationClass]RetryStats {
+	stats := make(map[OperationClass]RetryStats, len(cbr.classManagers))
+	for class, retryManager := range cbr.classManagers {
+		stats[class] = retryManager.GetRetryStats()
+	}
+	return stats
+}
+
+// Close stops the monitoring of every circuit breaker. It is safe to call more than once.
 func (cbr *CircuitBreakerWithRetry) Close() {
 	cbr.circuitBreaker.Close()
+	for _, breaker := range cbr.classBreakers {
+		breaker.Close()
+	}
 }
@@ -0,0 +1,211 @@
+package manticore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRecordedTransitions bounds the transition history kept for the status endpoint
+const maxRecordedTransitions = 20
+
+// Webhook payload formats
+const (
+	WebhookFormatJSON  = "json"
+	WebhookFormatSlack = "slack"
+)
+
+// WebhookConfig configures delivery of circuit breaker state changes to an external URL
+type WebhookConfig struct {
+	URL     string        `json:"url"`
+	Format  string        `json:"format"` // "json" or "slack"; detected from the URL when empty
+	Timeout time.Duration `json:"timeout"`
+}
+
+// CircuitBreakerTransition describes a single circuit breaker state change
+type CircuitBreakerTransition struct {
+	From                string    `json:"from"`
+	To                  string    `json:"to"`
+	Reason              string    `json:"reason"`
+	Endpoint            string    `json:"endpoint"`
+	Timestamp           time.Time `json:"timestamp"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	TotalFailures       int64     `json:"total_failures"`
+	TotalRequests       int64     `json:"total_requests"`
+	FailureRate         float64   `json:"failure_rate"`
+}
+
+// CircuitBreakerNotifier records circuit breaker transitions and optionally posts them to a webhook.
+// OnStateChange is called with the circuit breaker lock held, so statistics are collected and
+// delivered asynchronously by a background worker.
+type CircuitBreakerNotifier struct {
+	endpoint      string
+	webhook       WebhookConfig
+	httpClient    *http.Client
+	statsProvider func() CircuitBreakerStats
+
+	mutex       sync.RWMutex
+	transitions []CircuitBreakerTransition
+
+	queue    chan CircuitBreakerTransition
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewCircuitBreakerNotifier creates a notifier for the given endpoint. Webhook delivery is disabled when webhook.URL is empty.
+func NewCircuitBreakerNotifier(endpoint string, webhook WebhookConfig, statsProvider func() CircuitBreakerStats) *CircuitBreakerNotifier {
+	if webhook.Timeout <= 0 {
+		webhook.Timeout = 10 * time.Second
+	}
+	if webhook.Format == "" {
+		webhook.Format = WebhookFormatJSON
+		if parsed, err := url.Parse(webhook.URL); err == nil && strings.HasSuffix(parsed.Hostname(), "hooks.slack.com") {
+			webhook.Format = WebhookFormatSlack
+		}
+	}
+
+	n := &CircuitBreakerNotifier{
+		endpoint:      endpoint,
+		webhook:       webhook,
+		httpClient:    &http.Client{Timeout: webhook.Timeout},
+		statsProvider: statsProvider,
+		queue:         make(chan CircuitBreakerTransition, maxRecordedTransitions),
+		stop:          make(chan struct{}),
+	}
+
+	n.wg.Add(1)
+	go n.worker()
+
+	return n
+}
+
+// OnStateChange implements CircuitBreakerCallback
+func (n *CircuitBreakerNotifier) OnStateChange(oldState, newState CircuitBreakerState, reason string) {
+	transition := CircuitBreakerTransition{
+		From:      oldState.String(),
+		To:        newState.String(),
+		Reason:    reason,
+		Endpoint:  n.endpoint,
+		Timestamp: time.Now(),
+	}
+
+	select {
+	case n.queue <- transition:
+	default:
+		log.Printf("[CIRCUIT_BREAKER] [NOTIFIER] Queue full, dropping %s -> %s notification", transition.From, transition.To)
+	}
+}
+
+// RecentTransitions returns the most recent transitions, newest first
+func (n *CircuitBreakerNotifier) RecentTransitions() []CircuitBreakerTransition {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
+	result := make([]CircuitBreakerTransition, len(n.transitions))
+	for i, transition := range n.transitions {
+		result[len(n.transitions)-1-i] = transition
+	}
+	return result
+}
+
+// Close stops the background worker after pending notifications are processed
+func (n *CircuitBreakerNotifier) Close() {
+	n.stopOnce.Do(func() {
+		close(n.stop)
+	})
+	n.wg.Wait()
+}
+
+func (n *CircuitBreakerNotifier) worker() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case transition := <-n.queue:
+			n.process(transition)
+		case <-n.stop:
+			for {
+				select {
+				case transition := <-n.queue:
+					n.process(transition)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (n *CircuitBreakerNotifier) process(transition CircuitBreakerTransition) {
+	if n.statsProvider != nil {
+		stats := n.statsProvider()
+		transition.ConsecutiveFailures = stats.ConsecutiveFailures
+		transition.TotalFailures = stats.TotalFailures
+		transition.TotalRequests = stats.TotalRequests
+		transition.FailureRate = stats.CurrentFailureRate
+	}
+
+	n.mutex.Lock()
+	n.transitions = append(n.transitions, transition)
+	if len(n.transitions) > maxRecordedTransitions {
+		n.transitions = n.transitions[len(n.transitions)-maxRecordedTransitions:]
+	}
+	n.mutex.Unlock()
+
+	if n.webhook.URL == "" {
+		return
+	}
+
+	if err := n.send(transition); err != nil {
+		log.Printf("[CIRCUIT_BREAKER] [NOTIFIER] Failed to deliver %s -> %s notification: %v", transition.From, transition.To, err)
+		return
+	}
+	log.Printf("[CIRCUIT_BREAKER] [NOTIFIER] Delivered %s -> %s notification for %s", transition.From, transition.To, transition.Endpoint)
+}
+
+func (n *CircuitBreakerNotifier) send(transition CircuitBreakerTransition) error {
+	var payload interface{} = transition
+	if n.webhook.Format == WebhookFormatSlack {
+		payload = map[string]string{"text": formatSlackTransition(transition)}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %v", err)
+	}
+
+	resp, err := n.httpClient.Post(n.webhook.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func formatSlackTransition(t CircuitBreakerTransition) string {
+	return fmt.Sprintf("Manticore circuit breaker for %s: %s -> %s (%s). Consecutive failures: %d, failure rate: %.0f%%, failures: %d/%d requests",
+		t.Endpoint, t.From, t.To, t.Reason, t.ConsecutiveFailures, t.FailureRate*100, t.TotalFailures, t.TotalRequests)
+}
+
+// MultiCircuitBreakerCallback fans a state change out to several callbacks
+type MultiCircuitBreakerCallback []CircuitBreakerCallback
+
+// OnStateChange implements CircuitBreakerCallback
+func (m MultiCircuitBreakerCallback) OnStateChange(oldState, newState CircuitBreakerState, reason string) {
+	for _, callback := range m {
+		if callback != nil {
+			callback.OnStateChange(oldState, newState, reason)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package manticore
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerNotifierWebhook(t *testing.T) {
+	received := make(chan CircuitBreakerTransition, 1)
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var transition CircuitBreakerTransition
+		if err := json.NewDecoder(r.Body).Decode(&transition); err != nil {
+			t.Errorf("Failed to decode webhook payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+		received <- transition
+	})
+	defer server.Close()
+
+	stats := func() CircuitBreakerStats {
+		return CircuitBreakerStats{ConsecutiveFailures: 5, TotalFailures: 7, TotalRequests: 10, CurrentFailureRate: 0.7}
+	}
+	notifier := NewCircuitBreakerNotifier("http://manticore:9308", WebhookConfig{URL: server.URL}, stats)
+	defer notifier.Close()
+
+	notifier.OnStateChange(CircuitBreakerClosed, CircuitBreakerOpen, "too many failures (5)")
+
+	select {
+	case transition := <-received:
+		if transition.From != "CLOSED" || transition.To != "OPEN" {
+			t.Errorf("Unexpected transition: %s -> %s", transition.From, transition.To)
+		}
+		if transition.Endpoint != "http://manticore:9308" {
+			t.Errorf("Expected endpoint in payload, got %q", transition.Endpoint)
+		}
+		if transition.ConsecutiveFailures != 5 || transition.FailureRate != 0.7 {
+			t.Errorf("Expected failure stats in payload, got %+v", transition)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Webhook was not called")
+	}
+
+	recent := notifier.RecentTransitions()
+	if len(recent) != 1 || recent[0].To != "OPEN" {
+		t.Errorf("Expected transition to be recorded, got %+v", recent)
+	}
+}
+
+func TestCircuitBreakerNotifierSlackFormat(t *testing.T) {
+	received := make(chan map[string]string, 1)
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		received <- payload
+	})
+	defer server.Close()
+
+	notifier := NewCircuitBreakerNotifier("http://manticore:9308", WebhookConfig{URL: server.URL, Format: WebhookFormatSlack}, nil)
+	defer notifier.Close()
+
+	notifier.OnStateChange(CircuitBreakerOpen, CircuitBreakerHalfOpen, "recovery timeout reached")
+
+	select {
+	case payload := <-received:
+		if !strings.Contains(payload["text"], "OPEN -> HALF-OPEN") {
+			t.Errorf("Expected Slack text with transition, got %q", payload["text"])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Webhook was not called")
+	}
+}
+
+func TestCircuitBreakerNotifierRecordsWithoutWebhook(t *testing.T) {
+	notifier := NewCircuitBreakerNotifier("http://manticore:9308", WebhookConfig{}, nil)
+
+	for i := 0; i < maxRecordedTransitions+5; i++ {
+		notifier.OnStateChange(CircuitBreakerClosed, CircuitBreakerOpen, "test")
+		notifier.OnStateChange(CircuitBreakerOpen, CircuitBreakerClosed, "test")
+	}
+	notifier.Close()
+
+	recent := notifier.RecentTransitions()
+	if len(recent) == 0 || len(recent) > maxRecordedTransitions {
+		t.Fatalf("Expected between 1 and %d transitions, got %d", maxRecordedTransitions, len(recent))
+	}
+}
@@ -3,6 +3,8 @@ package manticore
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -10,9 +12,11 @@ import (
 func TestCircuitBreaker_Execute(t *testing.T) {
 	config := DefaultCircuitBreakerConfig()
 	config.FailureThreshold = 3
-	config.RecoveryTimeout = 100 * time.Millisecond // Short timeout for testing
+	config.RecoveryTimeout = 100 * time.Millisecond
 	config.HalfOpenMaxCalls = 2
 	config.SuccessThreshold = 2
+	clock := newFakeClock()
+	config.Clock = clock
 
 	cb := NewCircuitBreaker(config)
 	defer cb.Close()
@@ -85,7 +89,7 @@ func TestCircuitBreaker_Execute(t *testing.T) {
 		cb.ForceOpen()
 
 		// Wait for recovery timeout
-		time.Sleep(config.RecoveryTimeout + 10*time.Millisecond)
+		clock.Advance(config.RecoveryTimeout)
 
 		attempts := 0
 		operation := func(ctx context.Context) error {
@@ -107,7 +111,7 @@ func TestCircuitBreaker_Execute(t *testing.T) {
 		// Force circuit to half-open state
 		cb.Reset()
 		cb.ForceOpen()
-		time.Sleep(config.RecoveryTimeout + 10*time.Millisecond)
+		clock.Advance(config.RecoveryTimeout)
 
 		// Execute successful operations to close the circuit
 		operation := func(ctx context.Context) error {
@@ -130,7 +134,7 @@ func TestCircuitBreaker_Execute(t *testing.T) {
 		// Force circuit to half-open state
 		cb.Reset()
 		cb.ForceOpen()
-		time.Sleep(config.RecoveryTimeout + 10*time.Millisecond)
+		clock.Advance(config.RecoveryTimeout)
 
 		// First request succeeds (transitions to half-open)
 		err := cb.Execute(context.Background(), func(ctx context.Context) error {
@@ -330,6 +334,102 @@ func TestCircuitBreakerWithRetry_Execute(t *testing.T) {
 	})
 }
 
+func TestCircuitBreakerWithRetry_ClassBreaker(t *testing.T) {
+	cbConfig := DefaultCircuitBreakerConfig()
+	cbConfig.FailureThreshold = 2
+	cbConfig.RecoveryTimeout = time.Minute
+
+	retryConfig := DefaultRetryConfig()
+	retryConfig.MaxAttempts = 1
+
+	cbr := NewCircuitBreakerWithRetry(cbConfig, retryConfig)
+	cbr.SetCircuitBreaker(OperationClassEmbedding, cbConfig)
+	defer cbr.Close()
+
+	var reasons []string
+	cbr.SetCallback(callbackFunc(func(oldState, newState CircuitBreakerState, reason string) {
+		reasons = append(reasons, reason)
+	}))
+
+	// Failing embeddings open their own breaker only
+	for i := 0; i < cbConfig.FailureThreshold; i++ {
+		cbr.ExecuteClass(context.Background(), OperationClassEmbedding, "/search", "POST", func(ctx context.Context) error {
+			return errors.New("connection refused")
+		})
+	}
+	if state := cbr.GetCircuitBreakerStatsByClass()[OperationClassEmbedding].State; state != CircuitBreakerOpen {
+		t.Errorf("Expected the embedding breaker to be OPEN, got %v", state)
+	}
+	if state := cbr.GetCircuitBreakerStats().State; state != CircuitBreakerClosed {
+		t.Errorf("Expected the shared breaker to stay CLOSED, got %v", state)
+	}
+	if len(reasons) == 0 || !strings.HasPrefix(reasons[0], "embedding: ") {
+		t.Errorf("Expected the transition reason to name the class, got %q", reasons)
+	}
+
+	// Searches still run
+	if err := cbr.ExecuteClass(context.Background(), OperationClassSearch, "/search", "POST", func(ctx context.Context) error {
+		return nil
+	}); err != nil {
+		t.Errorf("Expected searches to pass the shared breaker, got %v", err)
+	}
+	if err := cbr.ExecuteClass(context.Background(), OperationClassEmbedding, "/search", "POST", func(ctx context.Context) error {
+		return nil
+	}); err == nil {
+		t.Error("Expected embeddings to be rejected by their open breaker")
+	}
+}
+
+// callbackFunc adapts a function to CircuitBreakerCallback
+type callbackFunc func(oldState, newState CircuitBreakerState, reason string)
+
+func (f callbackFunc) OnStateChange(oldState, newState CircuitBreakerState, reason string) {
+	f(oldState, newState, reason)
+}
+
+func TestCircuitBreakerCloseStopsMonitoring(t *testing.T) {
+	live := LiveCircuitBreakers()
+
+	cb := NewCircuitBreaker(DefaultCircuitBreakerConfig())
+	if got := LiveCircuitBreakers(); got != live+1 {
+		t.Fatalf("Expected %d live circuit breakers, got %d", live+1, got)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cb.Close()
+		}()
+	}
+	wg.Wait()
+	cb.Close()
+	if got := LiveCircuitBreakers(); got != live {
+		t.Errorf("Expected closing to unregister the circuit breaker, got %d live instead of %d", got, live)
+	}
+}
+
+func TestCircuitBreakerWithRetryContext(t *testing.T) {
+	live := LiveCircuitBreakers()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cbr := NewCircuitBreakerWithRetryContext(ctx, DefaultCircuitBreakerConfig(), DefaultRetryConfig())
+	cbr.SetCircuitBreaker(OperationClassEmbedding, DefaultCircuitBreakerConfig())
+	cbr.SetCircuitBreaker(OperationClassEmbedding, DefaultCircuitBreakerConfig())
+	if got := LiveCircuitBreakers(); got != live+2 {
+		t.Fatalf("Expected replacing a class breaker to close the old one, got %d live instead of %d", got, live+2)
+	}
+
+	cancel()
+	<-cbr.circuitBreaker.monitoringDone
+	<-cbr.classBreakers[OperationClassEmbedding].monitoringDone
+	if got := LiveCircuitBreakers(); got != live {
+		t.Errorf("Expected the context to stop monitoring, got %d live instead of %d", got, live)
+	}
+	cbr.Close()
+}
+
 func TestDefaultCircuitBreakerConfig(t *testing.T) {
 	config := DefaultCircuitBreakerConfig()
 
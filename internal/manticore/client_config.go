@@ -42,6 +42,32 @@ func LoadHTTPConfigFromEnvironment() (*HTTPClientConfig, error) {
 		config.Timeout = timeout
 	}
 
+	// A network profile presets the transport; the variables below override its settings
+	if profile := os.Getenv("MANTICORE_HTTP_NETWORK_PROFILE"); profile != "" {
+		if err := config.ApplyNetworkProfile(NetworkProfile(profile)); err != nil {
+			return nil, fmt.Errorf("invalid MANTICORE_HTTP_NETWORK_PROFILE: %w", err)
+		}
+	}
+
+	transportSwitches := []struct {
+		name   string
+		target *bool
+		invert bool
+	}{
+		{"MANTICORE_HTTP_HTTP2", &config.EnableHTTP2, false},
+		{"MANTICORE_HTTP_KEEP_ALIVES", &config.DisableKeepAlives, true},
+		{"MANTICORE_HTTP_COMPRESSION", &config.DisableCompression, true},
+	}
+	for _, s := range transportSwitches {
+		if valueStr := os.Getenv(s.name); valueStr != "" {
+			value, err := strconv.ParseBool(valueStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", s.name, err)
+			}
+			*s.target = value != s.invert
+		}
+	}
+
 	// Parse connection pool configuration
 	if maxIdleConnsStr := os.Getenv("MANTICORE_HTTP_MAX_IDLE_CONNS"); maxIdleConnsStr != "" {
 		maxIdleConns, err := strconv.Atoi(maxIdleConnsStr)
@@ -59,6 +85,17 @@ func LoadHTTPConfigFromEnvironment() (*HTTPClientConfig, error) {
 		config.MaxIdleConnsPerHost = maxIdleConnsPerHost
 	}
 
+	if maxConnsPerHostStr := os.Getenv("MANTICORE_HTTP_MAX_CONNS_PER_HOST"); maxConnsPerHostStr != "" {
+		maxConnsPerHost, err := strconv.Atoi(maxConnsPerHostStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MANTICORE_HTTP_MAX_CONNS_PER_HOST: %w", err)
+		}
+		if maxConnsPerHost < 0 {
+			return nil, fmt.Errorf("invalid MANTICORE_HTTP_MAX_CONNS_PER_HOST: %d (use 0 for no limit)", maxConnsPerHost)
+		}
+		config.MaxConnsPerHost = maxConnsPerHost
+	}
+
 	if idleConnTimeoutStr := os.Getenv("MANTICORE_HTTP_IDLE_CONN_TIMEOUT"); idleConnTimeoutStr != "" {
 		idleConnTimeout, err := time.ParseDuration(idleConnTimeoutStr)
 		if err != nil {
@@ -67,6 +104,14 @@ func LoadHTTPConfigFromEnvironment() (*HTTPClientConfig, error) {
 		config.IdleConnTimeout = idleConnTimeout
 	}
 
+	if dnsRefreshIntervalStr := os.Getenv("MANTICORE_HTTP_DNS_REFRESH_INTERVAL"); dnsRefreshIntervalStr != "" {
+		dnsRefreshInterval, err := time.ParseDuration(dnsRefreshIntervalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MANTICORE_HTTP_DNS_REFRESH_INTERVAL: %w", err)
+		}
+		config.DNSRefreshInterval = dnsRefreshInterval
+	}
+
 	// Parse retry configuration
 	if maxAttemptsStr := os.Getenv("MANTICORE_HTTP_RETRY_MAX_ATTEMPTS"); maxAttemptsStr != "" {
 		maxAttempts, err := strconv.Atoi(maxAttemptsStr)
@@ -125,6 +170,99 @@ func LoadHTTPConfigFromEnvironment() (*HTTPClientConfig, error) {
 		config.CircuitBreakerConfig.HalfOpenMaxCalls = halfOpenMaxCalls
 	}
 
+	// Parse circuit breaker notification configuration
+	config.CircuitBreakerWebhook.URL = os.Getenv("MANTICORE_CB_WEBHOOK_URL")
+	if format := os.Getenv("MANTICORE_CB_WEBHOOK_FORMAT"); format != "" {
+		if format != WebhookFormatJSON && format != WebhookFormatSlack {
+			return nil, fmt.Errorf("invalid MANTICORE_CB_WEBHOOK_FORMAT: %q (expected %q or %q)", format, WebhookFormatJSON, WebhookFormatSlack)
+		}
+		config.CircuitBreakerWebhook.Format = format
+	}
+	if webhookTimeoutStr := os.Getenv("MANTICORE_CB_WEBHOOK_TIMEOUT"); webhookTimeoutStr != "" {
+		webhookTimeout, err := time.ParseDuration(webhookTimeoutStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MANTICORE_CB_WEBHOOK_TIMEOUT: %w", err)
+		}
+		config.CircuitBreakerWebhook.Timeout = webhookTimeout
+	}
+
+	// Parse bulk configuration
+	if compressionStr := os.Getenv("MANTICORE_BULK_COMPRESSION"); compressionStr != "" {
+		compression, err := strconv.ParseBool(compressionStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MANTICORE_BULK_COMPRESSION: %w", err)
+		}
+		config.BulkConfig.Compression = compression
+	}
+
+	if batchDelayStr := os.Getenv("MANTICORE_BULK_BATCH_DELAY"); batchDelayStr != "" {
+		batchDelay, err := time.ParseDuration(batchDelayStr)
+		if err != nil || batchDelay < 0 {
+			return nil, fmt.Errorf("invalid MANTICORE_BULK_BATCH_DELAY: %q", batchDelayStr)
+		}
+		config.BulkConfig.BatchDelay = batchDelay
+	}
+
+	if fallbackDelayStr := os.Getenv("MANTICORE_BULK_FALLBACK_DELAY"); fallbackDelayStr != "" {
+		fallbackDelay, err := time.ParseDuration(fallbackDelayStr)
+		if err != nil || fallbackDelay < 0 {
+			return nil, fmt.Errorf("invalid MANTICORE_BULK_FALLBACK_DELAY: %q", fallbackDelayStr)
+		}
+		config.BulkConfig.FallbackDelay = fallbackDelay
+	}
+
+	if pacingFactorStr := os.Getenv("MANTICORE_BULK_PACING_FACTOR"); pacingFactorStr != "" {
+		pacingFactor, err := strconv.ParseFloat(pacingFactorStr, 64)
+		if err != nil || pacingFactor < 0 {
+			return nil, fmt.Errorf("invalid MANTICORE_BULK_PACING_FACTOR: %q", pacingFactorStr)
+		}
+		config.BulkConfig.PacingFactor = pacingFactor
+	}
+
+	// Parse SQL transport configuration
+	if transport := os.Getenv("MANTICORE_SQL_TRANSPORT"); transport != "" {
+		if transport != SQLTransportHTTP && transport != SQLTransportMySQL {
+			return nil, fmt.Errorf("invalid MANTICORE_SQL_TRANSPORT: %q (expected %q or %q)", transport, SQLTransportHTTP, SQLTransportMySQL)
+		}
+		config.SQLTransport = transport
+	}
+
+	// Parse per-class retry policies
+	if err := loadRetryPoliciesFromEnvironment(&config.RetryPolicies); err != nil {
+		return nil, err
+	}
+
+	// Parse response size limit
+	if err := loadMaxResponseSizeFromEnvironment(&config.MaxResponseSize); err != nil {
+		return nil, err
+	}
+
+	// Parse per-operation timeouts
+	if err := loadOperationTimeoutsFromEnvironment(&config.Timeouts); err != nil {
+		return nil, err
+	}
+
+	// Parse fault injection configuration
+	if err := loadFaultConfigFromEnvironment(&config.Faults); err != nil {
+		return nil, err
+	}
+
+	// Parse debug recording configuration
+	if err := loadRecordingConfigFromEnvironment(&config.Recording); err != nil {
+		return nil, err
+	}
+
+	// Parse query embedding cache configuration
+	if err := loadEmbeddingCacheConfigFromEnvironment(&config.EmbeddingCache); err != nil {
+		return nil, err
+	}
+
+	mysqlPort := os.Getenv("MANTICORE_MYSQL_PORT")
+	if mysqlPort == "" {
+		mysqlPort = "9306"
+	}
+	config.MySQLAddr = fmt.Sprintf("%s:%s", host, mysqlPort)
+
 	return config, nil
 }
 
@@ -149,6 +287,9 @@ func DefaultHTTPConfig(host string) *HTTPClientConfig {
 			RecoveryTimeout:  30 * time.Second,
 			HalfOpenMaxCalls: 3,
 		},
-		BulkConfig: DefaultBulkConfig(),
+		BulkConfig:      DefaultBulkConfig(),
+		SQLTransport:    SQLTransportHTTP,
+		Timeouts:        DefaultOperationTimeouts(),
+		MaxResponseSize: DefaultMaxResponseSize,
 	}
 }
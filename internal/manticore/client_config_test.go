@@ -113,6 +113,7 @@ func TestLoadHTTPConfigFromEnvironment(t *testing.T) {
 				"MANTICORE_HTTP_MAX_IDLE_CONNS":          "50",
 				"MANTICORE_HTTP_MAX_IDLE_CONNS_PER_HOST": "25",
 				"MANTICORE_HTTP_IDLE_CONN_TIMEOUT":       "120s",
+				"MANTICORE_HTTP_MAX_CONNS_PER_HOST":      "30",
 			},
 			wantErr: false,
 			checkFn: func(config *HTTPClientConfig) error {
@@ -125,9 +126,91 @@ func TestLoadHTTPConfigFromEnvironment(t *testing.T) {
 				if config.IdleConnTimeout != 120*time.Second {
 					t.Errorf("Expected IdleConnTimeout 120s, got %v", config.IdleConnTimeout)
 				}
+				if config.MaxConnsPerHost != 30 {
+					t.Errorf("Expected MaxConnsPerHost 30, got %d", config.MaxConnsPerHost)
+				}
 				return nil
 			},
 		},
+		{
+			name: "network profile with overrides",
+			envVars: map[string]string{
+				"MANTICORE_HOST":                         "localhost:9308",
+				"MANTICORE_HTTP_NETWORK_PROFILE":         "swarm",
+				"MANTICORE_HTTP_COMPRESSION":             "true",
+				"MANTICORE_HTTP_MAX_IDLE_CONNS_PER_HOST": "8",
+			},
+			wantErr: false,
+			checkFn: func(config *HTTPClientConfig) error {
+				if config.NetworkProfile != NetworkProfileSwarm || config.IdleConnTimeout != 30*time.Second {
+					t.Errorf("Expected the swarm profile with a 30s idle timeout, got %q and %v", config.NetworkProfile, config.IdleConnTimeout)
+				}
+				if config.DisableCompression || config.MaxIdleConnsPerHost != 8 {
+					t.Errorf("Expected the variables to override the profile, got DisableCompression %v and MaxIdleConnsPerHost %d", config.DisableCompression, config.MaxIdleConnsPerHost)
+				}
+				return nil
+			},
+		},
+		{
+			name: "transport switches",
+			envVars: map[string]string{
+				"MANTICORE_HOST":             "localhost:9308",
+				"MANTICORE_HTTP_HTTP2":       "true",
+				"MANTICORE_HTTP_KEEP_ALIVES": "false",
+			},
+			wantErr: false,
+			checkFn: func(config *HTTPClientConfig) error {
+				if !config.EnableHTTP2 || !config.DisableKeepAlives || config.DisableCompression {
+					t.Errorf("Expected HTTP/2 without keep-alives, got %+v", config)
+				}
+				return nil
+			},
+		},
+		{
+			name: "kubernetes profile",
+			envVars: map[string]string{
+				"MANTICORE_HOST":                 "manticore.search.svc",
+				"MANTICORE_HTTP_NETWORK_PROFILE": "kubernetes",
+			},
+			wantErr: false,
+			checkFn: func(config *HTTPClientConfig) error {
+				if config.DNSRefreshInterval != 30*time.Second {
+					t.Errorf("Expected a 30s DNS refresh interval, got %v", config.DNSRefreshInterval)
+				}
+				return nil
+			},
+		},
+		{
+			name: "dns refresh interval",
+			envVars: map[string]string{
+				"MANTICORE_HOST":                      "manticore.search.svc",
+				"MANTICORE_HTTP_NETWORK_PROFILE":      "kubernetes",
+				"MANTICORE_HTTP_DNS_REFRESH_INTERVAL": "5s",
+			},
+			wantErr: false,
+			checkFn: func(config *HTTPClientConfig) error {
+				if config.DNSRefreshInterval != 5*time.Second {
+					t.Errorf("Expected the variable to override the profile, got %v", config.DNSRefreshInterval)
+				}
+				return nil
+			},
+		},
+		{
+			name: "unknown network profile",
+			envVars: map[string]string{
+				"MANTICORE_HOST":                 "localhost:9308",
+				"MANTICORE_HTTP_NETWORK_PROFILE": "lan",
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative max conns per host",
+			envVars: map[string]string{
+				"MANTICORE_HOST":                    "localhost:9308",
+				"MANTICORE_HTTP_MAX_CONNS_PER_HOST": "-1",
+			},
+			wantErr: true,
+		},
 		{
 			name: "custom retry settings",
 			envVars: map[string]string{
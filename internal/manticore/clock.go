@@ -0,0 +1,35 @@
+package manticore
+
+import "time"
+
+// Clock tells the time and waits for the circuit breaker, the retry backoff and the connection
+// manager, so tests can drive recovery timeouts, sliding windows and backoff without sleeping
+type Clock interface {
+	Now() time.Time
+	// After sends the time on the returned channel once d has passed
+	After(d time.Duration) <-chan time.Time
+	// NewTicker sends the time on the returned channel every d until stop is called
+	NewTicker(d time.Duration) (ticks <-chan time.Time, stop func())
+}
+
+// SystemClock is the Clock of the time package, used when a config leaves its Clock nil
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (systemClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(d)
+	return ticker.C, ticker.Stop
+}
+
+// clockOrSystem returns clock, or SystemClock when it is nil
+func clockOrSystem(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+	return clock
+}
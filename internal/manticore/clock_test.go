@@ -0,0 +1,221 @@
+package manticore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only moves when Advance is called
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	at     time.Time
+	period time.Duration // 0 for After
+	ch     chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	waiter := &fakeWaiter{at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		waiter.ch <- c.now
+		return waiter.ch
+	}
+	c.waiters = append(c.waiters, waiter)
+	return waiter.ch
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	waiter := &fakeWaiter{at: c.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	c.waiters = append(c.waiters, waiter)
+	return waiter.ch, func() { c.remove(waiter) }
+}
+
+func (c *fakeClock) remove(waiter *fakeWaiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, w := range c.waiters {
+		if w == waiter {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// Advance moves the time forward by d, firing the timers and tickers due by then. Like
+// time.Ticker, a ticker whose tick was not received drops the ticks after it.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.at.After(c.now) {
+			pending = append(pending, waiter)
+			continue
+		}
+		select {
+		case waiter.ch <- c.now:
+		default:
+		}
+		if waiter.period > 0 {
+			for !waiter.at.After(c.now) {
+				waiter.at = waiter.at.Add(waiter.period)
+			}
+			pending = append(pending, waiter)
+		}
+	}
+	c.waiters = pending
+}
+
+// waitForAfter waits until a goroutine waits on After and returns how long it waits for
+func (c *fakeClock) waitForAfter(t *testing.T) time.Duration {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		for _, waiter := range c.waiters {
+			if waiter.period == 0 {
+				d := waiter.at.Sub(c.now)
+				c.mu.Unlock()
+				return d
+			}
+		}
+		c.mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for a timer")
+	return 0
+}
+
+func TestCircuitBreakerRecoveryTimeoutWithClock(t *testing.T) {
+	clock := newFakeClock()
+	config := DefaultCircuitBreakerConfig()
+	config.FailureThreshold = 2
+	config.RecoveryTimeout = time.Minute
+	config.Clock = clock
+	cb := NewCircuitBreaker(config)
+	defer cb.Close()
+
+	fail := func(ctx context.Context) error { return errors.New("connection refused") }
+	for i := 0; i < config.FailureThreshold; i++ {
+		cb.Execute(context.Background(), fail)
+	}
+	if !cb.IsOpen() {
+		t.Fatalf("Expected OPEN state, got %v", cb.GetState())
+	}
+
+	clock.Advance(config.RecoveryTimeout - time.Second)
+	if retryAfter := cb.GetStats().RetryAfter; retryAfter != time.Second {
+		t.Errorf("Expected a retry after 1s, got %v", retryAfter)
+	}
+	if err := cb.Execute(context.Background(), func(ctx context.Context) error { return nil }); GetErrorType(err) != ErrorTypeCircuitBreaker {
+		t.Errorf("Expected the request to be rejected before the recovery timeout, got %v", err)
+	}
+
+	clock.Advance(time.Second)
+	if err := cb.Execute(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Errorf("Expected a recovery attempt once the timeout passed, got %v", err)
+	}
+	if !cb.IsHalfOpen() {
+		t.Errorf("Expected HALF-OPEN state, got %v", cb.GetState())
+	}
+}
+
+func TestCircuitBreakerSlidingWindowWithClock(t *testing.T) {
+	clock := newFakeClock()
+	config := DefaultCircuitBreakerConfig()
+	config.FailureThreshold = 100
+	config.MinRequestThreshold = 100
+	config.MonitoringInterval = time.Second
+	config.Clock = clock
+	cb := NewCircuitBreaker(config)
+	defer cb.Close()
+
+	for i := 0; i < 3; i++ {
+		cb.Execute(context.Background(), func(ctx context.Context) error { return errors.New("timeout") })
+	}
+	clock.Advance(4 * time.Second)
+	cb.Execute(context.Background(), func(ctx context.Context) error { return nil })
+	if rate := cb.GetStats().CurrentFailureRate; rate != 0.75 {
+		t.Errorf("Expected a failure rate of 0.75, got %v", rate)
+	}
+
+	// The window covers 5 monitoring intervals, after which the failures no longer count
+	clock.Advance(time.Second)
+	if rate := cb.GetStats().CurrentFailureRate; rate != 0 {
+		t.Errorf("Expected the failures to leave the window, got a failure rate of %v", rate)
+	}
+}
+
+func TestRetryManagerBackoffWithClock(t *testing.T) {
+	clock := newFakeClock()
+	config := DefaultRetryConfig()
+	config.MaxAttempts = 5
+	config.BaseDelay = 100 * time.Millisecond
+	config.JitterPercent = 0.1
+	config.TotalTimeout = time.Second
+	config.Clock = clock
+	config.Random = func() float64 { return 0.5 }
+	rm := NewRetryManager(config)
+
+	result := make(chan error, 1)
+	attempts := 0
+	go func() {
+		result <- rm.Execute(context.Background(), "/sql", "POST", func(ctx context.Context, retryCtx *RetryContext) error {
+			attempts++
+			return errors.New("connection refused")
+		})
+	}()
+
+	// 100ms doubling per attempt, times 4 for connection refused, plus half of the 10% jitter
+	for _, expected := range []time.Duration{420 * time.Millisecond, 840 * time.Millisecond} {
+		if delay := clock.waitForAfter(t); delay != expected {
+			t.Fatalf("Expected a backoff of %v, got %v", expected, delay)
+		}
+		clock.Advance(expected)
+	}
+
+	// 1.26s have passed, over the 1s retry budget
+	err := <-result
+	if GetErrorType(err) != ErrorTypeTimeout || attempts != 2 {
+		t.Errorf("Expected the retry budget to end retries after 2 attempts, got %d attempts and %v", attempts, err)
+	}
+	if stats := rm.GetRetryStats(); !stats.LastRetryTime.Equal(clock.Now().Add(-840 * time.Millisecond)) {
+		t.Errorf("Expected the last retry time to come from the clock, got %v", stats.LastRetryTime)
+	}
+}
+
+func TestConnectionManagerClock(t *testing.T) {
+	clock := newFakeClock()
+	cm := NewConnectionManager(nil, ConnectionManagerConfig{Clock: clock})
+	if since := cm.Status().Since; !since.Equal(clock.Now()) {
+		t.Errorf("Expected the status to start at %v, got %v", clock.Now(), since)
+	}
+
+	clock.Advance(time.Minute)
+	cm.setState(ConnectionStateConnecting, nil)
+	if since := cm.Status().Since; !since.Equal(clock.Now()) {
+		t.Errorf("Expected the state change at %v, got %v", clock.Now(), since)
+	}
+}
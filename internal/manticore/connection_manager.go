@@ -0,0 +1,220 @@
+package manticore
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// ConnectionState is the lifecycle state tracked by ConnectionManager
+type ConnectionState string
+
+// Connection states, in the order a healthy startup passes through them
+const (
+	ConnectionStateDisconnected ConnectionState = "disconnected"
+	ConnectionStateConnecting   ConnectionState = "connecting"
+	ConnectionStateInitializing ConnectionState = "initializing"
+	ConnectionStateReady        ConnectionState = "ready"
+)
+
+// ConnectionStatus is a snapshot of the connection manager state
+type ConnectionStatus struct {
+	State       ConnectionState `json:"state"`
+	Since       time.Time       `json:"since"`
+	Initialized bool            `json:"initialized"`
+	Reconnects  int             `json:"reconnects"`
+	Attempts    int             `json:"attempts"` // probes made in the current connection attempt
+	LastError   string          `json:"last_error,omitempty"`
+}
+
+// ConnectionManagerConfig configures background connection management
+type ConnectionManagerConfig struct {
+	// CheckInterval is how often a ready connection is health checked and how long
+	// to wait before retrying a failed initialization
+	CheckInterval time.Duration
+	// ReadyOptions controls the backoff used while the backend is unreachable
+	ReadyOptions ReadyOptions
+	// OnReady runs the first time the backend becomes reachable, e.g. to create the schema
+	// and index documents. It is retried until it succeeds.
+	OnReady func() error
+	// Clock times the health checks and state changes; nil uses SystemClock
+	Clock Clock
+}
+
+// ConnectionManager keeps probing Manticore in the background. It connects the client when the
+// backend appears, runs initial setup once, and falls back to reconnecting when health checks fail.
+type ConnectionManager struct {
+	client ClientInterface
+	config ConnectionManagerConfig
+
+	mutex       sync.RWMutex
+	status      ConnectionStatus
+	readyCh     chan struct{}
+	readyClosed bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewConnectionManager creates a connection manager for the client. Call Start to begin probing.
+func NewConnectionManager(client ClientInterface, config ConnectionManagerConfig) *ConnectionManager {
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = 15 * time.Second
+	}
+	config.Clock = clockOrSystem(config.Clock)
+
+	return &ConnectionManager{
+		client:  client,
+		config:  config,
+		status:  ConnectionStatus{State: ConnectionStateDisconnected, Since: config.Clock.Now()},
+		readyCh: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start launches the background connection loop
+func (cm *ConnectionManager) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cm.cancel = cancel
+	go cm.run(ctx)
+}
+
+// Stop ends the background connection loop and waits for it to exit
+func (cm *ConnectionManager) Stop() {
+	if cm.cancel == nil {
+		return
+	}
+	cm.cancel()
+	<-cm.done
+}
+
+// Status returns the current connection status
+func (cm *ConnectionManager) Status() ConnectionStatus {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+	return cm.status
+}
+
+// IsReady reports whether the backend is connected and initial setup has completed
+func (cm *ConnectionManager) IsReady() bool {
+	return cm.Status().State == ConnectionStateReady
+}
+
+// WaitUntilReady blocks until the manager first reaches the ready state or ctx is done
+func (cm *ConnectionManager) WaitUntilReady(ctx context.Context) error {
+	select {
+	case <-cm.readyCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (cm *ConnectionManager) run(ctx context.Context) {
+	defer close(cm.done)
+
+	readyOptions := cm.config.ReadyOptions
+	onProgress := readyOptions.OnProgress
+	readyOptions.OnProgress = func(p ReadyProgress) {
+		cm.mutex.Lock()
+		cm.status.Attempts = p.Attempt
+		if p.Err != nil {
+			cm.status.LastError = p.Err.Error()
+		}
+		cm.mutex.Unlock()
+
+		if onProgress != nil {
+			onProgress(p)
+		}
+	}
+
+	for {
+		cm.setState(ConnectionStateConnecting, nil)
+		if err := cm.client.WaitForReadyContext(ctx, readyOptions); err != nil {
+			cm.setState(ConnectionStateDisconnected, nil)
+			return
+		}
+
+		if !cm.Status().Initialized {
+			cm.setState(ConnectionStateInitializing, nil)
+			if err := cm.initialize(); err != nil {
+				log.Printf("[CONNECTION] Initial setup failed, retrying in %v: %v", cm.config.CheckInterval, err)
+				cm.setState(ConnectionStateDisconnected, err)
+				if !cm.sleep(ctx) {
+					return
+				}
+				continue
+			}
+		}
+
+		cm.setState(ConnectionStateReady, nil)
+		err := cm.monitor(ctx)
+		if err == nil {
+			cm.setState(ConnectionStateDisconnected, nil)
+			return
+		}
+
+		log.Printf("[CONNECTION] Lost connection to Manticore, reconnecting: %v", err)
+		cm.mutex.Lock()
+		cm.status.Reconnects++
+		cm.mutex.Unlock()
+		cm.setState(ConnectionStateDisconnected, err)
+	}
+}
+
+// initialize runs the OnReady callback and records that initial setup has completed
+func (cm *ConnectionManager) initialize() error {
+	if cm.config.OnReady != nil {
+		if err := cm.config.OnReady(); err != nil {
+			return err
+		}
+	}
+
+	cm.mutex.Lock()
+	cm.status.Initialized = true
+	cm.mutex.Unlock()
+	return nil
+}
+
+// monitor health checks a ready connection until a check fails or ctx is done.
+// It returns the health check error, or nil when ctx is done.
+func (cm *ConnectionManager) monitor(ctx context.Context) error {
+	for {
+		if !cm.sleep(ctx) {
+			return nil
+		}
+		if err := cm.client.HealthCheck(); err != nil {
+			return err
+		}
+	}
+}
+
+func (cm *ConnectionManager) setState(state ConnectionState, err error) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	if cm.status.State != state {
+		log.Printf("[CONNECTION] State %s -> %s", cm.status.State, state)
+		cm.status.State = state
+		cm.status.Since = cm.config.Clock.Now()
+	}
+	if err != nil {
+		cm.status.LastError = err.Error()
+	}
+
+	if state == ConnectionStateReady && !cm.readyClosed {
+		close(cm.readyCh)
+		cm.readyClosed = true
+	}
+}
+
+// sleep waits for the check interval and reports false if ctx was done first
+func (cm *ConnectionManager) sleep(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-cm.config.Clock.After(cm.config.CheckInterval):
+		return true
+	}
+}
@@ -0,0 +1,115 @@
+package manticore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func waitForConnectionState(t *testing.T, cm *ConnectionManager, state ConnectionState) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cm.Status().State == state {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for state %s, current status %+v", state, cm.Status())
+}
+
+func TestConnectionManagerReconnects(t *testing.T) {
+	var up atomic.Bool
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"data":[],"error":""}]`)) // SHOW STATUS result
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+	defer client.Close()
+
+	var setupCalls atomic.Int32
+	cm := NewConnectionManager(client, ConnectionManagerConfig{
+		CheckInterval: 10 * time.Millisecond,
+		ReadyOptions:  ReadyOptions{InitialInterval: 5 * time.Millisecond, MaxInterval: 10 * time.Millisecond},
+		OnReady: func() error {
+			setupCalls.Add(1)
+			return nil
+		},
+	})
+	cm.Start()
+	defer cm.Stop()
+
+	waitForConnectionState(t, cm, ConnectionStateConnecting)
+	if cm.IsReady() {
+		t.Fatal("Manager should not be ready while the backend is down")
+	}
+
+	// Backend comes up after the app
+	up.Store(true)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := cm.WaitUntilReady(ctx); err != nil {
+		t.Fatalf("Expected manager to become ready: %v", err)
+	}
+	if !client.IsConnected() {
+		t.Error("Client should be connected once the manager is ready")
+	}
+
+	// Backend goes away and comes back; setup must not run again
+	up.Store(false)
+	waitForConnectionState(t, cm, ConnectionStateConnecting)
+	up.Store(true)
+	waitForConnectionState(t, cm, ConnectionStateReady)
+
+	status := cm.Status()
+	if status.Reconnects < 1 || !status.Initialized {
+		t.Errorf("Unexpected status after reconnect: %+v", status)
+	}
+	if calls := setupCalls.Load(); calls != 1 {
+		t.Errorf("Expected initial setup to run once, ran %d times", calls)
+	}
+}
+
+func TestConnectionManagerRetriesFailedSetup(t *testing.T) {
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"data":[],"error":""}]`)) // SHOW STATUS result
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+	defer client.Close()
+
+	var setupCalls atomic.Int32
+	cm := NewConnectionManager(client, ConnectionManagerConfig{
+		CheckInterval: 10 * time.Millisecond,
+		OnReady: func() error {
+			if setupCalls.Add(1) == 1 {
+				return errors.New("schema creation failed")
+			}
+			return nil
+		},
+	})
+	cm.Start()
+	defer cm.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := cm.WaitUntilReady(ctx); err != nil {
+		t.Fatalf("Expected manager to become ready after retrying setup: %v", err)
+	}
+
+	status := cm.Status()
+	if setupCalls.Load() != 2 || status.LastError != "schema creation failed" {
+		t.Errorf("Expected a failed and a successful setup, got %d calls and status %+v", setupCalls.Load(), status)
+	}
+}
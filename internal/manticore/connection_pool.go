@@ -0,0 +1,209 @@
+package manticore
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PoolStats reports the connections of the HTTP client to Manticore. Active connections carry a
+// request whose response body is still open; the other open connections are idle.
+type PoolStats struct {
+	MaxIdleConnsPerHost int           `json:"max_idle_conns_per_host"`
+	MaxConnsPerHost     int           `json:"max_conns_per_host"` // 0 for no limit
+	IdleConnTimeout     time.Duration `json:"idle_conn_timeout"`
+	Open                int64         `json:"open"`
+	Active              int64         `json:"active"`
+	Idle                int64         `json:"idle"`
+	Dials               int64         `json:"dials"`
+	Reused              int64         `json:"reused"`
+	Waits               int64         `json:"waits"`               // requests that waited because MaxConnsPerHost connections were active
+	WaitTime            time.Duration `json:"wait_time"`           // total time those requests waited
+	Addresses           []string      `json:"addresses,omitempty"` // addresses the Manticore host last resolved to, when refreshed
+	DNSRefreshes        int64         `json:"dns_refreshes"`
+	AddressChanges      int64         `json:"address_changes"` // refreshes that found new addresses and recycled the idle connections
+	Rebuilds            int64         `json:"rebuilds"`        // transports rebuilt after a refused connection to a stale address
+}
+
+// poolTransport is the transport of the HTTP client, counting the connections it opens and the
+// requests using them. The http.Transport underneath is replaced when it dials an address the
+// Manticore host no longer resolves to.
+type poolTransport struct {
+	config          HTTPClientConfig
+	current         atomic.Pointer[http.Transport]
+	maxConnsPerHost int
+	open            atomic.Int64
+	active          atomic.Int64
+	dials           atomic.Int64
+	reused          atomic.Int64
+	waits           atomic.Int64
+	waitTime        atomic.Int64 // nanoseconds
+	dns             *dnsRefresher
+}
+
+// newPoolTransport builds the transport of the HTTP client with the pool settings of config
+func newPoolTransport(config HTTPClientConfig) *poolTransport {
+	t := &poolTransport{config: config, maxConnsPerHost: config.MaxConnsPerHost}
+	t.current.Store(t.newTransport())
+	t.dns = newDNSRefresher(config, t)
+	return t
+}
+
+// newTransport builds an http.Transport with the settings of the config, whose connections are
+// counted by t
+func (t *poolTransport) newTransport() *http.Transport {
+	config := t.config
+	dialer := &net.Dialer{
+		Timeout:   15 * time.Second,
+		KeepAlive: 60 * time.Second,
+	}
+	return &http.Transport{
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, address)
+			if err != nil {
+				return nil, err
+			}
+			t.dials.Add(1)
+			t.open.Add(1)
+			return &poolConn{Conn: conn, pool: t}, nil
+		},
+		TLSHandshakeTimeout:   15 * time.Second,
+		ResponseHeaderTimeout: 90 * time.Second, // Increased from 20s to 90s for Auto Embeddings operations
+		ExpectContinueTimeout: 2 * time.Second,
+		MaxIdleConns:          config.MaxIdleConns,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       config.MaxConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		DisableKeepAlives:     config.DisableKeepAlives,
+		DisableCompression:    config.DisableCompression,
+		ForceAttemptHTTP2:     config.EnableHTTP2, // off by default for better compatibility
+		WriteBufferSize:       32768,              // 32KB write buffer
+		ReadBufferSize:        32768,              // 32KB read buffer
+	}
+}
+
+// transport returns the current http.Transport
+func (t *poolTransport) transport() *http.Transport {
+	return t.current.Load()
+}
+
+// rebuild replaces the http.Transport, closing the idle connections of the old one; requests in
+// flight finish on the old one
+func (t *poolTransport) rebuild() {
+	old := t.current.Swap(t.newTransport())
+	old.CloseIdleConnections()
+}
+
+// CloseIdleConnections closes the idle connections of the current http.Transport
+func (t *poolTransport) CloseIdleConnections() {
+	t.transport().CloseIdleConnections()
+}
+
+// close stops the DNS refresh and closes the idle connections
+func (t *poolTransport) close() {
+	t.dns.stop()
+	t.CloseIdleConnections()
+}
+
+// RoundTrip sends req, counting its connection as active until the response body is closed
+func (t *poolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var (
+		mu       sync.Mutex
+		held     bool
+		waitedAt time.Time
+	)
+	release := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if held {
+			held = false
+			t.active.Add(-1)
+		}
+	}
+	trace := &httptrace.ClientTrace{
+		GetConn: func(string) {
+			// Only MaxConnsPerHost makes requests wait; without it the transport dials instead
+			if t.maxConnsPerHost > 0 && t.active.Load() >= int64(t.maxConnsPerHost) {
+				mu.Lock()
+				waitedAt = time.Now()
+				mu.Unlock()
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			if !waitedAt.IsZero() {
+				t.waits.Add(1)
+				t.waitTime.Add(int64(time.Since(waitedAt)))
+				waitedAt = time.Time{}
+			}
+			if info.Reused {
+				t.reused.Add(1)
+			}
+			if !held {
+				held = true
+				t.active.Add(1)
+			}
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	resp, err := t.transport().RoundTrip(req)
+	if err != nil {
+		release()
+		t.dns.checkRefused(err)
+		return nil, err
+	}
+	resp.Body = &poolBody{ReadCloser: resp.Body, release: sync.OnceFunc(release)}
+	return resp, nil
+}
+
+// stats returns the pool settings and the connections counted so far
+func (t *poolTransport) stats() PoolStats {
+	open, active := t.open.Load(), t.active.Load()
+	addresses, refreshes, changes, rebuilds := t.dns.stats()
+	return PoolStats{
+		MaxIdleConnsPerHost: t.config.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     t.config.MaxConnsPerHost,
+		IdleConnTimeout:     t.config.IdleConnTimeout,
+		Open:                open,
+		Active:              active,
+		Idle:                max(open-active, 0),
+		Dials:               t.dials.Load(),
+		Reused:              t.reused.Load(),
+		Waits:               t.waits.Load(),
+		WaitTime:            time.Duration(t.waitTime.Load()),
+		Addresses:           addresses,
+		DNSRefreshes:        refreshes,
+		AddressChanges:      changes,
+		Rebuilds:            rebuilds,
+	}
+}
+
+// poolConn is a connection of a poolTransport, counted as open until closed
+type poolConn struct {
+	net.Conn
+	pool   *poolTransport
+	closed sync.Once
+}
+
+func (c *poolConn) Close() error {
+	c.closed.Do(func() { c.pool.open.Add(-1) })
+	return c.Conn.Close()
+}
+
+// poolBody releases the connection of a response when closed
+type poolBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *poolBody) Close() error {
+	b.release()
+	return b.ReadCloser.Close()
+}
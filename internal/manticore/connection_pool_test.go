@@ -0,0 +1,105 @@
+package manticore
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPoolTransportStats(t *testing.T) {
+	release := make(chan struct{})
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-release
+		}
+		w.Write([]byte("ok"))
+	})
+	defer server.Close()
+
+	config := DefaultHTTPClientConfig(server.URL)
+	config.MaxConnsPerHost = 1
+	transport := newPoolTransport(config)
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport}
+
+	get := func(path string) {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Errorf("Request failed: %v", err)
+			return
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	get("/")
+	get("/")
+	if stats := transport.stats(); stats.Dials != 1 || stats.Reused != 1 || stats.Open != 1 || stats.Active != 0 || stats.Idle != 1 {
+		t.Fatalf("Expected one idle connection used twice, got %+v", stats)
+	}
+
+	// The slow request holds the only connection, so the second one waits for it
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		get("/slow")
+	}()
+	waitFor(t, func() bool { return transport.stats().Active == 1 })
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		get("/")
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	stats := transport.stats()
+	if stats.Waits != 1 || stats.WaitTime <= 0 || stats.Dials != 1 || stats.Active != 0 {
+		t.Errorf("Expected a request to wait for the only connection, got %+v", stats)
+	}
+	if stats.MaxConnsPerHost != 1 || stats.MaxIdleConnsPerHost != config.MaxIdleConnsPerHost || stats.IdleConnTimeout != config.IdleConnTimeout {
+		t.Errorf("Expected the pool settings of the config, got %+v", stats)
+	}
+}
+
+// waitFor polls condition until it holds, failing the test after a few seconds
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPoolTransportNetworkProfile(t *testing.T) {
+	config := DefaultHTTPClientConfig("http://localhost:9308")
+	transport := newPoolTransport(config)
+	if settings := transport.transport(); settings.ForceAttemptHTTP2 || settings.DisableKeepAlives || settings.DisableCompression {
+		t.Errorf("Expected HTTP/1.1 with keep-alives and compression by default, got %+v", settings)
+	}
+
+	if err := config.ApplyNetworkProfile(NetworkProfileRemote); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	transport = newPoolTransport(config)
+	if settings := transport.transport(); !settings.ForceAttemptHTTP2 || settings.IdleConnTimeout != 50*time.Second {
+		t.Errorf("Expected HTTP/2 and a 50s idle timeout for the remote profile, got %+v", settings)
+	}
+
+	if err := config.ApplyNetworkProfile(NetworkProfileDocker); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.EnableHTTP2 || !config.DisableCompression || config.MaxIdleConnsPerHost != 32 || config.MaxIdleConns < 32 {
+		t.Errorf("Expected the docker profile to replace the remote one, got %+v", config)
+	}
+	if err := config.ApplyNetworkProfile("lan"); err == nil {
+		t.Error("Expected an error for an unknown profile")
+	}
+}
@@ -0,0 +1,138 @@
+package manticore
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/url"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// dnsRefresher re-resolves the Manticore host of a poolTransport, so long-lived clients follow
+// Manticore to a new address, as when a Kubernetes pod restarts. Every DNSRefreshInterval, new
+// addresses recycle the idle connections; a refused connection to an address the host no longer
+// resolves to rebuilds the transport.
+type dnsRefresher struct {
+	host     string // empty when the base URL has an IP address
+	interval time.Duration
+	lookup   func(ctx context.Context, host string) ([]string, error)
+	pool     *poolTransport
+
+	mu        sync.Mutex
+	addresses []string // sorted; nil until the first refresh
+	checking  sync.Mutex
+	refreshes atomic.Int64
+	changes   atomic.Int64
+	rebuilds  atomic.Int64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// newDNSRefresher creates the refresher of pool, refreshing in the background when
+// DNSRefreshInterval is set and the base URL names a host
+func newDNSRefresher(config HTTPClientConfig, pool *poolTransport) *dnsRefresher {
+	r := &dnsRefresher{interval: config.DNSRefreshInterval, lookup: net.DefaultResolver.LookupHost, pool: pool}
+	if u, err := url.Parse(config.BaseURL); err == nil && u.Hostname() != "" && net.ParseIP(u.Hostname()) == nil {
+		r.host = u.Hostname()
+	}
+	if r.host != "" && r.interval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		r.cancel = cancel
+		r.done = make(chan struct{})
+		go r.loop(ctx)
+	}
+	return r
+}
+
+// loop refreshes every interval until ctx is cancelled
+func (r *dnsRefresher) loop(ctx context.Context) {
+	defer close(r.done)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.refresh(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.refresh(ctx)
+		}
+	}
+}
+
+// stop ends the background refresh
+func (r *dnsRefresher) stop() {
+	if r.cancel != nil {
+		r.cancel()
+		<-r.done
+	}
+}
+
+// refresh resolves the host and recycles the idle connections when its addresses changed
+func (r *dnsRefresher) refresh(ctx context.Context) {
+	addresses, err := r.lookup(ctx, r.host)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[DNS] Failed to resolve %s: %v", r.host, err)
+		}
+		return
+	}
+	slices.Sort(addresses)
+	r.refreshes.Add(1)
+
+	r.mu.Lock()
+	previous := r.addresses
+	r.addresses = addresses
+	r.mu.Unlock()
+
+	if previous != nil && !slices.Equal(previous, addresses) {
+		r.changes.Add(1)
+		log.Printf("[DNS] %s moved from %v to %v, recycling idle connections", r.host, previous, addresses)
+		r.pool.CloseIdleConnections()
+	}
+}
+
+// checkRefused rebuilds the transport when err is a refused connection to an address the host no
+// longer resolves to. Concurrent refusals are checked once.
+func (r *dnsRefresher) checkRefused(err error) {
+	if r.host == "" || !errors.Is(err, syscall.ECONNREFUSED) {
+		return
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) || opErr.Addr == nil {
+		return
+	}
+	address, _, splitErr := net.SplitHostPort(opErr.Addr.String())
+	if splitErr != nil || !r.checking.TryLock() {
+		return
+	}
+	defer r.checking.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	r.refresh(ctx)
+
+	r.mu.Lock()
+	stale := len(r.addresses) > 0 && !slices.Contains(r.addresses, address)
+	r.mu.Unlock()
+	if stale {
+		r.rebuilds.Add(1)
+		log.Printf("[DNS] Connection refused by stale address %s of %s, rebuilding the transport", address, r.host)
+		r.pool.rebuild()
+	}
+}
+
+// stats returns the last resolved addresses and the refresh counters
+func (r *dnsRefresher) stats() (addresses []string, refreshes, changes, rebuilds int64) {
+	r.mu.Lock()
+	addresses = slices.Clone(r.addresses)
+	r.mu.Unlock()
+	return addresses, r.refreshes.Load(), r.changes.Load(), r.rebuilds.Load()
+}
@@ -0,0 +1,103 @@
+package manticore
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// stubLookup resolves every host to the addresses it was last set to
+type stubLookup struct {
+	mu        sync.Mutex
+	addresses []string
+}
+
+func (s *stubLookup) set(addresses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addresses = addresses
+}
+
+func (s *stubLookup) lookup(ctx context.Context, host string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.addresses...), nil
+}
+
+func refusedBy(address string) error {
+	return &net.OpError{Op: "dial", Net: "tcp", Addr: &net.TCPAddr{IP: net.ParseIP(address), Port: 9308},
+		Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+}
+
+func TestDNSRefresherRefresh(t *testing.T) {
+	pool := newPoolTransport(DefaultHTTPClientConfig("http://manticore.default.svc:9308"))
+	stub := &stubLookup{}
+	pool.dns.lookup = stub.lookup
+
+	stub.set("10.0.0.2", "10.0.0.1")
+	pool.dns.refresh(context.Background())
+	stub.set("10.0.0.1", "10.0.0.2")
+	pool.dns.refresh(context.Background())
+	if stats := pool.stats(); stats.DNSRefreshes != 2 || stats.AddressChanges != 0 || len(stats.Addresses) != 2 || stats.Addresses[0] != "10.0.0.1" {
+		t.Fatalf("Expected the same sorted addresses twice, got %+v", stats)
+	}
+
+	stub.set("10.0.0.3")
+	pool.dns.refresh(context.Background())
+	if stats := pool.stats(); stats.AddressChanges != 1 || stats.Addresses[0] != "10.0.0.3" {
+		t.Errorf("Expected an address change, got %+v", stats)
+	}
+}
+
+func TestDNSRefresherRebuildsOnStaleAddress(t *testing.T) {
+	pool := newPoolTransport(DefaultHTTPClientConfig("http://manticore.default.svc:9308"))
+	stub := &stubLookup{}
+	pool.dns.lookup = stub.lookup
+	stub.set("10.0.0.1")
+	original := pool.transport()
+
+	// Refused by an address the host still resolves to: Manticore is down, not moved
+	pool.dns.checkRefused(refusedBy("10.0.0.1"))
+	if pool.transport() != original || pool.stats().Rebuilds != 0 {
+		t.Fatal("Expected no rebuild while the address is current")
+	}
+
+	stub.set("10.0.0.7")
+	pool.dns.checkRefused(errors.New("connection refused"))
+	pool.dns.checkRefused(refusedBy("10.0.0.1"))
+	if pool.transport() == original || pool.stats().Rebuilds != 1 {
+		t.Errorf("Expected one rebuild after a refusal by the stale address, got %+v", pool.stats())
+	}
+}
+
+func TestDNSRefresherSkipsAddresses(t *testing.T) {
+	config := DefaultHTTPClientConfig("http://127.0.0.1:9308")
+	config.DNSRefreshInterval = time.Millisecond
+	pool := newPoolTransport(config)
+	defer pool.close()
+	if pool.dns.host != "" || pool.dns.cancel != nil {
+		t.Error("Expected no refresh for an IP address")
+	}
+	pool.dns.checkRefused(refusedBy("127.0.0.1"))
+	if pool.stats().DNSRefreshes != 0 {
+		t.Error("Expected no lookup for an IP address")
+	}
+}
+
+func TestDNSRefresherLoop(t *testing.T) {
+	config := DefaultHTTPClientConfig("http://localhost:9308")
+	config.DNSRefreshInterval = 10 * time.Millisecond
+	pool := newPoolTransport(config)
+	waitFor(t, func() bool { return pool.stats().DNSRefreshes >= 2 })
+	pool.close()
+	refreshes := pool.stats().DNSRefreshes
+	time.Sleep(30 * time.Millisecond)
+	if pool.stats().DNSRefreshes != refreshes {
+		t.Error("Expected the refresh to stop with the transport")
+	}
+}
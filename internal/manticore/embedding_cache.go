@@ -0,0 +1,320 @@
+package manticore
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEmbeddingCacheTTL is how long a query embedding is reused when MANTICORE_AI_EMBEDDING_CACHE_TTL is unset
+const DefaultEmbeddingCacheTTL = 5 * time.Minute
+
+// EmbeddingCacheConfig configures the cache of query embeddings. AI searches normally send the
+// query text and Manticore embeds it on every search and every retry; with the cache, the client
+// gets the embedding once and sends it as the query vector until it expires. The zero value
+// caches nothing.
+type EmbeddingCacheConfig struct {
+	Size int           // embeddings kept, least recently used first out; 0 disables the cache
+	TTL  time.Duration // how long an embedding is reused; 0 uses DefaultEmbeddingCacheTTL
+}
+
+// Enabled reports whether query embeddings are cached
+func (c EmbeddingCacheConfig) Enabled() bool {
+	return c.Size > 0
+}
+
+// loadEmbeddingCacheConfigFromEnvironment reads MANTICORE_AI_EMBEDDING_CACHE_SIZE and
+// MANTICORE_AI_EMBEDDING_CACHE_TTL
+func loadEmbeddingCacheConfigFromEnvironment(config *EmbeddingCacheConfig) error {
+	if value := os.Getenv("MANTICORE_AI_EMBEDDING_CACHE_SIZE"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 0 {
+			return fmt.Errorf("invalid MANTICORE_AI_EMBEDDING_CACHE_SIZE: %q (use a number of embeddings, 0 to disable)", value)
+		}
+		config.Size = size
+	}
+	if value := os.Getenv("MANTICORE_AI_EMBEDDING_CACHE_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			return fmt.Errorf("invalid MANTICORE_AI_EMBEDDING_CACHE_TTL: %q", value)
+		}
+		config.TTL = ttl
+	}
+	return nil
+}
+
+// embeddingCache is a fixed-size LRU of query embeddings keyed by model and query, with a maximum
+// entry age. A nil *embeddingCache is valid and caches nothing.
+type embeddingCache struct {
+	mutex   sync.Mutex
+	maxSize int
+	ttl     time.Duration
+	now     func() time.Time
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type embeddingCacheEntry struct {
+	key      string
+	vector   []float64
+	storedAt time.Time
+}
+
+// newEmbeddingCache creates the cache configured by config, nil when it is disabled
+func newEmbeddingCache(config EmbeddingCacheConfig) *embeddingCache {
+	if !config.Enabled() {
+		return nil
+	}
+	ttl := config.TTL
+	if ttl <= 0 {
+		ttl = DefaultEmbeddingCacheTTL
+	}
+	return &embeddingCache{
+		maxSize: config.Size,
+		ttl:     ttl,
+		now:     time.Now,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// embeddingCacheKey keys an embedding by the model that computed it and the query text
+func embeddingCacheKey(model, query string) string {
+	return model + "\x00" + query
+}
+
+// Get returns the embedding of query by model, if present and not expired
+func (c *embeddingCache) Get(model, query string) ([]float64, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := embeddingCacheKey(model, query)
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(embeddingCacheEntry)
+	if c.now().Sub(entry.storedAt) > c.ttl {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry.vector, true
+}
+
+// Put stores the embedding of query by model, evicting the least recently used beyond the size
+func (c *embeddingCache) Put(model, query string, vector []float64) {
+	if c == nil || len(vector) == 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := embeddingCacheKey(model, query)
+	entry := embeddingCacheEntry{key: key, vector: vector, storedAt: c.now()}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(embeddingCacheEntry).key)
+	}
+}
+
+// Len returns the number of cached embeddings, expired ones included
+func (c *embeddingCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+// freshEmbeddingKey is the context key for WithoutEmbeddingCache
+type freshEmbeddingKey struct{}
+
+// WithoutEmbeddingCache returns a context whose AI searches have Manticore embed the query text
+// instead of using a cached embedding, for searches that check embedding works at all
+func WithoutEmbeddingCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshEmbeddingKey{}, true)
+}
+
+// queryEmbedding returns the embedding of query by the model of the documents table from the
+// cache, getting it from Manticore on a miss. It returns nil when the cache is disabled or skipped,
+// the table has no Auto Embeddings or the embedding can't be had, and the search then sends the
+// query text for Manticore to embed.
+func (mc *manticoreHTTPClient) queryEmbedding(ctx context.Context, query string) []float64 {
+	if mc.embeddings == nil || strings.TrimSpace(query) == "" {
+		return nil
+	}
+	if fresh, _ := ctx.Value(freshEmbeddingKey{}).(bool); fresh {
+		return nil
+	}
+
+	// Manticore embeds with the model stored on the table, which may differ from the configured one
+	model, ok := mc.documentsEmbeddingModel()
+	if !ok {
+		return nil
+	}
+	key := model.Model + "\x00" + model.Similarity
+	if vector, ok := mc.embeddings.Get(key, query); ok {
+		return vector
+	}
+
+	vector, err := mc.embedQuery(ctx, model, query)
+	if err != nil {
+		log.Printf("[AI_SEARCH] [EMBEDDING_CACHE] [WARNING] Failed to get the embedding of query='%s', searching with the query text: %v", query, err)
+		return nil
+	}
+	mc.embeddings.Put(key, query, vector)
+	return vector
+}
+
+// documentsEmbeddingModel returns the Auto Embeddings model of the content_vector column of the
+// current documents table. It is read once per table and forgotten when the client creates the
+// table again.
+func (mc *manticoreHTTPClient) documentsEmbeddingModel() (EmbeddingModel, bool) {
+	table := mc.table("documents")
+	if model, ok := mc.embeddingModels.Load(table); ok {
+		return model.(EmbeddingModel), true
+	}
+
+	models, err := mc.EmbeddingModels("documents")
+	if err != nil {
+		log.Printf("[AI_SEARCH] [EMBEDDING_CACHE] [WARNING] Failed to read the embedding model of %s: %v", table, err)
+		return EmbeddingModel{}, false
+	}
+	for _, model := range models {
+		if model.Column == "content_vector" {
+			mc.embeddingModels.Store(table, model)
+			return model, true
+		}
+	}
+	return EmbeddingModel{}, false
+}
+
+// embedQuery has Manticore embed query as model does. Manticore has no statement returning an
+// embedding, so the query is written to a scratch table whose vector column is embedded by the
+// same model and similarity, read back and deleted.
+func (mc *manticoreHTTPClient) embedQuery(ctx context.Context, model EmbeddingModel, query string) ([]float64, error) {
+	table, err := mc.queryEmbeddingsTable(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every call writes a row of its own, so concurrent embeddings of the same query, from this
+	// client or another sharing the server, never read or delete each other's row
+	id := rand.Uint64()>>1 | 1
+	if _, err := mc.runSQLQuery(ctx, fmt.Sprintf("REPLACE INTO %s (id, query) VALUES (%d, '%s')", table, id, escapeSQLString(query))); err != nil {
+		if isUnknownTableError(err) {
+			// Dropped behind our back, so create it again next time
+			mc.embeddingTables.Delete(table)
+		}
+		return nil, fmt.Errorf("failed to embed query: %v", err)
+	}
+	defer func() {
+		if _, err := mc.runSQLQuery(context.WithoutCancel(ctx), fmt.Sprintf("DELETE FROM %s WHERE id = %d", table, id)); err != nil {
+			log.Printf("[AI_SEARCH] [EMBEDDING_CACHE] [WARNING] Failed to delete the embedded query from %s: %v", table, err)
+		}
+	}()
+
+	response, err := mc.runSQLQuery(ctx, fmt.Sprintf("SELECT query_vector FROM %s WHERE id = %d", table, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query embedding: %v", err)
+	}
+	if len(response.Data) == 0 {
+		return nil, fmt.Errorf("query embedding not found in %s", table)
+	}
+	return parseEmbedding(response.Data[0]["query_vector"])
+}
+
+// queryEmbeddingsTable creates, once per model and similarity, the scratch table embedding
+// queries as model does
+func (mc *manticoreHTTPClient) queryEmbeddingsTable(ctx context.Context, model EmbeddingModel) (string, error) {
+	similarity := model.Similarity
+	if similarity == "" {
+		similarity = "cosine"
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(model.Model + "\x00" + similarity))
+	table := mc.table(fmt.Sprintf("query_embeddings_%08x", hash.Sum32()))
+	if _, ok := mc.embeddingTables.Load(table); ok {
+		return table, nil
+	}
+
+	statement := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (query TEXT, query_vector FLOAT_VECTOR KNN_TYPE='hnsw' HNSW_SIMILARITY='%s' MODEL_NAME='%s' FROM='query')",
+		table, escapeSQLString(similarity), escapeSQLString(model.Model))
+	if _, err := mc.runSQLQuery(ctx, statement); err != nil {
+		return "", fmt.Errorf("failed to create %s: %v", table, err)
+	}
+	mc.embeddingTables.Store(table, struct{}{})
+	return table, nil
+}
+
+// parseEmbedding parses a float_vector value, which Manticore returns as comma-separated numbers,
+// optionally bracketed, or as a JSON array
+func parseEmbedding(value interface{}) ([]float64, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		vector := make([]float64, len(v))
+		for i, item := range v {
+			number, ok := item.(float64)
+			if !ok {
+				return nil, fmt.Errorf("invalid embedding value %v", item)
+			}
+			vector[i] = number
+		}
+		if len(vector) == 0 {
+			return nil, fmt.Errorf("empty embedding")
+		}
+		return vector, nil
+	case string:
+		text := strings.TrimSpace(v)
+		if strings.HasPrefix(text, "[") {
+			var vector []float64
+			if err := json.Unmarshal([]byte(text), &vector); err != nil {
+				return nil, fmt.Errorf("invalid embedding: %v", err)
+			}
+			if len(vector) == 0 {
+				return nil, fmt.Errorf("empty embedding")
+			}
+			return vector, nil
+		}
+		if text == "" {
+			return nil, fmt.Errorf("empty embedding")
+		}
+		parts := strings.Split(text, ",")
+		vector := make([]float64, len(parts))
+		for i, part := range parts {
+			number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid embedding value %q", part)
+			}
+			vector[i] = number
+		}
+		return vector, nil
+	default:
+		return nil, fmt.Errorf("unexpected embedding type %T", value)
+	}
+}
@@ -0,0 +1,463 @@
+package manticore
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestEmbeddingCacheLRU(t *testing.T) {
+	cache := newEmbeddingCache(EmbeddingCacheConfig{Size: 2, TTL: time.Minute})
+
+	cache.Put("model", "a", []float64{1})
+	cache.Put("model", "b", []float64{2})
+	if _, ok := cache.Get("model", "a"); !ok {
+		t.Fatal("Expected a to be cached")
+	}
+	cache.Put("model", "c", []float64{3})
+
+	if _, ok := cache.Get("model", "b"); ok {
+		t.Error("Expected b, the least recently used, to be evicted")
+	}
+	for _, query := range []string{"a", "c"} {
+		if _, ok := cache.Get("model", query); !ok {
+			t.Errorf("Expected %s to be cached", query)
+		}
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", cache.Len())
+	}
+}
+
+func TestEmbeddingCacheKeyedByModel(t *testing.T) {
+	cache := newEmbeddingCache(EmbeddingCacheConfig{Size: 10})
+
+	cache.Put("model-a", "query", []float64{1})
+	if _, ok := cache.Get("model-b", "query"); ok {
+		t.Error("Expected the embedding of another model to be a miss")
+	}
+	if vector, ok := cache.Get("model-a", "query"); !ok || vector[0] != 1 {
+		t.Errorf("Expected the embedding of model-a, got %v, %v", vector, ok)
+	}
+}
+
+func TestEmbeddingCacheTTL(t *testing.T) {
+	now := time.Now()
+	cache := newEmbeddingCache(EmbeddingCacheConfig{Size: 10, TTL: time.Minute})
+	cache.now = func() time.Time { return now }
+
+	cache.Put("model", "query", []float64{1})
+	now = now.Add(59 * time.Second)
+	if _, ok := cache.Get("model", "query"); !ok {
+		t.Error("Expected the embedding within the TTL")
+	}
+	now = now.Add(2 * time.Second)
+	if _, ok := cache.Get("model", "query"); ok {
+		t.Error("Expected the embedding to expire after the TTL")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Expected the expired entry to be removed, got %d entries", cache.Len())
+	}
+}
+
+func TestEmbeddingCacheDisabled(t *testing.T) {
+	cache := newEmbeddingCache(EmbeddingCacheConfig{})
+	if cache != nil {
+		t.Fatal("Expected no cache with size 0")
+	}
+	cache.Put("model", "query", []float64{1})
+	if _, ok := cache.Get("model", "query"); ok {
+		t.Error("Expected a nil cache to cache nothing")
+	}
+}
+
+func TestLoadEmbeddingCacheConfigFromEnvironment(t *testing.T) {
+	defer os.Unsetenv("MANTICORE_AI_EMBEDDING_CACHE_SIZE")
+	defer os.Unsetenv("MANTICORE_AI_EMBEDDING_CACHE_TTL")
+
+	os.Setenv("MANTICORE_AI_EMBEDDING_CACHE_SIZE", "500")
+	os.Setenv("MANTICORE_AI_EMBEDDING_CACHE_TTL", "30s")
+	var config EmbeddingCacheConfig
+	if err := loadEmbeddingCacheConfigFromEnvironment(&config); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Size != 500 || config.TTL != 30*time.Second {
+		t.Errorf("Expected size 500 and TTL 30s, got %+v", config)
+	}
+
+	tests := []struct {
+		key   string
+		value string
+	}{
+		{"MANTICORE_AI_EMBEDDING_CACHE_SIZE", "-1"},
+		{"MANTICORE_AI_EMBEDDING_CACHE_SIZE", "many"},
+		{"MANTICORE_AI_EMBEDDING_CACHE_TTL", "0s"},
+		{"MANTICORE_AI_EMBEDDING_CACHE_TTL", "soon"},
+	}
+	for _, tt := range tests {
+		os.Unsetenv("MANTICORE_AI_EMBEDDING_CACHE_SIZE")
+		os.Unsetenv("MANTICORE_AI_EMBEDDING_CACHE_TTL")
+		os.Setenv(tt.key, tt.value)
+		if err := loadEmbeddingCacheConfigFromEnvironment(&EmbeddingCacheConfig{}); err == nil {
+			t.Errorf("Expected an error for %s=%q", tt.key, tt.value)
+		}
+	}
+}
+
+func TestParseEmbedding(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected []float64
+	}{
+		{"0.5,-0.25,1", []float64{0.5, -0.25, 1}},
+		{"[0.5, -0.25, 1]", []float64{0.5, -0.25, 1}},
+		{[]interface{}{0.5, -0.25, 1.0}, []float64{0.5, -0.25, 1}},
+	}
+	for _, tt := range tests {
+		vector, err := parseEmbedding(tt.value)
+		if err != nil {
+			t.Errorf("parseEmbedding(%v) failed: %v", tt.value, err)
+			continue
+		}
+		if len(vector) != len(tt.expected) {
+			t.Errorf("parseEmbedding(%v) = %v, expected %v", tt.value, vector, tt.expected)
+			continue
+		}
+		for i := range vector {
+			if vector[i] != tt.expected[i] {
+				t.Errorf("parseEmbedding(%v) = %v, expected %v", tt.value, vector, tt.expected)
+				break
+			}
+		}
+	}
+
+	for _, value := range []interface{}{"", "[]", "0.5,x", nil, 42.0} {
+		if _, err := parseEmbedding(value); err == nil {
+			t.Errorf("Expected an error for %v", value)
+		}
+	}
+}
+
+// embeddingServer serves the documents table schema, the scratch table statements of embedQuery
+// and AI searches, recording the statements and searches
+type embeddingServer struct {
+	mu         sync.Mutex
+	statements []string
+	searches   []map[string]interface{}
+	rows       map[string]bool // scratch table rows written and not yet deleted, by id
+	model      string          // model_name of the documents table; empty uses table-model
+	similarity string          // hnsw_similarity of the documents table; empty uses cosine
+	failSearch int             // searches answered with a retryable HTTP 503 before succeeding
+}
+
+var scratchRowIDPattern = regexp.MustCompile(`VALUES \((\d+),|id = (\d+)`)
+
+func (s *embeddingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/sql":
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		statement := form.Get("query")
+		s.statements = append(s.statements, statement)
+		id := ""
+		if match := scratchRowIDPattern.FindStringSubmatch(statement); match != nil {
+			id = match[1] + match[2]
+		}
+		if s.rows == nil {
+			s.rows = make(map[string]bool)
+		}
+		switch {
+		case strings.HasPrefix(statement, "SHOW CREATE TABLE"):
+			model, similarity := cmp.Or(s.model, "table-model"), cmp.Or(s.similarity, "cosine")
+			schema := "CREATE TABLE documents (\ncontent_vector float_vector knn_type='hnsw' hnsw_similarity='" + similarity + "' model_name='" + model + "' from='content'\n)"
+			response, _ := json.Marshal([]map[string]interface{}{{"data": []map[string]string{{"Table": "documents", "Create Table": schema}}, "total": 1}})
+			w.Write(response)
+			return
+		case strings.HasPrefix(statement, "REPLACE INTO"):
+			s.rows[id] = true
+		case strings.HasPrefix(statement, "DELETE FROM"):
+			delete(s.rows, id)
+		case strings.HasPrefix(statement, "SELECT query_vector"):
+			if !s.rows[id] {
+				w.Write([]byte(`[{"columns":[{"query_vector":{"type":"float_vector"}}],"data":[],"total":0}]`))
+				return
+			}
+			w.Write([]byte(`[{"columns":[{"query_vector":{"type":"float_vector"}}],"data":[{"query_vector":"0.1,0.2,0.3"}],"total":1}]`))
+			return
+		}
+		w.Write([]byte(`[{"total":1,"error":"","warning":""}]`))
+	case "/cli":
+		// Schema statements such as CREATE TABLE documents
+		w.Write([]byte("Query OK"))
+	case "/search":
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		s.searches = append(s.searches, request)
+		if s.failSearch > 0 {
+			s.failSearch--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":"service unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"took":1,"timed_out":false,"hits":{"total":0,"hits":[]}}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// statementsStarting counts the recorded statements starting with prefix
+func (s *embeddingServer) statementsStarting(prefix string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, statement := range s.statements {
+		if strings.HasPrefix(statement, prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+func newEmbeddingCacheTestClient(t *testing.T, handler http.Handler, cache EmbeddingCacheConfig) ClientInterface {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := DefaultHTTPClientConfig(server.URL)
+	config.Timeout = 5 * time.Second
+	config.RetryConfig.BaseDelay = time.Millisecond
+	config.RetryConfig.MaxDelay = 5 * time.Millisecond
+	config.EmbeddingCache = cache
+	return NewHTTPClient(config)
+}
+
+func TestAISearchReusesCachedQueryEmbedding(t *testing.T) {
+	server := &embeddingServer{}
+	client := newEmbeddingCacheTestClient(t, server, EmbeddingCacheConfig{Size: 10, TTL: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.AISearch("what is manticore", "test-model", 10, 0); err != nil {
+			t.Fatalf("AISearch failed: %v", err)
+		}
+	}
+
+	if count := server.statementsStarting("CREATE TABLE IF NOT EXISTS query_embeddings_"); count != 1 {
+		t.Errorf("Expected the scratch table to be created once, got %d", count)
+	}
+	if count := server.statementsStarting("REPLACE INTO query_embeddings_"); count != 1 {
+		t.Errorf("Expected the query to be embedded once, got %d", count)
+	}
+	if count := server.statementsStarting("DELETE FROM query_embeddings_"); count != 1 {
+		t.Errorf("Expected the embedded query to be deleted, got %d deletes", count)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.searches) != 2 {
+		t.Fatalf("Expected 2 searches, got %d", len(server.searches))
+	}
+	for i, search := range server.searches {
+		knn, _ := search["query"].(map[string]interface{})["knn"].(map[string]interface{})
+		if _, ok := knn["query_vector"]; !ok {
+			t.Errorf("Expected search %d to send the cached query_vector, got %v", i, knn)
+		}
+		if _, ok := knn["query"]; ok {
+			t.Errorf("Expected search %d not to send the query text, got %v", i, knn)
+		}
+	}
+}
+
+func TestAISearchRetriesReuseQueryEmbedding(t *testing.T) {
+	server := &embeddingServer{failSearch: 1}
+	client := newEmbeddingCacheTestClient(t, server, EmbeddingCacheConfig{Size: 10})
+
+	if _, err := client.AISearch("retried query", "test-model", 10, 0); err != nil {
+		t.Fatalf("AISearch failed: %v", err)
+	}
+
+	if count := server.statementsStarting("REPLACE INTO"); count != 1 {
+		t.Errorf("Expected the query to be embedded once across retries, got %d", count)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.searches) != 2 {
+		t.Fatalf("Expected the search to be retried once, got %d searches", len(server.searches))
+	}
+}
+
+func TestAISearchWithoutEmbeddingCacheSendsQueryText(t *testing.T) {
+	server := &embeddingServer{}
+	client := newEmbeddingCacheTestClient(t, server, EmbeddingCacheConfig{})
+
+	if _, err := client.AISearch("plain query", "test-model", 10, 0); err != nil {
+		t.Fatalf("AISearch failed: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.statements) != 0 {
+		t.Errorf("Expected no scratch table statements, got %v", server.statements)
+	}
+	knn, _ := server.searches[0]["query"].(map[string]interface{})["knn"].(map[string]interface{})
+	if knn["query"] != "plain query" {
+		t.Errorf("Expected the query text to be sent, got %v", knn)
+	}
+}
+
+func TestAISearchFallsBackToQueryTextWhenEmbeddingFails(t *testing.T) {
+	var mu sync.Mutex
+	var searches []map[string]interface{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/sql" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"embeddings library not loaded"}`))
+			return
+		}
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		mu.Lock()
+		searches = append(searches, request)
+		mu.Unlock()
+		w.Write([]byte(`{"took":1,"timed_out":false,"hits":{"total":0,"hits":[]}}`))
+	})
+	client := newEmbeddingCacheTestClient(t, handler, EmbeddingCacheConfig{Size: 10})
+
+	if _, err := client.AISearch("fallback query", "test-model", 10, 0); err != nil {
+		t.Fatalf("AISearch failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(searches) != 1 {
+		t.Fatalf("Expected 1 search, got %d", len(searches))
+	}
+	knn, _ := searches[0]["query"].(map[string]interface{})["knn"].(map[string]interface{})
+	if knn["query"] != "fallback query" {
+		t.Errorf("Expected the query text to be sent, got %v", knn)
+	}
+}
+
+func TestAISearchEmbedsQueriesWithTheTableModel(t *testing.T) {
+	server := &embeddingServer{model: "reembedded-model", similarity: "l2"}
+	client := newEmbeddingCacheTestClient(t, server, EmbeddingCacheConfig{Size: 10})
+
+	// The configured model differs from the one the documents table embeds with
+	if _, err := client.AISearch("which model", "configured-model", 10, 0); err != nil {
+		t.Fatalf("AISearch failed: %v", err)
+	}
+
+	if count := server.statementsStarting("CREATE TABLE IF NOT EXISTS query_embeddings_"); count != 1 {
+		t.Fatalf("Expected the scratch table to be created once, got %d", count)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for _, statement := range server.statements {
+		if !strings.HasPrefix(statement, "CREATE TABLE") {
+			continue
+		}
+		if !strings.Contains(statement, "MODEL_NAME='reembedded-model'") || !strings.Contains(statement, "HNSW_SIMILARITY='l2'") {
+			t.Errorf("Expected the scratch table to embed as the documents table, got %s", statement)
+		}
+	}
+}
+
+func TestEmbeddingCacheKeyedByTableModel(t *testing.T) {
+	server := &embeddingServer{model: "old-model"}
+	client := newEmbeddingCacheTestClient(t, server, EmbeddingCacheConfig{Size: 10})
+	mc := client.(*manticoreHTTPClient)
+
+	if _, err := client.AISearch("same query", "test-model", 10, 0); err != nil {
+		t.Fatalf("AISearch failed: %v", err)
+	}
+
+	// Creating the documents table again, here with another model, forgets the embeddings of the old one
+	server.mu.Lock()
+	server.model = "new-model"
+	server.mu.Unlock()
+	if err := mc.createDocumentsTable(&models.AISearchConfig{Model: "new-model"}, true); err != nil {
+		t.Fatalf("createDocumentsTable failed: %v", err)
+	}
+	if _, err := client.AISearch("same query", "test-model", 10, 0); err != nil {
+		t.Fatalf("AISearch failed: %v", err)
+	}
+
+	if count := server.statementsStarting("REPLACE INTO query_embeddings_"); count != 2 {
+		t.Errorf("Expected the query to be embedded again by the new model, got %d embeddings", count)
+	}
+}
+
+func TestConcurrentQueryEmbeddingsUseTheirOwnRows(t *testing.T) {
+	server := &embeddingServer{}
+	client := newEmbeddingCacheTestClient(t, server, EmbeddingCacheConfig{Size: 10})
+	mc := client.(*manticoreHTTPClient)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			model, _ := mc.documentsEmbeddingModel()
+			if _, err := mc.embedQuery(context.Background(), model, "same query"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("embedQuery failed: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	ids := make(map[string]bool)
+	for _, statement := range server.statements {
+		if match := scratchRowIDPattern.FindStringSubmatch(statement); match != nil && strings.HasPrefix(statement, "REPLACE") {
+			ids[match[1]] = true
+		}
+	}
+	if len(ids) != 8 {
+		t.Errorf("Expected every embedding to write its own row, got %d distinct rows", len(ids))
+	}
+}
+
+func TestAISearchWithoutEmbeddingCacheContext(t *testing.T) {
+	server := &embeddingServer{}
+	client := newEmbeddingCacheTestClient(t, server, EmbeddingCacheConfig{Size: 10})
+
+	ctx := WithoutEmbeddingCache(context.Background())
+	for i := 0; i < 2; i++ {
+		if _, err := client.AISearchWithContext(ctx, "health check", "test-model", 1, 0); err != nil {
+			t.Fatalf("AISearchWithContext failed: %v", err)
+		}
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.statements) != 0 {
+		t.Errorf("Expected no scratch table statements, got %v", server.statements)
+	}
+	for i, search := range server.searches {
+		knn, _ := search["query"].(map[string]interface{})["knn"].(map[string]interface{})
+		if knn["query"] != "health check" {
+			t.Errorf("Expected search %d to send the query text, got %v", i, knn)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package manticore
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrorCategory is the coarse class of an error shared by retries, circuit breakers and API
+// error responses. Categories derive from ErrorType, so they never disagree with retry decisions.
+type ErrorCategory string
+
+// Error categories
+const (
+	ErrorCategoryNetwork     ErrorCategory = "network"      // Manticore can't be reached
+	ErrorCategoryTimeout     ErrorCategory = "timeout"      // Manticore did not answer in time
+	ErrorCategoryClient      ErrorCategory = "client_error" // the request was rejected: 4xx, validation, authentication
+	ErrorCategoryServer      ErrorCategory = "server_error" // Manticore failed: 5xx
+	ErrorCategorySchema      ErrorCategory = "schema"       // a table or column does not exist
+	ErrorCategoryEmbedding   ErrorCategory = "embedding"    // embeddings could not be generated
+	ErrorCategoryModel       ErrorCategory = "model"        // the embedding model is missing or unavailable
+	ErrorCategoryCircuitOpen ErrorCategory = "circuit_open" // rejected by an open circuit breaker
+	ErrorCategoryUnsupported ErrorCategory = "unsupported"  // the server lacks a feature, such as KNN search
+	ErrorCategoryUnknown     ErrorCategory = "unknown"
+)
+
+// Category returns the category of errors of the type
+func (et ErrorType) Category() ErrorCategory {
+	switch et {
+	case ErrorTypeNetwork, ErrorTypeConnectionRefused, ErrorTypeConnectionReset, ErrorTypeDNS:
+		return ErrorCategoryNetwork
+	case ErrorTypeTimeout:
+		return ErrorCategoryTimeout
+	case ErrorTypeHTTPClient, ErrorTypeAuthentication, ErrorTypeRateLimit, ErrorTypeValidation:
+		return ErrorCategoryClient
+	case ErrorTypeHTTPServer:
+		return ErrorCategoryServer
+	case ErrorTypeCircuitBreaker:
+		return ErrorCategoryCircuitOpen
+	default:
+		return ErrorCategoryUnknown
+	}
+}
+
+// BackendFailure reports whether errors of the category mean Manticore is unhealthy. Client and
+// schema errors were answered by a healthy Manticore, and unsupported features are rejected before
+// sending, so circuit breakers don't count them.
+func (c ErrorCategory) BackendFailure() bool {
+	switch c {
+	case ErrorCategoryClient, ErrorCategorySchema, ErrorCategoryCircuitOpen, ErrorCategoryUnsupported:
+		return false
+	default:
+		return true
+	}
+}
+
+// errorCategoryPatterns categorize, in order, the messages of errors whose type does not tell
+// whether Manticore was reachable
+var errorCategoryPatterns = []struct {
+	category ErrorCategory
+	patterns []string
+}{
+	{ErrorCategorySchema, []string{"unknown local table", "no such table", "unknown table", "unknown column", "unknown field"}},
+	{ErrorCategoryNetwork, []string{"connection", "network"}},
+	{ErrorCategoryEmbedding, []string{"embedding"}},
+	{ErrorCategoryModel, []string{"model"}},
+	{ErrorCategoryClient, []string{"http 4"}},
+	{ErrorCategoryServer, []string{"http 5"}},
+}
+
+// CategorizeError returns the category of err. Typed errors are categorized by their ErrorType,
+// others by the ErrorClassifier; network errors and timeouts are final, other categories give
+// way to more specific messages, such as a server error about an unknown table.
+func CategorizeError(err error) ErrorCategory {
+	if err == nil {
+		return ErrorCategoryUnknown
+	}
+	if IsCircuitOpenError(err) {
+		return ErrorCategoryCircuitOpen
+	}
+	if errors.Is(err, ErrUnsupported) {
+		return ErrorCategoryUnsupported
+	}
+
+	errorType := ErrorTypeUnknown
+	var manticoreErr *ManticoreError
+	var connErr *ConnectionError
+	switch {
+	case errors.As(err, &manticoreErr):
+		errorType = manticoreErr.ErrorType
+	case errors.As(err, &connErr):
+		errorType = connErr.ErrorType
+	}
+	// Retry wrappers flatten the cause into the message, so classify the full text
+	if errorType == ErrorTypeUnknown || errorType == ErrorTypeRetryExhausted {
+		errorType, _ = NewErrorClassifier().classifyErrorType(err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		errorType = ErrorTypeTimeout
+	}
+
+	category := errorType.Category()
+	if category == ErrorCategoryNetwork || category == ErrorCategoryTimeout {
+		return category
+	}
+	message := strings.ToLower(err.Error())
+	for _, rule := range errorCategoryPatterns {
+		if containsAny(message, rule.patterns) {
+			return rule.category
+		}
+	}
+	return category
+}
@@ -1,6 +1,7 @@
 package manticore
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -117,6 +118,16 @@ func (et ErrorType) String() string {
 	}
 }
 
+// ParseErrorType returns the ErrorType named by its String form
+func ParseErrorType(name string) (ErrorType, error) {
+	for et := ErrorTypeUnknown; et <= ErrorTypeRetryExhausted; et++ {
+		if et.String() == name {
+			return et, nil
+		}
+	}
+	return ErrorTypeUnknown, fmt.Errorf("unknown error type %q", name)
+}
+
 // ErrorClassifier provides methods to classify and handle different types of errors
 type ErrorClassifier struct{}
 
@@ -179,6 +190,8 @@ func (ec *ErrorClassifier) classifyErrorType(err error) (ErrorType, bool) {
 	// Network-related errors (typically retryable)
 	networkErrors := map[string]ErrorType{
 		"connection reset by peer":         ErrorTypeConnectionReset,
+		"connection reset":                 ErrorTypeConnectionReset,
+		"service unavailable":              ErrorTypeConnectionRefused,
 		"connection refused":               ErrorTypeConnectionRefused,
 		"broken pipe":                      ErrorTypeConnectionReset,
 		"use of closed network connection": ErrorTypeConnectionReset,
@@ -228,6 +241,18 @@ func (ec *ErrorClassifier) classifyErrorType(err error) (ErrorType, bool) {
 		}
 	}
 
+	// Rate limit errors (retryable)
+	rateLimitErrors := []string{
+		"rate limit",
+		"too many requests",
+	}
+
+	for _, rateLimitError := range rateLimitErrors {
+		if strings.Contains(errStr, rateLimitError) {
+			return ErrorTypeRateLimit, true
+		}
+	}
+
 	// Authentication errors (not retryable)
 	authErrors := []string{
 		"unauthorized",
@@ -276,6 +301,28 @@ func (ec *ErrorClassifier) isNetworkError(err error) bool {
 	return false
 }
 
+// newHTTPStatusError builds a typed error for a failed HTTP response so retry policies can match it by type
+func newHTTPStatusError(statusCode int, endpoint, method, message string) *ManticoreError {
+	errorType := ErrorTypeHTTPClient
+	switch {
+	case statusCode == 429:
+		errorType = ErrorTypeRateLimit
+	case statusCode == 408:
+		errorType = ErrorTypeTimeout
+	case statusCode >= 500:
+		errorType = ErrorTypeHTTPServer
+	}
+
+	return &ManticoreError{
+		StatusCode: statusCode,
+		Message:    message,
+		Endpoint:   endpoint,
+		Method:     method,
+		Retryable:  NewErrorClassifier().isHTTPStatusRetryable(statusCode),
+		ErrorType:  errorType,
+	}
+}
+
 // isHTTPStatusRetryable determines if an HTTP status code is retryable
 func (ec *ErrorClassifier) isHTTPStatusRetryable(statusCode int) bool {
 	switch {
@@ -381,6 +428,42 @@ func IsRetryableError(err error) bool {
 	return retryable
 }
 
+// IsCircuitOpenError reports whether err was caused by the circuit breaker rejecting a request.
+// Callers wrap errors with %v, so the message is checked when the typed error is not available.
+func IsCircuitOpenError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var manticoreErr *ManticoreError
+	if errors.As(err, &manticoreErr) && manticoreErr.ErrorType == ErrorTypeCircuitBreaker {
+		return true
+	}
+
+	return strings.Contains(err.Error(), "circuit breaker is OPEN") ||
+		strings.Contains(err.Error(), "circuit breaker is HALF-OPEN")
+}
+
+// GetErrorType extracts the error category from an error
+func GetErrorType(err error) ErrorType {
+	if err == nil {
+		return ErrorTypeUnknown
+	}
+
+	// Check for our custom error types
+	if manticoreErr, ok := err.(*ManticoreError); ok {
+		return manticoreErr.ErrorType
+	}
+	if connErr, ok := err.(*ConnectionError); ok {
+		return connErr.ErrorType
+	}
+
+	// Fallback to basic classification
+	classifier := NewErrorClassifier()
+	errorType, _ := classifier.classifyErrorType(err)
+	return errorType
+}
+
 // GetErrorBackoffDelay extracts backoff delay from error
 func GetErrorBackoffDelay(err error) time.Duration {
 	if err == nil {
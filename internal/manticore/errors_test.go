@@ -1,7 +1,9 @@
 package manticore
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net"
 	"strings"
 	"testing"
@@ -405,3 +407,52 @@ func TestErrorType_String(t *testing.T) {
 		})
 	}
 }
+
+func TestCategorizeError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected ErrorCategory
+	}{
+		{"nil", nil, ErrorCategoryUnknown},
+		{"deadline", fmt.Errorf("search: %w", context.DeadlineExceeded), ErrorCategoryTimeout},
+		{"typed timeout", &ManticoreError{ErrorType: ErrorTypeTimeout}, ErrorCategoryTimeout},
+		{"connection refused", errors.New("dial tcp 127.0.0.1:9308: connection refused"), ErrorCategoryNetwork},
+		{"typed connection error", &ConnectionError{ErrorType: ErrorTypeDNS}, ErrorCategoryNetwork},
+		{"service unavailable", errors.New("embedding service unavailable"), ErrorCategoryNetwork},
+		{"bad request", newHTTPStatusError(400, "/search", "POST", "bad request"), ErrorCategoryClient},
+		{"rate limit", newHTTPStatusError(429, "/search", "POST", "too many requests"), ErrorCategoryClient},
+		{"server error", newHTTPStatusError(500, "/search", "POST", "internal error"), ErrorCategoryServer},
+		{"unknown table", newHTTPStatusError(500, "/sql", "POST", "unknown local table 'documents'"), ErrorCategorySchema},
+		{"embedding", errors.New("embedding generation failed"), ErrorCategoryEmbedding},
+		{"model", errors.New("model not found"), ErrorCategoryModel},
+		{"circuit open", &ManticoreError{ErrorType: ErrorTypeCircuitBreaker, Message: "circuit breaker is OPEN: too many failures"}, ErrorCategoryCircuitOpen},
+		{"unsupported", (&Capabilities{Major: 6}).RequireAISearch(), ErrorCategoryUnsupported},
+		{"retry exhausted", &ManticoreError{ErrorType: ErrorTypeRetryExhausted, Message: "max retry attempts (3) exceeded, last error: read: connection reset"}, ErrorCategoryNetwork},
+		{"unknown", errors.New("something odd"), ErrorCategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if category := CategorizeError(tt.err); category != tt.expected {
+				t.Errorf("Expected category %q, got %q", tt.expected, category)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	config := DefaultCircuitBreakerConfig()
+	config.FailureThreshold = 2
+	cb := NewCircuitBreaker(config)
+	defer cb.Close()
+
+	for i := 0; i < 5; i++ {
+		cb.Execute(context.Background(), func(ctx context.Context) error {
+			return newHTTPStatusError(400, "/sql", "POST", "unknown column 'foo'")
+		})
+	}
+	if !cb.IsClosed() || cb.GetStats().TotalFailures != 0 {
+		t.Errorf("Expected client errors not to count as failures, got %v with %d failures", cb.GetState(), cb.GetStats().TotalFailures)
+	}
+}
@@ -0,0 +1,204 @@
+package manticore
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// FaultConfig configures the faults injected into the requests of the HTTP client, for exercising
+// the circuit breaker and retries in staging without breaking Manticore. Each request is delayed
+// by Latency with LatencyProbability, then fails with an ErrorStatus response with
+// ErrorProbability or with a connection reset with ResetProbability, without reaching Manticore.
+// The zero value injects nothing.
+type FaultConfig struct {
+	LatencyProbability float64
+	Latency            time.Duration
+	ErrorProbability   float64
+	ErrorStatus        int // 5xx status of injected errors; 0 uses 503
+	ResetProbability   float64
+}
+
+// Enabled reports whether any fault is injected
+func (c FaultConfig) Enabled() bool {
+	return (c.LatencyProbability > 0 && c.Latency > 0) || c.ErrorProbability > 0 || c.ResetProbability > 0
+}
+
+// String describes the injected faults for logs
+func (c FaultConfig) String() string {
+	return fmt.Sprintf("latency %v with probability %g, HTTP %d with probability %g, connection reset with probability %g",
+		c.Latency, c.LatencyProbability, c.errorStatus(), c.ErrorProbability, c.ResetProbability)
+}
+
+func (c FaultConfig) errorStatus() int {
+	if c.ErrorStatus == 0 {
+		return http.StatusServiceUnavailable
+	}
+	return c.ErrorStatus
+}
+
+// validate checks the probabilities and the error status
+func (c FaultConfig) validate() error {
+	for name, probability := range map[string]float64{"latency": c.LatencyProbability, "error": c.ErrorProbability, "reset": c.ResetProbability} {
+		if probability < 0 || probability > 1 {
+			return fmt.Errorf("invalid %s fault probability: %g (use 0 to 1)", name, probability)
+		}
+	}
+	if c.ErrorProbability+c.ResetProbability > 1 {
+		return fmt.Errorf("error and reset fault probabilities add up to more than 1")
+	}
+	if c.Latency < 0 {
+		return fmt.Errorf("invalid fault latency: %v", c.Latency)
+	}
+	if c.ErrorStatus != 0 && (c.ErrorStatus < 500 || c.ErrorStatus > 599) {
+		return fmt.Errorf("invalid fault error status: %d (use 500 to 599)", c.ErrorStatus)
+	}
+	return nil
+}
+
+// FaultStats counts the faults injected into requests
+type FaultStats struct {
+	Requests  int64 `json:"requests"`
+	Latencies int64 `json:"latencies"`
+	Errors    int64 `json:"errors"`
+	Resets    int64 `json:"resets"`
+}
+
+// faultTransport injects the faults of its config into the requests of next
+type faultTransport struct {
+	next   http.RoundTripper
+	config FaultConfig
+
+	mu     sync.Mutex
+	random *rand.Rand
+
+	requests, latencies, errors, resets atomic.Int64
+}
+
+// newFaultTransport wraps next with fault injection
+func newFaultTransport(next http.RoundTripper, config FaultConfig) *faultTransport {
+	return &faultTransport{next: next, config: config, random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// draw returns a random number in [0, 1)
+func (t *faultTransport) draw() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.random.Float64()
+}
+
+// RoundTrip delays, fails or passes req on to the next transport as the config draws
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+
+	if t.config.Latency > 0 && t.draw() < t.config.LatencyProbability {
+		t.latencies.Add(1)
+		timer := time.NewTimer(t.config.Latency)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+
+	draw := t.draw()
+	switch {
+	case draw < t.config.ErrorProbability:
+		t.errors.Add(1)
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		status := t.config.errorStatus()
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			StatusCode:    status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          io.NopCloser(strings.NewReader(`{"error":"injected fault"}`)),
+			ContentLength: -1,
+			Request:       req,
+		}, nil
+	case draw < t.config.ErrorProbability+t.config.ResetProbability:
+		t.resets.Add(1)
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of the next transport
+func (t *faultTransport) CloseIdleConnections() {
+	if closer, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// stats returns the faults injected so far, or nil when t is nil
+func (t *faultTransport) stats() *FaultStats {
+	if t == nil {
+		return nil
+	}
+	return &FaultStats{
+		Requests:  t.requests.Load(),
+		Latencies: t.latencies.Load(),
+		Errors:    t.errors.Load(),
+		Resets:    t.resets.Load(),
+	}
+}
+
+// loadFaultConfigFromEnvironment reads MANTICORE_FAULT_LATENCY_PROBABILITY,
+// MANTICORE_FAULT_LATENCY, MANTICORE_FAULT_ERROR_PROBABILITY, MANTICORE_FAULT_ERROR_STATUS and
+// MANTICORE_FAULT_RESET_PROBABILITY
+func loadFaultConfigFromEnvironment(config *FaultConfig) error {
+	probabilities := []struct {
+		name   string
+		target *float64
+	}{
+		{"MANTICORE_FAULT_LATENCY_PROBABILITY", &config.LatencyProbability},
+		{"MANTICORE_FAULT_ERROR_PROBABILITY", &config.ErrorProbability},
+		{"MANTICORE_FAULT_RESET_PROBABILITY", &config.ResetProbability},
+	}
+	for _, p := range probabilities {
+		if value := os.Getenv(p.name); value != "" {
+			probability, err := strconv.ParseFloat(value, 64)
+			if err != nil || probability < 0 || probability > 1 {
+				return fmt.Errorf("invalid %s: %q (use a probability from 0 to 1)", p.name, value)
+			}
+			*p.target = probability
+		}
+	}
+
+	if value := os.Getenv("MANTICORE_FAULT_LATENCY"); value != "" {
+		latency, err := time.ParseDuration(value)
+		if err != nil || latency < 0 {
+			return fmt.Errorf("invalid MANTICORE_FAULT_LATENCY: %q", value)
+		}
+		config.Latency = latency
+	} else if config.LatencyProbability > 0 && config.Latency == 0 {
+		config.Latency = 2 * time.Second
+	}
+
+	if value := os.Getenv("MANTICORE_FAULT_ERROR_STATUS"); value != "" {
+		status, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid MANTICORE_FAULT_ERROR_STATUS: %q", value)
+		}
+		config.ErrorStatus = status
+	}
+
+	return config.validate()
+}
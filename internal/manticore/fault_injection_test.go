@@ -0,0 +1,138 @@
+package manticore
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFaultTransport(t *testing.T) {
+	var served atomic.Int64
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		served.Add(1)
+		w.Write([]byte("ok"))
+	})
+	defer server.Close()
+
+	get := func(transport *faultTransport) (*http.Response, error) {
+		req, _ := http.NewRequest("GET", server.URL, nil)
+		return transport.RoundTrip(req)
+	}
+
+	t.Run("error", func(t *testing.T) {
+		transport := newFaultTransport(http.DefaultTransport, FaultConfig{ErrorProbability: 1, ErrorStatus: 502})
+		resp, err := get(transport)
+		if err != nil || resp.StatusCode != 502 {
+			t.Fatalf("Expected an injected 502, got %v, %v", resp, err)
+		}
+		resp.Body.Close()
+		if stats := transport.stats(); stats.Errors != 1 || stats.Requests != 1 || served.Load() != 0 {
+			t.Errorf("Expected the request to be failed without reaching the server, got %+v", stats)
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		transport := newFaultTransport(http.DefaultTransport, FaultConfig{ResetProbability: 1})
+		_, err := get(transport)
+		if err == nil || GetErrorType(err) != ErrorTypeConnectionReset {
+			t.Fatalf("Expected a connection reset, got %v", err)
+		}
+		if stats := transport.stats(); stats.Resets != 1 || served.Load() != 0 {
+			t.Errorf("Expected the connection to be reset without reaching the server, got %+v", stats)
+		}
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		transport := newFaultTransport(http.DefaultTransport, FaultConfig{LatencyProbability: 1, Latency: 20 * time.Millisecond})
+		start := time.Now()
+		resp, err := get(transport)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected the delayed request to succeed, got %v, %v", resp, err)
+		}
+		resp.Body.Close()
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond || served.Load() != 1 {
+			t.Errorf("Expected the request to reach the server after 20ms, took %v", elapsed)
+		}
+	})
+
+	t.Run("latency cancelled", func(t *testing.T) {
+		transport := newFaultTransport(http.DefaultTransport, FaultConfig{LatencyProbability: 1, Latency: time.Minute})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+		if _, err := transport.RoundTrip(req); err != context.DeadlineExceeded {
+			t.Errorf("Expected the delay to end with the request context, got %v", err)
+		}
+	})
+}
+
+func TestFaultInjectionResilienceStats(t *testing.T) {
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"data":[],"error":""}]`))
+	})
+	defer server.Close()
+
+	plain := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+	defer plain.Close()
+	if stats := plain.GetResilienceStats(); stats.FaultInjection != nil {
+		t.Errorf("Expected no fault injection stats without faults, got %+v", stats.FaultInjection)
+	}
+
+	config := DefaultHTTPClientConfig(server.URL)
+	config.Faults = FaultConfig{ErrorProbability: 1}
+	config.RetryConfig.MaxAttempts = 1
+	client := NewHTTPClient(config).(*manticoreHTTPClient)
+	defer client.Close()
+	if _, err := client.querySQL("SHOW TABLES"); err == nil {
+		t.Error("Expected an injected error")
+	}
+	stats := client.GetResilienceStats()
+	if stats.FaultInjection == nil || stats.FaultInjection.Errors == 0 || stats.CircuitBreaker.TotalFailures == 0 {
+		t.Errorf("Expected the injected error to reach the circuit breaker, got %+v and %+v", stats.FaultInjection, stats.CircuitBreaker)
+	}
+}
+
+func TestLoadFaultConfigFromEnvironment(t *testing.T) {
+	t.Setenv("MANTICORE_FAULT_LATENCY_PROBABILITY", "0.1")
+	t.Setenv("MANTICORE_FAULT_ERROR_PROBABILITY", "0.05")
+	t.Setenv("MANTICORE_FAULT_ERROR_STATUS", "500")
+	t.Setenv("MANTICORE_FAULT_RESET_PROBABILITY", "0.02")
+
+	var config FaultConfig
+	if err := loadFaultConfigFromEnvironment(&config); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := FaultConfig{LatencyProbability: 0.1, Latency: 2 * time.Second, ErrorProbability: 0.05, ErrorStatus: 500, ResetProbability: 0.02}
+	if config != expected || !config.Enabled() {
+		t.Errorf("Expected %+v, got %+v", expected, config)
+	}
+
+	for name, value := range map[string]string{
+		"MANTICORE_FAULT_ERROR_PROBABILITY": "1.5",
+		"MANTICORE_FAULT_ERROR_STATUS":      "404",
+		"MANTICORE_FAULT_LATENCY":           "soon",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			var config FaultConfig
+			if err := loadFaultConfigFromEnvironment(&config); err == nil {
+				t.Errorf("Expected an error for %s=%s", name, value)
+			}
+		})
+	}
+
+	t.Run("probabilities over 1", func(t *testing.T) {
+		t.Setenv("MANTICORE_FAULT_ERROR_PROBABILITY", "0.7")
+		t.Setenv("MANTICORE_FAULT_RESET_PROBABILITY", "0.5")
+		var config FaultConfig
+		if err := loadFaultConfigFromEnvironment(&config); err == nil {
+			t.Error("Expected an error when error and reset probabilities exceed 1")
+		}
+	})
+
+	if (FaultConfig{}).Enabled() {
+		t.Error("Expected the zero value to inject nothing")
+	}
+}
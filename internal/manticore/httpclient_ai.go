@@ -1,13 +1,12 @@
 package manticore
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"sort"
 	"time"
 
@@ -92,27 +91,55 @@ func (mc *manticoreHTTPClient) AISearchFallback(query string, model string, limi
 
 // AISearch performs AI-powered semantic search using Manticore's Auto Embeddings functionality
 func (mc *manticoreHTTPClient) AISearch(query string, model string, limit, offset int) (*SearchResponse, error) {
+	return mc.AISearchWithContext(context.Background(), query, model, limit, offset)
+}
+
+// AISearchWithContext performs AI search that is cancelled with ctx. Without a deadline on ctx
+// the AI timeout applies. With the embedding cache, the search and its retries send the cached
+// embedding of the query instead of the query text.
+func (mc *manticoreHTTPClient) AISearchWithContext(ctx context.Context, query string, model string, limit, offset int) (*SearchResponse, error) {
 	startTime := time.Now()
 	log.Printf("[AI_SEARCH] Starting AI search operation: query='%s', model='%s', limit=%d, offset=%d", query, model, limit, offset)
 
+	// Servers without KNN search or Auto Embeddings would reject the request, so don't send it
+	if err := mc.GetCapabilities().RequireAISearch(); err != nil {
+		log.Printf("[AI_SEARCH] [ERROR] %v", err)
+		return nil, err
+	}
+
+	// Execute with circuit breaker and retry logic
+	ctx, cancel := withDefaultDeadline(ctx, mc.timeouts.AI)
+	defer cancel()
+
+	queryVector := mc.queryEmbedding(ctx, query)
+
 	operation := func(ctx context.Context) (*SearchResponse, error) {
 		requestStartTime := time.Now()
 
-		// Create KNN search request with Auto Embeddings (text-based query)
-		request := mc.CreateAutoEmbeddingSearchRequest("documents", "content_vector", query, limit, offset)
-
-		// Marshal the search request
-		reqBody, err := json.Marshal(request)
-		if err != nil {
+		// Create KNN search request with the cached embedding, or with Auto Embeddings (text-based query)
+		request := mc.CreateAutoEmbeddingSearchRequest(mc.table("documents"), "content_vector", query, limit, offset)
+		if queryVector != nil {
+			request = mc.CreateKNNSearchRequest(mc.table("documents"), "content_vector", queryVector, limit, offset)
+		}
+		request = knnOptions(ctx).Apply(request)
+		request = FilterByStatus(request, searchStatuses(ctx))
+		request = recencyOptions(ctx).Apply(request)
+		request = idFilter(ctx).Apply(request)
+		request = regexFilter(ctx).Apply(request)
+		request = histogramFacets(ctx).Apply(WithTagFacet(tagFilter(ctx).Apply(request)))
+
+		// Encode the AI search request into a pooled buffer
+		reqBuf := getBuffer()
+		if err := encodeJSON(reqBuf, request); err != nil {
+			putBuffer(reqBuf)
 			log.Printf("[AI_SEARCH] [ERROR] Failed to marshal AI search request: %v", err)
 			return nil, fmt.Errorf("failed to marshal AI search request: %v", err)
 		}
 
-		log.Printf("[AI_SEARCH] [REQUEST] POST %s/search - Body size: %d bytes", mc.baseURL, len(reqBody))
-		log.Printf("[AI_SEARCH] [REQUEST] Payload: %s", string(reqBody))
+		log.Printf("[AI_SEARCH] [REQUEST] POST %s/search - Body size: %d bytes", mc.baseURL, reqBuf.Len())
 
 		// Create HTTP request
-		req, err := http.NewRequestWithContext(ctx, "POST", mc.baseURL+"/search", bytes.NewReader(reqBody))
+		req, err := newPooledRequest(ctx, "POST", mc.baseURL+"/search", reqBuf)
 		if err != nil {
 			log.Printf("[AI_SEARCH] [ERROR] Failed to create HTTP request: %v", err)
 			return nil, fmt.Errorf("failed to create AI search request: %v", err)
@@ -129,36 +156,34 @@ func (mc *manticoreHTTPClient) AISearch(query string, model string, limit, offse
 		}
 		defer resp.Body.Close()
 
-		// Read response body
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			log.Printf("[AI_SEARCH] [ERROR] Failed to read response body after %v: %v", requestDuration, err)
-			return nil, fmt.Errorf("failed to read AI search response: %v", err)
-		}
-
-		log.Printf("[AI_SEARCH] [RESPONSE] HTTP %d - Response size: %d bytes - Duration: %v", resp.StatusCode, len(body), requestDuration)
-		log.Printf("[AI_SEARCH] [RESPONSE] Body: %s", string(body))
+		// Bound the response size so an oversized payload cannot exhaust memory
+		body := newMaxSizeReader(resp.Body, mc.maxResponseSize)
 
 		if resp.StatusCode >= 400 {
-			log.Printf("[AI_SEARCH] [ERROR] AI search operation failed: HTTP %d, %s", resp.StatusCode, string(body))
-			return nil, fmt.Errorf("AI search operation failed: HTTP %d, %s", resp.StatusCode, string(body))
+			respBuf := getBuffer()
+			defer putBuffer(respBuf)
+			if err := readLimited(respBuf, body, maxLoggedBodySize*4); err != nil && !errors.Is(err, ErrResponseTooLarge) {
+				log.Printf("[AI_SEARCH] [ERROR] Failed to read response body after %v: %v", requestDuration, err)
+				return nil, fmt.Errorf("failed to read AI search response: %v", err)
+			}
+			log.Printf("[AI_SEARCH] [ERROR] AI search operation failed: HTTP %d, %s", resp.StatusCode, respBuf.String())
+			return nil, fmt.Errorf("AI search operation failed: HTTP %d, %s", resp.StatusCode, respBuf.String())
 		}
 
-		// Parse response
-		var searchResponse SearchResponse
-		if err := json.Unmarshal(body, &searchResponse); err != nil {
+		// Decode the response as it streams in, stopping after the requested number of hits
+		counter := &byteCounter{}
+		searchResponse, err := decodeSearchResponse(io.TeeReader(body, counter), limit)
+		if err != nil {
 			log.Printf("[AI_SEARCH] [ERROR] Failed to parse AI search response: %v", err)
 			return nil, fmt.Errorf("failed to parse AI search response: %v", err)
 		}
 
+		log.Printf("[AI_SEARCH] [RESPONSE] HTTP %d - Response size: %d bytes - Duration: %v", resp.StatusCode, counter.size, requestDuration)
+
 		log.Printf("[AI_SEARCH] [SUCCESS] AI search completed: %d hits found - Duration: %v", searchResponse.Hits.Total, requestDuration)
-		return &searchResponse, nil
+		return searchResponse, nil
 	}
 
-	// Execute with circuit breaker and retry logic
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second) // Longer timeout for AI operations
-	defer cancel()
-
 	result, err := mc.executeAISearchWithRetry(ctx, operation)
 
 	totalDuration := time.Since(startTime)
@@ -215,11 +240,18 @@ func (mc *manticoreHTTPClient) executeAISearchWithRetry(ctx context.Context, ope
 		return err
 	}
 
-	err := mc.circuitBreakerWithRetry.Execute(ctx, mc.baseURL+"/search", "POST", retryOperation)
+	err := mc.circuitBreakerWithRetry.ExecuteClass(ctx, OperationClassEmbedding, mc.baseURL+"/search", "POST", retryOperation)
 	return result, err
 }
 
-// CreateKNNSearchRequest creates a KNN (K-Nearest Neighbors) search request for AI search
+// knnK returns the number of nearest neighbours needed to serve a page. Manticore applies offset
+// and limit to the k neighbours found, so k has to cover every result before the page as well.
+func knnK(limit, offset int) int {
+	return offset + limit
+}
+
+// CreateKNNSearchRequest creates a KNN (K-Nearest Neighbors) search request for AI search. k covers
+// the results up to the page; KNNOptions.Apply tunes k and ef.
 func (mc *manticoreHTTPClient) CreateKNNSearchRequest(index string, vectorField string, queryVector []float64, limit, offset int) SearchRequest {
 	log.Printf("[AI_SEARCH] [KNN] Creating KNN search request: field='%s', vector size=%d, limit=%d, offset=%d",
 		vectorField, len(queryVector), limit, offset)
@@ -229,7 +261,7 @@ func (mc *manticoreHTTPClient) CreateKNNSearchRequest(index string, vectorField
 		"knn": map[string]interface{}{
 			"field":        vectorField,
 			"query_vector": queryVector,
-			"k":            limit,
+			"k":            knnK(limit, offset),
 		},
 	}
 
@@ -251,7 +283,7 @@ func (mc *manticoreHTTPClient) CreateAutoEmbeddingSearchRequest(index string, ve
 		"knn": map[string]interface{}{
 			"field": vectorField,
 			"query": queryText, // Text query for Auto Embeddings
-			"k":     limit,
+			"k":     knnK(limit, offset),
 		},
 	}
 
@@ -281,7 +313,7 @@ func (mc *manticoreHTTPClient) CreateHybridAISearchRequest(index string, textQue
 					"knn": map[string]interface{}{
 						"field":        "content_vector",
 						"query_vector": queryVector,
-						"k":            limit,
+						"k":            knnK(limit, offset),
 					},
 				},
 			},
@@ -34,24 +34,10 @@ func TestAISearch(t *testing.T) {
 			searchResp: SearchResponse{
 				Took:     5,
 				TimedOut: false,
-				Hits: struct {
-					Total         int32  `json:"total"`
-					TotalRelation string `json:"total_relation"`
-					Hits          []struct {
-						Index  string                 `json:"_index"`
-						ID     int64                  `json:"_id"`
-						Score  float32                `json:"_score"`
-						Source map[string]interface{} `json:"_source"`
-					} `json:"hits"`
-				}{
+				Hits: SearchHits{
 					Total:         2,
 					TotalRelation: "eq",
-					Hits: []struct {
-						Index  string                 `json:"_index"`
-						ID     int64                  `json:"_id"`
-						Score  float32                `json:"_score"`
-						Source map[string]interface{} `json:"_source"`
-					}{
+					Hits: []SearchHit{
 						{
 							Index: "documents",
 							ID:    1,
@@ -291,8 +277,9 @@ func TestCreateKNNSearchRequest(t *testing.T) {
 				t.Errorf("Expected field %s, got %v", tt.vectorField, knnQuery["field"])
 			}
 
-			if k, ok := knnQuery["k"].(int); !ok || k != tt.limit {
-				t.Errorf("Expected k %d, got %v", tt.limit, knnQuery["k"])
+			// k covers the skipped results as well as the page
+			if k, ok := knnQuery["k"].(int); !ok || k != tt.offset+tt.limit {
+				t.Errorf("Expected k %d, got %v", tt.offset+tt.limit, knnQuery["k"])
 			}
 
 			if queryVector, ok := knnQuery["query_vector"].([]float64); !ok || len(queryVector) != len(tt.queryVector) {
@@ -570,16 +557,7 @@ func BenchmarkAISearch(b *testing.B) {
 			response := SearchResponse{
 				Took:     5,
 				TimedOut: false,
-				Hits: struct {
-					Total         int32  `json:"total"`
-					TotalRelation string `json:"total_relation"`
-					Hits          []struct {
-						Index  string                 `json:"_index"`
-						ID     int64                  `json:"_id"`
-						Score  float32                `json:"_score"`
-						Source map[string]interface{} `json:"_source"`
-					} `json:"hits"`
-				}{
+				Hits: SearchHits{
 					Total: 10,
 				},
 			}
@@ -0,0 +1,62 @@
+package manticore
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// backupTimeout bounds a Manticore BACKUP statement, which copies every table file
+const backupTimeout = 10 * time.Minute
+
+// backupTables are the tables included in a Manticore-side backup
+var backupTables = append(append([]string(nil), requiredTables...), schemaMetaTable)
+
+// backupPathPattern matches absolute paths that can be passed to BACKUP without quoting
+var backupPathPattern = regexp.MustCompile(`^/[A-Za-z0-9._/-]*$`)
+
+// BackupResult describes a backup written by Manticore on its own host
+type BackupResult struct {
+	Path   string   `json:"path"`
+	Tables []string `json:"tables"`
+}
+
+// Backup runs Manticore's BACKUP statement, which freezes the tables and copies their files into
+// path on the Manticore host. It needs Manticore Buddy; restore such a backup with manticore-backup --restore.
+func (mc *manticoreHTTPClient) Backup(path string) (*BackupResult, error) {
+	if !backupPathPattern.MatchString(path) || strings.Contains(path, "..") {
+		return nil, fmt.Errorf("invalid backup path %q: must be an absolute path of letters, digits, '.', '_', '-' and '/'", path)
+	}
+
+	tables := mc.tables(backupTables)
+	query := fmt.Sprintf("BACKUP TABLES %s TO %s", strings.Join(tables, ", "), path)
+	log.Printf("[SCHEMA] [BACKUP] Starting Manticore backup: %s", query)
+	startTime := time.Now()
+
+	// Not retried: a partially written backup directory makes a second attempt fail anyway
+	ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+	defer cancel()
+	response, err := mc.runSQLQuery(ctx, query)
+
+	if mc.metricsCollector != nil {
+		mc.metricsCollector.RecordRequest("Backup", time.Since(startTime), err == nil, "")
+	}
+	if err != nil {
+		log.Printf("[SCHEMA] [BACKUP] [ERROR] Backup failed after %v: %v", time.Since(startTime), err)
+		return nil, fmt.Errorf("manticore backup failed: %v", err)
+	}
+
+	result := &BackupResult{Path: path, Tables: tables}
+	// Buddy reports the directory it created for this backup
+	if len(response.Data) > 0 {
+		if reported, ok := response.Data[0]["Path"].(string); ok && reported != "" {
+			result.Path = reported
+		}
+	}
+
+	log.Printf("[SCHEMA] [BACKUP] [SUCCESS] Backup written to %s in %v", result.Path, time.Since(startTime))
+	return result, nil
+}
@@ -0,0 +1,36 @@
+package manticore
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestBackup(t *testing.T) {
+	var received string
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		received = r.PostForm.Get("query")
+		w.WriteHeader(200)
+		w.Write([]byte(`[{"columns":[{"Path":{"type":"string"}}],"data":[{"Path":"/backups/backup-20261017120000"}],"total":1,"error":"","warning":""}]`))
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+
+	result, err := client.Backup("/backups")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if received != "BACKUP TABLES documents, documents_vector, schema_meta TO /backups" {
+		t.Errorf("Unexpected query: %s", received)
+	}
+	if result.Path != "/backups/backup-20261017120000" || len(result.Tables) != 3 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	for _, path := range []string{"backups", "/tmp/a b", "/tmp/x;DROP TABLE documents", "/tmp/../etc"} {
+		if _, err := client.Backup(path); err == nil {
+			t.Errorf("Expected error for path %q", path)
+		}
+	}
+}
@@ -3,11 +3,10 @@ package manticore
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/ad/manticoresearch-go/internal/models"
@@ -40,8 +39,13 @@ func (mc *manticoreHTTPClient) batchedBulkIndex(documents []*models.Document, ve
 
 	successfulBatches := 0
 	var lastError error
+	pace := newPacer(mc.bulkConfig.BatchDelay, mc.bulkConfig.PacingFactor)
 
 	for i := 0; i < len(documents); i += batchSize {
+		// Pause between batches to avoid overwhelming the server
+		pace.wait()
+		batchStartTime := time.Now()
+
 		batchStart := i
 		batchEnd := i + batchSize
 		if batchEnd > len(documents) {
@@ -57,7 +61,9 @@ func (mc *manticoreHTTPClient) batchedBulkIndex(documents []*models.Document, ve
 		batchNum := (i / batchSize) + 1
 		log.Printf("[INDEX] [BULK] [BATCHED] Processing batch %d/%d: documents %d-%d", batchNum, totalBatches, batchStart+1, batchEnd)
 
-		if err := mc.bulkIndexDocuments(batchDocs, batchVectors); err != nil {
+		err := mc.bulkIndexDocuments(batchDocs, batchVectors)
+		pace.observe(time.Since(batchStartTime))
+		if err != nil {
 			log.Printf("[INDEX] [BULK] [BATCHED] [WARNING] Batch %d failed, falling back to individual operations: %v", batchNum, err)
 			if err := mc.fallbackToIndividualIndexing(batchDocs, batchVectors); err != nil {
 				log.Printf("[INDEX] [BULK] [BATCHED] [ERROR] Individual fallback also failed for batch %d: %v", batchNum, err)
@@ -68,9 +74,6 @@ func (mc *manticoreHTTPClient) batchedBulkIndex(documents []*models.Document, ve
 
 		successfulBatches++
 		log.Printf("[INDEX] [BULK] [BATCHED] Completed batch %d/%d", batchNum, totalBatches)
-
-		// Small delay between batches to avoid overwhelming the server
-		time.Sleep(100 * time.Millisecond)
 	}
 
 	totalDuration := time.Since(startTime)
@@ -183,26 +186,57 @@ func (mc *manticoreHTTPClient) batchWorker(jobs <-chan batchJob, results chan<-
 	}
 }
 
-// bulkIndexDocuments performs bulk indexing using the /bulk endpoint with NDJSON format
+// bulkIndexDocuments performs bulk indexing using the /bulk endpoint with NDJSON format. The
+// unified and vector batches are submitted concurrently.
 func (mc *manticoreHTTPClient) bulkIndexDocuments(documents []*models.Document, vectors [][]float64) error {
-	// Index documents in unified table with Auto Embeddings (vectors will be generated automatically)
-	if err := mc.bulkIndexUnified(documents); err != nil {
-		return fmt.Errorf("bulk unified indexing with Auto Embeddings failed: %v", err)
-	}
-
 	// Also index documents with TF-IDF vectors in documents_vector table (if vectors provided)
+	var vectorErr error
+	var wg sync.WaitGroup
 	if len(vectors) > 0 {
-		if err := mc.bulkIndexVectors(documents, vectors); err != nil {
-			log.Printf("[INDEX] [BULK] [WARNING] Vector indexing failed, but unified indexing succeeded: %v", err)
-			// Don't fail the whole operation if vector indexing fails
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vectorErr = mc.bulkIndexVectors(documents, vectors)
+		}()
+	}
+
+	// Index documents in unified table with Auto Embeddings (vectors will be generated automatically)
+	unifiedErr := mc.bulkIndexUnified(documents)
+	wg.Wait()
+
+	if unifiedErr != nil {
+		err := fmt.Errorf("bulk unified indexing with Auto Embeddings failed: %v", unifiedErr)
+		if vectorErr != nil {
+			err = errors.Join(err, fmt.Errorf("bulk vector indexing failed: %v", vectorErr))
 		}
+		return err
+	}
+	if vectorErr != nil {
+		log.Printf("[INDEX] [BULK] [WARNING] Vector indexing failed, but unified indexing succeeded: %v", vectorErr)
+		// Don't fail the whole operation if vector indexing fails
 	}
 
 	return nil
 }
 
+// acquireBulkSlot waits until fewer than MaxConcurrentBatch bulk submissions are in flight and
+// returns the function releasing the slot
+func (mc *manticoreHTTPClient) acquireBulkSlot() func() {
+	if mc.bulkSlots == nil {
+		return func() {}
+	}
+	mc.bulkSlots <- struct{}{}
+	return func() { <-mc.bulkSlots }
+}
+
 // bulkIndexUnified performs bulk indexing for documents with Auto Embeddings using NDJSON format
 func (mc *manticoreHTTPClient) bulkIndexUnified(documents []*models.Document) error {
+	return mc.bulkReplaceDocuments(mc.table("documents"), documents)
+}
+
+// bulkReplaceDocuments replaces documents in table, a documents table named as in Manticore, whose
+// Auto Embeddings are generated from their content
+func (mc *manticoreHTTPClient) bulkReplaceDocuments(table string, documents []*models.Document) error {
 	if len(documents) == 0 {
 		return nil
 	}
@@ -210,60 +244,67 @@ func (mc *manticoreHTTPClient) bulkIndexUnified(documents []*models.Document) er
 	operation := func(ctx context.Context) error {
 		requestStartTime := time.Now()
 
-		// Build NDJSON payload for bulk operation
-		var ndjsonBuilder strings.Builder
-		for _, doc := range documents {
-			bulkReq := map[string]interface{}{
-				"replace": map[string]interface{}{
-					"index": "documents",
-					"id":    doc.ID,
-					"doc": map[string]interface{}{
-						"title":   doc.Title,
-						"content": doc.Content,
-						"url":     doc.URL,
+		// Stream the NDJSON payload for bulk operation as it is encoded
+		req, payload, err := mc.newBulkRequest(ctx, func(encode func(v interface{}) error) error {
+			for _, doc := range documents {
+				bulkReq := map[string]interface{}{
+					"replace": map[string]interface{}{
+						"index": table,
+						"id":    doc.ID,
+						"doc": map[string]interface{}{
+							"title":        doc.Title,
+							"content":      doc.Content,
+							"url":          doc.URL,
+							"status":       documentStatusCode(doc.Status),
+							"indexed_at":   doc.IndexedAt,
+							"updated_at":   doc.UpdatedAt,
+							"tags":         tagsValue(doc.Tags),
+							"content_hash": doc.ContentHash(),
+							URLAttribute:   doc.URL,
+						},
 					},
-				},
-			}
+				}
 
-			jsonBytes, err := json.Marshal(bulkReq)
-			if err != nil {
-				return fmt.Errorf("failed to marshal bulk request: %v", err)
+				if err := encode(bulkReq); err != nil {
+					return fmt.Errorf("failed to marshal bulk request: %v", err)
+				}
 			}
-			ndjsonBuilder.Write(jsonBytes)
-			ndjsonBuilder.WriteByte('\n')
-		}
-
-		payload := ndjsonBuilder.String()
-		log.Printf("[INDEX] [BULK] [UNIFIED] [REQUEST] POST %s/bulk - Documents: %d, Body size: %d bytes (Auto Embeddings)", mc.baseURL, len(documents), len(payload))
-		log.Printf("[INDEX] [BULK] [UNIFIED] [REQUEST] Sample payload (first 500 chars): %s", truncateString(payload, 500))
-
-		req, err := http.NewRequestWithContext(ctx, "POST", mc.baseURL+"/bulk", strings.NewReader(payload))
+			return nil
+		})
 		if err != nil {
 			return fmt.Errorf("failed to create bulk request: %v", err)
 		}
-		req.Header.Set("Content-Type", "application/x-ndjson")
 
 		resp, err := mc.httpClient.Do(req)
 		requestDuration := time.Since(requestStartTime)
+		if err == nil {
+			defer resp.Body.Close()
+		}
+		if payloadErr := payload.wait(); payloadErr != nil {
+			return payloadErr
+		}
+
+		log.Printf("[INDEX] [BULK] [UNIFIED] [REQUEST] POST %s/bulk - Documents: %d, Body size: %d bytes, sent: %d bytes (Auto Embeddings)", mc.baseURL, len(documents), payload.size, payload.sent)
+		log.Printf("[INDEX] [BULK] [UNIFIED] [REQUEST] Sample payload (first 500 chars): %s", payload.sample)
 
 		if err != nil {
 			log.Printf("[INDEX] [BULK] [UNIFIED] [ERROR] HTTP request failed after %v: %v", requestDuration, err)
 			return fmt.Errorf("bulk request failed: %v", err)
 		}
-		defer resp.Body.Close()
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
+		respBuf := getBuffer()
+		defer putBuffer(respBuf)
+		if err := readLimited(respBuf, resp.Body, mc.maxResponseSize); err != nil {
 			log.Printf("[INDEX] [BULK] [UNIFIED] [ERROR] Failed to read response body after %v: %v", requestDuration, err)
 			return fmt.Errorf("failed to read bulk response: %v", err)
 		}
+		body := respBuf.Bytes()
 
 		log.Printf("[INDEX] [BULK] [UNIFIED] [RESPONSE] HTTP %d - Response size: %d bytes - Duration: %v", resp.StatusCode, len(body), requestDuration)
-		log.Printf("[INDEX] [BULK] [UNIFIED] [RESPONSE] Body: %s", string(body))
 
 		if resp.StatusCode >= 400 {
 			log.Printf("[INDEX] [BULK] [UNIFIED] [ERROR] Bulk operation failed: HTTP %d, %s", resp.StatusCode, string(body))
-			return fmt.Errorf("bulk operation failed: HTTP %d, %s", resp.StatusCode, string(body))
+			return newHTTPStatusError(resp.StatusCode, mc.baseURL+"/bulk", "POST", fmt.Sprintf("bulk operation failed: HTTP %d, %s", resp.StatusCode, string(body)))
 		}
 
 		// Parse response to check for individual item errors
@@ -288,10 +329,13 @@ func (mc *manticoreHTTPClient) bulkIndexUnified(documents []*models.Document) er
 		return nil
 	}
 
+	release := mc.acquireBulkSlot()
+	defer release()
+
 	ctx, cancel := context.WithTimeout(context.Background(), mc.bulkConfig.BatchTimeout)
 	defer cancel()
 
-	return mc.circuitBreakerWithRetry.Execute(ctx, mc.baseURL+"/bulk", "POST", operation)
+	return mc.circuitBreakerWithRetry.ExecuteClass(ctx, OperationClassBulk, mc.baseURL+"/bulk", "POST", operation)
 }
 
 // bulkIndexVectors performs bulk indexing for vector documents using NDJSON format
@@ -307,62 +351,67 @@ func (mc *manticoreHTTPClient) bulkIndexVectors(documents []*models.Document, ve
 	operation := func(ctx context.Context) error {
 		requestStartTime := time.Now()
 
-		// Build NDJSON payload for bulk vector operation
-		var ndjsonBuilder strings.Builder
-		for i, doc := range documents {
-			vectorStr := formatVectorAsJSONArray(vectors[i])
-
-			bulkReq := map[string]interface{}{
-				"replace": map[string]interface{}{
-					"index": "documents_vector",
-					"id":    doc.ID,
-					"doc": map[string]interface{}{
-						"title":       doc.Title,
-						"url":         doc.URL,
-						"vector_data": vectorStr,
+		// Stream the NDJSON payload for bulk vector operation as it is encoded
+		req, payload, err := mc.newBulkRequest(ctx, func(encode func(v interface{}) error) error {
+			for i, doc := range documents {
+				vectorStr := formatVectorAsJSONArray(vectors[i])
+
+				bulkReq := map[string]interface{}{
+					"replace": map[string]interface{}{
+						"index": mc.table("documents_vector"),
+						"id":    doc.ID,
+						"doc": map[string]interface{}{
+							"title":       doc.Title,
+							"url":         doc.URL,
+							"status":      documentStatusCode(doc.Status),
+							"indexed_at":  doc.IndexedAt,
+							"updated_at":  doc.UpdatedAt,
+							"tags":        tagsValue(doc.Tags),
+							"vector_data": vectorStr,
+						},
 					},
-				},
-			}
+				}
 
-			jsonBytes, err := json.Marshal(bulkReq)
-			if err != nil {
-				return fmt.Errorf("failed to marshal vector bulk request: %v", err)
+				if err := encode(bulkReq); err != nil {
+					return fmt.Errorf("failed to marshal vector bulk request: %v", err)
+				}
 			}
-			ndjsonBuilder.Write(jsonBytes)
-			ndjsonBuilder.WriteByte('\n')
-		}
-
-		payload := ndjsonBuilder.String()
-		log.Printf("[INDEX] [BULK] [VECTOR] [REQUEST] POST %s/bulk - Documents: %d, Body size: %d bytes", mc.baseURL, len(documents), len(payload))
-		log.Printf("[INDEX] [BULK] [VECTOR] [REQUEST] Sample payload (first 500 chars): %s", truncateString(payload, 500))
-
-		req, err := http.NewRequestWithContext(ctx, "POST", mc.baseURL+"/bulk", strings.NewReader(payload))
+			return nil
+		})
 		if err != nil {
 			return fmt.Errorf("failed to create vector bulk request: %v", err)
 		}
-		req.Header.Set("Content-Type", "application/x-ndjson")
 
 		resp, err := mc.httpClient.Do(req)
 		requestDuration := time.Since(requestStartTime)
+		if err == nil {
+			defer resp.Body.Close()
+		}
+		if payloadErr := payload.wait(); payloadErr != nil {
+			return payloadErr
+		}
+
+		log.Printf("[INDEX] [BULK] [VECTOR] [REQUEST] POST %s/bulk - Documents: %d, Body size: %d bytes, sent: %d bytes", mc.baseURL, len(documents), payload.size, payload.sent)
+		log.Printf("[INDEX] [BULK] [VECTOR] [REQUEST] Sample payload (first 500 chars): %s", payload.sample)
 
 		if err != nil {
 			log.Printf("[INDEX] [BULK] [VECTOR] [ERROR] HTTP request failed after %v: %v", requestDuration, err)
 			return fmt.Errorf("vector bulk request failed: %v", err)
 		}
-		defer resp.Body.Close()
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
+		respBuf := getBuffer()
+		defer putBuffer(respBuf)
+		if err := readLimited(respBuf, resp.Body, mc.maxResponseSize); err != nil {
 			log.Printf("[INDEX] [BULK] [VECTOR] [ERROR] Failed to read response body after %v: %v", requestDuration, err)
 			return fmt.Errorf("failed to read vector bulk response: %v", err)
 		}
+		body := respBuf.Bytes()
 
 		log.Printf("[INDEX] [BULK] [VECTOR] [RESPONSE] HTTP %d - Response size: %d bytes - Duration: %v", resp.StatusCode, len(body), requestDuration)
-		log.Printf("[INDEX] [BULK] [VECTOR] [RESPONSE] Body: %s", string(body))
 
 		if resp.StatusCode >= 400 {
 			log.Printf("[INDEX] [BULK] [VECTOR] [ERROR] Vector bulk operation failed: HTTP %d, %s", resp.StatusCode, string(body))
-			return fmt.Errorf("vector bulk operation failed: HTTP %d, %s", resp.StatusCode, string(body))
+			return newHTTPStatusError(resp.StatusCode, mc.baseURL+"/bulk", "POST", fmt.Sprintf("vector bulk operation failed: HTTP %d, %s", resp.StatusCode, string(body)))
 		}
 
 		// Parse response to check for individual item errors
@@ -387,10 +436,13 @@ func (mc *manticoreHTTPClient) bulkIndexVectors(documents []*models.Document, ve
 		return nil
 	}
 
+	release := mc.acquireBulkSlot()
+	defer release()
+
 	ctx, cancel := context.WithTimeout(context.Background(), mc.bulkConfig.BatchTimeout)
 	defer cancel()
 
-	return mc.circuitBreakerWithRetry.Execute(ctx, mc.baseURL+"/bulk", "POST", operation)
+	return mc.circuitBreakerWithRetry.ExecuteClass(ctx, OperationClassBulk, mc.baseURL+"/bulk", "POST", operation)
 }
 
 // fallbackToIndividualIndexing falls back to individual document indexing when bulk operations fail
@@ -399,6 +451,7 @@ func (mc *manticoreHTTPClient) fallbackToIndividualIndexing(documents []*models.
 
 	var lastError error
 	successCount := 0
+	pace := newPacer(mc.bulkConfig.FallbackDelay, mc.bulkConfig.PacingFactor)
 
 	for i, doc := range documents {
 		var vector []float64
@@ -406,15 +459,17 @@ func (mc *manticoreHTTPClient) fallbackToIndividualIndexing(documents []*models.
 			vector = vectors[i]
 		}
 
-		if err := mc.IndexDocument(doc, vector); err != nil {
+		// Pause between individual operations
+		pace.wait()
+		startTime := time.Now()
+		err := mc.IndexDocument(doc, vector)
+		pace.observe(time.Since(startTime))
+		if err != nil {
 			log.Printf("[INDEX] [FALLBACK] [ERROR] Failed to index document %d individually: %v", doc.ID, err)
 			lastError = err
 		} else {
 			successCount++
 		}
-
-		// Small delay between individual operations
-		time.Sleep(50 * time.Millisecond)
 	}
 
 	log.Printf("[INDEX] [FALLBACK] [FINAL] Individual indexing completed: %d/%d documents successful", successCount, len(documents))
@@ -0,0 +1,130 @@
+package manticore
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Capabilities describes the Manticore server version and the features it supports
+type Capabilities struct {
+	Version        string    `json:"version"`
+	Major          int       `json:"major"`
+	Minor          int       `json:"minor"`
+	Patch          int       `json:"patch"`
+	KNN            bool      `json:"knn"`
+	AutoEmbeddings bool      `json:"auto_embeddings"`
+	Fuzzy          bool      `json:"fuzzy"`
+	DetectedAt     time.Time `json:"detected_at"`
+}
+
+// AtLeast reports whether the server version is greater than or equal to major.minor.patch
+func (c *Capabilities) AtLeast(major, minor, patch int) bool {
+	if c.Major != major {
+		return c.Major > major
+	}
+	if c.Minor != minor {
+		return c.Minor > minor
+	}
+	return c.Patch >= patch
+}
+
+// ErrUnsupported is wrapped by errors for features the Manticore server does not support
+var ErrUnsupported = errors.New("unsupported by the Manticore server")
+
+// RequireAISearch returns an error wrapping ErrUnsupported when the server lacks KNN search or
+// Auto Embeddings, which AI search needs. Unknown capabilities (a nil c) are not rejected.
+func (c *Capabilities) RequireAISearch() error {
+	switch {
+	case c == nil:
+		return nil
+	case !c.KNN:
+		return fmt.Errorf("Manticore %d.%d.%d does not support KNN search (%w)", c.Major, c.Minor, c.Patch, ErrUnsupported)
+	case !c.AutoEmbeddings:
+		return fmt.Errorf("Manticore %d.%d.%d does not support Auto Embeddings (%w)", c.Major, c.Minor, c.Patch, ErrUnsupported)
+	}
+	return nil
+}
+
+var versionPattern = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)`)
+
+// ParseCapabilities derives the feature set from a Manticore version string such as
+// "13.11.0 1aa7d8ac3@25060413 (columnar 5.0.1 ...) (knn 5.0.1 ...) (embeddings 1.0.0)"
+func ParseCapabilities(version string) (*Capabilities, error) {
+	version = strings.TrimSpace(version)
+	match := versionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return nil, fmt.Errorf("unrecognized Manticore version string: %q", version)
+	}
+
+	caps := &Capabilities{
+		Version:    version,
+		DetectedAt: time.Now(),
+	}
+	caps.Major, _ = strconv.Atoi(match[1])
+	caps.Minor, _ = strconv.Atoi(match[2])
+	caps.Patch, _ = strconv.Atoi(match[3])
+
+	lower := strings.ToLower(version)
+
+	// KNN search on float_vector attributes landed in 6.2.0 together with the knn library
+	caps.KNN = strings.Contains(lower, "(knn ") || caps.AtLeast(6, 2, 0)
+
+	// Auto Embeddings (MODEL_NAME/FROM in CREATE TABLE) require 13.11.0 and the embeddings library
+	caps.AutoEmbeddings = caps.KNN && (strings.Contains(lower, "(embeddings ") || caps.AtLeast(13, 11, 0))
+
+	// Fuzzy search options are available since 7.0.0
+	caps.Fuzzy = caps.AtLeast(7, 0, 0)
+
+	return caps, nil
+}
+
+// DetectCapabilities queries the server version and stores the detected capabilities on the client
+func (mc *manticoreHTTPClient) DetectCapabilities() (*Capabilities, error) {
+	log.Printf("[CAPABILITIES] Detecting Manticore server version and features")
+
+	response, err := mc.querySQL("SHOW STATUS LIKE 'version'")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query server version: %v", err)
+	}
+
+	version := ""
+	for _, row := range response.Data {
+		if value, ok := row["Value"].(string); ok {
+			version = value
+			break
+		}
+	}
+	if version == "" {
+		return nil, fmt.Errorf("server version not reported by SHOW STATUS")
+	}
+
+	caps, err := ParseCapabilities(version)
+	if err != nil {
+		return nil, err
+	}
+
+	mc.capabilitiesMutex.Lock()
+	mc.capabilities = caps
+	mc.capabilitiesMutex.Unlock()
+
+	log.Printf("[CAPABILITIES] Manticore %d.%d.%d detected - KNN: %t, Auto Embeddings: %t, Fuzzy: %t",
+		caps.Major, caps.Minor, caps.Patch, caps.KNN, caps.AutoEmbeddings, caps.Fuzzy)
+	return caps, nil
+}
+
+// GetCapabilities returns the detected server capabilities, or nil if detection has not succeeded yet
+func (mc *manticoreHTTPClient) GetCapabilities() *Capabilities {
+	mc.capabilitiesMutex.RLock()
+	defer mc.capabilitiesMutex.RUnlock()
+
+	if mc.capabilities == nil {
+		return nil
+	}
+	caps := *mc.capabilities
+	return &caps
+}
@@ -0,0 +1,161 @@
+package manticore
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	tests := []struct {
+		name               string
+		version            string
+		expectError        bool
+		expectedMajor      int
+		expectedKNN        bool
+		expectedEmbeddings bool
+		expectedFuzzy      bool
+	}{
+		{
+			name:               "current release with embeddings library",
+			version:            "13.11.0 1aa7d8ac3@25060413 (columnar 5.0.1 d2b6a0a@25052117) (secondary 5.0.1 d2b6a0a@25052117) (knn 5.0.1 d2b6a0a@25052117) (embeddings 1.0.0)",
+			expectedMajor:      13,
+			expectedKNN:        true,
+			expectedEmbeddings: true,
+			expectedFuzzy:      true,
+		},
+		{
+			name:          "release before auto embeddings",
+			version:       "9.2.14 4a3b4e2c2@240507",
+			expectedMajor: 9,
+			expectedKNN:   true,
+			expectedFuzzy: true,
+		},
+		{
+			name:          "release before knn",
+			version:       "6.0.4 1a3a4ea82@230314",
+			expectedMajor: 6,
+		},
+		{
+			name:        "unrecognized version",
+			version:     "unknown",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caps, err := ParseCapabilities(tt.version)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if caps.Major != tt.expectedMajor {
+				t.Errorf("Expected major %d, got %d", tt.expectedMajor, caps.Major)
+			}
+			if caps.KNN != tt.expectedKNN {
+				t.Errorf("Expected KNN %t, got %t", tt.expectedKNN, caps.KNN)
+			}
+			if caps.AutoEmbeddings != tt.expectedEmbeddings {
+				t.Errorf("Expected AutoEmbeddings %t, got %t", tt.expectedEmbeddings, caps.AutoEmbeddings)
+			}
+			if caps.Fuzzy != tt.expectedFuzzy {
+				t.Errorf("Expected Fuzzy %t, got %t", tt.expectedFuzzy, caps.Fuzzy)
+			}
+		})
+	}
+}
+
+func TestDetectCapabilities(t *testing.T) {
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sql" {
+			t.Errorf("Expected path /sql, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("mode") != "raw" {
+			t.Errorf("Expected raw mode, got %s", r.URL.RawQuery)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form: %v", err)
+		}
+		if r.PostForm.Get("query") != "SHOW STATUS LIKE 'version'" {
+			t.Errorf("Unexpected query: %s", r.PostForm.Get("query"))
+		}
+
+		w.WriteHeader(200)
+		w.Write([]byte(`[{"columns":[{"Counter":{"type":"string"}},{"Value":{"type":"string"}}],"data":[{"Counter":"version","Value":"6.3.2 37a4a1e45@240506"}],"total":1,"error":"","warning":""}]`))
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL)).(*manticoreHTTPClient)
+
+	if client.GetCapabilities() != nil {
+		t.Fatal("Expected no capabilities before detection")
+	}
+
+	caps, err := client.DetectCapabilities()
+	if err != nil {
+		t.Fatalf("DetectCapabilities failed: %v", err)
+	}
+	if !caps.KNN || caps.AutoEmbeddings {
+		t.Errorf("Expected KNN without Auto Embeddings for 6.3.2, got %+v", caps)
+	}
+
+	stored := client.GetCapabilities()
+	if stored == nil || stored.Version != caps.Version {
+		t.Errorf("Expected detected capabilities to be stored on the client, got %+v", stored)
+	}
+}
+
+func TestRequireAISearch(t *testing.T) {
+	tests := []struct {
+		name     string
+		caps     *Capabilities
+		expected string
+	}{
+		{"unknown", nil, ""},
+		{"no KNN", &Capabilities{Major: 6, Minor: 0}, "does not support KNN search"},
+		{"no Auto Embeddings", &Capabilities{Major: 9, Minor: 2, Patch: 14, KNN: true}, "does not support Auto Embeddings"},
+		{"supported", &Capabilities{Major: 13, Minor: 11, KNN: true, AutoEmbeddings: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.caps.RequireAISearch()
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrUnsupported) || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected an ErrUnsupported error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestAISearchWithoutKNNSendsNoRequest(t *testing.T) {
+	requests := 0
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(200)
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL)).(*manticoreHTTPClient)
+	client.capabilities = &Capabilities{Version: "6.0.4", Major: 6, Patch: 4}
+
+	_, err := client.AISearch("kubernetes", "sentence-transformers/all-MiniLM-L6-v2", 10, 0)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Expected ErrUnsupported, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request to Manticore, got %d", requests)
+	}
+}
@@ -2,11 +2,11 @@ package manticore
 
 import (
 	"fmt"
-	"io"
 	"log"
-	"net"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ad/manticoresearch-go/internal/models"
@@ -17,10 +17,26 @@ type manticoreHTTPClient struct {
 	httpClient              *http.Client
 	baseURL                 string
 	circuitBreakerWithRetry *CircuitBreakerWithRetry
-	isConnected             bool
+	isConnected             atomic.Bool
 	bulkConfig              BulkConfig
 	metricsCollector        *MetricsCollector
 	logger                  *Logger
+	capabilities            *Capabilities
+	capabilitiesMutex       sync.RWMutex
+	mysql                   *mysqlTransport // non-nil when SQL statements go over the MySQL protocol
+	notifier                *CircuitBreakerNotifier
+	timeouts                OperationTimeouts
+	maxResponseSize         int64
+	tablePrefix             string
+	documentsGeneration     atomic.Int64    // generation of the documents table, advanced by ReembedDocuments
+	reembedding             atomic.Bool     // whether ReembedDocuments is copying into the next generation
+	bulkSlots               chan struct{}   // bounds concurrent bulk submissions to MaxConcurrentBatch
+	faults                  *faultTransport // non-nil when faults are injected into requests
+	pool                    *poolTransport
+	recorder                *recordingTransport // non-nil when requests are recorded
+	embeddings              *embeddingCache     // non-nil when query embeddings are cached
+	embeddingTables         sync.Map            // scratch tables created by queryEmbeddingsTable
+	embeddingModels         sync.Map            // Auto Embeddings model of each documents table, see documentsEmbeddingModel
 }
 
 // Ensure manticoreHTTPClient implements ClientInterface
@@ -28,139 +44,157 @@ var _ ClientInterface = (*manticoreHTTPClient)(nil)
 
 // NewHTTPClient creates a new HTTP-based Manticore client
 func NewHTTPClient(config HTTPClientConfig) ClientInterface {
-	// Configure HTTP transport with optimized settings
-	transport := &http.Transport{
-		DialContext: (&net.Dialer{
-			Timeout:   15 * time.Second,
-			KeepAlive: 60 * time.Second,
-		}).DialContext,
-		TLSHandshakeTimeout:   15 * time.Second,
-		ResponseHeaderTimeout: 90 * time.Second, // Increased from 20s to 90s for Auto Embeddings operations
-		ExpectContinueTimeout: 2 * time.Second,
-		MaxIdleConns:          config.MaxIdleConns,
-		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
-		IdleConnTimeout:       config.IdleConnTimeout,
-		DisableCompression:    false,
-		ForceAttemptHTTP2:     false, // Disable HTTP/2 for better compatibility
-		WriteBufferSize:       32768, // 32KB write buffer
-		ReadBufferSize:        32768, // 32KB read buffer
-	}
+	transport := newPoolTransport(config)
 
 	httpClient := &http.Client{
 		Timeout:   config.Timeout,
 		Transport: transport,
 	}
+	var faults *faultTransport
+	if config.Faults.Enabled() {
+		faults = newFaultTransport(transport, config.Faults)
+		httpClient.Transport = faults
+		log.Printf("[FAULT] [WARNING] Fault injection is enabled for %s: %s", config.BaseURL, config.Faults)
+	}
+	var recorder *recordingTransport
+	if config.Recording.Enabled() {
+		recorder = newRecordingTransport(httpClient.Transport, config.Recording)
+		httpClient.Transport = recorder
+		log.Printf("[RECORDING] [WARNING] Recording a fraction %g of the requests to %s with their bodies, in a buffer of the last %d exchanges",
+			recorder.config.SampleRate, config.BaseURL, recorder.config.Capacity)
+	}
+
+	retryConfig := RetryConfig{
+		MaxAttempts:                  config.RetryConfig.MaxAttempts,
+		BaseDelay:                    config.RetryConfig.BaseDelay,
+		MaxDelay:                     config.RetryConfig.MaxDelay,
+		JitterPercent:                config.RetryConfig.JitterPercent,
+		RetryableErrorTypes:          config.RetryConfig.RetryableErrorTypes,
+		TimeoutMultiplier:            2.0,
+		ConnectionMultiplier:         3.0,
+		ServiceUnavailableMultiplier: 4.0,
+		RateLimitMultiplier:          5.0,
+		PerAttemptTimeout:            30 * time.Second,
+		TotalTimeout:                 5 * time.Minute,
+		Clock:                        config.RetryConfig.Clock,
+		Random:                       config.RetryConfig.Random,
+	}
 
 	// Create enhanced circuit breaker with retry integration
-	circuitBreakerWithRetry := NewCircuitBreakerWithRetry(
-		CircuitBreakerConfig{
-			FailureThreshold:     config.CircuitBreakerConfig.FailureThreshold,
-			RecoveryTimeout:      config.CircuitBreakerConfig.RecoveryTimeout,
-			HalfOpenMaxCalls:     config.CircuitBreakerConfig.HalfOpenMaxCalls,
-			SuccessThreshold:     2,
-			MinRequestThreshold:  5,
-			FailureRateThreshold: 0.5,
-			SlidingWindowSize:    20,
-			MonitoringInterval:   5 * time.Second,
-		},
-		RetryConfig{
-			MaxAttempts:                  config.RetryConfig.MaxAttempts,
-			BaseDelay:                    config.RetryConfig.BaseDelay,
-			MaxDelay:                     config.RetryConfig.MaxDelay,
-			JitterPercent:                config.RetryConfig.JitterPercent,
-			TimeoutMultiplier:            2.0,
-			ConnectionMultiplier:         3.0,
-			ServiceUnavailableMultiplier: 4.0,
-			RateLimitMultiplier:          5.0,
-			PerAttemptTimeout:            30 * time.Second,
-			TotalTimeout:                 5 * time.Minute,
-		},
-	)
+	circuitBreakerConfig := CircuitBreakerConfig{
+		FailureThreshold:     config.CircuitBreakerConfig.FailureThreshold,
+		RecoveryTimeout:      config.CircuitBreakerConfig.RecoveryTimeout,
+		HalfOpenMaxCalls:     config.CircuitBreakerConfig.HalfOpenMaxCalls,
+		SuccessThreshold:     2,
+		MinRequestThreshold:  5,
+		FailureRateThreshold: 0.5,
+		SlidingWindowSize:    20,
+		MonitoringInterval:   5 * time.Second,
+		Clock:                config.CircuitBreakerConfig.Clock,
+	}
+	circuitBreakerWithRetry := NewCircuitBreakerWithRetry(circuitBreakerConfig, retryConfig)
+	for class, policy := range resolveRetryPolicies(retryConfig, config) {
+		circuitBreakerWithRetry.SetRetryPolicy(class, policy)
+	}
+	// A slow or failing embedding model must not open the breaker of plain searches
+	circuitBreakerWithRetry.SetCircuitBreaker(OperationClassEmbedding, circuitBreakerConfig)
 
 	// Initialize monitoring components
 	metricsCollector := NewMetricsCollector()
 	logger := NewLogger(LogLevelInfo)
 
-	// Set up circuit breaker callback for monitoring
-	callback := NewMetricsCircuitBreakerCallback(metricsCollector, logger)
-	circuitBreakerWithRetry.SetCallback(callback)
+	// Set up circuit breaker callbacks for monitoring and external notifications
+	baseURL := strings.TrimSuffix(config.BaseURL, "/")
+	notifier := NewCircuitBreakerNotifier(baseURL, config.CircuitBreakerWebhook, circuitBreakerWithRetry.GetCircuitBreakerStats)
+	circuitBreakerWithRetry.SetCallback(MultiCircuitBreakerCallback{
+		NewMetricsCircuitBreakerCallback(metricsCollector, logger),
+		notifier,
+	})
 
-	return &manticoreHTTPClient{
+	client := &manticoreHTTPClient{
 		httpClient:              httpClient,
-		baseURL:                 strings.TrimSuffix(config.BaseURL, "/"),
+		baseURL:                 baseURL,
 		circuitBreakerWithRetry: circuitBreakerWithRetry,
-		isConnected:             false,
 		bulkConfig:              config.BulkConfig,
 		metricsCollector:        metricsCollector,
 		logger:                  logger,
+		notifier:                notifier,
+		timeouts:                config.Timeouts.withDefaults(),
+		maxResponseSize:         config.MaxResponseSize,
+		tablePrefix:             config.TablePrefix,
+		faults:                  faults,
+		pool:                    transport,
+		recorder:                recorder,
+		embeddings:              newEmbeddingCache(config.EmbeddingCache),
 	}
-}
-
-// Connection management methods
-
-// WaitForReady waits for Manticore to be ready with timeout and comprehensive logging
-func (mc *manticoreHTTPClient) WaitForReady(timeout time.Duration) error {
-	startTime := time.Now()
-	deadline := startTime.Add(timeout)
-	log.Printf("Waiting for Manticore HTTP client to be ready (timeout: %v)", timeout)
+	if client.maxResponseSize <= 0 {
+		client.maxResponseSize = DefaultMaxResponseSize
+	}
+	client.bulkSlots = make(chan struct{}, max(config.BulkConfig.MaxConcurrentBatch, 1))
 
-	attempt := 0
-	for time.Now().Before(deadline) {
-		attempt++
-		log.Printf("Health check attempt %d", attempt)
+	// SQL statements can be routed over the MySQL protocol while search stays on the JSON API
+	if config.SQLTransport == SQLTransportMySQL && config.MySQLAddr != "" {
+		client.mysql = newMySQLTransport(config.MySQLAddr, 15*time.Second)
+		log.Printf("Using MySQL protocol transport for SQL operations at %s", config.MySQLAddr)
+	}
 
-		if err := mc.HealthCheck(); err == nil {
-			totalDuration := time.Since(startTime)
-			log.Printf("Manticore HTTP client is ready after %v (%d attempts)", totalDuration, attempt)
-			mc.isConnected = true
-			return nil
-		}
+	return client
+}
 
-		// Wait before next attempt
-		time.Sleep(2 * time.Second)
+// table returns the Manticore name of a table, with the tenant's prefix. The documents table is
+// the one of the current generation, see ReembedDocuments.
+func (mc *manticoreHTTPClient) table(name string) string {
+	if name == "documents" {
+		name = documentsTableName(mc.documentsGeneration.Load())
 	}
+	return mc.tablePrefix + name
+}
 
-	totalDuration := time.Since(startTime)
-	log.Printf("Timeout waiting for Manticore HTTP client to be ready after %v (%d attempts)", totalDuration, attempt)
-	return fmt.Errorf("timeout waiting for Manticore to be ready after %v", totalDuration)
+// tables returns the Manticore names of tables
+func (mc *manticoreHTTPClient) tables(names []string) []string {
+	prefixed := make([]string, len(names))
+	for i, name := range names {
+		prefixed[i] = mc.table(name)
+	}
+	return prefixed
 }
 
-// HealthCheck verifies that the Manticore connection is healthy
-func (mc *manticoreHTTPClient) HealthCheck() error {
-	// log.Printf("Performing health check on %s", mc.baseURL)
+// Connection management methods
 
-	// Use a simple GET request to check if Manticore is responding
-	// This avoids creating unnecessary tables
-	req, err := http.NewRequest("GET", mc.baseURL, nil)
-	if err != nil {
-		log.Printf("Health check failed: could not create HTTP request: %v", err)
-		return fmt.Errorf("health check failed: %v", err)
+// GetCircuitBreakerTransitions returns recent circuit breaker state changes, newest first
+func (mc *manticoreHTTPClient) GetCircuitBreakerTransitions() []CircuitBreakerTransition {
+	if mc.notifier == nil {
+		return nil
 	}
+	return mc.notifier.RecentTransitions()
+}
 
-	// Use a shorter timeout for health checks
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Printf("Health check failed: HTTP request failed: %v", err)
-		return fmt.Errorf("health check failed: %v", err)
+// GetResilienceStats returns circuit breaker and retry statistics
+func (mc *manticoreHTTPClient) GetResilienceStats() ResilienceStats {
+	return ResilienceStats{
+		CircuitBreaker:        mc.circuitBreakerWithRetry.GetCircuitBreakerStats(),
+		CircuitBreakerByClass: mc.circuitBreakerWithRetry.GetCircuitBreakerStatsByClass(),
+		Retry:                 mc.circuitBreakerWithRetry.GetRetryStats(),
+		RetryByClass:          mc.circuitBreakerWithRetry.GetRetryStatsByClass(),
+		Transitions:           mc.GetCircuitBreakerTransitions(),
+		FaultInjection:        mc.faults.stats(),
+		ConnectionPool:        mc.pool.stats(),
 	}
-	defer resp.Body.Close()
+}
 
-	// Accept any response that indicates Manticore is running
-	// Even 404 or 400 responses mean the server is up and responding
-	if resp.StatusCode >= 500 {
-		body, _ := io.ReadAll(resp.Body)
-		log.Printf("Health check failed: HTTP %d, %s", resp.StatusCode, string(body))
-		return fmt.Errorf("health check failed: HTTP %d", resp.StatusCode)
-	}
+// GetRecordings returns the requests and responses recorded for debugging
+func (mc *manticoreHTTPClient) GetRecordings() Recordings {
+	return mc.recorder.recordings()
+}
 
-	// log.Printf("Health check passed: HTTP %d", resp.StatusCode)
-	return nil
+// GetOperationTimeouts returns the configured per-operation timeouts
+func (mc *manticoreHTTPClient) GetOperationTimeouts() OperationTimeouts {
+	return mc.timeouts
 }
 
 // IsConnected returns the connection status
 func (mc *manticoreHTTPClient) IsConnected() bool {
-	return mc.isConnected
+	return mc.isConnected.Load()
 }
 
 // Close performs graceful shutdown of the HTTP client
@@ -172,12 +206,18 @@ func (mc *manticoreHTTPClient) Close() error {
 		mc.circuitBreakerWithRetry.Close()
 	}
 
-	// Close idle connections
-	if transport, ok := mc.httpClient.Transport.(*http.Transport); ok {
-		transport.CloseIdleConnections()
+	if mc.mysql != nil {
+		mc.mysql.Close()
+	}
+
+	if mc.notifier != nil {
+		mc.notifier.Close()
 	}
 
-	mc.isConnected = false
+	// Stop re-resolving the Manticore host and close idle connections
+	mc.pool.close()
+
+	mc.isConnected.Store(false)
 
 	// Log final metrics before closing
 	if mc.metricsCollector != nil {
@@ -202,9 +242,17 @@ func (mc *manticoreHTTPClient) LogMetrics() {
 	}
 }
 
-// Search performs search using the HTTP client (adapter method for ClientInterface)
+// Search runs a basic or full-text search through the same SearchAdapter the search engine uses.
+// Vector, hybrid and AI searches need the vectorizer and AI configuration held by search.SearchEngine.
 func (mc *manticoreHTTPClient) Search(query string, mode models.SearchMode, page, pageSize int) (*models.SearchResponse, error) {
-	// This method is implemented as an adapter to maintain compatibility
-	// The actual search logic should be handled by the search engine
-	return nil, fmt.Errorf("search method not implemented for HTTP client - use search engine instead")
+	adapter := NewSearchAdapter(mc)
+
+	switch mode {
+	case models.SearchModeBasic:
+		return adapter.BasicSearch(query, page, pageSize)
+	case models.SearchModeFullText:
+		return adapter.FullTextSearch(query, page, pageSize)
+	default:
+		return nil, fmt.Errorf("%s search is not supported by the client, use the search engine instead", mode)
+	}
 }
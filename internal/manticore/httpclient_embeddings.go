@@ -0,0 +1,75 @@
+package manticore
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EmbeddingModel describes a float_vector column that Manticore fills with Auto Embeddings
+type EmbeddingModel struct {
+	Table      string `json:"table"`
+	Column     string `json:"column"`
+	Model      string `json:"model"`
+	Dimension  int    `json:"dimension,omitempty"` // 0 when the server doesn't report knn_dims
+	Source     string `json:"source,omitempty"`    // Fields the embedding is computed from
+	Similarity string `json:"similarity,omitempty"`
+}
+
+var (
+	vectorColumnPattern = regexp.MustCompile(`(?i)^\s*` + "`?" + `(\w+)` + "`?" + `\s+float_vector\b(.*)$`)
+	columnOptionPattern = regexp.MustCompile(`(?i)(\w+)\s*=\s*'([^']*)'`)
+)
+
+// EmbeddingModels returns the Auto Embeddings columns of a table from SHOW CREATE TABLE, which
+// tells the model Manticore embeds queries with. A missing table has none.
+func (mc *manticoreHTTPClient) EmbeddingModels(table string) ([]EmbeddingModel, error) {
+	if !IsDataTable(table) {
+		return nil, fmt.Errorf("unknown table %q", table)
+	}
+
+	response, err := mc.querySQL("SHOW CREATE TABLE " + mc.table(table))
+	if err != nil {
+		if isUnknownTableError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get schema of %s: %v", table, err)
+	}
+
+	var models []EmbeddingModel
+	for _, row := range response.Data {
+		statement, _ := row["Create Table"].(string)
+		models = append(models, parseEmbeddingModels(table, statement)...)
+	}
+	return models, nil
+}
+
+// parseEmbeddingModels finds the float_vector columns with a model_name in a CREATE TABLE statement
+func parseEmbeddingModels(table, statement string) []EmbeddingModel {
+	var models []EmbeddingModel
+	for _, line := range strings.Split(statement, "\n") {
+		match := vectorColumnPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		model := EmbeddingModel{Table: table, Column: match[1]}
+		for _, option := range columnOptionPattern.FindAllStringSubmatch(match[2], -1) {
+			switch strings.ToLower(option[1]) {
+			case "model_name":
+				model.Model = option[2]
+			case "knn_dims":
+				model.Dimension, _ = strconv.Atoi(option[2])
+			case "from":
+				model.Source = option[2]
+			case "hnsw_similarity":
+				model.Similarity = strings.ToLower(option[2])
+			}
+		}
+		// Vectors indexed by the client have no model
+		if model.Model != "" {
+			models = append(models, model)
+		}
+	}
+	return models
+}
@@ -0,0 +1,64 @@
+package manticore
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseEmbeddingModels(t *testing.T) {
+	statement := "CREATE TABLE documents (\n" +
+		"id bigint,\n" +
+		"title text,\n" +
+		"content_vector float_vector knn_type='hnsw' knn_dims='384' hnsw_similarity='COSINE' model_name='sentence-transformers/all-MiniLM-L6-v2' from='content',\n" +
+		"client_vector float_vector knn_type='hnsw' knn_dims='128' hnsw_similarity='L2'\n" +
+		") engine='columnar'"
+
+	expected := []EmbeddingModel{{
+		Table:      "documents",
+		Column:     "content_vector",
+		Model:      "sentence-transformers/all-MiniLM-L6-v2",
+		Dimension:  384,
+		Source:     "content",
+		Similarity: "cosine",
+	}}
+	if models := parseEmbeddingModels("documents", statement); !reflect.DeepEqual(models, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, models)
+	}
+}
+
+func TestEmbeddingModels(t *testing.T) {
+	missing := false
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if query := r.PostForm.Get("query"); query != "SHOW CREATE TABLE documents" {
+			t.Errorf("Unexpected query: %s", query)
+		}
+		w.WriteHeader(200)
+		if missing {
+			w.Write([]byte(`[{"error":"unknown local table(s) 'documents' in search request"}]`))
+			return
+		}
+		w.Write([]byte(`[{"columns":[{"Table":{"type":"string"}},{"Create Table":{"type":"string"}}],"data":[{"Table":"documents","Create Table":"CREATE TABLE documents (\nid bigint,\ncontent_vector float_vector knn_type='hnsw' knn_dims='384' hnsw_similarity='COSINE' model_name='sentence-transformers/all-MiniLM-L6-v2' from='content'\n)"}],"total":1,"error":""}]`))
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL)).(*manticoreHTTPClient)
+
+	models, err := client.EmbeddingModels("documents")
+	if err != nil {
+		t.Fatalf("EmbeddingModels failed: %v", err)
+	}
+	if len(models) != 1 || models[0].Model != "sentence-transformers/all-MiniLM-L6-v2" || models[0].Dimension != 384 {
+		t.Errorf("Unexpected models: %+v", models)
+	}
+
+	missing = true
+	if models, err := client.EmbeddingModels("documents"); err != nil || len(models) != 0 {
+		t.Errorf("Expected no models for a missing table, got %+v, %v", models, err)
+	}
+
+	if _, err := client.EmbeddingModels("schema_meta"); err == nil {
+		t.Error("Expected an error for a table that is not a data table")
+	}
+}
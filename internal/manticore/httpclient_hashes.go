@@ -0,0 +1,36 @@
+package manticore
+
+import (
+	"fmt"
+)
+
+// GetDocumentHashes returns the content hash of every document in the documents table by id, see
+// models.Document.ContentHash. The hash is that of the document as last indexed; changing its
+// status or tags at runtime keeps it. Documents indexed before the content_hash attribute existed
+// have an empty hash, and a missing table has no documents.
+func (mc *manticoreHTTPClient) GetDocumentHashes() (map[int64]string, error) {
+	hashes := make(map[int64]string)
+	var after int64
+	for {
+		response, err := mc.querySQL(fmt.Sprintf("SELECT id, content_hash FROM %s WHERE id > %d ORDER BY id ASC LIMIT %d", mc.table("documents"), after, idPageSize))
+		if err != nil {
+			if isUnknownTableError(err) {
+				return hashes, nil
+			}
+			return nil, fmt.Errorf("failed to list document hashes: %v", err)
+		}
+
+		for _, row := range response.Data {
+			id, err := parseSQLInt(row["id"])
+			if err != nil {
+				return nil, fmt.Errorf("invalid id in documents: %v", err)
+			}
+			hash, _ := row["content_hash"].(string)
+			hashes[id] = hash
+			after = id
+		}
+		if len(response.Data) < idPageSize {
+			return hashes, nil
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package manticore
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestGetDocumentHashes(t *testing.T) {
+	var queries []string
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		query := r.PostForm.Get("query")
+		queries = append(queries, query)
+
+		var after, limit int64
+		fmt.Sscanf(query, "SELECT id, content_hash FROM documents WHERE id > %d ORDER BY id ASC LIMIT %d", &after, &limit)
+
+		// A full first page, then a row indexed before content_hash existed
+		rows := []string{}
+		if after == 0 {
+			for id := int64(1); id <= limit; id++ {
+				rows = append(rows, fmt.Sprintf(`{"id":%d,"content_hash":"hash-%d"}`, id, id))
+			}
+		} else if after == limit {
+			rows = append(rows, `{"id":"5000","content_hash":""}`)
+		}
+		w.Write([]byte(`[{"columns":[{"id":{"type":"long long"}},{"content_hash":{"type":"string"}}],"data":[` + strings.Join(rows, ",") + `],"total":1,"error":"","warning":""}]`))
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+
+	hashes, err := client.GetDocumentHashes()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(hashes) != idPageSize+1 || hashes[1] != "hash-1" || hashes[idPageSize] != fmt.Sprintf("hash-%d", idPageSize) {
+		t.Errorf("Expected %d hashes, got %d", idPageSize+1, len(hashes))
+	}
+	if hash, ok := hashes[5000]; !ok || hash != "" {
+		t.Errorf("Expected an empty hash for document 5000, got %q, %v", hash, ok)
+	}
+	if len(queries) != 2 {
+		t.Errorf("Unexpected queries: %q", queries)
+	}
+}
+
+func TestGetDocumentHashesMissingTable(t *testing.T) {
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"total":0,"error":"unknown local table(s) 'documents' in search request","warning":""}]`))
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+	hashes, err := client.GetDocumentHashes()
+	if err != nil || len(hashes) != 0 {
+		t.Errorf("Expected a missing table to have no hashes, got %v, %v", hashes, err)
+	}
+}
+
+func TestBulkIndexStoresContentHash(t *testing.T) {
+	var lines []map[string]interface{}
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		lines = bulkLines(t, r)
+		w.Write([]byte(`{"items":[],"errors":false}`))
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL)).(*manticoreHTTPClient)
+	doc := &models.Document{ID: 1, Title: "One", URL: "one.md", Content: "first", Tags: []string{"go"}}
+	if err := client.bulkIndexUnified([]*models.Document{doc}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	stored := lines[0]["replace"].(map[string]interface{})["doc"].(map[string]interface{})["content_hash"]
+	if stored != doc.ContentHash() {
+		t.Errorf("Expected content hash %q, got %v", doc.ContentHash(), stored)
+	}
+}
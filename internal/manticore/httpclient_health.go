@@ -0,0 +1,94 @@
+package manticore
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// healthCheckTimeout bounds each health probe
+const healthCheckTimeout = 5 * time.Second
+
+// requiredTables are the tables that have to exist before documents can be searched
+var requiredTables = []string{"documents", "documents_vector"}
+
+// HealthState summarizes the result of CheckHealth
+type HealthState string
+
+const (
+	HealthStateDown          HealthState = "down"           // Manticore is not answering
+	HealthStateUp            HealthState = "up"             // Manticore answers but the schema could not be checked
+	HealthStateSchemaMissing HealthState = "schema_missing" // Manticore answers but required tables are missing
+	HealthStateReady         HealthState = "ready"          // Manticore answers and every required table exists
+)
+
+// HealthReport separates server availability from schema presence
+type HealthReport struct {
+	State         HealthState `json:"state"`
+	ServerUp      bool        `json:"server_up"`
+	SchemaPresent bool        `json:"schema_present"`
+	MissingTables []string    `json:"missing_tables,omitempty"`
+	Error         string      `json:"error,omitempty"`
+}
+
+// HealthCheck verifies that Manticore answers SQL statements. It does not check the schema,
+// so it succeeds before the tables are created; use CheckHealth for that.
+func (mc *manticoreHTTPClient) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	if _, err := mc.runSQLQuery(ctx, "SHOW STATUS"); err != nil {
+		log.Printf("Health check failed: %v", err)
+		return fmt.Errorf("health check failed: %v", err)
+	}
+	return nil
+}
+
+// CheckHealth reports whether Manticore is up and whether the required tables exist
+func (mc *manticoreHTTPClient) CheckHealth() HealthReport {
+	if err := mc.HealthCheck(); err != nil {
+		return HealthReport{State: HealthStateDown, Error: err.Error()}
+	}
+	report := HealthReport{State: HealthStateUp, ServerUp: true}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	response, err := mc.runSQLQuery(ctx, "SHOW TABLES")
+	if err != nil {
+		log.Printf("Health check: failed to list tables: %v", err)
+		report.Error = fmt.Sprintf("failed to list tables: %v", err)
+		return report
+	}
+
+	report.MissingTables = missingTables(response, mc.tables(requiredTables))
+	report.SchemaPresent = len(report.MissingTables) == 0
+	if report.SchemaPresent {
+		report.State = HealthStateReady
+	} else {
+		report.State = HealthStateSchemaMissing
+	}
+	return report
+}
+
+// missingTables returns the required tables absent from a SHOW TABLES result
+func missingTables(response *SQLResponse, required []string) []string {
+	existing := make(map[string]bool, len(response.Data))
+	for _, row := range response.Data {
+		// Older Manticore versions name the column Index instead of Table
+		for _, column := range []string{"Table", "Index"} {
+			if name, ok := row[column].(string); ok {
+				existing[name] = true
+			}
+		}
+	}
+
+	var missing []string
+	for _, table := range required {
+		if !existing[table] {
+			missing = append(missing, table)
+		}
+	}
+	return missing
+}
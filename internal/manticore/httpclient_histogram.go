@@ -0,0 +1,123 @@
+package manticore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// MaxHistogramBuckets is the number of highest buckets a histogram facet keeps, so small intervals
+// over long ranges can't produce unbounded responses
+const MaxHistogramBuckets = 100
+
+// HistogramFields are the numeric attributes histogram facets may count, all Unix timestamps in
+// seconds
+var HistogramFields = []string{"updated_at", "indexed_at"}
+
+// HistogramFacet counts matching documents in buckets of Interval over a numeric attribute. Its
+// Facets key is the attribute name and the value of each bucket its lower bound.
+type HistogramFacet struct {
+	Field    string
+	Interval int64
+}
+
+// HistogramFacets lists the histogram facets of a search
+type HistogramFacets []HistogramFacet
+
+// Apply asks Manticore to compute the histograms of the matching documents
+func (h HistogramFacets) Apply(request SearchRequest) SearchRequest {
+	if len(h) == 0 {
+		return request
+	}
+	aggs := make(map[string]interface{}, len(request.Aggs)+len(h))
+	for name, agg := range request.Aggs {
+		aggs[name] = agg
+	}
+	for _, facet := range h {
+		aggs[facet.Field] = map[string]interface{}{
+			"histogram": map[string]interface{}{"field": facet.Field, "interval": facet.Interval},
+		}
+	}
+	request.Aggs = aggs
+	return request
+}
+
+// Facets counts the histograms of documents ranked by the service rather than by Manticore, nil
+// when there are none
+func (h HistogramFacets) Facets(documents []*models.Document) map[string][]models.FacetValue {
+	if len(h) == 0 {
+		return nil
+	}
+	facets := make(map[string][]models.FacetValue, len(h))
+	for _, facet := range h {
+		counts := make(map[int64]int)
+		for _, doc := range documents {
+			value, ok := facet.value(doc)
+			if !ok {
+				continue
+			}
+			bucket := value - value%facet.Interval
+			if value < 0 && value%facet.Interval != 0 {
+				bucket -= facet.Interval
+			}
+			counts[bucket]++
+		}
+
+		keys := make([]int64, 0, len(counts))
+		for key := range counts {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		values := make([]models.FacetValue, 0, len(keys))
+		for _, key := range keys {
+			values = append(values, models.FacetValue{Value: strconv.FormatInt(key, 10), Count: counts[key]})
+		}
+		facets[facet.Field] = limitHistogram(values)
+	}
+	return facets
+}
+
+// value returns the attribute of doc the facet counts
+func (f HistogramFacet) value(doc *models.Document) (int64, bool) {
+	switch f.Field {
+	case "updated_at":
+		return doc.UpdatedAt, true
+	case "indexed_at":
+		return doc.IndexedAt, true
+	}
+	return 0, false
+}
+
+// facetValueKey formats the key of an aggregation bucket. Histogram keys are decoded as float64, so
+// whole numbers are written without an exponent.
+func facetValueKey(key interface{}) string {
+	if number, ok := key.(float64); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return fmt.Sprint(key)
+}
+
+// limitHistogram keeps the MaxHistogramBuckets highest buckets of a histogram sorted by value
+func limitHistogram(values []models.FacetValue) []models.FacetValue {
+	if len(values) > MaxHistogramBuckets {
+		return values[len(values)-MaxHistogramBuckets:]
+	}
+	return values
+}
+
+// histogramFacetsKey is the context key for WithHistogramFacets
+type histogramFacetsKey struct{}
+
+// WithHistogramFacets returns a context whose AI searches compute facets, see WithSearchStatuses
+func WithHistogramFacets(ctx context.Context, facets HistogramFacets) context.Context {
+	return context.WithValue(ctx, histogramFacetsKey{}, facets)
+}
+
+// histogramFacets returns the facets set with WithHistogramFacets
+func histogramFacets(ctx context.Context) HistogramFacets {
+	facets, _ := ctx.Value(histogramFacetsKey{}).(HistogramFacets)
+	return facets
+}
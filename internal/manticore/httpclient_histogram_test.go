@@ -0,0 +1,71 @@
+package manticore
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestHistogramFacetsApply(t *testing.T) {
+	facets := HistogramFacets{{Field: "updated_at", Interval: 86400}}
+	request := facets.Apply(WithTagFacet(NewBasicSearchRequest("documents", "form", 10, 0)))
+
+	expected := map[string]interface{}{"histogram": map[string]interface{}{"field": "updated_at", "interval": int64(86400)}}
+	if !reflect.DeepEqual(request.Aggs["updated_at"], expected) {
+		t.Errorf("Unexpected histogram aggregation: %v", request.Aggs["updated_at"])
+	}
+	if _, ok := request.Aggs[models.FacetTags]; !ok {
+		t.Errorf("Expected the tag facet to be kept, got %v", request.Aggs)
+	}
+
+	if request := (HistogramFacets{}).Apply(NewBasicSearchRequest("documents", "form", 10, 0)); request.Aggs != nil {
+		t.Errorf("Expected no aggregations without facets, got %v", request.Aggs)
+	}
+}
+
+func TestHistogramFacetsFromResponse(t *testing.T) {
+	payload := `{"took":1,"timed_out":false,"hits":{"total":3,"hits":[]},` +
+		`"aggregations":{"updated_at":{"buckets":[{"key":1700000000,"doc_count":2},{"key":1700086400,"doc_count":0},{"key":1700172800,"doc_count":1}]}}}`
+
+	response, err := decodeSearchResponse(strings.NewReader(payload), 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string][]models.FacetValue{
+		"updated_at": {{Value: "1700000000", Count: 2}, {Value: "1700172800", Count: 1}},
+	}
+	if facets := FacetsFromResponse(response); !reflect.DeepEqual(facets, expected) {
+		t.Errorf("Unexpected facets: %v", facets)
+	}
+}
+
+func TestHistogramFacetsCount(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, UpdatedAt: 86400*2 + 5, IndexedAt: 10},
+		{ID: 2, UpdatedAt: 86400*2 + 100},
+		{ID: 3, UpdatedAt: 50},
+	}
+
+	facets := HistogramFacets{{Field: "updated_at", Interval: 86400}}.Facets(documents)
+	expected := map[string][]models.FacetValue{
+		"updated_at": {{Value: "0", Count: 1}, {Value: "172800", Count: 2}},
+	}
+	if !reflect.DeepEqual(facets, expected) {
+		t.Errorf("Unexpected facets: %v", facets)
+	}
+	if facets := (HistogramFacets{}).Facets(documents); facets != nil {
+		t.Errorf("Expected no facets, got %v", facets)
+	}
+
+	many := make([]*models.Document, MaxHistogramBuckets+5)
+	for i := range many {
+		many[i] = &models.Document{ID: i + 1, UpdatedAt: int64(i) * 3600}
+	}
+	values := HistogramFacets{{Field: "updated_at", Interval: 3600}}.Facets(many)["updated_at"]
+	if len(values) != MaxHistogramBuckets || values[0].Value != "18000" {
+		t.Errorf("Expected the %d highest buckets, got %d starting at %v", MaxHistogramBuckets, len(values), values[0])
+	}
+}
@@ -0,0 +1,94 @@
+package manticore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// IDFilter restricts searches to a set of documents and hides others
+type IDFilter struct {
+	IDs     []int64 // Only match these documents, empty matches every document
+	Exclude []int64 // Never match these documents
+}
+
+// IsZero reports whether the filter matches every document
+func (f IDFilter) IsZero() bool {
+	return len(f.IDs) == 0 && len(f.Exclude) == 0
+}
+
+// Matches reports whether a document passes the filter
+func (f IDFilter) Matches(doc *models.Document) bool {
+	id := int64(doc.ID)
+	for _, excluded := range f.Exclude {
+		if id == excluded {
+			return false
+		}
+	}
+	if len(f.IDs) == 0 {
+		return true
+	}
+	for _, included := range f.IDs {
+		if id == included {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply adds the filter to a search request (see addFilter), with an in clause for IDs and a
+// negated one for Exclude
+func (f IDFilter) Apply(request SearchRequest) SearchRequest {
+	if request.Query == nil {
+		return request
+	}
+	if len(f.IDs) > 0 {
+		request = addFilter(request, map[string]interface{}{"in": map[string]interface{}{"id": f.IDs}})
+	}
+	if len(f.Exclude) > 0 {
+		request = addFilter(request, map[string]interface{}{"bool": map[string]interface{}{
+			"must_not": []map[string]interface{}{{"in": map[string]interface{}{"id": f.Exclude}}},
+		}})
+	}
+	return request
+}
+
+// sqlCondition returns the WHERE condition for Count
+func (f IDFilter) sqlCondition(field string) string {
+	var conditions []string
+	if len(f.IDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("%s IN (%s)", field, joinIDs(f.IDs)))
+	}
+	if len(f.Exclude) > 0 {
+		conditions = append(conditions, fmt.Sprintf("%s NOT IN (%s)", field, joinIDs(f.Exclude)))
+	}
+	if len(conditions) == 0 {
+		return "1 = 1"
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+func joinIDs(ids []int64) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(values, ", ")
+}
+
+// idFilterKey is the context key for WithIDFilter
+type idFilterKey struct{}
+
+// WithIDFilter returns a context whose AI searches apply filter, see WithSearchStatuses
+func WithIDFilter(ctx context.Context, filter IDFilter) context.Context {
+	return context.WithValue(ctx, idFilterKey{}, filter)
+}
+
+// idFilter returns the filter set with WithIDFilter
+func idFilter(ctx context.Context) IDFilter {
+	filter, _ := ctx.Value(idFilterKey{}).(IDFilter)
+	return filter
+}
@@ -0,0 +1,68 @@
+package manticore
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestIDFilterMatches(t *testing.T) {
+	doc := &models.Document{ID: 2}
+
+	tests := []struct {
+		filter   IDFilter
+		expected bool
+	}{
+		{IDFilter{}, true},
+		{IDFilter{IDs: []int64{1, 2}}, true},
+		{IDFilter{IDs: []int64{1}}, false},
+		{IDFilter{Exclude: []int64{2}}, false},
+		{IDFilter{IDs: []int64{2}, Exclude: []int64{2}}, false},
+	}
+	for _, tt := range tests {
+		if matches := tt.filter.Matches(doc); matches != tt.expected {
+			t.Errorf("%+v.Matches() = %v, expected %v", tt.filter, matches, tt.expected)
+		}
+	}
+}
+
+func TestIDFilterApply(t *testing.T) {
+	match := map[string]interface{}{"match": map[string]interface{}{"*": "form"}}
+
+	request := IDFilter{IDs: []int64{1, 2}, Exclude: []int64{3}}.Apply(NewBasicSearchRequest("documents", "form", 10, 0))
+	expected := map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must": []map[string]interface{}{
+				{
+					"bool": map[string]interface{}{
+						"must": []map[string]interface{}{
+							match,
+							{"in": map[string]interface{}{"id": []int64{1, 2}}},
+						},
+					},
+				},
+				{"bool": map[string]interface{}{
+					"must_not": []map[string]interface{}{{"in": map[string]interface{}{"id": []int64{3}}}},
+				}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(request.Query, expected) {
+		t.Errorf("Unexpected query: %v", request.Query)
+	}
+
+	if request := (IDFilter{}).Apply(NewBasicSearchRequest("documents", "form", 10, 0)); !reflect.DeepEqual(request.Query, match) {
+		t.Errorf("Expected an empty filter to leave the query unchanged, got %v", request.Query)
+	}
+}
+
+func TestBuildCountQueryIDs(t *testing.T) {
+	query, err := buildCountQuery("documents", "", map[string]interface{}{"id": IDFilter{IDs: []int64{1, 2}, Exclude: []int64{3}}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := `SELECT COUNT(*) AS total FROM documents WHERE id IN (1, 2) AND id NOT IN (3)`; query != expected {
+		t.Errorf("Expected %s, got %s", expected, query)
+	}
+}
@@ -1,13 +1,9 @@
 package manticore
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"strings"
 	"time"
 
@@ -20,6 +16,7 @@ import (
 func (mc *manticoreHTTPClient) IndexDocument(doc *models.Document, vector []float64) error {
 	startTime := time.Now()
 	log.Printf("[INDEX] [SINGLE] Starting document indexing with Auto Embeddings: ID=%d, Title='%s'", doc.ID, doc.Title)
+	stampIndexTimes([]*models.Document{doc}, startTime)
 
 	// Index in unified documents table (Auto Embeddings will generate vectors automatically)
 	if err := mc.indexDocumentUnified(doc); err != nil {
@@ -51,26 +48,34 @@ func (mc *manticoreHTTPClient) indexDocumentUnified(doc *models.Document) error
 		// Create replace request for unified documents table with Auto Embeddings
 		// Note: content_vector field will be populated automatically by ManticoreSearch
 		replaceReq := ReplaceRequest{
-			Index: "documents",
+			Index: mc.table("documents"),
 			ID:    int64(doc.ID),
 			Doc: map[string]interface{}{
-				"title":   doc.Title,
-				"content": doc.Content,
-				"url":     doc.URL,
+				"title":      doc.Title,
+				"content":    doc.Content,
+				"url":        doc.URL,
+				"status":     documentStatusCode(doc.Status),
+				"indexed_at": doc.IndexedAt,
+				"updated_at": doc.UpdatedAt,
+				"tags":       tagsValue(doc.Tags),
+				// content_hash lets an incremental indexer skip unchanged documents, see GetDocumentHashes
+				"content_hash": doc.ContentHash(),
+				// url_string copies url as a string attribute for regex filters, see RegexFilter
+				URLAttribute: doc.URL,
 				// content_vector field is omitted - it will be generated automatically from title+content
 			},
 		}
 
-		reqBody, err := json.Marshal(replaceReq)
-		if err != nil {
+		reqBuf := getBuffer()
+		if err := encodeJSON(reqBuf, replaceReq); err != nil {
+			putBuffer(reqBuf)
 			log.Printf("[INDEX] [UNIFIED] [ERROR] Failed to marshal replace request for doc ID=%d: %v", doc.ID, err)
 			return fmt.Errorf("failed to marshal replace request: %v", err)
 		}
 
-		log.Printf("[INDEX] [UNIFIED] [REQUEST] POST %s/replace - Doc ID=%d, Body size: %d bytes (Auto Embeddings)", mc.baseURL, doc.ID, len(reqBody))
-		log.Printf("[INDEX] [UNIFIED] [REQUEST] Payload: %s", string(reqBody))
+		log.Printf("[INDEX] [UNIFIED] [REQUEST] POST %s/replace - Doc ID=%d, Body size: %d bytes (Auto Embeddings)", mc.baseURL, doc.ID, reqBuf.Len())
 
-		req, err := http.NewRequestWithContext(ctx, "POST", mc.baseURL+"/replace", bytes.NewReader(reqBody))
+		req, err := newPooledRequest(ctx, "POST", mc.baseURL+"/replace", reqBuf)
 		if err != nil {
 			log.Printf("[INDEX] [UNIFIED] [ERROR] Failed to create HTTP request for doc ID=%d: %v", doc.ID, err)
 			return fmt.Errorf("failed to create replace request: %v", err)
@@ -86,28 +91,29 @@ func (mc *manticoreHTTPClient) indexDocumentUnified(doc *models.Document) error
 		}
 		defer resp.Body.Close()
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
+		respBuf := getBuffer()
+		defer putBuffer(respBuf)
+		if err := readLimited(respBuf, resp.Body, mc.maxResponseSize); err != nil {
 			log.Printf("[INDEX] [UNIFIED] [ERROR] Failed to read response body for doc ID=%d after %v: %v", doc.ID, requestDuration, err)
 			return fmt.Errorf("failed to read replace response: %v", err)
 		}
+		body := respBuf.Bytes()
 
 		log.Printf("[INDEX] [UNIFIED] [RESPONSE] HTTP %d - Response size: %d bytes - Duration: %v", resp.StatusCode, len(body), requestDuration)
-		log.Printf("[INDEX] [UNIFIED] [RESPONSE] Body: %s", string(body))
 
 		if resp.StatusCode >= 400 {
 			log.Printf("[INDEX] [UNIFIED] [ERROR] Replace operation failed for doc ID=%d: HTTP %d, %s", doc.ID, resp.StatusCode, string(body))
-			return fmt.Errorf("replace operation failed: HTTP %d, %s", resp.StatusCode, string(body))
+			return newHTTPStatusError(resp.StatusCode, mc.baseURL+"/replace", "POST", fmt.Sprintf("replace operation failed: HTTP %d, %s", resp.StatusCode, string(body)))
 		}
 
 		log.Printf("[INDEX] [UNIFIED] [SUCCESS] Document indexed with Auto Embeddings: ID=%d - Duration: %v", doc.ID, requestDuration)
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), mc.timeouts.Index)
 	defer cancel()
 
-	return mc.circuitBreakerWithRetry.Execute(ctx, mc.baseURL+"/replace", "POST", operation)
+	return mc.circuitBreakerWithRetry.ExecuteClass(ctx, OperationClassBulk, mc.baseURL+"/replace", "POST", operation)
 }
 
 // indexDocumentFullText indexes a document in the full-text search table using /replace endpoint
@@ -127,25 +133,29 @@ func (mc *manticoreHTTPClient) indexDocumentVector(doc *models.Document, vector
 
 		// Create replace request for vector table
 		replaceReq := ReplaceRequest{
-			Index: "documents_vector",
+			Index: mc.table("documents_vector"),
 			ID:    int64(doc.ID),
 			Doc: map[string]interface{}{
 				"title":       doc.Title,
 				"url":         doc.URL,
+				"status":      documentStatusCode(doc.Status),
+				"indexed_at":  doc.IndexedAt,
+				"updated_at":  doc.UpdatedAt,
+				"tags":        tagsValue(doc.Tags),
 				"vector_data": vectorStr,
 			},
 		}
 
-		reqBody, err := json.Marshal(replaceReq)
-		if err != nil {
+		reqBuf := getBuffer()
+		if err := encodeJSON(reqBuf, replaceReq); err != nil {
+			putBuffer(reqBuf)
 			log.Printf("[INDEX] [VECTOR] [ERROR] Failed to marshal replace request for doc ID=%d: %v", doc.ID, err)
 			return fmt.Errorf("failed to marshal vector replace request: %v", err)
 		}
 
-		log.Printf("[INDEX] [VECTOR] [REQUEST] POST %s/replace - Doc ID=%d, Vector size: %d, Body size: %d bytes", mc.baseURL, doc.ID, len(vector), len(reqBody))
-		log.Printf("[INDEX] [VECTOR] [REQUEST] Payload: %s", string(reqBody))
+		log.Printf("[INDEX] [VECTOR] [REQUEST] POST %s/replace - Doc ID=%d, Vector size: %d, Body size: %d bytes", mc.baseURL, doc.ID, len(vector), reqBuf.Len())
 
-		req, err := http.NewRequestWithContext(ctx, "POST", mc.baseURL+"/replace", bytes.NewReader(reqBody))
+		req, err := newPooledRequest(ctx, "POST", mc.baseURL+"/replace", reqBuf)
 		if err != nil {
 			log.Printf("[INDEX] [VECTOR] [ERROR] Failed to create HTTP request for doc ID=%d: %v", doc.ID, err)
 			return fmt.Errorf("failed to create vector replace request: %v", err)
@@ -161,28 +171,29 @@ func (mc *manticoreHTTPClient) indexDocumentVector(doc *models.Document, vector
 		}
 		defer resp.Body.Close()
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
+		respBuf := getBuffer()
+		defer putBuffer(respBuf)
+		if err := readLimited(respBuf, resp.Body, mc.maxResponseSize); err != nil {
 			log.Printf("[INDEX] [VECTOR] [ERROR] Failed to read response body for doc ID=%d after %v: %v", doc.ID, requestDuration, err)
 			return fmt.Errorf("failed to read vector replace response: %v", err)
 		}
+		body := respBuf.Bytes()
 
 		log.Printf("[INDEX] [VECTOR] [RESPONSE] HTTP %d - Response size: %d bytes - Duration: %v", resp.StatusCode, len(body), requestDuration)
-		log.Printf("[INDEX] [VECTOR] [RESPONSE] Body: %s", string(body))
 
 		if resp.StatusCode >= 400 {
 			log.Printf("[INDEX] [VECTOR] [ERROR] Vector replace operation failed for doc ID=%d: HTTP %d, %s", doc.ID, resp.StatusCode, string(body))
-			return fmt.Errorf("vector replace operation failed: HTTP %d, %s", resp.StatusCode, string(body))
+			return newHTTPStatusError(resp.StatusCode, mc.baseURL+"/replace", "POST", fmt.Sprintf("vector replace operation failed: HTTP %d, %s", resp.StatusCode, string(body)))
 		}
 
 		log.Printf("[INDEX] [VECTOR] [SUCCESS] Document indexed in vector table: ID=%d - Duration: %v", doc.ID, requestDuration)
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), mc.timeouts.Index)
 	defer cancel()
 
-	return mc.circuitBreakerWithRetry.Execute(ctx, mc.baseURL+"/replace", "POST", operation)
+	return mc.circuitBreakerWithRetry.ExecuteClass(ctx, OperationClassBulk, mc.baseURL+"/replace", "POST", operation)
 }
 
 // IndexDocuments indexes multiple documents using efficient bulk operations with optimization
@@ -194,6 +205,7 @@ func (mc *manticoreHTTPClient) IndexDocuments(documents []*models.Document, vect
 
 	startTime := time.Now()
 	log.Printf("[INDEX] [BULK] Starting optimized bulk document indexing: %d documents", len(documents))
+	stampIndexTimes(documents, startTime)
 
 	// Validate vectors length matches documents length if provided
 	if len(vectors) > 0 && len(vectors) != len(documents) {
@@ -0,0 +1,22 @@
+package manticore
+
+// MinInfixLen is the length of the shortest word infix the documents table indexes. Indexing every
+// infix of three or more characters (trigrams and longer) lets spell-tolerant searches match
+// substrings and misspellings of indexed words.
+const MinInfixLen = 3
+
+// WithSpellTolerance returns request with each query word also matching the indexed words that
+// contain it (expand_keywords), and with fuzzy the indexed words up to two edits away from it. The
+// documents table must index infixes, see MinInfixLen; fuzzy matching requires Manticore 7.0.
+func WithSpellTolerance(request SearchRequest, fuzzy bool) SearchRequest {
+	options := make(map[string]interface{}, len(request.Options)+1)
+	for name, value := range request.Options {
+		options[name] = value
+	}
+	options["expand_keywords"] = 1
+	if fuzzy {
+		options["fuzzy"] = 1
+	}
+	request.Options = options
+	return request
+}
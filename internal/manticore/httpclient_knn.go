@@ -0,0 +1,76 @@
+package manticore
+
+import (
+	"context"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// KNNOptions tunes the nearest neighbour search of AI searches
+type KNNOptions struct {
+	K  int // Least number of neighbours to find, raised to cover the requested page; 0 finds just the page
+	Ef int // Size of the HNSW candidate list while searching, 0 for the server default
+}
+
+// IsZero reports whether the options leave searches unchanged
+func (o KNNOptions) IsZero() bool {
+	return o.K == 0 && o.Ef == 0
+}
+
+// Apply sets k and ef of the knn query of a search request. k is only raised, so the neighbours
+// still cover every result up to the requested page. Requests without a knn query are unchanged.
+func (o KNNOptions) Apply(request SearchRequest) SearchRequest {
+	knn, ok := request.Query["knn"].(map[string]interface{})
+	if !ok || o.IsZero() {
+		return request
+	}
+
+	tuned := make(map[string]interface{}, len(knn)+1)
+	for key, value := range knn {
+		tuned[key] = value
+	}
+	if k, _ := knn["k"].(int); o.K > k {
+		tuned["k"] = o.K
+	}
+	if o.Ef > 0 {
+		tuned["ef"] = o.Ef
+	}
+
+	query := make(map[string]interface{}, len(request.Query))
+	for key, value := range request.Query {
+		query[key] = value
+	}
+	query["knn"] = tuned
+	request.Query = query
+	return request
+}
+
+// knnOptionsKey is the context key for WithKNNOptions
+type knnOptionsKey struct{}
+
+// WithKNNOptions returns a context whose AI searches apply options, see WithSearchStatuses
+func WithKNNOptions(ctx context.Context, options KNNOptions) context.Context {
+	return context.WithValue(ctx, knnOptionsKey{}, options)
+}
+
+// knnOptions returns the options set with WithKNNOptions
+func knnOptions(ctx context.Context) KNNOptions {
+	options, _ := ctx.Value(knnOptionsKey{}).(KNNOptions)
+	return options
+}
+
+// HNSWSimilarity returns the HNSW_SIMILARITY of the content_vector column for the distance metric
+// of aiConfig, cosine unless configured otherwise
+func HNSWSimilarity(aiConfig *models.AISearchConfig) string {
+	if aiConfig == nil {
+		return "cosine"
+	}
+	switch aiConfig.Similarity {
+	case models.SimilarityL2:
+		return "l2"
+	case models.SimilarityDot:
+		return "ip"
+	default:
+		return "cosine"
+	}
+}
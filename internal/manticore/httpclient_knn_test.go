@@ -0,0 +1,46 @@
+package manticore
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestKNNOptionsApply(t *testing.T) {
+	client := NewHTTPClient(DefaultHTTPClientConfig("http://localhost:9308")).(*manticoreHTTPClient)
+	original := client.CreateAutoEmbeddingSearchRequest("documents", "content_vector", "form", 10, 20)
+
+	request := KNNOptions{K: 100, Ef: 200}.Apply(original)
+	expected := map[string]interface{}{"field": "content_vector", "query": "form", "k": 100, "ef": 200}
+	if knn := request.Query["knn"]; !reflect.DeepEqual(knn, expected) {
+		t.Errorf("Unexpected knn query: %v", knn)
+	}
+	if k := original.Query["knn"].(map[string]interface{})["k"]; k != 30 {
+		t.Errorf("Expected the original request to keep k 30, got %v", k)
+	}
+
+	// k still covers the requested page
+	request = KNNOptions{K: 5}.Apply(original)
+	if k := request.Query["knn"].(map[string]interface{})["k"]; k != 30 {
+		t.Errorf("Expected k 30 to cover the page, got %v", k)
+	}
+
+	// Requests without a knn query are unchanged
+	basic := NewBasicSearchRequest("documents", "form", 10, 0)
+	if request := (KNNOptions{K: 100, Ef: 200}).Apply(basic); !reflect.DeepEqual(request, basic) {
+		t.Errorf("Expected the basic request to be unchanged, got %v", request.Query)
+	}
+}
+
+func TestHNSWSimilarity(t *testing.T) {
+	tests := map[string]string{"": "cosine", models.SimilarityCosine: "cosine", models.SimilarityL2: "l2", models.SimilarityDot: "ip"}
+	for similarity, expected := range tests {
+		if actual := HNSWSimilarity(&models.AISearchConfig{Similarity: similarity}); actual != expected {
+			t.Errorf("HNSWSimilarity(%q) = %q, expected %q", similarity, actual, expected)
+		}
+	}
+	if actual := HNSWSimilarity(nil); actual != "cosine" {
+		t.Errorf("Expected cosine without a configuration, got %q", actual)
+	}
+}
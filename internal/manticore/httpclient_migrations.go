@@ -0,0 +1,258 @@
+package manticore
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// SchemaVersion is the schema version this binary expects. Bump it together with a new entry in schemaMigrations.
+const SchemaVersion = 8
+
+// schemaMetaTable stores the applied schema version in a single row
+const schemaMetaTable = "schema_meta"
+
+// schemaMetaRowID is the id of the row holding the schema version
+const schemaMetaRowID = 1
+
+// schemaMigration upgrades the schema from Version-1 to Version without dropping document data.
+// Version 1 creates the latest table layout, so later steps must tolerate changes that already exist.
+type schemaMigration struct {
+	Version     int
+	Description string
+	Apply       func(c *manticoreHTTPClient, aiConfig *models.AISearchConfig) error
+}
+
+// schemaMigrations lists every migration in ascending version order
+var schemaMigrations = []schemaMigration{
+	{
+		Version:     1,
+		Description: "create documents and documents_vector tables",
+		Apply: func(c *manticoreHTTPClient, aiConfig *models.AISearchConfig) error {
+			if err := c.createDocumentsTable(aiConfig, true); err != nil {
+				return err
+			}
+			return c.createVectorTable(true)
+		},
+	},
+	{
+		Version:     2,
+		Description: "drop legacy per-mode tables",
+		Apply: func(c *manticoreHTTPClient, aiConfig *models.AISearchConfig) error {
+			for _, table := range legacyTables {
+				if err := c.executeSQL(fmt.Sprintf("DROP TABLE IF EXISTS %s", c.table(table))); err != nil {
+					return fmt.Errorf("failed to drop legacy table %s: %v", table, err)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     3,
+		Description: "add status attribute for archived and soft deleted documents",
+		Apply: func(c *manticoreHTTPClient, aiConfig *models.AISearchConfig) error {
+			for _, table := range requiredTables {
+				if err := c.addColumn(table, "status", "INT"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     4,
+		Description: "add indexed_at and updated_at attributes",
+		Apply: func(c *manticoreHTTPClient, aiConfig *models.AISearchConfig) error {
+			for _, table := range requiredTables {
+				for _, column := range []string{"indexed_at", "updated_at"} {
+					if err := c.addColumn(table, column, "TIMESTAMP"); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     5,
+		Description: "add tags attribute",
+		Apply: func(c *manticoreHTTPClient, aiConfig *models.AISearchConfig) error {
+			for _, table := range requiredTables {
+				if err := c.addColumn(table, "tags", "JSON"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     6,
+		Description: "add content_hash attribute for change detection",
+		Apply: func(c *manticoreHTTPClient, aiConfig *models.AISearchConfig) error {
+			return c.addColumn("documents", "content_hash", "STRING")
+		},
+	},
+	{
+		Version:     7,
+		Description: "index word infixes for spell-tolerant basic search",
+		Apply: func(c *manticoreHTTPClient, aiConfig *models.AISearchConfig) error {
+			// Only documents indexed afterwards get infixes, so existing ones need a reindex
+			query := fmt.Sprintf("ALTER TABLE %s min_infix_len='%d'", c.table("documents"), MinInfixLen)
+			if err := c.executeSQL(query); err != nil {
+				return fmt.Errorf("failed to enable infixes on documents: %v", err)
+			}
+			return nil
+		},
+	},
+	{
+		Version:     8,
+		Description: "add url_string attribute for regex filters",
+		Apply: func(c *manticoreHTTPClient, aiConfig *models.AISearchConfig) error {
+			if err := c.addColumn("documents", URLAttribute, "STRING"); err != nil {
+				return err
+			}
+			// Existing documents read an empty url_string, which regex filters would never match
+			return c.backfillURLAttribute()
+		},
+	},
+}
+
+// MigrationResult describes what MigrateSchema changed
+type MigrationResult struct {
+	FromVersion int      `json:"from_version"`
+	ToVersion   int      `json:"to_version"`
+	Applied     []string `json:"applied,omitempty"`
+	// Generation is the generation of the documents table searches use, advanced by ReembedDocuments
+	Generation int64 `json:"generation,omitempty"`
+}
+
+// MigrateSchema brings the schema up to SchemaVersion by running the pending migrations in order.
+// Tables created before versioning existed are treated as version 1. The stored version is updated
+// after every step, so a failed migration resumes from the same step on the next start.
+func (mc *manticoreHTTPClient) MigrateSchema(aiConfig *models.AISearchConfig) (*MigrationResult, error) {
+	// Migrations apply to the documents table searches use, see ReembedDocuments
+	if err := mc.loadDocumentsGeneration(); err != nil {
+		return nil, err
+	}
+	current, err := mc.currentSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MigrationResult{FromVersion: current, ToVersion: current, Generation: mc.documentsGeneration.Load()}
+	if current > SchemaVersion {
+		return result, fmt.Errorf("schema version %d is newer than supported version %d", current, SchemaVersion)
+	}
+	if current == SchemaVersion {
+		log.Printf("[SCHEMA] [MIGRATE] Schema is up to date at version %d", current)
+		return result, nil
+	}
+
+	for _, migration := range schemaMigrations {
+		if migration.Version <= current {
+			continue
+		}
+
+		log.Printf("[SCHEMA] [MIGRATE] Applying migration %d: %s", migration.Version, migration.Description)
+		if err := migration.Apply(mc, aiConfig); err != nil {
+			return result, fmt.Errorf("migration %d (%s) failed: %v", migration.Version, migration.Description, err)
+		}
+		if err := mc.setSchemaVersion(migration.Version); err != nil {
+			return result, err
+		}
+
+		result.ToVersion = migration.Version
+		result.Applied = append(result.Applied, migration.Description)
+	}
+
+	log.Printf("[SCHEMA] [MIGRATE] [SUCCESS] Migrated schema from version %d to %d", result.FromVersion, result.ToVersion)
+	return result, nil
+}
+
+// currentSchemaVersion returns the stored schema version, 1 for unversioned tables from older releases
+// and 0 when no schema exists yet
+func (mc *manticoreHTTPClient) currentSchemaVersion() (int, error) {
+	response, err := mc.querySQL(fmt.Sprintf("SELECT version FROM %s WHERE id = %d", mc.table(schemaMetaTable), schemaMetaRowID))
+	if err != nil && !isUnknownTableError(err) {
+		return 0, fmt.Errorf("failed to read schema version: %v", err)
+	}
+	if err == nil && len(response.Data) > 0 {
+		version, err := parseSQLInt(response.Data[0]["version"])
+		if err != nil {
+			return 0, fmt.Errorf("invalid schema version: %v", err)
+		}
+		return int(version), nil
+	}
+
+	tables, err := mc.querySQL("SHOW TABLES")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list tables: %v", err)
+	}
+	if len(missingTables(tables, []string{mc.table("documents")})) == 0 {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// backfillURLAttribute copies the url field of every document into URLAttribute, a page of
+// documents at a time. Documents whose copy is already up to date are left alone, so the backfill
+// can be repeated after a failure.
+func (mc *manticoreHTTPClient) backfillURLAttribute() error {
+	var after int64
+	for {
+		response, err := mc.querySQL(fmt.Sprintf("SELECT id, url, %s FROM %s WHERE id > %d ORDER BY id ASC LIMIT %d",
+			URLAttribute, mc.table("documents"), after, idPageSize))
+		if err != nil {
+			return fmt.Errorf("failed to read document urls: %v", err)
+		}
+
+		for _, row := range response.Data {
+			id, err := parseSQLInt(row["id"])
+			if err != nil {
+				return fmt.Errorf("invalid id in documents: %v", err)
+			}
+			after = id
+
+			url, _ := row["url"].(string)
+			current, _ := row[URLAttribute].(string)
+			if url == current {
+				continue
+			}
+			query := fmt.Sprintf("UPDATE %s SET %s = '%s' WHERE id = %d", mc.table("documents"), URLAttribute, escapeSQLString(url), id)
+			if _, err := mc.querySQL(query); err != nil {
+				return fmt.Errorf("failed to copy the url of document %d: %v", id, err)
+			}
+		}
+		if len(response.Data) < idPageSize {
+			return nil
+		}
+	}
+}
+
+// addColumn adds an attribute to a table, succeeding when the attribute already exists.
+// Existing rows read the new attribute as zero.
+func (mc *manticoreHTTPClient) addColumn(table, column, columnType string) error {
+	err := mc.executeSQL(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", mc.table(table), column, columnType))
+	if err != nil && !strings.Contains(strings.ToLower(err.Error()), "already") {
+		return fmt.Errorf("failed to add column %s to %s: %v", column, table, err)
+	}
+	return nil
+}
+
+// setSchemaVersion records the applied schema version
+func (mc *manticoreHTTPClient) setSchemaVersion(version int) error {
+	createQuery := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version INT, applied_at BIGINT)", mc.table(schemaMetaTable))
+	if err := mc.executeSQL(createQuery); err != nil {
+		return fmt.Errorf("failed to create %s table: %v", schemaMetaTable, err)
+	}
+
+	replaceQuery := fmt.Sprintf("REPLACE INTO %s (id, version, applied_at) VALUES (%d, %d, %d)",
+		mc.table(schemaMetaTable), schemaMetaRowID, version, time.Now().Unix())
+	if err := mc.executeSQL(replaceQuery); err != nil {
+		return fmt.Errorf("failed to store schema version %d: %v", version, err)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package manticore
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// migrationServer answers schema version and table queries and records executed statements
+func migrationServer(t *testing.T, versionResponse, tablesResponse string) (string, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var executed []string
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cli" {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			executed = append(executed, strings.TrimSpace(string(body)))
+			mu.Unlock()
+			w.WriteHeader(200)
+			w.Write([]byte("Query OK"))
+			return
+		}
+
+		r.ParseForm()
+		query := r.PostForm.Get("query")
+		w.WriteHeader(200)
+		switch {
+		case strings.HasPrefix(query, fmt.Sprintf("SELECT version FROM schema_meta WHERE id = %d", documentsGenerationRowID)):
+			w.Write([]byte(`[{"data":[],"error":""}]`))
+		case strings.HasPrefix(query, "SELECT version FROM schema_meta"):
+			w.Write([]byte(versionResponse))
+		case query == "SHOW TABLES":
+			w.Write([]byte(tablesResponse))
+		case strings.HasPrefix(query, "SELECT id, url, url_string FROM documents"):
+			w.Write([]byte(`[{"data":[],"error":""}]`))
+		default:
+			t.Errorf("Unexpected query: %s", query)
+			w.Write([]byte(`[{"data":[],"error":""}]`))
+		}
+	})
+	t.Cleanup(server.Close)
+
+	return server.URL, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), executed...)
+	}
+}
+
+// storeVersionPrefix starts the statement that records the latest schema version
+var storeVersionPrefix = fmt.Sprintf("REPLACE INTO schema_meta (id, version, applied_at) VALUES (1, %d, ", SchemaVersion)
+
+func TestMigrateSchema(t *testing.T) {
+	const (
+		noMeta     = `[{"total":0,"error":"unknown local table(s) 'schema_meta' in search request","warning":""}]`
+		noTables   = `[{"data":[],"error":""}]`
+		withTables = `[{"data":[{"Table":"documents","Type":"rt"},{"Table":"documents_vector","Type":"rt"}],"error":""}]`
+	)
+
+	tests := []struct {
+		name            string
+		version         string
+		tables          string
+		expectedFrom    int
+		expectedApplied int
+		expectCreate    bool
+		expectError     bool
+	}{
+		{"fresh database", noMeta, noTables, 0, SchemaVersion, true, false},
+		{"unversioned tables", noMeta, withTables, 1, SchemaVersion - 1, false, false},
+		{"up to date", fmt.Sprintf(`[{"data":[{"version":%d}],"error":""}]`, SchemaVersion), withTables, SchemaVersion, 0, false, false},
+		{"newer schema", `[{"data":[{"version":99}],"error":""}]`, withTables, 99, 0, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, executed := migrationServer(t, tt.version, tt.tables)
+			client := NewHTTPClient(DefaultHTTPClientConfig(url))
+
+			result, err := client.MigrateSchema(nil)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+				if len(executed()) != 0 {
+					t.Errorf("Expected no statements, got %v", executed())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.FromVersion != tt.expectedFrom || result.ToVersion != SchemaVersion || len(result.Applied) != tt.expectedApplied {
+				t.Errorf("Unexpected result: %+v", result)
+			}
+
+			statements := executed()
+			created, altered, timestamped := false, false, false
+			for _, statement := range statements {
+				if statement == "ALTER TABLE documents ADD COLUMN status INT" {
+					altered = true
+				}
+				if statement == "ALTER TABLE documents_vector ADD COLUMN updated_at TIMESTAMP" {
+					timestamped = true
+				}
+				if strings.HasPrefix(statement, "DROP TABLE IF EXISTS documents ") || statement == "DROP TABLE IF EXISTS documents" {
+					t.Errorf("Migration dropped the documents table: %s", statement)
+				}
+				if strings.Contains(statement, "CREATE TABLE IF NOT EXISTS documents (") {
+					created = true
+				}
+			}
+			if altered != (tt.expectedApplied > 0) {
+				t.Errorf("Expected status column migration %v, statements: %v", tt.expectedApplied > 0, statements)
+			}
+			if timestamped != (tt.expectedApplied > 0) {
+				t.Errorf("Expected timestamp column migration %v, statements: %v", tt.expectedApplied > 0, statements)
+			}
+			if created != tt.expectCreate {
+				t.Errorf("Expected documents table creation %v, statements: %v", tt.expectCreate, statements)
+			}
+
+			if tt.expectedApplied > 0 {
+				last := statements[len(statements)-1]
+				if !strings.HasPrefix(last, storeVersionPrefix) {
+					t.Errorf("Expected schema version to be stored last, got %q", last)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrateSchemaBackfillsURLAttribute(t *testing.T) {
+	var mu sync.Mutex
+	var updates []string
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		if r.URL.Path == "/cli" {
+			w.Write([]byte("Query OK"))
+			return
+		}
+
+		r.ParseForm()
+		query := r.PostForm.Get("query")
+		switch {
+		case strings.HasPrefix(query, fmt.Sprintf("SELECT version FROM schema_meta WHERE id = %d", documentsGenerationRowID)):
+			w.Write([]byte(`[{"data":[],"error":""}]`))
+		case strings.HasPrefix(query, "SELECT version FROM schema_meta"):
+			w.Write([]byte(`[{"data":[{"version":7}],"error":""}]`))
+		case strings.HasPrefix(query, "SELECT id, url, url_string FROM documents WHERE id > 0 "):
+			w.Write([]byte(`[{"data":[{"id":1,"url":"https://example.com/a","url_string":""},{"id":2,"url":"https://example.com/it's","url_string":""},{"id":3,"url":"https://example.com/c","url_string":"https://example.com/c"}],"error":""}]`))
+		case strings.HasPrefix(query, "UPDATE "):
+			mu.Lock()
+			updates = append(updates, query)
+			mu.Unlock()
+			w.Write([]byte(`[{"total":1,"error":""}]`))
+		default:
+			t.Errorf("Unexpected query: %s", query)
+			w.Write([]byte(`[{"data":[],"error":""}]`))
+		}
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+	if _, err := client.MigrateSchema(nil); err != nil {
+		t.Fatalf("MigrateSchema failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{
+		"UPDATE documents SET url_string = 'https://example.com/a' WHERE id = 1",
+		`UPDATE documents SET url_string = 'https://example.com/it\'s' WHERE id = 2`,
+	}
+	if strings.Join(updates, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected updates %q, got %q", expected, updates)
+	}
+}
+
+func TestCreateSchemaStoresVersion(t *testing.T) {
+	url, executed := migrationServer(t, "", "")
+	client := NewHTTPClient(DefaultHTTPClientConfig(url))
+
+	if err := client.CreateSchema(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	statements := executed()
+	last := statements[len(statements)-1]
+	if !strings.HasPrefix(last, storeVersionPrefix) {
+		t.Errorf("Expected schema version to be stored, got %q", last)
+	}
+}
+
+func TestTruncateAndResetTable(t *testing.T) {
+	url, executed := migrationServer(t, "", "")
+	client := NewHTTPClient(DefaultHTTPClientConfig(url))
+
+	if err := client.TruncateTable("documents_vector"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := client.ResetTable("documents_vector", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	statements := executed()
+	if len(statements) != 3 || statements[0] != "TRUNCATE TABLE documents_vector" || statements[1] != "DROP TABLE IF EXISTS documents_vector" || !strings.Contains(statements[2], "CREATE TABLE documents_vector") {
+		t.Errorf("Unexpected statements: %q", statements)
+	}
+
+	for _, table := range []string{"schema_meta", "documents_basic"} {
+		if err := client.TruncateTable(table); err == nil {
+			t.Errorf("Expected an error truncating %s", table)
+		}
+		if err := client.ResetTable(table, nil); err == nil {
+			t.Errorf("Expected an error resetting %s", table)
+		}
+	}
+	if len(executed()) != 3 {
+		t.Error("Expected no statements for unknown tables")
+	}
+}
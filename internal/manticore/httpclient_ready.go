@@ -0,0 +1,120 @@
+package manticore
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ReadyProgress describes a single readiness probe made while waiting for Manticore
+type ReadyProgress struct {
+	Attempt   int           // 1-based attempt number
+	Elapsed   time.Duration // time since waiting started
+	NextDelay time.Duration // delay before the next attempt; zero when ready
+	Err       error         // health check error; nil when ready
+	Ready     bool
+}
+
+// ReadyOptions configures how WaitForReadyContext polls the server
+type ReadyOptions struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	Jitter          float64 // fraction of the delay randomized in both directions, 0..1
+	OnProgress      func(ReadyProgress)
+}
+
+// DefaultReadyOptions returns the backoff used by WaitForReady
+func DefaultReadyOptions() ReadyOptions {
+	return ReadyOptions{
+		InitialInterval: 500 * time.Millisecond,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      2.0,
+		Jitter:          0.2,
+	}
+}
+
+// readyBackoffDelay returns the jittered delay to wait after the given 1-based attempt
+func readyBackoffDelay(opts ReadyOptions, attempt int, random func() float64) time.Duration {
+	delay := float64(opts.InitialInterval) * math.Pow(opts.Multiplier, float64(attempt-1))
+	if maxDelay := float64(opts.MaxInterval); opts.MaxInterval > 0 && delay > maxDelay {
+		delay = maxDelay
+	}
+
+	if opts.Jitter > 0 {
+		delay += delay * opts.Jitter * (2*random() - 1)
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return time.Duration(delay)
+}
+
+// WaitForReady waits for Manticore to be ready with timeout and comprehensive logging
+func (mc *manticoreHTTPClient) WaitForReady(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	log.Printf("Waiting for Manticore HTTP client to be ready (timeout: %v)", timeout)
+	return mc.WaitForReadyContext(ctx, DefaultReadyOptions())
+}
+
+// WaitForReadyContext polls the server with jittered exponential backoff until it responds or ctx is done.
+// Every attempt is reported to opts.OnProgress. On success the client is marked connected and server
+// capabilities are detected.
+func (mc *manticoreHTTPClient) WaitForReadyContext(ctx context.Context, opts ReadyOptions) error {
+	defaults := DefaultReadyOptions()
+	if opts.InitialInterval <= 0 {
+		opts.InitialInterval = defaults.InitialInterval
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = defaults.Multiplier
+	}
+	if opts.Jitter < 0 || opts.Jitter > 1 {
+		opts.Jitter = defaults.Jitter
+	}
+
+	startTime := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		err := mc.HealthCheck()
+		progress := ReadyProgress{Attempt: attempt, Elapsed: time.Since(startTime), Err: err, Ready: err == nil}
+
+		if err == nil {
+			log.Printf("Manticore HTTP client is ready after %v (%d attempts)", progress.Elapsed, attempt)
+			mc.isConnected.Store(true)
+			if opts.OnProgress != nil {
+				opts.OnProgress(progress)
+			}
+
+			// Detect server features so search modes can choose native or fallback implementations
+			if _, err := mc.DetectCapabilities(); err != nil {
+				log.Printf("Warning: Failed to detect Manticore capabilities: %v", err)
+			}
+			return nil
+		}
+
+		progress.NextDelay = readyBackoffDelay(opts, attempt, rand.Float64)
+		log.Printf("Manticore not ready (attempt %d, elapsed %v), retrying in %v: %v",
+			attempt, progress.Elapsed.Round(time.Millisecond), progress.NextDelay.Round(time.Millisecond), err)
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+
+		timer := time.NewTimer(progress.NextDelay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			totalDuration := time.Since(startTime)
+			log.Printf("Gave up waiting for Manticore HTTP client after %v (%d attempts)", totalDuration, attempt)
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("timeout waiting for Manticore to be ready after %v: %v", totalDuration, err)
+			}
+			return fmt.Errorf("stopped waiting for Manticore to be ready after %v: %v", totalDuration, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
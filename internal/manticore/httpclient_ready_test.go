@@ -0,0 +1,105 @@
+package manticore
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadyBackoffDelay(t *testing.T) {
+	opts := ReadyOptions{InitialInterval: 100 * time.Millisecond, MaxInterval: time.Second, Multiplier: 2}
+	noJitter := func() float64 { return 0.5 }
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, want := range expected {
+		if got := readyBackoffDelay(opts, i+1, noJitter); got != want {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+
+	opts.Jitter = 0.2
+	if got := readyBackoffDelay(opts, 1, func() float64 { return 0 }); got != 80*time.Millisecond {
+		t.Errorf("Expected lower jitter bound 80ms, got %v", got)
+	}
+	if got := readyBackoffDelay(opts, 1, func() float64 { return 1 }); got != 120*time.Millisecond {
+		t.Errorf("Expected upper jitter bound 120ms, got %v", got)
+	}
+}
+
+func TestWaitForReadyContextReportsProgress(t *testing.T) {
+	var requests int32
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"data":[],"error":""}]`)) // SHOW STATUS result
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+	defer client.Close()
+
+	var events []ReadyProgress
+	opts := ReadyOptions{
+		InitialInterval: 10 * time.Millisecond,
+		MaxInterval:     50 * time.Millisecond,
+		Multiplier:      2,
+		OnProgress:      func(p ReadyProgress) { events = append(events, p) },
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.WaitForReadyContext(ctx, opts); err != nil {
+		t.Fatalf("Expected client to become ready, got %v", err)
+	}
+	if !client.IsConnected() {
+		t.Error("Client should be connected after WaitForReadyContext")
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 progress events, got %d: %+v", len(events), events)
+	}
+	if events[0].Ready || events[0].Err == nil || events[0].NextDelay != 10*time.Millisecond {
+		t.Errorf("Unexpected first event: %+v", events[0])
+	}
+	if events[1].NextDelay != 20*time.Millisecond {
+		t.Errorf("Expected backoff to double, got %v", events[1].NextDelay)
+	}
+	if last := events[2]; !last.Ready || last.Attempt != 3 || last.Err != nil {
+		t.Errorf("Unexpected final event: %+v", last)
+	}
+}
+
+func TestWaitForReadyContextCancelled(t *testing.T) {
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	opts := ReadyOptions{
+		InitialInterval: time.Hour,
+		OnProgress:      func(ReadyProgress) { cancel() },
+	}
+
+	start := time.Now()
+	err := client.WaitForReadyContext(ctx, opts)
+	if err == nil || !strings.Contains(err.Error(), "stopped waiting") {
+		t.Fatalf("Expected cancellation error, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("WaitForReadyContext did not return promptly after cancellation")
+	}
+	if client.IsConnected() {
+		t.Error("Client should not be connected after cancellation")
+	}
+}
@@ -0,0 +1,106 @@
+package manticore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// SortUpdatedAt orders results by the updated_at attribute
+const SortUpdatedAt = "updated_at"
+
+// AtLeast is a Count filter value matching attribute values greater than or equal to it
+type AtLeast int64
+
+// RecencyOptions restricts searches to recently updated documents and orders them by attributes
+// such as the update time
+type RecencyOptions struct {
+	Since int64     // Only match documents updated at or after this Unix time, zero matches all
+	Sort  SortOrder // Order of the results, empty to order them by relevance
+}
+
+// IsZero reports whether the options leave searches unchanged
+func (o RecencyOptions) IsZero() bool {
+	return o.Since == 0 && len(o.Sort) == 0
+}
+
+// Matches reports whether a document passes the Since filter
+func (o RecencyOptions) Matches(doc *models.Document) bool {
+	return o.Since == 0 || doc.UpdatedAt >= o.Since
+}
+
+// Apply adds the Since filter (see addFilter) and the sort order to a search request
+func (o RecencyOptions) Apply(request SearchRequest) SearchRequest {
+	request = o.Sort.Apply(request)
+	if o.Since == 0 || request.Query == nil {
+		return request
+	}
+	return addFilter(request, map[string]interface{}{"range": map[string]interface{}{"updated_at": map[string]interface{}{"gte": o.Since}}})
+}
+
+// recencyKey is the context key for WithRecency
+type recencyKey struct{}
+
+// WithRecency returns a context whose AI searches apply options, see WithSearchStatuses
+func WithRecency(ctx context.Context, options RecencyOptions) context.Context {
+	return context.WithValue(ctx, recencyKey{}, options)
+}
+
+// recencyOptions returns the options set with WithRecency
+func recencyOptions(ctx context.Context) RecencyOptions {
+	options, _ := ctx.Value(recencyKey{}).(RecencyOptions)
+	return options
+}
+
+// DocumentTimesFromSource decodes the indexed_at and updated_at attributes of a hit, zero when missing
+func DocumentTimesFromSource(source map[string]interface{}) (indexedAt, updatedAt int64) {
+	indexedAt, _ = parseSQLInt(source["indexed_at"])
+	updatedAt, _ = parseSQLInt(source["updated_at"])
+	return indexedAt, updatedAt
+}
+
+// GetDocumentTimes returns when each document of the documents table was last updated, in Unix
+// seconds by id. Documents indexed before updated_at existed report their indexed_at time, or
+// zero when neither is known, and a missing table has no documents.
+func (mc *manticoreHTTPClient) GetDocumentTimes() (map[int64]int64, error) {
+	times := make(map[int64]int64)
+	var after int64
+	for {
+		response, err := mc.querySQL(fmt.Sprintf("SELECT id, indexed_at, updated_at FROM %s WHERE id > %d ORDER BY id ASC LIMIT %d", mc.table("documents"), after, idPageSize))
+		if err != nil {
+			if isUnknownTableError(err) {
+				return times, nil
+			}
+			return nil, fmt.Errorf("failed to list document times: %v", err)
+		}
+
+		for _, row := range response.Data {
+			id, err := parseSQLInt(row["id"])
+			if err != nil {
+				return nil, fmt.Errorf("invalid id in documents: %v", err)
+			}
+			indexedAt, updatedAt := DocumentTimesFromSource(row)
+			if updatedAt == 0 {
+				updatedAt = indexedAt
+			}
+			times[id] = updatedAt
+			after = id
+		}
+		if len(response.Data) < idPageSize {
+			return times, nil
+		}
+	}
+}
+
+// stampIndexTimes records that documents are written to Manticore at now. Documents without a
+// known source modification time are treated as updated when they are indexed.
+func stampIndexTimes(documents []*models.Document, now time.Time) {
+	for _, doc := range documents {
+		doc.IndexedAt = now.Unix()
+		if doc.UpdatedAt == 0 {
+			doc.UpdatedAt = doc.IndexedAt
+		}
+	}
+}
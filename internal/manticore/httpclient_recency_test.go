@@ -0,0 +1,114 @@
+package manticore
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestRecencyOptionsApply(t *testing.T) {
+	sinceFilter := map[string]interface{}{"range": map[string]interface{}{"updated_at": map[string]interface{}{"gte": int64(1700000000)}}}
+	options := RecencyOptions{Since: 1700000000, Sort: SortOrder{{Field: SortUpdatedAt}}}
+
+	t.Run("match query", func(t *testing.T) {
+		request := options.Apply(NewBasicSearchRequest("documents", "form", 10, 0))
+
+		expected := map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []map[string]interface{}{
+					{"match": map[string]interface{}{"*": "form"}},
+					sinceFilter,
+				},
+			},
+		}
+		if !reflect.DeepEqual(request.Query, expected) {
+			t.Errorf("Unexpected query: %v", request.Query)
+		}
+		if !reflect.DeepEqual(request.Sort, []map[string]interface{}{{"updated_at": "desc"}}) {
+			t.Errorf("Expected newest first, got %v", request.Sort)
+		}
+	})
+
+	t.Run("knn query with status filter", func(t *testing.T) {
+		client := NewHTTPClient(DefaultHTTPClientConfig("http://localhost:9308")).(*manticoreHTTPClient)
+		request := client.CreateAutoEmbeddingSearchRequest("documents", "content_vector", "form", 10, 0)
+		request = options.Apply(FilterByStatus(request, DefaultSearchStatuses))
+
+		knn, ok := request.Query["knn"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected a knn query, got %v", request.Query)
+		}
+		expected := map[string]interface{}{
+			"bool": map[string]interface{}{"must": []interface{}{
+				map[string]interface{}{"in": map[string]interface{}{"status": []int{0}}},
+				sinceFilter,
+			}},
+		}
+		if !reflect.DeepEqual(knn["filter"], expected) {
+			t.Errorf("Expected both filters inside the knn clause, got %v", knn["filter"])
+		}
+	})
+
+	t.Run("zero options", func(t *testing.T) {
+		original := NewFullTextSearchRequest("documents", "form", 10, 0)
+		request := RecencyOptions{}.Apply(original)
+		if !reflect.DeepEqual(request.Query, original.Query) || request.Sort != nil {
+			t.Errorf("Expected an unchanged request, got %+v", request)
+		}
+	})
+}
+
+func TestStampIndexTimes(t *testing.T) {
+	now := time.Unix(1700000500, 0)
+	documents := []*models.Document{
+		{ID: 1, UpdatedAt: 1600000000},
+		{ID: 2},
+	}
+
+	stampIndexTimes(documents, now)
+
+	if documents[0].IndexedAt != now.Unix() || documents[0].UpdatedAt != 1600000000 {
+		t.Errorf("Expected the source modification time to be kept, got %+v", documents[0])
+	}
+	if documents[1].IndexedAt != now.Unix() || documents[1].UpdatedAt != now.Unix() {
+		t.Errorf("Expected a document without a modification time to be updated now, got %+v", documents[1])
+	}
+}
+
+func TestDocumentTimesFromSource(t *testing.T) {
+	indexedAt, updatedAt := DocumentTimesFromSource(map[string]interface{}{"indexed_at": float64(20), "updated_at": "10"})
+	if indexedAt != 20 || updatedAt != 10 {
+		t.Errorf("Expected 20 and 10, got %d and %d", indexedAt, updatedAt)
+	}
+
+	if indexedAt, updatedAt := DocumentTimesFromSource(map[string]interface{}{}); indexedAt != 0 || updatedAt != 0 {
+		t.Errorf("Expected zero times for rows stored before the attributes existed, got %d and %d", indexedAt, updatedAt)
+	}
+}
+
+func TestGetDocumentTimes(t *testing.T) {
+	var query string
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		query = r.PostForm.Get("query")
+		// Document 2 was indexed before updated_at existed, document 3 before either
+		w.Write([]byte(`[{"columns":[{"id":{"type":"long long"}},{"indexed_at":{"type":"bigint"}},{"updated_at":{"type":"bigint"}}],"data":[{"id":1,"indexed_at":200,"updated_at":100},{"id":2,"indexed_at":300,"updated_at":0},{"id":3,"indexed_at":0,"updated_at":0}],"total":3,"error":"","warning":""}]`))
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+	times, err := client.GetDocumentTimes()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(times) != 3 || times[1] != 100 || times[2] != 300 || times[3] != 0 {
+		t.Errorf("Unexpected times: %v", times)
+	}
+	if !strings.HasPrefix(query, "SELECT id, indexed_at, updated_at FROM documents WHERE id > 0") {
+		t.Errorf("Unexpected query: %q", query)
+	}
+}
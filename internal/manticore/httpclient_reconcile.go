@@ -0,0 +1,58 @@
+package manticore
+
+import (
+	"fmt"
+	"log"
+)
+
+// idPageSize is the number of ids DocumentIDs reads and DeleteDocuments removes per statement,
+// within Manticore's default max_matches
+const idPageSize = 1000
+
+// DocumentIDs returns the ids of every row of one of DataTables in ascending order. A missing
+// table has no ids.
+func (mc *manticoreHTTPClient) DocumentIDs(table string) ([]int64, error) {
+	if !IsDataTable(table) {
+		return nil, fmt.Errorf("unknown table %q", table)
+	}
+
+	var ids []int64
+	var after int64
+	for {
+		response, err := mc.querySQL(fmt.Sprintf("SELECT id FROM %s WHERE id > %d ORDER BY id ASC LIMIT %d", mc.table(table), after, idPageSize))
+		if err != nil {
+			if isUnknownTableError(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to list ids of %s: %v", table, err)
+		}
+
+		for _, row := range response.Data {
+			id, err := parseSQLInt(row["id"])
+			if err != nil {
+				return nil, fmt.Errorf("invalid id in %s: %v", table, err)
+			}
+			ids = append(ids, id)
+			after = id
+		}
+		if len(response.Data) < idPageSize {
+			return ids, nil
+		}
+	}
+}
+
+// DeleteDocuments removes the rows with the given ids from one of DataTables
+func (mc *manticoreHTTPClient) DeleteDocuments(table string, ids []int64) error {
+	if !IsDataTable(table) {
+		return fmt.Errorf("unknown table %q", table)
+	}
+
+	for start := 0; start < len(ids); start += idPageSize {
+		page := ids[start:min(start+idPageSize, len(ids))]
+		if err := mc.executeSQL(fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", mc.table(table), joinIDs(page))); err != nil {
+			return fmt.Errorf("failed to delete rows of %s: %v", table, err)
+		}
+	}
+	log.Printf("[SCHEMA] [DELETE] Deleted %d rows of %s", len(ids), table)
+	return nil
+}
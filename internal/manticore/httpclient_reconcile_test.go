@@ -0,0 +1,84 @@
+package manticore
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDocumentIDs(t *testing.T) {
+	var queries []string
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		query := r.PostForm.Get("query")
+		queries = append(queries, query)
+
+		var after, limit int64
+		switch {
+		case strings.HasPrefix(query, "SELECT id FROM documents "):
+			fmt.Sscanf(query, "SELECT id FROM documents WHERE id > %d ORDER BY id ASC LIMIT %d", &after, &limit)
+		default:
+			w.Write([]byte(`[{"total":0,"error":"unknown local table(s) 'documents_vector' in search request","warning":""}]`))
+			return
+		}
+
+		// A full first page, then the last id
+		rows := []string{}
+		if after == 0 {
+			for id := int64(1); id <= limit; id++ {
+				rows = append(rows, fmt.Sprintf(`{"id":%d}`, id))
+			}
+		} else if after == limit {
+			rows = append(rows, `{"id":"5000"}`)
+		}
+		w.Write([]byte(`[{"columns":[{"id":{"type":"long long"}}],"data":[` + strings.Join(rows, ",") + `],"total":1,"error":"","warning":""}]`))
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+
+	ids, err := client.DocumentIDs("documents")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ids) != idPageSize+1 || ids[0] != 1 || ids[idPageSize] != 5000 {
+		t.Errorf("Expected %d ids ending with 5000, got %d", idPageSize+1, len(ids))
+	}
+	if len(queries) != 2 || queries[1] != fmt.Sprintf("SELECT id FROM documents WHERE id > %d ORDER BY id ASC LIMIT %d", idPageSize, idPageSize) {
+		t.Errorf("Unexpected queries: %q", queries)
+	}
+
+	missing, err := client.DocumentIDs("documents_vector")
+	if err != nil || len(missing) != 0 {
+		t.Errorf("Expected a missing table to have no ids, got %v, %v", missing, err)
+	}
+
+	if _, err := client.DocumentIDs("schema_meta"); err == nil {
+		t.Error("Expected an error for a table other than the data tables")
+	}
+}
+
+func TestDeleteDocuments(t *testing.T) {
+	var statements []string
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		statements = append(statements, strings.TrimSpace(string(body)))
+		w.Write([]byte("Query OK"))
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+
+	ids := make([]int64, idPageSize+2)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	if err := client.DeleteDocuments("documents_vector", ids); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(statements) != 2 || statements[1] != fmt.Sprintf("DELETE FROM documents_vector WHERE id IN (%d, %d)", idPageSize+1, idPageSize+2) {
+		t.Errorf("Unexpected statements: %q", statements[len(statements)-1])
+	}
+}
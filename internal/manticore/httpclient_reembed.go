@@ -0,0 +1,251 @@
+package manticore
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// documentsGenerationRowID is the id of the schema_meta row holding the generation of the
+// documents table in its version column
+const documentsGenerationRowID = 2
+
+// reembedPageSize is the number of documents read at a time while re-embedding
+const reembedPageSize = 100
+
+// documentsTableName returns the name of the documents table of a generation: documents for the
+// first, and documents_g<generation> for the tables ReembedDocuments created since
+func documentsTableName(generation int64) string {
+	if generation == 0 {
+		return "documents"
+	}
+	return fmt.Sprintf("documents_g%d", generation)
+}
+
+// documentsTablePattern matches the names of the documents tables of every generation, capturing
+// the generation of those after the first
+var documentsTablePattern = regexp.MustCompile(`^documents(?:_g([0-9]+))?$`)
+
+// ReembedProgress reports how far ReembedDocuments got
+type ReembedProgress struct {
+	Copied int64 // documents copied into the new table so far
+	Total  int64 // documents in the current table when re-embedding started
+}
+
+// ReembedResult describes the documents table ReembedDocuments switched to
+type ReembedResult struct {
+	Model     string `json:"model"`
+	Table     string `json:"table"`
+	Documents int64  `json:"documents"`
+}
+
+// ReembedDocuments moves the documents to a new documents table whose Auto Embeddings use the
+// model and similarity of aiConfig, for example after switching embedding models. It copies every
+// document into the new table in pages, calling progress after each, while searches keep using the
+// current table. Once all are copied, the new table replaces the current one in a single step, which
+// is recorded in the schema_meta table so it survives restarts, and the old table is dropped. When
+// copying fails or ctx is cancelled the new table is dropped and the current one is kept. Documents
+// indexed or changed while copying may be missed by the copy; an incremental reindex afterwards
+// catches up on their content.
+func (mc *manticoreHTTPClient) ReembedDocuments(ctx context.Context, aiConfig *models.AISearchConfig, progress func(ReembedProgress)) (*ReembedResult, error) {
+	if err := mc.GetCapabilities().RequireAISearch(); err != nil {
+		return nil, err
+	}
+
+	if !mc.reembedding.CompareAndSwap(false, true) {
+		return nil, fmt.Errorf("re-embedding is already running")
+	}
+	defer mc.reembedding.Store(false)
+
+	current := mc.documentsGeneration.Load()
+	next := current + 1
+	shadow := mc.tablePrefix + documentsTableName(next)
+	total, err := mc.CountDocuments("documents")
+	if err != nil {
+		return nil, err
+	}
+
+	// A table left over by an interrupted run is started over
+	if err := mc.executeSQL("DROP TABLE IF EXISTS " + shadow); err != nil {
+		return nil, fmt.Errorf("failed to drop table %s: %v", shadow, err)
+	}
+	if err := mc.createDocumentsTableNamed(shadow, aiConfig, false); err != nil {
+		return nil, err
+	}
+	log.Printf("[SCHEMA] [REEMBED] Copying %d documents into %s with model %s", total, shadow, aiConfig.Model)
+
+	copied, err := mc.copyDocuments(ctx, shadow, func(copied int64) {
+		if progress != nil {
+			progress(ReembedProgress{Copied: copied, Total: total})
+		}
+	})
+	if err != nil {
+		if dropErr := mc.executeSQL("DROP TABLE IF EXISTS " + shadow); dropErr != nil {
+			log.Printf("[SCHEMA] [REEMBED] [WARNING] Failed to drop table %s: %v", shadow, dropErr)
+		}
+		return nil, err
+	}
+
+	// Searches switch tables as soon as the generation is stored
+	if err := mc.setDocumentsGeneration(next); err != nil {
+		return nil, err
+	}
+	old := mc.table("documents")
+	mc.documentsGeneration.Store(next)
+	if err := mc.executeSQL("DROP TABLE IF EXISTS " + old); err != nil {
+		log.Printf("[SCHEMA] [REEMBED] [WARNING] Failed to drop previous documents table %s: %v", old, err)
+	}
+
+	log.Printf("[SCHEMA] [REEMBED] [SUCCESS] Switched to %s with %d documents embedded by %s", shadow, copied, aiConfig.Model)
+	return &ReembedResult{Model: aiConfig.Model, Table: shadow, Documents: copied}, nil
+}
+
+// copyDocuments copies every document of the documents table into table in ascending id order,
+// calling copied with the number copied after each page, and returns that number
+func (mc *manticoreHTTPClient) copyDocuments(ctx context.Context, table string, copied func(int64)) (int64, error) {
+	batchSize := mc.bulkConfig.BatchSize
+	if batchSize <= 0 {
+		batchSize = reembedPageSize
+	}
+
+	var count, after int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		query := fmt.Sprintf("SELECT id, title, content, url, status, indexed_at, updated_at, tags FROM %s WHERE id > %d ORDER BY id ASC LIMIT %d",
+			mc.table("documents"), after, reembedPageSize)
+		response, err := mc.querySQL(query)
+		if err != nil {
+			return count, fmt.Errorf("failed to read documents: %v", err)
+		}
+
+		documents := make([]*models.Document, 0, len(response.Data))
+		for _, row := range response.Data {
+			id, err := parseSQLInt(row["id"])
+			if err != nil {
+				return count, fmt.Errorf("invalid id in documents: %v", err)
+			}
+			doc := &models.Document{ID: int(id)}
+			doc.Title, _ = row["title"].(string)
+			doc.Content, _ = row["content"].(string)
+			doc.URL, _ = row["url"].(string)
+			ApplySourceAttributes(doc, row)
+			documents = append(documents, doc)
+			after = id
+		}
+
+		for start := 0; start < len(documents); start += batchSize {
+			end := min(start+batchSize, len(documents))
+			if err := mc.bulkReplaceDocuments(table, documents[start:end]); err != nil {
+				return count, fmt.Errorf("failed to copy documents into %s: %v", table, err)
+			}
+		}
+		count += int64(len(documents))
+		copied(count)
+
+		if len(response.Data) < reembedPageSize {
+			return count, nil
+		}
+	}
+}
+
+// DroppedTable is a documents table DropStaleDocumentsTables dropped
+type DroppedTable struct {
+	Table string `json:"table"`
+	Bytes int64  `json:"bytes"` // disk and RAM size of the table before it was dropped
+}
+
+// DropStaleDocumentsTables drops the documents tables of other generations than the current one:
+// tables ReembedDocuments replaced but failed to drop, and tables of runs interrupted before they
+// completed. The table a running ReembedDocuments copies into is kept.
+func (mc *manticoreHTTPClient) DropStaleDocumentsTables() ([]DroppedTable, error) {
+	response, err := mc.querySQL("SHOW TABLES")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %v", err)
+	}
+
+	current := mc.documentsGeneration.Load()
+	var dropped []DroppedTable
+	for _, row := range response.Data {
+		name, ok := row["Table"].(string)
+		if !ok {
+			// Older Manticore versions name the column Index instead of Table
+			if name, ok = row["Index"].(string); !ok {
+				continue
+			}
+		}
+		if !strings.HasPrefix(name, mc.tablePrefix) {
+			continue
+		}
+		match := documentsTablePattern.FindStringSubmatch(strings.TrimPrefix(name, mc.tablePrefix))
+		if match == nil {
+			continue
+		}
+		var generation int64
+		if match[1] != "" {
+			if generation, err = strconv.ParseInt(match[1], 10, 64); err != nil {
+				continue
+			}
+		}
+		if generation == current || (generation > current && mc.reembedding.Load()) {
+			continue
+		}
+
+		stats, err := mc.indexStatsNamed(name, name)
+		if err != nil {
+			return dropped, err
+		}
+		if err := mc.executeSQL("DROP TABLE IF EXISTS " + name); err != nil {
+			return dropped, fmt.Errorf("failed to drop table %s: %v", name, err)
+		}
+		log.Printf("[SCHEMA] [GC] Dropped stale documents table %s (%d bytes)", name, stats.DiskBytes+stats.RAMBytes)
+		dropped = append(dropped, DroppedTable{Table: name, Bytes: stats.DiskBytes + stats.RAMBytes})
+	}
+	return dropped, nil
+}
+
+// loadDocumentsGeneration reads the generation of the documents table stored by ReembedDocuments,
+// keeping the first generation when none is stored
+func (mc *manticoreHTTPClient) loadDocumentsGeneration() error {
+	response, err := mc.querySQL(fmt.Sprintf("SELECT version FROM %s WHERE id = %d", mc.table(schemaMetaTable), documentsGenerationRowID))
+	if err != nil {
+		if isUnknownTableError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read documents table generation: %v", err)
+	}
+	if len(response.Data) == 0 {
+		return nil
+	}
+	generation, err := parseSQLInt(response.Data[0]["version"])
+	if err != nil {
+		return fmt.Errorf("invalid documents table generation: %v", err)
+	}
+	if generation != mc.documentsGeneration.Load() {
+		log.Printf("[SCHEMA] Using documents table %s", documentsTableName(generation))
+	}
+	mc.documentsGeneration.Store(generation)
+	return nil
+}
+
+// setDocumentsGeneration records the generation of the documents table
+func (mc *manticoreHTTPClient) setDocumentsGeneration(generation int64) error {
+	createQuery := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version INT, applied_at BIGINT)", mc.table(schemaMetaTable))
+	if err := mc.executeSQL(createQuery); err != nil {
+		return fmt.Errorf("failed to create %s table: %v", schemaMetaTable, err)
+	}
+
+	replaceQuery := fmt.Sprintf("REPLACE INTO %s (id, version, applied_at) VALUES (%d, %d, %d)",
+		mc.table(schemaMetaTable), documentsGenerationRowID, generation, time.Now().Unix())
+	if err := mc.executeSQL(replaceQuery); err != nil {
+		return fmt.Errorf("failed to store documents table generation %d: %v", generation, err)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package manticore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// reembedServer serves three documents and records executed statements and the tables documents
+// are bulk indexed into
+func reembedServer(t *testing.T) (string, func() ([]string, map[string]int)) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var executed []string
+	indexed := make(map[string]int)
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/cli":
+			body, _ := io.ReadAll(r.Body)
+			executed = append(executed, strings.TrimSpace(string(body)))
+			w.Write([]byte("Query OK"))
+			return
+		case "/bulk":
+			for _, line := range bulkLines(t, r) {
+				replace := line["replace"].(map[string]interface{})
+				indexed[replace["index"].(string)]++
+			}
+			w.Write([]byte(`{"items":[],"errors":false}`))
+			return
+		}
+
+		r.ParseForm()
+		query := r.PostForm.Get("query")
+		switch {
+		case strings.HasPrefix(query, "SELECT COUNT(*)"):
+			w.Write([]byte(`[{"data":[{"total":3}],"error":""}]`))
+		case strings.HasPrefix(query, "SELECT id, title, content, url") && strings.Contains(query, "WHERE id > 0 "):
+			w.Write([]byte(`[{"data":[
+				{"id":1,"title":"One","content":"first","url":"one.md","status":0,"indexed_at":100,"updated_at":100,"tags":"[\"go\"]"},
+				{"id":2,"title":"Two","content":"second","url":"two.md","status":1,"indexed_at":100,"updated_at":200,"tags":"[]"},
+				{"id":3,"title":"Three","content":"third","url":"three.md","status":0,"indexed_at":100,"updated_at":300,"tags":"[]"}
+			],"error":""}]`))
+		default:
+			w.Write([]byte(`[{"data":[],"error":""}]`))
+		}
+	})
+	t.Cleanup(server.Close)
+
+	return server.URL, func() ([]string, map[string]int) {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), executed...), indexed
+	}
+}
+
+func TestReembedDocuments(t *testing.T) {
+	url, recorded := reembedServer(t)
+	client := NewHTTPClient(DefaultHTTPClientConfig(url)).(*manticoreHTTPClient)
+
+	var progress []ReembedProgress
+	aiConfig := &models.AISearchConfig{Model: "sentence-transformers/all-mpnet-base-v2"}
+	result, err := client.ReembedDocuments(context.Background(), aiConfig, func(p ReembedProgress) {
+		progress = append(progress, p)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Table != "documents_g1" || result.Documents != 3 || result.Model != aiConfig.Model {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if len(progress) != 1 || progress[0] != (ReembedProgress{Copied: 3, Total: 3}) {
+		t.Errorf("Unexpected progress: %+v", progress)
+	}
+
+	executed, indexed := recorded()
+	if indexed["documents_g1"] != 3 || indexed["documents"] != 0 {
+		t.Errorf("Expected the documents to be copied into documents_g1, got %v", indexed)
+	}
+	statements := strings.Join(executed, "\n")
+	for _, expected := range []string{
+		"CREATE TABLE documents_g1",
+		"MODEL_NAME='sentence-transformers/all-mpnet-base-v2'",
+		fmt.Sprintf("REPLACE INTO schema_meta (id, version, applied_at) VALUES (%d, 1, ", documentsGenerationRowID),
+		"DROP TABLE IF EXISTS documents\n",
+	} {
+		if !strings.Contains(statements+"\n", expected) {
+			t.Errorf("Expected a statement containing %q, got:\n%s", expected, statements)
+		}
+	}
+	if table := client.table("documents"); table != "documents_g1" {
+		t.Errorf("Expected searches to use documents_g1, got %s", table)
+	}
+}
+
+func TestReembedDocumentsCancelled(t *testing.T) {
+	url, recorded := reembedServer(t)
+	client := NewHTTPClient(DefaultHTTPClientConfig(url)).(*manticoreHTTPClient)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.ReembedDocuments(ctx, &models.AISearchConfig{Model: "sentence-transformers/all-mpnet-base-v2"}, nil); err == nil {
+		t.Fatal("Expected an error for a cancelled context")
+	}
+
+	// The new table is dropped and searches keep the current one
+	executed, _ := recorded()
+	if last := executed[len(executed)-1]; last != "DROP TABLE IF EXISTS documents_g1" {
+		t.Errorf("Expected the new table to be dropped, got %q", last)
+	}
+	if table := client.table("documents"); table != "documents" {
+		t.Errorf("Expected searches to keep documents, got %s", table)
+	}
+}
+
+func TestLoadDocumentsGeneration(t *testing.T) {
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"data":[{"version":3}],"error":""}]`))
+	})
+	defer server.Close()
+
+	config := DefaultHTTPClientConfig(server.URL)
+	config.TablePrefix = "acme_"
+	client := NewHTTPClient(config).(*manticoreHTTPClient)
+	if err := client.loadDocumentsGeneration(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if table := client.table("documents"); table != "acme_documents_g3" {
+		t.Errorf("Expected acme_documents_g3, got %s", table)
+	}
+	if table := client.table("documents_vector"); table != "acme_documents_vector" {
+		t.Errorf("Expected acme_documents_vector, got %s", table)
+	}
+}
+
+func TestDropStaleDocumentsTables(t *testing.T) {
+	var mu sync.Mutex
+	var executed []string
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/cli" {
+			body, _ := io.ReadAll(r.Body)
+			executed = append(executed, strings.TrimSpace(string(body)))
+			w.Write([]byte("Query OK"))
+			return
+		}
+		r.ParseForm()
+		query := r.PostForm.Get("query")
+		switch {
+		case query == "SHOW TABLES":
+			w.Write([]byte(`[{"data":[
+				{"Table":"acme_documents","Type":"rt"},
+				{"Table":"acme_documents_g1","Type":"rt"},
+				{"Table":"acme_documents_g2","Type":"rt"},
+				{"Table":"acme_documents_vector","Type":"rt"},
+				{"Table":"documents_g5","Type":"rt"}
+			],"error":""}]`))
+		case strings.HasPrefix(query, "SHOW INDEX"):
+			w.Write([]byte(`[{"data":[{"Variable_name":"disk_bytes","Value":"1000"},{"Variable_name":"ram_bytes","Value":"24"}],"error":""}]`))
+		default:
+			w.Write([]byte(`[{"data":[],"error":""}]`))
+		}
+	})
+	defer server.Close()
+
+	config := DefaultHTTPClientConfig(server.URL)
+	config.TablePrefix = "acme_"
+	client := NewHTTPClient(config).(*manticoreHTTPClient)
+	client.documentsGeneration.Store(1)
+
+	// The next generation is kept while a re-embedding copies into it
+	client.reembedding.Store(true)
+	dropped, err := client.DropStaleDocumentsTables()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(dropped) != 1 || dropped[0] != (DroppedTable{Table: "acme_documents", Bytes: 1024}) {
+		t.Errorf("Expected only acme_documents to be dropped, got %+v", dropped)
+	}
+
+	client.reembedding.Store(false)
+	dropped, err = client.DropStaleDocumentsTables()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(dropped) != 2 || dropped[1].Table != "acme_documents_g2" {
+		t.Errorf("Expected acme_documents and acme_documents_g2 to be dropped, got %+v", dropped)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, statement := range executed {
+		if strings.Contains(statement, "acme_documents_g1") || strings.Contains(statement, "vector") || strings.Contains(statement, "documents_g5") {
+			t.Errorf("Expected the current, vector and other tenants' tables to be kept, got %q", statement)
+		}
+	}
+}
+
+func TestReembedDocumentsAlreadyRunning(t *testing.T) {
+	url, _ := reembedServer(t)
+	client := NewHTTPClient(DefaultHTTPClientConfig(url)).(*manticoreHTTPClient)
+	client.reembedding.Store(true)
+
+	if _, err := client.ReembedDocuments(context.Background(), &models.AISearchConfig{Model: "sentence-transformers/all-mpnet-base-v2"}, nil); err == nil {
+		t.Error("Expected an error while re-embedding is already running")
+	}
+}
@@ -0,0 +1,105 @@
+package manticore
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// URLAttribute is the string attribute holding a copy of the url field. Full-text fields can't be
+// filtered, so REGEX() filters on url read this attribute instead.
+const URLAttribute = "url_string"
+
+// regexMatchExpression is the name of the expression a RegexFilter computes in search requests
+const regexMatchExpression = "regex_match"
+
+// RegexFields maps the fields a RegexFilter may match to the string attributes holding them
+var RegexFields = map[string]string{
+	"url": URLAttribute,
+}
+
+// RegexFilter restricts searches to documents whose field matches an RE2 pattern, the syntax of
+// both Go's regexp package and Manticore's REGEX() function
+type RegexFilter struct {
+	Field   string // One of RegexFields, empty matches every document
+	Pattern string
+	regexp  *regexp.Regexp
+}
+
+// NewRegexFilter creates a filter matching field against pattern
+func NewRegexFilter(field, pattern string) (RegexFilter, error) {
+	if _, ok := RegexFields[field]; !ok {
+		return RegexFilter{}, fmt.Errorf("unsupported regex field %q", field)
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return RegexFilter{}, err
+	}
+	return RegexFilter{Field: field, Pattern: pattern, regexp: compiled}, nil
+}
+
+// IsZero reports whether the filter matches every document
+func (f RegexFilter) IsZero() bool {
+	return f.Field == ""
+}
+
+// Matches reports whether a document passes the filter
+func (f RegexFilter) Matches(doc *models.Document) bool {
+	if f.IsZero() {
+		return true
+	}
+	compiled := f.regexp
+	if compiled == nil {
+		// Filters built as literals rather than with NewRegexFilter
+		var err error
+		if compiled, err = regexp.Compile(f.Pattern); err != nil {
+			return false
+		}
+	}
+	switch f.Field {
+	case "url":
+		return compiled.MatchString(doc.URL)
+	}
+	return false
+}
+
+// Apply adds the filter to a search request (see addFilter). The REGEX() expression is computed
+// for every hit and the filter keeps those for which it is 1.
+func (f RegexFilter) Apply(request SearchRequest) SearchRequest {
+	if f.IsZero() || request.Query == nil {
+		return request
+	}
+	expressions := make(map[string]string, len(request.Expressions)+1)
+	for name, expression := range request.Expressions {
+		expressions[name] = expression
+	}
+	expressions[regexMatchExpression] = f.expression()
+	request.Expressions = expressions
+	return addFilter(request, map[string]interface{}{"equals": map[string]interface{}{regexMatchExpression: 1}})
+}
+
+// expression returns the REGEX() call matching the filter
+func (f RegexFilter) expression() string {
+	return fmt.Sprintf("REGEX(%s, '%s')", RegexFields[f.Field], escapeSQLString(f.Pattern))
+}
+
+// sqlCondition returns the WHERE condition for Count
+func (f RegexFilter) sqlCondition() string {
+	return f.expression() + " = 1"
+}
+
+// regexFilterKey is the context key for WithRegexFilter
+type regexFilterKey struct{}
+
+// WithRegexFilter returns a context whose AI searches apply filter, see WithSearchStatuses
+func WithRegexFilter(ctx context.Context, filter RegexFilter) context.Context {
+	return context.WithValue(ctx, regexFilterKey{}, filter)
+}
+
+// regexFilter returns the filter set with WithRegexFilter
+func regexFilter(ctx context.Context) RegexFilter {
+	filter, _ := ctx.Value(regexFilterKey{}).(RegexFilter)
+	return filter
+}
@@ -0,0 +1,66 @@
+package manticore
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestRegexFilterMatches(t *testing.T) {
+	doc := &models.Document{URL: "https://go.dev/doc/effective_go"}
+
+	filter, err := NewRegexFilter("url", `^https://go\.dev/doc/`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !filter.Matches(doc) {
+		t.Error("Expected the filter to match the document URL")
+	}
+	if (RegexFilter{Field: "url", Pattern: `^http://`}).Matches(doc) {
+		t.Error("Expected a literal filter not to match another URL")
+	}
+	if !(RegexFilter{}).Matches(doc) {
+		t.Error("Expected an empty filter to match every document")
+	}
+
+	if _, err := NewRegexFilter("title", "go"); err == nil {
+		t.Error("Expected an error for a field without a string attribute")
+	}
+	if _, err := NewRegexFilter("url", "(go"); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestRegexFilterApply(t *testing.T) {
+	match := map[string]interface{}{"match": map[string]interface{}{"*": "form"}}
+	filter, _ := NewRegexFilter("url", `^https://go\.dev/it's`)
+
+	request := filter.Apply(NewBasicSearchRequest("documents", "form", 10, 0))
+	expected := map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must": []map[string]interface{}{match, {"equals": map[string]interface{}{"regex_match": 1}}},
+		},
+	}
+	if !reflect.DeepEqual(request.Query, expected) {
+		t.Errorf("Unexpected query: %v", request.Query)
+	}
+	if expression := request.Expressions["regex_match"]; expression != `REGEX(url_string, '^https://go\\.dev/it\'s')` {
+		t.Errorf("Unexpected expression: %s", expression)
+	}
+
+	if request := (RegexFilter{}).Apply(NewBasicSearchRequest("documents", "form", 10, 0)); !reflect.DeepEqual(request.Query, match) || request.Expressions != nil {
+		t.Errorf("Expected an empty filter to leave the request unchanged, got %+v", request)
+	}
+}
+
+func TestBuildCountQueryRegex(t *testing.T) {
+	filter, _ := NewRegexFilter("url", `^https://`)
+	query, err := buildCountQuery("documents", "", map[string]interface{}{URLAttribute: filter})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := `SELECT COUNT(*) AS total FROM documents WHERE REGEX(url_string, '^https://') = 1`; query != expected {
+		t.Errorf("Expected %s, got %s", expected, query)
+	}
+}
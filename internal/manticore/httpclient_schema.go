@@ -2,10 +2,12 @@ package manticore
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,7 +16,7 @@ import (
 
 // Schema operations
 
-// executeSQL executes a SQL command using the /cli endpoint with comprehensive logging
+// executeSQL executes a SQL command using the /cli endpoint (or the MySQL transport when configured) with comprehensive logging
 func (mc *manticoreHTTPClient) executeSQL(query string) error {
 	startTime := time.Now()
 	log.Printf("[SQL] Starting execution: %s", query)
@@ -22,6 +24,16 @@ func (mc *manticoreHTTPClient) executeSQL(query string) error {
 	operation := func(ctx context.Context) error {
 		requestStartTime := time.Now()
 
+		if mc.mysql != nil {
+			log.Printf("[SQL] [REQUEST] MySQL %s - Query: %s", mc.mysql.addr, query)
+			if _, err := mc.mysql.Query(ctx, query); err != nil {
+				log.Printf("[SQL] [ERROR] MySQL query '%s' failed after %v: %v", query, time.Since(requestStartTime), err)
+				return fmt.Errorf("SQL execution failed: %v", err)
+			}
+			log.Printf("[SQL] [SUCCESS] Query executed successfully: %s - Duration: %v", query, time.Since(requestStartTime))
+			return nil
+		}
+
 		// Use /cli endpoint with form data instead of /sql with JSON
 		log.Printf("[SQL] [REQUEST] POST %s/cli - Query: %s", mc.baseURL, query)
 
@@ -41,16 +53,15 @@ func (mc *manticoreHTTPClient) executeSQL(query string) error {
 		}
 		defer resp.Body.Close()
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
+		respBuf := getBuffer()
+		defer putBuffer(respBuf)
+		if err := readLimited(respBuf, resp.Body, mc.maxResponseSize); err != nil {
 			log.Printf("[SQL] [ERROR] Failed to read response body for query '%s' after %v: %v", query, requestDuration, err)
 			return fmt.Errorf("failed to read SQL response: %v", err)
 		}
+		body := respBuf.Bytes()
 
 		log.Printf("[SQL] [RESPONSE] HTTP %d - Response size: %d bytes - Duration: %v", resp.StatusCode, len(body), requestDuration)
-		if len(body) > 0 {
-			log.Printf("[SQL] [RESPONSE] Body: %s", string(body))
-		}
 
 		if resp.StatusCode >= 400 {
 			log.Printf("[SQL] [ERROR] SQL execution failed for query '%s': HTTP %d, %s", query, resp.StatusCode, string(body))
@@ -101,19 +112,153 @@ func (mc *manticoreHTTPClient) executeSQL(query string) error {
 	return err
 }
 
-// CreateSchema creates the database schema for Manticore Search
+// querySQL executes a SQL statement using the /sql?mode=raw endpoint (or the MySQL transport) and returns the parsed result set
+func (mc *manticoreHTTPClient) querySQL(query string) (*SQLResponse, error) {
+	startTime := time.Now()
+	log.Printf("[SQL] [QUERY] Starting query: %s", query)
+
+	var result *SQLResponse
+	operation := func(ctx context.Context) error {
+		parsed, err := mc.runSQLQuery(ctx, query)
+		if err != nil {
+			return err
+		}
+		result = parsed
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := mc.circuitBreakerWithRetry.Execute(ctx, mc.baseURL+"/sql", "POST", operation)
+
+	totalDuration := time.Since(startTime)
+	if mc.metricsCollector != nil {
+		mc.metricsCollector.RecordRequest("SQLQuery", totalDuration, err == nil, "")
+	}
+
+	if err != nil {
+		log.Printf("[SQL] [QUERY] [ERROR] Query failed after %v: %s - Error: %v", totalDuration, query, err)
+		return nil, err
+	}
+
+	log.Printf("[SQL] [QUERY] [SUCCESS] Query returned %d rows in %v", len(result.Data), totalDuration)
+	return result, nil
+}
+
+// runSQLQuery sends a single SQL statement over the configured transport, without retries
+func (mc *manticoreHTTPClient) runSQLQuery(ctx context.Context, query string) (*SQLResponse, error) {
+	if mc.mysql != nil {
+		parsed, err := mc.mysql.Query(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("SQL query failed: %v", err)
+		}
+		return parsed, nil
+	}
+
+	form := url.Values{}
+	form.Set("query", query)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", mc.baseURL+"/sql?mode=raw", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL query request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := mc.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("SQL query request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBuf := getBuffer()
+	defer putBuffer(respBuf)
+	if err := readLimited(respBuf, resp.Body, mc.maxResponseSize); err != nil {
+		return nil, fmt.Errorf("failed to read SQL query response: %v", err)
+	}
+	body := respBuf.Bytes()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("SQL query failed: HTTP %d, %s", resp.StatusCode, string(body))
+	}
+
+	parsed, err := parseSQLResponse(body)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Error != "" {
+		return nil, fmt.Errorf("SQL error: %s", parsed.Error)
+	}
+	return parsed, nil
+}
+
+// parseSQLResponse parses a /sql?mode=raw response, which is an array of result sets
+func parseSQLResponse(body []byte) (*SQLResponse, error) {
+	trimmed := strings.TrimSpace(string(body))
+
+	if strings.HasPrefix(trimmed, "[") {
+		var results []SQLResponse
+		if err := json.Unmarshal(body, &results); err != nil {
+			return nil, fmt.Errorf("failed to parse SQL response: %v", err)
+		}
+		if len(results) == 0 {
+			return &SQLResponse{}, nil
+		}
+		return &results[0], nil
+	}
+
+	var result SQLResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse SQL response: %v", err)
+	}
+	return &result, nil
+}
+
+// legacyTables are per-mode tables created by earlier versions of the schema
+var legacyTables = []string{"documents_basic", "documents_fulltext", "documents_hybrid"}
+
+// CreateSchema drops every table and creates the current schema from scratch.
+// Startup uses MigrateSchema instead so existing data survives upgrades.
 func (c *manticoreHTTPClient) CreateSchema(aiConfig *models.AISearchConfig) error {
 	log.Println("Creating Manticore Search schema...")
 
-	// Drop existing tables first
-	tables := []string{"documents", "documents_basic", "documents_fulltext", "documents_vector", "documents_hybrid"}
+	// Drop existing tables first, starting over with the first generation of the documents table
+	if generation := c.documentsGeneration.Swap(0); generation > 0 {
+		if err := c.executeSQL(fmt.Sprintf("DROP TABLE IF EXISTS %s%s", c.tablePrefix, documentsTableName(generation))); err != nil {
+			log.Printf("Warning: Failed to drop table %s: %v", documentsTableName(generation), err)
+		}
+	}
+	tables := append([]string{"documents", "documents_vector", schemaMetaTable}, legacyTables...)
 	for _, table := range tables {
-		dropQuery := fmt.Sprintf("DROP TABLE IF EXISTS %s", table)
+		dropQuery := fmt.Sprintf("DROP TABLE IF EXISTS %s", c.table(table))
 		if err := c.executeSQL(dropQuery); err != nil {
 			log.Printf("Warning: Failed to drop table %s: %v", table, err)
 		}
 	}
 
+	if err := c.createDocumentsTable(aiConfig, false); err != nil {
+		return err
+	}
+	if err := c.createVectorTable(false); err != nil {
+		return err
+	}
+
+	// A freshly created schema already matches the latest version
+	if err := c.setSchemaVersion(SchemaVersion); err != nil {
+		return err
+	}
+
+	log.Println("Schema creation completed successfully")
+	return nil
+}
+
+// createDocumentsTable creates the unified documents table, with Auto Embeddings when the server supports them
+func (c *manticoreHTTPClient) createDocumentsTable(aiConfig *models.AISearchConfig, ifNotExists bool) error {
+	return c.createDocumentsTableNamed(c.table("documents"), aiConfig, ifNotExists)
+}
+
+// createDocumentsTableNamed creates a documents table named as in Manticore, see createDocumentsTable
+func (c *manticoreHTTPClient) createDocumentsTableNamed(table string, aiConfig *models.AISearchConfig, ifNotExists bool) error {
 	// Determine AI model to use
 	aiModel := "sentence-transformers/all-MiniLM-L6-v2" // Default fallback
 	if aiConfig != nil && aiConfig.Model != "" {
@@ -126,15 +271,43 @@ func (c *manticoreHTTPClient) CreateSchema(aiConfig *models.AISearchConfig) erro
 	// Create unified documents table with Auto Embeddings using configurable model
 	// Correct syntax for Auto Embeddings in Manticore Search 13.11+ (all in CREATE TABLE)
 	createTableQuery := fmt.Sprintf(`
-		CREATE TABLE documents (
+		CREATE TABLE %s%s (
 			id BIGINT,
 			title TEXT,
 			content TEXT,
 			url TEXT,
-			content_vector FLOAT_VECTOR KNN_TYPE='hnsw' HNSW_SIMILARITY='cosine' MODEL_NAME='%s' FROM='content'
-		) ENGINE='columnar'`, aiModel)
+			status INT,
+			indexed_at TIMESTAMP,
+			updated_at TIMESTAMP,
+			tags JSON,
+			content_hash STRING,
+			url_string STRING,
+			content_vector FLOAT_VECTOR KNN_TYPE='hnsw' HNSW_SIMILARITY='%s' MODEL_NAME='%s' FROM='content'
+		) ENGINE='columnar' min_infix_len='%d'`, createTableModifier(ifNotExists), table, HNSWSimilarity(aiConfig), aiModel, MinInfixLen)
+
+	// Servers without Auto Embeddings would reject MODEL_NAME, so create a plain full-text table instead
+	if caps := c.GetCapabilities(); caps != nil && !caps.AutoEmbeddings {
+		log.Printf("Manticore %d.%d.%d does not support Auto Embeddings, creating documents table without content_vector",
+			caps.Major, caps.Minor, caps.Patch)
+		createTableQuery = fmt.Sprintf(`
+		CREATE TABLE %s%s (
+			id BIGINT,
+			title TEXT,
+			content TEXT,
+			url TEXT,
+			status INT,
+			indexed_at TIMESTAMP,
+			updated_at TIMESTAMP,
+			tags JSON,
+			content_hash STRING,
+			url_string STRING
+		) ENGINE='columnar' min_infix_len='%d'`, createTableModifier(ifNotExists), table, MinInfixLen)
+	}
+
+	// Query embeddings follow the model of the table as created now, see documentsEmbeddingModel
+	c.embeddingModels.Delete(table)
 
-	log.Printf("Executing schema creation query with Auto Embeddings: %s", createTableQuery)
+	log.Printf("Executing schema creation query: %s", createTableQuery)
 
 	if err := c.executeSQL(createTableQuery); err != nil {
 		log.Printf("Schema creation failed: %v", err)
@@ -142,15 +315,22 @@ func (c *manticoreHTTPClient) CreateSchema(aiConfig *models.AISearchConfig) erro
 	}
 
 	log.Printf("Successfully created documents table with Auto Embeddings model: %s", aiModel)
+	return nil
+}
 
-	// Create documents_vector table for traditional vector search (fallback)
-	vectorTableQuery := `
-		CREATE TABLE documents_vector (
+// createVectorTable creates the documents_vector table used for traditional vector search (fallback)
+func (c *manticoreHTTPClient) createVectorTable(ifNotExists bool) error {
+	vectorTableQuery := fmt.Sprintf(`
+		CREATE TABLE %s%s (
 			id BIGINT,
 			title TEXT,
 			url TEXT,
-			vector_data TEXT
-		) ENGINE='columnar'`
+			vector_data TEXT,
+			status INT,
+			indexed_at TIMESTAMP,
+			updated_at TIMESTAMP,
+			tags JSON
+		) ENGINE='columnar'`, createTableModifier(ifNotExists), c.table("documents_vector"))
 
 	log.Printf("Creating documents_vector table: %s", vectorTableQuery)
 
@@ -158,27 +338,40 @@ func (c *manticoreHTTPClient) CreateSchema(aiConfig *models.AISearchConfig) erro
 		log.Printf("Vector table creation failed: %v", err)
 		return fmt.Errorf("failed to create documents_vector table: %v", err)
 	}
-
-	log.Println("Schema creation completed successfully with AI model:", aiModel)
 	return nil
 }
 
+// createTableModifier returns the IF NOT EXISTS clause when requested
+func createTableModifier(ifNotExists bool) string {
+	if ifNotExists {
+		return "IF NOT EXISTS "
+	}
+	return ""
+}
+
 // ResetDatabase drops existing tables to start fresh
 func (mc *manticoreHTTPClient) ResetDatabase() error {
 	log.Printf("[SCHEMA] [RESET] Starting database reset...")
 
 	// Drop existing tables using SQL API (ignore errors if tables don't exist)
-	dropDocuments := "DROP TABLE IF EXISTS documents"
+	dropDocuments := "DROP TABLE IF EXISTS " + mc.table("documents")
 	if err := mc.executeSQL(dropDocuments); err != nil {
 		log.Printf("[SCHEMA] [RESET] [WARNING] Failed to drop documents table: %v", err)
 	}
 
 	// Also drop old documents_vector table if it exists (from previous schema)
-	dropVectors := "DROP TABLE IF EXISTS documents_vector"
+	dropVectors := "DROP TABLE IF EXISTS " + mc.table("documents_vector")
 	if err := mc.executeSQL(dropVectors); err != nil {
 		log.Printf("[SCHEMA] [RESET] [WARNING] Failed to drop documents_vector table: %v", err)
 	}
 
+	// Without tables the stored schema version no longer describes anything
+	dropMeta := "DROP TABLE IF EXISTS " + mc.table(schemaMetaTable)
+	if err := mc.executeSQL(dropMeta); err != nil {
+		log.Printf("[SCHEMA] [RESET] [WARNING] Failed to drop %s table: %v", schemaMetaTable, err)
+	}
+	mc.documentsGeneration.Store(0)
+
 	log.Printf("[SCHEMA] [RESET] [SUCCESS] Database reset completed")
 	return nil
 }
@@ -188,11 +381,173 @@ func (mc *manticoreHTTPClient) TruncateTables() error {
 	log.Printf("[SCHEMA] [TRUNCATE] Starting table truncation...")
 
 	// Truncate documents table (now includes auto-generated vectors)
-	truncateDocuments := "TRUNCATE TABLE documents"
+	truncateDocuments := "TRUNCATE TABLE " + mc.table("documents")
 	if err := mc.executeSQL(truncateDocuments); err != nil {
 		log.Printf("[SCHEMA] [TRUNCATE] [WARNING] Failed to truncate documents table: %v", err)
 	}
 
+	// Truncate the TF-IDF vectors so reindexing does not collide with old rows
+	truncateVectors := "TRUNCATE TABLE " + mc.table("documents_vector")
+	if err := mc.executeSQL(truncateVectors); err != nil {
+		log.Printf("[SCHEMA] [TRUNCATE] [WARNING] Failed to truncate documents_vector table: %v", err)
+	}
+
 	log.Printf("[SCHEMA] [TRUNCATE] [SUCCESS] Table truncation completed")
 	return nil
 }
+
+// DataTables are the tables holding indexed documents, the ones TruncateTable and ResetTable accept
+var DataTables = []string{"documents", "documents_vector"}
+
+// IsDataTable reports whether table is one of DataTables
+func IsDataTable(table string) bool {
+	for _, name := range DataTables {
+		if name == table {
+			return true
+		}
+	}
+	return false
+}
+
+// TruncateTable removes every document from one of DataTables, keeping its schema
+func (mc *manticoreHTTPClient) TruncateTable(table string) error {
+	if !IsDataTable(table) {
+		return fmt.Errorf("unknown table %q", table)
+	}
+	if err := mc.executeSQL("TRUNCATE TABLE " + mc.table(table)); err != nil {
+		return fmt.Errorf("failed to truncate table %s: %v", table, err)
+	}
+	log.Printf("[SCHEMA] [TRUNCATE] Table %s truncated", table)
+	return nil
+}
+
+// ResetTable drops one of DataTables and creates it again with the current schema, leaving the
+// other tables untouched
+func (mc *manticoreHTTPClient) ResetTable(table string, aiConfig *models.AISearchConfig) error {
+	if !IsDataTable(table) {
+		return fmt.Errorf("unknown table %q", table)
+	}
+	if err := mc.executeSQL("DROP TABLE IF EXISTS " + mc.table(table)); err != nil {
+		return fmt.Errorf("failed to drop table %s: %v", table, err)
+	}
+
+	var err error
+	if table == "documents" {
+		err = mc.createDocumentsTable(aiConfig, false)
+	} else {
+		err = mc.createVectorTable(false)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to recreate table %s: %v", table, err)
+	}
+	log.Printf("[SCHEMA] [RESET] Table %s recreated", table)
+	return nil
+}
+
+// CountDocuments returns the number of documents stored in a table, or 0 when the table does not exist
+func (mc *manticoreHTTPClient) CountDocuments(table string) (int64, error) {
+	response, err := mc.querySQL(fmt.Sprintf("SELECT COUNT(*) AS total FROM %s", mc.table(table)))
+	if err != nil {
+		if isUnknownTableError(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to count documents in %s: %v", table, err)
+	}
+
+	if len(response.Data) == 0 {
+		return 0, nil
+	}
+
+	count, err := parseSQLInt(response.Data[0]["total"])
+	if err != nil {
+		return 0, fmt.Errorf("invalid document count for %s: %v", table, err)
+	}
+	return count, nil
+}
+
+// OptimizeTable asks Manticore to merge the disk chunks of one of DataTables. The merge runs in
+// the background; GetIndexStats reports it as Optimizing until it completes.
+func (mc *manticoreHTTPClient) OptimizeTable(table string) error {
+	if !IsDataTable(table) {
+		return fmt.Errorf("unknown table %q", table)
+	}
+	if err := mc.executeSQL("OPTIMIZE TABLE " + mc.table(table)); err != nil {
+		return fmt.Errorf("failed to optimize table %s: %v", table, err)
+	}
+	log.Printf("[SCHEMA] [OPTIMIZE] Optimization of table %s started", table)
+	return nil
+}
+
+// GetIndexStats returns document count and storage size of a table using SHOW INDEX STATUS.
+// A missing table is reported with Exists set to false rather than as an error.
+func (mc *manticoreHTTPClient) GetIndexStats(table string) (*IndexStats, error) {
+	return mc.indexStatsNamed(table, mc.table(table))
+}
+
+// indexStatsNamed returns the stats of table, stored in Manticore as name
+func (mc *manticoreHTTPClient) indexStatsNamed(table, name string) (*IndexStats, error) {
+	stats := &IndexStats{Table: table}
+
+	response, err := mc.querySQL(fmt.Sprintf("SHOW INDEX %s STATUS", name))
+	if err != nil {
+		if isUnknownTableError(err) {
+			return stats, nil
+		}
+		return nil, fmt.Errorf("failed to get status of %s: %v", table, err)
+	}
+	stats.Exists = true
+
+	var optimizing int64
+	for _, row := range response.Data {
+		name, _ := row["Variable_name"].(string)
+		var target *int64
+		switch name {
+		case "indexed_documents":
+			target = &stats.IndexedDocuments
+		case "indexed_bytes":
+			target = &stats.IndexedBytes
+		case "disk_chunks":
+			target = &stats.DiskChunks
+		case "optimizing":
+			target = &optimizing
+		case "disk_bytes":
+			target = &stats.DiskBytes
+		case "ram_bytes":
+			target = &stats.RAMBytes
+		default:
+			continue
+		}
+
+		value, err := parseSQLInt(row["Value"])
+		if err != nil {
+			log.Printf("[SQL] [STATUS] [WARNING] Ignoring %s of %s: %v", name, table, err)
+			continue
+		}
+		*target = value
+	}
+	stats.Optimizing = optimizing != 0
+
+	return stats, nil
+}
+
+// parseSQLInt converts a numeric SQL value, which the JSON API returns as a number and the MySQL transport as a string
+func parseSQLInt(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case float64:
+		return int64(v), nil
+	case string:
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid integer %q", v)
+		}
+		return parsed, nil
+	default:
+		return 0, fmt.Errorf("unexpected value %v", value)
+	}
+}
+
+// isUnknownTableError reports whether a query failed because the table has not been created yet
+func isUnknownTableError(err error) bool {
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "unknown local table") || strings.Contains(message, "no such table")
+}
@@ -1,18 +1,21 @@
 package manticore
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"math"
-	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/ad/manticoresearch-go/internal/blocklist"
+	"github.com/ad/manticoresearch-go/internal/boost"
 	"github.com/ad/manticoresearch-go/internal/models"
 )
 
@@ -20,24 +23,31 @@ import (
 
 // SearchWithRequest performs search operations using the JSON API with comprehensive logging
 func (mc *manticoreHTTPClient) SearchWithRequest(request SearchRequest) (*SearchResponse, error) {
+	return mc.SearchWithContext(context.Background(), request)
+}
+
+// SearchWithContext performs a search that is cancelled with ctx. Without a deadline on ctx
+// the full-text timeout applies.
+func (mc *manticoreHTTPClient) SearchWithContext(ctx context.Context, request SearchRequest) (*SearchResponse, error) {
 	startTime := time.Now()
+	request.Index = mc.table(request.Index)
 	log.Printf("[SEARCH] Starting search operation: index='%s', limit=%d, offset=%d", request.Index, request.Limit, request.Offset)
 
 	operation := func(ctx context.Context) (*SearchResponse, error) {
 		requestStartTime := time.Now()
 
-		// Marshal the search request
-		reqBody, err := json.Marshal(request)
-		if err != nil {
+		// Encode the search request into a pooled buffer
+		reqBuf := getBuffer()
+		if err := encodeJSON(reqBuf, request); err != nil {
+			putBuffer(reqBuf)
 			log.Printf("[SEARCH] [ERROR] Failed to marshal search request: %v", err)
 			return nil, fmt.Errorf("failed to marshal search request: %v", err)
 		}
 
-		log.Printf("[SEARCH] [REQUEST] POST %s/search - Body size: %d bytes", mc.baseURL, len(reqBody))
-		log.Printf("[SEARCH] [REQUEST] Payload: %s", string(reqBody))
+		log.Printf("[SEARCH] [REQUEST] POST %s/search - Body size: %d bytes", mc.baseURL, reqBuf.Len())
 
 		// Create HTTP request
-		req, err := http.NewRequestWithContext(ctx, "POST", mc.baseURL+"/search", bytes.NewReader(reqBody))
+		req, err := newPooledRequest(ctx, "POST", mc.baseURL+"/search", reqBuf)
 		if err != nil {
 			log.Printf("[SEARCH] [ERROR] Failed to create HTTP request: %v", err)
 			return nil, fmt.Errorf("failed to create search request: %v", err)
@@ -54,34 +64,36 @@ func (mc *manticoreHTTPClient) SearchWithRequest(request SearchRequest) (*Search
 		}
 		defer resp.Body.Close()
 
-		// Read response body
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			log.Printf("[SEARCH] [ERROR] Failed to read response body after %v: %v", requestDuration, err)
-			return nil, fmt.Errorf("failed to read search response: %v", err)
-		}
-
-		log.Printf("[SEARCH] [RESPONSE] HTTP %d - Response size: %d bytes - Duration: %v", resp.StatusCode, len(body), requestDuration)
-		log.Printf("[SEARCH] [RESPONSE] Body: %s", string(body))
+		// Bound the response size so an oversized payload cannot exhaust memory
+		body := newMaxSizeReader(resp.Body, mc.maxResponseSize)
 
 		if resp.StatusCode >= 400 {
-			log.Printf("[SEARCH] [ERROR] Search operation failed: HTTP %d, %s", resp.StatusCode, string(body))
-			return nil, fmt.Errorf("search operation failed: HTTP %d, %s", resp.StatusCode, string(body))
+			respBuf := getBuffer()
+			defer putBuffer(respBuf)
+			if err := readLimited(respBuf, body, maxLoggedBodySize*4); err != nil && !errors.Is(err, ErrResponseTooLarge) {
+				log.Printf("[SEARCH] [ERROR] Failed to read response body after %v: %v", requestDuration, err)
+				return nil, fmt.Errorf("failed to read search response: %v", err)
+			}
+			log.Printf("[SEARCH] [ERROR] Search operation failed: HTTP %d, %s", resp.StatusCode, respBuf.String())
+			return nil, fmt.Errorf("search operation failed: HTTP %d, %s", resp.StatusCode, respBuf.String())
 		}
 
-		// Parse response
-		var searchResponse SearchResponse
-		if err := json.Unmarshal(body, &searchResponse); err != nil {
+		// Decode the response as it streams in, stopping after the requested number of hits
+		counter := &byteCounter{}
+		searchResponse, err := decodeSearchResponse(io.TeeReader(body, counter), int(request.Limit))
+		if err != nil {
 			log.Printf("[SEARCH] [ERROR] Failed to parse search response: %v", err)
 			return nil, fmt.Errorf("failed to parse search response: %v", err)
 		}
 
+		log.Printf("[SEARCH] [RESPONSE] HTTP %d - Response size: %d bytes - Duration: %v", resp.StatusCode, counter.size, requestDuration)
+
 		log.Printf("[SEARCH] [SUCCESS] Search completed: %d hits found - Duration: %v", searchResponse.Hits.Total, requestDuration)
-		return &searchResponse, nil
+		return searchResponse, nil
 	}
 
 	// Execute with circuit breaker and retry logic
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := withDefaultDeadline(ctx, mc.timeouts.FullText)
 	defer cancel()
 
 	result, err := mc.executeSearchWithRetry(ctx, operation)
@@ -119,7 +131,7 @@ func (mc *manticoreHTTPClient) executeSearchWithRetry(ctx context.Context, opera
 		return err
 	}
 
-	err := mc.circuitBreakerWithRetry.Execute(ctx, mc.baseURL+"/search", "POST", retryOperation)
+	err := mc.circuitBreakerWithRetry.ExecuteClass(ctx, OperationClassSearch, mc.baseURL+"/search", "POST", retryOperation)
 	return result, err
 }
 
@@ -150,8 +162,37 @@ func (mc *manticoreHTTPClient) GetAllDocuments() ([]*models.Document, error) {
 	return documents, nil
 }
 
+// GetDocument returns the document with the given ID whatever its status, or ErrDocumentNotFound
+func (mc *manticoreHTTPClient) GetDocument(id int64) (*models.Document, error) {
+	request := SearchRequest{
+		Index: "documents",
+		Query: map[string]interface{}{"equals": map[string]interface{}{"id": id}},
+		Limit: 1,
+	}
+
+	response, err := mc.SearchWithRequest(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document %d: %v", id, err)
+	}
+	documents, err := mc.convertSearchResponse(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert document %d: %v", id, err)
+	}
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrDocumentNotFound, id)
+	}
+	return documents[0], nil
+}
+
 // GetAllDocumentsWithVectors retrieves all documents with their vector data from documents_vector table
 func (mc *manticoreHTTPClient) GetAllDocumentsWithVectors() ([]*models.Document, [][]float64, error) {
+	ctx, cancel := withDefaultDeadline(context.Background(), mc.timeouts.Vector)
+	defer cancel()
+	return mc.GetAllDocumentsWithVectorsContext(ctx)
+}
+
+// GetAllDocumentsWithVectorsContext retrieves all documents with their vectors, cancelled with ctx
+func (mc *manticoreHTTPClient) GetAllDocumentsWithVectorsContext(ctx context.Context) ([]*models.Document, [][]float64, error) {
 	startTime := time.Now()
 	log.Printf("[SEARCH] [VECTOR] [GETALL] Starting GetAllDocumentsWithVectors operation")
 
@@ -159,7 +200,7 @@ func (mc *manticoreHTTPClient) GetAllDocumentsWithVectors() ([]*models.Document,
 	request := mc.CreateMatchAllRequest("documents_vector", 10000, 0)
 
 	// Execute search
-	response, err := mc.SearchWithRequest(request)
+	response, err := mc.SearchWithContext(ctx, request)
 	if err != nil {
 		log.Printf("[SEARCH] [VECTOR] [GETALL] [ERROR] Failed to execute match_all query on vector table: %v", err)
 		return nil, nil, fmt.Errorf("failed to get all documents with vectors: %v", err)
@@ -177,10 +218,135 @@ func (mc *manticoreHTTPClient) GetAllDocumentsWithVectors() ([]*models.Document,
 	return documents, vectors, nil
 }
 
+// Count returns the exact number of documents matching query and filters, also when a search caps
+// its total. An empty query counts all documents. A string filter restricts the full-text match to
+// that field; numeric filters compare the attribute for equality.
+func (mc *manticoreHTTPClient) Count(query string, filters map[string]interface{}) (int64, error) {
+	sqlQuery, err := buildCountQuery(mc.table("documents"), query, filters)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := mc.querySQL(sqlQuery)
+	if err != nil {
+		if isUnknownTableError(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to count matching documents: %v", err)
+	}
+
+	if len(response.Data) == 0 {
+		return 0, nil
+	}
+
+	count, err := parseSQLInt(response.Data[0]["total"])
+	if err != nil {
+		return 0, fmt.Errorf("invalid matching document count: %v", err)
+	}
+	return count, nil
+}
+
+// buildCountQuery builds the SELECT COUNT(*) statement for Count
+func buildCountQuery(table, query string, filters map[string]interface{}) (string, error) {
+	var match []string
+	if query = SanitizeQueryString(query); query != "" {
+		match = append(match, "("+query+")")
+	}
+
+	var conditions []string
+
+	// Sort the fields so the statement is deterministic
+	fields := make([]string, 0, len(filters))
+	for field := range filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if !isSQLIdentifier(field) {
+			return "", fmt.Errorf("invalid filter field: %q", field)
+		}
+
+		switch value := filters[field].(type) {
+		case models.DocumentStatus:
+			codes, err := statusCodes([]models.DocumentStatus{value})
+			if err != nil {
+				return "", err
+			}
+			conditions = append(conditions, fmt.Sprintf("%s = %d", field, codes[0]))
+		case []models.DocumentStatus:
+			codes, err := statusCodes(value)
+			if err != nil || len(codes) == 0 {
+				return "", fmt.Errorf("invalid status filter for %s: %v", field, value)
+			}
+			values := make([]string, len(codes))
+			for i, code := range codes {
+				values[i] = strconv.Itoa(code)
+			}
+			conditions = append(conditions, fmt.Sprintf("%s IN (%s)", field, strings.Join(values, ", ")))
+		case TagFilter:
+			conditions = append(conditions, value.sqlCondition(field))
+		case IDFilter:
+			conditions = append(conditions, value.sqlCondition(field))
+		case RegexFilter:
+			conditions = append(conditions, value.sqlCondition())
+		case AtLeast:
+			conditions = append(conditions, fmt.Sprintf("%s >= %d", field, value))
+		case string:
+			match = append(match, fmt.Sprintf(`@%s "%s"`, field, escapeFullTextPhrase(value)))
+		case int, int32, int64:
+			conditions = append(conditions, fmt.Sprintf("%s = %d", field, value))
+		case float32, float64:
+			conditions = append(conditions, fmt.Sprintf("%s = %v", field, value))
+		default:
+			return "", fmt.Errorf("unsupported value for filter %s: %T", field, value)
+		}
+	}
+
+	if len(match) > 0 {
+		conditions = append([]string{fmt.Sprintf("MATCH('%s')", escapeSQLString(strings.Join(match, " ")))}, conditions...)
+	}
+
+	sqlQuery := fmt.Sprintf("SELECT COUNT(*) AS total FROM %s", table)
+	if len(conditions) > 0 {
+		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return sqlQuery, nil
+}
+
+// isSQLIdentifier reports whether name can be used unquoted as a field name
+func isSQLIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// escapeFullTextPhrase escapes a value for use inside a quoted full-text phrase
+func escapeFullTextPhrase(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
+
+// escapeSQLString escapes a value for use inside a single-quoted SQL string
+func escapeSQLString(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+}
+
 // Search request creation methods
 
 // CreateBasicSearchRequest creates a basic search request with match query
 func (mc *manticoreHTTPClient) CreateBasicSearchRequest(index, query string, limit, offset int32) SearchRequest {
+	return NewBasicSearchRequest(index, query, limit, offset)
+}
+
+// NewBasicSearchRequest creates a basic search request matching query against all fields
+func NewBasicSearchRequest(index, query string, limit, offset int32) SearchRequest {
 	log.Printf("[SEARCH] [BASIC] Creating basic search request: query='%s', limit=%d, offset=%d", query, limit, offset)
 
 	searchQuery := map[string]interface{}{
@@ -199,10 +365,21 @@ func (mc *manticoreHTTPClient) CreateBasicSearchRequest(index, query string, lim
 
 // CreateFullTextSearchRequest creates a full-text search request with query_string
 func (mc *manticoreHTTPClient) CreateFullTextSearchRequest(index, query string, limit, offset int32) SearchRequest {
+	return NewFullTextSearchRequest(index, query, limit, offset)
+}
+
+// NewFullTextSearchRequest creates a full-text search request using Manticore's query_string syntax.
+// The query is sanitized first; one without searchable words matches all documents.
+func NewFullTextSearchRequest(index, query string, limit, offset int32) SearchRequest {
 	log.Printf("[SEARCH] [FULLTEXT] Creating full-text search request: query='%s', limit=%d, offset=%d", query, limit, offset)
 
 	searchQuery := map[string]interface{}{
-		"query_string": query,
+		"match_all": map[string]interface{}{},
+	}
+	if sanitized := SanitizeQueryString(query); sanitized != "" {
+		searchQuery = map[string]interface{}{
+			"query_string": sanitized,
+		}
 	}
 
 	return SearchRequest{
@@ -213,6 +390,14 @@ func (mc *manticoreHTTPClient) CreateFullTextSearchRequest(index, query string,
 	}
 }
 
+// NewProximitySearchRequest creates a full-text search request for documents containing the terms
+// within distance words of each other, see NearQuery. Without searchable terms it matches all
+// documents.
+func NewProximitySearchRequest(index string, terms []string, distance int, limit, offset int32) SearchRequest {
+	log.Printf("[SEARCH] [PROXIMITY] Creating proximity search request: terms=%q, distance=%d, limit=%d, offset=%d", terms, distance, limit, offset)
+	return NewFullTextSearchRequest(index, NearQuery(distance, terms...), limit, offset)
+}
+
 // CreateMatchQueryRequest creates a match query for specific fields
 func (mc *manticoreHTTPClient) CreateMatchQueryRequest(index string, field, query string, limit, offset int32) SearchRequest {
 	log.Printf("[SEARCH] [MATCH] Creating match query request: field='%s', query='%s', limit=%d, offset=%d", field, query, limit, offset)
@@ -247,6 +432,42 @@ func (mc *manticoreHTTPClient) CreateMatchAllRequest(index string, limit, offset
 	}
 }
 
+// ApplySourceAttributes sets the attributes of a document that are decoded from a hit's _source:
+// status, timestamps and tags
+func ApplySourceAttributes(doc *models.Document, source map[string]interface{}) {
+	doc.Status = DocumentStatusFromSource(source)
+	doc.IndexedAt, doc.UpdatedAt = DocumentTimesFromSource(source)
+	doc.Tags = DocumentTagsFromSource(source)
+}
+
+// addFilter restricts a search request with an attribute filter. KNN queries take the filter inside
+// the knn clause so it is applied before the nearest neighbours are chosen, combined with any filter
+// already there; other queries are wrapped in a bool query. The request's query is not modified.
+func addFilter(request SearchRequest, filter map[string]interface{}) SearchRequest {
+	if knn, ok := request.Query["knn"].(map[string]interface{}); ok && len(request.Query) == 1 {
+		filtered := make(map[string]interface{}, len(knn)+1)
+		for key, value := range knn {
+			filtered[key] = value
+		}
+		if existing, ok := knn["filter"]; ok {
+			filtered["filter"] = map[string]interface{}{
+				"bool": map[string]interface{}{"must": []interface{}{existing, filter}},
+			}
+		} else {
+			filtered["filter"] = filter
+		}
+		request.Query = map[string]interface{}{"knn": filtered}
+		return request
+	}
+
+	request.Query = map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must": []map[string]interface{}{request.Query, filter},
+		},
+	}
+	return request
+}
+
 // Response conversion methods
 
 // convertSearchResponse converts Manticore JSON API response to internal models
@@ -270,6 +491,7 @@ func (mc *manticoreHTTPClient) convertSearchResponse(response *SearchResponse) (
 		if url, ok := hit.Source["url"].(string); ok {
 			doc.URL = url
 		}
+		ApplySourceAttributes(doc, hit.Source)
 
 		documents = append(documents, doc)
 	}
@@ -278,8 +500,8 @@ func (mc *manticoreHTTPClient) convertSearchResponse(response *SearchResponse) (
 	return documents, nil
 }
 
-// convertSearchResponseWithScores converts Manticore JSON API response to search results with scores
-func (mc *manticoreHTTPClient) convertSearchResponseWithScores(response *SearchResponse) ([]models.SearchResult, error) {
+// searchResultsFromResponse converts the hits of a search response to search results with scores
+func searchResultsFromResponse(response *SearchResponse) []models.SearchResult {
 	log.Printf("[SEARCH] [CONVERT] Converting search response with scores: %d hits", response.Hits.Total)
 
 	results := make([]models.SearchResult, 0, len(response.Hits.Hits))
@@ -299,6 +521,7 @@ func (mc *manticoreHTTPClient) convertSearchResponseWithScores(response *SearchR
 		if url, ok := hit.Source["url"].(string); ok {
 			doc.URL = url
 		}
+		ApplySourceAttributes(doc, hit.Source)
 
 		result := models.SearchResult{
 			Document: doc,
@@ -309,7 +532,7 @@ func (mc *manticoreHTTPClient) convertSearchResponseWithScores(response *SearchR
 	}
 
 	log.Printf("[SEARCH] [CONVERT] Successfully converted %d search results", len(results))
-	return results, nil
+	return results
 }
 
 // convertVectorSearchResponse converts search response from documents_vector table to documents and vectors
@@ -331,6 +554,7 @@ func (mc *manticoreHTTPClient) convertVectorSearchResponse(response *SearchRespo
 		if url, ok := hit.Source["url"].(string); ok {
 			doc.URL = url
 		}
+		ApplySourceAttributes(doc, hit.Source)
 
 		// Parse vector data
 		var vector []float64
@@ -503,15 +727,44 @@ func (mc *manticoreHTTPClient) NewSearchResultProcessor() *SearchResultProcessor
 	}
 }
 
+// WithBoosts returns a copy of the processor that applies rules after ranking, in every mode
+func (srp *SearchResultProcessor) WithBoosts(rules boost.Rules) *SearchResultProcessor {
+	processor := *srp
+	processor.boosts = rules
+	return &processor
+}
+
+// WithBlocklist returns a copy of the processor that removes the documents of b from every result
+func (srp *SearchResultProcessor) WithBlocklist(b *blocklist.Blocklist) *SearchResultProcessor {
+	processor := *srp
+	processor.blocklist = b
+	return &processor
+}
+
+// DefaultLengthSlope is a common slope for WithLengthNormalization, see Singhal et al., "Pivoted
+// Document Length Normalization"
+const DefaultLengthSlope = 0.2
+
+// WithLengthNormalization returns a copy of the processor that divides scores by the pivoted
+// length (1 - slope) + slope × length / pivot, where length is a document's content length in
+// characters and pivot the average length of the results. A slope of 0 leaves scores as ranked, 1
+// divides them by the relative length; values in between only correct the bias of the ranker
+// toward long or short documents. Results without content are not adjusted.
+func (srp *SearchResultProcessor) WithLengthNormalization(slope float64) *SearchResultProcessor {
+	processor := *srp
+	processor.lengthSlope = slope
+	return &processor
+}
+
 // ProcessSearchResults processes search results with normalization and ranking
 func (srp *SearchResultProcessor) ProcessSearchResults(response *SearchResponse, mode models.SearchMode) (*models.SearchResponse, error) {
 	log.Printf("[SEARCH] [PROCESS] Processing search results: mode=%s, hits=%d", mode, response.Hits.Total)
 
 	// Convert to search results with scores
-	results, err := srp.client.(*manticoreHTTPClient).convertSearchResponseWithScores(response)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert search response: %v", err)
-	}
+	results := searchResultsFromResponse(response)
+
+	// Correct the length bias before normalizing, so adjusted scores stay within 0-1
+	srp.normalizeLengths(results)
 
 	// Normalize scores
 	normalizedResults := srp.normalizeScores(results)
@@ -519,12 +772,18 @@ func (srp *SearchResultProcessor) ProcessSearchResults(response *SearchResponse,
 	// Apply ranking based on mode
 	rankedResults := srp.rankResults(normalizedResults, mode)
 
+	// Apply attribute boosts on top of the mode's ranking
+	srp.boosts.Apply(rankedResults)
+
 	// Validate results
 	validatedResults := srp.validateResults(rankedResults)
 
+	// Remove blocked documents, which are not counted either
+	validatedResults, blocked := srp.blocklist.Filter(validatedResults)
+
 	return &models.SearchResponse{
 		Documents: validatedResults,
-		Total:     int(response.Hits.Total),
+		Total:     max(int(response.Hits.Total)-blocked, 0),
 		Page:      1, // Default page
 		Mode:      string(mode),
 	}, nil
@@ -562,6 +821,37 @@ func (srp *SearchResultProcessor) normalizeScores(results []models.SearchResult)
 	return results
 }
 
+// normalizeLengths applies pivoted length normalization, see WithLengthNormalization
+func (srp *SearchResultProcessor) normalizeLengths(results []models.SearchResult) {
+	if srp.lengthSlope <= 0 {
+		return
+	}
+
+	lengths := make([]int, len(results))
+	total, counted := 0, 0
+	for i, result := range results {
+		if result.Document == nil {
+			continue
+		}
+		lengths[i] = utf8.RuneCountInString(result.Document.Content)
+		if lengths[i] > 0 {
+			total += lengths[i]
+			counted++
+		}
+	}
+	if counted == 0 {
+		return
+	}
+	pivot := float64(total) / float64(counted)
+
+	for i := range results {
+		if lengths[i] == 0 {
+			continue
+		}
+		results[i].Score /= (1 - srp.lengthSlope) + srp.lengthSlope*float64(lengths[i])/pivot
+	}
+}
+
 // rankResults applies additional ranking logic based on search mode
 func (srp *SearchResultProcessor) rankResults(results []models.SearchResult, mode models.SearchMode) []models.SearchResult {
 	log.Printf("[SEARCH] [RANK] Ranking %d results for mode=%s", len(results), mode)
@@ -596,20 +886,9 @@ func (srp *SearchResultProcessor) rankBasicResults(results []models.SearchResult
 func (srp *SearchResultProcessor) rankFullTextResults(results []models.SearchResult) []models.SearchResult {
 	log.Printf("[SEARCH] [RANK] [FULLTEXT] Applying full-text ranking")
 
-	// Sort by score descending with title boost
+	// Sort by score descending
 	sort.Slice(results, func(i, j int) bool {
-		scoreI := results[i].Score
-		scoreJ := results[j].Score
-
-		// Boost documents with matches in title
-		if strings.Contains(strings.ToLower(results[i].Document.Title), "search") {
-			scoreI *= 1.2
-		}
-		if strings.Contains(strings.ToLower(results[j].Document.Title), "search") {
-			scoreJ *= 1.2
-		}
-
-		return scoreI > scoreJ
+		return results[i].Score > results[j].Score
 	})
 
 	return results
@@ -631,31 +910,9 @@ func (srp *SearchResultProcessor) rankVectorResults(results []models.SearchResul
 func (srp *SearchResultProcessor) rankHybridResults(results []models.SearchResult) []models.SearchResult {
 	log.Printf("[SEARCH] [RANK] [HYBRID] Applying hybrid ranking")
 
-	// Complex ranking that considers multiple factors
+	// Sort by score descending; title matches are weighted by the engine's scoring features
 	sort.Slice(results, func(i, j int) bool {
-		scoreI := results[i].Score
-		scoreJ := results[j].Score
-
-		// Factor in document length (shorter documents get slight boost)
-		contentLenI := len(results[i].Document.Content)
-		contentLenJ := len(results[j].Document.Content)
-
-		if contentLenI > 0 && contentLenI < 1000 {
-			scoreI *= 1.1
-		}
-		if contentLenJ > 0 && contentLenJ < 1000 {
-			scoreJ *= 1.1
-		}
-
-		// Factor in title matches
-		if strings.Contains(strings.ToLower(results[i].Document.Title), "important") {
-			scoreI *= 1.15
-		}
-		if strings.Contains(strings.ToLower(results[j].Document.Title), "important") {
-			scoreJ *= 1.15
-		}
-
-		return scoreI > scoreJ
+		return results[i].Score > results[j].Score
 	})
 
 	return results
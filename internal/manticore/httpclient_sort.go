@@ -0,0 +1,158 @@
+package manticore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// Fields a SortKey may order by
+const (
+	SortRelevance = "relevance" // Score, highest first unless ascending
+	SortIndexedAt = "indexed_at"
+	SortID        = "id"
+)
+
+// SortFields are the fields results may be ordered by. Missing values only exist for the
+// timestamps, which read zero for documents indexed before the attribute existed.
+var SortFields = []string{SortRelevance, SortUpdatedAt, SortIndexedAt, SortID}
+
+// SortKey orders results by one field
+type SortKey struct {
+	Field        string
+	Ascending    bool
+	MissingFirst bool // Documents without a value come first instead of last
+}
+
+// HasMissing reports whether the field can be missing, so MissingFirst applies
+func (k SortKey) HasMissing() bool {
+	return k.Field == SortUpdatedAt || k.Field == SortIndexedAt
+}
+
+// String formats the key as field:direction, with :first or :last for fields that can be missing
+func (k SortKey) String() string {
+	direction := "desc"
+	if k.Ascending {
+		direction = "asc"
+	}
+	if !k.HasMissing() {
+		return k.Field + ":" + direction
+	}
+	missing := "last"
+	if k.MissingFirst {
+		missing = "first"
+	}
+	return k.Field + ":" + direction + ":" + missing
+}
+
+// SortOrder lists sort keys, each breaking the ties of the previous ones. An empty order ranks by
+// relevance; results equal on every key keep their relevance order.
+type SortOrder []SortKey
+
+// String formats the order as comma-separated keys, see SortKey.String
+func (o SortOrder) String() string {
+	keys := make([]string, len(o))
+	for i, key := range o {
+		keys[i] = key.String()
+	}
+	return strings.Join(keys, ",")
+}
+
+// Fields returns the stored fields the order reads
+func (o SortOrder) Fields() []string {
+	var fields []string
+	for _, key := range o {
+		if key.HasMissing() {
+			fields = append(fields, key.Field)
+		}
+	}
+	return fields
+}
+
+// Apply sets the order of a search request. A missing value policy Manticore would not follow by
+// itself, such as missing values last in ascending order, sorts on an expression marking the
+// documents without a value first.
+func (o SortOrder) Apply(request SearchRequest) SearchRequest {
+	if len(o) == 0 {
+		return request
+	}
+	var expressions map[string]string
+	sorts := make([]map[string]interface{}, 0, len(o)*2)
+	for _, key := range o {
+		direction := "desc"
+		if key.Ascending {
+			direction = "asc"
+		}
+		// Zero sorts first in ascending order and last in descending order
+		if key.HasMissing() && key.MissingFirst != key.Ascending {
+			if expressions == nil {
+				expressions = make(map[string]string, len(request.Expressions)+len(o))
+				for name, expression := range request.Expressions {
+					expressions[name] = expression
+				}
+			}
+			name := key.Field + "_missing"
+			expressions[name] = fmt.Sprintf("IF(%s=0,1,0)", key.Field)
+			missingDirection := "asc"
+			if key.MissingFirst {
+				missingDirection = "desc"
+			}
+			sorts = append(sorts, map[string]interface{}{name: missingDirection})
+		}
+		field := key.Field
+		if field == SortRelevance {
+			field = "_score"
+		}
+		sorts = append(sorts, map[string]interface{}{field: direction})
+	}
+	if expressions != nil {
+		request.Expressions = expressions
+	}
+	request.Sort = sorts
+	return request
+}
+
+// SortResults orders results ranked by the service, keeping their order for ties
+func (o SortOrder) SortResults(results []models.SearchResult) {
+	if len(o) == 0 {
+		return
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return o.less(results[i], results[j])
+	})
+}
+
+// less reports whether a sorts before b
+func (o SortOrder) less(a, b models.SearchResult) bool {
+	for _, key := range o {
+		va, vb := key.value(a), key.value(b)
+		if key.HasMissing() && (va == 0) != (vb == 0) {
+			return (va == 0) == key.MissingFirst
+		}
+		if va != vb {
+			return (va < vb) == key.Ascending
+		}
+	}
+	return false
+}
+
+// value returns the field of a result the key orders by
+func (k SortKey) value(result models.SearchResult) float64 {
+	if k.Field == SortRelevance {
+		return result.Score
+	}
+	if result.Document == nil {
+		return 0
+	}
+	switch k.Field {
+	case SortUpdatedAt:
+		return float64(result.Document.UpdatedAt)
+	case SortIndexedAt:
+		return float64(result.Document.IndexedAt)
+	case SortID:
+		return float64(result.Document.ID)
+	}
+	return 0
+}
@@ -0,0 +1,48 @@
+package manticore
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortOrderApply(t *testing.T) {
+	order := SortOrder{
+		{Field: SortUpdatedAt, Ascending: true},
+		{Field: SortIndexedAt, MissingFirst: true},
+		{Field: SortRelevance},
+		{Field: SortID, Ascending: true},
+	}
+	request := order.Apply(NewBasicSearchRequest("documents", "form", 10, 0))
+
+	expectedSort := []map[string]interface{}{
+		{"updated_at_missing": "asc"},
+		{"updated_at": "asc"},
+		{"indexed_at_missing": "desc"},
+		{"indexed_at": "desc"},
+		{"_score": "desc"},
+		{"id": "asc"},
+	}
+	if !reflect.DeepEqual(request.Sort, expectedSort) {
+		t.Errorf("Unexpected sort: %v", request.Sort)
+	}
+	expectedExpressions := map[string]string{
+		"updated_at_missing": "IF(updated_at=0,1,0)",
+		"indexed_at_missing": "IF(indexed_at=0,1,0)",
+	}
+	if !reflect.DeepEqual(request.Expressions, expectedExpressions) {
+		t.Errorf("Unexpected expressions: %v", request.Expressions)
+	}
+	if order.String() != "updated_at:asc:last,indexed_at:desc:first,relevance:desc,id:asc" {
+		t.Errorf("Unexpected string: %s", order)
+	}
+
+	// Manticore already sorts zero last in descending order and first in ascending order
+	request = SortOrder{{Field: SortUpdatedAt}, {Field: SortIndexedAt, Ascending: true, MissingFirst: true}}.Apply(NewBasicSearchRequest("documents", "form", 10, 0))
+	if !reflect.DeepEqual(request.Sort, []map[string]interface{}{{"updated_at": "desc"}, {"indexed_at": "asc"}}) || request.Expressions != nil {
+		t.Errorf("Expected plain attribute sorts, got %v and %v", request.Sort, request.Expressions)
+	}
+
+	if request := (SortOrder{}).Apply(NewBasicSearchRequest("documents", "form", 10, 0)); request.Sort != nil {
+		t.Errorf("Expected relevance order, got %v", request.Sort)
+	}
+}
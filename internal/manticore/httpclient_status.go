@@ -0,0 +1,110 @@
+package manticore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// ErrDocumentNotFound is returned when a status change targets a document that does not exist
+var ErrDocumentNotFound = errors.New("document not found")
+
+// documentStatusCodes are the values stored in the integer status attribute. Active is 0 so rows
+// written before the attribute existed count as active.
+var documentStatusCodes = map[models.DocumentStatus]int{
+	models.DocumentStatusActive:   0,
+	models.DocumentStatusArchived: 1,
+	models.DocumentStatusDeleted:  2,
+}
+
+// DefaultSearchStatuses are the statuses matched by searches that do not ask for others
+var DefaultSearchStatuses = []models.DocumentStatus{models.DocumentStatusActive}
+
+// searchStatusesKey is the context key for WithSearchStatuses
+type searchStatusesKey struct{}
+
+// WithSearchStatuses returns a context whose AI searches only match documents with one of statuses.
+// AI search requests are built inside the client, so the filter travels with the request context.
+func WithSearchStatuses(ctx context.Context, statuses []models.DocumentStatus) context.Context {
+	return context.WithValue(ctx, searchStatusesKey{}, statuses)
+}
+
+// searchStatuses returns the statuses set with WithSearchStatuses, nil when searches are unfiltered
+func searchStatuses(ctx context.Context) []models.DocumentStatus {
+	statuses, _ := ctx.Value(searchStatusesKey{}).([]models.DocumentStatus)
+	return statuses
+}
+
+// DocumentStatusFromSource decodes the status attribute of a hit, defaulting to active
+func DocumentStatusFromSource(source map[string]interface{}) models.DocumentStatus {
+	code, err := parseSQLInt(source["status"])
+	if err != nil {
+		return models.DocumentStatusActive
+	}
+	for status, statusCode := range documentStatusCodes {
+		if int64(statusCode) == code {
+			return status
+		}
+	}
+	return models.DocumentStatusActive
+}
+
+// statusCodes converts statuses to attribute values
+func statusCodes(statuses []models.DocumentStatus) ([]int, error) {
+	codes := make([]int, 0, len(statuses))
+	for _, status := range statuses {
+		code, ok := documentStatusCodes[status]
+		if !ok {
+			return nil, fmt.Errorf("invalid document status: %q", status)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// FilterByStatus restricts a search request to documents with one of statuses (see addFilter).
+// A nil or empty list leaves the request unfiltered.
+func FilterByStatus(request SearchRequest, statuses []models.DocumentStatus) SearchRequest {
+	if len(statuses) == 0 || request.Query == nil {
+		return request
+	}
+	codes, err := statusCodes(statuses)
+	if err != nil {
+		log.Printf("[SEARCH] [STATUS] [WARNING] Ignoring status filter: %v", err)
+		return request
+	}
+	return addFilter(request, map[string]interface{}{"in": map[string]interface{}{"status": codes}})
+}
+
+// SetDocumentStatus changes the status of a document in both tables, so archived and deleted
+// documents disappear from every search mode while their data is kept
+func (mc *manticoreHTTPClient) SetDocumentStatus(id int64, status models.DocumentStatus) error {
+	code, ok := documentStatusCodes[status]
+	if !ok {
+		return fmt.Errorf("invalid document status: %q", status)
+	}
+
+	response, err := mc.querySQL(fmt.Sprintf("UPDATE %s SET status = %d WHERE id = %d", mc.table("documents"), code, id))
+	if err != nil {
+		return fmt.Errorf("failed to update status of document %d: %v", id, err)
+	}
+	if response.Total == 0 {
+		return fmt.Errorf("%w: %d", ErrDocumentNotFound, id)
+	}
+
+	// The vector table only mirrors the documents table, so a missing row there is not an error
+	if _, err := mc.querySQL(fmt.Sprintf("UPDATE %s SET status = %d WHERE id = %d", mc.table("documents_vector"), code, id)); err != nil && !isUnknownTableError(err) {
+		return fmt.Errorf("failed to update vector status of document %d: %v", id, err)
+	}
+
+	log.Printf("[SCHEMA] [STATUS] Document %d is now %s", id, status)
+	return nil
+}
+
+// documentStatusCode returns the attribute value stored for a document, treating an empty status as active
+func documentStatusCode(status models.DocumentStatus) int {
+	return documentStatusCodes[status]
+}
@@ -0,0 +1,117 @@
+package manticore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestFilterByStatus(t *testing.T) {
+	active := []models.DocumentStatus{models.DocumentStatusActive}
+	statusFilter := map[string]interface{}{"in": map[string]interface{}{"status": []int{0}}}
+
+	t.Run("match query", func(t *testing.T) {
+		request := FilterByStatus(NewBasicSearchRequest("documents", "form", 10, 0), active)
+
+		expected := map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []map[string]interface{}{
+					{"match": map[string]interface{}{"*": "form"}},
+					statusFilter,
+				},
+			},
+		}
+		if !reflect.DeepEqual(request.Query, expected) {
+			t.Errorf("Unexpected query: %v", request.Query)
+		}
+	})
+
+	t.Run("knn query", func(t *testing.T) {
+		client := NewHTTPClient(DefaultHTTPClientConfig("http://localhost:9308")).(*manticoreHTTPClient)
+		original := client.CreateAutoEmbeddingSearchRequest("documents", "content_vector", "form", 10, 0)
+		request := FilterByStatus(original, active)
+
+		knn, ok := request.Query["knn"].(map[string]interface{})
+		if !ok || !reflect.DeepEqual(knn["filter"], statusFilter) || knn["query"] != "form" {
+			t.Errorf("Expected the filter inside the knn clause, got %v", request.Query)
+		}
+		if _, changed := original.Query["knn"].(map[string]interface{})["filter"]; changed {
+			t.Error("The original request was modified")
+		}
+	})
+
+	t.Run("no statuses", func(t *testing.T) {
+		original := NewFullTextSearchRequest("documents", "form", 10, 0)
+		if request := FilterByStatus(original, nil); !reflect.DeepEqual(request.Query, original.Query) {
+			t.Errorf("Expected an unfiltered query, got %v", request.Query)
+		}
+	})
+}
+
+func TestDocumentStatusFromSource(t *testing.T) {
+	tests := []struct {
+		source   map[string]interface{}
+		expected models.DocumentStatus
+	}{
+		{map[string]interface{}{}, models.DocumentStatusActive},
+		{map[string]interface{}{"status": float64(1)}, models.DocumentStatusArchived},
+		{map[string]interface{}{"status": "2"}, models.DocumentStatusDeleted},
+		{map[string]interface{}{"status": float64(9)}, models.DocumentStatusActive},
+	}
+	for _, tt := range tests {
+		if status := DocumentStatusFromSource(tt.source); status != tt.expected {
+			t.Errorf("DocumentStatusFromSource(%v) = %s, expected %s", tt.source, status, tt.expected)
+		}
+	}
+}
+
+func TestSearchStatusesContext(t *testing.T) {
+	if statuses := searchStatuses(context.Background()); statuses != nil {
+		t.Errorf("Expected no statuses, got %v", statuses)
+	}
+	ctx := WithSearchStatuses(context.Background(), models.DocumentStatuses)
+	if statuses := searchStatuses(ctx); len(statuses) != 3 {
+		t.Errorf("Expected every status, got %v", statuses)
+	}
+}
+
+func TestSetDocumentStatus(t *testing.T) {
+	var queries []string
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		query := r.PostForm.Get("query")
+		queries = append(queries, query)
+		w.WriteHeader(200)
+		if strings.HasSuffix(query, "WHERE id = 404") {
+			w.Write([]byte(`[{"total":0,"error":"","warning":""}]`))
+			return
+		}
+		w.Write([]byte(`[{"total":1,"error":"","warning":""}]`))
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+
+	if err := client.SetDocumentStatus(5, models.DocumentStatusArchived); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{
+		"UPDATE documents SET status = 1 WHERE id = 5",
+		"UPDATE documents_vector SET status = 1 WHERE id = 5",
+	}
+	if !reflect.DeepEqual(queries, expected) {
+		t.Errorf("Unexpected queries: %v", queries)
+	}
+
+	if err := client.SetDocumentStatus(404, models.DocumentStatusDeleted); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("Expected ErrDocumentNotFound, got %v", err)
+	}
+	if err := client.SetDocumentStatus(5, "hidden"); err == nil {
+		t.Error("Expected error for an invalid status")
+	}
+}
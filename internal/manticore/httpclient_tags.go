@@ -0,0 +1,207 @@
+package manticore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// TagFacetSize is the number of most frequent tags returned in the tag facet
+const TagFacetSize = 20
+
+// TagFilter restricts searches to documents with the given tags
+type TagFilter struct {
+	Tags     []string // Normalized tags, empty matches every document
+	MatchAll bool     // Require every tag instead of any of them
+}
+
+// Matches reports whether a document passes the filter
+func (f TagFilter) Matches(doc *models.Document) bool {
+	if len(f.Tags) == 0 {
+		return true
+	}
+	for _, tag := range f.Tags {
+		has := false
+		for _, docTag := range doc.Tags {
+			if docTag == tag {
+				has = true
+				break
+			}
+		}
+		if has && !f.MatchAll {
+			return true
+		}
+		if !has && f.MatchAll {
+			return false
+		}
+	}
+	return f.MatchAll
+}
+
+// Apply adds the tag filter to a search request (see addFilter). Any-of filters use a single in
+// clause; all-of filters add one clause per tag.
+func (f TagFilter) Apply(request SearchRequest) SearchRequest {
+	if len(f.Tags) == 0 || request.Query == nil {
+		return request
+	}
+	if !f.MatchAll {
+		return addFilter(request, map[string]interface{}{"in": map[string]interface{}{"tags": f.Tags}})
+	}
+	for _, tag := range f.Tags {
+		request = addFilter(request, map[string]interface{}{"in": map[string]interface{}{"tags": []string{tag}}})
+	}
+	return request
+}
+
+// sqlCondition returns the WHERE condition for Count
+func (f TagFilter) sqlCondition(field string) string {
+	values := make([]string, len(f.Tags))
+	for i, tag := range f.Tags {
+		values[i] = "'" + escapeSQLString(tag) + "'"
+	}
+	if !f.MatchAll {
+		return fmt.Sprintf("IN(%s, %s)", field, strings.Join(values, ", "))
+	}
+	conditions := make([]string, len(values))
+	for i, value := range values {
+		conditions[i] = fmt.Sprintf("IN(%s, %s)", field, value)
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// tagFilterKey is the context key for WithTagFilter
+type tagFilterKey struct{}
+
+// WithTagFilter returns a context whose AI searches apply filter, see WithSearchStatuses
+func WithTagFilter(ctx context.Context, filter TagFilter) context.Context {
+	return context.WithValue(ctx, tagFilterKey{}, filter)
+}
+
+// tagFilter returns the filter set with WithTagFilter
+func tagFilter(ctx context.Context) TagFilter {
+	filter, _ := ctx.Value(tagFilterKey{}).(TagFilter)
+	return filter
+}
+
+// WithTagFacet asks Manticore to count the most frequent tags of the matching documents
+func WithTagFacet(request SearchRequest) SearchRequest {
+	aggs := make(map[string]interface{}, len(request.Aggs)+1)
+	for name, agg := range request.Aggs {
+		aggs[name] = agg
+	}
+	aggs[models.FacetTags] = map[string]interface{}{
+		"terms": map[string]interface{}{"field": "tags", "size": TagFacetSize},
+	}
+	request.Aggs = aggs
+	return request
+}
+
+// FacetsFromResponse converts the aggregations of a search response to facets, nil when there are
+// none. Empty histogram buckets are left out and histograms keep their MaxHistogramBuckets highest
+// buckets.
+func FacetsFromResponse(response *SearchResponse) map[string][]models.FacetValue {
+	if response == nil || len(response.Aggregations) == 0 {
+		return nil
+	}
+
+	facets := make(map[string][]models.FacetValue, len(response.Aggregations))
+	for name, aggregation := range response.Aggregations {
+		values := make([]models.FacetValue, 0, len(aggregation.Buckets))
+		for _, bucket := range aggregation.Buckets {
+			if bucket.DocCount > 0 {
+				values = append(values, models.FacetValue{Value: facetValueKey(bucket.Key), Count: bucket.DocCount})
+			}
+		}
+		if name != models.FacetTags {
+			values = limitHistogram(values)
+		}
+		facets[name] = values
+	}
+	return facets
+}
+
+// TagFacet counts the tags of documents, most frequent first, keeping at most TagFacetSize values.
+// It is used by modes that rank documents in the service rather than in Manticore.
+func TagFacet(documents []*models.Document) []models.FacetValue {
+	counts := make(map[string]int)
+	for _, doc := range documents {
+		for _, tag := range doc.Tags {
+			counts[tag]++
+		}
+	}
+
+	values := make([]models.FacetValue, 0, len(counts))
+	for tag, count := range counts {
+		values = append(values, models.FacetValue{Value: tag, Count: count})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+	if len(values) > TagFacetSize {
+		values = values[:TagFacetSize]
+	}
+	return values
+}
+
+// DocumentTagsFromSource decodes the tags attribute of a hit
+func DocumentTagsFromSource(source map[string]interface{}) []string {
+	var tags []string
+	switch value := source["tags"].(type) {
+	case []interface{}:
+		for _, tag := range value {
+			if s, ok := tag.(string); ok {
+				tags = append(tags, s)
+			}
+		}
+	case string:
+		// JSON attributes are returned as strings by some Manticore versions
+		if err := json.Unmarshal([]byte(value), &tags); err != nil {
+			return nil
+		}
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// tagsValue returns the value stored in the tags attribute, an empty array for untagged documents
+func tagsValue(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// SetDocumentTags replaces the tags of a document in both tables
+func (mc *manticoreHTTPClient) SetDocumentTags(id int64, tags []string) error {
+	encoded, err := json.Marshal(tagsValue(models.NormalizeTags(tags)))
+	if err != nil {
+		return fmt.Errorf("failed to encode tags: %v", err)
+	}
+	value := escapeSQLString(string(encoded))
+
+	response, err := mc.querySQL(fmt.Sprintf("UPDATE %s SET tags = '%s' WHERE id = %d", mc.table("documents"), value, id))
+	if err != nil {
+		return fmt.Errorf("failed to update tags of document %d: %v", id, err)
+	}
+	if response.Total == 0 {
+		return fmt.Errorf("%w: %d", ErrDocumentNotFound, id)
+	}
+
+	// The vector table only mirrors the documents table, so a missing row there is not an error
+	if _, err := mc.querySQL(fmt.Sprintf("UPDATE %s SET tags = '%s' WHERE id = %d", mc.table("documents_vector"), value, id)); err != nil && !isUnknownTableError(err) {
+		return fmt.Errorf("failed to update vector tags of document %d: %v", id, err)
+	}
+
+	log.Printf("[SCHEMA] [TAGS] Document %d tags set to %s", id, encoded)
+	return nil
+}
@@ -0,0 +1,172 @@
+package manticore
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestTagFilterMatches(t *testing.T) {
+	doc := &models.Document{Tags: []string{"go", "search"}}
+
+	tests := []struct {
+		filter   TagFilter
+		expected bool
+	}{
+		{TagFilter{}, true},
+		{TagFilter{Tags: []string{"go", "rust"}}, true},
+		{TagFilter{Tags: []string{"rust"}}, false},
+		{TagFilter{Tags: []string{"go", "search"}, MatchAll: true}, true},
+		{TagFilter{Tags: []string{"go", "rust"}, MatchAll: true}, false},
+	}
+	for _, tt := range tests {
+		if matches := tt.filter.Matches(doc); matches != tt.expected {
+			t.Errorf("%+v.Matches() = %v, expected %v", tt.filter, matches, tt.expected)
+		}
+	}
+}
+
+func TestTagFilterApply(t *testing.T) {
+	match := map[string]interface{}{"match": map[string]interface{}{"*": "form"}}
+
+	request := TagFilter{Tags: []string{"go", "search"}}.Apply(NewBasicSearchRequest("documents", "form", 10, 0))
+	expected := map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must": []map[string]interface{}{
+				match,
+				{"in": map[string]interface{}{"tags": []string{"go", "search"}}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(request.Query, expected) {
+		t.Errorf("Unexpected any-of query: %v", request.Query)
+	}
+
+	request = TagFilter{Tags: []string{"go", "search"}, MatchAll: true}.Apply(NewBasicSearchRequest("documents", "form", 10, 0))
+	expected = map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must": []map[string]interface{}{
+				{
+					"bool": map[string]interface{}{
+						"must": []map[string]interface{}{
+							match,
+							{"in": map[string]interface{}{"tags": []string{"go"}}},
+						},
+					},
+				},
+				{"in": map[string]interface{}{"tags": []string{"search"}}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(request.Query, expected) {
+		t.Errorf("Unexpected all-of query: %v", request.Query)
+	}
+}
+
+func TestBuildCountQueryTags(t *testing.T) {
+	query, err := buildCountQuery("documents", "", map[string]interface{}{"tags": TagFilter{Tags: []string{"go", "it's"}}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := `SELECT COUNT(*) AS total FROM documents WHERE IN(tags, 'go', 'it\'s')`; query != expected {
+		t.Errorf("Expected %s, got %s", expected, query)
+	}
+
+	query, err = buildCountQuery("documents", "", map[string]interface{}{"tags": TagFilter{Tags: []string{"go", "search"}, MatchAll: true}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "SELECT COUNT(*) AS total FROM documents WHERE IN(tags, 'go') AND IN(tags, 'search')"; query != expected {
+		t.Errorf("Expected %s, got %s", expected, query)
+	}
+}
+
+func TestTagFacetFromResponse(t *testing.T) {
+	payload := `{"took":1,"timed_out":false,"hits":{"total":2,"hits":[]},` +
+		`"aggregations":{"tags":{"buckets":[{"key":"go","doc_count":2},{"key":"search","doc_count":1}]}}}`
+
+	response, err := decodeSearchResponse(strings.NewReader(payload), 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string][]models.FacetValue{
+		models.FacetTags: {{Value: "go", Count: 2}, {Value: "search", Count: 1}},
+	}
+	if facets := FacetsFromResponse(response); !reflect.DeepEqual(facets, expected) {
+		t.Errorf("Unexpected facets: %v", facets)
+	}
+	if facets := FacetsFromResponse(&SearchResponse{}); facets != nil {
+		t.Errorf("Expected no facets without aggregations, got %v", facets)
+	}
+}
+
+func TestTagFacet(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, Tags: []string{"go", "search"}},
+		{ID: 2, Tags: []string{"search"}},
+		{ID: 3},
+	}
+
+	expected := []models.FacetValue{{Value: "search", Count: 2}, {Value: "go", Count: 1}}
+	if facet := TagFacet(documents); !reflect.DeepEqual(facet, expected) {
+		t.Errorf("Unexpected facet: %v", facet)
+	}
+}
+
+func TestDocumentTagsFromSource(t *testing.T) {
+	tests := []struct {
+		source   map[string]interface{}
+		expected []string
+	}{
+		{map[string]interface{}{}, nil},
+		{map[string]interface{}{"tags": []interface{}{"go", "search"}}, []string{"go", "search"}},
+		{map[string]interface{}{"tags": `["go"]`}, []string{"go"}},
+		{map[string]interface{}{"tags": []interface{}{}}, nil},
+	}
+	for _, tt := range tests {
+		if tags := DocumentTagsFromSource(tt.source); !reflect.DeepEqual(tags, tt.expected) {
+			t.Errorf("DocumentTagsFromSource(%v) = %v, expected %v", tt.source, tags, tt.expected)
+		}
+	}
+}
+
+func TestSetDocumentTags(t *testing.T) {
+	var queries []string
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		query := r.PostForm.Get("query")
+		queries = append(queries, query)
+		w.WriteHeader(200)
+		if strings.HasSuffix(query, "WHERE id = 404") {
+			w.Write([]byte(`[{"total":0,"error":"","warning":""}]`))
+			return
+		}
+		w.Write([]byte(`[{"total":1,"error":"","warning":""}]`))
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+
+	if err := client.SetDocumentTags(5, []string{" Go", "search", "go"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{
+		`UPDATE documents SET tags = '["go","search"]' WHERE id = 5`,
+		`UPDATE documents_vector SET tags = '["go","search"]' WHERE id = 5`,
+	}
+	if !reflect.DeepEqual(queries, expected) {
+		t.Errorf("Unexpected queries: %v", queries)
+	}
+
+	if err := client.SetDocumentTags(404, nil); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("Expected ErrDocumentNotFound, got %v", err)
+	}
+	if last := queries[len(queries)-1]; last != "UPDATE documents SET tags = '[]' WHERE id = 404" {
+		t.Errorf("Expected tags to be cleared, got %s", last)
+	}
+}
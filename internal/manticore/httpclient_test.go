@@ -74,7 +74,7 @@ func TestIsConnected(t *testing.T) {
 	}
 
 	// Simulate connection
-	client.isConnected = true
+	client.isConnected.Store(true)
 	if !client.IsConnected() {
 		t.Error("Client should be connected after setting isConnected to true")
 	}
@@ -168,13 +168,7 @@ func TestHealthCheck(t *testing.T) {
 		{
 			name:          "successful health check",
 			statusCode:    200,
-			responseBody:  `{"hits":{"total":0,"hits":[]}}`,
-			expectedError: false,
-		},
-		{
-			name:          "table not found (acceptable for health check)",
-			statusCode:    400,
-			responseBody:  `{"error":"table 'test_health_check' doesn't exist"}`,
+			responseBody:  `[{"columns":[{"Counter":{"type":"string"}},{"Value":{"type":"string"}}],"data":[{"Counter":"uptime","Value":"42"}],"total":1,"error":"","warning":""}]`,
 			expectedError: false,
 		},
 		{
@@ -196,11 +190,12 @@ func TestHealthCheck(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
-				if r.URL.Path != "/search" {
-					t.Errorf("Expected path /search, got %s", r.URL.Path)
+				if r.URL.Path != "/sql" {
+					t.Errorf("Expected path /sql, got %s", r.URL.Path)
 				}
-				if r.Method != "POST" {
-					t.Errorf("Expected method POST, got %s", r.Method)
+				r.ParseForm()
+				if query := r.PostForm.Get("query"); query != "SHOW STATUS" {
+					t.Errorf("Expected SHOW STATUS, got %q", query)
 				}
 
 				w.WriteHeader(tt.statusCode)
@@ -228,6 +223,58 @@ func TestHealthCheck(t *testing.T) {
 	}
 }
 
+func TestCheckHealth(t *testing.T) {
+	tests := []struct {
+		name          string
+		tables        string
+		tablesStatus  int
+		expectedState HealthState
+		missing       int
+	}{
+		{"schema present", `[{"data":[{"Table":"documents","Type":"rt"},{"Table":"documents_vector","Type":"rt"}],"error":""}]`, 200, HealthStateReady, 0},
+		{"legacy column name", `[{"data":[{"Index":"documents","Type":"rt"}],"error":""}]`, 200, HealthStateSchemaMissing, 1},
+		{"schema missing", `[{"data":[],"error":""}]`, 200, HealthStateSchemaMissing, 2},
+		{"tables unavailable", `{"error":"internal"}`, 500, HealthStateUp, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				if r.PostForm.Get("query") == "SHOW TABLES" {
+					w.WriteHeader(tt.tablesStatus)
+					w.Write([]byte(tt.tables))
+					return
+				}
+				w.WriteHeader(200)
+				w.Write([]byte(`[{"data":[],"error":""}]`))
+			})
+			defer server.Close()
+
+			report := NewHTTPClient(DefaultHTTPClientConfig(server.URL)).CheckHealth()
+
+			if report.State != tt.expectedState || !report.ServerUp || len(report.MissingTables) != tt.missing {
+				t.Errorf("Unexpected report: %+v", report)
+			}
+			if report.SchemaPresent != (tt.expectedState == HealthStateReady) {
+				t.Errorf("Unexpected schema presence: %+v", report)
+			}
+		})
+	}
+
+	t.Run("server down", func(t *testing.T) {
+		server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(503)
+		})
+		defer server.Close()
+
+		report := NewHTTPClient(DefaultHTTPClientConfig(server.URL)).CheckHealth()
+		if report.State != HealthStateDown || report.ServerUp || report.Error == "" {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+	})
+}
+
 // Test WaitForReady functionality
 func TestWaitForReady(t *testing.T) {
 	t.Run("successful wait", func(t *testing.T) {
@@ -1078,3 +1125,131 @@ func TestRequestCreationMethods(t *testing.T) {
 		}
 	})
 }
+
+// Test document counting used by the startup indexing policy
+func TestCountDocuments(t *testing.T) {
+	tests := []struct {
+		name          string
+		response      string
+		expectedCount int64
+		expectError   bool
+	}{
+		{"populated table", `[{"columns":[{"total":{"type":"long long"}}],"data":[{"total":42}],"total":1,"error":"","warning":""}]`, 42, false},
+		{"missing table", `[{"total":0,"error":"unknown local table(s) 'documents' in search request","warning":""}]`, 0, false},
+		{"other error", `[{"total":0,"error":"internal error","warning":""}]`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				if query := r.PostForm.Get("query"); query != "SELECT COUNT(*) AS total FROM documents" {
+					t.Errorf("Unexpected query: %s", query)
+				}
+				w.WriteHeader(200)
+				w.Write([]byte(tt.response))
+			})
+			defer server.Close()
+
+			client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+			count, err := client.CountDocuments("documents")
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if count != tt.expectedCount {
+				t.Errorf("Expected count %d, got %d", tt.expectedCount, count)
+			}
+		})
+	}
+}
+
+// Test table statistics reported by the status endpoint
+func TestGetIndexStats(t *testing.T) {
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.PostForm.Get("query") {
+		case "SHOW INDEX documents STATUS":
+			w.Write([]byte(`[{"columns":[{"Variable_name":{"type":"string"}},{"Value":{"type":"string"}}],"data":[` +
+				`{"Variable_name":"table_type","Value":"rt"},` +
+				`{"Variable_name":"indexed_documents","Value":"150"},` +
+				`{"Variable_name":"indexed_bytes","Value":"30000"},` +
+				`{"Variable_name":"ram_bytes","Value":"4096"},` +
+				`{"Variable_name":"disk_bytes","Value":"123456"},` +
+				`{"Variable_name":"disk_chunks","Value":"3"},` +
+				`{"Variable_name":"optimizing","Value":"1"}],"total":7,"error":"","warning":""}]`))
+		default:
+			w.Write([]byte(`[{"total":0,"error":"unknown local table(s) 'documents_vector' in search request","warning":""}]`))
+		}
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+
+	stats, err := client.GetIndexStats("documents")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !stats.Exists || stats.IndexedDocuments != 150 || stats.DiskBytes != 123456 || stats.RAMBytes != 4096 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.IndexedBytes != 30000 || stats.DiskChunks != 3 || !stats.Optimizing {
+		t.Errorf("Unexpected chunk stats: %+v", stats)
+	}
+
+	missing, err := client.GetIndexStats("documents_vector")
+	if err != nil {
+		t.Fatalf("Expected missing table to be reported without error, got %v", err)
+	}
+	if missing.Exists || missing.IndexedDocuments != 0 {
+		t.Errorf("Expected missing table stats, got %+v", missing)
+	}
+}
+
+func TestTablePrefix(t *testing.T) {
+	var queries, indexes []string
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/search" {
+			var request SearchRequest
+			json.NewDecoder(r.Body).Decode(&request)
+			indexes = append(indexes, request.Index)
+			w.Write([]byte(`{"took":1,"timed_out":false,"hits":{"total":0,"hits":[]}}`))
+			return
+		}
+		r.ParseForm()
+		queries = append(queries, r.PostForm.Get("query"))
+		w.Write([]byte(`[{"total":1,"error":"","warning":"","data":[{"total":3}]}]`))
+	})
+	defer server.Close()
+
+	config := DefaultHTTPClientConfig(server.URL)
+	config.TablePrefix = "acme_"
+	client := NewHTTPClient(config)
+
+	if err := client.SetDocumentStatus(5, models.DocumentStatusArchived); err != nil {
+		t.Fatalf("SetDocumentStatus failed: %v", err)
+	}
+	if count, err := client.CountDocuments("documents"); err != nil || count != 3 {
+		t.Fatalf("Expected 3 documents, got %d and %v", count, err)
+	}
+	if _, err := client.SearchWithRequest(NewBasicSearchRequest("documents", "form", 10, 0)); err != nil {
+		t.Fatalf("SearchWithRequest failed: %v", err)
+	}
+
+	expected := []string{
+		"UPDATE acme_documents SET status = 1 WHERE id = 5",
+		"UPDATE acme_documents_vector SET status = 1 WHERE id = 5",
+		"SELECT COUNT(*) AS total FROM acme_documents",
+	}
+	if strings.Join(queries, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected queries: %v", queries)
+	}
+	if len(indexes) != 1 || indexes[0] != "acme_documents" {
+		t.Errorf("Expected a search of acme_documents, got %v", indexes)
+	}
+}
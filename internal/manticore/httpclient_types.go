@@ -1,8 +1,11 @@
 package manticore
 
 import (
+	"context"
 	"time"
 
+	"github.com/ad/manticoresearch-go/internal/blocklist"
+	"github.com/ad/manticoresearch-go/internal/boost"
 	"github.com/ad/manticoresearch-go/internal/models"
 )
 
@@ -10,14 +13,34 @@ import (
 type ClientInterface interface {
 	// Connection management
 	WaitForReady(timeout time.Duration) error
+	WaitForReadyContext(ctx context.Context, opts ReadyOptions) error
 	HealthCheck() error
+	CheckHealth() HealthReport
 	Close() error
 	IsConnected() bool
+	GetCapabilities() *Capabilities
+	GetCircuitBreakerTransitions() []CircuitBreakerTransition
+	GetResilienceStats() ResilienceStats
+	GetRecordings() Recordings
 
 	// Schema operations
 	CreateSchema(aiConfig *models.AISearchConfig) error
+	MigrateSchema(aiConfig *models.AISearchConfig) (*MigrationResult, error)
 	ResetDatabase() error
 	TruncateTables() error
+	TruncateTable(table string) error
+	ResetTable(table string, aiConfig *models.AISearchConfig) error
+	OptimizeTable(table string) error
+	CountDocuments(table string) (int64, error)
+	GetIndexStats(table string) (*IndexStats, error)
+	EmbeddingModels(table string) ([]EmbeddingModel, error)
+	DocumentIDs(table string) ([]int64, error)
+	GetDocumentHashes() (map[int64]string, error)
+	GetDocumentTimes() (map[int64]int64, error)
+	DeleteDocuments(table string, ids []int64) error
+	Backup(path string) (*BackupResult, error)
+	ReembedDocuments(ctx context.Context, aiConfig *models.AISearchConfig, progress func(ReembedProgress)) (*ReembedResult, error)
+	DropStaleDocumentsTables() ([]DroppedTable, error)
 
 	// Document operations
 	IndexDocument(doc *models.Document, vector []float64) error
@@ -26,35 +49,75 @@ type ClientInterface interface {
 	// Search operations (for ClientInterface compatibility)
 	Search(query string, mode models.SearchMode, page, pageSize int) (*models.SearchResponse, error)
 	GetAllDocuments() ([]*models.Document, error)
+	GetDocument(id int64) (*models.Document, error)
 	GetAllDocumentsWithVectors() ([]*models.Document, [][]float64, error)
 
 	// HTTP-specific search operations
 	SearchWithRequest(request SearchRequest) (*SearchResponse, error)
+	SearchWithContext(ctx context.Context, request SearchRequest) (*SearchResponse, error)
+	GetAllDocumentsWithVectorsContext(ctx context.Context) ([]*models.Document, [][]float64, error)
+	GetOperationTimeouts() OperationTimeouts
+	Count(query string, filters map[string]interface{}) (int64, error)
+	SetDocumentStatus(id int64, status models.DocumentStatus) error
+	SetDocumentTags(id int64, tags []string) error
 
 	// AI search operations
 	AISearch(query string, model string, limit, offset int) (*SearchResponse, error)
+	AISearchWithContext(ctx context.Context, query string, model string, limit, offset int) (*SearchResponse, error)
 	GenerateEmbedding(text string, model string) ([]float64, error)
 }
 
+// ResilienceStats combines circuit breaker and retry statistics for operators
+type ResilienceStats struct {
+	CircuitBreaker        CircuitBreakerStats                    `json:"circuit_breaker"`
+	CircuitBreakerByClass map[OperationClass]CircuitBreakerStats `json:"circuit_breaker_by_class,omitempty"`
+	Retry                 RetryStats                             `json:"retry"`
+	RetryByClass          map[OperationClass]RetryStats          `json:"retry_by_class,omitempty"`
+	Transitions           []CircuitBreakerTransition             `json:"transitions"`
+	FaultInjection        *FaultStats                            `json:"fault_injection,omitempty"` // nil unless faults are injected
+	ConnectionPool        PoolStats                              `json:"connection_pool"`
+}
+
 // HTTPClientConfig holds configuration for the HTTP client
 type HTTPClientConfig struct {
-	BaseURL              string
-	Timeout              time.Duration
-	MaxIdleConns         int
-	MaxIdleConnsPerHost  int
-	IdleConnTimeout      time.Duration
-	RetryConfig          RetryConfig
-	CircuitBreakerConfig CircuitBreakerConfig
-	BulkConfig           BulkConfig
+	BaseURL               string
+	Timeout               time.Duration
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	IdleConnTimeout       time.Duration
+	MaxConnsPerHost       int            // connections per host, active or idle, beyond which requests wait; 0 for no limit
+	EnableHTTP2           bool           // attempt HTTP/2, which Manticore only gets through a TLS proxy offering it
+	DisableKeepAlives     bool           // open a connection per request
+	DisableCompression    bool           // don't ask for gzip-compressed responses
+	DNSRefreshInterval    time.Duration  // how often the Manticore host is re-resolved to recycle connections to old addresses; 0 disables it
+	NetworkProfile        NetworkProfile // the preset applied by ApplyNetworkProfile, empty when none was
+	RetryConfig           RetryConfig
+	CircuitBreakerConfig  CircuitBreakerConfig
+	BulkConfig            BulkConfig
+	SQLTransport          string // "http" (default) or "mysql"
+	MySQLAddr             string // host:port of the MySQL protocol listener, used when SQLTransport is "mysql"
+	CircuitBreakerWebhook WebhookConfig
+	Timeouts              OperationTimeouts    // per-operation deadlines; unset values use DefaultOperationTimeouts
+	MaxResponseSize       int64                // largest response body read from Manticore; zero uses DefaultMaxResponseSize
+	TablePrefix           string               // prepended to every table name, isolating the tables of a tenant
+	Faults                FaultConfig          // faults injected into requests, for staging; the zero value injects none
+	Recording             RecordingConfig      // requests and responses recorded for debugging; the zero value records none
+	EmbeddingCache        EmbeddingCacheConfig // query embeddings reused by AI searches; the zero value caches none
+	// RetryPolicies overrides the retry policy of an operation class. Unset fields keep the class default.
+	RetryPolicies map[OperationClass]RetryConfig
 }
 
 // BulkConfig holds configuration for bulk operations
 type BulkConfig struct {
 	BatchSize           int           // Number of documents per batch
-	MaxConcurrentBatch  int           // Maximum concurrent batch operations
+	MaxConcurrentBatch  int           // Maximum concurrent batch operations, and bulk requests in flight
 	StreamingThreshold  int           // Threshold for using streaming operations
 	ProgressLogInterval int           // Log progress every N documents
 	BatchTimeout        time.Duration // Timeout for individual batch operations
+	Compression         bool          // Gzip bulk request bodies; Manticore must accept Content-Encoding: gzip
+	BatchDelay          time.Duration // Minimum pause between sequential batches; 0 for none
+	FallbackDelay       time.Duration // Minimum pause between documents indexed individually after a failed batch; 0 for none
+	PacingFactor        float64       // Pauses grow to this fraction of the backend's average latency; 0 keeps the fixed delays
 }
 
 // DefaultBulkConfig returns a default bulk configuration for performance
@@ -65,6 +128,9 @@ func DefaultBulkConfig() BulkConfig {
 		StreamingThreshold:  1000,
 		ProgressLogInterval: 500,
 		BatchTimeout:        60 * time.Second,
+		BatchDelay:          100 * time.Millisecond,
+		FallbackDelay:       50 * time.Millisecond,
+		PacingFactor:        0.1,
 	}
 }
 
@@ -79,36 +145,87 @@ func DefaultHTTPClientConfig(baseURL string) HTTPClientConfig {
 		RetryConfig:          DefaultRetryConfig(),
 		CircuitBreakerConfig: DefaultCircuitBreakerConfig(),
 		BulkConfig:           DefaultBulkConfig(),
+		Timeouts:             DefaultOperationTimeouts(),
+		MaxResponseSize:      DefaultMaxResponseSize,
 	}
 }
 
 // JSON API request/response types
 type SearchRequest struct {
-	Index  string                 `json:"index"`
-	Query  map[string]interface{} `json:"query"`
-	Limit  int32                  `json:"limit,omitempty"`
-	Offset int32                  `json:"offset,omitempty"`
+	Index  string                   `json:"index"`
+	Query  map[string]interface{}   `json:"query"`
+	Limit  int32                    `json:"limit,omitempty"`
+	Offset int32                    `json:"offset,omitempty"`
+	Source *SourceFilter            `json:"_source,omitempty"` // Nil returns every stored field
+	Sort   []map[string]interface{} `json:"sort,omitempty"`    // Nil orders hits by relevance
+	Aggs   map[string]interface{}   `json:"aggs,omitempty"`    // Named aggregations, see WithTagFacet and HistogramFacets
+	// Options are search options such as expand_keywords and fuzzy, see WithSpellTolerance
+	Options map[string]interface{} `json:"options,omitempty"`
+	// Expressions are named values computed for each hit that filters can test, see RegexFilter
+	Expressions map[string]string `json:"expressions,omitempty"`
+}
+
+// SourceFilter selects which stored fields Manticore returns in each hit's _source
+type SourceFilter struct {
+	Includes []string `json:"includes,omitempty"`
+	Excludes []string `json:"excludes,omitempty"`
 }
 
 type SearchResponse struct {
-	Took     int  `json:"took"`
-	TimedOut bool `json:"timed_out"`
-	Hits     struct {
-		Total         int32  `json:"total"`
-		TotalRelation string `json:"total_relation"`
-		Hits          []struct {
-			Index  string                 `json:"_index"`
-			ID     int64                  `json:"_id"`
-			Score  float32                `json:"_score"`
-			Source map[string]interface{} `json:"_source"`
-		} `json:"hits"`
-	} `json:"hits"`
+	Took         int                    `json:"took"`
+	TimedOut     bool                   `json:"timed_out"`
+	Hits         SearchHits             `json:"hits"`
+	Aggregations map[string]Aggregation `json:"aggregations,omitempty"`
+}
+
+// Aggregation is the result of a terms aggregation
+type Aggregation struct {
+	Buckets []AggregationBucket `json:"buckets"`
+}
+
+// AggregationBucket is the number of matching documents with one value of the aggregated field
+type AggregationBucket struct {
+	Key      interface{} `json:"key"`
+	DocCount int         `json:"doc_count"`
+}
+
+// Values of SearchHits.TotalRelation
+const (
+	TotalRelationEqual   = "eq"  // Total is exact
+	TotalRelationAtLeast = "gte" // Total is a lower bound because matching stopped early
+)
+
+// SearchHits is the hits section of a search response
+type SearchHits struct {
+	Total         int32       `json:"total"`
+	TotalRelation string      `json:"total_relation"`
+	Hits          []SearchHit `json:"hits"`
+}
+
+// SearchHit is a single matched document in a search response
+type SearchHit struct {
+	Index  string                 `json:"_index"`
+	ID     int64                  `json:"_id"`
+	Score  float32                `json:"_score"`
+	Source map[string]interface{} `json:"_source"`
 }
 
 type SQLRequest struct {
 	Query string `json:"query"`
 }
 
+// IndexStats describes the size of a Manticore table as reported by SHOW INDEX STATUS
+type IndexStats struct {
+	Table            string `json:"table"`
+	Exists           bool   `json:"exists"`
+	IndexedDocuments int64  `json:"indexed_documents"`
+	IndexedBytes     int64  `json:"indexed_bytes"`
+	DiskBytes        int64  `json:"disk_bytes"`
+	RAMBytes         int64  `json:"ram_bytes"`
+	DiskChunks       int64  `json:"disk_chunks"`
+	Optimizing       bool   `json:"optimizing"` // Whether Manticore is merging disk chunks
+}
+
 type SQLResponse struct {
 	Data  []map[string]interface{} `json:"data,omitempty"`
 	Total int                      `json:"total,omitempty"`
@@ -168,5 +285,8 @@ type EmbeddingResponse struct {
 
 // SearchResultProcessor handles search result processing and ranking
 type SearchResultProcessor struct {
-	client ClientInterface
+	client      ClientInterface
+	boosts      boost.Rules          // Score factors applied after the mode's ranking
+	blocklist   *blocklist.Blocklist // Documents never returned
+	lengthSlope float64              // Slope of pivoted length normalization, 0 for none
 }
@@ -4,8 +4,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ad/manticoresearch-go/internal/models"
 )
@@ -190,3 +194,83 @@ func TestBatchSizeSelection(t *testing.T) {
 		})
 	}
 }
+
+// concurrencyServer answers bulk requests after holding each for delay, recording the most
+// requests in flight at once and the indexes they targeted
+type concurrencyServer struct {
+	mutex    sync.Mutex
+	inFlight int
+	peak     int
+	indexes  []string
+}
+
+func (s *concurrencyServer) handle(delay time.Duration, status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var line struct {
+			Replace struct {
+				Index string `json:"index"`
+			} `json:"replace"`
+		}
+		json.NewDecoder(r.Body).Decode(&line)
+
+		s.mutex.Lock()
+		s.inFlight++
+		s.peak = max(s.peak, s.inFlight)
+		s.indexes = append(s.indexes, line.Replace.Index)
+		s.mutex.Unlock()
+
+		time.Sleep(delay)
+
+		s.mutex.Lock()
+		s.inFlight--
+		s.mutex.Unlock()
+
+		w.WriteHeader(status)
+		w.Write([]byte(`{"items":[],"errors":false}`))
+	}
+}
+
+func TestBulkIndexDocumentsConcurrentSubmission(t *testing.T) {
+	documents := []*models.Document{{ID: 1, Title: "Doc 1"}, {ID: 2, Title: "Doc 2"}}
+	vectors := [][]float64{{0.1}, {0.2}}
+
+	for _, test := range []struct {
+		maxConcurrent int
+		expectedPeak  int
+	}{
+		{maxConcurrent: 3, expectedPeak: 2},
+		{maxConcurrent: 1, expectedPeak: 1},
+	} {
+		recorder := &concurrencyServer{}
+		server := createMockServer(t, recorder.handle(100*time.Millisecond, http.StatusOK))
+
+		config := DefaultHTTPClientConfig(server.URL)
+		config.BulkConfig.MaxConcurrentBatch = test.maxConcurrent
+		client := NewHTTPClient(config).(*manticoreHTTPClient)
+
+		if err := client.bulkIndexDocuments(documents, vectors); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		server.Close()
+
+		sort.Strings(recorder.indexes)
+		if !reflect.DeepEqual(recorder.indexes, []string{"documents", "documents_vector"}) {
+			t.Errorf("Expected a unified and a vector batch, got %v", recorder.indexes)
+		}
+		if recorder.peak != test.expectedPeak {
+			t.Errorf("Expected %d concurrent submissions with MaxConcurrentBatch %d, got %d", test.expectedPeak, test.maxConcurrent, recorder.peak)
+		}
+	}
+}
+
+func TestBulkIndexDocumentsAggregatesErrors(t *testing.T) {
+	recorder := &concurrencyServer{}
+	server := createMockServer(t, recorder.handle(0, http.StatusBadRequest))
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL)).(*manticoreHTTPClient)
+	err := client.bulkIndexDocuments([]*models.Document{{ID: 1}}, [][]float64{{0.1}})
+	if err == nil || !strings.Contains(err.Error(), "bulk unified indexing") || !strings.Contains(err.Error(), "bulk vector indexing") {
+		t.Errorf("Expected both batch errors, got %v", err)
+	}
+}
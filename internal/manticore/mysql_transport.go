@@ -0,0 +1,488 @@
+package manticore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// SQL transport names accepted by HTTPClientConfig.SQLTransport
+const (
+	SQLTransportHTTP  = "http"
+	SQLTransportMySQL = "mysql"
+)
+
+// MySQL protocol constants used by the minimal client below
+const (
+	mysqlClientLongPassword     = 0x00000001
+	mysqlClientProtocol41       = 0x00000200
+	mysqlClientTransactions     = 0x00002000
+	mysqlClientSecureConnection = 0x00008000
+	mysqlClientPluginAuth       = 0x00080000
+
+	mysqlComQuit  = 0x01
+	mysqlComQuery = 0x03
+	mysqlComPing  = 0x0e
+
+	mysqlPacketOK  = 0x00
+	mysqlPacketEOF = 0xfe
+	mysqlPacketErr = 0xff
+
+	mysqlCharsetUTF8   = 33
+	mysqlMaxPacketSize = 1<<24 - 1
+	mysqlNativeAuth    = "mysql_native_password"
+)
+
+// MySQLError is an error packet returned by the server
+type MySQLError struct {
+	Code     uint16
+	SQLState string
+	Message  string
+}
+
+func (e *MySQLError) Error() string {
+	if e.SQLState != "" {
+		return fmt.Sprintf("mysql error %d (%s): %s", e.Code, e.SQLState, e.Message)
+	}
+	return fmt.Sprintf("mysql error %d: %s", e.Code, e.Message)
+}
+
+// mysqlTransport runs SQL statements over the MySQL wire protocol (Manticore port 9306).
+// It keeps a single connection that is re-established after any I/O failure.
+type mysqlTransport struct {
+	addr        string
+	dialTimeout time.Duration
+
+	mutex sync.Mutex
+	conn  *mysqlConn
+}
+
+// newMySQLTransport creates a transport for the given host:port address
+func newMySQLTransport(addr string, dialTimeout time.Duration) *mysqlTransport {
+	if dialTimeout <= 0 {
+		dialTimeout = 15 * time.Second
+	}
+	return &mysqlTransport{
+		addr:        addr,
+		dialTimeout: dialTimeout,
+	}
+}
+
+// Query executes a statement and returns its result set (empty for statements without rows)
+func (t *mysqlTransport) Query(ctx context.Context, query string) (*SQLResponse, error) {
+	var response *SQLResponse
+	err := t.withConn(ctx, func(conn *mysqlConn) error {
+		var err error
+		response, err = conn.query(query)
+		return err
+	})
+	return response, err
+}
+
+// Ping checks that the server answers COM_PING
+func (t *mysqlTransport) Ping(ctx context.Context) error {
+	return t.withConn(ctx, func(conn *mysqlConn) error {
+		return conn.ping()
+	})
+}
+
+// Close closes the underlying connection
+func (t *mysqlTransport) Close() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.close()
+	t.conn = nil
+	return err
+}
+
+func (t *mysqlTransport) withConn(ctx context.Context, fn func(conn *mysqlConn) error) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.conn == nil {
+		conn, err := dialMySQL(ctx, t.addr, t.dialTimeout)
+		if err != nil {
+			return err
+		}
+		t.conn = conn
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Time{}
+	}
+	t.conn.netConn.SetDeadline(deadline)
+
+	err := fn(t.conn)
+
+	// Server-side SQL errors leave the connection usable; anything else may have desynchronised the stream
+	var mysqlErr *MySQLError
+	if err != nil && !errors.As(err, &mysqlErr) {
+		t.conn.netConn.Close()
+		t.conn = nil
+	}
+	return err
+}
+
+// mysqlConn is a single authenticated MySQL protocol connection
+type mysqlConn struct {
+	netConn  net.Conn
+	reader   *bufio.Reader
+	sequence byte
+}
+
+func dialMySQL(ctx context.Context, addr string, timeout time.Duration) (*mysqlConn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MySQL endpoint %s: %v", addr, err)
+	}
+
+	conn := &mysqlConn{
+		netConn: netConn,
+		reader:  bufio.NewReader(netConn),
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		netConn.SetDeadline(deadline)
+	} else {
+		netConn.SetDeadline(time.Now().Add(timeout))
+	}
+
+	if err := conn.handshake(); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("MySQL handshake with %s failed: %v", addr, err)
+	}
+
+	log.Printf("[SQL] [MYSQL] Connected to %s", addr)
+	return conn, nil
+}
+
+func (c *mysqlConn) close() error {
+	c.sequence = 0
+	c.writePacket([]byte{mysqlComQuit})
+	return c.netConn.Close()
+}
+
+// handshake reads the server greeting and authenticates with an empty password.
+// Manticore does not enforce credentials, so no user configuration is required.
+func (c *mysqlConn) handshake() error {
+	greeting, err := c.readPacket()
+	if err != nil {
+		return err
+	}
+	if len(greeting) > 0 && greeting[0] == mysqlPacketErr {
+		return parseMySQLError(greeting)
+	}
+	if len(greeting) < 1 || greeting[0] != 10 {
+		return fmt.Errorf("unsupported protocol version")
+	}
+
+	pos := 1
+	end := bytes.IndexByte(greeting[pos:], 0)
+	if end < 0 {
+		return fmt.Errorf("malformed server greeting")
+	}
+	pos += end + 1 // server version
+
+	if len(greeting) < pos+4+8+1 {
+		return fmt.Errorf("malformed server greeting")
+	}
+	pos += 4 + 8 + 1 // connection id, auth data part 1, filler
+
+	// The password is always empty, so the salt is skipped and only the plugin name is echoed back
+	plugin := mysqlNativeAuth
+	if len(greeting) >= pos+2+1+2+2+1+10 {
+		pos += 2 + 1 + 2 + 2 // capability lower, charset, status, capability upper
+		authDataLen := int(greeting[pos])
+		pos += 1 + 10
+		part2Len := authDataLen - 8
+		if part2Len < 13 {
+			part2Len = 13
+		}
+		if len(greeting) >= pos+part2Len {
+			pos += part2Len
+			if end := bytes.IndexByte(greeting[pos:], 0); end > 0 {
+				plugin = string(greeting[pos : pos+end])
+			}
+		}
+	}
+
+	capabilities := uint32(mysqlClientLongPassword | mysqlClientProtocol41 | mysqlClientTransactions |
+		mysqlClientSecureConnection | mysqlClientPluginAuth)
+
+	response := make([]byte, 0, 64)
+	response = binary.LittleEndian.AppendUint32(response, capabilities)
+	response = binary.LittleEndian.AppendUint32(response, mysqlMaxPacketSize)
+	response = append(response, mysqlCharsetUTF8)
+	response = append(response, make([]byte, 23)...)
+	response = append(response, 0) // empty user name
+	response = append(response, 0) // empty auth response
+	response = append(response, plugin...)
+	response = append(response, 0)
+
+	if err := c.writePacket(response); err != nil {
+		return err
+	}
+
+	result, err := c.readPacket()
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(result) > 0 && result[0] == mysqlPacketOK:
+		return nil
+	case len(result) > 0 && result[0] == mysqlPacketErr:
+		return parseMySQLError(result)
+	case len(result) > 0 && result[0] == mysqlPacketEOF:
+		// Auth switch request: an empty password scrambles to an empty response with any salt
+		rest := result[1:]
+		end := bytes.IndexByte(rest, 0)
+		if end < 0 || string(rest[:end]) != mysqlNativeAuth {
+			return fmt.Errorf("unsupported authentication plugin requested by server")
+		}
+		if err := c.writePacket(nil); err != nil {
+			return err
+		}
+		result, err = c.readPacket()
+		if err != nil {
+			return err
+		}
+		if len(result) > 0 && result[0] == mysqlPacketErr {
+			return parseMySQLError(result)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unexpected authentication response")
+	}
+}
+
+func (c *mysqlConn) ping() error {
+	c.sequence = 0
+	if err := c.writePacket([]byte{mysqlComPing}); err != nil {
+		return err
+	}
+	packet, err := c.readPacket()
+	if err != nil {
+		return err
+	}
+	if len(packet) > 0 && packet[0] == mysqlPacketErr {
+		return parseMySQLError(packet)
+	}
+	return nil
+}
+
+// query sends COM_QUERY and decodes a text protocol result set into the same shape as /sql?mode=raw
+func (c *mysqlConn) query(query string) (*SQLResponse, error) {
+	c.sequence = 0
+	if err := c.writePacket(append([]byte{mysqlComQuery}, query...)); err != nil {
+		return nil, err
+	}
+
+	packet, err := c.readPacket()
+	if err != nil {
+		return nil, err
+	}
+	if len(packet) == 0 {
+		return nil, fmt.Errorf("empty response packet")
+	}
+	switch packet[0] {
+	case mysqlPacketOK:
+		affected, _, _ := readLengthEncodedInt(packet[1:])
+		return &SQLResponse{Total: int(affected)}, nil
+	case mysqlPacketErr:
+		return nil, parseMySQLError(packet)
+	}
+
+	columnCount, _, ok := readLengthEncodedInt(packet)
+	if !ok {
+		return nil, fmt.Errorf("malformed column count")
+	}
+
+	columns := make([]string, 0, columnCount)
+	for i := uint64(0); i < columnCount; i++ {
+		definition, err := c.readPacket()
+		if err != nil {
+			return nil, err
+		}
+		name, err := parseColumnName(definition)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+	}
+	if packet, err = c.readPacket(); err != nil {
+		return nil, err
+	} else if !isEOFPacket(packet) {
+		return nil, fmt.Errorf("expected EOF after column definitions")
+	}
+
+	response := &SQLResponse{Data: []map[string]interface{}{}}
+	for {
+		packet, err := c.readPacket()
+		if err != nil {
+			return nil, err
+		}
+		if isEOFPacket(packet) {
+			break
+		}
+		if len(packet) > 0 && packet[0] == mysqlPacketErr {
+			return nil, parseMySQLError(packet)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		pos := 0
+		for _, column := range columns {
+			if pos >= len(packet) {
+				return nil, fmt.Errorf("truncated row packet")
+			}
+			if packet[pos] == 0xfb {
+				row[column] = nil
+				pos++
+				continue
+			}
+			value, n, ok := readLengthEncodedString(packet[pos:])
+			if !ok {
+				return nil, fmt.Errorf("truncated row packet")
+			}
+			row[column] = value
+			pos += n
+		}
+		response.Data = append(response.Data, row)
+	}
+
+	response.Total = len(response.Data)
+	return response, nil
+}
+
+func (c *mysqlConn) readPacket() ([]byte, error) {
+	var payload []byte
+	for {
+		var header [4]byte
+		if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+			return nil, fmt.Errorf("failed to read MySQL packet: %v", err)
+		}
+		length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+		c.sequence = header[3] + 1
+
+		chunk := make([]byte, length)
+		if _, err := io.ReadFull(c.reader, chunk); err != nil {
+			return nil, fmt.Errorf("failed to read MySQL packet: %v", err)
+		}
+		payload = append(payload, chunk...)
+
+		// Payloads of exactly 2^24-1 bytes continue in the next packet
+		if length < mysqlMaxPacketSize {
+			return payload, nil
+		}
+	}
+}
+
+func (c *mysqlConn) writePacket(payload []byte) error {
+	for {
+		length := len(payload)
+		if length > mysqlMaxPacketSize {
+			length = mysqlMaxPacketSize
+		}
+
+		packet := make([]byte, 4+length)
+		packet[0] = byte(length)
+		packet[1] = byte(length >> 8)
+		packet[2] = byte(length >> 16)
+		packet[3] = c.sequence
+		copy(packet[4:], payload[:length])
+		c.sequence++
+
+		if _, err := c.netConn.Write(packet); err != nil {
+			return fmt.Errorf("failed to write MySQL packet: %v", err)
+		}
+
+		payload = payload[length:]
+		if length < mysqlMaxPacketSize {
+			return nil
+		}
+	}
+}
+
+func parseMySQLError(packet []byte) error {
+	if len(packet) < 3 {
+		return &MySQLError{Message: "malformed error packet"}
+	}
+	mysqlErr := &MySQLError{Code: binary.LittleEndian.Uint16(packet[1:3])}
+	rest := packet[3:]
+	if len(rest) >= 6 && rest[0] == '#' {
+		mysqlErr.SQLState = string(rest[1:6])
+		rest = rest[6:]
+	}
+	mysqlErr.Message = string(rest)
+	return mysqlErr
+}
+
+func isEOFPacket(packet []byte) bool {
+	return len(packet) > 0 && packet[0] == mysqlPacketEOF && len(packet) < 9
+}
+
+// parseColumnName extracts the column alias from a ColumnDefinition41 packet
+func parseColumnName(packet []byte) (string, error) {
+	pos := 0
+	// catalog, schema, table, org_table precede the name
+	for i := 0; i < 4; i++ {
+		_, n, ok := readLengthEncodedString(packet[pos:])
+		if !ok {
+			return "", fmt.Errorf("malformed column definition")
+		}
+		pos += n
+	}
+	name, _, ok := readLengthEncodedString(packet[pos:])
+	if !ok {
+		return "", fmt.Errorf("malformed column definition")
+	}
+	return name, nil
+}
+
+func readLengthEncodedInt(b []byte) (uint64, int, bool) {
+	if len(b) == 0 {
+		return 0, 0, false
+	}
+	switch b[0] {
+	case 0xfc:
+		if len(b) < 3 {
+			return 0, 0, false
+		}
+		return uint64(binary.LittleEndian.Uint16(b[1:3])), 3, true
+	case 0xfd:
+		if len(b) < 4 {
+			return 0, 0, false
+		}
+		return uint64(b[1]) | uint64(b[2])<<8 | uint64(b[3])<<16, 4, true
+	case 0xfe:
+		if len(b) < 9 {
+			return 0, 0, false
+		}
+		return binary.LittleEndian.Uint64(b[1:9]), 9, true
+	case 0xfb, 0xff:
+		return 0, 1, false
+	default:
+		return uint64(b[0]), 1, true
+	}
+}
+
+func readLengthEncodedString(b []byte) (string, int, bool) {
+	length, n, ok := readLengthEncodedInt(b)
+	if !ok || uint64(len(b)-n) < length {
+		return "", 0, false
+	}
+	end := n + int(length)
+	return string(b[n:end]), end, true
+}
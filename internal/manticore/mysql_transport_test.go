@@ -0,0 +1,240 @@
+package manticore
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMySQLServer implements just enough of the MySQL protocol to exercise mysqlTransport
+type fakeMySQLServer struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	queries  []string
+}
+
+func newFakeMySQLServer(t *testing.T) *fakeMySQLServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &fakeMySQLServer{listener: listener}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *fakeMySQLServer) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeMySQLServer) receivedQueries() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.queries...)
+}
+
+func (s *fakeMySQLServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeMySQLServer) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	greeting := []byte{10}
+	greeting = append(greeting, "13.11.0 test\x00"...)
+	greeting = append(greeting, 1, 0, 0, 0)           // connection id
+	greeting = append(greeting, "abcdefgh"...)        // auth data part 1
+	greeting = append(greeting, 0)                    // filler
+	greeting = append(greeting, 0x00, 0x82, 33, 2, 0) // capability lower, charset, status
+	greeting = append(greeting, 0x08, 0x00, 21)       // capability upper, auth data length
+	greeting = append(greeting, make([]byte, 10)...)
+	greeting = append(greeting, "ijklmnopqrst\x00"...)
+	greeting = append(greeting, "mysql_native_password\x00"...)
+	writeFakePacket(conn, 0, greeting)
+
+	if _, _, err := readFakePacket(reader); err != nil {
+		return
+	}
+	writeFakePacket(conn, 2, []byte{0x00, 0, 0, 2, 0, 0, 0})
+
+	for {
+		payload, _, err := readFakePacket(reader)
+		if err != nil || len(payload) == 0 {
+			return
+		}
+
+		switch payload[0] {
+		case mysqlComQuit:
+			return
+		case mysqlComPing:
+			writeFakePacket(conn, 1, []byte{0x00, 0, 0, 2, 0, 0, 0})
+		case mysqlComQuery:
+			query := string(payload[1:])
+			s.mutex.Lock()
+			s.queries = append(s.queries, query)
+			s.mutex.Unlock()
+
+			switch {
+			case strings.HasPrefix(query, "SHOW STATUS"):
+				writeFakeResultSet(conn, []string{"Counter", "Value"}, [][]string{{"version", "13.11.0 test"}})
+			case strings.HasPrefix(query, "BROKEN"):
+				errPacket := []byte{0xff, 0x28, 0x04, '#', '4', '2', '0', '0', '0'}
+				errPacket = append(errPacket, "syntax error"...)
+				writeFakePacket(conn, 1, errPacket)
+			default:
+				writeFakePacket(conn, 1, []byte{0x00, 1, 0, 2, 0, 0, 0})
+			}
+		}
+	}
+}
+
+func readFakePacket(reader *bufio.Reader) ([]byte, byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		return nil, 0, err
+	}
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	payload := make([]byte, length)
+	_, err := io.ReadFull(reader, payload)
+	return payload, header[3], err
+}
+
+func writeFakePacket(conn net.Conn, sequence byte, payload []byte) {
+	header := []byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), sequence}
+	conn.Write(append(header, payload...))
+}
+
+func appendFakeString(b []byte, s string) []byte {
+	return append(append(b, byte(len(s))), s...)
+}
+
+func writeFakeResultSet(conn net.Conn, columns []string, rows [][]string) {
+	sequence := byte(1)
+	writeFakePacket(conn, sequence, []byte{byte(len(columns))})
+
+	for _, column := range columns {
+		sequence++
+		definition := appendFakeString(nil, "def")
+		definition = appendFakeString(definition, "")
+		definition = appendFakeString(definition, "")
+		definition = appendFakeString(definition, "")
+		definition = appendFakeString(definition, column)
+		definition = appendFakeString(definition, column)
+		definition = append(definition, 0x0c, 33, 0, 255, 0, 0, 0, 0xfd, 0, 0, 0, 0, 0)
+		writeFakePacket(conn, sequence, definition)
+	}
+	sequence++
+	writeFakePacket(conn, sequence, []byte{0xfe, 0, 0, 2, 0})
+
+	for _, row := range rows {
+		sequence++
+		var packet []byte
+		for _, value := range row {
+			packet = appendFakeString(packet, value)
+		}
+		writeFakePacket(conn, sequence, packet)
+	}
+	sequence++
+	writeFakePacket(conn, sequence, []byte{0xfe, 0, 0, 2, 0})
+}
+
+func TestMySQLTransportQuery(t *testing.T) {
+	server := newFakeMySQLServer(t)
+	transport := newMySQLTransport(server.addr(), time.Second)
+	defer transport.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response, err := transport.Query(ctx, "SHOW STATUS LIKE 'version'")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if response.Total != 1 || len(response.Data) != 1 {
+		t.Fatalf("Expected 1 row, got %+v", response)
+	}
+	if response.Data[0]["Value"] != "13.11.0 test" {
+		t.Errorf("Expected version value, got %v", response.Data[0]["Value"])
+	}
+
+	if _, err := transport.Query(ctx, "TRUNCATE TABLE documents"); err != nil {
+		t.Fatalf("Statement failed: %v", err)
+	}
+
+	if err := transport.Ping(ctx); err != nil {
+		t.Errorf("Ping failed: %v", err)
+	}
+}
+
+func TestMySQLTransportServerError(t *testing.T) {
+	server := newFakeMySQLServer(t)
+	transport := newMySQLTransport(server.addr(), time.Second)
+	defer transport.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := transport.Query(ctx, "BROKEN QUERY")
+	mysqlErr, ok := err.(*MySQLError)
+	if !ok {
+		t.Fatalf("Expected *MySQLError, got %T: %v", err, err)
+	}
+	if mysqlErr.Code != 1064 || mysqlErr.SQLState != "42000" || mysqlErr.Message != "syntax error" {
+		t.Errorf("Unexpected error fields: %+v", mysqlErr)
+	}
+
+	// A server-side error keeps the connection usable
+	if transport.conn == nil {
+		t.Error("Expected connection to be kept after SQL error")
+	}
+	if _, err := transport.Query(ctx, "SHOW STATUS LIKE 'version'"); err != nil {
+		t.Errorf("Query after SQL error failed: %v", err)
+	}
+}
+
+func TestHTTPClientUsesMySQLTransportForSQL(t *testing.T) {
+	server := newFakeMySQLServer(t)
+
+	config := DefaultHTTPClientConfig("http://127.0.0.1:1")
+	config.SQLTransport = SQLTransportMySQL
+	config.MySQLAddr = server.addr()
+	client := NewHTTPClient(config).(*manticoreHTTPClient)
+	defer client.Close()
+
+	if err := client.executeSQL("TRUNCATE TABLE documents"); err != nil {
+		t.Fatalf("executeSQL failed: %v", err)
+	}
+
+	caps, err := client.DetectCapabilities()
+	if err != nil {
+		t.Fatalf("DetectCapabilities failed: %v", err)
+	}
+	if !caps.AutoEmbeddings {
+		t.Errorf("Expected Auto Embeddings for 13.11.0, got %+v", caps)
+	}
+
+	queries := server.receivedQueries()
+	if len(queries) != 2 || queries[0] != "TRUNCATE TABLE documents" {
+		t.Errorf("Unexpected queries received: %v", queries)
+	}
+
+	// HTTP is unreachable, but the MySQL listener answers pings
+	if err := client.HealthCheck(); err != nil {
+		t.Errorf("Expected health check to pass over MySQL, got %v", err)
+	}
+}
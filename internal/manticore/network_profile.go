@@ -0,0 +1,62 @@
+package manticore
+
+import (
+	"fmt"
+	"time"
+)
+
+// NetworkProfile names a preset of the transport settings of the HTTP client for the network
+// between the application and Manticore
+type NetworkProfile string
+
+// Network profiles
+const (
+	// NetworkProfileDefault keeps the settings of DefaultHTTPConfig
+	NetworkProfileDefault NetworkProfile = "default"
+	// NetworkProfileDocker suits Manticore on the same Docker bridge network: every request goes
+	// to one host, so more connections stay idle, and compression only costs CPU
+	NetworkProfileDocker NetworkProfile = "docker"
+	// NetworkProfileSwarm suits a Docker Swarm overlay network, whose load balancer silently drops
+	// idle connections and whose tasks change addresses on updates, so idle connections are closed
+	// early rather than reused after they went stale
+	NetworkProfileSwarm NetworkProfile = "swarm"
+	// NetworkProfileRemote suits Manticore behind a TLS proxy or load balancer across a network:
+	// HTTP/2 is negotiated when the proxy offers it, responses are compressed, and idle
+	// connections are closed before the usual 60s idle timeout of load balancers
+	NetworkProfileRemote NetworkProfile = "remote"
+	// NetworkProfileKubernetes suits Manticore in a Kubernetes cluster, where a restarted pod comes
+	// back with a new address: the host is re-resolved every 30s to recycle connections to the old
+	// one, and idle connections are closed early like for Swarm
+	NetworkProfileKubernetes NetworkProfile = "kubernetes"
+)
+
+// ApplyNetworkProfile sets the HTTP/2, keep-alive, compression, idle connection and DNS refresh
+// settings of the profile, overwriting those of the config
+func (c *HTTPClientConfig) ApplyNetworkProfile(profile NetworkProfile) error {
+	var dnsRefreshInterval time.Duration
+	switch profile {
+	case NetworkProfileDefault:
+		c.EnableHTTP2, c.DisableKeepAlives, c.DisableCompression = false, false, false
+		c.MaxIdleConnsPerHost, c.IdleConnTimeout = 10, 90*time.Second
+	case NetworkProfileDocker:
+		c.EnableHTTP2, c.DisableKeepAlives, c.DisableCompression = false, false, true
+		c.MaxIdleConnsPerHost, c.IdleConnTimeout = 32, 90*time.Second
+	case NetworkProfileSwarm:
+		c.EnableHTTP2, c.DisableKeepAlives, c.DisableCompression = false, false, true
+		c.MaxIdleConnsPerHost, c.IdleConnTimeout = 16, 30*time.Second
+	case NetworkProfileRemote:
+		c.EnableHTTP2, c.DisableKeepAlives, c.DisableCompression = true, false, false
+		c.MaxIdleConnsPerHost, c.IdleConnTimeout = 10, 50*time.Second
+	case NetworkProfileKubernetes:
+		c.EnableHTTP2, c.DisableKeepAlives, c.DisableCompression = false, false, true
+		c.MaxIdleConnsPerHost, c.IdleConnTimeout = 16, 30*time.Second
+		dnsRefreshInterval = 30 * time.Second
+	default:
+		return fmt.Errorf("unknown network profile %q (expected %q, %q, %q, %q or %q)",
+			profile, NetworkProfileDefault, NetworkProfileDocker, NetworkProfileSwarm, NetworkProfileRemote, NetworkProfileKubernetes)
+	}
+	c.DNSRefreshInterval = dnsRefreshInterval
+	c.MaxIdleConns = max(c.MaxIdleConns, c.MaxIdleConnsPerHost)
+	c.NetworkProfile = profile
+	return nil
+}
@@ -0,0 +1,54 @@
+package manticore
+
+import (
+	"sync"
+	"time"
+)
+
+// latencyWeight is the weight of the newest latency in the moving average a pacer keeps
+const latencyWeight = 0.3
+
+// pacer spaces consecutive bulk requests so indexing does not monopolize the backend. The pause
+// before a request is the larger of a fixed delay and a fraction of the backend's recent average
+// latency, so a slowing backend is given proportionally more room.
+type pacer struct {
+	delay  time.Duration // minimum pause between requests
+	factor float64       // pause as a fraction of the average latency; 0 disables adaptive pacing
+
+	mutex   sync.Mutex
+	latency time.Duration // exponentially weighted moving average
+	next    time.Time     // earliest start of the next request
+}
+
+func newPacer(delay time.Duration, factor float64) *pacer {
+	return &pacer{delay: delay, factor: factor}
+}
+
+// wait blocks until the next request may start. The first request starts immediately.
+func (p *pacer) wait() {
+	p.mutex.Lock()
+	next := p.next
+	p.mutex.Unlock()
+
+	if pause := time.Until(next); pause > 0 {
+		time.Sleep(pause)
+	}
+}
+
+// observe records the latency of a finished request and schedules the next one
+func (p *pacer) observe(latency time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.latency == 0 {
+		p.latency = latency
+	} else {
+		p.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(p.latency))
+	}
+	p.next = time.Now().Add(p.pause())
+}
+
+// pause returns the pause before the next request; the caller holds the mutex
+func (p *pacer) pause() time.Duration {
+	return max(p.delay, time.Duration(p.factor*float64(p.latency)))
+}
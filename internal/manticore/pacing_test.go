@@ -0,0 +1,78 @@
+package manticore
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPacerPause(t *testing.T) {
+	p := newPacer(100*time.Millisecond, 0.5)
+	if p.pause() != 100*time.Millisecond {
+		t.Errorf("Expected the fixed delay before any latency is observed, got %v", p.pause())
+	}
+
+	p.observe(time.Second)
+	if p.pause() != 500*time.Millisecond {
+		t.Errorf("Expected half the observed latency, got %v", p.pause())
+	}
+
+	// The average moves towards faster responses, but not all the way at once
+	p.observe(0)
+	if pause := p.pause(); pause != 350*time.Millisecond {
+		t.Errorf("Expected the moving average to lower the pause to 350ms, got %v", pause)
+	}
+
+	fixed := newPacer(100*time.Millisecond, 0)
+	fixed.observe(time.Minute)
+	if fixed.pause() != 100*time.Millisecond {
+		t.Errorf("Expected a zero factor to keep the fixed delay, got %v", fixed.pause())
+	}
+}
+
+func TestPacerWait(t *testing.T) {
+	p := newPacer(50*time.Millisecond, 0)
+
+	start := time.Now()
+	p.wait()
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("Expected the first request to start immediately, waited %v", elapsed)
+	}
+
+	p.observe(time.Millisecond)
+	start = time.Now()
+	p.wait()
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Expected a pause of about 50ms, waited %v", elapsed)
+	}
+
+	unpaced := newPacer(0, 0)
+	unpaced.observe(time.Second)
+	start = time.Now()
+	unpaced.wait()
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("Expected no pause without pacing, waited %v", elapsed)
+	}
+}
+
+func TestLoadBulkPacingFromEnvironment(t *testing.T) {
+	t.Setenv("MANTICORE_BULK_BATCH_DELAY", "0")
+	t.Setenv("MANTICORE_BULK_FALLBACK_DELAY", "10ms")
+	t.Setenv("MANTICORE_BULK_PACING_FACTOR", "0.25")
+
+	config, err := LoadHTTPConfigFromEnvironment()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.BulkConfig.BatchDelay != 0 || config.BulkConfig.FallbackDelay != 10*time.Millisecond || config.BulkConfig.PacingFactor != 0.25 {
+		t.Errorf("Unexpected pacing configuration: %+v", config.BulkConfig)
+	}
+
+	for _, name := range []string{"MANTICORE_BULK_BATCH_DELAY", "MANTICORE_BULK_FALLBACK_DELAY", "MANTICORE_BULK_PACING_FACTOR"} {
+		t.Setenv(name, "-1")
+		if _, err := LoadHTTPConfigFromEnvironment(); err == nil {
+			t.Errorf("Expected an error for a negative %s", name)
+		}
+		os.Unsetenv(name)
+	}
+}
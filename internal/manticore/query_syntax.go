@@ -0,0 +1,413 @@
+package manticore
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SearchableFields are the full-text fields a query may restrict terms to with @field
+var SearchableFields = []string{"title", "content", "url"}
+
+// querySpecialChars are the characters with a meaning in Manticore's query syntax. Outside of the
+// operators SanitizeQueryString understands they separate words.
+const querySpecialChars = `\()|-!@~"&/^$=<*`
+
+// codeConnectors join the parts of identifiers, paths and version strings such as net/http and
+// v1.21.3. Manticore indexes the parts as separate words, so such a term is searched as a phrase.
+// A colon only separates the parts of terms other connectors join, such as URLs, so key:value
+// terms are kept as written.
+const codeConnectors = "./-"
+
+// queryOperatorWords are the upper case words Manticore reads as operators
+var queryOperatorWords = map[string]bool{
+	"MAYBE": true, "NEAR": true, "NOTNEAR": true, "SENTENCE": true, "PARAGRAPH": true, "ZONE": true, "ZONESPAN": true,
+}
+
+// MaxNearDistance is the largest distance, in words, of a NEAR/N or NOTNEAR/N operator
+const MaxNearDistance = 1000
+
+// queryItem is a parsed element of a full-text query
+type queryItem struct {
+	kind    queryItemKind
+	text    string      // Word, phrase text, field name or proximity operator
+	negated bool        // Operand is prefixed with -
+	group   []queryItem // Items of a parenthesized group
+}
+
+type queryItemKind int
+
+const (
+	queryWord queryItemKind = iota
+	queryPhrase
+	queryGroup
+	queryOr
+	queryField
+	queryNear // NEAR/N or NOTNEAR/N between two operands
+)
+
+func (item queryItem) isOperand() bool {
+	return item.kind == queryWord || item.kind == queryPhrase || item.kind == queryGroup
+}
+
+// isBinaryOperator reports whether the item joins the operands on either side of it
+func (item queryItem) isBinaryOperator() bool {
+	return item.kind == queryOr || item.kind == queryNear
+}
+
+// SanitizeQueryString rewrites a user query into valid Manticore query_string syntax, so crafted
+// input cannot produce a syntax error. It keeps words (with * wildcards), "phrases" (with an
+// optional ~N proximity), parenthesized groups, | alternatives, NEAR/N and NOTNEAR/N between two
+// operands, -negation and @title, @content and @url field limits; other operators and unbalanced
+// quotes or parentheses are dropped. Negations are removed from groups without a positive term,
+// which Manticore cannot evaluate. The result is empty when the query has no searchable words.
+func SanitizeQueryString(query string) string {
+	p := &queryParser{input: []rune(query)}
+	return renderQuery(p.parseSequence(0))
+}
+
+// QueryTerms reduces a value to plain words separated by spaces, so it can be inserted into a
+// query without adding operators, phrases or field limits
+func QueryTerms(value string) string {
+	words := strings.FieldsFunc(value, func(r rune) bool { return !isWordRune(r) })
+	for i, word := range words {
+		if queryOperatorWords[word] {
+			words[i] = strings.ToLower(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// NearQuery builds a query matching the terms within distance words of each other, joined by
+// NEAR/distance. Terms of several words are searched as phrases and operators in them are removed.
+// The result is empty when no term has searchable words.
+func NearQuery(distance int, terms ...string) string {
+	distance = max(1, min(distance, MaxNearDistance))
+	operands := make([]string, 0, len(terms))
+	for _, term := range terms {
+		words := QueryTerms(term)
+		if words == "" {
+			continue
+		}
+		if strings.Contains(words, " ") {
+			words = `"` + words + `"`
+		}
+		operands = append(operands, words)
+	}
+	return strings.Join(operands, " NEAR/"+strconv.Itoa(distance)+" ")
+}
+
+// RemoveQueryWords sanitizes a query like SanitizeQueryString without the words, outside of
+// phrases, for which drop returns true. Operators left without an operand are dropped as well.
+// The sanitized query is returned unchanged when every word would be removed.
+func RemoveQueryWords(query string, drop func(word string) bool) string {
+	p := &queryParser{input: []rune(query)}
+	items := p.parseSequence(0)
+	if removed := renderQuery(removeWords(items, drop)); removed != "" {
+		return removed
+	}
+	return renderQuery(items)
+}
+
+// removeWords returns items without the words for which drop returns true, including in groups
+func removeWords(items []queryItem, drop func(word string) bool) []queryItem {
+	kept := make([]queryItem, 0, len(items))
+	for _, item := range items {
+		switch item.kind {
+		case queryWord:
+			if drop(item.text) {
+				continue
+			}
+		case queryGroup:
+			item.group = removeWords(item.group, drop)
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+type queryParser struct {
+	input []rune
+	pos   int
+}
+
+// parseSequence parses items up to the end of input or, inside a group, the closing parenthesis
+func (p *queryParser) parseSequence(depth int) []queryItem {
+	var items []queryItem
+	negated := false
+
+	for p.pos < len(p.input) {
+		r := p.input[p.pos]
+		switch {
+		case unicode.IsSpace(r):
+			p.pos++
+			negated = false
+			continue
+		case r == ')':
+			p.pos++
+			if depth > 0 {
+				return items
+			}
+			// A stray closing parenthesis is dropped
+		case r == '(':
+			p.pos++
+			items = append(items, queryItem{kind: queryGroup, negated: negated, group: p.parseSequence(depth + 1)})
+		case r == '"':
+			p.pos++
+			if phrase, ok := p.parsePhrase(); ok {
+				items = append(items, queryItem{kind: queryPhrase, text: phrase, negated: negated})
+			}
+		case r == '|':
+			p.pos++
+			items = append(items, queryItem{kind: queryOr})
+		case (r == '-' || r == '!') && !negated && p.startsTerm() && p.startsOperand(p.pos+1):
+			p.pos++
+			negated = true
+			continue
+		case r == '@':
+			p.pos++
+			if name := p.readWord(); isSearchableField(name) {
+				items = append(items, queryItem{kind: queryField, text: name})
+			}
+		case isWordRune(r) || r == '*':
+			term := p.readTerm()
+			if operator, ok := nearOperator(term); ok && !negated {
+				items = append(items, queryItem{kind: queryNear, text: operator})
+				break
+			}
+			items = append(items, termItems(term, negated)...)
+		default:
+			p.pos++
+		}
+		negated = false
+	}
+	return items
+}
+
+// startsTerm reports whether the rune at pos begins a term rather than continuing a word like e-mail
+func (p *queryParser) startsTerm() bool {
+	if p.pos == 0 {
+		return true
+	}
+	prev := p.input[p.pos-1]
+	return unicode.IsSpace(prev) || prev == '(' || prev == '|'
+}
+
+// startsOperand reports whether a word, phrase or group starts at pos
+func (p *queryParser) startsOperand(pos int) bool {
+	if pos >= len(p.input) {
+		return false
+	}
+	r := p.input[pos]
+	return r == '(' || r == '"' || isWordRune(r) || r == '*'
+}
+
+// readWord reads a word with its * wildcards
+func (p *queryParser) readWord() string {
+	start := p.pos
+	for p.pos < len(p.input) && (isWordRune(p.input[p.pos]) || p.input[p.pos] == '*') {
+		p.pos++
+	}
+	return string(p.input[start:p.pos])
+}
+
+// readTerm reads a word like readWord, continuing over runs of / and - between words, so paths
+// such as net/http and names such as utf-8 are read as one term
+func (p *queryParser) readTerm() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		r := p.input[p.pos]
+		if isWordRune(r) || r == '*' {
+			p.pos++
+			continue
+		}
+		end := p.pos
+		for end < len(p.input) && (p.input[end] == '/' || p.input[end] == '-') {
+			end++
+		}
+		if end == p.pos || end == len(p.input) || !isWordRune(p.input[end]) {
+			break
+		}
+		p.pos = end
+	}
+	return string(p.input[start:p.pos])
+}
+
+// termItems returns the items of a term: a word, or a phrase of its parts when codeConnectors
+// join several. Terms with wildcards or operator keywords, such as NEAR/0, are split into words.
+func termItems(term string, negated bool) []queryItem {
+	separator := isCodeConnector
+	if len(strings.FieldsFunc(term, isCodeConnector)) > 1 {
+		separator = func(r rune) bool { return r == ':' || isCodeConnector(r) }
+	}
+
+	var parts []string
+	split := false
+	for _, part := range strings.FieldsFunc(term, separator) {
+		if strings.Trim(part, "*") == "" {
+			continue
+		}
+		if queryOperatorWords[part] {
+			// Keep operator keywords such as SENTENCE as plain words
+			part = strings.ToLower(part)
+			split = true
+		}
+		if strings.Contains(part, "*") {
+			split = true
+		}
+		parts = append(parts, part)
+	}
+
+	if len(parts) > 1 && !split {
+		return []queryItem{{kind: queryPhrase, text: `"` + strings.Join(parts, " ") + `"`, negated: negated}}
+	}
+	items := make([]queryItem, 0, len(parts))
+	for i, part := range parts {
+		items = append(items, queryItem{kind: queryWord, text: part, negated: negated && i == 0})
+	}
+	return items
+}
+
+// nearOperator reports whether term is a NEAR/N or NOTNEAR/N operator with a distance from 1 to
+// MaxNearDistance, returning it in canonical form
+func nearOperator(term string) (string, bool) {
+	name, distance, found := strings.Cut(term, "/")
+	if !found || (name != "NEAR" && name != "NOTNEAR") {
+		return "", false
+	}
+	for _, r := range distance {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+	n, err := strconv.Atoi(distance)
+	if err != nil || n < 1 || n > MaxNearDistance {
+		return "", false
+	}
+	return name + "/" + strconv.Itoa(n), true
+}
+
+// parsePhrase reads a phrase after its opening quote, keeping only its words. An unterminated
+// phrase is reported as not ok and its words are parsed as ordinary terms.
+func (p *queryParser) parsePhrase() (string, bool) {
+	end := p.pos
+	for end < len(p.input) && p.input[end] != '"' {
+		end++
+	}
+	if end == len(p.input) {
+		return "", false
+	}
+
+	words := strings.FieldsFunc(string(p.input[p.pos:end]), func(r rune) bool { return !isWordRune(r) || isCodeConnector(r) })
+	p.pos = end + 1
+	if len(words) == 0 {
+		return "", false
+	}
+	phrase := `"` + strings.Join(words, " ") + `"`
+
+	// Proximity: "a b"~N
+	if p.pos+1 < len(p.input) && p.input[p.pos] == '~' && unicode.IsDigit(p.input[p.pos+1]) {
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.input) && unicode.IsDigit(p.input[p.pos]) {
+			p.pos++
+		}
+		phrase += string(p.input[start:p.pos])
+	}
+	return phrase, true
+}
+
+// renderQuery writes items as query_string syntax, dropping the ones that would be invalid
+func renderQuery(items []queryItem) string {
+	// Render groups first so empty ones can be dropped
+	operands := make([]queryItem, 0, len(items))
+	for _, item := range items {
+		if item.kind == queryGroup {
+			inner := renderQuery(item.group)
+			if inner == "" {
+				continue
+			}
+			item.text = "(" + inner + ")"
+		}
+		operands = append(operands, item)
+	}
+
+	// Keep | only between two operands, NEAR/N only between two operands without field limits and
+	// field limits only before an operand
+	var valid []queryItem
+	for i, item := range operands {
+		switch item.kind {
+		case queryOr:
+			if len(valid) == 0 || !valid[len(valid)-1].isOperand() || i+1 >= len(operands) || !startsWithOperand(operands[i+1:]) {
+				continue
+			}
+		case queryNear:
+			if len(valid) == 0 || !valid[len(valid)-1].isOperand() || i+1 >= len(operands) || !operands[i+1].isOperand() {
+				continue
+			}
+		case queryField:
+			if i+1 >= len(operands) || !operands[i+1].isOperand() {
+				continue
+			}
+		}
+		valid = append(valid, item)
+	}
+
+	// Alternatives and proximity operands can't be negated, and a sequence without a positive term
+	// can't be evaluated
+	positive := false
+	for i, item := range valid {
+		if !item.isOperand() {
+			continue
+		}
+		if item.negated && ((i > 0 && valid[i-1].isBinaryOperator()) || (i+1 < len(valid) && valid[i+1].isBinaryOperator())) {
+			valid[i].negated = false
+		}
+		if !valid[i].negated {
+			positive = true
+		}
+	}
+
+	parts := make([]string, 0, len(valid))
+	for _, item := range valid {
+		text := item.text
+		if item.kind == queryOr {
+			text = "|"
+		} else if item.kind == queryNear {
+			text = item.text
+		} else if item.kind == queryField {
+			text = "@" + item.text
+		} else if item.negated && positive {
+			text = "-" + text
+		}
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// startsWithOperand reports whether items begin with an operand, skipping field limits
+func startsWithOperand(items []queryItem) bool {
+	for _, item := range items {
+		if item.kind != queryField {
+			return item.isOperand()
+		}
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !unicode.IsControl(r) && !strings.ContainsRune(querySpecialChars, r)
+}
+
+func isCodeConnector(r rune) bool {
+	return strings.ContainsRune(codeConnectors, r)
+}
+
+func isSearchableField(name string) bool {
+	for _, field := range SearchableFields {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package manticore
+
+import "testing"
+
+func TestSanitizeQueryString(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"plain words", "golang search", "golang search"},
+		{"unicode", "поиск  документов", "поиск документов"},
+		{"apostrophe", "it's", "it's"},
+		{"wildcards", "comp* *put*", "comp* *put*"},
+		{"lone wildcard", "* go", "go"},
+		{"phrase", `"quick brown fox"`, `"quick brown fox"`},
+		{"phrase proximity", `"quick fox"~3`, `"quick fox"~3`},
+		{"phrase special characters", `"a (b) | c"`, `"a b c"`},
+		{"unterminated phrase", `"quick fox`, "quick fox"},
+		{"empty phrase", `"" go`, "go"},
+		{"negation", "go -java !rust", "go -java -rust"},
+		{"only negations", "-java -rust", "java rust"},
+		{"negated group", "go -(java | rust)", "go -(java | rust)"},
+		{"negation inside words", "e-mail", `"e mail"`},
+		{"paths", "net/http -a/b", `"net http" -"a b"`},
+		{"versions", "go v1.21.3 release.", `go "v1 21 3" release`},
+		{"key value terms", "title:test", "title:test"},
+		{"urls", "https://go.dev/doc", `"https go dev doc"`},
+		{"code tokens with wildcards", "net/ht*", "net ht*"},
+		{"phrase code tokens", `"go v1.21"`, `"go v1 21"`},
+		{"dangling negation", "go -", "go"},
+		{"alternatives", "go | rust", "go | rust"},
+		{"dangling alternatives", "| go | | rust |", "go | rust"},
+		{"negated alternative", "go | -rust", "go | rust"},
+		{"groups", "(go | rust) search", "(go | rust) search"},
+		{"unbalanced open", "((go search", "((go search))"},
+		{"unbalanced close", "go) search)", "go search"},
+		{"empty group", "() go ( | )", "go"},
+		{"negative group", "go (-java)", "go (java)"},
+		{"fields", "@title go @content search", "@title go @content search"},
+		{"unknown field", "@secret go", "go"},
+		{"dangling field", "go @title", "go"},
+		{"field before alternative", "go @title | rust", "go | rust"},
+		{"operator keywords", "a SENTENCE b NEAR c", "a sentence b near c"},
+		{"quorum operator", `"a b c"/2 a`, `"a b c" 2 a`},
+		{"near", "a NEAR/3 b NOTNEAR/10 c", "a NEAR/3 b NOTNEAR/10 c"},
+		{"near phrases and groups", `"a b" NEAR/2 (c | d)`, `"a b" NEAR/2 (c | d)`},
+		{"near canonical distance", "a NEAR/03 b", "a NEAR/3 b"},
+		{"near invalid distance", "a NEAR/0 b NEAR/1001 c", "a near 0 b near 1001 c"},
+		{"lower case near", "a near/3 b", `a "near 3" b`},
+		{"dangling near", "NEAR/3 a NEAR/3", "a"},
+		{"near before field", "a NEAR/3 @title b", "a @title b"},
+		{"negated near operands", "-a NEAR/3 -b c", "a NEAR/3 b c"},
+		{"other operators", `=exact ^start end$ a << b \x`, "exact start end a b x"},
+		{"only special characters", `"()|-!@~"`, ""},
+		{"empty", "   ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeQueryString(tt.query); got != tt.expected {
+				t.Errorf("SanitizeQueryString(%q) = %q, want %q", tt.query, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQueryTerms(t *testing.T) {
+	tests := map[string]string{
+		"golang search":         "golang search",
+		`"go" | -(rust) @title`: "go rust title",
+		"a NEAR b":              "a near b",
+		"comp* it's":            "comp it's",
+		"  ":                    "",
+	}
+	for value, expected := range tests {
+		if got := QueryTerms(value); got != expected {
+			t.Errorf("QueryTerms(%q) = %q, want %q", value, got, expected)
+		}
+	}
+}
+
+func TestRemoveQueryWords(t *testing.T) {
+	drop := func(word string) bool { return word == "the" || word == "is" }
+	tests := map[string]string{
+		"what is the golang": "what golang",
+		`"the golang" is`:    `"the golang"`,
+		"(the | is) go":      "go",
+		"@title the go -is":  "@title go",
+		"the is":             "the is",
+		"the | go":           "go",
+	}
+	for query, expected := range tests {
+		if got := RemoveQueryWords(query, drop); got != expected {
+			t.Errorf("RemoveQueryWords(%q) = %q, want %q", query, got, expected)
+		}
+	}
+}
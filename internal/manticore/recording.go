@@ -0,0 +1,241 @@
+package manticore
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Defaults of RecordingConfig
+const (
+	DefaultRecordingCapacity    = 100
+	DefaultRecordingMaxBodySize = 64 << 10
+)
+
+// RecordingConfig configures the recording of full Manticore requests and responses for
+// debugging. A SampleRate fraction of requests is kept in a ring buffer of the last Capacity
+// exchanges, with up to MaxBodySize bytes of each body. The zero value records nothing.
+type RecordingConfig struct {
+	SampleRate  float64
+	Capacity    int // 0 uses DefaultRecordingCapacity
+	MaxBodySize int // 0 uses DefaultRecordingMaxBodySize
+}
+
+// Enabled reports whether any request is recorded
+func (c RecordingConfig) Enabled() bool {
+	return c.SampleRate > 0
+}
+
+func (c RecordingConfig) withDefaults() RecordingConfig {
+	if c.Capacity <= 0 {
+		c.Capacity = DefaultRecordingCapacity
+	}
+	if c.MaxBodySize <= 0 {
+		c.MaxBodySize = DefaultRecordingMaxBodySize
+	}
+	return c
+}
+
+// RecordedExchange is a recorded request to Manticore and its response. Bodies hold what was
+// sent and what the client read, so a response the client stopped reading early, like a search
+// past its requested hits, is partial.
+type RecordedExchange struct {
+	ID                int64         `json:"id"`
+	Time              time.Time     `json:"time"`
+	Method            string        `json:"method"`
+	URL               string        `json:"url"`
+	RequestBody       string        `json:"request_body"`
+	RequestTruncated  bool          `json:"request_truncated"` // longer than MaxBodySize
+	StatusCode        int           `json:"status_code,omitempty"`
+	ResponseBody      string        `json:"response_body"`
+	ResponseTruncated bool          `json:"response_truncated"`
+	Duration          time.Duration `json:"duration"` // until the response body was closed
+	Error             string        `json:"error,omitempty"`
+}
+
+// Recordings is the recording configuration and the exchanges recorded so far, newest first
+type Recordings struct {
+	Config    RecordingConfig
+	Recorded  int64 // exchanges recorded since the client started, including those dropped from the buffer
+	Exchanges []RecordedExchange
+}
+
+// recordingTransport records a sample of the requests of next and their responses
+type recordingTransport struct {
+	next   http.RoundTripper
+	config RecordingConfig
+
+	mu       sync.Mutex
+	random   *rand.Rand
+	ring     []RecordedExchange
+	recorded int64
+}
+
+// newRecordingTransport wraps next with recording
+func newRecordingTransport(next http.RoundTripper, config RecordingConfig) *recordingTransport {
+	config = config.withDefaults()
+	return &recordingTransport{
+		next:   next,
+		config: config,
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+		ring:   make([]RecordedExchange, 0, config.Capacity),
+	}
+}
+
+// sampled draws whether the next request is recorded
+func (t *recordingTransport) sampled() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.random.Float64() < t.config.SampleRate
+}
+
+// RoundTrip sends req, recording it when sampled once its response body is closed
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.sampled() {
+		return t.next.RoundTrip(req)
+	}
+
+	exchange := RecordedExchange{Time: time.Now(), Method: req.Method, URL: req.URL.String()}
+	requestBody := &captureBuffer{max: t.config.MaxBodySize}
+	if req.Body != nil && req.Body != http.NoBody {
+		req = req.Clone(req.Context())
+		req.Body = &captureReader{ReadCloser: req.Body, capture: requestBody}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		exchange.Error = err.Error()
+		t.add(exchange, requestBody, nil)
+		return nil, err
+	}
+	exchange.StatusCode = resp.StatusCode
+	responseBody := &captureBuffer{max: t.config.MaxBodySize}
+	var once sync.Once
+	resp.Body = &captureReader{ReadCloser: resp.Body, capture: responseBody, onClose: func() {
+		once.Do(func() { t.add(exchange, requestBody, responseBody) })
+	}}
+	return resp, nil
+}
+
+// add completes exchange with the captured bodies and adds it to the ring, dropping the oldest
+// exchange when it is full
+func (t *recordingTransport) add(exchange RecordedExchange, request, response *captureBuffer) {
+	exchange.Duration = time.Since(exchange.Time)
+	exchange.RequestBody, exchange.RequestTruncated = request.snapshot()
+	if response != nil {
+		exchange.ResponseBody, exchange.ResponseTruncated = response.snapshot()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.recorded++
+	exchange.ID = t.recorded
+	if len(t.ring) < t.config.Capacity {
+		t.ring = append(t.ring, exchange)
+		return
+	}
+	t.ring[(t.recorded-1)%int64(t.config.Capacity)] = exchange
+}
+
+// CloseIdleConnections closes the idle connections of the next transport
+func (t *recordingTransport) CloseIdleConnections() {
+	if closer, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// recordings returns the configuration and the recorded exchanges, newest first; the zero value
+// when t is nil
+func (t *recordingTransport) recordings() Recordings {
+	if t == nil {
+		return Recordings{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	exchanges := make([]RecordedExchange, 0, len(t.ring))
+	for i := int64(0); i < int64(len(t.ring)); i++ {
+		exchanges = append(exchanges, t.ring[(t.recorded-1-i)%int64(t.config.Capacity)])
+	}
+	return Recordings{Config: t.config, Recorded: t.recorded, Exchanges: exchanges}
+}
+
+// captureBuffer keeps the first max bytes written to it. The transport may write a request body
+// while the response is read, so it is safe for concurrent use.
+type captureBuffer struct {
+	mu        sync.Mutex
+	buf       []byte
+	max       int
+	truncated bool
+}
+
+func (c *captureBuffer) write(p []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	room := c.max - len(c.buf)
+	if len(p) > room {
+		c.truncated = true
+		p = p[:max(room, 0)]
+	}
+	c.buf = append(c.buf, p...)
+}
+
+func (c *captureBuffer) snapshot() (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return string(c.buf), c.truncated
+}
+
+// captureReader copies what is read from a body into a captureBuffer
+type captureReader struct {
+	io.ReadCloser
+	capture *captureBuffer
+	onClose func()
+}
+
+func (r *captureReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.capture.write(p[:n])
+	return n, err
+}
+
+func (r *captureReader) Close() error {
+	err := r.ReadCloser.Close()
+	if r.onClose != nil {
+		r.onClose()
+	}
+	return err
+}
+
+// loadRecordingConfigFromEnvironment reads MANTICORE_DEBUG_RECORD_SAMPLE_RATE,
+// MANTICORE_DEBUG_RECORD_CAPACITY and MANTICORE_DEBUG_RECORD_MAX_BODY_SIZE
+func loadRecordingConfigFromEnvironment(config *RecordingConfig) error {
+	if value := os.Getenv("MANTICORE_DEBUG_RECORD_SAMPLE_RATE"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return fmt.Errorf("invalid MANTICORE_DEBUG_RECORD_SAMPLE_RATE: %q (use a fraction from 0 to 1)", value)
+		}
+		config.SampleRate = rate
+	}
+	sizes := []struct {
+		name   string
+		target *int
+	}{
+		{"MANTICORE_DEBUG_RECORD_CAPACITY", &config.Capacity},
+		{"MANTICORE_DEBUG_RECORD_MAX_BODY_SIZE", &config.MaxBodySize},
+	}
+	for _, s := range sizes {
+		if value := os.Getenv(s.name); value != "" {
+			size, err := strconv.Atoi(value)
+			if err != nil || size <= 0 {
+				return fmt.Errorf("invalid %s: %q", s.name, value)
+			}
+			*s.target = size
+		}
+	}
+	return nil
+}
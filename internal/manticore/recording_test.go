@@ -0,0 +1,98 @@
+package manticore
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRecordingTransport(t *testing.T) {
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write([]byte("echo " + string(body)))
+	})
+	defer server.Close()
+
+	post := func(transport *recordingTransport, body string) {
+		req, _ := http.NewRequest("POST", server.URL+"/search", strings.NewReader(body))
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+
+	t.Run("ring", func(t *testing.T) {
+		transport := newRecordingTransport(http.DefaultTransport, RecordingConfig{SampleRate: 1, Capacity: 2})
+		for _, body := range []string{"one", "two", "three"} {
+			post(transport, body)
+		}
+		recordings := transport.recordings()
+		if recordings.Recorded != 3 || len(recordings.Exchanges) != 2 {
+			t.Fatalf("Expected the last 2 of 3 exchanges, got %d of %d", len(recordings.Exchanges), recordings.Recorded)
+		}
+		newest := recordings.Exchanges[0]
+		if newest.ID != 3 || newest.RequestBody != "three" || newest.ResponseBody != "echo three" || newest.StatusCode != http.StatusOK {
+			t.Errorf("Expected the newest exchange first, got %+v", newest)
+		}
+		if recordings.Exchanges[1].RequestBody != "two" {
+			t.Errorf("Expected the oldest exchange to be dropped, got %+v", recordings.Exchanges[1])
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		transport := newRecordingTransport(http.DefaultTransport, RecordingConfig{SampleRate: 1, MaxBodySize: 4})
+		post(transport, "truncated")
+		exchange := transport.recordings().Exchanges[0]
+		if exchange.RequestBody != "trun" || !exchange.RequestTruncated || exchange.ResponseBody != "echo" || !exchange.ResponseTruncated {
+			t.Errorf("Expected bodies truncated to 4 bytes, got %+v", exchange)
+		}
+	})
+
+	t.Run("not sampled", func(t *testing.T) {
+		transport := newRecordingTransport(http.DefaultTransport, RecordingConfig{SampleRate: 0})
+		post(transport, "skipped")
+		if recordings := transport.recordings(); recordings.Recorded != 0 {
+			t.Errorf("Expected nothing recorded, got %+v", recordings)
+		}
+	})
+}
+
+func TestClientRecordingsDisabled(t *testing.T) {
+	client := NewHTTPClient(DefaultHTTPClientConfig("http://localhost:9308"))
+	defer client.Close()
+	if recordings := client.GetRecordings(); recordings.Config.Enabled() || recordings.Exchanges != nil {
+		t.Errorf("Expected nothing recorded by default, got %+v", recordings)
+	}
+}
+
+func TestLoadRecordingConfigFromEnvironment(t *testing.T) {
+	t.Setenv("MANTICORE_DEBUG_RECORD_SAMPLE_RATE", "0.25")
+	t.Setenv("MANTICORE_DEBUG_RECORD_CAPACITY", "50")
+	t.Setenv("MANTICORE_DEBUG_RECORD_MAX_BODY_SIZE", "1024")
+
+	var config RecordingConfig
+	if err := loadRecordingConfigFromEnvironment(&config); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := RecordingConfig{SampleRate: 0.25, Capacity: 50, MaxBodySize: 1024}
+	if config != expected || !config.Enabled() {
+		t.Errorf("Expected %+v, got %+v", expected, config)
+	}
+
+	for name, value := range map[string]string{
+		"MANTICORE_DEBUG_RECORD_SAMPLE_RATE":   "2",
+		"MANTICORE_DEBUG_RECORD_CAPACITY":      "0",
+		"MANTICORE_DEBUG_RECORD_MAX_BODY_SIZE": "big",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			var config RecordingConfig
+			if err := loadRecordingConfigFromEnvironment(&config); err == nil {
+				t.Errorf("Expected an error for %s=%s", name, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package manticore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// DefaultMaxResponseSize is the largest response body read from Manticore unless configured otherwise
+const DefaultMaxResponseSize int64 = 64 << 20
+
+// ErrResponseTooLarge is returned when a Manticore response exceeds the configured maximum size
+var ErrResponseTooLarge = errors.New("response exceeds maximum size")
+
+// maxSizeReader fails with ErrResponseTooLarge once more than limit bytes are read
+type maxSizeReader struct {
+	r         io.Reader
+	limit     int64
+	remaining int64
+}
+
+// newMaxSizeReader bounds r to limit bytes
+func newMaxSizeReader(r io.Reader, limit int64) io.Reader {
+	return &maxSizeReader{r: r, limit: limit, remaining: limit}
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	if m.remaining <= 0 {
+		// Only fail if the body really continues past the limit
+		var probe [1]byte
+		n, err := m.r.Read(probe[:])
+		if n > 0 {
+			return 0, fmt.Errorf("%w of %d bytes", ErrResponseTooLarge, m.limit)
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > m.remaining {
+		p = p[:m.remaining]
+	}
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+	return n, err
+}
+
+// readLimited reads r into buf, failing with ErrResponseTooLarge when it is larger than limit bytes
+func readLimited(buf *bytes.Buffer, r io.Reader, limit int64) error {
+	_, err := buf.ReadFrom(newMaxSizeReader(r, limit))
+	return err
+}
+
+// loadMaxResponseSizeFromEnvironment reads MANTICORE_MAX_RESPONSE_SIZE in bytes
+func loadMaxResponseSizeFromEnvironment(size *int64) error {
+	valueStr := os.Getenv("MANTICORE_MAX_RESPONSE_SIZE")
+	if valueStr == "" {
+		return nil
+	}
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil || value <= 0 {
+		return fmt.Errorf("invalid MANTICORE_MAX_RESPONSE_SIZE: %q", valueStr)
+	}
+	*size = value
+	return nil
+}
+
+// byteCounter counts the bytes written to it
+type byteCounter struct {
+	size int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.size += int64(len(p))
+	return len(p), nil
+}
+
+// decodeSearchResponse decodes a search response as it streams in. When maxHits is positive it stops
+// reading after that many hits, so an oversized result set is never held in memory. Fields that
+// follow the hits array, such as aggregations, are not read in that case.
+func decodeSearchResponse(r io.Reader, maxHits int) (*SearchResponse, error) {
+	dec := json.NewDecoder(r)
+	var response SearchResponse
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		key, err := decodeKey(dec)
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case "took":
+			err = dec.Decode(&response.Took)
+		case "timed_out":
+			err = dec.Decode(&response.TimedOut)
+		case "aggregations":
+			err = dec.Decode(&response.Aggregations)
+		case "hits":
+			var stopped bool
+			stopped, err = decodeSearchHits(dec, &response, maxHits)
+			if err == nil && stopped {
+				return &response, nil
+			}
+		default:
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &response, nil
+}
+
+// decodeSearchHits decodes the "hits" object and reports whether it stopped early at maxHits
+func decodeSearchHits(dec *json.Decoder, response *SearchResponse, maxHits int) (bool, error) {
+	if err := expectDelim(dec, '{'); err != nil {
+		return false, err
+	}
+	for dec.More() {
+		key, err := decodeKey(dec)
+		if err != nil {
+			return false, err
+		}
+
+		switch key {
+		case "total":
+			err = dec.Decode(&response.Hits.Total)
+		case "total_relation":
+			err = dec.Decode(&response.Hits.TotalRelation)
+		case "hits":
+			if err := expectDelim(dec, '['); err != nil {
+				return false, err
+			}
+			for dec.More() {
+				if maxHits > 0 && len(response.Hits.Hits) >= maxHits {
+					return true, nil
+				}
+				response.Hits.Hits = append(response.Hits.Hits, SearchHit{})
+				if err := dec.Decode(&response.Hits.Hits[len(response.Hits.Hits)-1]); err != nil {
+					return false, err
+				}
+			}
+			err = expectDelim(dec, ']')
+		default:
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return false, expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("unexpected token %v, expected %v", token, delim)
+	}
+	return nil
+}
+
+func decodeKey(dec *json.Decoder) (string, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := token.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected token %v, expected an object key", token)
+	}
+	return key, nil
+}
+
+func skipValue(dec *json.Decoder) error {
+	var value json.RawMessage
+	return dec.Decode(&value)
+}
@@ -0,0 +1,93 @@
+package manticore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDecodeSearchResponseMatchesUnmarshal(t *testing.T) {
+	payload := benchmarkSearchResponse(3)
+
+	var expected SearchResponse
+	if err := json.Unmarshal(payload, &expected); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	response, err := decodeSearchResponse(bytes.NewReader(payload), 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Took != expected.Took || response.Hits.Total != expected.Hits.Total || len(response.Hits.Hits) != 3 {
+		t.Errorf("Decoded response differs: got %+v", response)
+	}
+	if response.Hits.Hits[2].ID != expected.Hits.Hits[2].ID || response.Hits.Hits[2].Source["title"] != expected.Hits.Hits[2].Source["title"] {
+		t.Errorf("Decoded hit differs: got %+v, expected %+v", response.Hits.Hits[2], expected.Hits.Hits[2])
+	}
+}
+
+func TestDecodeSearchResponseStopsAtMaxHits(t *testing.T) {
+	// Anything after the second hit is never parsed
+	payload := `{"took":1,"timed_out":false,"hits":{"total":1000,"total_relation":"eq","hits":[` +
+		`{"_id":1,"_score":1,"_source":{}},{"_id":2,"_score":1,"_source":{}},{"_id":3, not json`
+
+	response, err := decodeSearchResponse(strings.NewReader(payload), 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Hits.Hits) != 2 || response.Hits.Total != 1000 {
+		t.Errorf("Expected 2 hits of 1000, got %d of %d", len(response.Hits.Hits), response.Hits.Total)
+	}
+}
+
+func TestMaxSizeReader(t *testing.T) {
+	var buf bytes.Buffer
+	if err := readLimited(&buf, strings.NewReader("12345"), 5); err != nil {
+		t.Errorf("Expected a body at the limit to be accepted, got %v", err)
+	}
+
+	buf.Reset()
+	err := readLimited(&buf, strings.NewReader("123456"), 5)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+	}
+}
+
+func TestSearchRejectsOversizedResponse(t *testing.T) {
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"took":1,"timed_out":false,"hits":{"total":1,"hits":[{"_id":1,"_score":1,"_source":{"content":"` +
+			strings.Repeat("x", 4096) + `"}}]}}`))
+	})
+	defer server.Close()
+
+	config := DefaultHTTPClientConfig(server.URL)
+	config.RetryConfig.MaxAttempts = 1
+	config.MaxResponseSize = 1024
+	client := NewHTTPClient(config)
+	defer client.Close()
+
+	_, err := client.SearchWithRequest(SearchRequest{Index: "documents", Query: map[string]interface{}{"match_all": map[string]interface{}{}}})
+	if err == nil || !strings.Contains(err.Error(), ErrResponseTooLarge.Error()) {
+		t.Errorf("Expected oversized response error, got %v", err)
+	}
+}
+
+func TestMaxResponseSizeFromEnvironment(t *testing.T) {
+	os.Setenv("MANTICORE_MAX_RESPONSE_SIZE", "1048576")
+	defer os.Unsetenv("MANTICORE_MAX_RESPONSE_SIZE")
+
+	size := DefaultMaxResponseSize
+	if err := loadMaxResponseSizeFromEnvironment(&size); err != nil || size != 1<<20 {
+		t.Errorf("Expected 1MiB limit, got %d (%v)", size, err)
+	}
+
+	os.Setenv("MANTICORE_MAX_RESPONSE_SIZE", "0")
+	if err := loadMaxResponseSizeFromEnvironment(&size); err == nil {
+		t.Error("Expected error for a zero limit")
+	}
+}
@@ -5,13 +5,56 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"net/url"
+	"sort"
+	"sync"
 	"time"
 )
 
 // RetryManager handles retry logic with exponential backoff and jitter
 type RetryManager struct {
 	config          RetryConfig
+	clock           Clock
+	random          func() float64
 	errorClassifier *ErrorClassifier
+
+	// Outcome counters exposed through GetRetryStats
+	statsMutex         sync.Mutex
+	totalOperations    int64
+	totalRetries       int64
+	succeededAfter     int64
+	exhausted          int64
+	nonRetryable       int64
+	retriesByErrorType map[string]int64
+	outcomes           map[retryOutcomeKey]int64
+	lastRetryTime      time.Time
+	lastRetryError     string
+}
+
+// RetryOutcome is how an operation run by a RetryManager ended
+type RetryOutcome string
+
+// Retry outcomes
+const (
+	RetryOutcomeFirstTry     RetryOutcome = "success_first_try"
+	RetryOutcomeAfterRetry   RetryOutcome = "success_after_retry"
+	RetryOutcomeExhausted    RetryOutcome = "exhausted" // out of attempts or retry budget
+	RetryOutcomeNonRetryable RetryOutcome = "non_retryable"
+)
+
+// RetryOutcomeCount counts the operations on an endpoint that ended with an outcome. ErrorType is
+// the type of the last error of the operation, "none" for successes on the first try.
+type RetryOutcomeCount struct {
+	Endpoint  string       `json:"endpoint"`
+	ErrorType string       `json:"error_type"`
+	Outcome   RetryOutcome `json:"outcome"`
+	Count     int64        `json:"count"`
+}
+
+type retryOutcomeKey struct {
+	endpoint  string
+	errorType string
+	outcome   RetryOutcome
 }
 
 // RetryConfig defines retry behavior with enhanced options
@@ -29,7 +72,13 @@ type RetryConfig struct {
 
 	// Timeout handling
 	PerAttemptTimeout time.Duration `json:"per_attempt_timeout"`
-	TotalTimeout      time.Duration `json:"total_timeout"`
+	TotalTimeout      time.Duration `json:"total_timeout"` // retry budget across all attempts
+
+	// RetryableErrorTypes limits retries to these error types. Empty uses the classifier's default.
+	RetryableErrorTypes []ErrorType `json:"retryable_error_types,omitempty"`
+
+	Clock  Clock          `json:"-"` // Time source for backoff and the retry budget; nil uses SystemClock
+	Random func() float64 `json:"-"` // Source of jitter in [0, 1); nil uses math/rand
 }
 
 // DefaultRetryConfig returns a default retry configuration
@@ -50,9 +99,17 @@ func DefaultRetryConfig() RetryConfig {
 
 // NewRetryManager creates a new retry manager with enhanced capabilities
 func NewRetryManager(config RetryConfig) *RetryManager {
+	random := config.Random
+	if random == nil {
+		random = rand.Float64
+	}
 	return &RetryManager{
-		config:          config,
-		errorClassifier: NewErrorClassifier(),
+		config:             config,
+		clock:              clockOrSystem(config.Clock),
+		random:             random,
+		errorClassifier:    NewErrorClassifier(),
+		retriesByErrorType: make(map[string]int64),
+		outcomes:           make(map[retryOutcomeKey]int64),
 	}
 }
 
@@ -73,11 +130,15 @@ type RetryableOperation func(ctx context.Context, retryCtx *RetryContext) error
 func (rm *RetryManager) Execute(ctx context.Context, endpoint, method string, operation RetryableOperation) error {
 	retryCtx := &RetryContext{
 		Attempt:   0,
-		StartTime: time.Now(),
+		StartTime: rm.clock.Now(),
 		Endpoint:  endpoint,
 		Method:    method,
 	}
 
+	rm.statsMutex.Lock()
+	rm.totalOperations++
+	rm.statsMutex.Unlock()
+
 	// Create context with total timeout if specified
 	var operationCtx context.Context
 	var cancel context.CancelFunc
@@ -89,12 +150,14 @@ func (rm *RetryManager) Execute(ctx context.Context, endpoint, method string, op
 		operationCtx = ctx
 	}
 
+	var lastErr error // classified error of the previous attempt
 	for retryCtx.Attempt < rm.config.MaxAttempts {
 		retryCtx.Attempt++
-		retryCtx.TotalDuration = time.Since(retryCtx.StartTime)
+		retryCtx.TotalDuration = rm.clock.Now().Sub(retryCtx.StartTime)
 
 		// Check if total timeout exceeded
 		if rm.config.TotalTimeout > 0 && retryCtx.TotalDuration >= rm.config.TotalTimeout {
+			rm.recordOutcome(endpoint, lastErr, RetryOutcomeExhausted)
 			return &ManticoreError{
 				StatusCode: 0,
 				Message:    fmt.Sprintf("total timeout exceeded after %v", retryCtx.TotalDuration),
@@ -125,6 +188,9 @@ func (rm *RetryManager) Execute(ctx context.Context, endpoint, method string, op
 			if retryCtx.Attempt > 1 {
 				log.Printf("Operation succeeded after %d attempts (total duration: %v) for %s %s",
 					retryCtx.Attempt, retryCtx.TotalDuration, method, endpoint)
+				rm.recordOutcome(endpoint, lastErr, RetryOutcomeAfterRetry)
+			} else {
+				rm.recordOutcome(endpoint, nil, RetryOutcomeFirstTry)
 			}
 			return nil
 		}
@@ -135,9 +201,10 @@ func (rm *RetryManager) Execute(ctx context.Context, endpoint, method string, op
 		classifiedErr := rm.errorClassifier.ClassifyError(err, endpoint, method)
 
 		// Check if error is retryable
-		if !IsRetryableError(classifiedErr) {
+		if !rm.isRetryable(classifiedErr) {
 			log.Printf("Non-retryable error on attempt %d for %s %s: %v",
 				retryCtx.Attempt, method, endpoint, classifiedErr)
+			rm.recordOutcome(endpoint, classifiedErr, RetryOutcomeNonRetryable)
 			return classifiedErr
 		}
 
@@ -145,6 +212,7 @@ func (rm *RetryManager) Execute(ctx context.Context, endpoint, method string, op
 		if retryCtx.Attempt >= rm.config.MaxAttempts {
 			log.Printf("Max attempts (%d) exceeded for %s %s, last error: %v",
 				rm.config.MaxAttempts, method, endpoint, classifiedErr)
+			rm.recordOutcome(endpoint, classifiedErr, RetryOutcomeExhausted)
 
 			return &ManticoreError{
 				StatusCode: 0,
@@ -158,6 +226,8 @@ func (rm *RetryManager) Execute(ctx context.Context, endpoint, method string, op
 
 		// Calculate backoff delay
 		delay := rm.calculateBackoffDelay(classifiedErr, retryCtx.Attempt)
+		rm.recordRetry(classifiedErr)
+		lastErr = classifiedErr
 
 		log.Printf("Retrying operation (attempt %d/%d) after %v delay for %s %s due to error: %v",
 			retryCtx.Attempt+1, rm.config.MaxAttempts, delay, method, endpoint, classifiedErr)
@@ -166,7 +236,7 @@ func (rm *RetryManager) Execute(ctx context.Context, endpoint, method string, op
 		select {
 		case <-operationCtx.Done():
 			return operationCtx.Err()
-		case <-time.After(delay):
+		case <-rm.clock.After(delay):
 			// Continue to next attempt
 		}
 	}
@@ -214,45 +284,14 @@ func (rm *RetryManager) getErrorMultiplier(err error) float64 {
 		return 1.0
 	}
 
-	// Check for our custom error types first
-	if manticoreErr, ok := err.(*ManticoreError); ok {
-		switch manticoreErr.ErrorType {
-		case ErrorTypeTimeout:
-			return rm.config.TimeoutMultiplier
-		case ErrorTypeConnectionRefused:
-			return rm.config.ServiceUnavailableMultiplier
-		case ErrorTypeConnectionReset, ErrorTypeNetwork:
-			return rm.config.ConnectionMultiplier
-		case ErrorTypeRateLimit:
-			return rm.config.RateLimitMultiplier
-		default:
-			return 1.0
-		}
-	}
-
-	if connErr, ok := err.(*ConnectionError); ok {
-		switch connErr.ErrorType {
-		case ErrorTypeTimeout:
-			return rm.config.TimeoutMultiplier
-		case ErrorTypeConnectionRefused:
-			return rm.config.ServiceUnavailableMultiplier
-		case ErrorTypeConnectionReset, ErrorTypeNetwork:
-			return rm.config.ConnectionMultiplier
-		default:
-			return 1.0
-		}
-	}
-
-	// Fallback to basic error string analysis
-	errorStr := err.Error()
-	switch {
-	case containsAny(errorStr, []string{"timeout", "deadline exceeded"}):
+	switch GetErrorType(err) {
+	case ErrorTypeTimeout:
 		return rm.config.TimeoutMultiplier
-	case containsAny(errorStr, []string{"connection refused", "service unavailable"}):
+	case ErrorTypeConnectionRefused:
 		return rm.config.ServiceUnavailableMultiplier
-	case containsAny(errorStr, []string{"connection reset", "broken pipe", "network"}):
+	case ErrorTypeConnectionReset, ErrorTypeNetwork:
 		return rm.config.ConnectionMultiplier
-	case containsAny(errorStr, []string{"rate limit", "too many requests"}):
+	case ErrorTypeRateLimit:
 		return rm.config.RateLimitMultiplier
 	default:
 		return 1.0
@@ -270,7 +309,7 @@ func (rm *RetryManager) calculateJitter(delay time.Duration) time.Duration {
 
 	// Generate random jitter between 0 and maxJitter
 	if maxJitter > 0 {
-		return time.Duration(rand.Int63n(int64(maxJitter)))
+		return time.Duration(rm.random() * float64(maxJitter))
 	}
 
 	return 0
@@ -285,11 +324,15 @@ func (rm *RetryManager) ExecuteWithCustomBackoff(
 ) error {
 	retryCtx := &RetryContext{
 		Attempt:   0,
-		StartTime: time.Now(),
+		StartTime: rm.clock.Now(),
 		Endpoint:  endpoint,
 		Method:    method,
 	}
 
+	rm.statsMutex.Lock()
+	rm.totalOperations++
+	rm.statsMutex.Unlock()
+
 	// Create context with total timeout if specified
 	var operationCtx context.Context
 	var cancel context.CancelFunc
@@ -301,9 +344,10 @@ func (rm *RetryManager) ExecuteWithCustomBackoff(
 		operationCtx = ctx
 	}
 
+	var lastErr error // classified error of the previous attempt
 	for retryCtx.Attempt < rm.config.MaxAttempts {
 		retryCtx.Attempt++
-		retryCtx.TotalDuration = time.Since(retryCtx.StartTime)
+		retryCtx.TotalDuration = rm.clock.Now().Sub(retryCtx.StartTime)
 
 		// Create per-attempt context with timeout
 		var attemptCtx context.Context
@@ -321,6 +365,11 @@ func (rm *RetryManager) ExecuteWithCustomBackoff(
 		attemptCancel()
 
 		if err == nil {
+			if retryCtx.Attempt > 1 {
+				rm.recordOutcome(endpoint, lastErr, RetryOutcomeAfterRetry)
+			} else {
+				rm.recordOutcome(endpoint, nil, RetryOutcomeFirstTry)
+			}
 			return nil
 		}
 
@@ -330,12 +379,15 @@ func (rm *RetryManager) ExecuteWithCustomBackoff(
 		classifiedErr := rm.errorClassifier.ClassifyError(err, endpoint, method)
 
 		// Check if error is retryable
-		if !IsRetryableError(classifiedErr) {
+		if !rm.isRetryable(classifiedErr) {
+			rm.recordOutcome(endpoint, classifiedErr, RetryOutcomeNonRetryable)
 			return classifiedErr
 		}
 
 		// Check if we've exhausted all attempts
 		if retryCtx.Attempt >= rm.config.MaxAttempts {
+			rm.recordOutcome(endpoint, classifiedErr, RetryOutcomeExhausted)
+
 			return &ManticoreError{
 				StatusCode: 0,
 				Message:    fmt.Sprintf("max retry attempts (%d) exceeded", rm.config.MaxAttempts),
@@ -348,6 +400,8 @@ func (rm *RetryManager) ExecuteWithCustomBackoff(
 
 		// Calculate custom backoff delay
 		delay := backoffCalculator(retryCtx.Attempt, classifiedErr)
+		rm.recordRetry(classifiedErr)
+		lastErr = classifiedErr
 
 		log.Printf("Retrying operation (attempt %d/%d) after custom %v delay for %s %s",
 			retryCtx.Attempt+1, rm.config.MaxAttempts, delay, method, endpoint)
@@ -356,7 +410,7 @@ func (rm *RetryManager) ExecuteWithCustomBackoff(
 		select {
 		case <-operationCtx.Done():
 			return operationCtx.Err()
-		case <-time.After(delay):
+		case <-rm.clock.After(delay):
 			// Continue to next attempt
 		}
 	}
@@ -371,22 +425,129 @@ func (rm *RetryManager) ExecuteWithCustomBackoff(
 	}
 }
 
+// isRetryable reports whether err should be retried under this manager's policy
+func (rm *RetryManager) isRetryable(err error) bool {
+	if len(rm.config.RetryableErrorTypes) == 0 {
+		return IsRetryableError(err)
+	}
+
+	errorType := GetErrorType(err)
+	for _, retryable := range rm.config.RetryableErrorTypes {
+		if errorType == retryable {
+			return true
+		}
+	}
+	return false
+}
+
+// recordRetry counts a retry caused by err
+func (rm *RetryManager) recordRetry(err error) {
+	rm.statsMutex.Lock()
+	defer rm.statsMutex.Unlock()
+
+	rm.totalRetries++
+	rm.retriesByErrorType[GetErrorType(err).String()]++
+	rm.lastRetryTime = rm.clock.Now()
+	rm.lastRetryError = err.Error()
+}
+
+// recordOutcome counts an operation on endpoint that ended with outcome after err, nil for
+// successes on the first try
+func (rm *RetryManager) recordOutcome(endpoint string, err error, outcome RetryOutcome) {
+	errorType := "none"
+	if err != nil {
+		errorType = GetErrorType(err).String()
+	}
+
+	rm.statsMutex.Lock()
+	defer rm.statsMutex.Unlock()
+
+	switch outcome {
+	case RetryOutcomeAfterRetry:
+		rm.succeededAfter++
+	case RetryOutcomeExhausted:
+		rm.exhausted++
+	case RetryOutcomeNonRetryable:
+		rm.nonRetryable++
+	}
+	rm.outcomes[retryOutcomeKey{endpoint: endpointPath(endpoint), errorType: errorType, outcome: outcome}]++
+}
+
+// endpointPath returns the path of an endpoint URL, so outcomes are not repeated per Manticore URL
+func endpointPath(endpoint string) string {
+	if u, err := url.Parse(endpoint); err == nil && u.Path != "" {
+		return u.Path
+	}
+	return endpoint
+}
+
 // GetRetryStats returns statistics about retry behavior
 func (rm *RetryManager) GetRetryStats() RetryStats {
+	rm.statsMutex.Lock()
+	defer rm.statsMutex.Unlock()
+
+	retriesByErrorType := make(map[string]int64, len(rm.retriesByErrorType))
+	for errorType, count := range rm.retriesByErrorType {
+		retriesByErrorType[errorType] = count
+	}
+
+	var retryableErrors []string
+	for _, errorType := range rm.config.RetryableErrorTypes {
+		retryableErrors = append(retryableErrors, errorType.String())
+	}
+
+	outcomes := make([]RetryOutcomeCount, 0, len(rm.outcomes))
+	for key, count := range rm.outcomes {
+		outcomes = append(outcomes, RetryOutcomeCount{Endpoint: key.endpoint, ErrorType: key.errorType, Outcome: key.outcome, Count: count})
+	}
+	sort.Slice(outcomes, func(i, j int) bool {
+		a, b := outcomes[i], outcomes[j]
+		if a.Endpoint != b.Endpoint {
+			return a.Endpoint < b.Endpoint
+		}
+		if a.Outcome != b.Outcome {
+			return a.Outcome < b.Outcome
+		}
+		return a.ErrorType < b.ErrorType
+	})
+
 	return RetryStats{
-		MaxAttempts:   rm.config.MaxAttempts,
-		BaseDelay:     rm.config.BaseDelay,
-		MaxDelay:      rm.config.MaxDelay,
-		JitterPercent: rm.config.JitterPercent,
+		RetryableErrors:      retryableErrors,
+		MaxAttempts:          rm.config.MaxAttempts,
+		BaseDelay:            rm.config.BaseDelay,
+		MaxDelay:             rm.config.MaxDelay,
+		JitterPercent:        rm.config.JitterPercent,
+		TotalOperations:      rm.totalOperations,
+		TotalRetries:         rm.totalRetries,
+		SucceededAfterRetry:  rm.succeededAfter,
+		RetriesExhausted:     rm.exhausted,
+		NonRetryableFailures: rm.nonRetryable,
+		RetriesByErrorType:   retriesByErrorType,
+		Outcomes:             outcomes,
+		LastRetryTime:        rm.lastRetryTime,
+		LastRetryError:       rm.lastRetryError,
 	}
 }
 
-// RetryStats provides information about retry configuration
+// RetryStats provides information about retry configuration and outcomes
 type RetryStats struct {
-	MaxAttempts   int           `json:"max_attempts"`
-	BaseDelay     time.Duration `json:"base_delay"`
-	MaxDelay      time.Duration `json:"max_delay"`
-	JitterPercent float64       `json:"jitter_percent"`
+	MaxAttempts     int           `json:"max_attempts"`
+	BaseDelay       time.Duration `json:"base_delay"`
+	MaxDelay        time.Duration `json:"max_delay"`
+	JitterPercent   float64       `json:"jitter_percent"`
+	RetryableErrors []string      `json:"retryable_errors,omitempty"`
+
+	TotalOperations      int64            `json:"total_operations"`
+	TotalRetries         int64            `json:"total_retries"`
+	SucceededAfterRetry  int64            `json:"succeeded_after_retry"`
+	RetriesExhausted     int64            `json:"retries_exhausted"`
+	NonRetryableFailures int64            `json:"non_retryable_failures"`
+	RetriesByErrorType   map[string]int64 `json:"retries_by_error_type"`
+	LastRetryTime        time.Time        `json:"last_retry_time"`
+	LastRetryError       string           `json:"last_retry_error,omitempty"`
+
+	// Outcomes counts the operations by endpoint, last error type and outcome
+	Outcomes []RetryOutcomeCount `json:"outcomes"`
 }
 
 // containsAny checks if a string contains any of the given substrings
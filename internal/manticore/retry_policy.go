@@ -0,0 +1,165 @@
+package manticore
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OperationClass groups operations that share a retry policy
+type OperationClass string
+
+// Operation classes with their own retry policy. Operations outside these classes,
+// such as schema changes, use the client's base RetryConfig.
+const (
+	OperationClassSearch    OperationClass = "search"
+	OperationClassBulk      OperationClass = "bulk"
+	OperationClassEmbedding OperationClass = "embedding"
+)
+
+// OperationClasses lists the operation classes that accept a retry policy
+var OperationClasses = []OperationClass{OperationClassSearch, OperationClassBulk, OperationClassEmbedding}
+
+// defaultRetryPolicies derives per-class retry policies from the base retry configuration.
+// Searches are cheap and keep the base policy. A bulk batch can run for the whole BatchTimeout,
+// so it is attempted at most twice and a timed out batch is not resent. AI searches wait on
+// embedding generation, so attempts are bounded by the AI operation timeout instead of the
+// base per-attempt timeout.
+func defaultRetryPolicies(base RetryConfig, bulkConfig BulkConfig, timeouts OperationTimeouts) map[OperationClass]RetryConfig {
+	bulk := base
+	bulk.MaxAttempts = min(base.MaxAttempts, 2)
+	bulk.PerAttemptTimeout = bulkConfig.BatchTimeout
+	bulk.RetryableErrorTypes = []ErrorType{
+		ErrorTypeNetwork,
+		ErrorTypeConnectionRefused,
+		ErrorTypeConnectionReset,
+		ErrorTypeDNS,
+		ErrorTypeHTTPServer,
+		ErrorTypeRateLimit,
+	}
+
+	embedding := base
+	embedding.MaxAttempts = min(base.MaxAttempts, 3)
+	embedding.PerAttemptTimeout = timeouts.AI
+
+	return map[OperationClass]RetryConfig{
+		OperationClassSearch:    base,
+		OperationClassBulk:      bulk,
+		OperationClassEmbedding: embedding,
+	}
+}
+
+// resolveRetryPolicies applies configured per-class overrides on top of the default policies
+func resolveRetryPolicies(base RetryConfig, config HTTPClientConfig) map[OperationClass]RetryConfig {
+	policies := defaultRetryPolicies(base, config.BulkConfig, config.Timeouts.withDefaults())
+	for class, override := range config.RetryPolicies {
+		defaults, ok := policies[class]
+		if !ok {
+			continue
+		}
+		policies[class] = override.withDefaults(defaults)
+	}
+	return policies
+}
+
+// withDefaults fills unset fields from defaults, so a policy override only needs the values it changes
+func (c RetryConfig) withDefaults(defaults RetryConfig) RetryConfig {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaults.MaxAttempts
+	}
+	for _, pair := range []struct{ value, fallback *time.Duration }{
+		{&c.BaseDelay, &defaults.BaseDelay},
+		{&c.MaxDelay, &defaults.MaxDelay},
+		{&c.PerAttemptTimeout, &defaults.PerAttemptTimeout},
+		{&c.TotalTimeout, &defaults.TotalTimeout},
+	} {
+		if *pair.value <= 0 {
+			*pair.value = *pair.fallback
+		}
+	}
+	for _, pair := range []struct{ value, fallback *float64 }{
+		{&c.JitterPercent, &defaults.JitterPercent},
+		{&c.TimeoutMultiplier, &defaults.TimeoutMultiplier},
+		{&c.ConnectionMultiplier, &defaults.ConnectionMultiplier},
+		{&c.ServiceUnavailableMultiplier, &defaults.ServiceUnavailableMultiplier},
+		{&c.RateLimitMultiplier, &defaults.RateLimitMultiplier},
+	} {
+		if *pair.value <= 0 {
+			*pair.value = *pair.fallback
+		}
+	}
+	if len(c.RetryableErrorTypes) == 0 {
+		c.RetryableErrorTypes = defaults.RetryableErrorTypes
+	}
+	if c.Clock == nil {
+		c.Clock = defaults.Clock
+	}
+	if c.Random == nil {
+		c.Random = defaults.Random
+	}
+	return c
+}
+
+// loadRetryPoliciesFromEnvironment reads MANTICORE_RETRY_<CLASS>_{MAX_ATTEMPTS,BASE_DELAY,MAX_DELAY,
+// ATTEMPT_TIMEOUT,BUDGET,RETRYABLE_ERRORS} for each operation class. RETRYABLE_ERRORS is a comma
+// separated list of error type names such as "network,connection_refused,http_server".
+func loadRetryPoliciesFromEnvironment(policies *map[OperationClass]RetryConfig) error {
+	for _, class := range OperationClasses {
+		prefix := "MANTICORE_RETRY_" + strings.ToUpper(string(class)) + "_"
+		policy := (*policies)[class]
+		changed := false
+
+		if valueStr := os.Getenv(prefix + "MAX_ATTEMPTS"); valueStr != "" {
+			value, err := strconv.Atoi(valueStr)
+			if err != nil || value <= 0 {
+				return fmt.Errorf("invalid %sMAX_ATTEMPTS: %q", prefix, valueStr)
+			}
+			policy.MaxAttempts = value
+			changed = true
+		}
+
+		for _, setting := range []struct {
+			name  string
+			value *time.Duration
+		}{
+			{"BASE_DELAY", &policy.BaseDelay},
+			{"MAX_DELAY", &policy.MaxDelay},
+			{"ATTEMPT_TIMEOUT", &policy.PerAttemptTimeout},
+			{"BUDGET", &policy.TotalTimeout},
+		} {
+			valueStr := os.Getenv(prefix + setting.name)
+			if valueStr == "" {
+				continue
+			}
+			value, err := time.ParseDuration(valueStr)
+			if err != nil || value <= 0 {
+				return fmt.Errorf("invalid %s%s: %q", prefix, setting.name, valueStr)
+			}
+			*setting.value = value
+			changed = true
+		}
+
+		if valueStr := os.Getenv(prefix + "RETRYABLE_ERRORS"); valueStr != "" {
+			var errorTypes []ErrorType
+			for _, name := range strings.Split(valueStr, ",") {
+				errorType, err := ParseErrorType(strings.TrimSpace(name))
+				if err != nil {
+					return fmt.Errorf("invalid %sRETRYABLE_ERRORS: %w", prefix, err)
+				}
+				errorTypes = append(errorTypes, errorType)
+			}
+			policy.RetryableErrorTypes = errorTypes
+			changed = true
+		}
+
+		if changed {
+			if *policies == nil {
+				*policies = make(map[OperationClass]RetryConfig)
+			}
+			(*policies)[class] = policy
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package manticore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestRetryManagerRetryableErrorTypes(t *testing.T) {
+	config := DefaultRetryConfig()
+	config.MaxAttempts = 3
+	config.BaseDelay = time.Millisecond
+	config.MaxDelay = 5 * time.Millisecond
+	config.RetryableErrorTypes = []ErrorType{ErrorTypeConnectionRefused}
+	retryManager := NewRetryManager(config)
+
+	attempts := 0
+	err := retryManager.Execute(context.Background(), "/test", "POST", func(ctx context.Context, retryCtx *RetryContext) error {
+		attempts++
+		return errors.New("i/o timeout")
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("Expected a timeout to fail without retries, got %d attempts and error %v", attempts, err)
+	}
+
+	attempts = 0
+	err = retryManager.Execute(context.Background(), "/test", "POST", func(ctx context.Context, retryCtx *RetryContext) error {
+		attempts++
+		return errors.New("connection refused")
+	})
+	if err == nil || attempts != 3 {
+		t.Errorf("Expected connection refused to be retried 3 times, got %d attempts and error %v", attempts, err)
+	}
+
+	if stats := retryManager.GetRetryStats(); len(stats.RetryableErrors) != 1 || stats.RetryableErrors[0] != "connection_refused" {
+		t.Errorf("Expected retryable errors in stats, got %v", stats.RetryableErrors)
+	}
+}
+
+func TestResolveRetryPolicies(t *testing.T) {
+	config := DefaultHTTPClientConfig("http://localhost:9308")
+	config.RetryPolicies = map[OperationClass]RetryConfig{
+		OperationClassSearch: {MaxAttempts: 1},
+	}
+
+	policies := resolveRetryPolicies(DefaultRetryConfig(), config)
+
+	search := policies[OperationClassSearch]
+	if search.MaxAttempts != 1 || search.BaseDelay != DefaultRetryConfig().BaseDelay {
+		t.Errorf("Expected search override with inherited delays, got %+v", search)
+	}
+
+	bulk := policies[OperationClassBulk]
+	if bulk.MaxAttempts != 2 || bulk.PerAttemptTimeout != config.BulkConfig.BatchTimeout {
+		t.Errorf("Unexpected bulk defaults: %+v", bulk)
+	}
+	for _, errorType := range bulk.RetryableErrorTypes {
+		if errorType == ErrorTypeTimeout {
+			t.Error("Bulk operations should not retry timeouts by default")
+		}
+	}
+
+	if embedding := policies[OperationClassEmbedding]; embedding.PerAttemptTimeout != config.Timeouts.AI {
+		t.Errorf("Expected embedding attempts bounded by the AI timeout, got %v", embedding.PerAttemptTimeout)
+	}
+}
+
+func TestRetryPoliciesFromEnvironment(t *testing.T) {
+	os.Setenv("MANTICORE_RETRY_BULK_MAX_ATTEMPTS", "1")
+	os.Setenv("MANTICORE_RETRY_SEARCH_BUDGET", "2s")
+	os.Setenv("MANTICORE_RETRY_EMBEDDING_RETRYABLE_ERRORS", "timeout, http_server")
+	defer os.Unsetenv("MANTICORE_RETRY_BULK_MAX_ATTEMPTS")
+	defer os.Unsetenv("MANTICORE_RETRY_SEARCH_BUDGET")
+	defer os.Unsetenv("MANTICORE_RETRY_EMBEDDING_RETRYABLE_ERRORS")
+
+	var policies map[OperationClass]RetryConfig
+	if err := loadRetryPoliciesFromEnvironment(&policies); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if policies[OperationClassBulk].MaxAttempts != 1 {
+		t.Errorf("Expected bulk max attempts 1, got %d", policies[OperationClassBulk].MaxAttempts)
+	}
+	if policies[OperationClassSearch].TotalTimeout != 2*time.Second {
+		t.Errorf("Expected search budget 2s, got %v", policies[OperationClassSearch].TotalTimeout)
+	}
+	embeddingErrors := policies[OperationClassEmbedding].RetryableErrorTypes
+	if len(embeddingErrors) != 2 || embeddingErrors[0] != ErrorTypeTimeout || embeddingErrors[1] != ErrorTypeHTTPServer {
+		t.Errorf("Unexpected embedding retryable errors: %v", embeddingErrors)
+	}
+
+	os.Setenv("MANTICORE_RETRY_EMBEDDING_RETRYABLE_ERRORS", "flaky")
+	if err := loadRetryPoliciesFromEnvironment(&policies); err == nil {
+		t.Error("Expected error for unknown error type")
+	}
+}
+
+func TestBulkIndexUsesBulkRetryPolicy(t *testing.T) {
+	var bulkRequests atomic.Int32
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/bulk" {
+			bulkRequests.Add(1)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":"temporary failure"}`))
+	})
+	defer server.Close()
+
+	config := DefaultHTTPClientConfig(server.URL)
+	config.RetryConfig.MaxAttempts = 5
+	config.RetryConfig.BaseDelay = time.Millisecond
+	config.RetryConfig.MaxDelay = 5 * time.Millisecond
+	config.CircuitBreakerConfig.FailureThreshold = 100
+	config.RetryPolicies = map[OperationClass]RetryConfig{
+		OperationClassBulk: {MaxAttempts: 2},
+	}
+	client := NewHTTPClient(config).(*manticoreHTTPClient)
+	defer client.Close()
+
+	docs := []*models.Document{{ID: 1, Title: "t", Content: "c", URL: "u"}}
+	if err := client.bulkIndexUnified(docs); err == nil {
+		t.Fatal("Expected bulk indexing to fail")
+	}
+
+	if got := bulkRequests.Load(); got != 2 {
+		t.Errorf("Expected 2 bulk attempts, got %d", got)
+	}
+	if stats := client.GetResilienceStats().RetryByClass[OperationClassBulk]; stats.TotalOperations != 1 {
+		t.Errorf("Expected bulk retry stats to record the operation, got %+v", stats)
+	}
+}
@@ -3,6 +3,7 @@ package manticore
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -250,6 +251,60 @@ func TestRetryManager_GetRetryStats(t *testing.T) {
 	}
 }
 
+func TestRetryManager_GetRetryStatsOutcomes(t *testing.T) {
+	config := DefaultRetryConfig()
+	config.MaxAttempts = 2
+	config.BaseDelay = time.Millisecond
+	config.MaxDelay = 5 * time.Millisecond
+	retryManager := NewRetryManager(config)
+
+	attempts := 0
+	retryManager.Execute(context.Background(), "/test", "GET", func(ctx context.Context, retryCtx *RetryContext) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	retryManager.Execute(context.Background(), "/test", "GET", func(ctx context.Context, retryCtx *RetryContext) error {
+		return errors.New("i/o timeout")
+	})
+	retryManager.Execute(context.Background(), "/test", "GET", func(ctx context.Context, retryCtx *RetryContext) error {
+		return errors.New("unauthorized access")
+	})
+
+	stats := retryManager.GetRetryStats()
+
+	if stats.TotalOperations != 3 {
+		t.Errorf("Expected 3 operations, got %d", stats.TotalOperations)
+	}
+	if stats.TotalRetries != 2 {
+		t.Errorf("Expected 2 retries, got %d", stats.TotalRetries)
+	}
+	if stats.SucceededAfterRetry != 1 || stats.RetriesExhausted != 1 || stats.NonRetryableFailures != 1 {
+		t.Errorf("Unexpected outcome counters: %+v", stats)
+	}
+	if stats.RetriesByErrorType[ErrorTypeTimeout.String()] != 1 {
+		t.Errorf("Expected one timeout retry, got %v", stats.RetriesByErrorType)
+	}
+	if stats.LastRetryError == "" || stats.LastRetryTime.IsZero() {
+		t.Errorf("Expected last retry details to be recorded")
+	}
+
+	retryManager.Execute(context.Background(), "http://manticore:9308/test", "GET", func(ctx context.Context, retryCtx *RetryContext) error {
+		return nil
+	})
+	expected := []RetryOutcomeCount{
+		{Endpoint: "/test", ErrorType: "timeout", Outcome: RetryOutcomeExhausted, Count: 1},
+		{Endpoint: "/test", ErrorType: "authentication", Outcome: RetryOutcomeNonRetryable, Count: 1},
+		{Endpoint: "/test", ErrorType: "connection_refused", Outcome: RetryOutcomeAfterRetry, Count: 1},
+		{Endpoint: "/test", ErrorType: "none", Outcome: RetryOutcomeFirstTry, Count: 1},
+	}
+	if outcomes := retryManager.GetRetryStats().Outcomes; !reflect.DeepEqual(outcomes, expected) {
+		t.Errorf("Expected outcomes %+v, got %+v", expected, outcomes)
+	}
+}
+
 func TestDefaultRetryConfig(t *testing.T) {
 	config := DefaultRetryConfig()
 
@@ -1,15 +1,24 @@
 package manticore
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/ad/manticoresearch-go/internal/models"
 )
 
-// SearchAdapter provides a unified search interface for both client types
+// SearchAdapter runs basic and full-text searches through any ClientInterface implementation
 type SearchAdapter struct {
-	client ClientInterface
+	client   ClientInterface
+	source   *SourceFilter
+	statuses []models.DocumentStatus // Document statuses matched by searches, nil for DefaultSearchStatuses
+	recency  RecencyOptions
+	tags     TagFilter
+	ids      IDFilter
+	regex    RegexFilter
+	facets   HistogramFacets
+	tolerant bool // Basic searches also match substrings and misspellings, see WithSpellTolerance
 }
 
 // NewSearchAdapter creates a new search adapter
@@ -19,24 +28,96 @@ func NewSearchAdapter(client ClientInterface) *SearchAdapter {
 	}
 }
 
+// WithSource returns an adapter whose basic and full-text searches only fetch the stored fields selected by source
+func (sa *SearchAdapter) WithSource(source *SourceFilter) *SearchAdapter {
+	adapter := *sa
+	adapter.source = source
+	return &adapter
+}
+
+// WithStatuses returns an adapter whose basic and full-text searches match documents with one of statuses
+func (sa *SearchAdapter) WithStatuses(statuses []models.DocumentStatus) *SearchAdapter {
+	adapter := *sa
+	adapter.statuses = statuses
+	return &adapter
+}
+
+// WithRecency returns an adapter whose basic and full-text searches apply options
+func (sa *SearchAdapter) WithRecency(options RecencyOptions) *SearchAdapter {
+	adapter := *sa
+	adapter.recency = options
+	return &adapter
+}
+
+// WithTags returns an adapter whose basic and full-text searches apply filter
+func (sa *SearchAdapter) WithTags(filter TagFilter) *SearchAdapter {
+	adapter := *sa
+	adapter.tags = filter
+	return &adapter
+}
+
+// WithIDs returns an adapter whose basic and full-text searches apply filter
+func (sa *SearchAdapter) WithIDs(filter IDFilter) *SearchAdapter {
+	adapter := *sa
+	adapter.ids = filter
+	return &adapter
+}
+
+// WithRegex returns an adapter whose basic and full-text searches apply filter
+func (sa *SearchAdapter) WithRegex(filter RegexFilter) *SearchAdapter {
+	adapter := *sa
+	adapter.regex = filter
+	return &adapter
+}
+
+// WithHistogramFacets returns an adapter whose basic and full-text searches also return facets
+func (sa *SearchAdapter) WithHistogramFacets(facets HistogramFacets) *SearchAdapter {
+	adapter := *sa
+	adapter.facets = facets
+	return &adapter
+}
+
+// WithSpellTolerance returns an adapter whose basic searches also match indexed words containing
+// the query words and, on servers supporting it, words a few edits away
+func (sa *SearchAdapter) WithSpellTolerance(enabled bool) *SearchAdapter {
+	adapter := *sa
+	adapter.tolerant = enabled
+	return &adapter
+}
+
+// searchStatuses returns the statuses searches are restricted to
+func (sa *SearchAdapter) searchStatuses() []models.DocumentStatus {
+	if len(sa.statuses) == 0 {
+		return DefaultSearchStatuses
+	}
+	return sa.statuses
+}
+
 // BasicSearch performs basic text matching search
 func (sa *SearchAdapter) BasicSearch(query string, page, pageSize int) (*models.SearchResponse, error) {
-	switch client := sa.client.(type) {
-	case *manticoreHTTPClient:
-		return sa.basicSearchHTTP(client, query, page, pageSize)
-	default:
-		return nil, fmt.Errorf("unsupported client type")
+	return sa.BasicSearchContext(context.Background(), query, page, pageSize)
+}
+
+// BasicSearchContext performs basic text matching search that is cancelled with ctx
+func (sa *SearchAdapter) BasicSearchContext(ctx context.Context, query string, page, pageSize int) (*models.SearchResponse, error) {
+	offset, limit := pageWindow(page, pageSize)
+	request := NewBasicSearchRequest("documents", query, limit, offset)
+	if sa.tolerant {
+		caps := sa.client.GetCapabilities()
+		request = WithSpellTolerance(request, caps != nil && caps.Fuzzy)
 	}
+	return sa.search(ctx, models.SearchModeBasic, "BasicSearch", query, page, request)
 }
 
 // FullTextSearch performs full-text search
 func (sa *SearchAdapter) FullTextSearch(query string, page, pageSize int) (*models.SearchResponse, error) {
-	switch client := sa.client.(type) {
-	case *manticoreHTTPClient:
-		return sa.fullTextSearchHTTP(client, query, page, pageSize)
-	default:
-		return nil, fmt.Errorf("unsupported client type")
-	}
+	return sa.FullTextSearchContext(context.Background(), query, page, pageSize)
+}
+
+// FullTextSearchContext performs full-text search that is cancelled with ctx
+func (sa *SearchAdapter) FullTextSearchContext(ctx context.Context, query string, page, pageSize int) (*models.SearchResponse, error) {
+	offset, limit := pageWindow(page, pageSize)
+	return sa.search(ctx, models.SearchModeFullText, "FullTextSearch", query, page, NewFullTextSearchRequest("documents", query, limit, offset))
 }
 
 // GetAllDocuments retrieves all documents
@@ -49,72 +130,130 @@ func (sa *SearchAdapter) GetAllDocumentsWithVectors() ([]*models.Document, [][]f
 	return sa.client.GetAllDocumentsWithVectors()
 }
 
-// basicSearchHTTP performs basic search using the HTTP client
-func (sa *SearchAdapter) basicSearchHTTP(client *manticoreHTTPClient, query string, page, pageSize int) (*models.SearchResponse, error) {
-	log.Printf("BasicSearch (HTTP): query='%s', page=%d, pageSize=%d", query, page, pageSize)
-
-	offset := int32((page - 1) * pageSize)
-	limit := int32(pageSize)
-
-	// Create basic search request
-	searchReq := client.CreateBasicSearchRequest("documents", query, limit, offset)
+// GetAllDocumentsWithVectorsContext retrieves all documents with their vector data, cancelled with ctx
+func (sa *SearchAdapter) GetAllDocumentsWithVectorsContext(ctx context.Context) ([]*models.Document, [][]float64, error) {
+	return sa.client.GetAllDocumentsWithVectorsContext(ctx)
+}
 
-	// Execute search
-	resp, err := client.SearchWithRequest(searchReq)
-	if err != nil {
-		log.Printf("BasicSearch (HTTP): search failed: %v", err)
-		return nil, fmt.Errorf("basic search failed: %v", err)
+// DocumentVectors returns the stored TF-IDF vectors of documents by id. Documents missing from
+// the vector table, or whose vector can't be parsed, are left out.
+func (sa *SearchAdapter) DocumentVectors(ctx context.Context, ids []int64) (map[int64][]float64, error) {
+	vectors := make(map[int64][]float64, len(ids))
+	if len(ids) == 0 {
+		return vectors, nil
 	}
 
-	log.Printf("BasicSearch (HTTP): got response with %d hits", resp.Hits.Total)
-
-	// Convert to internal format
-	results, err := client.convertSearchResponseWithScores(resp)
+	request := IDFilter{IDs: ids}.Apply(SearchRequest{
+		Index:  "documents_vector",
+		Query:  map[string]interface{}{"match_all": map[string]interface{}{}},
+		Limit:  int32(len(ids)),
+		Source: &SourceFilter{Includes: []string{"vector_data"}},
+	})
+	resp, err := sa.client.SearchWithContext(ctx, request)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert search response: %v", err)
+		return nil, fmt.Errorf("failed to get document vectors: %v", err)
+	}
+	if resp == nil {
+		return vectors, nil
 	}
 
-	log.Printf("BasicSearch (HTTP): returning %d results", len(results))
-
-	return &models.SearchResponse{
-		Documents: results,
-		Total:     int(resp.Hits.Total),
-		Page:      page,
-		Mode:      string(models.SearchModeBasic),
-	}, nil
+	for _, hit := range resp.Hits.Hits {
+		data, ok := hit.Source["vector_data"].(string)
+		if !ok {
+			continue
+		}
+		vector, err := parseVectorFromJSONArray(data)
+		if err != nil {
+			log.Printf("DocumentVectors: skipping document %d: %v", hit.ID, err)
+			continue
+		}
+		vectors[int64(hit.ID)] = vector
+	}
+	return vectors, nil
 }
 
-// fullTextSearchHTTP performs full-text search using the HTTP client
-func (sa *SearchAdapter) fullTextSearchHTTP(client *manticoreHTTPClient, query string, page, pageSize int) (*models.SearchResponse, error) {
-	log.Printf("FullTextSearch (HTTP): query='%s', page=%d, pageSize=%d", query, page, pageSize)
+// pageWindow converts a page number and size into the offset and limit of a search request
+func pageWindow(page, pageSize int) (int32, int32) {
+	return int32((page - 1) * pageSize), int32(pageSize)
+}
 
-	offset := int32((page - 1) * pageSize)
-	limit := int32(pageSize)
+// search executes a search request through the client interface and converts the response
+func (sa *SearchAdapter) search(ctx context.Context, mode models.SearchMode, operation, query string, page int, searchReq SearchRequest) (*models.SearchResponse, error) {
+	log.Printf("%s: query='%s', limit=%d, offset=%d", operation, query, searchReq.Limit, searchReq.Offset)
 
-	// Create full-text search request
-	searchReq := client.CreateFullTextSearchRequest("documents", query, limit, offset)
+	searchReq.Source = sa.source
+	searchReq = FilterByStatus(searchReq, sa.searchStatuses())
+	searchReq = sa.recency.Apply(searchReq)
+	searchReq = sa.ids.Apply(searchReq)
+	searchReq = sa.regex.Apply(searchReq)
+	searchReq = sa.facets.Apply(WithTagFacet(sa.tags.Apply(searchReq)))
 
 	// Execute search
-	resp, err := client.SearchWithRequest(searchReq)
+	resp, err := sa.client.SearchWithContext(ctx, searchReq)
 	if err != nil {
-		log.Printf("FullTextSearch (HTTP): search failed: %v", err)
-		return nil, fmt.Errorf("full-text search failed: %v", err)
+		log.Printf("%s: search failed: %v", operation, err)
+		return nil, fmt.Errorf("%s search failed: %v", searchModeLabel(mode), err)
+	}
+	if resp == nil {
+		resp = &SearchResponse{}
 	}
 
-	log.Printf("FullTextSearch (HTTP): got response with %d hits", resp.Hits.Total)
+	log.Printf("%s: got response with %d hits", operation, resp.Hits.Total)
 
 	// Convert to internal format
-	results, err := client.convertSearchResponseWithScores(resp)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert search response: %v", err)
-	}
+	results := searchResultsFromResponse(resp)
+
+	log.Printf("%s: returning %d results", operation, len(results))
 
-	log.Printf("FullTextSearch (HTTP): returning %d results", len(results))
+	total, relation := sa.exactTotal(query, resp)
 
 	return &models.SearchResponse{
-		Documents: results,
-		Total:     int(resp.Hits.Total),
-		Page:      page,
-		Mode:      string(models.SearchModeFullText),
+		Documents:     results,
+		Total:         total,
+		TotalRelation: relation,
+		Page:          page,
+		Mode:          string(mode),
+		Pagination:    models.PaginationServer,
+		Facets:        FacetsFromResponse(resp),
 	}, nil
 }
+
+// searchModeLabel names a mode in error messages
+func searchModeLabel(mode models.SearchMode) string {
+	if mode == models.SearchModeFullText {
+		return "full-text"
+	}
+	return string(mode)
+}
+
+// exactTotal returns the total reported by a search. When Manticore only reports a lower bound
+// because it capped the matches, the exact count is looked up so pagination stays accurate.
+func (sa *SearchAdapter) exactTotal(query string, resp *SearchResponse) (int, string) {
+	total, relation := int(resp.Hits.Total), resp.Hits.TotalRelation
+	if relation == "" {
+		relation = TotalRelationEqual
+	}
+	if relation != TotalRelationAtLeast {
+		return total, relation
+	}
+
+	filters := map[string]interface{}{"status": sa.searchStatuses()}
+	if sa.recency.Since > 0 {
+		filters["updated_at"] = AtLeast(sa.recency.Since)
+	}
+	if len(sa.tags.Tags) > 0 {
+		filters["tags"] = sa.tags
+	}
+	if !sa.ids.IsZero() {
+		filters["id"] = sa.ids
+	}
+	if !sa.regex.IsZero() {
+		filters[RegexFields[sa.regex.Field]] = sa.regex
+	}
+	count, err := sa.client.Count(query, filters)
+	if err != nil {
+		log.Printf("Search: failed to count exact total, reporting at least %d: %v", total, err)
+		return total, relation
+	}
+	return int(count), TotalRelationEqual
+}
@@ -1,7 +1,13 @@
 package manticore
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
 	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
 )
 
 func TestSearchAdapter_NewSearchAdapter(t *testing.T) {
@@ -80,3 +86,113 @@ func TestSearchAdapter_TypeSwitching(t *testing.T) {
 		t.Errorf("HTTP client adapter has wrong client reference")
 	}
 }
+
+func TestSearchAdapter_WithSource(t *testing.T) {
+	var request SearchRequest
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&request)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"took":1,"timed_out":false,"hits":{"total":1,"hits":[{"_id":7,"_score":2,"_source":{"title":"Only title"}}]}}`))
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+	defer client.Close()
+	adapter := NewSearchAdapter(client).WithSource(&SourceFilter{Includes: []string{"title"}})
+
+	response, err := adapter.FullTextSearch("test query", 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if request.Source == nil || len(request.Source.Includes) != 1 || request.Source.Includes[0] != "title" {
+		t.Errorf("Expected _source includes [title], got %+v", request.Source)
+	}
+	if len(response.Documents) != 1 || response.Documents[0].Document.Title != "Only title" {
+		t.Errorf("Unexpected documents: %+v", response.Documents)
+	}
+}
+
+// stubSearchClient is a ClientInterface implementation other than the HTTP client. Methods the
+// adapter does not need are left to the embedded nil interface.
+type stubSearchClient struct {
+	ClientInterface
+	request SearchRequest
+}
+
+func (c *stubSearchClient) SearchWithContext(ctx context.Context, request SearchRequest) (*SearchResponse, error) {
+	c.request = request
+	return &SearchResponse{Hits: SearchHits{Total: 1, TotalRelation: TotalRelationEqual, Hits: []SearchHit{
+		{ID: 3, Score: 1.5, Source: map[string]interface{}{"title": "Stub"}},
+	}}}, nil
+}
+
+func TestSearchAdapter_AnyClient(t *testing.T) {
+	client := &stubSearchClient{}
+
+	response, err := NewSearchAdapter(client).BasicSearch("test", 2, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if client.request.Limit != 5 || client.request.Offset != 5 {
+		t.Errorf("Expected limit 5 and offset 5, got %d and %d", client.request.Limit, client.request.Offset)
+	}
+	if len(response.Documents) != 1 || response.Documents[0].Document.Title != "Stub" || response.Mode != "basic" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+}
+
+// capabilityClient is a stubSearchClient reporting server capabilities
+type capabilityClient struct {
+	stubSearchClient
+	caps *Capabilities
+}
+
+func (c *capabilityClient) GetCapabilities() *Capabilities {
+	return c.caps
+}
+
+func TestSearchAdapter_WithSpellTolerance(t *testing.T) {
+	tests := []struct {
+		name     string
+		tolerant bool
+		caps     *Capabilities
+		options  map[string]interface{}
+	}{
+		{"disabled", false, &Capabilities{Fuzzy: true}, nil},
+		{"unknown server", true, nil, map[string]interface{}{"expand_keywords": 1}},
+		{"no fuzzy support", true, &Capabilities{Major: 6}, map[string]interface{}{"expand_keywords": 1}},
+		{"fuzzy", true, &Capabilities{Major: 7, Fuzzy: true}, map[string]interface{}{"expand_keywords": 1, "fuzzy": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &capabilityClient{caps: tt.caps}
+			adapter := NewSearchAdapter(client).WithSpellTolerance(tt.tolerant)
+			if _, err := adapter.BasicSearch("serch", 1, 10); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(client.request.Options, tt.options) {
+				t.Errorf("Expected options %v, got %v", tt.options, client.request.Options)
+			}
+
+			// Full-text queries keep their own syntax
+			if _, err := adapter.FullTextSearch("serch", 1, 10); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if client.request.Options != nil {
+				t.Errorf("Expected no full-text options, got %v", client.request.Options)
+			}
+		})
+	}
+}
+
+func TestHTTPClientSearchUnsupportedMode(t *testing.T) {
+	client := NewHTTPClient(DefaultHTTPClientConfig("http://localhost:9308"))
+	defer client.Close()
+
+	if _, err := client.Search("test", models.SearchModeVector, 1, 10); err == nil {
+		t.Error("Expected vector search through the client to be rejected")
+	}
+}
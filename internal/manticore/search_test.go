@@ -1,8 +1,14 @@
 package manticore
 
 import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/ad/manticoresearch-go/internal/blocklist"
+	"github.com/ad/manticoresearch-go/internal/boost"
 	"github.com/ad/manticoresearch-go/internal/models"
 )
 
@@ -84,6 +90,35 @@ func TestCreateFullTextSearchRequest(t *testing.T) {
 	}
 }
 
+func TestFullTextSearchRequestSanitizesQuery(t *testing.T) {
+	request := NewFullTextSearchRequest("documents", `go ("unclosed | @secret`, 10, 0)
+	if queryStr := request.Query["query_string"]; queryStr != "go (unclosed)" {
+		t.Errorf("Expected the sanitized query_string 'go (unclosed)', got %v", queryStr)
+	}
+
+	request = NewFullTextSearchRequest("documents", `"()"`, 10, 0)
+	if _, ok := request.Query["match_all"]; !ok {
+		t.Errorf("Expected match_all for a query without words, got %v", request.Query)
+	}
+}
+
+func TestProximitySearchRequest(t *testing.T) {
+	request := NewProximitySearchRequest("documents", []string{"go", "net/http", "SENTENCE"}, 5, 10, 0)
+	if queryStr := request.Query["query_string"]; queryStr != `go NEAR/5 "net http" NEAR/5 sentence` {
+		t.Errorf("Expected a NEAR/5 query_string, got %v", queryStr)
+	}
+
+	request = NewProximitySearchRequest("documents", []string{"go", "rust"}, 0, 10, 0)
+	if queryStr := request.Query["query_string"]; queryStr != "go NEAR/1 rust" {
+		t.Errorf("Expected the distance to be raised to 1, got %v", queryStr)
+	}
+
+	request = NewProximitySearchRequest("documents", []string{"()", ""}, 3, 10, 0)
+	if _, ok := request.Query["match_all"]; !ok {
+		t.Errorf("Expected match_all without searchable terms, got %v", request.Query)
+	}
+}
+
 func TestCreateMatchAllRequest(t *testing.T) {
 	config := DefaultHTTPClientConfig("http://localhost:9308")
 	client := NewHTTPClient(config)
@@ -109,6 +144,38 @@ func TestCreateMatchAllRequest(t *testing.T) {
 	}
 }
 
+func TestGetDocument(t *testing.T) {
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var request SearchRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		id := request.Query["equals"].(map[string]interface{})["id"].(float64)
+
+		response := SearchResponse{Hits: SearchHits{Hits: []SearchHit{}}}
+		if id == 5 {
+			response.Hits = SearchHits{Total: 1, Hits: []SearchHit{{ID: 5, Source: map[string]interface{}{
+				"title": "Contact form", "status": float64(1), "tags": []interface{}{"forms"},
+			}}}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+
+	doc, err := client.GetDocument(5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if doc.ID != 5 || doc.Title != "Contact form" || doc.Status != models.DocumentStatusArchived || len(doc.Tags) != 1 {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+
+	if _, err := client.GetDocument(404); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("Expected ErrDocumentNotFound, got %v", err)
+	}
+}
+
 func TestCosineSimilarity(t *testing.T) {
 	config := DefaultHTTPClientConfig("http://localhost:9308")
 	client := NewHTTPClient(config)
@@ -154,6 +221,93 @@ func TestSearchResultProcessor(t *testing.T) {
 	}
 }
 
+func TestProcessSearchResultsBoosts(t *testing.T) {
+	client := NewHTTPClient(DefaultHTTPClientConfig("http://localhost:9308")).(*manticoreHTTPClient)
+	rules, err := boost.Parse("tag=handbook:2,tag=deprecated:0.5")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	processor := client.NewSearchResultProcessor().WithBoosts(rules)
+
+	response := &SearchResponse{Hits: SearchHits{Total: 3, Hits: []SearchHit{
+		{ID: 1, Score: 10, Source: map[string]interface{}{"title": "Old", "tags": []interface{}{"deprecated"}}},
+		{ID: 2, Score: 8, Source: map[string]interface{}{"title": "Plain"}},
+		{ID: 3, Score: 5, Source: map[string]interface{}{"title": "Guide", "tags": []interface{}{"handbook"}}},
+	}}}
+
+	// Boosts apply in every mode, after the mode's own ranking
+	for _, mode := range []models.SearchMode{models.SearchModeBasic, models.SearchModeVector, models.SearchModeAI} {
+		result, err := processor.ProcessSearchResults(response, mode)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids := []int{result.Documents[0].Document.ID, result.Documents[1].Document.ID, result.Documents[2].Document.ID}
+		if ids[0] != 3 || ids[1] != 2 || ids[2] != 1 {
+			t.Errorf("Mode %s: expected the boosted order 3, 2, 1, got %v", mode, ids)
+		}
+	}
+}
+
+func TestProcessSearchResultsBlocklist(t *testing.T) {
+	client := NewHTTPClient(DefaultHTTPClientConfig("http://localhost:9308")).(*manticoreHTTPClient)
+	blocked, err := blocklist.Parse("2", "https://wiki.example.com/drafts/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	processor := client.NewSearchResultProcessor().WithBlocklist(blocked)
+
+	response := &SearchResponse{Hits: SearchHits{Total: 10, Hits: []SearchHit{
+		{ID: 1, Score: 10, Source: map[string]interface{}{"title": "Draft", "url": "https://wiki.example.com/drafts/plan"}},
+		{ID: 2, Score: 8, Source: map[string]interface{}{"title": "Blocked"}},
+		{ID: 3, Score: 5, Source: map[string]interface{}{"title": "Guide", "url": "https://wiki.example.com/guide"}},
+	}}}
+
+	result, err := processor.ProcessSearchResults(response, models.SearchModeBasic)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Documents) != 1 || result.Documents[0].Document.ID != 3 {
+		t.Errorf("Expected only document 3, got %+v", result.Documents)
+	}
+	if result.Total != 8 {
+		t.Errorf("Expected the blocked documents not to be counted, got total %d", result.Total)
+	}
+}
+
+func TestProcessSearchResultsLengthNormalization(t *testing.T) {
+	client := NewHTTPClient(DefaultHTTPClientConfig("http://localhost:9308")).(*manticoreHTTPClient)
+	long := strings.Repeat("word ", 600)
+	response := &SearchResponse{Hits: SearchHits{Total: 3, Hits: []SearchHit{
+		{ID: 1, Score: 10, Source: map[string]interface{}{"title": "Long", "content": long}},
+		{ID: 2, Score: 8, Source: map[string]interface{}{"title": "Short", "content": "word word"}},
+		{ID: 3, Score: 6, Source: map[string]interface{}{"title": "Untitled"}},
+	}}}
+
+	// Without the option, and in hybrid mode, document length doesn't change the ranking
+	for _, mode := range []models.SearchMode{models.SearchModeBasic, models.SearchModeHybrid} {
+		result, err := client.NewSearchResultProcessor().ProcessSearchResults(response, mode)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Documents[0].Document.ID != 1 || result.Documents[1].Document.ID != 2 {
+			t.Errorf("Mode %s: expected the ranker's order, got %+v", mode, result.Documents)
+		}
+	}
+
+	// The long document is scaled down relative to the average length of the results
+	result, err := client.NewSearchResultProcessor().WithLengthNormalization(DefaultLengthSlope).ProcessSearchResults(response, models.SearchModeBasic)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ids := []int{result.Documents[0].Document.ID, result.Documents[1].Document.ID, result.Documents[2].Document.ID}
+	if ids[0] != 2 || ids[1] != 1 || ids[2] != 3 {
+		t.Errorf("Expected the normalized order 2, 1, 3, got %v", ids)
+	}
+	if result.Documents[0].Score != 1 {
+		t.Errorf("Expected normalized scores, got %f", result.Documents[0].Score)
+	}
+}
+
 func TestNormalizeScores(t *testing.T) {
 	config := DefaultHTTPClientConfig("http://localhost:9308")
 	client := NewHTTPClient(config)
@@ -230,3 +384,79 @@ func TestCalculatePagination(t *testing.T) {
 		t.Errorf("Expected page=1, totalPages=1 for zero limit, got page=%d, totalPages=%d", page, totalPages)
 	}
 }
+
+func TestBuildCountQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		filters  map[string]interface{}
+		expected string
+	}{
+		{"all documents", "", nil, "SELECT COUNT(*) AS total FROM documents"},
+		{"query", "it's", nil, `SELECT COUNT(*) AS total FROM documents WHERE MATCH('(it\'s)')`},
+		{"crafted query", `go') | (@secret`, nil, `SELECT COUNT(*) AS total FROM documents WHERE MATCH('(go\')')`},
+		{"query without words", `"()"`, nil, "SELECT COUNT(*) AS total FROM documents"},
+		{
+			"query and filters", "блок",
+			map[string]interface{}{"title": `say "hi"`, "id": int64(7)},
+			`SELECT COUNT(*) AS total FROM documents WHERE MATCH('(блок) @title "say \\"hi\\""') AND id = 7`,
+		},
+		{
+			"status filters", "",
+			map[string]interface{}{"status": []models.DocumentStatus{models.DocumentStatusActive, models.DocumentStatusArchived}},
+			"SELECT COUNT(*) AS total FROM documents WHERE status IN (0, 1)",
+		},
+		{
+			"single status", "",
+			map[string]interface{}{"status": models.DocumentStatusDeleted},
+			"SELECT COUNT(*) AS total FROM documents WHERE status = 2",
+		},
+		{
+			"updated since", "",
+			map[string]interface{}{"status": models.DocumentStatusActive, "updated_at": AtLeast(1700000000)},
+			"SELECT COUNT(*) AS total FROM documents WHERE status = 0 AND updated_at >= 1700000000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := buildCountQuery("documents", tt.query, tt.filters)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, query)
+			}
+		})
+	}
+
+	if _, err := buildCountQuery("documents", "", map[string]interface{}{"title; DROP": "x"}); err == nil {
+		t.Error("Expected error for an invalid field name")
+	}
+	if _, err := buildCountQuery("documents", "", map[string]interface{}{"id": []int{1}}); err == nil {
+		t.Error("Expected error for an unsupported filter value")
+	}
+}
+
+func TestSearchAdapterExactTotal(t *testing.T) {
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Path == "/sql" {
+			w.Write([]byte(`[{"columns":[{"total":{"type":"long long"}}],"data":[{"total":2500}],"total":1,"error":"","warning":""}]`))
+			return
+		}
+		w.Write([]byte(`{"took":1,"timed_out":false,"hits":{"total":1000,"total_relation":"gte","hits":[{"_id":1,"_score":1,"_source":{}}]}}`))
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(DefaultHTTPClientConfig(server.URL))
+	defer client.Close()
+
+	response, err := NewSearchAdapter(client).FullTextSearch("test", 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Total != 2500 || response.TotalRelation != TotalRelationEqual {
+		t.Errorf("Expected exact total 2500, got %d (%s)", response.Total, response.TotalRelation)
+	}
+}
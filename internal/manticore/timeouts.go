@@ -0,0 +1,122 @@
+package manticore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// OperationTimeouts bounds how long each kind of operation may take end to end, including retries.
+// A caller's context deadline still applies when it is shorter.
+type OperationTimeouts struct {
+	Basic    time.Duration `json:"basic"`
+	FullText time.Duration `json:"fulltext"`
+	Vector   time.Duration `json:"vector"`
+	Hybrid   time.Duration `json:"hybrid"`
+	AI       time.Duration `json:"ai"`
+	Index    time.Duration `json:"index"`
+}
+
+// DefaultOperationTimeouts returns the timeouts used when none are configured
+func DefaultOperationTimeouts() OperationTimeouts {
+	return OperationTimeouts{
+		Basic:    30 * time.Second,
+		FullText: 30 * time.Second,
+		Vector:   30 * time.Second,
+		Hybrid:   30 * time.Second,
+		AI:       60 * time.Second, // Longer timeout for AI operations
+		Index:    30 * time.Second,
+	}
+}
+
+// withDefaults fills unset timeouts from DefaultOperationTimeouts
+func (t OperationTimeouts) withDefaults() OperationTimeouts {
+	defaults := DefaultOperationTimeouts()
+	for _, pair := range []struct{ value, fallback *time.Duration }{
+		{&t.Basic, &defaults.Basic},
+		{&t.FullText, &defaults.FullText},
+		{&t.Vector, &defaults.Vector},
+		{&t.Hybrid, &defaults.Hybrid},
+		{&t.AI, &defaults.AI},
+		{&t.Index, &defaults.Index},
+	} {
+		if *pair.value <= 0 {
+			*pair.value = *pair.fallback
+		}
+	}
+	return t
+}
+
+// ForMode returns the timeout for a search mode
+func (t OperationTimeouts) ForMode(mode models.SearchMode) time.Duration {
+	switch mode {
+	case models.SearchModeBasic:
+		return t.Basic
+	case models.SearchModeFullText:
+		return t.FullText
+	case models.SearchModeVector:
+		return t.Vector
+	case models.SearchModeHybrid:
+		return t.Hybrid
+	case models.SearchModeAI, models.SearchModeAIHybrid:
+		return t.AI
+	default:
+		return t.Hybrid
+	}
+}
+
+// loadOperationTimeoutsFromEnvironment overrides timeouts from MANTICORE_TIMEOUT_<OPERATION> variables
+func loadOperationTimeoutsFromEnvironment(timeouts *OperationTimeouts) error {
+	for _, setting := range []struct {
+		name  string
+		value *time.Duration
+	}{
+		{"MANTICORE_TIMEOUT_BASIC", &timeouts.Basic},
+		{"MANTICORE_TIMEOUT_FULLTEXT", &timeouts.FullText},
+		{"MANTICORE_TIMEOUT_VECTOR", &timeouts.Vector},
+		{"MANTICORE_TIMEOUT_HYBRID", &timeouts.Hybrid},
+		{"MANTICORE_TIMEOUT_AI", &timeouts.AI},
+		{"MANTICORE_TIMEOUT_INDEX", &timeouts.Index},
+	} {
+		valueStr := os.Getenv(setting.name)
+		if valueStr == "" {
+			continue
+		}
+		value, err := time.ParseDuration(valueStr)
+		if err != nil || value <= 0 {
+			return fmt.Errorf("invalid %s: %q", setting.name, valueStr)
+		}
+		*setting.value = value
+	}
+	return nil
+}
+
+// withOperationTimeout bounds ctx by timeout. The caller's deadline wins when it is earlier.
+func withOperationTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// withDefaultDeadline bounds ctx by timeout only when the caller did not set a deadline
+func withDefaultDeadline(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return withOperationTimeout(ctx, timeout)
+}
+
+// IsTimeoutError reports whether an operation failed because its deadline passed
+func IsTimeoutError(err error) bool {
+	return CategorizeError(err) == ErrorCategoryTimeout
+}
@@ -0,0 +1,70 @@
+package manticore
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestOperationTimeoutsFromEnvironment(t *testing.T) {
+	os.Setenv("MANTICORE_TIMEOUT_BASIC", "2s")
+	os.Setenv("MANTICORE_TIMEOUT_AI", "90s")
+	defer os.Unsetenv("MANTICORE_TIMEOUT_BASIC")
+	defer os.Unsetenv("MANTICORE_TIMEOUT_AI")
+
+	timeouts := DefaultOperationTimeouts()
+	if err := loadOperationTimeoutsFromEnvironment(&timeouts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := timeouts.ForMode(models.SearchModeBasic); got != 2*time.Second {
+		t.Errorf("Expected basic timeout 2s, got %v", got)
+	}
+	if got := timeouts.ForMode(models.SearchModeAI); got != 90*time.Second {
+		t.Errorf("Expected AI timeout 90s, got %v", got)
+	}
+	if got := timeouts.ForMode(models.SearchModeVector); got != DefaultOperationTimeouts().Vector {
+		t.Errorf("Expected default vector timeout, got %v", got)
+	}
+
+	os.Setenv("MANTICORE_TIMEOUT_BASIC", "soon")
+	if err := loadOperationTimeoutsFromEnvironment(&timeouts); err == nil {
+		t.Error("Expected error for invalid duration")
+	}
+}
+
+func TestSearchWithContextHonorsDeadline(t *testing.T) {
+	release := make(chan struct{})
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer server.Close()
+	defer close(release)
+
+	config := DefaultHTTPClientConfig(server.URL)
+	config.RetryConfig.MaxAttempts = 1
+	client := NewHTTPClient(config)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.SearchWithContext(ctx, SearchRequest{Index: "documents", Query: map[string]interface{}{"match_all": map[string]interface{}{}}})
+	if err == nil {
+		t.Fatal("Expected search to fail when the context deadline passes")
+	}
+	if !IsTimeoutError(err) {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Search did not stop at the context deadline, took %v", elapsed)
+	}
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// aiConfigOverrides are the AI search settings of a collection in the collections file; unset
+// settings keep the global configuration
+type aiConfigOverrides struct {
+	Model      *string `json:"model"`
+	Enabled    *bool   `json:"enabled"`
+	Timeout    *string `json:"timeout"`
+	MaxTokens  *int    `json:"max_tokens"`
+	KNNK       *int    `json:"knn_k"`
+	KNNEf      *int    `json:"knn_ef"`
+	Similarity *string `json:"similarity"`
+}
+
+// apply returns a copy of base with the overrides set, validated like the environment variables
+func (o aiConfigOverrides) apply(base *AISearchConfig) (*AISearchConfig, error) {
+	config := *base
+	if o.Model != nil {
+		if err := validateAIModel(*o.Model); err != nil {
+			return nil, fmt.Errorf("invalid model: %w", err)
+		}
+		config.Model = *o.Model
+	}
+	if o.Enabled != nil {
+		config.Enabled = *o.Enabled
+	}
+	if o.Timeout != nil {
+		timeout, err := time.ParseDuration(*o.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout: %q (use a positive duration such as 30s)", *o.Timeout)
+		}
+		config.Timeout = timeout
+	}
+	if o.MaxTokens != nil {
+		if *o.MaxTokens < 0 {
+			return nil, fmt.Errorf("invalid max_tokens: %d (use a number of tokens, 0 to disable)", *o.MaxTokens)
+		}
+		config.MaxTokens = *o.MaxTokens
+	}
+	if o.KNNK != nil {
+		if *o.KNNK < 0 || *o.KNNK > MaxKNNK {
+			return nil, fmt.Errorf("invalid knn_k: %d (use a number of neighbours up to %d, 0 for the results up to the page)", *o.KNNK, MaxKNNK)
+		}
+		config.KNNK = *o.KNNK
+	}
+	if o.KNNEf != nil {
+		if *o.KNNEf < 0 || *o.KNNEf > MaxKNNEf {
+			return nil, fmt.Errorf("invalid knn_ef: %d (use a candidate list size up to %d, 0 for the server default)", *o.KNNEf, MaxKNNEf)
+		}
+		config.KNNEf = *o.KNNEf
+	}
+	if o.Similarity != nil {
+		if err := ValidateSimilarity(*o.Similarity); err != nil {
+			return nil, err
+		}
+		config.Similarity = *o.Similarity
+	}
+	return &config, nil
+}
+
+// ParseCollectionAIConfigs parses an AI collections file: a JSON object whose keys are collections
+// (tenants) and whose values override settings of base for that collection, such as
+// {"legal": {"model": "sentence-transformers/all-mpnet-base-v2", "max_tokens": 384}}. The settings
+// are model, enabled, timeout, max_tokens, knn_k, knn_ef and similarity, like the MANTICORE_AI_
+// environment variables.
+func ParseCollectionAIConfigs(data []byte, base *AISearchConfig) (map[string]*AISearchConfig, error) {
+	var collections map[string]aiConfigOverrides
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&collections); err != nil {
+		return nil, fmt.Errorf("invalid AI collections: %w", err)
+	}
+
+	configs := make(map[string]*AISearchConfig, len(collections))
+	for collection, overrides := range collections {
+		config, err := overrides.apply(base)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AI configuration of collection %s: %w", collection, err)
+		}
+		configs[collection] = config
+	}
+	return configs, nil
+}
+
+// LoadCollectionAIConfigsFromEnvironment reads the AI collections file AI_COLLECTIONS_FILE, see
+// ParseCollectionAIConfigs; unset configures every collection with base
+func LoadCollectionAIConfigsFromEnvironment(base *AISearchConfig) (map[string]*AISearchConfig, error) {
+	path := os.Getenv("AI_COLLECTIONS_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read AI_COLLECTIONS_FILE: %w", err)
+	}
+	return ParseCollectionAIConfigs(data, base)
+}
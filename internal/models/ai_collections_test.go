@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCollectionAIConfigs(t *testing.T) {
+	base := DefaultAISearchConfig()
+	configs, err := ParseCollectionAIConfigs([]byte(`{
+		"legal": {"model": "sentence-transformers/all-mpnet-base-v2", "max_tokens": 384, "timeout": "45s"},
+		"news": {"enabled": false, "knn_k": 50, "similarity": "dot"}
+	}`), base)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	legal := configs["legal"]
+	if legal.Model != "sentence-transformers/all-mpnet-base-v2" || legal.MaxTokens != 384 || legal.Timeout != 45*time.Second || !legal.Enabled {
+		t.Errorf("Unexpected legal configuration: %+v", legal)
+	}
+	news := configs["news"]
+	if news.Model != base.Model || news.Enabled || news.KNNK != 50 || news.Similarity != SimilarityDot {
+		t.Errorf("Unexpected news configuration: %+v", news)
+	}
+	if base.Model != "sentence-transformers/all-MiniLM-L6-v2" || !base.Enabled {
+		t.Errorf("The base configuration was modified: %+v", base)
+	}
+}
+
+func TestParseCollectionAIConfigs_KNNBounds(t *testing.T) {
+	data := fmt.Sprintf(`{"legal": {"knn_k": %d, "knn_ef": %d}}`, MaxKNNK, MaxKNNEf)
+	configs, err := ParseCollectionAIConfigs([]byte(data), DefaultAISearchConfig())
+	if err != nil || configs["legal"].KNNK != MaxKNNK || configs["legal"].KNNEf != MaxKNNEf {
+		t.Errorf("Expected the largest k and ef to be accepted, got %+v, %v", configs["legal"], err)
+	}
+}
+
+func TestParseCollectionAIConfigs_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not an object":   `[]`,
+		"unknown setting": `{"legal": {"chunk": 3}}`,
+		"bad model":       `{"legal": {"model": "../model"}}`,
+		"bad timeout":     `{"legal": {"timeout": "-1s"}}`,
+		"bad max_tokens":  `{"legal": {"max_tokens": -1}}`,
+		"bad similarity":  `{"legal": {"similarity": "hamming"}}`,
+		"negative knn_k":  `{"legal": {"knn_k": -1}}`,
+		"large knn_k":     `{"legal": {"knn_k": 1001}}`,
+		"large knn_ef":    `{"legal": {"knn_ef": 10001}}`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseCollectionAIConfigs([]byte(data), DefaultAISearchConfig()); err == nil || !strings.Contains(err.Error(), "invalid") {
+				t.Errorf("Expected an invalid configuration error, got %v", err)
+			}
+		})
+	}
+}
@@ -44,18 +44,83 @@ func LoadAISearchConfigFromEnvironment() (*AISearchConfig, error) {
 		config.Timeout = timeout
 	}
 
+	// Parse the input limit of the model
+	if maxTokensStr := os.Getenv("MANTICORE_AI_MAX_TOKENS"); maxTokensStr != "" {
+		maxTokens, err := strconv.Atoi(maxTokensStr)
+		if err != nil || maxTokens < 0 {
+			return nil, fmt.Errorf("invalid MANTICORE_AI_MAX_TOKENS: %q (use a number of tokens, 0 to disable)", maxTokensStr)
+		}
+		config.MaxTokens = maxTokens
+	}
+
+	// Parse the KNN tuning of AI searches
+	if kStr := os.Getenv("MANTICORE_AI_KNN_K"); kStr != "" {
+		k, err := strconv.Atoi(kStr)
+		if err != nil || k < 0 || k > MaxKNNK {
+			return nil, fmt.Errorf("invalid MANTICORE_AI_KNN_K: %q (use a number of neighbours up to %d, 0 for the results up to the page)", kStr, MaxKNNK)
+		}
+		config.KNNK = k
+	}
+	if efStr := os.Getenv("MANTICORE_AI_KNN_EF"); efStr != "" {
+		ef, err := strconv.Atoi(efStr)
+		if err != nil || ef < 0 || ef > MaxKNNEf {
+			return nil, fmt.Errorf("invalid MANTICORE_AI_KNN_EF: %q (use a candidate list size up to %d, 0 for the server default)", efStr, MaxKNNEf)
+		}
+		config.KNNEf = ef
+	}
+	if similarity := os.Getenv("MANTICORE_AI_SIMILARITY"); similarity != "" {
+		if err := ValidateSimilarity(similarity); err != nil {
+			return nil, fmt.Errorf("invalid MANTICORE_AI_SIMILARITY: %w", err)
+		}
+		config.Similarity = similarity
+	}
+
 	return config, nil
 }
 
 // DefaultAISearchConfig returns default AI search configuration
 func DefaultAISearchConfig() *AISearchConfig {
 	return &AISearchConfig{
-		Model:   "sentence-transformers/all-MiniLM-L6-v2",
-		Enabled: true,
-		Timeout: 30 * time.Second,
+		Model:      "sentence-transformers/all-MiniLM-L6-v2",
+		Enabled:    true,
+		Timeout:    30 * time.Second,
+		MaxTokens:  DefaultAIMaxTokens,
+		Similarity: SimilarityCosine,
+	}
+}
+
+// Distance metrics of AI embeddings
+const (
+	SimilarityCosine = "cosine"
+	SimilarityL2     = "l2"
+	SimilarityDot    = "dot"
+)
+
+// ValidateSimilarity validates an embedding distance metric
+func ValidateSimilarity(similarity string) error {
+	switch similarity {
+	case SimilarityCosine, SimilarityL2, SimilarityDot:
+		return nil
+	default:
+		return fmt.Errorf("unsupported similarity: %q (use cosine, l2 or dot)", similarity)
 	}
 }
 
+// DefaultAIMaxTokens is the default MANTICORE_AI_MAX_TOKENS, the input limit of all-MiniLM-L6-v2
+const DefaultAIMaxTokens = 256
+
+// MaxKNNK is the largest number of nearest neighbours an AI search may request, which is
+// Manticore's default max_matches
+const MaxKNNK = 1000
+
+// MaxKNNEf is the largest HNSW candidate list an AI search may request
+const MaxKNNEf = 10000
+
+// ValidateAIModel validates an AI model name, such as one requested for re-embedding
+func ValidateAIModel(model string) error {
+	return validateAIModel(model)
+}
+
 // validateAIModel validates the AI model name
 func validateAIModel(model string) error {
 	if model == "" {
@@ -96,7 +161,7 @@ func containsPattern(s, pattern string) bool {
 // ValidateSearchMode validates if the provided search mode is supported
 func ValidateSearchMode(mode string) error {
 	switch SearchMode(mode) {
-	case SearchModeBasic, SearchModeFullText, SearchModeVector, SearchModeHybrid, SearchModeAI:
+	case SearchModeBasic, SearchModeFullText, SearchModeVector, SearchModeHybrid, SearchModeAI, SearchModeAIHybrid, SearchModeAuto:
 		return nil
 	default:
 		return fmt.Errorf("unsupported search mode: %s", mode)
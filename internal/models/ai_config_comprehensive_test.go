@@ -185,7 +185,7 @@ func testAIConfigurationValidation(t *testing.T) {
 	})
 
 	t.Run("Search Mode Validation", func(t *testing.T) {
-		validModes := []string{"basic", "fulltext", "vector", "hybrid", "ai"}
+		validModes := []string{"basic", "fulltext", "vector", "hybrid", "ai", "ai-hybrid", "auto"}
 		for _, mode := range validModes {
 			if err := ValidateSearchMode(mode); err != nil {
 				t.Errorf("Expected mode '%s' to be valid, got error: %v", mode, err)
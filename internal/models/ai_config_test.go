@@ -2,6 +2,7 @@ package models
 
 import (
 	"os"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -25,6 +26,85 @@ func TestLoadAISearchConfigFromEnvironment_Defaults(t *testing.T) {
 	if config.Timeout != expected.Timeout {
 		t.Errorf("Expected timeout %v, got %v", expected.Timeout, config.Timeout)
 	}
+	if config.MaxTokens != DefaultAIMaxTokens {
+		t.Errorf("Expected max tokens %d, got %d", DefaultAIMaxTokens, config.MaxTokens)
+	}
+}
+
+func TestLoadAISearchConfigFromEnvironment_MaxTokens(t *testing.T) {
+	clearAIEnvVars()
+	defer clearAIEnvVars()
+
+	os.Setenv("MANTICORE_AI_MAX_TOKENS", "512")
+	config, err := LoadAISearchConfigFromEnvironment()
+	if err != nil || config.MaxTokens != 512 {
+		t.Errorf("Expected 512 max tokens, got %+v, %v", config, err)
+	}
+
+	os.Setenv("MANTICORE_AI_MAX_TOKENS", "0")
+	if config, err := LoadAISearchConfigFromEnvironment(); err != nil || config.MaxTokens != 0 {
+		t.Errorf("Expected the limit to be disabled, got %+v, %v", config, err)
+	}
+
+	for _, value := range []string{"-1", "many"} {
+		os.Setenv("MANTICORE_AI_MAX_TOKENS", value)
+		if _, err := LoadAISearchConfigFromEnvironment(); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestLoadAISearchConfigFromEnvironment_KNN(t *testing.T) {
+	clearAIEnvVars()
+	defer clearAIEnvVars()
+
+	config, err := LoadAISearchConfigFromEnvironment()
+	if err != nil || config.KNNK != 0 || config.KNNEf != 0 || config.Similarity != SimilarityCosine {
+		t.Errorf("Expected the default KNN tuning, got %+v, %v", config, err)
+	}
+
+	os.Setenv("MANTICORE_AI_KNN_K", "100")
+	os.Setenv("MANTICORE_AI_KNN_EF", "200")
+	os.Setenv("MANTICORE_AI_SIMILARITY", "dot")
+	config, err = LoadAISearchConfigFromEnvironment()
+	if err != nil || config.KNNK != 100 || config.KNNEf != 200 || config.Similarity != SimilarityDot {
+		t.Errorf("Expected k 100, ef 200 and dot similarity, got %+v, %v", config, err)
+	}
+
+	for key, value := range map[string]string{"MANTICORE_AI_KNN_K": "-1", "MANTICORE_AI_KNN_EF": "many", "MANTICORE_AI_SIMILARITY": "euclid"} {
+		clearAIEnvVars()
+		os.Setenv(key, value)
+		if _, err := LoadAISearchConfigFromEnvironment(); err == nil {
+			t.Errorf("Expected an error for %s=%q", key, value)
+		}
+	}
+}
+
+func TestLoadAISearchConfigFromEnvironment_KNNBounds(t *testing.T) {
+	clearAIEnvVars()
+	defer clearAIEnvVars()
+
+	os.Setenv("MANTICORE_AI_KNN_K", strconv.Itoa(MaxKNNK))
+	os.Setenv("MANTICORE_AI_KNN_EF", strconv.Itoa(MaxKNNEf))
+	config, err := LoadAISearchConfigFromEnvironment()
+	if err != nil || config.KNNK != MaxKNNK || config.KNNEf != MaxKNNEf {
+		t.Errorf("Expected the largest k and ef to be accepted, got %+v, %v", config, err)
+	}
+
+	tests := []struct {
+		key   string
+		value string
+	}{
+		{"MANTICORE_AI_KNN_K", strconv.Itoa(MaxKNNK + 1)},
+		{"MANTICORE_AI_KNN_EF", strconv.Itoa(MaxKNNEf + 1)},
+	}
+	for _, tt := range tests {
+		clearAIEnvVars()
+		os.Setenv(tt.key, tt.value)
+		if _, err := LoadAISearchConfigFromEnvironment(); err == nil {
+			t.Errorf("Expected an error for %s=%q", tt.key, tt.value)
+		}
+	}
 }
 
 func TestLoadAISearchConfigFromEnvironment_CustomValues(t *testing.T) {
@@ -186,4 +266,8 @@ func clearAIEnvVars() {
 	os.Unsetenv("MANTICORE_AI_MODEL")
 	os.Unsetenv("MANTICORE_AI_ENABLED")
 	os.Unsetenv("MANTICORE_AI_TIMEOUT")
+	os.Unsetenv("MANTICORE_AI_MAX_TOKENS")
+	os.Unsetenv("MANTICORE_AI_KNN_K")
+	os.Unsetenv("MANTICORE_AI_KNN_EF")
+	os.Unsetenv("MANTICORE_AI_SIMILARITY")
 }
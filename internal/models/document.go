@@ -1,36 +1,169 @@
 package models
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
 
 // AISearchConfig holds configuration for AI search functionality
 type AISearchConfig struct {
 	Model   string        `json:"model"`
 	Enabled bool          `json:"enabled"`
 	Timeout time.Duration `json:"timeout"`
+	// MaxTokens is the estimated input limit of the model: longer queries are truncated before
+	// embedding and longer documents are reported, as the model ignores the rest. 0 disables it.
+	MaxTokens int `json:"max_tokens"`
+	// KNNK is the least number of nearest neighbours AI searches find, raised to cover the
+	// requested page. 0 finds just the results up to the page.
+	KNNK int `json:"knn_k"`
+	// KNNEf is the size of the HNSW candidate list of AI searches, 0 for the server default
+	KNNEf int `json:"knn_ef"`
+	// Similarity is the distance metric of the embeddings, applied when the table is created:
+	// cosine, l2 or dot. Empty means cosine.
+	Similarity string `json:"similarity"`
+}
+
+// DocumentStatus controls whether a document is returned by searches
+type DocumentStatus string
+
+const (
+	DocumentStatusActive   DocumentStatus = "active"   // Returned by searches
+	DocumentStatusArchived DocumentStatus = "archived" // Hidden from searches by default, kept for history
+	DocumentStatusDeleted  DocumentStatus = "deleted"  // Soft deleted, hidden from searches by default
+)
+
+// DocumentStatuses lists every document status
+var DocumentStatuses = []DocumentStatus{DocumentStatusActive, DocumentStatusArchived, DocumentStatusDeleted}
+
+// ParseDocumentStatus validates a status name
+func ParseDocumentStatus(value string) (DocumentStatus, error) {
+	for _, status := range DocumentStatuses {
+		if string(status) == value {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("invalid document status: %s. Valid statuses are: active, archived, deleted", value)
+}
+
+// NormalizeTags lowercases and trims tags, dropping empty and repeated ones while keeping their order
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
 }
 
 // Document represents a parsed markdown document
 type Document struct {
-	ID      int    `json:"id"`
-	Title   string `json:"title"`
-	URL     string `json:"url"`
-	Content string `json:"content"`
+	ID      int            `json:"id"`
+	Title   string         `json:"title,omitempty"`
+	URL     string         `json:"url,omitempty"`
+	Content string         `json:"content,omitempty"`
+	Snippet string         `json:"snippet,omitempty"` // Leading excerpt of Content, only set when requested
+	Status  DocumentStatus `json:"status,omitempty"`  // Empty for documents that were never stored in Manticore
+	// IndexedAt is when the document was last written to Manticore, in Unix seconds
+	IndexedAt int64 `json:"indexed_at,omitempty"`
+	// UpdatedAt is when the document source last changed, in Unix seconds
+	UpdatedAt int64 `json:"updated_at,omitempty"`
+	// Tags are normalized with NormalizeTags
+	Tags []string `json:"tags,omitempty"`
+}
+
+// ContentHash returns a hex SHA-256 digest of the indexed content of the document: its title, URL,
+// content and tags. Status and timestamps are left out, so the hash only changes with the content.
+func (d *Document) ContentHash() string {
+	h := sha256.New()
+	// Length-prefix each field so moving text between fields changes the hash
+	for _, field := range append([]string{d.Title, d.URL, d.Content}, d.Tags...) {
+		fmt.Fprintf(h, "%d:%s", len(field), field)
+	}
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 // SearchResult represents a search result with document and score
 type SearchResult struct {
 	Document *Document `json:"document"`
 	Score    float64   `json:"score"`
+	Pinned   bool      `json:"pinned,omitempty"` // Placed first by a curation rather than by its score
 }
 
 // SearchResponse represents the response structure for search API
 type SearchResponse struct {
-	Documents []SearchResult `json:"documents"`
-	Total     int            `json:"total"`
-	Page      int            `json:"page"`
-	Mode      string         `json:"mode"`
+	Documents     []SearchResult `json:"documents"`
+	Total         int            `json:"total"`
+	TotalRelation string         `json:"total_relation,omitempty"` // "eq" for an exact Total, "gte" for a lower bound; empty when the mode computes totals itself
+	Page          int            `json:"page"`
+	Mode          string         `json:"mode"`
+	Pagination    string         `json:"pagination,omitempty"` // PaginationServer or PaginationClient
+	Stale         bool           `json:"stale,omitempty"`      // Served from cache while the backend is unavailable
+	// Facets counts the values of a facet field, such as FacetTags, across the matching documents
+	Facets map[string][]FacetValue `json:"facets,omitempty"`
+	// Partial marks full-text results returned ahead of a progressive search's final ranking,
+	// which is collected with the Continuation token
+	Partial      bool   `json:"partial,omitempty"`
+	Continuation string `json:"continuation,omitempty"`
+	// Relaxation names the strategy that produced the results after the query as written matched
+	// nothing, and RelaxedQuery is the full-text query it ran (empty for vector results)
+	Relaxation   string `json:"relaxation,omitempty"`
+	RelaxedQuery string `json:"relaxed_query,omitempty"`
+	// Curation names the curation that pinned or hid documents of the results
+	Curation string `json:"curation,omitempty"`
+	// Collapsed is the number of near-duplicate results removed from the page, see dedup
+	Collapsed int `json:"collapsed,omitempty"`
+	// QueryClass is how mode=auto classified the query, "keyword" or "natural_language"; Mode is
+	// the mode it was routed to
+	QueryClass string `json:"query_class,omitempty"`
+	// IndexGeneration is the index generation the results were computed at, see
+	// AppState.IndexChanged; results of equal generations are interchangeable
+	IndexGeneration uint64 `json:"index_generation,omitempty"`
+	// Debug explains how the query was processed, returned when requested with debug=true
+	Debug *SearchDebug `json:"debug,omitempty"`
 }
 
+// SearchDebug explains how a search processed its query
+type SearchDebug struct {
+	Language           string  `json:"language,omitempty"`  // Language whose stopwords were removed, empty when undetermined
+	LanguageConfidence float64 `json:"language_confidence"` // Share of the query's words pointing to Language, 1 when requested
+	LanguageSource     string  `json:"language_source"`     // "detected" or "requested"
+	FullTextQuery      string  `json:"full_text_query"`     // Query sent to Manticore after stopword removal
+	Routing            string  `json:"routing,omitempty"`   // Rule that classified the query for mode=auto
+	// Features lists the results of the page whose scores were weighted by scoring features
+	Features []FeatureMatch `json:"features,omitempty"`
+}
+
+// FeatureMatch is the scoring features a result has for the query and the product of their weights
+type FeatureMatch struct {
+	ID       int      `json:"id"`
+	Features []string `json:"features"`
+	Factor   float64  `json:"factor"`
+}
+
+// FacetTags is the Facets key of the tag facet
+const FacetTags = "tags"
+
+// FacetValue is the number of matching documents with a facet value. For histogram facets the
+// value is the lower bound of a bucket.
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Values of SearchResponse.Pagination
+const (
+	PaginationServer = "server" // Manticore applied limit and offset
+	PaginationClient = "client" // Results were ranked by the service and the page was cut from them
+)
+
 // AISearchResponse extends SearchResponse with AI-specific metadata
 type AISearchResponse struct {
 	SearchResponse
@@ -49,4 +182,12 @@ const (
 	SearchModeVector   SearchMode = "vector"
 	SearchModeHybrid   SearchMode = "hybrid"
 	SearchModeAI       SearchMode = "ai"
+	SearchModeAIHybrid SearchMode = "ai-hybrid"
+	// SearchModeAuto classifies each query and searches in the mode suited to it
+	SearchModeAuto SearchMode = "auto"
 )
+
+// UsesAI reports whether the mode searches with Manticore's auto embeddings
+func (m SearchMode) UsesAI() bool {
+	return m == SearchModeAI || m == SearchModeAIHybrid
+}
@@ -0,0 +1,28 @@
+package models
+
+import "testing"
+
+func TestContentHash(t *testing.T) {
+	doc := Document{ID: 1, Title: "Guide", URL: "guide.md", Content: "How to search", Tags: []string{"go"}}
+	hash := doc.ContentHash()
+	if len(hash) != 64 {
+		t.Fatalf("Expected a hex SHA-256 digest, got %q", hash)
+	}
+
+	same := doc
+	same.Status, same.IndexedAt, same.UpdatedAt = DocumentStatusArchived, 100, 200
+	if same.ContentHash() != hash {
+		t.Error("Expected status and timestamps to keep the hash")
+	}
+
+	for name, changed := range map[string]Document{
+		"title":   {Title: "Guides", URL: doc.URL, Content: doc.Content, Tags: doc.Tags},
+		"content": {Title: doc.Title, URL: doc.URL, Content: "How to index", Tags: doc.Tags},
+		"tags":    {Title: doc.Title, URL: doc.URL, Content: doc.Content},
+		"moved":   {Title: doc.Title + "guide.md", Content: doc.Content, Tags: doc.Tags},
+	} {
+		if changed.ContentHash() == hash {
+			t.Errorf("Expected a %s change to change the hash", name)
+		}
+	}
+}
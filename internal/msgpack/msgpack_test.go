@@ -0,0 +1,97 @@
+package msgpack
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshal_Scalars(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []byte
+	}{
+		{"nil", nil, []byte{0xc0}},
+		{"true", true, []byte{0xc3}},
+		{"false", false, []byte{0xc2}},
+		{"positive fixint", 7, []byte{0x07}},
+		{"negative fixint", -3, []byte{0xfd}},
+		{"uint8", 200, []byte{0xcc, 0xc8}},
+		{"uint16", 1000, []byte{0xcd, 0x03, 0xe8}},
+		{"int8", -100, []byte{0xd0, 0x9c}},
+		{"int32", -100000, []byte{0xd2, 0xff, 0xfe, 0x79, 0x60}},
+		{"float64", 0.5, []byte{0xcb, 0x3f, 0xe0, 0, 0, 0, 0, 0, 0}},
+		{"fixstr", "hi", []byte{0xa2, 'h', 'i'}},
+		{"binary", []byte{1, 2}, []byte{0xc4, 0x02, 1, 2}},
+		{"array", []int{1, 2}, []byte{0x92, 0x01, 0x02}},
+		{"map", map[string]int{"b": 2, "a": 1}, []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x02}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("Marshal(%v) = %x, want %x", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshal_LongString(t *testing.T) {
+	got, err := Marshal(strings.Repeat("x", 40))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if got[0] != 0xd9 || got[1] != 40 || len(got) != 42 {
+		t.Errorf("Expected a str8 header, got %x", got[:2])
+	}
+}
+
+type embedded struct {
+	Kind string `json:"kind"`
+}
+
+type document struct {
+	embedded
+	ID      int        `json:"id"`
+	Title   string     `json:"title,omitempty"`
+	Secret  string     `json:"-"`
+	Created *time.Time `json:"created,omitempty"`
+	hidden  int
+}
+
+func TestMarshal_StructFollowsJSONTags(t *testing.T) {
+	got, err := Marshal(document{embedded: embedded{Kind: "a"}, ID: 1, Secret: "s", hidden: 2})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := []byte{0x82, 0xa4, 'k', 'i', 'n', 'd', 0xa1, 'a', 0xa2, 'i', 'd', 0x01}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Marshal = %x, want %x", got, want)
+	}
+}
+
+func TestMarshal_TextMarshaler(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	got, err := Marshal(document{ID: 1, Created: &created})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !bytes.Contains(got, []byte("2024-01-02T03:04:05Z")) {
+		t.Errorf("Expected the time as RFC 3339 text, got %x", got)
+	}
+}
+
+func TestMarshal_Unsupported(t *testing.T) {
+	if _, err := Marshal(make(chan int)); err == nil {
+		t.Error("Expected an error for a channel")
+	}
+	if _, err := Marshal(map[bool]int{true: 1}); err == nil {
+		t.Error("Expected an error for a bool map key")
+	}
+}
@@ -0,0 +1,193 @@
+package quality
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/search"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+// TablePrefix prefixes the tables fixtures are indexed into, so an evaluation against a shared
+// Manticore leaves the served tables alone
+const TablePrefix = "quality_"
+
+// Report is the outcome of evaluating a fixture
+type Report struct {
+	Fixture string       `json:"fixture"`
+	Modes   []ModeReport `json:"modes"`
+}
+
+// ModeReport is the mean precision@k a search mode reached on a fixture's queries
+type ModeReport struct {
+	Mode      models.SearchMode `json:"mode"`
+	Precision float64           `json:"precision"`
+	Minimum   float64           `json:"minimum"`
+	Skipped   string            `json:"skipped,omitempty"` // why the mode was not evaluated
+	Queries   []QueryReport     `json:"queries,omitempty"`
+}
+
+// QueryReport is the precision@k of one query
+type QueryReport struct {
+	Query     string  `json:"query"`
+	K         int     `json:"k"`
+	Retrieved []int   `json:"retrieved"` // ids of the top k results
+	Precision float64 `json:"precision"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Passed reports whether the mode reached its minimum or was skipped
+func (m ModeReport) Passed() bool {
+	return m.Skipped != "" || m.Precision >= m.Minimum
+}
+
+// Passed reports whether every mode reached its minimum
+func (r *Report) Passed() bool {
+	for _, mode := range r.Modes {
+		if !mode.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// Run evaluates the fixtures at path, see LoadFixtures and Evaluate, and drops the tables of
+// client afterwards
+func Run(ctx context.Context, client manticore.ClientInterface, aiConfig *models.AISearchConfig, path string) ([]*Report, error) {
+	fixtures, err := LoadFixtures(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := client.ResetDatabase(); err != nil {
+			log.Printf("Failed to drop the quality tables: %v", err)
+		}
+	}()
+
+	reports := make([]*Report, 0, len(fixtures))
+	for _, fixture := range fixtures {
+		report, err := Evaluate(ctx, client, aiConfig, fixture)
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// Write prints the precision of each mode of report and, for the modes below their minimum,
+// the queries that missed relevant documents
+func (r *Report) Write(w io.Writer) {
+	fmt.Fprintf(w, "%s:\n", r.Fixture)
+	for _, mode := range r.Modes {
+		switch {
+		case mode.Skipped != "":
+			fmt.Fprintf(w, "  %-10s skipped: %s\n", mode.Mode, mode.Skipped)
+			continue
+		case mode.Passed():
+			fmt.Fprintf(w, "  %-10s precision@k %.3f (minimum %.3f) ok\n", mode.Mode, mode.Precision, mode.Minimum)
+			continue
+		}
+		fmt.Fprintf(w, "  %-10s precision@k %.3f (minimum %.3f) FAIL\n", mode.Mode, mode.Precision, mode.Minimum)
+		for _, query := range mode.Queries {
+			if query.Error != "" {
+				fmt.Fprintf(w, "    %q: %s\n", query.Query, query.Error)
+			} else if query.Precision < 1 {
+				fmt.Fprintf(w, "    %q: precision@%d %.3f, retrieved %v\n", query.Query, query.K, query.Precision, query.Retrieved)
+			}
+		}
+	}
+}
+
+// Evaluate indexes the fixture's corpus with client, replacing the documents of its tables, and
+// runs every judged query in each mode the fixture sets a minimum for. client should use
+// TablePrefix. Modes that use AI are skipped unless aiConfig enables AI search and Manticore
+// supports Auto Embeddings. A failed query scores 0.
+func Evaluate(ctx context.Context, client manticore.ClientInterface, aiConfig *models.AISearchConfig, fixture *Fixture) (*Report, error) {
+	documents := fixture.documents()
+	vec := vectorizer.NewTFIDFVectorizer()
+	vectors := vec.FitTransform(documents)
+	if err := client.CreateSchema(aiConfig); err != nil {
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+	if err := client.IndexDocuments(documents, vectors); err != nil {
+		return nil, fmt.Errorf("failed to index the corpus of %s: %w", fixture.Name, err)
+	}
+
+	engine := search.NewSearchEngine(client, vec, aiConfig)
+	report := &Report{Fixture: fixture.Name}
+	for _, mode := range fixture.modes() {
+		modeReport := ModeReport{Mode: mode, Minimum: fixture.MinPrecision[mode]}
+		if reason := aiUnavailable(client, aiConfig, mode); reason != "" {
+			modeReport.Skipped = reason
+			report.Modes = append(report.Modes, modeReport)
+			continue
+		}
+
+		var total float64
+		for _, query := range fixture.Queries {
+			queryReport := evaluateQuery(ctx, engine, mode, query, fixture.k(query))
+			total += queryReport.Precision
+			modeReport.Queries = append(modeReport.Queries, queryReport)
+		}
+		modeReport.Precision = total / float64(len(fixture.Queries))
+		report.Modes = append(report.Modes, modeReport)
+	}
+	return report, nil
+}
+
+// aiUnavailable returns why mode can't be evaluated with client, or "" when it can
+func aiUnavailable(client manticore.ClientInterface, aiConfig *models.AISearchConfig, mode models.SearchMode) string {
+	if !mode.UsesAI() {
+		return ""
+	}
+	if aiConfig == nil || !aiConfig.Enabled {
+		return "AI search is disabled"
+	}
+	if err := client.GetCapabilities().RequireAISearch(); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// evaluateQuery runs query in mode and judges its top k results
+func evaluateQuery(ctx context.Context, engine *search.SearchEngine, mode models.SearchMode, query Query, k int) QueryReport {
+	report := QueryReport{Query: query.Query, K: k, Retrieved: []int{}}
+	response, err := engine.SearchContext(ctx, query.Query, mode, 1, k)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	for _, result := range response.Documents {
+		if result.Document != nil && len(report.Retrieved) < k {
+			report.Retrieved = append(report.Retrieved, result.Document.ID)
+		}
+	}
+	report.Precision = PrecisionAtK(report.Retrieved, query.Relevant, k)
+	return report
+}
+
+// PrecisionAtK returns the share of the top k retrieved ids that are relevant. The share is of
+// k or of the number of relevant ids when there are fewer, so that a query with a single relevant
+// document still reaches 1 by ranking it in the top k.
+func PrecisionAtK(retrieved, relevant []int, k int) float64 {
+	if k <= 0 || len(relevant) == 0 {
+		return 0
+	}
+	judged := make(map[int]bool, len(relevant))
+	for _, id := range relevant {
+		judged[id] = true
+	}
+	hits := 0
+	for _, id := range retrieved[:min(k, len(retrieved))] {
+		if judged[id] {
+			hits++
+			delete(judged, id)
+		}
+	}
+	return float64(hits) / float64(min(k, len(relevant)))
+}
@@ -0,0 +1,188 @@
+package quality
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/search"
+)
+
+// DefaultK is the number of top results judged when neither the fixture nor the query sets k
+const DefaultK = 5
+
+// Fixture is a canned corpus with judged queries and the least mean precision@k each search mode
+// must reach on it. Fixtures are JSON files, usually in testdata/quality:
+//
+//	{
+//	  "k": 3,
+//	  "min_precision": {"basic": 0.6, "vector": 0.5},
+//	  "documents": [{"id": 1, "title": "...", "content": "...", "url": "..."}],
+//	  "queries": [{"query": "...", "relevant": [1, 4]}]
+//	}
+type Fixture struct {
+	Name         string                        `json:"-"` // file name without the extension
+	K            int                           `json:"k"`
+	MinPrecision map[models.SearchMode]float64 `json:"min_precision"`
+	Documents    []FixtureDocument             `json:"documents"`
+	Queries      []Query                       `json:"queries"`
+}
+
+// FixtureDocument is a document of a fixture's corpus
+type FixtureDocument struct {
+	ID      int    `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	URL     string `json:"url"`
+}
+
+// Query is a judged query: relevant lists the ids of the documents that answer it
+type Query struct {
+	Query    string `json:"query"`
+	Relevant []int  `json:"relevant"`
+	K        int    `json:"k,omitempty"` // overrides the fixture's k
+}
+
+// LoadFixtures reads the fixture at path, or every .json fixture of the directory at path in
+// name order
+func LoadFixtures(path string) ([]*Fixture, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.json")); err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no quality fixtures in %s", path)
+		}
+	}
+
+	fixtures := make([]*Fixture, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		fixture, err := ParseFixture(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		fixture.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, nil
+}
+
+// ParseFixture parses and validates a fixture
+func ParseFixture(data []byte) (*Fixture, error) {
+	var fixture Fixture
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&fixture); err != nil {
+		return nil, fmt.Errorf("invalid quality fixture: %w", err)
+	}
+	if err := fixture.validate(); err != nil {
+		return nil, err
+	}
+	return &fixture, nil
+}
+
+func (f *Fixture) validate() error {
+	if f.K < 0 {
+		return fmt.Errorf("invalid k: %d", f.K)
+	}
+	if len(f.MinPrecision) == 0 {
+		return fmt.Errorf("min_precision sets no search mode")
+	}
+	for mode, minimum := range f.MinPrecision {
+		if _, err := search.ValidateSearchMode(string(mode)); err != nil {
+			return err
+		}
+		if minimum < 0 || minimum > 1 {
+			return fmt.Errorf("invalid min_precision of %s: %v (use 0 to 1)", mode, minimum)
+		}
+	}
+
+	if len(f.Documents) == 0 {
+		return fmt.Errorf("fixture has no documents")
+	}
+	ids := make(map[int]bool, len(f.Documents))
+	for _, doc := range f.Documents {
+		if doc.ID <= 0 || ids[doc.ID] {
+			return fmt.Errorf("invalid or duplicate document id %d", doc.ID)
+		}
+		if doc.Title == "" || doc.Content == "" || doc.URL == "" {
+			return fmt.Errorf("document %d needs a title, content and url", doc.ID)
+		}
+		ids[doc.ID] = true
+	}
+
+	if len(f.Queries) == 0 {
+		return fmt.Errorf("fixture has no queries")
+	}
+	for _, query := range f.Queries {
+		if strings.TrimSpace(query.Query) == "" || len(query.Relevant) == 0 {
+			return fmt.Errorf("query %q needs text and relevant documents", query.Query)
+		}
+		if query.K < 0 {
+			return fmt.Errorf("invalid k of query %q: %d", query.Query, query.K)
+		}
+		for _, id := range query.Relevant {
+			if !ids[id] {
+				return fmt.Errorf("query %q judges unknown document %d relevant", query.Query, id)
+			}
+		}
+	}
+	return nil
+}
+
+// k returns the number of top results judged for query
+func (f *Fixture) k(query Query) int {
+	switch {
+	case query.K > 0:
+		return query.K
+	case f.K > 0:
+		return f.K
+	}
+	return DefaultK
+}
+
+// modes returns the modes the fixture sets a minimum for, in modeOrder
+func (f *Fixture) modes() []models.SearchMode {
+	var modes []models.SearchMode
+	for _, mode := range modeOrder {
+		if _, ok := f.MinPrecision[mode]; ok {
+			modes = append(modes, mode)
+		}
+	}
+	return modes
+}
+
+// modeOrder is the order modes are evaluated and reported in
+var modeOrder = []models.SearchMode{
+	models.SearchModeBasic,
+	models.SearchModeFullText,
+	models.SearchModeVector,
+	models.SearchModeHybrid,
+	models.SearchModeAI,
+	models.SearchModeAIHybrid,
+	models.SearchModeAuto,
+}
+
+// documents converts the corpus to indexable documents
+func (f *Fixture) documents() []*models.Document {
+	documents := make([]*models.Document, 0, len(f.Documents))
+	for _, doc := range f.Documents {
+		documents = append(documents, &models.Document{ID: doc.ID, Title: doc.Title, Content: doc.Content, URL: doc.URL})
+	}
+	return documents
+}
@@ -0,0 +1,142 @@
+package quality
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// fixturesPath is the directory of the quality fixtures, relative to this package
+const fixturesPath = "../../testdata/quality"
+
+func TestPrecisionAtK(t *testing.T) {
+	tests := []struct {
+		name      string
+		retrieved []int
+		relevant  []int
+		k         int
+		expected  float64
+	}{
+		{"all relevant", []int{1, 2, 3}, []int{1, 2, 3, 4}, 3, 1},
+		{"one of three", []int{5, 1, 6}, []int{1, 2, 3}, 3, 1.0 / 3},
+		{"single relevant ranked", []int{5, 1, 6}, []int{1}, 3, 1},
+		{"single relevant beyond k", []int{5, 6, 1}, []int{1}, 2, 0},
+		{"fewer results than k", []int{2}, []int{1, 2}, 3, 0.5},
+		{"repeated results count once", []int{1, 1, 1}, []int{1, 2, 3}, 3, 1.0 / 3},
+		{"nothing retrieved", nil, []int{1}, 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PrecisionAtK(tt.retrieved, tt.relevant, tt.k); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseFixture(t *testing.T) {
+	valid := `{"k": 2, "min_precision": {"basic": 0.5}, "documents": [{"id": 1, "title": "T", "content": "C", "url": "u"}], "queries": [{"query": "q", "relevant": [1]}]}`
+	fixture, err := ParseFixture([]byte(valid))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fixture.k(fixture.Queries[0]) != 2 || fixture.k(Query{K: 4}) != 4 || (&Fixture{}).k(Query{}) != DefaultK {
+		t.Error("Expected k to come from the query, then the fixture, then DefaultK")
+	}
+
+	for _, invalid := range []string{
+		`{"min_precision": {}, "documents": [{"id": 1, "title": "T", "content": "C", "url": "u"}], "queries": [{"query": "q", "relevant": [1]}]}`,
+		`{"min_precision": {"semantic": 0.5}, "documents": [{"id": 1, "title": "T", "content": "C", "url": "u"}], "queries": [{"query": "q", "relevant": [1]}]}`,
+		`{"min_precision": {"basic": 1.5}, "documents": [{"id": 1, "title": "T", "content": "C", "url": "u"}], "queries": [{"query": "q", "relevant": [1]}]}`,
+		`{"min_precision": {"basic": 0.5}, "documents": [{"id": 1, "title": "T", "content": "C", "url": "u"}, {"id": 1, "title": "T", "content": "C", "url": "u"}], "queries": [{"query": "q", "relevant": [1]}]}`,
+		`{"min_precision": {"basic": 0.5}, "documents": [{"id": 1, "title": "T", "content": "C", "url": "u"}], "queries": [{"query": "q", "relevant": [2]}]}`,
+		`{"min_precision": {"basic": 0.5}, "documents": [{"id": 1, "title": "T", "content": "C", "url": "u"}], "queries": [{"query": "q", "relevant": []}]}`,
+		`{"min_precision": {"basic": 0.5}, "documents": [{"id": 1, "title": "T", "content": "C", "url": "u"}], "queries": [{"query": "q", "relevant": [1]}], "extra": true}`,
+	} {
+		if _, err := ParseFixture([]byte(invalid)); err == nil {
+			t.Errorf("Expected an error for %s", invalid)
+		}
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	fixtures, err := LoadFixtures(fixturesPath)
+	if err != nil {
+		t.Fatalf("Failed to load the quality fixtures: %v", err)
+	}
+	for _, fixture := range fixtures {
+		if fixture.Name == "" || len(fixture.modes()) != len(fixture.MinPrecision) {
+			t.Errorf("Unexpected fixture %q with modes %v", fixture.Name, fixture.modes())
+		}
+	}
+}
+
+func TestReportWrite(t *testing.T) {
+	report := &Report{Fixture: "handbook", Modes: []ModeReport{
+		{Mode: models.SearchModeBasic, Precision: 0.9, Minimum: 0.6},
+		{Mode: models.SearchModeVector, Precision: 0.4, Minimum: 0.6, Queries: []QueryReport{
+			{Query: "backup", K: 3, Retrieved: []int{7, 3}, Precision: 1.0 / 3},
+			{Query: "tls", K: 3, Retrieved: []int{7}, Precision: 1},
+		}},
+		{Mode: models.SearchModeAI, Minimum: 0.5, Skipped: "AI search is disabled"},
+	}}
+	if report.Passed() {
+		t.Error("Expected a mode below its minimum to fail the report")
+	}
+
+	var out bytes.Buffer
+	report.Write(&out)
+	for _, expected := range []string{"basic      precision@k 0.900 (minimum 0.600) ok", "vector     precision@k 0.400 (minimum 0.600) FAIL", `"backup": precision@3 0.333, retrieved [7 3]`, "ai         skipped"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in:\n%s", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), `"tls"`) {
+		t.Errorf("Expected queries that found every relevant document to be left out:\n%s", out.String())
+	}
+}
+
+// TestQualityRegression evaluates the fixtures of testdata/quality against a real Manticore and
+// fails when a mode falls below its minimum precision. It is skipped unless
+// MANTICORE_INTEGRATION_TESTS=1 is set, see scripts/run-quality-tests.sh.
+func TestQualityRegression(t *testing.T) {
+	if os.Getenv("MANTICORE_INTEGRATION_TESTS") != "1" {
+		t.Skip("Skipping quality regression test. Set MANTICORE_INTEGRATION_TESTS=1 to run.")
+	}
+	url := os.Getenv("MANTICORE_URL")
+	if url == "" {
+		url = "http://localhost:9308"
+	}
+
+	config := manticore.DefaultHTTPClientConfig(url)
+	config.TablePrefix = TablePrefix
+	client := manticore.NewHTTPClient(config)
+	defer client.Close()
+	if err := client.WaitForReady(30 * time.Second); err != nil {
+		t.Fatalf("Failed to connect to Manticore at %s: %v", url, err)
+	}
+	aiConfig, err := models.LoadAISearchConfigFromEnvironment()
+	if err != nil {
+		t.Fatalf("Invalid AI configuration: %v", err)
+	}
+
+	reports, err := Run(context.Background(), client, aiConfig, fixturesPath)
+	if err != nil {
+		t.Fatalf("Evaluation failed: %v", err)
+	}
+	for _, report := range reports {
+		var out strings.Builder
+		report.Write(&out)
+		if !report.Passed() {
+			t.Errorf("Search quality regressed:\n%s", out.String())
+		} else {
+			t.Log("\n" + out.String())
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package querytemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/jsonfile"
+)
+
+// Store keeps templates in memory and, when it has a path, rewrites them to a JSON file after
+// every change so they survive restarts. A nil *Store is valid and holds nothing.
+type Store struct {
+	mutex     sync.RWMutex
+	path      string
+	templates map[string]*Template
+}
+
+// NewStore creates a store. When path is empty templates are kept in memory only; otherwise
+// existing ones are loaded from the file.
+func NewStore(path string) (*Store, error) {
+	s := &Store{
+		path:      path,
+		templates: make(map[string]*Template),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query templates %s: %v", path, err)
+	}
+
+	var templates []*Template
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("failed to parse query templates %s: %v", path, err)
+	}
+	for _, template := range templates {
+		s.templates[template.Name] = template
+	}
+	return s, nil
+}
+
+// NewStoreFromEnvironment creates a store persisted to QUERY_TEMPLATES_FILE
+func NewStoreFromEnvironment() (*Store, error) {
+	return NewStore(os.Getenv("QUERY_TEMPLATES_FILE"))
+}
+
+// Put stores a template, replacing the one with the same name but keeping its creation time
+func (s *Store) Put(template Template) (Template, error) {
+	if s == nil {
+		return Template{}, fmt.Errorf("query templates are not available")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	template.UpdatedAt = time.Now()
+	template.CreatedAt = template.UpdatedAt
+	previous, replaced := s.templates[template.Name]
+	if replaced {
+		template.CreatedAt = previous.CreatedAt
+	}
+	s.templates[template.Name] = &template
+
+	if err := s.save(); err != nil {
+		if replaced {
+			s.templates[template.Name] = previous
+		} else {
+			delete(s.templates, template.Name)
+		}
+		return Template{}, err
+	}
+	return template, nil
+}
+
+// Get returns the template with the given name
+func (s *Store) Get(name string) (Template, bool) {
+	if s == nil {
+		return Template{}, false
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	template, ok := s.templates[name]
+	if !ok {
+		return Template{}, false
+	}
+	return *template, true
+}
+
+// List returns every template ordered by name
+func (s *Store) List() []Template {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]Template, 0, len(s.templates))
+	for _, template := range s.templates {
+		result = append(result, *template)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Delete removes a template and reports whether it existed
+func (s *Store) Delete(name string) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	template, ok := s.templates[name]
+	if !ok {
+		return false, nil
+	}
+	delete(s.templates, name)
+	if err := s.save(); err != nil {
+		s.templates[name] = template
+		return false, err
+	}
+	return true, nil
+}
+
+// save rewrites the file atomically (caller holds the lock)
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	templates := make([]*Template, 0, len(s.templates))
+	for _, template := range s.templates {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+
+	if err := jsonfile.WriteAtomic(s.path, templates); err != nil {
+		return fmt.Errorf("failed to write query templates: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package querytemplate
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// namePattern restricts template names to characters that need no escaping in a URL
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// placeholderPattern matches {{name}} placeholders, allowing spaces inside the braces
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]*)\s*\}\}`)
+
+// paramNamePattern restricts parameter names so placeholders are unambiguous
+var paramNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// Template is a named search whose values may contain {{name}} placeholders, replaced by the
+// parameters of each run. Values are kept as the search endpoint's parameter values and parsed by
+// the search endpoint after expansion.
+type Template struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Query       string           `json:"query"`
+	Mode        string           `json:"mode,omitempty"`
+	Fields      string           `json:"fields,omitempty"`    // fields parameter
+	Status      string           `json:"status,omitempty"`    // status parameter
+	Sort        string           `json:"sort,omitempty"`      // sort parameter
+	Since       string           `json:"since,omitempty"`     // since parameter
+	Tags        string           `json:"tags,omitempty"`      // tags parameter
+	TagsMode    string           `json:"tags_mode,omitempty"` // tags_mode parameter
+	Limit       int              `json:"limit,omitempty"`     // Fixed page size, 0 to let callers choose
+	Params      map[string]Param `json:"params,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// Param declares a placeholder of a template. One without a default is required.
+type Param struct {
+	Description string  `json:"description,omitempty"`
+	Default     *string `json:"default,omitempty"`
+}
+
+// values returns the search parameters of the template by name, before expansion
+func (t Template) values() map[string]string {
+	return map[string]string{
+		"query":     t.Query,
+		"mode":      t.Mode,
+		"fields":    t.Fields,
+		"status":    t.Status,
+		"sort":      t.Sort,
+		"since":     t.Since,
+		"tags":      t.Tags,
+		"tags_mode": t.TagsMode,
+	}
+}
+
+// Validate checks the name, the query and that every placeholder is a declared parameter. The
+// search parameters themselves are validated by the search endpoint once expanded.
+func (t Template) Validate() error {
+	var errs validation.Errors
+	if !namePattern.MatchString(t.Name) {
+		errs.Add("name", validation.InvalidFormat("name", t.Name, "template_name"))
+	}
+	if strings.TrimSpace(t.Query) == "" {
+		errs.Add("query", validation.Required("query"))
+	}
+	if t.Limit < 0 || t.Limit > 100 {
+		errs.Add("limit", validation.OutOfRange("limit", strconv.Itoa(t.Limit), 0, 100))
+	}
+
+	for name := range t.Params {
+		if !paramNamePattern.MatchString(name) {
+			errs.Add("params", validation.InvalidFormat("params", name, "parameter_name"))
+		}
+	}
+	for field, value := range t.values() {
+		for _, name := range Placeholders(value) {
+			if _, ok := t.Params[name]; !ok {
+				errs.Add(field, validation.InvalidValue(field, "{{"+name+"}}", t.paramNames()...))
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// Expand replaces the placeholders with params, or the defaults of missing ones, and returns the
+// search parameters of the template. Values inserted into the query are reduced to plain words so
+// they can't change the structure of the vetted query.
+func (t Template) Expand(params map[string]string) (url.Values, error) {
+	var errs validation.Errors
+	for name := range params {
+		if _, ok := t.Params[name]; !ok {
+			errs.Add("params", validation.InvalidValue("params", name, t.paramNames()...))
+		}
+	}
+
+	resolved := make(map[string]string, len(t.Params))
+	for name, param := range t.Params {
+		if value, ok := params[name]; ok {
+			resolved[name] = value
+		} else if param.Default != nil {
+			resolved[name] = *param.Default
+		} else {
+			errs.Add("params."+name, validation.Required("params."+name))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	values := make(url.Values)
+	for field, value := range t.values() {
+		value = placeholderPattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+			name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+			if field == "query" {
+				return manticore.QueryTerms(resolved[name])
+			}
+			return resolved[name]
+		})
+		if value = strings.TrimSpace(value); value != "" {
+			values.Set(field, value)
+		}
+	}
+	if t.Limit > 0 {
+		values.Set("limit", strconv.Itoa(t.Limit))
+	}
+	return values, nil
+}
+
+// Placeholders returns the names of the placeholders in value
+func Placeholders(value string) []string {
+	var names []string
+	for _, match := range placeholderPattern.FindAllStringSubmatch(value, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+func (t Template) paramNames() []string {
+	names := make([]string, 0, len(t.Params))
+	for name := range t.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,129 @@
+package querytemplate
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+func stringPtr(s string) *string { return &s }
+
+func testTemplate() Template {
+	return Template{
+		Name:  "recent-by-tag",
+		Query: "@title {{terms}} -draft",
+		Mode:  "hybrid",
+		Tags:  "{{tag}}",
+		Sort:  "updated_at",
+		Limit: 20,
+		Params: map[string]Param{
+			"terms": {Description: "Words to find in titles"},
+			"tag":   {Default: stringPtr("go")},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := testTemplate().Validate(); err != nil {
+		t.Fatalf("Expected a valid template, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Template)
+		field  string
+	}{
+		{"invalid name", func(tpl *Template) { tpl.Name = "Recent By Tag" }, "name"},
+		{"missing query", func(tpl *Template) { tpl.Query = " " }, "query"},
+		{"limit", func(tpl *Template) { tpl.Limit = 500 }, "limit"},
+		{"undeclared placeholder", func(tpl *Template) { tpl.Status = "{{status}}" }, "status"},
+		{"invalid parameter name", func(tpl *Template) { tpl.Params["Bad-Name"] = Param{} }, "params"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tpl := testTemplate()
+			tt.modify(&tpl)
+			var errs validation.Errors
+			if err := tpl.Validate(); !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != tt.field {
+				t.Errorf("Expected one %s error, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	values, err := testTemplate().Expand(map[string]string{"terms": `forms" | @content (secret`})
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+	expected := map[string]string{
+		"query": "@title forms content secret -draft",
+		"mode":  "hybrid",
+		"tags":  "go",
+		"sort":  "updated_at",
+		"limit": "20",
+	}
+	if len(values) != len(expected) {
+		t.Errorf("Unexpected parameters: %v", values)
+	}
+	for name, value := range expected {
+		if got := values.Get(name); got != value {
+			t.Errorf("Expected %s=%q, got %q", name, value, got)
+		}
+	}
+
+	var errs validation.Errors
+	if _, err := testTemplate().Expand(map[string]string{"tag": "go", "other": "x"}); !errors.As(err, &errs) || len(errs) != 2 {
+		t.Errorf("Expected errors for the unknown and the missing parameter, got %v", err)
+	}
+}
+
+func TestStorePutGetDelete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "templates.json")
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	created, err := s.Put(testTemplate())
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	replacement := testTemplate()
+	replacement.Mode = "basic"
+	replaced, err := s.Put(replacement)
+	if err != nil || !replaced.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("Expected the replacement to keep the creation time, got %+v, %v", replaced, err)
+	}
+
+	reopened, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	loaded, ok := reopened.Get("recent-by-tag")
+	if !ok || loaded.Mode != "basic" || *loaded.Params["tag"].Default != "go" {
+		t.Errorf("Unexpected reloaded template: %+v", loaded)
+	}
+	if list := reopened.List(); len(list) != 1 {
+		t.Errorf("Expected 1 template, got %d", len(list))
+	}
+
+	if deleted, err := reopened.Delete("recent-by-tag"); !deleted || err != nil {
+		t.Errorf("Expected the template to be deleted, got %v, %v", deleted, err)
+	}
+	if _, ok := reopened.Get("recent-by-tag"); ok {
+		t.Error("Expected the template to be gone")
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	var s *Store
+	if _, err := s.Put(testTemplate()); err == nil {
+		t.Error("Expected Put on a nil store to fail")
+	}
+	if _, ok := s.Get("recent-by-tag"); ok || s.List() != nil {
+		t.Error("Expected a nil store to hold nothing")
+	}
+}
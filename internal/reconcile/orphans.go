@@ -0,0 +1,154 @@
+package reconcile
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// Cleanup is the outcome of an orphan cleanup. Documents and their vectors are written to
+// separate tables, so a failure between the two writes leaves one without the other.
+type Cleanup struct {
+	CheckedAt       time.Time
+	DryRun          bool
+	OrphanedVectors IDs // documents_vector rows without a documents row
+	MissingVectors  IDs // documents rows without a documents_vector row
+
+	DeletedVectors     int // orphaned vector rows deleted
+	ReindexedDocuments int // documents reindexed to restore their vector rows
+	Error              string
+}
+
+// CleanOrphans deletes the vector rows without a document and reindexes the documents without a
+// vector row, loading them with load and computing their vectors with vectorize. Without
+// vectorize, documents missing their vectors are only reported. A dry run only reports both.
+func CleanOrphans(index Index, load func(id int64) (*models.Document, error), vectorize func(doc *models.Document) []float64, dryRun bool) (*Cleanup, error) {
+	indexed, err := index.DocumentIDs("documents")
+	if err != nil {
+		return nil, err
+	}
+	vectors, err := index.DocumentIDs("documents_vector")
+	if err != nil {
+		return nil, err
+	}
+
+	indexedSet := idSet(indexed)
+	vectorSet := idSet(vectors)
+	var orphaned, missingVectors []int64
+	for _, id := range vectors {
+		if !indexedSet[id] {
+			orphaned = append(orphaned, id)
+		}
+	}
+	for _, id := range indexed {
+		if !vectorSet[id] {
+			missingVectors = append(missingVectors, id)
+		}
+	}
+
+	cleanup := &Cleanup{
+		CheckedAt:       time.Now(),
+		DryRun:          dryRun,
+		OrphanedVectors: listIDs(orphaned),
+		MissingVectors:  listIDs(missingVectors),
+	}
+	if dryRun {
+		return cleanup, nil
+	}
+
+	if len(orphaned) > 0 {
+		if err := index.DeleteDocuments("documents_vector", orphaned); err != nil {
+			cleanup.Error = err.Error()
+			return cleanup, nil
+		}
+		cleanup.DeletedVectors = len(orphaned)
+		log.Printf("[CONSISTENCY] [CLEANUP] Deleted %d orphaned vector rows", len(orphaned))
+	}
+
+	if len(missingVectors) == 0 || vectorize == nil {
+		return cleanup, nil
+	}
+	documents := make([]*models.Document, 0, len(missingVectors))
+	documentVectors := make([][]float64, 0, len(missingVectors))
+	for _, id := range missingVectors {
+		doc, err := load(id)
+		if err != nil {
+			cleanup.Error = fmt.Sprintf("failed to load document %d: %v", id, err)
+			return cleanup, nil
+		}
+		documents = append(documents, doc)
+		documentVectors = append(documentVectors, vectorize(doc))
+	}
+	if err := index.IndexDocuments(documents, documentVectors); err != nil {
+		cleanup.Error = err.Error()
+		return cleanup, nil
+	}
+	cleanup.ReindexedDocuments = len(documents)
+	log.Printf("[CONSISTENCY] [CLEANUP] Reindexed %d documents without vector rows", len(documents))
+	return cleanup, nil
+}
+
+// CleanupIntervalFromEnvironment reads ORPHAN_CLEANUP_INTERVAL, a duration such as "1h". Unset
+// or 0 disables the periodic cleanup.
+func CleanupIntervalFromEnvironment() (time.Duration, error) {
+	value := os.Getenv("ORPHAN_CLEANUP_INTERVAL")
+	if value == "" {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		return 0, fmt.Errorf("invalid ORPHAN_CLEANUP_INTERVAL: %q", value)
+	}
+	return interval, nil
+}
+
+// Scheduler periodically runs an orphan cleanup
+type Scheduler struct {
+	run      func()
+	interval time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler calling run every interval; call Start to begin
+func NewScheduler(run func(), interval time.Duration) *Scheduler {
+	return &Scheduler{
+		run:      run,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start calls run in a background goroutine every interval
+func (s *Scheduler) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.run()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the background goroutine, waiting for a run in progress to finish
+func (s *Scheduler) Close() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	s.wg.Wait()
+}
@@ -0,0 +1,116 @@
+package reconcile
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func loadDocument(id int64) (*models.Document, error) {
+	if id > 100 {
+		return nil, errors.New("not found")
+	}
+	return &models.Document{ID: int(id), Title: "loaded"}, nil
+}
+
+func vectorizeDocument(doc *models.Document) []float64 {
+	return []float64{float64(doc.ID)}
+}
+
+func TestCleanOrphans(t *testing.T) {
+	index := newFakeIndex([]int64{1, 2, 4}, []int64{1, 3, 4, 6})
+
+	cleanup, err := CleanOrphans(index, loadDocument, vectorizeDocument, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cleanup.OrphanedVectors.Count != 2 || cleanup.OrphanedVectors.IDs[0] != 3 || cleanup.OrphanedVectors.IDs[1] != 6 {
+		t.Errorf("Expected vectors 3 and 6 to be orphaned, got %+v", cleanup.OrphanedVectors)
+	}
+	if cleanup.MissingVectors.Count != 1 || cleanup.MissingVectors.IDs[0] != 2 {
+		t.Errorf("Expected document 2 to miss its vector, got %+v", cleanup.MissingVectors)
+	}
+	if cleanup.DeletedVectors != 2 || cleanup.ReindexedDocuments != 1 || cleanup.Error != "" {
+		t.Errorf("Unexpected cleanup: %+v", cleanup)
+	}
+	if index.tables["documents_vector"][3] || index.tables["documents_vector"][6] || !index.tables["documents_vector"][2] {
+		t.Errorf("Expected orphans deleted and the missing vector restored, got %v", index.tables["documents_vector"])
+	}
+	if len(index.vectors) != 1 || index.vectors[0][0] != 2 {
+		t.Errorf("Expected the vector of document 2, got %v", index.vectors)
+	}
+
+	cleanup, err = CleanOrphans(index, loadDocument, vectorizeDocument, false)
+	if err != nil || cleanup.OrphanedVectors.Count != 0 || cleanup.MissingVectors.Count != 0 || cleanup.DeletedVectors != 0 {
+		t.Errorf("Expected no orphans after the cleanup, got %+v, %v", cleanup, err)
+	}
+}
+
+func TestCleanOrphansDryRun(t *testing.T) {
+	index := newFakeIndex([]int64{1, 2}, []int64{1, 3})
+
+	cleanup, err := CleanOrphans(index, loadDocument, vectorizeDocument, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !cleanup.DryRun || cleanup.OrphanedVectors.Count != 1 || cleanup.MissingVectors.Count != 1 {
+		t.Errorf("Expected both orphans to be reported, got %+v", cleanup)
+	}
+	if !index.tables["documents_vector"][3] || len(index.indexed) != 0 {
+		t.Error("Expected a dry run to change nothing")
+	}
+}
+
+func TestCleanOrphansWithoutVectorizer(t *testing.T) {
+	index := newFakeIndex([]int64{1, 2}, []int64{1})
+
+	cleanup, err := CleanOrphans(index, loadDocument, nil, false)
+	if err != nil || cleanup.MissingVectors.Count != 1 || cleanup.ReindexedDocuments != 0 || len(index.indexed) != 0 {
+		t.Errorf("Expected the missing vector to be only reported, got %+v, %v", cleanup, err)
+	}
+}
+
+func TestCleanOrphansLoadFailure(t *testing.T) {
+	index := newFakeIndex([]int64{1, 200}, []int64{1})
+
+	cleanup, err := CleanOrphans(index, loadDocument, vectorizeDocument, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cleanup.Error == "" || cleanup.ReindexedDocuments != 0 {
+		t.Errorf("Expected the load failure to be reported, got %+v", cleanup)
+	}
+}
+
+func TestCleanupIntervalFromEnvironment(t *testing.T) {
+	t.Setenv("ORPHAN_CLEANUP_INTERVAL", "")
+	if interval, err := CleanupIntervalFromEnvironment(); err != nil || interval != 0 {
+		t.Errorf("Expected the cleanup to be disabled by default, got %v, %v", interval, err)
+	}
+
+	t.Setenv("ORPHAN_CLEANUP_INTERVAL", "1h")
+	if interval, err := CleanupIntervalFromEnvironment(); err != nil || interval != time.Hour {
+		t.Errorf("Expected 1h, got %v, %v", interval, err)
+	}
+
+	t.Setenv("ORPHAN_CLEANUP_INTERVAL", "hourly")
+	if _, err := CleanupIntervalFromEnvironment(); err == nil {
+		t.Error("Expected an error for an invalid interval")
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	runs := make(chan struct{}, 10)
+	scheduler := NewScheduler(func() { runs <- struct{}{} }, 10*time.Millisecond)
+	scheduler.Start()
+
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the scheduler to run the cleanup")
+	}
+	scheduler.Close()
+	scheduler.Close()
+}
@@ -0,0 +1,228 @@
+package reconcile
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// Modes selected by STARTUP_CONSISTENCY_CHECK
+const (
+	ModeOff    = "off"
+	ModeReport = "report"
+	ModeRepair = "repair"
+)
+
+// maxListedIDs bounds the ids each list of a Report keeps, so a badly broken index does not
+// produce a huge status response; the counts are always complete
+const maxListedIDs = 100
+
+// Index is the part of the Manticore client a consistency check needs
+type Index interface {
+	DocumentIDs(table string) ([]int64, error)
+	DeleteDocuments(table string, ids []int64) error
+	IndexDocuments(documents []*models.Document, vectors [][]float64) error
+}
+
+// Report describes how the indexed corpus differs from the documents of the data directory
+type Report struct {
+	CheckedAt        time.Time
+	SourceDocuments  int // documents in the data directory
+	IndexedDocuments int // rows of the documents table
+	IndexedVectors   int // rows of the documents_vector table
+
+	Missing        IDs // source documents absent from the documents table
+	MissingVectors IDs // source documents absent from the documents_vector table
+	Orphaned       IDs // documents_vector rows without a documents row
+	Extra          IDs // documents rows not in the data directory, such as documents added at runtime
+
+	Repaired    int    // rows reindexed or deleted by Repair
+	RepairError string // why Repair failed, if it did
+}
+
+// IDs counts a set of document ids and lists the lowest of them
+type IDs struct {
+	Count int
+	IDs   []int64 // at most maxListedIDs
+}
+
+// Consistent reports whether the index holds every source document and no orphaned vectors.
+// Extra documents do not make an index inconsistent.
+func (r *Report) Consistent() bool {
+	return r.Missing.Count == 0 && r.MissingVectors.Count == 0 && r.Orphaned.Count == 0
+}
+
+// ModeFromEnvironment reads STARTUP_CONSISTENCY_CHECK: off, report (the default) or repair
+func ModeFromEnvironment() (string, error) {
+	mode := os.Getenv("STARTUP_CONSISTENCY_CHECK")
+	switch mode {
+	case "":
+		return ModeReport, nil
+	case ModeOff, ModeReport, ModeRepair:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid STARTUP_CONSISTENCY_CHECK %q, expected %q, %q or %q", mode, ModeOff, ModeReport, ModeRepair)
+}
+
+// Check compares documents with the ids indexed in the documents and documents_vector tables
+func Check(index Index, documents []*models.Document) (*Report, error) {
+	d, err := compare(index, documents)
+	if err != nil {
+		return nil, err
+	}
+	return d.report, nil
+}
+
+// Repair reindexes the source documents missing from either table and deletes the orphaned
+// vector rows, then checks again. Extra documents are kept, they may have been added at runtime.
+// vectors are the TF-IDF vectors of documents, in the same order. The returned report is the one
+// of the second check, or the first one with RepairError set when the repair failed.
+func Repair(index Index, documents []*models.Document, vectors [][]float64) (*Report, error) {
+	d, err := compare(index, documents)
+	if err != nil {
+		return nil, err
+	}
+	if d.report.Consistent() {
+		return d.report, nil
+	}
+
+	unindexed := idSet(d.missing)
+	for _, id := range d.missingVectors {
+		unindexed[id] = true
+	}
+	var reindex []*models.Document
+	var reindexVectors [][]float64
+	for i, doc := range documents {
+		if !unindexed[int64(doc.ID)] {
+			continue
+		}
+		reindex = append(reindex, doc)
+		if len(vectors) == len(documents) {
+			reindexVectors = append(reindexVectors, vectors[i])
+		}
+	}
+
+	if len(d.orphaned) > 0 {
+		if err := index.DeleteDocuments("documents_vector", d.orphaned); err != nil {
+			d.report.RepairError = err.Error()
+			return d.report, nil
+		}
+		log.Printf("[CONSISTENCY] [REPAIR] Deleted %d orphaned vector rows", len(d.orphaned))
+	}
+	if len(reindex) > 0 {
+		if err := index.IndexDocuments(reindex, reindexVectors); err != nil {
+			d.report.Repaired = len(d.orphaned)
+			d.report.RepairError = err.Error()
+			return d.report, nil
+		}
+		log.Printf("[CONSISTENCY] [REPAIR] Reindexed %d missing documents", len(reindex))
+	}
+
+	report, err := Check(index, documents)
+	if err != nil {
+		return nil, err
+	}
+	report.Repaired = len(d.orphaned) + len(reindex)
+	return report, nil
+}
+
+// difference holds the complete id lists behind a report
+type difference struct {
+	report                            *Report
+	missing, missingVectors, orphaned []int64
+}
+
+func compare(index Index, documents []*models.Document) (*difference, error) {
+	indexed, err := index.DocumentIDs("documents")
+	if err != nil {
+		return nil, err
+	}
+	vectors, err := index.DocumentIDs("documents_vector")
+	if err != nil {
+		return nil, err
+	}
+
+	source := make(map[int64]bool, len(documents))
+	for _, doc := range documents {
+		source[int64(doc.ID)] = true
+	}
+	indexedSet := idSet(indexed)
+	vectorSet := idSet(vectors)
+
+	d := &difference{}
+	var extra []int64
+	for id := range source {
+		if !indexedSet[id] {
+			d.missing = append(d.missing, id)
+		}
+		if !vectorSet[id] {
+			d.missingVectors = append(d.missingVectors, id)
+		}
+	}
+	for _, id := range vectors {
+		if !indexedSet[id] {
+			d.orphaned = append(d.orphaned, id)
+		}
+	}
+	for _, id := range indexed {
+		if !source[id] {
+			extra = append(extra, id)
+		}
+	}
+
+	d.report = &Report{
+		CheckedAt:        time.Now(),
+		SourceDocuments:  len(source),
+		IndexedDocuments: len(indexed),
+		IndexedVectors:   len(vectors),
+		Missing:          listIDs(d.missing),
+		MissingVectors:   listIDs(d.missingVectors),
+		Orphaned:         listIDs(d.orphaned),
+		Extra:            listIDs(extra),
+	}
+	return d, nil
+}
+
+// Log writes the outcome of a check
+func (r *Report) Log() {
+	if r.Consistent() {
+		log.Printf("[CONSISTENCY] Index is consistent with the data directory: %d source documents, %d indexed, %d vectors, %d extra",
+			r.SourceDocuments, r.IndexedDocuments, r.IndexedVectors, r.Extra.Count)
+	} else {
+		log.Printf("[CONSISTENCY] [WARNING] Index differs from the data directory: %d source documents, %d indexed, %d vectors; "+
+			"missing %d %v, missing vectors %d %v, orphaned vectors %d %v",
+			r.SourceDocuments, r.IndexedDocuments, r.IndexedVectors,
+			r.Missing.Count, r.Missing.IDs, r.MissingVectors.Count, r.MissingVectors.IDs, r.Orphaned.Count, r.Orphaned.IDs)
+	}
+	if r.Extra.Count > 0 {
+		log.Printf("[CONSISTENCY] %d indexed documents are not in the data directory: %v", r.Extra.Count, r.Extra.IDs)
+	}
+	if r.Repaired > 0 {
+		log.Printf("[CONSISTENCY] Repaired %d rows", r.Repaired)
+	}
+	if r.RepairError != "" {
+		log.Printf("[CONSISTENCY] [ERROR] Repair failed: %s", r.RepairError)
+	}
+}
+
+func idSet(ids []int64) map[int64]bool {
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+// listIDs sorts ids and keeps the lowest maxListedIDs of them
+func listIDs(ids []int64) IDs {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	listed := IDs{Count: len(ids), IDs: ids}
+	if len(ids) > maxListedIDs {
+		listed.IDs = ids[:maxListedIDs]
+	}
+	return listed
+}
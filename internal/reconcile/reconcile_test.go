@@ -0,0 +1,151 @@
+package reconcile
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// fakeIndex keeps the ids of both tables in memory
+type fakeIndex struct {
+	tables      map[string]map[int64]bool
+	indexed     []int
+	vectors     [][]float64
+	deleteError error
+}
+
+func newFakeIndex(documents, vectors []int64) *fakeIndex {
+	return &fakeIndex{tables: map[string]map[int64]bool{
+		"documents":        idSet(documents),
+		"documents_vector": idSet(vectors),
+	}}
+}
+
+func (f *fakeIndex) DocumentIDs(table string) ([]int64, error) {
+	var ids []int64
+	for id := range f.tables[table] {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (f *fakeIndex) DeleteDocuments(table string, ids []int64) error {
+	if f.deleteError != nil {
+		return f.deleteError
+	}
+	for _, id := range ids {
+		delete(f.tables[table], id)
+	}
+	return nil
+}
+
+func (f *fakeIndex) IndexDocuments(documents []*models.Document, vectors [][]float64) error {
+	for _, doc := range documents {
+		f.tables["documents"][int64(doc.ID)] = true
+		f.tables["documents_vector"][int64(doc.ID)] = true
+		f.indexed = append(f.indexed, doc.ID)
+	}
+	f.vectors = append(f.vectors, vectors...)
+	return nil
+}
+
+func sourceDocuments(ids ...int) []*models.Document {
+	var documents []*models.Document
+	for _, id := range ids {
+		documents = append(documents, &models.Document{ID: id})
+	}
+	return documents
+}
+
+func TestCheck(t *testing.T) {
+	index := newFakeIndex([]int64{1, 2, 5}, []int64{1, 3, 5})
+
+	report, err := Check(index, sourceDocuments(1, 2, 4))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.SourceDocuments != 3 || report.IndexedDocuments != 3 || report.IndexedVectors != 3 {
+		t.Errorf("Unexpected counts: %+v", report)
+	}
+	if report.Missing.Count != 1 || report.Missing.IDs[0] != 4 {
+		t.Errorf("Expected document 4 to be missing, got %+v", report.Missing)
+	}
+	if report.MissingVectors.Count != 2 || report.MissingVectors.IDs[0] != 2 || report.MissingVectors.IDs[1] != 4 {
+		t.Errorf("Expected vectors 2 and 4 to be missing, got %+v", report.MissingVectors)
+	}
+	if report.Orphaned.Count != 1 || report.Orphaned.IDs[0] != 3 {
+		t.Errorf("Expected vector 3 to be orphaned, got %+v", report.Orphaned)
+	}
+	if report.Extra.Count != 1 || report.Extra.IDs[0] != 5 {
+		t.Errorf("Expected document 5 to be extra, got %+v", report.Extra)
+	}
+	if report.Consistent() {
+		t.Error("Expected the report to be inconsistent")
+	}
+}
+
+func TestCheckListsAtMostMaxListedIDs(t *testing.T) {
+	var ids []int
+	for id := maxListedIDs + 10; id > 0; id-- {
+		ids = append(ids, id)
+	}
+	report, err := Check(newFakeIndex(nil, nil), sourceDocuments(ids...))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.Missing.Count != maxListedIDs+10 || len(report.Missing.IDs) != maxListedIDs || report.Missing.IDs[0] != 1 {
+		t.Errorf("Expected the lowest %d of %d ids, got %d starting with %d", maxListedIDs, maxListedIDs+10, len(report.Missing.IDs), report.Missing.IDs[0])
+	}
+}
+
+func TestRepair(t *testing.T) {
+	index := newFakeIndex([]int64{1, 2, 5}, []int64{1, 3, 5})
+
+	report, err := Repair(index, sourceDocuments(1, 2, 4), [][]float64{{1}, {2}, {4}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !report.Consistent() || report.Repaired != 3 || report.RepairError != "" {
+		t.Errorf("Expected a repaired index, got %+v", report)
+	}
+	if len(index.indexed) != 2 || index.indexed[0] != 2 || index.indexed[1] != 4 {
+		t.Errorf("Expected documents 2 and 4 to be reindexed, got %v", index.indexed)
+	}
+	if len(index.vectors) != 2 || index.vectors[0][0] != 2 || index.vectors[1][0] != 4 {
+		t.Errorf("Expected the vectors of the reindexed documents, got %v", index.vectors)
+	}
+	if index.tables["documents_vector"][3] {
+		t.Error("Expected the orphaned vector to be deleted")
+	}
+	if !index.tables["documents"][5] || report.Extra.Count != 1 {
+		t.Error("Expected the extra document to be kept")
+	}
+}
+
+func TestRepairFailure(t *testing.T) {
+	index := newFakeIndex([]int64{1}, []int64{1, 3})
+	index.deleteError = errors.New("connection refused")
+
+	report, err := Repair(index, sourceDocuments(1), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.Consistent() || report.RepairError != "connection refused" || report.Repaired != 0 {
+		t.Errorf("Expected the failed repair to be reported, got %+v", report)
+	}
+}
+
+func TestModeFromEnvironment(t *testing.T) {
+	for value, expected := range map[string]string{"": ModeReport, "off": ModeOff, "repair": ModeRepair} {
+		t.Setenv("STARTUP_CONSISTENCY_CHECK", value)
+		if mode, err := ModeFromEnvironment(); err != nil || mode != expected {
+			t.Errorf("Expected %q for %q, got %q, %v", expected, value, mode, err)
+		}
+	}
+
+	t.Setenv("STARTUP_CONSISTENCY_CHECK", "fix")
+	if _, err := ModeFromEnvironment(); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
@@ -0,0 +1,206 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Built-in detectors of personal data
+const (
+	DetectorEmail = "email" // Email addresses, masked as [EMAIL]
+	DetectorPhone = "phone" // Phone numbers written with a leading + or separators, masked as [PHONE]
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+	// phonePattern finds candidates, which isPhone then tells apart from dates, addresses and ids
+	phonePattern = regexp.MustCompile(`\+?\(?\d[\d ().\-]{5,}\d\)?`)
+	isoDate      = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// Minimum and maximum digits of a phone number, following E.164
+const (
+	minPhoneDigits = 7
+	maxPhoneDigits = 15
+)
+
+// rule masks the matches of one detector or pattern
+type rule struct {
+	name    string
+	pattern *regexp.Regexp
+	mask    string
+	accept  func(text string, start, end int) bool // nil accepts every match text[start:end]
+}
+
+// Redactor masks personal data in text before it is indexed or embedded. A nil *Redactor is
+// valid and leaves text unchanged.
+type Redactor struct {
+	rules []rule
+}
+
+// New creates a redactor applying the built-in detectors and the named regular expressions of
+// patterns, whose matches are masked as the upper-cased name in brackets, such as [TICKET]. The
+// patterns are applied first, in name order, then the detectors.
+func New(detectors []string, patterns map[string]string) (*Redactor, error) {
+	r := &Redactor{}
+
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("redaction pattern without a name")
+		}
+		pattern, err := regexp.Compile(patterns[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", name, err)
+		}
+		r.rules = append(r.rules, rule{name: name, pattern: pattern, mask: "[" + strings.ToUpper(name) + "]"})
+	}
+
+	seen := make(map[string]bool)
+	for _, detector := range detectors {
+		if seen[detector] {
+			return nil, fmt.Errorf("duplicate redaction detector %q", detector)
+		}
+		seen[detector] = true
+		switch detector {
+		case DetectorEmail:
+			r.rules = append(r.rules, rule{name: detector, pattern: emailPattern, mask: "[EMAIL]"})
+		case DetectorPhone:
+			r.rules = append(r.rules, rule{name: detector, pattern: phonePattern, mask: "[PHONE]", accept: isPhone})
+		default:
+			return nil, fmt.Errorf("unknown redaction detector %q (use %s or %s)", detector, DetectorEmail, DetectorPhone)
+		}
+	}
+
+	return r, nil
+}
+
+// FromEnvironment creates a redactor from PII_REDACTION, a comma-separated list of detectors, and
+// PII_REDACTION_PATTERNS, a JSON object of named regular expressions (JSON since expressions may
+// contain commas). It returns nil, which leaves text unchanged, when neither is set or
+// PII_REDACTION is "none" without patterns.
+func FromEnvironment() (*Redactor, error) {
+	var detectors []string
+	value := strings.ToLower(strings.TrimSpace(os.Getenv("PII_REDACTION")))
+	if value != "" && value != "none" {
+		for _, detector := range strings.Split(value, ",") {
+			detectors = append(detectors, strings.TrimSpace(detector))
+		}
+	}
+
+	var patterns map[string]string
+	if raw := strings.TrimSpace(os.Getenv("PII_REDACTION_PATTERNS")); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &patterns); err != nil {
+			return nil, fmt.Errorf("invalid PII_REDACTION_PATTERNS, expected a JSON object of named patterns: %w", err)
+		}
+	}
+
+	if len(detectors) == 0 && len(patterns) == 0 {
+		return nil, nil
+	}
+	return New(detectors, patterns)
+}
+
+// Names returns the configured patterns and detectors in the order they are applied
+func (r *Redactor) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.rules))
+	for _, rule := range r.rules {
+		names = append(names, rule.name)
+	}
+	return names
+}
+
+// Redact masks the personal data in text, returning the masked text and the number of masked
+// matches
+func (r *Redactor) Redact(text string) (string, int) {
+	if r == nil {
+		return text, 0
+	}
+
+	total := 0
+	for _, rule := range r.rules {
+		var count int
+		text, count = rule.apply(text)
+		total += count
+	}
+	return text, total
+}
+
+// apply masks the accepted matches of the rule in text
+func (rl rule) apply(text string) (string, int) {
+	matches := rl.pattern.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return text, 0
+	}
+
+	var b strings.Builder
+	count, last := 0, 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		if rl.accept != nil && !rl.accept(text, start, end) {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(rl.mask)
+		last = end
+		count++
+	}
+	if count == 0 {
+		return text, 0
+	}
+	b.WriteString(text[last:])
+	return b.String(), count
+}
+
+// isPhone reports whether the phone candidate text[start:end] is a phone number rather than a
+// date, an IP address, a plain number such as an order id or the digits of a longer word
+func isPhone(text string, start, end int) bool {
+	if wordRuneBefore(text, start) || wordRuneAfter(text, end) {
+		return false
+	}
+
+	candidate := text[start:end]
+	digits := 0
+	for _, r := range candidate {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	if digits < minPhoneDigits || digits > maxPhoneDigits {
+		return false
+	}
+	if !strings.HasPrefix(candidate, "+") && !strings.ContainsAny(candidate, " ().-") {
+		return false
+	}
+	if isoDate.MatchString(candidate) || net.ParseIP(candidate) != nil {
+		return false
+	}
+	// Parentheses must be balanced, so a trailing one closing the surrounding text is not part of it
+	return strings.Count(candidate, "(") == strings.Count(candidate, ")")
+}
+
+// wordRuneBefore reports whether text has a letter or digit right before offset i
+func wordRuneBefore(text string, i int) bool {
+	r, size := utf8.DecodeLastRuneInString(text[:i])
+	return size > 0 && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// wordRuneAfter reports whether text has a letter or digit right after offset i
+func wordRuneAfter(text string, i int) bool {
+	r, size := utf8.DecodeRuneInString(text[i:])
+	return size > 0 && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
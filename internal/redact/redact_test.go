@@ -0,0 +1,80 @@
+package redact
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	r, err := New([]string{DetectorEmail, DetectorPhone}, map[string]string{"ticket": `TCK-\d+`})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		text     string
+		expected string
+		count    int
+	}{
+		{"Contact jane.doe+support@example.co.uk for help", "Contact [EMAIL] for help", 1},
+		{"Call +1 555 123 4567 or (555) 123-4567.", "Call [PHONE] or [PHONE].", 2},
+		{"Reported in TCK-4521 by bob@example.com", "Reported in [TICKET] by [EMAIL]", 2},
+		{"Released 2024-05-01 on 192.168.100.200", "Released 2024-05-01 on 192.168.100.200", 0},
+		{"Order 123456789 and build v1.2.3", "Order 123456789 and build v1.2.3", 0},
+		{"Serial AB555-123-4567", "Serial AB555-123-4567", 0},
+		{"No personal data here", "No personal data here", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			result, count := r.Redact(tt.text)
+			if result != tt.expected || count != tt.count {
+				t.Errorf("Expected %q with %d matches, got %q with %d", tt.expected, tt.count, result, count)
+			}
+		})
+	}
+
+	if names := r.Names(); !reflect.DeepEqual(names, []string{"ticket", DetectorEmail, DetectorPhone}) {
+		t.Errorf("Unexpected names: %v", names)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New([]string{"ssn"}, nil); err == nil {
+		t.Error("Expected an error for an unknown detector")
+	}
+	if _, err := New([]string{DetectorEmail, DetectorEmail}, nil); err == nil {
+		t.Error("Expected an error for a duplicate detector")
+	}
+	if _, err := New(nil, map[string]string{"broken": `(`}); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestFromEnvironment(t *testing.T) {
+	t.Setenv("PII_REDACTION", "")
+	t.Setenv("PII_REDACTION_PATTERNS", "")
+	if r, err := FromEnvironment(); r != nil || err != nil {
+		t.Errorf("Expected no redactor, got %v, %v", r, err)
+	}
+
+	t.Setenv("PII_REDACTION", "Email, phone")
+	t.Setenv("PII_REDACTION_PATTERNS", `{"account": "ACC\\d{6}"}`)
+	r, err := FromEnvironment()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result, _ := r.Redact("ACC123456 owned by a@b.io"); result != "[ACCOUNT] owned by [EMAIL]" {
+		t.Errorf("Unexpected redaction: %q", result)
+	}
+
+	t.Setenv("PII_REDACTION_PATTERNS", `["ACC\\d{6}"]`)
+	if _, err := FromEnvironment(); err == nil {
+		t.Error("Expected an error for patterns that are not a JSON object")
+	}
+
+	var nilRedactor *Redactor
+	if result, count := nilRedactor.Redact("a@b.io"); result != "a@b.io" || count != 0 {
+		t.Errorf("Expected a nil redactor to keep text, got %q", result)
+	}
+}
@@ -0,0 +1,188 @@
+package retention
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// Policy limits which documents are kept in the index. A document is expired when it was not
+// updated within MaxAge, or when more than MaxDocuments more recently updated documents are
+// indexed. A nil *Policy keeps every document.
+type Policy struct {
+	MaxAge       time.Duration // zero keeps documents of any age
+	MaxDocuments int           // zero keeps any number of documents
+	// DryRun only reports the expired documents, neither deleting them nor keeping them from
+	// being indexed
+	DryRun bool
+}
+
+// PolicyFromEnvironment reads RETENTION_MAX_AGE_DAYS, RETENTION_MAX_DOCUMENTS and
+// RETENTION_DRY_RUN. It returns nil, keeping every document, when neither limit is set.
+func PolicyFromEnvironment() (*Policy, error) {
+	policy := &Policy{}
+
+	if value := os.Getenv("RETENTION_MAX_AGE_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RETENTION_MAX_AGE_DAYS: %w", err)
+		}
+		if days < 0 {
+			return nil, fmt.Errorf("RETENTION_MAX_AGE_DAYS must not be negative, got: %d", days)
+		}
+		policy.MaxAge = time.Duration(days) * 24 * time.Hour
+	}
+
+	if value := os.Getenv("RETENTION_MAX_DOCUMENTS"); value != "" {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RETENTION_MAX_DOCUMENTS: %w", err)
+		}
+		if count < 0 {
+			return nil, fmt.Errorf("RETENTION_MAX_DOCUMENTS must not be negative, got: %d", count)
+		}
+		policy.MaxDocuments = count
+	}
+
+	if value := os.Getenv("RETENTION_DRY_RUN"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RETENTION_DRY_RUN: %w", err)
+		}
+		policy.DryRun = dryRun
+	}
+
+	if policy.MaxAge == 0 && policy.MaxDocuments == 0 {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+// String describes the limits of the policy
+func (p *Policy) String() string {
+	if p == nil {
+		return "none"
+	}
+	var limits []string
+	if p.MaxAge > 0 {
+		limits = append(limits, fmt.Sprintf("max age %d days", int(p.MaxAge/(24*time.Hour))))
+	}
+	if p.MaxDocuments > 0 {
+		limits = append(limits, fmt.Sprintf("max %d documents", p.MaxDocuments))
+	}
+	if p.DryRun {
+		limits = append(limits, "dry run")
+	}
+	return strings.Join(limits, ", ")
+}
+
+// Evaluate returns the ids of the documents the policy expires, given when each was last updated
+// in Unix seconds by id: those older than MaxAge, and of the rest those beyond the MaxDocuments
+// most recently updated. Documents with an unknown (zero) update time never expire by age and
+// are the first to go over the cap. Both lists are sorted by id.
+func (p *Policy) Evaluate(times map[int64]int64, now time.Time) (expired, excess []int64) {
+	if p == nil {
+		return nil, nil
+	}
+
+	var kept []int64
+	cutoff := now.Add(-p.MaxAge).Unix()
+	for id, updatedAt := range times {
+		if p.MaxAge > 0 && updatedAt != 0 && updatedAt < cutoff {
+			expired = append(expired, id)
+			continue
+		}
+		kept = append(kept, id)
+	}
+
+	if p.MaxDocuments > 0 && len(kept) > p.MaxDocuments {
+		// Newest first, ties keeping the lower id, so Filter and Evaluate agree
+		sort.Slice(kept, func(i, j int) bool {
+			if times[kept[i]] != times[kept[j]] {
+				return times[kept[i]] > times[kept[j]]
+			}
+			return kept[i] < kept[j]
+		})
+		excess = kept[p.MaxDocuments:]
+	}
+
+	sort.Slice(expired, func(i, j int) bool { return expired[i] < expired[j] })
+	sort.Slice(excess, func(i, j int) bool { return excess[i] < excess[j] })
+	return expired, excess
+}
+
+// Filter returns the documents the policy keeps, by their UpdatedAt, so an indexing run does not
+// add back the documents a retention run deletes. A dry-run policy keeps every document.
+func (p *Policy) Filter(documents []*models.Document, now time.Time) (kept []*models.Document, dropped int) {
+	if p == nil || p.DryRun {
+		return documents, 0
+	}
+
+	times := make(map[int64]int64, len(documents))
+	for _, doc := range documents {
+		times[int64(doc.ID)] = doc.UpdatedAt
+	}
+	expired, excess := p.Evaluate(times, now)
+	if len(expired) == 0 && len(excess) == 0 {
+		return documents, 0
+	}
+
+	drop := make(map[int64]bool, len(expired)+len(excess))
+	for _, id := range append(expired, excess...) {
+		drop[id] = true
+	}
+	kept = make([]*models.Document, 0, len(documents)-len(drop))
+	for _, doc := range documents {
+		if !drop[int64(doc.ID)] {
+			kept = append(kept, doc)
+		}
+	}
+	return kept, len(documents) - len(kept)
+}
+
+// Index is the part of the Manticore client a retention run needs
+type Index interface {
+	GetDocumentTimes() (map[int64]int64, error)
+	DeleteDocuments(table string, ids []int64) error
+}
+
+// Outcome is the outcome of a retention run
+type Outcome struct {
+	CheckedAt time.Time
+	DryRun    bool
+	Documents int     // indexed documents evaluated
+	Expired   []int64 // documents older than the maximum age, sorted by id
+	Excess    []int64 // documents beyond the maximum count, sorted by id
+	Deleted   int     // documents deleted from every table
+}
+
+// Apply deletes the indexed documents policy expires from each of tables, or only reports them
+// when dryRun is set
+func Apply(index Index, tables []string, policy *Policy, dryRun bool) (*Outcome, error) {
+	times, err := index.GetDocumentTimes()
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := &Outcome{CheckedAt: time.Now(), DryRun: dryRun, Documents: len(times)}
+	outcome.Expired, outcome.Excess = policy.Evaluate(times, outcome.CheckedAt)
+	ids := append(append([]int64{}, outcome.Expired...), outcome.Excess...)
+	if dryRun || len(ids) == 0 {
+		return outcome, nil
+	}
+
+	for _, table := range tables {
+		if err := index.DeleteDocuments(table, ids); err != nil {
+			return outcome, fmt.Errorf("failed to delete expired documents from %s: %v", table, err)
+		}
+	}
+	outcome.Deleted = len(ids)
+	log.Printf("[RETENTION] Deleted %d expired and %d excess documents", len(outcome.Expired), len(outcome.Excess))
+	return outcome, nil
+}
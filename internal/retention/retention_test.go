@@ -0,0 +1,153 @@
+package retention
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+var now = time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC)
+
+func daysAgo(days int) int64 {
+	return now.Add(-time.Duration(days) * 24 * time.Hour).Unix()
+}
+
+func TestEvaluate(t *testing.T) {
+	times := map[int64]int64{1: daysAgo(40), 2: daysAgo(10), 3: daysAgo(1), 4: daysAgo(1), 5: 0}
+
+	tests := []struct {
+		name            string
+		policy          *Policy
+		expired, excess []int64
+	}{
+		{"no policy", nil, nil, nil},
+		{"max age", &Policy{MaxAge: 30 * 24 * time.Hour}, []int64{1}, nil},
+		{"max documents", &Policy{MaxDocuments: 2}, nil, []int64{1, 2, 5}},
+		{"both", &Policy{MaxAge: 30 * 24 * time.Hour, MaxDocuments: 3}, []int64{1}, []int64{5}},
+		{"tie keeps the lower id", &Policy{MaxDocuments: 1}, nil, []int64{1, 2, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expired, excess := tt.policy.Evaluate(times, now)
+			if !reflect.DeepEqual(expired, tt.expired) || !reflect.DeepEqual(excess, tt.excess) {
+				t.Errorf("Expected %v and %v, got %v and %v", tt.expired, tt.excess, expired, excess)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, UpdatedAt: daysAgo(40)},
+		{ID: 2, UpdatedAt: daysAgo(10)},
+		{ID: 3, UpdatedAt: daysAgo(1)},
+	}
+
+	policy := &Policy{MaxAge: 30 * 24 * time.Hour, MaxDocuments: 1}
+	kept, dropped := policy.Filter(documents, now)
+	if dropped != 2 || len(kept) != 1 || kept[0].ID != 3 {
+		t.Errorf("Expected only document 3 to be kept, got %d dropped", dropped)
+	}
+
+	policy.DryRun = true
+	if kept, dropped := policy.Filter(documents, now); dropped != 0 || len(kept) != 3 {
+		t.Errorf("Expected a dry run to keep every document, got %d dropped", dropped)
+	}
+}
+
+// fakeIndex serves update times and records deletions by table
+type fakeIndex struct {
+	times   map[int64]int64
+	deleted map[string][]int64
+	err     error
+}
+
+func (f *fakeIndex) GetDocumentTimes() (map[int64]int64, error) {
+	return f.times, nil
+}
+
+func (f *fakeIndex) DeleteDocuments(table string, ids []int64) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.deleted[table] = append(f.deleted[table], ids...)
+	return nil
+}
+
+func TestApply(t *testing.T) {
+	index := &fakeIndex{
+		times:   map[int64]int64{1: time.Now().Add(-48 * time.Hour).Unix(), 2: time.Now().Unix(), 3: time.Now().Unix()},
+		deleted: make(map[string][]int64),
+	}
+	policy := &Policy{MaxAge: 24 * time.Hour, MaxDocuments: 1}
+	tables := []string{"documents", "documents_vector"}
+
+	outcome, err := Apply(index, tables, policy, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !outcome.DryRun || outcome.Documents != 3 || !reflect.DeepEqual(outcome.Expired, []int64{1}) || !reflect.DeepEqual(outcome.Excess, []int64{3}) {
+		t.Errorf("Unexpected dry run outcome: %+v", outcome)
+	}
+	if len(index.deleted) != 0 || outcome.Deleted != 0 {
+		t.Error("Expected a dry run to delete nothing")
+	}
+
+	outcome, err = Apply(index, tables, policy, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, table := range tables {
+		if !reflect.DeepEqual(index.deleted[table], []int64{1, 3}) {
+			t.Errorf("Expected documents 1 and 3 to be deleted from %s, got %v", table, index.deleted[table])
+		}
+	}
+	if outcome.Deleted != 2 {
+		t.Errorf("Expected 2 deleted documents, got %d", outcome.Deleted)
+	}
+
+	index.err = fmt.Errorf("connection refused")
+	if _, err := Apply(index, tables, policy, false); err == nil {
+		t.Error("Expected a failed deletion to return an error")
+	}
+}
+
+func TestPolicyFromEnvironment(t *testing.T) {
+	t.Setenv("RETENTION_MAX_AGE_DAYS", "")
+	t.Setenv("RETENTION_MAX_DOCUMENTS", "")
+	t.Setenv("RETENTION_DRY_RUN", "")
+	if policy, err := PolicyFromEnvironment(); policy != nil || err != nil {
+		t.Errorf("Expected no policy, got %v, %v", policy, err)
+	}
+
+	t.Setenv("RETENTION_MAX_AGE_DAYS", "90")
+	t.Setenv("RETENTION_MAX_DOCUMENTS", "1000")
+	t.Setenv("RETENTION_DRY_RUN", "true")
+	policy, err := PolicyFromEnvironment()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if policy.MaxAge != 90*24*time.Hour || policy.MaxDocuments != 1000 || !policy.DryRun {
+		t.Errorf("Unexpected policy: %+v", policy)
+	}
+	if policy.String() != "max age 90 days, max 1000 documents, dry run" {
+		t.Errorf("Unexpected description: %q", policy.String())
+	}
+
+	for name, value := range map[string]string{
+		"RETENTION_MAX_AGE_DAYS":  "-1",
+		"RETENTION_MAX_DOCUMENTS": "many",
+		"RETENTION_DRY_RUN":       "maybe",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, err := PolicyFromEnvironment(); err == nil {
+				t.Errorf("Expected an error for %s=%s", name, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package savedsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Notifier delivers alerts about new saved search results
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// NotifierFunc adapts a function to Notifier
+type NotifierFunc func(ctx context.Context, alert Alert) error
+
+// Notify implements Notifier
+func (f NotifierFunc) Notify(ctx context.Context, alert Alert) error {
+	return f(ctx, alert)
+}
+
+// Notifiers delivers an alert through every notifier, returning the joined errors
+type Notifiers []Notifier
+
+// Notify implements Notifier
+func (n Notifiers) Notify(ctx context.Context, alert Alert) error {
+	var errs []error
+	for _, notifier := range n {
+		if notifier == nil {
+			continue
+		}
+		if err := notifier.Notify(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NewNotifierFromEnvironment creates the webhook and email notifiers. A saved search's own webhook
+// and email take precedence over SAVED_SEARCH_WEBHOOK_URL and SAVED_SEARCH_EMAIL_TO; email is only
+// sent when SAVED_SEARCH_SMTP_ADDR is set.
+func NewNotifierFromEnvironment() Notifiers {
+	return Notifiers{
+		NewWebhookNotifier(os.Getenv("SAVED_SEARCH_WEBHOOK_URL"), 10*time.Second),
+		&EmailNotifier{
+			Addr:      os.Getenv("SAVED_SEARCH_SMTP_ADDR"),
+			From:      os.Getenv("SAVED_SEARCH_SMTP_FROM"),
+			Username:  os.Getenv("SAVED_SEARCH_SMTP_USERNAME"),
+			Password:  os.Getenv("SAVED_SEARCH_SMTP_PASSWORD"),
+			DefaultTo: os.Getenv("SAVED_SEARCH_EMAIL_TO"),
+		},
+	}
+}
+
+// WebhookNotifier posts alerts as JSON, or as a Slack message to Slack incoming webhooks
+type WebhookNotifier struct {
+	DefaultURL string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a webhook notifier; defaultURL may be empty when every saved search sets its own webhook
+func NewWebhookNotifier(defaultURL string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		DefaultURL: defaultURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Notify implements Notifier, doing nothing when no webhook is configured for the saved search
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	target := alert.SavedSearch.Webhook
+	if target == "" {
+		target = n.DefaultURL
+	}
+	if target == "" {
+		return nil
+	}
+
+	var payload interface{} = alert
+	if parsed, err := url.Parse(target); err == nil && strings.HasSuffix(parsed.Hostname(), "hooks.slack.com") {
+		payload = map[string]string{"text": formatAlert(alert)}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// EmailNotifier sends alerts by email through an SMTP server
+type EmailNotifier struct {
+	Addr      string // SMTP server host:port, empty disables email
+	From      string
+	Username  string // Optional PLAIN authentication
+	Password  string
+	DefaultTo string
+}
+
+// Notify implements Notifier, doing nothing when email is not configured for the saved search
+func (n *EmailNotifier) Notify(ctx context.Context, alert Alert) error {
+	to := alert.SavedSearch.Email
+	if to == "" {
+		to = n.DefaultTo
+	}
+	if n.Addr == "" || to == "" {
+		return nil
+	}
+
+	var auth smtp.Auth
+	if n.Username != "" {
+		host := n.Addr
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		auth = smtp.PlainAuth("", n.Username, n.Password, host)
+	}
+
+	if err := smtp.SendMail(n.Addr, auth, n.From, []string{to}, n.message(to, alert)); err != nil {
+		return fmt.Errorf("failed to send alert email to %s: %v", to, err)
+	}
+	return nil
+}
+
+// message builds the email sent for an alert
+func (n *EmailNotifier) message(to string, alert Alert) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.From)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %d new results for saved search %q\r\n", len(alert.Results), alert.SavedSearch.Name)
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(formatAlert(alert), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// formatAlert describes an alert as plain text
+func formatAlert(alert Alert) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Saved search %q (%s search for %q) has %d new results:", alert.SavedSearch.Name, alert.SavedSearch.Mode, alert.SavedSearch.Query, len(alert.Results))
+	for _, result := range alert.Results {
+		title := result.Title
+		if title == "" {
+			title = fmt.Sprintf("Document %d", result.ID)
+		}
+		b.WriteString("\n- " + title)
+		if result.URL != "" {
+			b.WriteString(" " + result.URL)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package savedsearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	var received Alert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode alert: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	alert := Alert{
+		SavedSearch: SavedSearch{ID: 7, Name: "forms", Query: "form", Webhook: server.URL},
+		Results:     []AlertResult{{ID: 3, Title: "Forms"}},
+	}
+	if err := NewWebhookNotifier("", time.Second).Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if received.SavedSearch.ID != 7 || len(received.Results) != 1 || received.Results[0].ID != 3 {
+		t.Errorf("Unexpected alert delivered: %+v", received)
+	}
+
+	// Nothing to deliver to without a webhook
+	alert.SavedSearch.Webhook = ""
+	if err := NewWebhookNotifier("", time.Second).Notify(context.Background(), alert); err != nil {
+		t.Errorf("Expected no error without a webhook, got %v", err)
+	}
+}
+
+func TestWebhookNotifierError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL, time.Second).Notify(context.Background(), Alert{})
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Expected an HTTP 500 error, got %v", err)
+	}
+}
+
+func TestEmailMessage(t *testing.T) {
+	n := &EmailNotifier{From: "search@example.com"}
+	alert := Alert{
+		SavedSearch: SavedSearch{Name: "forms", Query: "form", Mode: "basic"},
+		Results:     []AlertResult{{ID: 3, Title: "Forms", URL: "https://example.com/forms"}, {ID: 4}},
+	}
+
+	message := string(n.message("team@example.com", alert))
+	for _, expected := range []string{
+		"To: team@example.com\r\n",
+		"Subject: 2 new results for saved search \"forms\"\r\n",
+		"- Forms https://example.com/forms\r\n",
+		"- Document 4\r\n",
+	} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("Expected message to contain %q, got:\n%s", expected, message)
+		}
+	}
+
+	// Email is disabled without an SMTP server
+	if err := n.Notify(context.Background(), alert); err != nil {
+		t.Errorf("Expected no error without an SMTP server, got %v", err)
+	}
+}
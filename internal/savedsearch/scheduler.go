@@ -0,0 +1,173 @@
+package savedsearch
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// DefaultInterval is how often saved searches run when SAVED_SEARCH_INTERVAL is not set
+const DefaultInterval = 5 * time.Minute
+
+// Runner executes a saved search and returns its top results, at most MaxResults
+type Runner func(ctx context.Context, search SavedSearch) ([]models.SearchResult, error)
+
+// AlertResult is a new result reported by an Alert
+type AlertResult struct {
+	ID    int     `json:"id"`
+	Title string  `json:"title,omitempty"`
+	URL   string  `json:"url,omitempty"`
+	Score float64 `json:"score"`
+}
+
+// Alert reports the results a saved search returned that its previous run did not
+type Alert struct {
+	SavedSearch SavedSearch   `json:"saved_search"`
+	Results     []AlertResult `json:"results"`
+	Timestamp   time.Time     `json:"timestamp"`
+}
+
+// Scheduler periodically runs every saved search and notifies when new results appear. The first
+// run of a saved search only records its results, so creating one never alerts on existing documents.
+type Scheduler struct {
+	store    *Store
+	run      Runner
+	notifier Notifier
+	interval time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler; call Start to begin running saved searches
+func NewScheduler(store *Store, run Runner, notifier Notifier, interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Scheduler{
+		store:    store,
+		run:      run,
+		notifier: notifier,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// IntervalFromEnvironment reads SAVED_SEARCH_INTERVAL, a duration such as "10m"
+func IntervalFromEnvironment() time.Duration {
+	value := os.Getenv("SAVED_SEARCH_INTERVAL")
+	if value == "" {
+		return DefaultInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		log.Printf("[SAVED_SEARCH] Invalid SAVED_SEARCH_INTERVAL %q, using %v", value, DefaultInterval)
+		return DefaultInterval
+	}
+	return interval
+}
+
+// Start runs saved searches in a background goroutine every interval
+func (s *Scheduler) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.RunOnce(context.Background())
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the background goroutine, waiting for a run in progress to finish
+func (s *Scheduler) Close() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	s.wg.Wait()
+}
+
+// RunOnce runs every saved search once and returns the number of alerts sent
+func (s *Scheduler) RunOnce(ctx context.Context) int {
+	alerts := 0
+	for _, search := range s.store.List() {
+		if s.runSearch(ctx, search) {
+			alerts++
+		}
+	}
+	return alerts
+}
+
+// runSearch runs a single saved search and reports whether an alert was sent
+func (s *Scheduler) runSearch(ctx context.Context, search SavedSearch) bool {
+	runAt := time.Now()
+	results, err := s.run(ctx, search)
+	if err != nil {
+		log.Printf("[SAVED_SEARCH] Saved search %d (%s) failed: %v", search.ID, search.Name, err)
+		if recordErr := s.store.RecordRun(search.ID, runAt, nil, 0, false, err); recordErr != nil {
+			log.Printf("[SAVED_SEARCH] Failed to record run of saved search %d: %v", search.ID, recordErr)
+		}
+		return false
+	}
+
+	seen := make(map[int]bool, len(search.SeenIDs))
+	for _, id := range search.SeenIDs {
+		seen[id] = true
+	}
+
+	ids := make([]int, 0, len(results))
+	var newResults []AlertResult
+	for _, result := range results {
+		if result.Document == nil {
+			continue
+		}
+		ids = append(ids, result.Document.ID)
+		if !seen[result.Document.ID] {
+			newResults = append(newResults, AlertResult{
+				ID:    result.Document.ID,
+				Title: result.Document.Title,
+				URL:   result.Document.URL,
+				Score: result.Score,
+			})
+		}
+	}
+
+	// The first run only establishes which results are already known
+	alerted := false
+	if !search.LastRunAt.IsZero() && len(newResults) > 0 && s.notifier != nil {
+		alert := Alert{SavedSearch: search, Results: newResults, Timestamp: runAt}
+		alert.SavedSearch.SeenIDs = nil
+		if err := s.notifier.Notify(ctx, alert); err != nil {
+			log.Printf("[SAVED_SEARCH] Failed to notify about %d new results of saved search %d: %v", len(newResults), search.ID, err)
+			// Keep the previous results so the next run reports these again
+			if recordErr := s.store.RecordRun(search.ID, runAt, nil, 0, false, fmt.Errorf("notification failed: %v", err)); recordErr != nil {
+				log.Printf("[SAVED_SEARCH] Failed to record run of saved search %d: %v", search.ID, recordErr)
+			}
+			return false
+		}
+		alerted = true
+		log.Printf("[SAVED_SEARCH] Notified about %d new results of saved search %d (%s)", len(newResults), search.ID, search.Name)
+	}
+	if search.LastRunAt.IsZero() {
+		newResults = nil
+	}
+
+	if err := s.store.RecordRun(search.ID, runAt, ids, len(newResults), alerted, nil); err != nil {
+		log.Printf("[SAVED_SEARCH] Failed to record run of saved search %d: %v", search.ID, err)
+	}
+	return alerted
+}
@@ -0,0 +1,101 @@
+package savedsearch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+type recordingNotifier struct {
+	alerts []Alert
+	err    error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.alerts = append(n.alerts, alert)
+	return n.err
+}
+
+func resultsFor(ids ...int) []models.SearchResult {
+	results := make([]models.SearchResult, len(ids))
+	for i, id := range ids {
+		results[i] = models.SearchResult{Document: &models.Document{ID: id, Title: "Doc"}, Score: 1}
+	}
+	return results
+}
+
+func TestSchedulerAlertsOnNewResults(t *testing.T) {
+	store, _ := NewStore("")
+	created, _ := store.Create(SavedSearch{Name: "forms", Query: "form", Mode: "basic"})
+
+	current := resultsFor(1, 2)
+	runner := func(ctx context.Context, search SavedSearch) ([]models.SearchResult, error) {
+		return current, nil
+	}
+	notifier := &recordingNotifier{}
+	scheduler := NewScheduler(store, runner, notifier, 0)
+
+	// The first run only records the existing results
+	if alerts := scheduler.RunOnce(context.Background()); alerts != 0 || len(notifier.alerts) != 0 {
+		t.Fatalf("Expected no alert on the first run, got %d", len(notifier.alerts))
+	}
+
+	// Unchanged results do not alert
+	if alerts := scheduler.RunOnce(context.Background()); alerts != 0 {
+		t.Fatalf("Expected no alert for unchanged results, got %d", alerts)
+	}
+
+	current = resultsFor(3, 1, 2)
+	if alerts := scheduler.RunOnce(context.Background()); alerts != 1 {
+		t.Fatalf("Expected one alert, got %d", alerts)
+	}
+	alert := notifier.alerts[0]
+	if alert.SavedSearch.ID != created.ID || len(alert.Results) != 1 || alert.Results[0].ID != 3 {
+		t.Errorf("Unexpected alert: %+v", alert)
+	}
+
+	search := store.List()[0]
+	if search.LastNew != 1 || search.AlertsTotal != 1 || len(search.SeenIDs) != 3 {
+		t.Errorf("Unexpected run state: %+v", search)
+	}
+}
+
+func TestSchedulerRetriesFailedNotifications(t *testing.T) {
+	store, _ := NewStore("")
+	store.Create(SavedSearch{Name: "forms", Query: "form"})
+
+	current := resultsFor(1)
+	runner := func(ctx context.Context, search SavedSearch) ([]models.SearchResult, error) {
+		return current, nil
+	}
+	notifier := &recordingNotifier{err: errors.New("webhook down")}
+	scheduler := NewScheduler(store, runner, notifier, 0)
+
+	scheduler.RunOnce(context.Background())
+	current = resultsFor(1, 2)
+	scheduler.RunOnce(context.Background())
+	if search := store.List()[0]; search.LastError == "" || len(search.SeenIDs) != 1 {
+		t.Fatalf("Expected the failed notification to keep the previous results, got %+v", search)
+	}
+
+	notifier.err = nil
+	if alerts := scheduler.RunOnce(context.Background()); alerts != 1 || notifier.alerts[1].Results[0].ID != 2 {
+		t.Errorf("Expected the new result to be reported again, got %d alerts: %+v", alerts, notifier.alerts)
+	}
+}
+
+func TestSchedulerRecordsSearchErrors(t *testing.T) {
+	store, _ := NewStore("")
+	store.Create(SavedSearch{Name: "forms", Query: "form"})
+
+	runner := func(ctx context.Context, search SavedSearch) ([]models.SearchResult, error) {
+		return nil, errors.New("manticore unavailable")
+	}
+	NewScheduler(store, runner, &recordingNotifier{}, 0).RunOnce(context.Background())
+
+	if search := store.List()[0]; search.LastError != "manticore unavailable" || !search.LastRunAt.IsZero() {
+		t.Errorf("Expected the error to be recorded without completing a run, got %+v", search)
+	}
+}
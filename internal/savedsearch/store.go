@@ -0,0 +1,205 @@
+package savedsearch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/jsonfile"
+)
+
+// MaxResults is the number of top results a saved search run compares with the previous run
+const MaxResults = 100
+
+// MaxSearches is the number of saved searches a store holds
+const MaxSearches = 100
+
+// ErrTooManySearches is returned by Create when the store already holds MaxSearches saved searches
+var ErrTooManySearches = errors.New("too many saved searches")
+
+// SavedSearch is a stored query whose new results are reported by the Scheduler. Filters are kept
+// as the search endpoint's parameter values and parsed again on every run.
+type SavedSearch struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Query     string    `json:"query"`
+	Mode      string    `json:"mode"`
+	Status    string    `json:"status,omitempty"`    // status parameter, empty for active documents only
+	Tags      string    `json:"tags,omitempty"`      // tags parameter
+	TagsMode  string    `json:"tags_mode,omitempty"` // tags_mode parameter
+	Webhook   string    `json:"webhook,omitempty"`   // Alert webhook URL, empty for the default
+	Email     string    `json:"email,omitempty"`     // Alert recipient, empty for the default
+	CreatedAt time.Time `json:"created_at"`
+
+	// Run state, updated by RecordRun
+	LastRunAt   time.Time `json:"last_run_at"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastNew     int       `json:"last_new"`           // Number of new results found by the last run
+	SeenIDs     []int     `json:"seen_ids,omitempty"` // Document IDs returned by the last successful run
+	AlertsTotal int64     `json:"alerts_total"`
+}
+
+// Store keeps saved searches in memory and, when it has a path, rewrites them to a JSON file after
+// every change so they survive restarts. A nil *Store is valid and holds nothing.
+type Store struct {
+	mutex    sync.RWMutex
+	path     string
+	searches map[int64]*SavedSearch
+	nextID   int64
+}
+
+// NewStore creates a store. When path is empty saved searches are kept in memory only; otherwise
+// existing ones are loaded from the file.
+func NewStore(path string) (*Store, error) {
+	s := &Store{
+		path:     path,
+		searches: make(map[int64]*SavedSearch),
+		nextID:   1,
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved searches %s: %v", path, err)
+	}
+
+	var searches []*SavedSearch
+	if err := json.Unmarshal(data, &searches); err != nil {
+		return nil, fmt.Errorf("failed to parse saved searches %s: %v", path, err)
+	}
+	for _, search := range searches {
+		s.searches[search.ID] = search
+		if search.ID >= s.nextID {
+			s.nextID = search.ID + 1
+		}
+	}
+	return s, nil
+}
+
+// NewStoreFromEnvironment creates a store persisted to SAVED_SEARCHES_FILE
+func NewStoreFromEnvironment() (*Store, error) {
+	return NewStore(os.Getenv("SAVED_SEARCHES_FILE"))
+}
+
+// Create stores a new saved search, assigning its ID and creation time. It fails with
+// ErrTooManySearches when the store is full.
+func (s *Store) Create(search SavedSearch) (SavedSearch, error) {
+	if s == nil {
+		return SavedSearch{}, fmt.Errorf("saved searches are not available")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.searches) >= MaxSearches {
+		return SavedSearch{}, ErrTooManySearches
+	}
+
+	search.ID = s.nextID
+	search.CreatedAt = time.Now()
+	search.LastRunAt = time.Time{}
+	search.SeenIDs = nil
+	s.searches[search.ID] = &search
+
+	if err := s.save(); err != nil {
+		delete(s.searches, search.ID)
+		return SavedSearch{}, err
+	}
+	s.nextID++
+	return search, nil
+}
+
+// List returns every saved search ordered by ID
+func (s *Store) List() []SavedSearch {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]SavedSearch, 0, len(s.searches))
+	for _, search := range s.searches {
+		result = append(result, *search)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// Delete removes a saved search and reports whether it existed
+func (s *Store) Delete(id int64) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	search, ok := s.searches[id]
+	if !ok {
+		return false, nil
+	}
+	delete(s.searches, id)
+	if err := s.save(); err != nil {
+		s.searches[id] = search
+		return false, err
+	}
+	return true, nil
+}
+
+// RecordRun stores the outcome of a run. The seen IDs are only replaced after a successful run, so
+// a failed run does not make the next one report old results as new.
+func (s *Store) RecordRun(id int64, runAt time.Time, seenIDs []int, newResults int, alerted bool, runErr error) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	search, ok := s.searches[id]
+	if !ok {
+		// Deleted while it was running
+		return nil
+	}
+
+	search.LastError = ""
+	if runErr != nil {
+		search.LastError = runErr.Error()
+	} else {
+		search.LastRunAt = runAt
+		search.LastNew = newResults
+		search.SeenIDs = seenIDs
+	}
+	if alerted {
+		search.AlertsTotal++
+	}
+	return s.save()
+}
+
+// save rewrites the file atomically (caller holds the lock)
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	searches := make([]*SavedSearch, 0, len(s.searches))
+	for _, search := range s.searches {
+		searches = append(searches, search)
+	}
+	sort.Slice(searches, func(i, j int) bool { return searches[i].ID < searches[j].ID })
+
+	if err := jsonfile.WriteAtomic(s.path, searches); err != nil {
+		return fmt.Errorf("failed to write saved searches: %v", err)
+	}
+	return nil
+}
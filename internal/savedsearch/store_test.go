@@ -0,0 +1,110 @@
+package savedsearch
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStoreCreateListDelete(t *testing.T) {
+	s, err := NewStore("")
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	first, err := s.Create(SavedSearch{Name: "forms", Query: "form", Mode: "basic"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	second, _ := s.Create(SavedSearch{Name: "go", Query: "golang", Mode: "hybrid", Tags: "go"})
+	if first.ID != 1 || second.ID != 2 || first.CreatedAt.IsZero() {
+		t.Errorf("Unexpected saved searches: %+v, %+v", first, second)
+	}
+
+	if list := s.List(); len(list) != 2 || list[0].ID != 1 || list[1].Tags != "go" {
+		t.Errorf("Unexpected list: %+v", list)
+	}
+
+	if deleted, err := s.Delete(1); !deleted || err != nil {
+		t.Errorf("Expected saved search 1 to be deleted, got %v, %v", deleted, err)
+	}
+	if deleted, _ := s.Delete(1); deleted {
+		t.Error("Expected a second delete to find nothing")
+	}
+	if list := s.List(); len(list) != 1 || list[0].ID != 2 {
+		t.Errorf("Unexpected list after delete: %+v", list)
+	}
+}
+
+func TestStoreLimit(t *testing.T) {
+	s, _ := NewStore("")
+	for i := 0; i < MaxSearches; i++ {
+		if _, err := s.Create(SavedSearch{Query: "form", Mode: "basic"}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	if _, err := s.Create(SavedSearch{Query: "form", Mode: "basic"}); !errors.Is(err, ErrTooManySearches) {
+		t.Fatalf("Expected ErrTooManySearches once full, got %v", err)
+	}
+	s.Delete(1)
+	if _, err := s.Create(SavedSearch{Query: "form", Mode: "basic"}); err != nil {
+		t.Errorf("Expected a deleted saved search to make room, got %v", err)
+	}
+}
+
+func TestStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "saved_searches.json")
+
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	created, _ := s.Create(SavedSearch{Name: "forms", Query: "form", Mode: "basic", Webhook: "http://example.com/hook"})
+	runAt := time.Unix(1700000000, 0)
+	if err := s.RecordRun(created.ID, runAt, []int{3, 1}, 0, false, nil); err != nil {
+		t.Fatalf("RecordRun failed: %v", err)
+	}
+
+	reopened, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	list := reopened.List()
+	if len(list) != 1 {
+		t.Fatalf("Expected 1 saved search, got %d", len(list))
+	}
+	if list[0].Webhook != "http://example.com/hook" || !list[0].LastRunAt.Equal(runAt) || !reflect.DeepEqual(list[0].SeenIDs, []int{3, 1}) {
+		t.Errorf("Unexpected reloaded saved search: %+v", list[0])
+	}
+
+	if next, _ := reopened.Create(SavedSearch{Name: "next"}); next.ID != 2 {
+		t.Errorf("Expected IDs to continue after reload, got %d", next.ID)
+	}
+}
+
+func TestStoreRecordRunFailureKeepsResults(t *testing.T) {
+	s, _ := NewStore("")
+	created, _ := s.Create(SavedSearch{Name: "forms", Query: "form"})
+	runAt := time.Unix(1700000000, 0)
+	s.RecordRun(created.ID, runAt, []int{1, 2}, 0, false, nil)
+
+	s.RecordRun(created.ID, runAt.Add(time.Minute), nil, 0, false, errors.New("unavailable"))
+
+	search := s.List()[0]
+	if search.LastError != "unavailable" || !search.LastRunAt.Equal(runAt) || !reflect.DeepEqual(search.SeenIDs, []int{1, 2}) {
+		t.Errorf("Expected a failed run to keep the previous results, got %+v", search)
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	var s *Store
+	if list := s.List(); list != nil {
+		t.Errorf("Expected no saved searches, got %v", list)
+	}
+	if _, err := s.Create(SavedSearch{}); err == nil {
+		t.Error("Expected Create to fail without a store")
+	}
+}
@@ -0,0 +1,106 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// rrfK dampens the weight of the top ranks in reciprocal rank fusion; 60 is the constant of the
+// original paper and of most search engines
+const rrfK = 60
+
+// aiHybridSearch combines BM25 full-text search with the KNN search of AI embeddings. Both legs
+// contribute candidates of the rescore window, which are fused by their ranks, so the scores of
+// both legs need not be comparable. A failing leg leaves the ranking to the other one, so an AI
+// search failure still returns the keyword matches.
+func (e *SearchEngine) aiHybridSearch(ctx context.Context, query string, page, pageSize int) (*models.SearchResponse, error) {
+	log.Printf("AIHybridSearch: Starting AI hybrid search for query='%s', page=%d, pageSize=%d", query, page, pageSize)
+
+	window := e.candidateWindow(page, pageSize)
+
+	// Boosts and features apply to the fused ranking rather than to either leg
+	legs := *e
+	legs.boosts = nil
+	legs.features = nil
+
+	ftResults, ftErr := legs.fullTextSearch(ctx, query, 1, window)
+	if ftErr != nil {
+		log.Printf("AIHybridSearch: Full-text search failed: %v", ftErr)
+		ftResults = &models.SearchResponse{Documents: []models.SearchResult{}}
+	}
+
+	aiResults, aiErr := legs.aiSearch(ctx, query, 1, window)
+	if aiErr != nil {
+		log.Printf("AIHybridSearch: AI search failed: %v", aiErr)
+		aiResults = &models.SearchResponse{Documents: []models.SearchResult{}}
+	}
+
+	if ftErr != nil && aiErr != nil {
+		return nil, fmt.Errorf("AI hybrid search failed: %w", ftErr)
+	}
+	log.Printf("AIHybridSearch: Fusing %d full-text and %d AI results", len(ftResults.Documents), len(aiResults.Documents))
+
+	combined := fuseRanks(ftResults.Documents, aiResults.Documents)
+	e.applyFeatures(query, combined)
+	e.boosts.Apply(combined)
+	e.recency.Sort.SortResults(combined)
+
+	// The total only counts the merged candidates, so it is a lower bound when either source had more
+	totalResults := len(combined)
+	relation := manticore.TotalRelationEqual
+	if ftResults.Total > len(ftResults.Documents) || aiResults.Total > len(aiResults.Documents) {
+		relation = manticore.TotalRelationAtLeast
+	}
+
+	candidates := make([]*models.Document, len(combined))
+	for i, result := range combined {
+		candidates[i] = result.Document
+	}
+	facets := e.serviceFacets(candidates)
+
+	start, end := pageBounds(len(combined), page, pageSize)
+	combined = combined[start:end]
+
+	log.Printf("AIHybridSearch: Returning %d results (total: %d, window: %d) after pagination", len(combined), totalResults, window)
+
+	return &models.SearchResponse{
+		Documents:     combined,
+		Total:         totalResults,
+		TotalRelation: relation,
+		Page:          page,
+		Mode:          string(models.SearchModeAIHybrid),
+		Pagination:    models.PaginationClient,
+		Facets:        facets,
+	}, nil
+}
+
+// fuseRanks merges ranked result lists by reciprocal rank fusion: every document scores the sum of
+// 1/(rrfK+rank) over the lists it appears in. Equal scores keep the order of first appearance.
+func fuseRanks(lists ...[]models.SearchResult) []models.SearchResult {
+	index := make(map[int]int)
+	fused := []models.SearchResult{}
+	for _, list := range lists {
+		for rank, result := range list {
+			if result.Document == nil {
+				continue
+			}
+			score := 1 / float64(rrfK+rank+1)
+			if i, ok := index[result.Document.ID]; ok {
+				fused[i].Score += score
+				continue
+			}
+			index[result.Document.ID] = len(fused)
+			fused = append(fused, models.SearchResult{Document: result.Document, Score: score})
+		}
+	}
+
+	sort.SliceStable(fused, func(i, j int) bool {
+		return fused[i].Score > fused[j].Score
+	})
+	return fused
+}
@@ -0,0 +1,87 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// keywordMockClient matches two documents in full-text searches
+type keywordMockClient struct {
+	MockClient
+}
+
+func (c *keywordMockClient) SearchWithContext(ctx context.Context, request manticore.SearchRequest) (*manticore.SearchResponse, error) {
+	return &manticore.SearchResponse{Hits: manticore.SearchHits{Total: 2, Hits: []manticore.SearchHit{
+		{ID: 1, Score: 10, Source: map[string]interface{}{"title": "Keyword match"}},
+		{ID: 2, Score: 5, Source: map[string]interface{}{"title": "Both match"}},
+	}}}, nil
+}
+
+func resultIDs(results []models.SearchResult) []int {
+	ids := make([]int, len(results))
+	for i, result := range results {
+		ids[i] = result.Document.ID
+	}
+	return ids
+}
+
+func TestFuseRanks(t *testing.T) {
+	list := func(ids ...int) []models.SearchResult {
+		results := make([]models.SearchResult, len(ids))
+		for i, id := range ids {
+			results[i] = models.SearchResult{Document: &models.Document{ID: id}, Score: float64(100 - i)}
+		}
+		return results
+	}
+
+	// Documents of both lists rank first; equal ranks keep the order of the first list
+	fused := fuseRanks(list(1, 2, 3), list(3, 4))
+	if ids := resultIDs(fused); !reflect.DeepEqual(ids, []int{3, 1, 2, 4}) {
+		t.Errorf("Expected fused order [3 1 2 4], got %v", ids)
+	}
+	if expected := 1.0/63 + 1.0/61; fused[0].Score != expected {
+		t.Errorf("Expected score %v, got %v", expected, fused[0].Score)
+	}
+
+	if fused := fuseRanks(nil, nil); fused == nil || len(fused) != 0 {
+		t.Errorf("Expected empty results, got %v", fused)
+	}
+}
+
+func TestAIHybridSearch(t *testing.T) {
+	client := &keywordMockClient{MockClient{aiSearchResponse: &manticore.SearchResponse{Hits: manticore.SearchHits{Total: 2, Hits: []manticore.SearchHit{
+		{ID: 2, Score: 0.9, Source: map[string]interface{}{"title": "Both match"}},
+		{ID: 3, Score: 0.8, Source: map[string]interface{}{"title": "Semantic match"}},
+	}}}}}
+	engine := NewSearchEngine(client, nil, &models.AISearchConfig{Model: "sentence-transformers/all-MiniLM-L6-v2", Enabled: true})
+
+	response, err := engine.SearchContext(context.Background(), "match", models.SearchModeAIHybrid, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ids := resultIDs(response.Documents); !reflect.DeepEqual(ids, []int{2, 1, 3}) {
+		t.Errorf("Expected fused order [2 1 3], got %v", ids)
+	}
+	if response.Mode != string(models.SearchModeAIHybrid) || response.Total != 3 {
+		t.Errorf("Expected 3 ai-hybrid results, got mode %q with total %d", response.Mode, response.Total)
+	}
+}
+
+func TestAIHybridSearch_AIFailure(t *testing.T) {
+	client := &keywordMockClient{MockClient{aiSearchError: errors.New("embedding model unavailable")}}
+	engine := NewSearchEngine(client, nil, &models.AISearchConfig{Model: "sentence-transformers/all-MiniLM-L6-v2", Enabled: true})
+
+	// The keyword matches are still returned
+	response, err := engine.SearchContext(context.Background(), "match", models.SearchModeAIHybrid, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ids := resultIDs(response.Documents); !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("Expected the full-text order [1 2], got %v", ids)
+	}
+}
@@ -0,0 +1,39 @@
+package search
+
+import (
+	"github.com/ad/manticoresearch-go/internal/blocklist"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// WithBlocklist returns a copy of the engine that removes the documents of b from the results
+// of every mode, after ranking and curation, so no caller has to filter them itself
+func (e *SearchEngine) WithBlocklist(b *blocklist.Blocklist) *SearchEngine {
+	engine := *e
+	engine.blocklist = b
+	if len(e.fields) > 0 {
+		engine.searchAdapter = e.searchAdapter.WithSource(sourceFilter(e.fields, engine.requiredFields()...))
+	}
+	return &engine
+}
+
+// removeBlocked removes the blocked documents from a page of results. They are taken off the
+// total too, which only counts those of the page, so later pages may still have been counted.
+func (e *SearchEngine) removeBlocked(response *models.SearchResponse) {
+	var blocked int
+	response.Documents, blocked = e.blocklist.Filter(response.Documents)
+	response.Total = max(response.Total-blocked, 0)
+}
+
+// requiredFields returns the stored fields the boosts, the features, the blocklist, the histogram
+// facets and the sort order read, which searches returning selected fields still have to fetch
+func (e *SearchEngine) requiredFields() []string {
+	fields := e.boosts.SourceFields()
+	others := append(e.featureFields(), e.blocklist.SourceFields()...)
+	others = append(others, e.histogramFields()...)
+	for _, field := range append(others, e.recency.Sort.Fields()...) {
+		if !hasField(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,49 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/blocklist"
+	"github.com/ad/manticoresearch-go/internal/curation"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+func TestSearchBlocklist(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, Title: "Guide", URL: "https://wiki.example.com/drafts/guide", Content: "guide guide guide", Status: models.DocumentStatusActive},
+		{ID: 2, Title: "Notes", URL: "https://wiki.example.com/notes", Content: "guide guide notes", Status: models.DocumentStatusActive},
+		{ID: 3, Title: "Handbook", URL: "https://wiki.example.com/handbook", Content: "guide handbook chapter one", Status: models.DocumentStatusActive},
+		{ID: 4, Title: "Pricing", URL: "https://wiki.example.com/pricing", Content: "prices", Status: models.DocumentStatusActive},
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	client := &documentMockClient{vectorMockClient{documents: documents, vectors: vec.FitTransform(documents)}}
+
+	blocked, _ := blocklist.Parse("3", "https://wiki.example.com/drafts/")
+	// A curation can't pin a blocked document back into the results
+	store, _ := curation.NewStore("")
+	store.Put(curation.Curation{Name: "guide", Pattern: "guide", Pinned: []int{3}})
+	engine := NewSearchEngine(client, vec, nil).WithFields([]string{FieldTitle}).WithBlocklist(blocked).WithCurations(store)
+
+	for _, mode := range []models.SearchMode{models.SearchModeVector, models.SearchModeHybrid} {
+		response, err := engine.Search("guide", mode, 1, 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		// Every document is listed, but only the matching unblocked one is counted
+		for _, result := range response.Documents {
+			if id := result.Document.ID; id == 1 || id == 3 {
+				t.Errorf("Mode %s: unexpected blocked document %d", mode, id)
+			}
+		}
+		if response.Documents[0].Document.ID != 2 || response.Total != 1 {
+			t.Errorf("Mode %s: expected document 2 first of 1, got %d of %d", mode, response.Documents[0].Document.ID, response.Total)
+		}
+	}
+
+	// URL prefixes are matched on documents fetched with selected fields
+	filter := sourceFilter(engine.fields, engine.requiredFields()...)
+	if !hasField(filter.Includes, FieldURL) {
+		t.Errorf("Expected the url to be fetched, got %v", filter.Includes)
+	}
+}
@@ -0,0 +1,30 @@
+package search
+
+import (
+	"github.com/ad/manticoresearch-go/internal/boost"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// WithBoosts returns a copy of the engine that multiplies the scores of the documents matching
+// rules in every mode. Vector searches rank every document in memory and hybrid searches the
+// candidates of the rescore window, so boosts reorder their whole ranking; basic, full-text and
+// AI searches are paginated by Manticore, so boosts only reorder the results within each page.
+func (e *SearchEngine) WithBoosts(rules boost.Rules) *SearchEngine {
+	engine := *e
+	engine.boosts = rules
+	if len(e.fields) > 0 {
+		engine.searchAdapter = e.searchAdapter.WithSource(sourceFilter(e.fields, engine.requiredFields()...))
+	}
+	return &engine
+}
+
+// boostPage applies the features and boosts to a page of results ranked by Manticore, keeping it
+// in the requested order
+func (e *SearchEngine) boostPage(response *models.SearchResponse, query string) {
+	if response == nil || (len(e.boosts) == 0 && len(e.features) == 0) {
+		return
+	}
+	e.applyFeatures(query, response.Documents)
+	e.boosts.Apply(response.Documents)
+	e.recency.Sort.SortResults(response.Documents)
+}
@@ -0,0 +1,64 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/boost"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+func TestSearchBoosts(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, Title: "Guide", Content: "guide guide guide", Tags: []string{"deprecated"}},
+		{ID: 2, Title: "Notes", Content: "guide guide notes"},
+		{ID: 3, Title: "Handbook", Content: "guide handbook chapter one", Tags: []string{"handbook"}},
+		{ID: 4, Title: "Pricing", Content: "prices"},
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	engine := NewSearchEngine(&vectorMockClient{documents: documents, vectors: vec.FitTransform(documents)}, vec, nil)
+
+	response, err := engine.VectorSearch("guide", 1, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Documents[0].Document.ID == 3 {
+		t.Fatal("Expected the handbook document not to rank first without boosts")
+	}
+
+	rules, err := boost.Parse("tag=handbook:10,tag=deprecated:0.1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Hybrid boosts reorder the candidates of the rescore window
+	boosted := engine.WithBoosts(rules).WithRescoreWindow(10)
+
+	// Vector and hybrid searches boost the whole ranking, so the first page changes. Document 4 does
+	// not match, so the deprecated document is third.
+	for _, mode := range []models.SearchMode{models.SearchModeVector, models.SearchModeHybrid} {
+		response, err := boosted.Search("guide", mode, 1, 1)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.Documents[0].Document.ID != 3 {
+			t.Errorf("Mode %s: expected the handbook document first, got %d", mode, response.Documents[0].Document.ID)
+		}
+		last, err := boosted.Search("guide", mode, 3, 1)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if last.Documents[0].Document.ID != 1 {
+			t.Errorf("Mode %s: expected the deprecated document last, got %d", mode, last.Documents[0].Document.ID)
+		}
+	}
+}
+
+func TestBoostsSourceFields(t *testing.T) {
+	rules, _ := boost.Parse("tag=handbook:2,status=archived:0.5")
+	engine := NewSearchEngine(&MockClient{}, nil, nil).WithFields([]string{FieldTitle}).WithBoosts(rules)
+
+	filter := sourceFilter(engine.fields, engine.boosts.SourceFields()...)
+	if len(filter.Includes) != 3 || filter.Includes[0] != FieldTitle || filter.Includes[1] != "tags" || filter.Includes[2] != "status" {
+		t.Errorf("Expected the boosted attributes to be fetched, got %v", filter.Includes)
+	}
+}
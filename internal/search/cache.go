@@ -0,0 +1,156 @@
+package search
+
+import (
+	"container/list"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// ResultCache keeps recent successful search responses so they can be served while the backend recovers.
+// It is a fixed-size LRU with a maximum entry age. A nil *ResultCache is valid and caches nothing.
+type ResultCache struct {
+	mutex   sync.Mutex
+	maxSize int
+	ttl     time.Duration
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type resultCacheEntry struct {
+	key      string
+	response models.SearchResponse
+	storedAt time.Time
+}
+
+// NewResultCache creates a cache holding up to maxSize responses for at most ttl
+func NewResultCache(maxSize int, ttl time.Duration) *ResultCache {
+	return &ResultCache{
+		maxSize: maxSize,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// NewResultCacheFromEnvironment creates a cache configured by SEARCH_STALE_CACHE_SIZE and SEARCH_STALE_CACHE_TTL.
+// It returns nil when SEARCH_STALE_CACHE_SIZE is unset or zero.
+func NewResultCacheFromEnvironment() (*ResultCache, error) {
+	sizeStr := os.Getenv("SEARCH_STALE_CACHE_SIZE")
+	if sizeStr == "" {
+		return nil, nil
+	}
+	size, err := strconv.Atoi(sizeStr)
+	if err != nil || size < 0 {
+		return nil, fmt.Errorf("invalid SEARCH_STALE_CACHE_SIZE: %q", sizeStr)
+	}
+	if size == 0 {
+		return nil, nil
+	}
+
+	ttl := 10 * time.Minute
+	if ttlStr := os.Getenv("SEARCH_STALE_CACHE_TTL"); ttlStr != "" {
+		ttl, err = time.ParseDuration(ttlStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SEARCH_STALE_CACHE_TTL: %w", err)
+		}
+	}
+
+	return NewResultCache(size, ttl), nil
+}
+
+// ResultCacheKey builds the cache key for a search request
+func ResultCacheKey(query string, mode models.SearchMode, page, pageSize int) string {
+	return fmt.Sprintf("%s|%d|%d|%s", mode, page, pageSize, query)
+}
+
+// Put stores a copy of the response
+func (c *ResultCache) Put(key string, response *models.SearchResponse) {
+	if c == nil || response == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry := resultCacheEntry{
+		key:      key,
+		response: copySearchResponse(response),
+		storedAt: time.Now(),
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(resultCacheEntry).key)
+	}
+}
+
+// Get returns a copy of the cached response and its age, if present and not expired
+func (c *ResultCache) Get(key string) (*models.SearchResponse, time.Duration, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, 0, false
+	}
+
+	entry := element.Value.(resultCacheEntry)
+	age := time.Since(entry.storedAt)
+	if c.ttl > 0 && age > c.ttl {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, 0, false
+	}
+
+	c.order.MoveToFront(element)
+	response := copySearchResponse(&entry.response)
+	return &response, age, true
+}
+
+// EvictExpired removes the entries older than the TTL, returning how many were removed. Get skips
+// them anyway; evicting them frees their memory without waiting for newer entries to push them out.
+func (c *ResultCache) EvictExpired() int {
+	if c == nil || c.ttl <= 0 {
+		return 0
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	evicted := 0
+	for element := c.order.Back(); element != nil; {
+		previous := element.Prev()
+		entry := element.Value.(resultCacheEntry)
+		if time.Since(entry.storedAt) > c.ttl {
+			c.order.Remove(element)
+			delete(c.entries, entry.key)
+			evicted++
+		}
+		element = previous
+	}
+	return evicted
+}
+
+// copySearchResponse copies the response so cached entries are not mutated by later metadata updates
+func copySearchResponse(response *models.SearchResponse) models.SearchResponse {
+	clone := *response
+	clone.Documents = append([]models.SearchResult(nil), response.Documents...)
+	return clone
+}
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestResultCache(t *testing.T) {
+	cache := NewResultCache(2, time.Minute)
+
+	response := &models.SearchResponse{
+		Documents: []models.SearchResult{{Score: 1}},
+		Total:     1,
+		Mode:      "basic",
+	}
+	cache.Put("a", response)
+	cache.Put("b", response)
+
+	// Mutating the original must not affect the cached copy
+	response.Documents[0].Score = 5
+	response.Mode = "changed"
+
+	cached, _, ok := cache.Get("a")
+	if !ok {
+		t.Fatal("Expected cache hit for a")
+	}
+	if cached.Mode != "basic" || cached.Documents[0].Score != 1 {
+		t.Errorf("Cached response was mutated: %+v", cached)
+	}
+
+	// "a" was used most recently, so adding "c" evicts "b"
+	cache.Put("c", response)
+	if _, _, ok := cache.Get("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	if _, _, ok := cache.Get("a"); !ok {
+		t.Error("Expected a to remain cached")
+	}
+}
+
+func TestResultCacheExpiry(t *testing.T) {
+	cache := NewResultCache(10, time.Millisecond)
+	cache.Put("a", &models.SearchResponse{})
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, _, ok := cache.Get("a"); ok {
+		t.Error("Expected expired entry to be dropped")
+	}
+}
+
+func TestResultCacheEvictExpired(t *testing.T) {
+	cache := NewResultCache(10, 20*time.Millisecond)
+	cache.Put("old", &models.SearchResponse{})
+	time.Sleep(30 * time.Millisecond)
+	cache.Put("new", &models.SearchResponse{})
+
+	if evicted := cache.EvictExpired(); evicted != 1 {
+		t.Errorf("Expected 1 expired entry to be evicted, got %d", evicted)
+	}
+	if _, _, ok := cache.Get("new"); !ok {
+		t.Error("Expected the fresh entry to be kept")
+	}
+
+	var nilCache *ResultCache
+	if nilCache.EvictExpired() != 0 {
+		t.Error("Expected a nil cache to evict nothing")
+	}
+}
+
+func TestNilResultCache(t *testing.T) {
+	var cache *ResultCache
+	cache.Put("a", &models.SearchResponse{})
+	if _, _, ok := cache.Get("a"); ok {
+		t.Error("Expected nil cache to miss")
+	}
+}
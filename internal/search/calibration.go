@@ -0,0 +1,26 @@
+package search
+
+import (
+	"github.com/ad/manticoresearch-go/internal/calibration"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// WithCalibration returns a copy of the engine that maps the scores of each mode with c, so they
+// are comparable across modes and pages. Hybrid searches fuse the calibrated scores of their legs.
+func (e *SearchEngine) WithCalibration(c calibration.Calibration) *SearchEngine {
+	engine := *e
+	engine.calibration = c
+	return &engine
+}
+
+// normalizeLeg returns the scores of a hybrid leg found in mode mapped to 0-1: by the calibration
+// of mode when there is one, so a score means the same on every page, otherwise relative to the
+// best result of the leg
+func (e *SearchEngine) normalizeLeg(mode models.SearchMode, results []models.SearchResult) []models.SearchResult {
+	normalized := append([]models.SearchResult(nil), results...)
+	if !e.calibration.Has(mode) {
+		return normalizeScores(normalized)
+	}
+	e.calibration.Apply(mode, normalized)
+	return normalized
+}
@@ -0,0 +1,40 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/calibration"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestCalibration(t *testing.T) {
+	c := calibration.Calibration{models.SearchModeFullText: calibration.Percentiles{0, 20}}
+	engine := NewSearchEngine(&keywordMockClient{}, nil, nil).WithCalibration(c)
+
+	response, err := engine.SearchContext(context.Background(), "match", models.SearchModeFullText, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Documents) != 2 || response.Documents[0].Score != 0.5 || response.Documents[1].Score != 0.25 {
+		t.Errorf("Expected the calibrated scores 0.5 and 0.25, got %v", response.Documents)
+	}
+}
+
+func TestNormalizeLeg(t *testing.T) {
+	results := []models.SearchResult{{Score: 10}, {Score: 5}}
+
+	// Without a calibration the best result of the leg scores 1, whatever its raw score
+	engine := NewSearchEngine(&MockClient{}, nil, nil)
+	if normalized := engine.normalizeLeg(models.SearchModeFullText, results); normalized[0].Score != 1 || normalized[1].Score != 0.5 {
+		t.Errorf("Expected scores relative to the best result, got %v", normalized)
+	}
+
+	engine = engine.WithCalibration(calibration.Calibration{models.SearchModeFullText: calibration.Percentiles{0, 20}})
+	if normalized := engine.normalizeLeg(models.SearchModeFullText, results); normalized[0].Score != 0.5 || normalized[1].Score != 0.25 {
+		t.Errorf("Expected calibrated scores, got %v", normalized)
+	}
+	if results[0].Score != 10 {
+		t.Errorf("Expected the leg results to be unchanged, got %v", results[0].Score)
+	}
+}
@@ -0,0 +1,74 @@
+package search
+
+import (
+	"log"
+
+	"github.com/ad/manticoresearch-go/internal/curation"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// WithCurations returns a copy of the engine that applies the curation of store matching each
+// query after ranking: its pinned documents lead the first page, in order, and its pinned and
+// hidden documents are removed from every page. Total still counts the ranking before curation.
+func (e *SearchEngine) WithCurations(store *curation.Store) *SearchEngine {
+	engine := *e
+	engine.curations = store
+	return &engine
+}
+
+// curate applies the curation matching query, as typed, to a page of ranked results. Pinned
+// documents that were not ranked are fetched, and skipped when they don't exist or don't match
+// the search's filters, so a first page may hold up to the number of pinned documents more than
+// the page size.
+func (e *SearchEngine) curate(query string, response *models.SearchResponse, page int) {
+	c, ok := e.curations.Match(query)
+	if !ok {
+		return
+	}
+
+	ranked := make(map[int]models.SearchResult)
+	kept := make([]models.SearchResult, 0, len(response.Documents))
+	for _, result := range response.Documents {
+		if result.Document != nil && c.Hides(result.Document.ID) {
+			ranked[result.Document.ID] = result
+			continue
+		}
+		kept = append(kept, result)
+	}
+
+	var pinned []models.SearchResult
+	if page <= 1 {
+		for _, id := range c.Pinned {
+			result, ok := ranked[id]
+			if !ok {
+				doc := e.pinnedDocument(id)
+				if doc == nil {
+					continue
+				}
+				result = models.SearchResult{Document: doc}
+			}
+			result.Pinned = true
+			pinned = append(pinned, result)
+		}
+	}
+
+	response.Documents = append(pinned, kept...)
+	response.Curation = c.Name
+}
+
+// pinnedDocument fetches a pinned document that was not ranked, or returns nil when it doesn't
+// exist or the search's filters exclude it
+func (e *SearchEngine) pinnedDocument(id int) *models.Document {
+	if e.client == nil {
+		return nil
+	}
+	doc, err := e.client.GetDocument(int64(id))
+	if err != nil || doc == nil {
+		log.Printf("Curation: Skipping pinned document %d: %v", id, err)
+		return nil
+	}
+	if !hasStatus(e.searchStatuses(), doc.Status) || !e.recency.Matches(doc) || !e.tags.Matches(doc) || !e.ids.Matches(doc) || !e.regex.Matches(doc) {
+		return nil
+	}
+	return doc
+}
@@ -0,0 +1,83 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/curation"
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+// documentMockClient also serves its documents by id
+type documentMockClient struct {
+	vectorMockClient
+}
+
+func (c *documentMockClient) GetDocument(id int64) (*models.Document, error) {
+	for _, doc := range c.documents {
+		if int64(doc.ID) == id {
+			return doc, nil
+		}
+	}
+	return nil, manticore.ErrDocumentNotFound
+}
+
+func TestSearchCurations(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, Title: "Guide", Content: "guide guide guide", Status: models.DocumentStatusActive},
+		{ID: 2, Title: "Notes", Content: "guide guide notes", Status: models.DocumentStatusActive},
+		{ID: 3, Title: "Handbook", Content: "guide handbook chapter one", Status: models.DocumentStatusActive},
+		{ID: 4, Title: "Pricing", Content: "prices", Status: models.DocumentStatusActive},
+		{ID: 5, Title: "Old pricing", Content: "prices", Status: models.DocumentStatusArchived},
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	client := &documentMockClient{vectorMockClient{documents: documents, vectors: vec.FitTransform(documents)}}
+
+	store, _ := curation.NewStore("")
+	if _, err := store.Put(curation.Curation{Name: "guide", Pattern: "Guide", Pinned: []int{4, 3, 5, 9}, Hidden: []int{1}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	engine := NewSearchEngine(client, vec, nil).WithCurations(store)
+
+	response, err := engine.Search("  GUIDE ", models.SearchModeVector, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The archived and missing pinned documents are skipped, the hidden one removed
+	var ids []int
+	for _, result := range response.Documents {
+		ids = append(ids, result.Document.ID)
+	}
+	if len(ids) != 3 || ids[0] != 4 || ids[1] != 3 || ids[2] != 2 {
+		t.Fatalf("Expected documents 4, 3 and 2, got %v", ids)
+	}
+	if !response.Documents[0].Pinned || !response.Documents[1].Pinned || response.Documents[2].Pinned {
+		t.Errorf("Expected only the pinned documents to be marked: %+v", response.Documents)
+	}
+	if response.Documents[1].Score == 0 {
+		t.Error("Expected a ranked pinned document to keep its score")
+	}
+	if response.Curation != "guide" {
+		t.Errorf("Expected the curation to be named, got %q", response.Curation)
+	}
+
+	// Later pages only drop the curated documents
+	second, err := engine.Search("guide", models.SearchModeVector, 2, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, result := range second.Documents {
+		if result.Pinned || result.Document.ID == 1 || result.Document.ID == 3 {
+			t.Errorf("Unexpected curated document on page 2: %+v", result)
+		}
+	}
+
+	other, err := engine.Search("guide notes", models.SearchModeVector, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if other.Curation != "" || other.Documents[0].Pinned {
+		t.Error("Expected an exact curation not to apply to another query")
+	}
+}
@@ -0,0 +1,126 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/validation"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+// DefaultDedupThreshold is the cosine similarity of stored vectors above which results are
+// collapsed as near duplicates, such as mirrored pages
+const DefaultDedupThreshold = 0.95
+
+// DedupThresholdFromEnvironment reads SEARCH_DEDUP_THRESHOLD, the similarity above which the dedup
+// parameter collapses results, between 0 and 1. It returns DefaultDedupThreshold when unset.
+func DedupThresholdFromEnvironment() (float64, error) {
+	value := os.Getenv("SEARCH_DEDUP_THRESHOLD")
+	if value == "" {
+		return DefaultDedupThreshold, nil
+	}
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		return DefaultDedupThreshold, fmt.Errorf("invalid SEARCH_DEDUP_THRESHOLD: %q (use a number above 0 and at most 1)", value)
+	}
+	return threshold, nil
+}
+
+// ParseDedup parses the dedup parameter, whether near-duplicate results are collapsed. It is off
+// when empty.
+func ParseDedup(param string) (bool, error) {
+	param = strings.TrimSpace(param)
+	if param == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(param)
+	if err != nil {
+		return false, validation.InvalidValue("dedup", param, "true", "false")
+	}
+	return enabled, nil
+}
+
+// WithDeduplication returns a copy of the engine that collapses the results of a page whose stored
+// vectors have a cosine similarity above threshold, keeping the highest-scored one. A threshold of
+// 0 disables it.
+func (e *SearchEngine) WithDeduplication(threshold float64) *SearchEngine {
+	engine := *e
+	engine.dedupThreshold = threshold
+	return &engine
+}
+
+// collapseDuplicates removes the near duplicates from a page of results. Pinned results are kept
+// and compared first; the others are compared from the highest score down, so each group keeps
+// its highest-scored result at its position. Removed results are taken off the total like
+// blocked ones. Without stored vectors results are kept as they are.
+func (e *SearchEngine) collapseDuplicates(ctx context.Context, response *models.SearchResponse) {
+	if e.dedupThreshold <= 0 || len(response.Documents) < 2 {
+		return
+	}
+
+	ids := make([]int64, 0, len(response.Documents))
+	for _, result := range response.Documents {
+		if result.Document != nil {
+			ids = append(ids, int64(result.Document.ID))
+		}
+	}
+	vectors, err := e.searchAdapter.DocumentVectors(ctx, ids)
+	if err != nil {
+		log.Printf("Dedup: Keeping results without vectors: %v", err)
+		return
+	}
+
+	order := make([]int, len(response.Documents))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ra, rb := response.Documents[order[a]], response.Documents[order[b]]
+		if ra.Pinned != rb.Pinned {
+			return ra.Pinned
+		}
+		return ra.Score > rb.Score
+	})
+
+	removed := make([]bool, len(response.Documents))
+	var representatives [][]float64
+	for _, i := range order {
+		result := response.Documents[i]
+		if result.Document == nil {
+			continue
+		}
+		vector := vectors[int64(result.Document.ID)]
+		if len(vector) == 0 {
+			continue
+		}
+		duplicate := false
+		for _, kept := range representatives {
+			if vectorizer.CosineSimilarity(vector, kept) > e.dedupThreshold {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate && !result.Pinned {
+			removed[i] = true
+			continue
+		}
+		representatives = append(representatives, vector)
+	}
+
+	kept := make([]models.SearchResult, 0, len(response.Documents))
+	for i, result := range response.Documents {
+		if !removed[i] {
+			kept = append(kept, result)
+		}
+	}
+	collapsed := len(response.Documents) - len(kept)
+	response.Documents = kept
+	response.Total = max(response.Total-collapsed, 0)
+	response.Collapsed = collapsed
+}
@@ -0,0 +1,98 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+// dedupMockClient serves stored vectors by id
+type dedupMockClient struct {
+	MockClient
+	vectors map[int64][]float64
+}
+
+func (c *dedupMockClient) SearchWithContext(ctx context.Context, request manticore.SearchRequest) (*manticore.SearchResponse, error) {
+	var hits []manticore.SearchHit
+	for id, vector := range c.vectors {
+		data, _ := json.Marshal(vector)
+		hits = append(hits, manticore.SearchHit{ID: id, Source: map[string]interface{}{"vector_data": string(data)}})
+	}
+	return &manticore.SearchResponse{Hits: manticore.SearchHits{Total: int32(len(hits)), Hits: hits}}, nil
+}
+
+func TestParseDedup(t *testing.T) {
+	for param, expected := range map[string]bool{"": false, "true": true, " 1 ": true, "false": false} {
+		enabled, err := ParseDedup(param)
+		if err != nil || enabled != expected {
+			t.Errorf("ParseDedup(%q) = %v, %v, expected %v", param, enabled, err, expected)
+		}
+	}
+	if _, err := ParseDedup("maybe"); err == nil {
+		t.Error("Expected an error for an invalid value")
+	}
+}
+
+func TestDedupThresholdFromEnvironment(t *testing.T) {
+	t.Setenv("SEARCH_DEDUP_THRESHOLD", "")
+	if threshold, err := DedupThresholdFromEnvironment(); err != nil || threshold != DefaultDedupThreshold {
+		t.Errorf("Expected the default threshold, got %v, %v", threshold, err)
+	}
+	t.Setenv("SEARCH_DEDUP_THRESHOLD", "0.9")
+	if threshold, err := DedupThresholdFromEnvironment(); err != nil || threshold != 0.9 {
+		t.Errorf("Expected 0.9, got %v, %v", threshold, err)
+	}
+	for _, value := range []string{"0", "1.5", "high"} {
+		t.Setenv("SEARCH_DEDUP_THRESHOLD", value)
+		if _, err := DedupThresholdFromEnvironment(); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestCollapseDuplicates(t *testing.T) {
+	client := &dedupMockClient{vectors: map[int64][]float64{
+		1: {1, 0, 0},
+		2: {0.99, 0.01, 0}, // Mirror of 1
+		3: {0, 1, 0},
+		4: {1, 0.01, 0}, // Pinned mirror of 1
+		5: {0, 0, 1},
+	}}
+	engine := NewSearchEngine(client, vectorizer.NewTFIDFVectorizer(), nil).WithDeduplication(DefaultDedupThreshold)
+
+	response := &models.SearchResponse{Total: 12, Documents: []models.SearchResult{
+		{Document: &models.Document{ID: 4}, Score: 0.1, Pinned: true},
+		{Document: &models.Document{ID: 2}, Score: 0.8},
+		{Document: &models.Document{ID: 1}, Score: 0.9},
+		{Document: &models.Document{ID: 3}, Score: 0.7},
+		{Document: &models.Document{ID: 6}, Score: 0.6}, // No stored vector
+		{Document: &models.Document{ID: 5}, Score: 0.5},
+	}}
+	engine.collapseDuplicates(context.Background(), response)
+
+	// The pinned document is kept and both mirrors of it are collapsed
+	var ids []int
+	for _, result := range response.Documents {
+		ids = append(ids, result.Document.ID)
+	}
+	if len(ids) != 4 || ids[0] != 4 || ids[1] != 3 || ids[2] != 6 || ids[3] != 5 {
+		t.Fatalf("Expected documents 4, 3, 6 and 5, got %v", ids)
+	}
+	if response.Collapsed != 2 || response.Total != 10 {
+		t.Errorf("Expected 2 collapsed of 10, got %d of %d", response.Collapsed, response.Total)
+	}
+
+	// Without a threshold nothing is collapsed
+	response = &models.SearchResponse{Total: 2, Documents: []models.SearchResult{
+		{Document: &models.Document{ID: 1}, Score: 0.9},
+		{Document: &models.Document{ID: 2}, Score: 0.8},
+	}}
+	engine.WithDeduplication(0).collapseDuplicates(context.Background(), response)
+	if len(response.Documents) != 2 || response.Collapsed != 0 {
+		t.Errorf("Expected no collapsed documents, got %+v", response)
+	}
+}
@@ -1,13 +1,22 @@
 package search
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sort"
 	"time"
 
+	"github.com/ad/manticoresearch-go/internal/blocklist"
+	"github.com/ad/manticoresearch-go/internal/boost"
+	"github.com/ad/manticoresearch-go/internal/calibration"
+	"github.com/ad/manticoresearch-go/internal/curation"
 	"github.com/ad/manticoresearch-go/internal/manticore"
 	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/stopwords"
+	"github.com/ad/manticoresearch-go/internal/textnorm"
+	"github.com/ad/manticoresearch-go/internal/tokens"
+	"github.com/ad/manticoresearch-go/internal/validation"
 	"github.com/ad/manticoresearch-go/internal/vectorizer"
 )
 
@@ -24,61 +33,210 @@ func ValidateSearchMode(modeStr string) (models.SearchMode, error) {
 		return models.SearchModeHybrid, nil
 	case "ai":
 		return models.SearchModeAI, nil
+	case "ai-hybrid":
+		return models.SearchModeAIHybrid, nil
+	case "auto":
+		return models.SearchModeAuto, nil
 	default:
-		return "", fmt.Errorf("invalid search mode: %s. Valid modes are: basic, fulltext, vector, hybrid, ai", modeStr)
+		return "", validation.InvalidValue("mode", modeStr, "basic", "fulltext", "vector", "hybrid", "ai", "ai-hybrid", "auto")
 	}
 }
 
 // SearchEngine handles all search operations using the Manticore client interface
 type SearchEngine struct {
-	client        manticore.ClientInterface
-	searchAdapter *manticore.SearchAdapter
-	vectorizer    *vectorizer.TFIDFVectorizer
-	aiConfig      *models.AISearchConfig
+	client         manticore.ClientInterface
+	searchAdapter  *manticore.SearchAdapter
+	vectorizer     *vectorizer.TFIDFVectorizer
+	aiConfig       *models.AISearchConfig
+	timeouts       manticore.OperationTimeouts
+	fields         []string                // Result fields to return, empty for full documents
+	statuses       []models.DocumentStatus // Document statuses to match, empty for manticore.DefaultSearchStatuses
+	recency        manticore.RecencyOptions
+	tags           manticore.TagFilter
+	ids            manticore.IDFilter
+	regex          manticore.RegexFilter
+	histograms     manticore.HistogramFacets
+	knn            manticore.KNNOptions // Overrides of the configured KNN tuning of AI searches
+	dedupThreshold float64              // Similarity above which results are collapsed, 0 to keep every result
+	rescoreWindow  int                  // Candidates each hybrid leg contributes, 0 for twice the results up to the page
+	relaxation     []string             // Strategies retried when a basic or full-text search matches nothing
+	stopwords      *stopwords.List
+	language       string // Language whose stopwords are used, empty to detect it from the query
+	debug          bool   // Attach models.SearchDebug to responses
+	normalizer     *textnorm.Normalizer
+	boosts         boost.Rules             // Score factors of documents by attribute
+	features       boost.Features          // Score factors of documents by how they match the query
+	curations      *curation.Store         // Documents pinned and hidden by query
+	blocklist      *blocklist.Blocklist    // Documents never returned
+	calibration    calibration.Calibration // Score mappings by mode
 }
 
 // NewSearchEngine creates a new search engine with the Manticore client interface
 func NewSearchEngine(client manticore.ClientInterface, vectorizer *vectorizer.TFIDFVectorizer, aiConfig *models.AISearchConfig) *SearchEngine {
+	timeouts := manticore.DefaultOperationTimeouts()
+	if client != nil {
+		timeouts = client.GetOperationTimeouts()
+	}
+
 	return &SearchEngine{
 		client:        client,
 		searchAdapter: manticore.NewSearchAdapter(client),
 		vectorizer:    vectorizer,
 		aiConfig:      aiConfig,
+		timeouts:      timeouts,
+	}
+}
+
+// WithFields returns a copy of the engine that only returns the selected result fields (see ParseFields).
+// Basic and full-text searches also limit the fields fetched from Manticore.
+func (e *SearchEngine) WithFields(fields []string) *SearchEngine {
+	engine := *e
+	engine.fields = fields
+	engine.searchAdapter = e.searchAdapter.WithSource(sourceFilter(fields, e.requiredFields()...))
+	return &engine
+}
+
+// WithStatuses returns a copy of the engine whose searches match documents with one of statuses
+// (see ParseStatuses) instead of only active ones
+func (e *SearchEngine) WithStatuses(statuses []models.DocumentStatus) *SearchEngine {
+	engine := *e
+	engine.statuses = statuses
+	engine.searchAdapter = e.searchAdapter.WithStatuses(statuses)
+	return &engine
+}
+
+// WithRecency returns a copy of the engine whose searches apply options (see ParseRecency)
+func (e *SearchEngine) WithRecency(options manticore.RecencyOptions) *SearchEngine {
+	engine := *e
+	engine.recency = options
+	engine.searchAdapter = e.searchAdapter.WithRecency(options)
+	if len(e.fields) > 0 {
+		engine.searchAdapter = engine.searchAdapter.WithSource(sourceFilter(e.fields, engine.requiredFields()...))
 	}
+	return &engine
+}
+
+// WithTags returns a copy of the engine whose searches apply filter (see ParseTags)
+func (e *SearchEngine) WithTags(filter manticore.TagFilter) *SearchEngine {
+	engine := *e
+	engine.tags = filter
+	engine.searchAdapter = e.searchAdapter.WithTags(filter)
+	return &engine
+}
+
+// searchStatuses returns the document statuses searches are restricted to
+func (e *SearchEngine) searchStatuses() []models.DocumentStatus {
+	if len(e.statuses) == 0 {
+		return manticore.DefaultSearchStatuses
+	}
+	return e.statuses
 }
 
 // Search performs search across different modes using official client
 func (e *SearchEngine) Search(query string, mode models.SearchMode, page, pageSize int) (*models.SearchResponse, error) {
+	return e.SearchContext(context.Background(), query, mode, page, pageSize)
+}
+
+// SearchContext performs search bounded by the mode's configured timeout and cancelled with ctx,
+// so a slow mode or a disconnected caller does not hold the request goroutine
+func (e *SearchEngine) SearchContext(ctx context.Context, query string, mode models.SearchMode, page, pageSize int) (*models.SearchResponse, error) {
+	// mode=auto searches in the mode suited to the query, with that mode's timeout
+	var queryClass, routing string
+	if mode == models.SearchModeAuto {
+		mode, queryClass, routing = e.routeMode(query)
+		log.Printf("Auto mode: routed %s query='%s' (%s) to %s search", queryClass, query, routing, mode)
+	}
+
+	if timeout := e.timeouts.ForMode(mode); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Normalize the query like the indexed text, then use the stopwords of its language
+	original := query
+	query = e.normalizer.String(query)
+	e, detection := e.routeLanguage(query)
+
+	var response *models.SearchResponse
+	var err error
+
 	switch mode {
 	case models.SearchModeBasic:
-		return e.BasicSearch(query, page, pageSize)
+		response, err = e.basicSearch(ctx, query, page, pageSize)
+		response = e.relaxIfEmpty(ctx, response, err, query, mode, page, pageSize)
 	case models.SearchModeFullText:
-		return e.FullTextSearch(query, page, pageSize)
+		response, err = e.fullTextSearch(ctx, query, page, pageSize)
+		response = e.relaxIfEmpty(ctx, response, err, query, mode, page, pageSize)
 	case models.SearchModeVector:
-		return e.VectorSearch(query, page, pageSize)
+		response, err = e.vectorSearch(ctx, query, page, pageSize)
 	case models.SearchModeHybrid:
-		return e.HybridSearch(query, page, pageSize)
+		response, err = e.hybridSearch(ctx, query, page, pageSize)
 	case models.SearchModeAI:
-		return e.AISearch(query, page, pageSize)
+		response, err = e.aiSearch(ctx, query, page, pageSize)
+	case models.SearchModeAIHybrid:
+		response, err = e.aiHybridSearch(ctx, query, page, pageSize)
 	default:
 		return nil, fmt.Errorf("unknown search mode: %s", mode)
 	}
+
+	if err == nil && response != nil {
+		e.calibration.Apply(mode, response.Documents)
+		e.curate(original, response, page)
+		e.removeBlocked(response)
+		e.collapseDuplicates(ctx, response)
+		if e.debug {
+			response.Debug = e.searchDebug(query, detection, response.Documents)
+			response.Debug.Routing = routing
+		}
+		response.QueryClass = queryClass
+		projectFields(response.Documents, e.fields, e.snippetFunc(mode, query))
+	}
+	return response, err
+}
+
+// relaxIfEmpty returns the results of the relaxation strategies when response matched nothing,
+// or response otherwise
+func (e *SearchEngine) relaxIfEmpty(ctx context.Context, response *models.SearchResponse, err error, query string, mode models.SearchMode, page, pageSize int) *models.SearchResponse {
+	if err != nil || response == nil || response.Total > 0 || len(e.relaxation) == 0 {
+		return response
+	}
+	if relaxed := e.relax(ctx, query, mode, page, pageSize); relaxed != nil {
+		return relaxed
+	}
+	return response
 }
 
 // BasicSearch performs simple text matching
 func (e *SearchEngine) BasicSearch(query string, page, pageSize int) (*models.SearchResponse, error) {
-	return e.searchAdapter.BasicSearch(query, page, pageSize)
+	return e.basicSearch(context.Background(), query, page, pageSize)
+}
+
+func (e *SearchEngine) basicSearch(ctx context.Context, query string, page, pageSize int) (*models.SearchResponse, error) {
+	response, err := e.searchAdapter.BasicSearchContext(ctx, e.basicQuery(query), page, pageSize)
+	e.boostPage(response, query)
+	return response, err
 }
 
 // FullTextSearch performs full-text search with Manticore's query language
 func (e *SearchEngine) FullTextSearch(query string, page, pageSize int) (*models.SearchResponse, error) {
-	return e.searchAdapter.FullTextSearch(query, page, pageSize)
+	return e.fullTextSearch(context.Background(), query, page, pageSize)
+}
+
+func (e *SearchEngine) fullTextSearch(ctx context.Context, query string, page, pageSize int) (*models.SearchResponse, error) {
+	response, err := e.searchAdapter.FullTextSearchContext(ctx, FullTextQuery(query, e.stopwords), page, pageSize)
+	e.boostPage(response, query)
+	return response, err
 }
 
 // VectorSearch performs vector similarity search
 func (e *SearchEngine) VectorSearch(query string, page, pageSize int) (*models.SearchResponse, error) {
+	return e.vectorSearch(context.Background(), query, page, pageSize)
+}
+
+func (e *SearchEngine) vectorSearch(ctx context.Context, query string, page, pageSize int) (*models.SearchResponse, error) {
 	// Get all documents with pre-computed vectors from documents_vector table
-	documents, vectors, err := e.searchAdapter.GetAllDocumentsWithVectors()
+	documents, vectors, err := e.searchAdapter.GetAllDocumentsWithVectorsContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get documents with vectors: %v", err)
 	}
@@ -92,8 +250,12 @@ func (e *SearchEngine) VectorSearch(query string, page, pageSize int) (*models.S
 		}, nil
 	}
 
-	// Vectorize query using same TF-IDF approach
-	queryVec := e.vectorizer.TransformQuery(query)
+	// Vectorize query using same TF-IDF approach. A query of stopwords only is not vectorized, as
+	// its zero vector would match every document.
+	var queryVec []float64
+	if filtered := e.stopwords.Filter(query); filtered != "" {
+		queryVec = e.vectorizer.TransformQuery(filtered)
+	}
 	if len(queryVec) == 0 {
 		return &models.SearchResponse{
 			Documents: []models.SearchResult{},
@@ -109,10 +271,16 @@ func (e *SearchEngine) VectorSearch(query string, page, pageSize int) (*models.S
 		similarity float64
 	}
 
+	// Vectors are scored in memory, so the status, since, tag and id filters and the blocklist are
+	// applied here as well, keeping pages full and totals exact
+	statuses := e.searchStatuses()
+	words := queryWords(query)
 	similarities := make([]docSimilarity, 0, len(documents))
+	matched := make([]*models.Document, 0, len(documents))
 	for i, doc := range documents {
-		if i < len(vectors) {
-			similarity := vectorizer.CosineSimilarity(queryVec, vectors[i])
+		if i < len(vectors) && hasStatus(statuses, doc.Status) && e.recency.Matches(doc) && e.tags.Matches(doc) && e.ids.Matches(doc) && e.regex.Matches(doc) && !e.blocklist.Blocks(doc) {
+			matched = append(matched, doc)
+			similarity := vectorizer.CosineSimilarity(queryVec, vectors[i]) * e.boosts.Factor(doc) * e.featureFactor(words, doc)
 			similarities = append(similarities, docSimilarity{
 				document:   doc,
 				similarity: similarity,
@@ -120,45 +288,55 @@ func (e *SearchEngine) VectorSearch(query string, page, pageSize int) (*models.S
 		}
 	}
 
-	// Sort by similarity (descending)
+	// Sort by boosted similarity (descending), then by the requested order
 	sort.Slice(similarities, func(i, j int) bool {
 		return similarities[i].similarity > similarities[j].similarity
 	})
-
-	// Convert to search results
-	searchResults := make([]models.SearchResult, 0, len(similarities))
-	for _, sim := range similarities {
-		searchResults = append(searchResults, models.SearchResult{
-			Document: sim.document,
-			Score:    sim.similarity,
-		})
+	ranked := make([]models.SearchResult, len(similarities))
+	for i, sim := range similarities {
+		ranked[i] = models.SearchResult{Document: sim.document, Score: sim.similarity}
 	}
+	e.recency.Sort.SortResults(ranked)
 
-	// Apply pagination
-	start := (page - 1) * pageSize
-	end := start + pageSize
-	if start > len(searchResults) {
-		searchResults = []models.SearchResult{}
-	} else if end > len(searchResults) {
-		searchResults = searchResults[start:]
-	} else {
-		searchResults = searchResults[start:end]
-	}
+	// Similarities are computed here rather than by Manticore, so only the requested page is returned
+	start, end := pageBounds(len(ranked), page, pageSize)
+	searchResults := ranked[start:end:end]
 
 	return &models.SearchResponse{
-		Documents: searchResults,
-		Total:     len(similarities),
-		Page:      page,
-		Mode:      string(models.SearchModeVector),
+		Documents:  searchResults,
+		Total:      len(similarities),
+		Page:       page,
+		Mode:       string(models.SearchModeVector),
+		Pagination: models.PaginationClient,
+		Facets:     e.serviceFacets(matched),
 	}, nil
 }
 
+// pageBounds returns the slice bounds of a page within n ranked results
+func pageBounds(n, page, pageSize int) (int, int) {
+	start := min((page-1)*pageSize, n)
+	end := min(start+pageSize, n)
+	return start, end
+}
+
 // HybridSearch combines full-text and vector search results
 func (e *SearchEngine) HybridSearch(query string, page, pageSize int) (*models.SearchResponse, error) {
+	return e.hybridSearch(context.Background(), query, page, pageSize)
+}
+
+func (e *SearchEngine) hybridSearch(ctx context.Context, query string, page, pageSize int) (*models.SearchResponse, error) {
 	log.Printf("HybridSearch: Starting hybrid search for query='%s', page=%d, pageSize=%d", query, page, pageSize)
 
+	// The full-text limit is applied by Manticore
+	window := e.candidateWindow(page, pageSize)
+
+	// Boosts and features apply to the fused ranking rather than to either leg
+	legs := *e
+	legs.boosts = nil
+	legs.features = nil
+
 	// Get full-text search results
-	ftResults, err := e.FullTextSearch(query, 1, pageSize*2) // Get more results for merging
+	ftResults, err := legs.fullTextSearch(ctx, query, 1, window)
 	if err != nil {
 		log.Printf("HybridSearch: Full-text search failed: %v", err)
 		ftResults = &models.SearchResponse{Documents: []models.SearchResult{}}
@@ -171,7 +349,7 @@ func (e *SearchEngine) HybridSearch(query string, page, pageSize int) (*models.S
 	}
 
 	// Get vector search results
-	vectorResults, err := e.VectorSearch(query, 1, pageSize*2) // Get more results for merging
+	vectorResults, err := legs.vectorSearch(ctx, query, 1, window)
 	if err != nil {
 		log.Printf("HybridSearch: Vector search failed: %v", err)
 		vectorResults = &models.SearchResponse{Documents: []models.SearchResult{}}
@@ -185,31 +363,42 @@ func (e *SearchEngine) HybridSearch(query string, page, pageSize int) (*models.S
 
 	// Combine and deduplicate results
 	combined := e.combineResults(ftResults.Documents, vectorResults.Documents)
+	e.applyFeatures(query, combined)
+	e.boosts.Apply(combined)
+	e.recency.Sort.SortResults(combined)
 
-	// Apply pagination
-	start := (page - 1) * pageSize
-	end := start + pageSize
+	// The total only counts the merged candidates, so it is a lower bound when either source had more
 	totalResults := len(combined)
+	relation := manticore.TotalRelationEqual
+	if ftResults.Total > len(ftResults.Documents) || vectorResults.Total > len(vectorResults.Documents) {
+		relation = manticore.TotalRelationAtLeast
+	}
 
-	if start > len(combined) {
-		combined = []models.SearchResult{}
-	} else if end > len(combined) {
-		combined = combined[start:]
-	} else {
-		combined = combined[start:end]
+	// The facet counts the merged candidates, like the total
+	candidates := make([]*models.Document, len(combined))
+	for i, result := range combined {
+		candidates[i] = result.Document
 	}
+	facets := e.serviceFacets(candidates)
+
+	// Apply pagination
+	start, end := pageBounds(len(combined), page, pageSize)
+	combined = combined[start:end]
 
-	log.Printf("HybridSearch: Returning %d results (total: %d) after pagination", len(combined), totalResults)
+	log.Printf("HybridSearch: Returning %d results (total: %d, window: %d) after pagination", len(combined), totalResults, window)
 	if len(combined) > 0 {
 		log.Printf("HybridSearch: Final top result: '%s' (combined score: %.4f)",
 			combined[0].Document.Title, combined[0].Score)
 	}
 
 	return &models.SearchResponse{
-		Documents: combined,
-		Total:     totalResults,
-		Page:      page,
-		Mode:      string(models.SearchModeHybrid),
+		Documents:     combined,
+		Total:         totalResults,
+		TotalRelation: relation,
+		Page:          page,
+		Mode:          string(models.SearchModeHybrid),
+		Pagination:    models.PaginationClient,
+		Facets:        facets,
 	}, nil
 }
 
@@ -263,8 +452,8 @@ func (e *SearchEngine) combineResults(ftResults, vectorResults []models.SearchRe
 	}
 
 	// Normalize scores to 0-1 range for both result sets
-	normalizedFTResults := normalizeScores(append([]models.SearchResult(nil), ftResults...))         // Copy slice
-	normalizedVectorResults := normalizeScores(append([]models.SearchResult(nil), vectorResults...)) // Copy slice
+	normalizedFTResults := e.normalizeLeg(models.SearchModeFullText, ftResults)
+	normalizedVectorResults := e.normalizeLeg(models.SearchModeVector, vectorResults)
 
 	log.Printf("HybridSearch: After normalization - FT max score: %.4f, Vector max score: %.4f",
 		getMaxScore(normalizedFTResults), getMaxScore(normalizedVectorResults))
@@ -349,6 +538,10 @@ func getMaxScore(results []models.SearchResult) float64 {
 
 // AISearch performs AI-powered semantic search using Manticore's AI search functionality
 func (e *SearchEngine) AISearch(query string, page, pageSize int) (*models.SearchResponse, error) {
+	return e.aiSearch(context.Background(), query, page, pageSize)
+}
+
+func (e *SearchEngine) aiSearch(ctx context.Context, query string, page, pageSize int) (*models.SearchResponse, error) {
 	startTime := time.Now()
 	log.Printf("AISearch: Starting AI search for query='%s', page=%d, pageSize=%d", query, page, pageSize)
 
@@ -375,6 +568,12 @@ func (e *SearchEngine) AISearch(query string, page, pageSize int) (*models.Searc
 		return nil, fmt.Errorf("Manticore client is not available for AI search")
 	}
 
+	// Skip the request entirely when the server is known not to support KNN search or Auto Embeddings
+	if err := e.client.GetCapabilities().RequireAISearch(); err != nil {
+		log.Printf("AISearch: %v", err)
+		return nil, fmt.Errorf("AI search is not available: %w", err)
+	}
+
 	// Calculate offset for pagination
 	offset := (page - 1) * pageSize
 
@@ -391,8 +590,18 @@ func (e *SearchEngine) AISearch(query string, page, pageSize int) (*models.Searc
 	log.Printf("AISearch: Configuration - Model: %s, Enabled: %t, Timeout: %v",
 		model, e.aiConfig.Enabled, e.aiConfig.Timeout)
 
+	// Bound the embedding and search by the AI timeout; the caller's cancellation still applies
+	if e.aiConfig.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.aiConfig.Timeout)
+		defer cancel()
+	}
+
 	// Perform AI search using the client
-	response, err := e.client.AISearch(query, model, pageSize, offset)
+	aiCtx := manticore.WithRecency(manticore.WithSearchStatuses(ctx, e.searchStatuses()), e.recency)
+	aiCtx = manticore.WithRegexFilter(manticore.WithIDFilter(manticore.WithTagFilter(aiCtx, e.tags), e.ids), e.regex)
+	aiCtx = manticore.WithKNNOptions(manticore.WithHistogramFacets(aiCtx, e.histograms), e.knnOptions())
+	response, err := e.client.AISearchWithContext(aiCtx, e.embeddingQuery(query), model, pageSize, offset)
 	searchDuration := time.Since(startTime)
 
 	if err != nil {
@@ -413,19 +622,40 @@ func (e *SearchEngine) AISearch(query string, page, pageSize int) (*models.Searc
 	totalDuration := time.Since(startTime)
 	resultCount := len(searchResults)
 
+	// A client may report success without a response body
+	total, relation := 0, ""
+	if response != nil {
+		total, relation = int(response.Hits.Total), response.Hits.TotalRelation
+	}
+
 	log.Printf("AISearch: Successfully completed AI search in %v - Query: '%s', Model: '%s', Results: %d/%d",
-		totalDuration, query, model, resultCount, int(response.Hits.Total))
+		totalDuration, query, model, resultCount, total)
 
 	// Log performance metrics for monitoring
 	log.Printf("AISearch: Performance - Search Duration: %v, Processing Duration: %v, Total Duration: %v",
 		searchDuration, totalDuration-searchDuration, totalDuration)
 
-	return &models.SearchResponse{
-		Documents: searchResults,
-		Total:     int(response.Hits.Total),
-		Page:      page,
-		Mode:      string(models.SearchModeAI),
-	}, nil
+	result := &models.SearchResponse{
+		Documents:     searchResults,
+		Total:         total,
+		TotalRelation: relation,
+		Page:          page,
+		Mode:          string(models.SearchModeAI),
+		Pagination:    models.PaginationServer,
+		Facets:        manticore.FacetsFromResponse(response),
+	}
+	e.boostPage(result, query)
+	return result, nil
+}
+
+// embeddingQuery returns the query Manticore embeds for AI search, truncated to the input limit of
+// the model, which would ignore the rest anyway
+func (e *SearchEngine) embeddingQuery(query string) string {
+	truncated, ok := tokens.Truncate(query, e.aiConfig.MaxTokens)
+	if ok {
+		log.Printf("AISearch: Truncated query of ~%d tokens to %d tokens before embedding", tokens.Estimate(query), e.aiConfig.MaxTokens)
+	}
+	return truncated
 }
 
 // processAISearchResults converts Manticore AI search response to SearchResult format
@@ -458,12 +688,7 @@ func (e *SearchEngine) processAISearchResults(response *manticore.SearchResponse
 }
 
 // extractDocumentFromHit extracts document information from a Manticore search hit
-func (e *SearchEngine) extractDocumentFromHit(hit struct {
-	Index  string                 `json:"_index"`
-	ID     int64                  `json:"_id"`
-	Score  float32                `json:"_score"`
-	Source map[string]interface{} `json:"_source"`
-}) (*models.Document, error) {
+func (e *SearchEngine) extractDocumentFromHit(hit manticore.SearchHit) (*models.Document, error) {
 	// Extract document fields from source
 	title, _ := hit.Source["title"].(string)
 	content, _ := hit.Source["content"].(string)
@@ -476,6 +701,7 @@ func (e *SearchEngine) extractDocumentFromHit(hit struct {
 		Content: content,
 		URL:     url,
 	}
+	manticore.ApplySourceAttributes(doc, hit.Source)
 
 	return doc, nil
 }
@@ -1,6 +1,8 @@
 package search
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -18,9 +20,30 @@ func (m *MockClient) WaitForReady(timeout time.Duration) error           { retur
 func (m *MockClient) HealthCheck() error                                 { return nil }
 func (m *MockClient) Close() error                                       { return nil }
 func (m *MockClient) IsConnected() bool                                  { return true }
+func (m *MockClient) GetCapabilities() *manticore.Capabilities           { return nil }
 func (m *MockClient) CreateSchema(aiConfig *models.AISearchConfig) error { return nil }
-func (m *MockClient) ResetDatabase() error                               { return nil }
-func (m *MockClient) TruncateTables() error                              { return nil }
+func (m *MockClient) MigrateSchema(aiConfig *models.AISearchConfig) (*manticore.MigrationResult, error) {
+	return &manticore.MigrationResult{}, nil
+}
+func (m *MockClient) Backup(path string) (*manticore.BackupResult, error) {
+	return &manticore.BackupResult{Path: path}, nil
+}
+func (m *MockClient) ReembedDocuments(ctx context.Context, aiConfig *models.AISearchConfig, progress func(manticore.ReembedProgress)) (*manticore.ReembedResult, error) {
+	return &manticore.ReembedResult{Model: aiConfig.Model}, nil
+}
+func (m *MockClient) DropStaleDocumentsTables() ([]manticore.DroppedTable, error)    { return nil, nil }
+func (m *MockClient) SetDocumentStatus(id int64, status models.DocumentStatus) error { return nil }
+func (m *MockClient) SetDocumentTags(id int64, tags []string) error                  { return nil }
+func (m *MockClient) ResetDatabase() error                                           { return nil }
+func (m *MockClient) TruncateTables() error                                          { return nil }
+func (m *MockClient) TruncateTable(table string) error                               { return nil }
+func (m *MockClient) OptimizeTable(table string) error                               { return nil }
+func (m *MockClient) DocumentIDs(table string) ([]int64, error)                      { return nil, nil }
+func (m *MockClient) GetDocumentHashes() (map[int64]string, error)                   { return nil, nil }
+func (m *MockClient) GetDocumentTimes() (map[int64]int64, error)                     { return nil, nil }
+func (m *MockClient) DeleteDocuments(table string, ids []int64) error                { return nil }
+func (m *MockClient) ResetTable(table string, aiConfig *models.AISearchConfig) error { return nil }
+func (m *MockClient) CountDocuments(table string) (int64, error)                     { return 0, nil }
 func (m *MockClient) IndexDocument(doc *models.Document, vector []float64) error {
 	return nil
 }
@@ -31,10 +54,64 @@ func (m *MockClient) Search(query string, mode models.SearchMode, page, pageSize
 	return nil, nil
 }
 func (m *MockClient) GetAllDocuments() ([]*models.Document, error) { return nil, nil }
+func (m *MockClient) GetDocument(id int64) (*models.Document, error) {
+	return nil, manticore.ErrDocumentNotFound
+}
+func (m *MockClient) GetAllDocumentsWithVectors() ([]*models.Document, [][]float64, error) {
+	return nil, nil, nil
+}
 func (m *MockClient) SearchWithRequest(request manticore.SearchRequest) (*manticore.SearchResponse, error) {
 	return nil, nil
 }
 
+func (m *MockClient) SearchWithContext(ctx context.Context, request manticore.SearchRequest) (*manticore.SearchResponse, error) {
+	return m.SearchWithRequest(request)
+}
+
+func (m *MockClient) GetAllDocumentsWithVectorsContext(ctx context.Context) ([]*models.Document, [][]float64, error) {
+	return m.GetAllDocumentsWithVectors()
+}
+
+func (m *MockClient) GetOperationTimeouts() manticore.OperationTimeouts {
+	return manticore.DefaultOperationTimeouts()
+}
+
+func (m *MockClient) CheckHealth() manticore.HealthReport {
+	return manticore.HealthReport{State: manticore.HealthStateReady, ServerUp: true, SchemaPresent: true}
+}
+
+func (m *MockClient) Count(query string, filters map[string]interface{}) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockClient) AISearchWithContext(ctx context.Context, query string, model string, limit, offset int) (*manticore.SearchResponse, error) {
+	return m.AISearch(query, model, limit, offset)
+}
+
+func (m *MockClient) GetIndexStats(table string) (*manticore.IndexStats, error) {
+	return &manticore.IndexStats{Table: table}, nil
+}
+
+func (m *MockClient) EmbeddingModels(table string) ([]manticore.EmbeddingModel, error) {
+	return nil, nil
+}
+
+func (m *MockClient) WaitForReadyContext(ctx context.Context, opts manticore.ReadyOptions) error {
+	return nil
+}
+
+func (m *MockClient) GetCircuitBreakerTransitions() []manticore.CircuitBreakerTransition {
+	return nil
+}
+
+func (m *MockClient) GetResilienceStats() manticore.ResilienceStats {
+	return manticore.ResilienceStats{}
+}
+
+func (m *MockClient) GetRecordings() manticore.Recordings {
+	return manticore.Recordings{}
+}
+
 func (m *MockClient) AISearch(query string, model string, limit, offset int) (*manticore.SearchResponse, error) {
 	return m.aiSearchResponse, m.aiSearchError
 }
@@ -48,24 +125,10 @@ func TestAISearch_Success(t *testing.T) {
 	mockResponse := &manticore.SearchResponse{
 		Took:     5,
 		TimedOut: false,
-		Hits: struct {
-			Total         int32  `json:"total"`
-			TotalRelation string `json:"total_relation"`
-			Hits          []struct {
-				Index  string                 `json:"_index"`
-				ID     int64                  `json:"_id"`
-				Score  float32                `json:"_score"`
-				Source map[string]interface{} `json:"_source"`
-			} `json:"hits"`
-		}{
+		Hits: manticore.SearchHits{
 			Total:         2,
 			TotalRelation: "eq",
-			Hits: []struct {
-				Index  string                 `json:"_index"`
-				ID     int64                  `json:"_id"`
-				Score  float32                `json:"_score"`
-				Source map[string]interface{} `json:"_source"`
-			}{
+			Hits: []manticore.SearchHit{
 				{
 					Index: "documents",
 					ID:    1,
@@ -209,6 +272,72 @@ func TestAISearch_EmptyQuery(t *testing.T) {
 	}
 }
 
+// slowAIClient blocks AI searches until their context is done
+type slowAIClient struct {
+	MockClient
+}
+
+func (c *slowAIClient) AISearchWithContext(ctx context.Context, query string, model string, limit, offset int) (*manticore.SearchResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestAISearch_Timeout(t *testing.T) {
+	aiConfig := &models.AISearchConfig{
+		Model:   "sentence-transformers/all-MiniLM-L6-v2",
+		Enabled: true,
+		Timeout: 20 * time.Millisecond,
+	}
+	engine := NewSearchEngine(&slowAIClient{}, nil, aiConfig)
+
+	start := time.Now()
+	_, err := engine.AISearch("test query", 1, 10)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected a deadline error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the AI timeout to stop the search, took %v", elapsed)
+	}
+
+	// Cancelling the caller's context stops the search before the timeout
+	aiConfig.Timeout = time.Minute
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := engine.aiSearch(ctx, "test query", 1, 10); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancellation error, got: %v", err)
+	}
+}
+
+// queryRecordingClient records the query of AI searches
+type queryRecordingClient struct {
+	MockClient
+	query string
+}
+
+func (c *queryRecordingClient) AISearchWithContext(ctx context.Context, query string, model string, limit, offset int) (*manticore.SearchResponse, error) {
+	c.query = query
+	return &manticore.SearchResponse{}, nil
+}
+
+func TestAISearch_TruncatesQuery(t *testing.T) {
+	client := &queryRecordingClient{}
+	aiConfig := &models.AISearchConfig{Model: "sentence-transformers/all-MiniLM-L6-v2", Enabled: true, MaxTokens: 4}
+	engine := NewSearchEngine(client, nil, aiConfig)
+
+	if _, err := engine.AISearch("how to search documents", 1, 10); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if client.query != "how to search" {
+		t.Errorf("Expected the query to be truncated to 4 tokens, got %q", client.query)
+	}
+
+	aiConfig.MaxTokens = 0
+	engine.AISearch("how to search documents", 1, 10)
+	if client.query != "how to search documents" {
+		t.Errorf("Expected the whole query without a limit, got %q", client.query)
+	}
+}
+
 // Helper function to check if a string contains a substring
 func containsString(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || findSubstring(s, substr))
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"sort"
+
+	"github.com/ad/manticoresearch-go/internal/boost"
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// WithFeatures returns a copy of the engine that multiplies the scores of documents by the weights
+// of the scoring features they have for the query, such as a title equal to the query. Features
+// apply in every mode like boosts, see WithBoosts.
+func (e *SearchEngine) WithFeatures(features boost.Features) *SearchEngine {
+	engine := *e
+	engine.features = features
+	if len(e.fields) > 0 {
+		engine.searchAdapter = e.searchAdapter.WithSource(sourceFilter(e.fields, engine.requiredFields()...))
+	}
+	return &engine
+}
+
+// featureFields returns the stored fields the features read
+func (e *SearchEngine) featureFields() []string {
+	if len(e.features) == 0 {
+		return nil
+	}
+	return []string{FieldTitle, FieldURL}
+}
+
+// queryWords returns the words of a normalized query the features compare documents with
+func queryWords(query string) []string {
+	return boost.Words(manticore.QueryTerms(query))
+}
+
+// documentFeatures returns the features doc has for the query words
+func (e *SearchEngine) documentFeatures(words []string, doc *models.Document) []string {
+	if len(e.features) == 0 || doc == nil {
+		return nil
+	}
+	return e.features.Match(words, e.normalizer.String(doc.Title), e.normalizer.String(doc.URL))
+}
+
+// featureFactor returns the product of the weights of the features doc has for the query words
+func (e *SearchEngine) featureFactor(words []string, doc *models.Document) float64 {
+	return e.features.Factor(e.documentFeatures(words, doc))
+}
+
+// applyFeatures multiplies the scores of results by their feature weights and orders them by the
+// weighted score, keeping the order of equal scores
+func (e *SearchEngine) applyFeatures(query string, results []models.SearchResult) {
+	if len(e.features) == 0 {
+		return
+	}
+	words := queryWords(query)
+	for i := range results {
+		results[i].Score *= e.featureFactor(words, results[i].Document)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+}
+
+// featureDebug lists the features of each result that has any
+func (e *SearchEngine) featureDebug(query string, results []models.SearchResult) []models.FeatureMatch {
+	if len(e.features) == 0 {
+		return nil
+	}
+	words := queryWords(query)
+	var matches []models.FeatureMatch
+	for _, result := range results {
+		features := e.documentFeatures(words, result.Document)
+		if len(features) > 0 {
+			matches = append(matches, models.FeatureMatch{ID: result.Document.ID, Features: features, Factor: e.features.Factor(features)})
+		}
+	}
+	return matches
+}
@@ -0,0 +1,56 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/boost"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+func TestSearchFeatures(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, Title: "Notes", URL: "https://wiki.example.com/notes", Content: "guide guide guide"},
+		{ID: 2, Title: "Other notes", URL: "https://wiki.example.com/other", Content: "guide guide notes"},
+		{ID: 3, Title: "Guide", URL: "https://wiki.example.com/handbook", Content: "guide handbook chapter one"},
+		{ID: 4, Title: "Handbook", URL: "https://wiki.example.com/guide", Content: "guide handbook chapter two"},
+		{ID: 5, Title: "Pricing", URL: "https://wiki.example.com/pricing", Content: "prices"},
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	engine := NewSearchEngine(&vectorMockClient{documents: documents, vectors: vec.FitTransform(documents)}, vec, nil).WithRescoreWindow(10)
+
+	response, err := engine.VectorSearch("guide", 1, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Documents[0].Document.ID == 3 {
+		t.Fatal("Expected the exact title not to rank first without features")
+	}
+
+	features := boost.Features{boost.FeatureExactTitle: 20, boost.FeatureURLSlug: 10}
+	weighted := engine.WithFeatures(features).WithFields([]string{FieldID}).WithDebug(true)
+	for _, mode := range []models.SearchMode{models.SearchModeVector, models.SearchModeHybrid} {
+		response, err := weighted.SearchContext(context.Background(), "guide", mode, 1, 2)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.Documents[0].Document.ID != 3 || response.Documents[1].Document.ID != 4 {
+			t.Errorf("Mode %s: expected the exact title then the URL slug first, got %d and %d", mode, response.Documents[0].Document.ID, response.Documents[1].Document.ID)
+		}
+		// The features are reported before the fields are projected
+		debug := response.Debug.Features
+		if len(debug) != 2 || debug[0].ID != 3 || debug[0].Features[0] != boost.FeatureExactTitle || debug[0].Factor != 20 || debug[1].Features[0] != boost.FeatureURLSlug {
+			t.Errorf("Mode %s: unexpected debug features %+v", mode, debug)
+		}
+		if response.Documents[0].Document.Title != "" {
+			t.Errorf("Mode %s: expected only the id to be returned", mode)
+		}
+	}
+
+	// Titles and URLs are fetched for searches returning selected fields
+	filter := sourceFilter(weighted.fields, weighted.requiredFields()...)
+	if !hasField(filter.Includes, FieldTitle) || !hasField(filter.Includes, FieldURL) {
+		t.Errorf("Expected the title and url to be fetched, got %v", filter.Includes)
+	}
+}
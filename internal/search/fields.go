@@ -0,0 +1,139 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// Result fields that can be selected with the fields parameter
+const (
+	FieldID        = "id"
+	FieldTitle     = "title"
+	FieldURL       = "url"
+	FieldContent   = "content"
+	FieldSnippet   = "snippet"
+	FieldIndexedAt = "indexed_at"
+	FieldUpdatedAt = "updated_at"
+	FieldTags      = "tags"
+)
+
+// selectableFields lists the fields accepted by ParseFields
+var selectableFields = []string{FieldID, FieldTitle, FieldURL, FieldContent, FieldSnippet, FieldIndexedAt, FieldUpdatedAt, FieldTags}
+
+// snippetLength is the maximum number of characters in a snippet
+const snippetLength = 200
+
+// ParseFields parses a comma-separated list of result fields. An empty list selects full documents.
+func ParseFields(param string) ([]string, error) {
+	var fields []string
+	seen := make(map[string]bool)
+
+	for _, field := range strings.Split(param, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" || seen[field] {
+			continue
+		}
+		if !hasField(selectableFields, field) {
+			return nil, validation.InvalidValue("fields", field, selectableFields...)
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// sourceFilter returns the stored fields Manticore has to return for the selected result fields
+// and the required stored fields, such as those read by boost rules, or nil when full documents
+// are needed
+func sourceFilter(fields []string, required ...string) *manticore.SourceFilter {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	// The id is always returned as _id, so it never has to be fetched from the source
+	includes := []string{}
+	for _, field := range []string{FieldTitle, FieldURL, FieldContent, FieldIndexedAt, FieldUpdatedAt, FieldTags} {
+		if hasField(fields, field) || (field == FieldContent && hasField(fields, FieldSnippet)) {
+			includes = append(includes, field)
+		}
+	}
+	for _, field := range required {
+		if !hasField(includes, field) {
+			includes = append(includes, field)
+		}
+	}
+	if len(includes) == 0 {
+		includes = append(includes, FieldID)
+	}
+	return &manticore.SourceFilter{Includes: includes}
+}
+
+// projectFields reduces each result's document to the selected fields, filling in snippets made
+// from the content with snippet. Documents are copied, so documents shared with other results are
+// left unchanged.
+func projectFields(results []models.SearchResult, fields []string, snippet func(content string) string) {
+	if len(fields) == 0 {
+		return
+	}
+
+	for i := range results {
+		if results[i].Document == nil {
+			continue
+		}
+		source := results[i].Document
+		doc := &models.Document{ID: source.ID}
+		if hasField(fields, FieldTitle) {
+			doc.Title = source.Title
+		}
+		if hasField(fields, FieldURL) {
+			doc.URL = source.URL
+		}
+		if hasField(fields, FieldContent) {
+			doc.Content = source.Content
+		}
+		if hasField(fields, FieldSnippet) {
+			doc.Snippet = snippet(source.Content)
+		}
+		if hasField(fields, FieldIndexedAt) {
+			doc.IndexedAt = source.IndexedAt
+		}
+		if hasField(fields, FieldUpdatedAt) {
+			doc.UpdatedAt = source.UpdatedAt
+		}
+		if hasField(fields, FieldTags) {
+			doc.Tags = source.Tags
+		}
+		results[i].Document = doc
+	}
+}
+
+// makeSnippet returns the start of content, cut at a word boundary when longer than maxChars
+func makeSnippet(content string, maxChars int) string {
+	content = strings.Join(strings.Fields(content), " ")
+	runes := []rune(content)
+	if len(runes) <= maxChars {
+		return content
+	}
+
+	cut := maxChars
+	for i := maxChars; i > maxChars/2; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), unicode.IsPunct) + "..."
+}
+
+func hasField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
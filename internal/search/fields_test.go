@@ -0,0 +1,96 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields(" id, Title ,url,,title")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(fields, ",") != "id,title,url" {
+		t.Errorf("Expected id,title,url, got %v", fields)
+	}
+
+	if fields, err := ParseFields(""); err != nil || len(fields) != 0 {
+		t.Errorf("Expected no fields for an empty parameter, got %v (%v)", fields, err)
+	}
+
+	if _, err := ParseFields("id,body"); err == nil {
+		t.Error("Expected error for an unknown field")
+	}
+}
+
+func TestSourceFilter(t *testing.T) {
+	if filter := sourceFilter(nil); filter != nil {
+		t.Errorf("Expected no filter for full documents, got %+v", filter)
+	}
+
+	filter := sourceFilter([]string{FieldID, FieldURL, FieldSnippet})
+	if strings.Join(filter.Includes, ",") != "url,content" {
+		t.Errorf("Expected url and content (for the snippet), got %v", filter.Includes)
+	}
+
+	if filter := sourceFilter([]string{FieldID}); len(filter.Includes) != 1 || filter.Includes[0] != FieldID {
+		t.Errorf("Expected an id-only filter, got %v", filter.Includes)
+	}
+}
+
+func TestProjectFields(t *testing.T) {
+	shared := &models.Document{ID: 1, Title: "Title", URL: "http://example.com/1", Content: strings.Repeat("word ", 100)}
+	results := []models.SearchResult{{Document: shared, Score: 1}}
+
+	projectFields(results, []string{FieldID, FieldTitle, FieldSnippet}, func(content string) string { return makeSnippet(content, snippetLength) })
+
+	doc := results[0].Document
+	if doc.Title != "Title" || doc.URL != "" || doc.Content != "" {
+		t.Errorf("Unexpected projected document: %+v", doc)
+	}
+	if !strings.HasSuffix(doc.Snippet, "...") || len([]rune(doc.Snippet)) > snippetLength+3 {
+		t.Errorf("Expected a truncated snippet, got %q", doc.Snippet)
+	}
+	if shared.Content == "" {
+		t.Error("Projection modified the original document")
+	}
+}
+
+func TestMakeSnippet(t *testing.T) {
+	if snippet := makeSnippet("Short\n  text", 200); snippet != "Short text" {
+		t.Errorf("Expected whitespace to be collapsed, got %q", snippet)
+	}
+	if snippet := makeSnippet("Привет мир, как дела", 12); snippet != "Привет мир..." {
+		t.Errorf("Expected a cut at a word boundary, got %q", snippet)
+	}
+}
+
+func TestSearchWithFields(t *testing.T) {
+	mockClient := &MockClient{
+		aiSearchResponse: &manticore.SearchResponse{
+			Hits: manticore.SearchHits{
+				Total: 1,
+				Hits: []manticore.SearchHit{{
+					ID:     1,
+					Score:  0.9,
+					Source: map[string]interface{}{"title": "Doc", "content": "Full content", "url": "http://example.com/1"},
+				}},
+			},
+		},
+	}
+	engine := NewSearchEngine(mockClient, nil, &models.AISearchConfig{Enabled: true}).WithFields([]string{FieldID, FieldURL})
+
+	response, err := engine.SearchContext(context.Background(), "test", models.SearchModeAI, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	doc := response.Documents[0].Document
+	if doc.ID != 1 || doc.URL != "http://example.com/1" || doc.Title != "" || doc.Content != "" {
+		t.Errorf("Expected only id and url, got %+v", doc)
+	}
+}
@@ -0,0 +1,104 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// MinHistogramInterval is the smallest histogram interval in seconds
+const MinHistogramInterval = 3600
+
+// histogramIntervals are the named intervals of the histogram parameter in seconds
+var histogramIntervals = map[string]int64{
+	"hour": 3600,
+	"day":  86400,
+	"week": 7 * 86400,
+}
+
+// ParseHistograms parses the histogram parameter, a comma-separated list of field:interval facets
+// such as updated_at:day. Fields are manticore.HistogramFields and intervals are hour, day, week
+// or a number of seconds of at least MinHistogramInterval.
+func ParseHistograms(param string) (manticore.HistogramFacets, error) {
+	var facets manticore.HistogramFacets
+	seen := make(map[string]bool)
+	for _, value := range strings.Split(param, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		field, interval, found := strings.Cut(value, ":")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !found {
+			return nil, validation.InvalidFormat("histogram", value, "field:interval")
+		}
+		if !isHistogramField(field) {
+			return nil, validation.InvalidValue("histogram", field, manticore.HistogramFields...)
+		}
+		if seen[field] {
+			continue
+		}
+		seen[field] = true
+
+		interval = strings.ToLower(strings.TrimSpace(interval))
+		seconds, ok := histogramIntervals[interval]
+		if !ok {
+			var err error
+			if seconds, err = strconv.ParseInt(interval, 10, 64); err != nil {
+				return nil, validation.InvalidFormat("histogram", interval, "hour", "day", "week", "seconds")
+			}
+			if seconds < MinHistogramInterval {
+				return nil, validation.TooSmall("histogram", interval, MinHistogramInterval)
+			}
+		}
+		facets = append(facets, manticore.HistogramFacet{Field: field, Interval: seconds})
+	}
+	return facets, nil
+}
+
+func isHistogramField(field string) bool {
+	for _, name := range manticore.HistogramFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// WithHistogramFacets returns a copy of the engine whose searches also count the matching
+// documents in the buckets of facets
+func (e *SearchEngine) WithHistogramFacets(facets manticore.HistogramFacets) *SearchEngine {
+	engine := *e
+	engine.histograms = facets
+	engine.searchAdapter = e.searchAdapter.WithHistogramFacets(facets)
+	if len(e.fields) > 0 {
+		// Hybrid searches count the histograms of full-text candidates in the service
+		engine.searchAdapter = engine.searchAdapter.WithSource(sourceFilter(e.fields, engine.requiredFields()...))
+	}
+	return &engine
+}
+
+// histogramFields returns the attributes the histogram facets count
+func (e *SearchEngine) histogramFields() []string {
+	fields := make([]string, 0, len(e.histograms))
+	for _, facet := range e.histograms {
+		fields = append(fields, facet.Field)
+	}
+	return fields
+}
+
+// serviceFacets returns the tag and histogram facets of documents ranked by the service, nil when
+// there are none
+func (e *SearchEngine) serviceFacets(documents []*models.Document) map[string][]models.FacetValue {
+	facets := tagFacets(documents)
+	for field, values := range e.histograms.Facets(documents) {
+		if facets == nil {
+			facets = make(map[string][]models.FacetValue)
+		}
+		facets[field] = values
+	}
+	return facets
+}
@@ -0,0 +1,57 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+func TestParseHistograms(t *testing.T) {
+	facets, err := ParseHistograms("updated_at:day, INDEXED_AT:7200,updated_at:week")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := manticore.HistogramFacets{{Field: "updated_at", Interval: 86400}, {Field: "indexed_at", Interval: 7200}}
+	if !reflect.DeepEqual(facets, expected) {
+		t.Errorf("Unexpected facets: %v", facets)
+	}
+
+	if facets, err := ParseHistograms(""); err != nil || facets != nil {
+		t.Errorf("Expected no facets, got %v, %v", facets, err)
+	}
+
+	tests := map[string]string{
+		"updated_at":       validation.CodeInvalidFormat,
+		"status:day":       validation.CodeInvalidValue,
+		"updated_at:month": validation.CodeInvalidFormat,
+		"updated_at:60":    validation.CodeTooSmall,
+	}
+	for param, code := range tests {
+		_, err := ParseHistograms(param)
+		var fieldErr *validation.FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Code != code {
+			t.Errorf("ParseHistograms(%q) = %v, expected a %s error", param, err, code)
+		}
+	}
+}
+
+func TestServiceFacets(t *testing.T) {
+	documents := []*models.Document{{ID: 1, UpdatedAt: 90000, Tags: []string{"go"}}}
+
+	engine := NewSearchEngine(nil, nil, nil).WithHistogramFacets(manticore.HistogramFacets{{Field: "updated_at", Interval: 86400}})
+	expected := map[string][]models.FacetValue{
+		models.FacetTags: {{Value: "go", Count: 1}},
+		"updated_at":     {{Value: "86400", Count: 1}},
+	}
+	if facets := engine.serviceFacets(documents); !reflect.DeepEqual(facets, expected) {
+		t.Errorf("Unexpected facets: %v", facets)
+	}
+
+	if facets := NewSearchEngine(nil, nil, nil).serviceFacets([]*models.Document{{ID: 1}}); facets != nil {
+		t.Errorf("Expected no facets, got %v", facets)
+	}
+}
@@ -0,0 +1,46 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// MaxIDs is the largest number of document ids the ids and exclude_ids parameters may each list
+const MaxIDs = 1000
+
+// ParseIDs parses a comma-separated list of document ids, such as the ids or exclude_ids
+// parameter named field, dropping duplicates
+func ParseIDs(field, param string) ([]int64, error) {
+	var ids []int64
+	seen := make(map[int64]bool)
+	for _, value := range strings.Split(param, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id < 1 {
+			return nil, validation.InvalidFormat(field, value, "document_id")
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > MaxIDs {
+		return nil, validation.OutOfRange(field, strconv.Itoa(len(ids)), 1, MaxIDs)
+	}
+	return ids, nil
+}
+
+// WithIDs returns a copy of the engine whose searches apply filter, restricting them to a known
+// candidate set or hiding documents the caller has already seen
+func (e *SearchEngine) WithIDs(filter manticore.IDFilter) *SearchEngine {
+	engine := *e
+	engine.ids = filter
+	engine.searchAdapter = e.searchAdapter.WithIDs(filter)
+	return &engine
+}
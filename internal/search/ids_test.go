@@ -0,0 +1,49 @@
+package search
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+func TestParseIDs(t *testing.T) {
+	if ids, err := ParseIDs("ids", " 3, 1,3 ,"); err != nil || !reflect.DeepEqual(ids, []int64{3, 1}) {
+		t.Errorf("Expected [3 1], got %v, %v", ids, err)
+	}
+	if ids, err := ParseIDs("ids", ""); ids != nil || err != nil {
+		t.Errorf("Expected no ids, got %v, %v", ids, err)
+	}
+	values := make([]string, MaxIDs+1)
+	for i := range values {
+		values[i] = strconv.Itoa(i + 1)
+	}
+	tooMany := strings.Join(values, ",")
+	for _, param := range []string{"1,x", "0", "-5", tooMany} {
+		if _, err := ParseIDs("ids", param); err == nil {
+			t.Errorf("Expected an error for %.20q", param)
+		}
+	}
+}
+
+func TestVectorSearchIDs(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, Title: "Go guide", Content: "guide to go"},
+		{ID: 2, Title: "Search guide", Content: "guide to search"},
+		{ID: 3, Title: "Pricing guide", Content: "guide to prices"},
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	engine := NewSearchEngine(&vectorMockClient{documents: documents, vectors: vec.FitTransform(documents)}, vec, nil)
+
+	response, err := engine.WithIDs(manticore.IDFilter{IDs: []int64{1, 2}, Exclude: []int64{1}}).VectorSearch("guide", 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Total != 1 || response.Documents[0].Document.ID != 2 {
+		t.Errorf("Expected only document 2, got %+v", response.Documents)
+	}
+}
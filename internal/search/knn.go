@@ -0,0 +1,61 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// MaxKNNK is the largest number of nearest neighbours an AI search may request
+const MaxKNNK = models.MaxKNNK
+
+// MaxKNNEf is the largest HNSW candidate list an AI search may request
+const MaxKNNEf = models.MaxKNNEf
+
+// ParseKNN parses the knn_k and knn_ef parameters, leaving a value 0 when its parameter is empty
+func ParseKNN(kParam, efParam string) (manticore.KNNOptions, error) {
+	var options manticore.KNNOptions
+	var errs validation.Errors
+	if kParam = strings.TrimSpace(kParam); kParam != "" {
+		k, err := strconv.Atoi(kParam)
+		if err != nil || k < 1 || k > MaxKNNK {
+			errs.Add("knn_k", validation.OutOfRange("knn_k", kParam, 1, MaxKNNK))
+		} else {
+			options.K = k
+		}
+	}
+	if efParam = strings.TrimSpace(efParam); efParam != "" {
+		ef, err := strconv.Atoi(efParam)
+		if err != nil || ef < 1 || ef > MaxKNNEf {
+			errs.Add("knn_ef", validation.OutOfRange("knn_ef", efParam, 1, MaxKNNEf))
+		} else {
+			options.Ef = ef
+		}
+	}
+	return options, errs.Err()
+}
+
+// WithKNN returns a copy of the engine whose AI searches use the k and ef of options, falling back
+// to the AI configuration for values of 0
+func (e *SearchEngine) WithKNN(options manticore.KNNOptions) *SearchEngine {
+	engine := *e
+	engine.knn = options
+	return &engine
+}
+
+// knnOptions returns the KNN tuning of AI searches: the engine's options over the configured ones
+func (e *SearchEngine) knnOptions() manticore.KNNOptions {
+	options := e.knn
+	if e.aiConfig != nil {
+		if options.K == 0 {
+			options.K = e.aiConfig.KNNK
+		}
+		if options.Ef == 0 {
+			options.Ef = e.aiConfig.KNNEf
+		}
+	}
+	return options
+}
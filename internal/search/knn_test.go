@@ -0,0 +1,38 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestParseKNN(t *testing.T) {
+	options, err := ParseKNN("50", " 200 ")
+	if err != nil || options != (manticore.KNNOptions{K: 50, Ef: 200}) {
+		t.Errorf("Expected k 50 and ef 200, got %+v, %v", options, err)
+	}
+	if options, err := ParseKNN("", ""); err != nil || !options.IsZero() {
+		t.Errorf("Expected no options, got %+v, %v", options, err)
+	}
+	for _, params := range [][2]string{{"0", ""}, {"1001", ""}, {"many", ""}, {"", "0"}, {"", "10001"}} {
+		if _, err := ParseKNN(params[0], params[1]); err == nil {
+			t.Errorf("Expected an error for knn_k=%q knn_ef=%q", params[0], params[1])
+		}
+	}
+}
+
+func TestKNNOptions(t *testing.T) {
+	engine := NewSearchEngine(&MockClient{}, nil, &models.AISearchConfig{Enabled: true, KNNK: 100, KNNEf: 64})
+	if options := engine.knnOptions(); options != (manticore.KNNOptions{K: 100, Ef: 64}) {
+		t.Errorf("Expected the configured options, got %+v", options)
+	}
+
+	// Request options override the configured ones
+	if options := engine.WithKNN(manticore.KNNOptions{Ef: 256}).knnOptions(); options != (manticore.KNNOptions{K: 100, Ef: 256}) {
+		t.Errorf("Expected ef 256 with the configured k, got %+v", options)
+	}
+	if options := NewSearchEngine(&MockClient{}, nil, nil).knnOptions(); !options.IsZero() {
+		t.Errorf("Expected no options without a configuration, got %+v", options)
+	}
+}
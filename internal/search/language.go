@@ -0,0 +1,79 @@
+package search
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/stopwords"
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// Sources of the language of a search, see models.SearchDebug
+const (
+	LanguageDetected  = "detected"
+	LanguageRequested = "requested"
+)
+
+// ParseLanguage parses the language parameter, the language whose stopwords a search uses instead
+// of the one detected from the query. It returns an empty language when the parameter is empty.
+func ParseLanguage(param string) (string, error) {
+	language := strings.ToLower(strings.TrimSpace(param))
+	if language == "" {
+		return "", nil
+	}
+	if languages := stopwords.Languages(); !slices.Contains(languages, language) {
+		return "", validation.InvalidValue("language", param, languages...)
+	}
+	return language, nil
+}
+
+// WithLanguage returns a copy of the engine that uses the stopwords of language instead of
+// detecting the language of each query, or detects it again when language is empty
+func (e *SearchEngine) WithLanguage(language string) *SearchEngine {
+	engine := *e
+	engine.language = language
+	return &engine
+}
+
+// WithDebug returns a copy of the engine that explains how queries were processed in the Debug
+// field of its responses
+func (e *SearchEngine) WithDebug(debug bool) *SearchEngine {
+	engine := *e
+	engine.debug = debug
+	return &engine
+}
+
+// routeLanguage returns a copy of the engine restricted to the stopwords of the query's language.
+// A query whose language can't be detected keeps the stopwords of every configured language.
+func (e *SearchEngine) routeLanguage(query string) (*SearchEngine, stopwords.Detection) {
+	detection := stopwords.Detection{Language: e.language, Confidence: 1}
+	if e.language == "" {
+		if e.stopwords == nil && !e.debug {
+			return e, stopwords.Detection{}
+		}
+		detection = stopwords.Detect(manticore.QueryTerms(query))
+	}
+	return e.WithStopwords(e.stopwords.ForLanguage(detection.Language)), detection
+}
+
+// QueryStopwords returns the stopwords of list in the language detected for query
+func QueryStopwords(query string, list *stopwords.List) *stopwords.List {
+	return list.ForLanguage(stopwords.Detect(manticore.QueryTerms(query)).Language)
+}
+
+// searchDebug describes how the engine, as routed for the query, processed it and scored results
+func (e *SearchEngine) searchDebug(query string, detection stopwords.Detection, results []models.SearchResult) *models.SearchDebug {
+	debug := &models.SearchDebug{
+		Language:           detection.Language,
+		LanguageConfidence: detection.Confidence,
+		LanguageSource:     LanguageDetected,
+		FullTextQuery:      FullTextQuery(query, e.stopwords),
+		Features:           e.featureDebug(query, results),
+	}
+	if e.language != "" {
+		debug.LanguageSource = LanguageRequested
+	}
+	return debug
+}
@@ -0,0 +1,13 @@
+package search
+
+import (
+	"github.com/ad/manticoresearch-go/internal/textnorm"
+)
+
+// WithNormalizer returns a copy of the engine that normalizes queries with normalizer before
+// every search, so "Café" finds documents written "cafe" in every mode
+func (e *SearchEngine) WithNormalizer(normalizer *textnorm.Normalizer) *SearchEngine {
+	engine := *e
+	engine.normalizer = normalizer
+	return &engine
+}
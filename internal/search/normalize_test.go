@@ -0,0 +1,41 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/textnorm"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+func TestNormalizedSearch(t *testing.T) {
+	normalizer, _ := textnorm.New(textnorm.DefaultSteps...)
+	documents := []*models.Document{
+		{ID: 1, Title: "Café culture", Content: "coffee houses"},
+		{ID: 2, Title: "Manticore", Content: "search indexing"},
+		{ID: 3, Title: "Python", Content: "python scripts"},
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	vec.SetNormalizer(normalizer)
+	client := &relaxMockClient{vectorMockClient: vectorMockClient{documents: documents, vectors: vec.FitTransform(documents)}, matches: "cafe"}
+	engine := NewSearchEngine(client, vec, nil).WithNormalizer(normalizer)
+
+	for _, query := range []string{"cafe", "CAFÉ", "Café"} {
+		response, err := engine.SearchContext(context.Background(), query, models.SearchModeVector, 1, 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.Total == 0 || response.Documents[0].Document.ID != 1 {
+			t.Errorf("Expected %q to find the café document, got %+v", query, response)
+		}
+
+		response, err = engine.SearchContext(context.Background(), query, models.SearchModeFullText, 1, 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.Total != 1 {
+			t.Errorf("Expected %q to be sent as cafe, got %v", query, client.queries)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+// vectorMockClient serves pre-computed document vectors
+type vectorMockClient struct {
+	MockClient
+	documents []*models.Document
+	vectors   [][]float64
+}
+
+func (c *vectorMockClient) GetAllDocumentsWithVectorsContext(ctx context.Context) ([]*models.Document, [][]float64, error) {
+	return c.documents, c.vectors, nil
+}
+
+func newVectorTestEngine(n int) *SearchEngine {
+	documents := make([]*models.Document, n)
+	for i := range documents {
+		documents[i] = &models.Document{ID: i + 1, Title: fmt.Sprintf("Document %d", i+1), Content: fmt.Sprintf("search term %d", i+1)}
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	vectors := vec.FitTransform(documents)
+
+	return NewSearchEngine(&vectorMockClient{documents: documents, vectors: vectors}, vec, nil)
+}
+
+func TestPageBounds(t *testing.T) {
+	tests := []struct {
+		n, page, pageSize int
+		start, end        int
+	}{
+		{25, 1, 10, 0, 10},
+		{25, 3, 10, 20, 25},
+		{25, 4, 10, 25, 25},
+	}
+	for _, tt := range tests {
+		if start, end := pageBounds(tt.n, tt.page, tt.pageSize); start != tt.start || end != tt.end {
+			t.Errorf("pageBounds(%d, %d, %d) = %d, %d; expected %d, %d", tt.n, tt.page, tt.pageSize, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestVectorSearchPagination(t *testing.T) {
+	engine := newVectorTestEngine(25)
+
+	response, err := engine.SearchContext(context.Background(), "search term", models.SearchModeVector, 3, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Documents) != 5 || response.Total != 25 {
+		t.Errorf("Expected 5 of 25 results on the last page, got %d of %d", len(response.Documents), response.Total)
+	}
+	if response.Pagination != models.PaginationClient {
+		t.Errorf("Expected client-side pagination, got %q", response.Pagination)
+	}
+}
+
+func TestHybridSearchDeepPage(t *testing.T) {
+	engine := newVectorTestEngine(100)
+
+	// The first pages must not exhaust the candidates of later pages
+	response, err := engine.SearchContext(context.Background(), "search term", models.SearchModeHybrid, 3, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Documents) != 10 {
+		t.Errorf("Expected a full third page, got %d results", len(response.Documents))
+	}
+	if response.TotalRelation != manticore.TotalRelationAtLeast {
+		t.Errorf("Expected the candidate total to be a lower bound, got %q", response.TotalRelation)
+	}
+}
+
+func TestHybridSearchRescoreWindow(t *testing.T) {
+	engine := newVectorTestEngine(100).WithRescoreWindow(50)
+
+	response, err := engine.SearchContext(context.Background(), "search term", models.SearchModeHybrid, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Total != 50 {
+		t.Errorf("Expected 50 fused candidates, got %d", response.Total)
+	}
+
+	// A window smaller than the requested pages still covers them
+	if window := newVectorTestEngine(0).WithRescoreWindow(5).candidateWindow(3, 10); window != 30 {
+		t.Errorf("Expected a window of 30, got %d", window)
+	}
+	if window := newVectorTestEngine(0).candidateWindow(3, 10); window != 60 {
+		t.Errorf("Expected the default window of 60, got %d", window)
+	}
+}
+
+func TestParseRescoreWindow(t *testing.T) {
+	if window, err := ParseRescoreWindow("", 40); window != 40 || err != nil {
+		t.Errorf("Expected the fallback, got %d, %v", window, err)
+	}
+	if window, err := ParseRescoreWindow("200", 40); window != 200 || err != nil {
+		t.Errorf("Expected 200, got %d, %v", window, err)
+	}
+	for _, param := range []string{"0", "1001", "many"} {
+		if _, err := ParseRescoreWindow(param, 40); err == nil {
+			t.Errorf("Expected an error for %q", param)
+		}
+	}
+}
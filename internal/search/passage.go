@@ -0,0 +1,93 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// semanticSnippets reports whether snippets of mode's results are the passages closest to the query
+// in meaning rather than the start of the content: results matched by meaning often don't contain
+// the query's words, so a leading excerpt rarely shows why they matched
+func semanticSnippets(mode models.SearchMode) bool {
+	return mode == models.SearchModeVector || mode.UsesAI()
+}
+
+// snippetFunc returns how projectFields makes the snippets of results found in mode for query: the
+// best matching passage of semantic modes, or the start of the content
+func (e *SearchEngine) snippetFunc(mode models.SearchMode, query string) func(content string) string {
+	leading := func(content string) string { return makeSnippet(content, snippetLength) }
+	if !hasField(e.fields, FieldSnippet) || !semanticSnippets(mode) || e.vectorizer == nil {
+		return leading
+	}
+
+	// Manticore embeds AI queries itself and doesn't return the embeddings, so sentences are
+	// compared with the TF-IDF vectors vector search uses
+	filtered := e.stopwords.Filter(query)
+	if filtered == "" {
+		return leading
+	}
+	queryVec := e.vectorizer.TransformQuery(filtered)
+	return func(content string) string {
+		sentences := splitSentences(content)
+		best, bestSimilarity := -1, 0.0
+		for i, sentence := range sentences {
+			if similarity := e.vectorizer.TextSimilarity(queryVec, sentence); similarity > bestSimilarity {
+				best, bestSimilarity = i, similarity
+			}
+		}
+		if best < 0 {
+			return leading(content)
+		}
+		return makePassage(sentences, best, snippetLength)
+	}
+}
+
+// splitSentences splits content into sentences at sentence-ending punctuation followed by a space
+// and at line breaks, collapsing whitespace
+func splitSentences(content string) []string {
+	var sentences []string
+	add := func(sentence string) {
+		if sentence = strings.Join(strings.Fields(sentence), " "); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+	}
+
+	runes := []rune(content)
+	start := 0
+	for i, r := range runes {
+		end := r == '\n'
+		if r == '.' || r == '!' || r == '?' || r == '…' {
+			end = i+1 == len(runes) || runes[i+1] == ' ' || runes[i+1] == '\n' || runes[i+1] == '\t'
+		}
+		if end {
+			add(string(runes[start : i+1]))
+			start = i + 1
+		}
+	}
+	add(string(runes[start:]))
+	return sentences
+}
+
+// makePassage returns the sentence at best followed by as many of the next sentences as fit in
+// maxChars, with "..." before it unless it starts the content. A longer sentence is cut like a
+// snippet.
+func makePassage(sentences []string, best, maxChars int) string {
+	passage := sentences[best]
+	if len([]rune(passage)) > maxChars {
+		passage = makeSnippet(passage, maxChars)
+	} else {
+		length := len([]rune(passage))
+		for _, sentence := range sentences[best+1:] {
+			length += 1 + len([]rune(sentence))
+			if length > maxChars {
+				break
+			}
+			passage += " " + sentence
+		}
+	}
+	if best > 0 {
+		passage = "..." + passage
+	}
+	return passage
+}
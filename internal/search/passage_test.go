@@ -0,0 +1,56 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+func TestSplitSentences(t *testing.T) {
+	sentences := splitSentences("# Setup\nInstall Go 1.21.3 first.  Then run it! Done?Not yet")
+	expected := []string{"# Setup", "Install Go 1.21.3 first.", "Then run it!", "Done?Not yet"}
+	if !reflect.DeepEqual(sentences, expected) {
+		t.Errorf("Expected %q, got %q", expected, sentences)
+	}
+}
+
+func TestMakePassage(t *testing.T) {
+	sentences := []string{"First one.", "Second one.", "Third one."}
+	if passage := makePassage(sentences, 1, 200); passage != "...Second one. Third one." {
+		t.Errorf("Expected the passage from the second sentence, got %q", passage)
+	}
+	if passage := makePassage(sentences, 0, 15); passage != "First one." {
+		t.Errorf("Expected only the first sentence, got %q", passage)
+	}
+	long := []string{strings.Repeat("word ", 100)}
+	if passage := makePassage(long, 0, 50); !strings.HasSuffix(passage, "...") || len([]rune(passage)) > 53 {
+		t.Errorf("Expected a cut passage, got %q", passage)
+	}
+}
+
+func TestSnippetFunc(t *testing.T) {
+	content := "Our company was founded in 2010. Shipping takes three days within the country. Returns are accepted for a month."
+	tfidf := vectorizer.NewTFIDFVectorizer()
+	tfidf.FitTransform([]*models.Document{
+		{Title: "About", Content: content},
+		{Title: "Contacts", Content: "Write to us by email."},
+	})
+	engine := NewSearchEngine(nil, tfidf, nil).WithFields([]string{FieldSnippet})
+
+	// Semantic modes show the sentence closest to the query
+	snippet := engine.snippetFunc(models.SearchModeAI, "how long is shipping")(content)
+	if snippet != "...Shipping takes three days within the country. Returns are accepted for a month." {
+		t.Errorf("Expected the shipping passage, got %q", snippet)
+	}
+
+	// Keyword modes and queries matching no sentence show the start of the content
+	if snippet := engine.snippetFunc(models.SearchModeFullText, "shipping")(content); !strings.HasPrefix(snippet, "Our company") {
+		t.Errorf("Expected the leading snippet, got %q", snippet)
+	}
+	if snippet := engine.snippetFunc(models.SearchModeVector, "unrelated")(content); !strings.HasPrefix(snippet, "Our company") {
+		t.Errorf("Expected the leading snippet, got %q", snippet)
+	}
+}
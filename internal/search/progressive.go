@@ -0,0 +1,214 @@
+package search
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// Defaults for the continuations of progressive searches
+const (
+	DefaultContinuationTTL  = time.Minute
+	DefaultMaxContinuations = 1000
+)
+
+// Refinement is the final ranking of a progressive search, computed in the background
+type Refinement struct {
+	done     chan struct{}
+	cancel   context.CancelFunc
+	response *models.SearchResponse
+	err      error
+}
+
+// Wait returns the final results once they are ready, or ctx's error if it ends first
+func (r *Refinement) Wait(ctx context.Context) (*models.SearchResponse, error) {
+	select {
+	case <-r.done:
+		return r.response, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Done reports whether the final results are ready
+func (r *Refinement) Done() bool {
+	select {
+	case <-r.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Cancel stops the background search if it is still running
+func (r *Refinement) Cancel() {
+	r.cancel()
+}
+
+// IsProgressiveMode reports whether a mode combines full-text search with a slower vector or AI
+// leg, so a full-text preview can be returned before the final ranking
+func IsProgressiveMode(mode models.SearchMode) bool {
+	return mode == models.SearchModeHybrid || mode.UsesAI()
+}
+
+// SearchProgressive starts the search of the requested mode in the background and returns
+// full-text results as a preview together with the pending refinement. The refinement is not bound
+// to ctx, so it completes after the caller returns; it is bounded by the mode's timeout instead.
+// Modes without a slower leg, and failed previews, return the final results with a nil refinement.
+func (e *SearchEngine) SearchProgressive(ctx context.Context, query string, mode models.SearchMode, page, pageSize int) (*models.SearchResponse, *Refinement, error) {
+	if !IsProgressiveMode(mode) {
+		response, err := e.SearchContext(ctx, query, mode, page, pageSize)
+		return response, nil, err
+	}
+
+	refineCtx, cancel := context.WithCancel(context.Background())
+	refinement := &Refinement{done: make(chan struct{}), cancel: cancel}
+	go func() {
+		defer close(refinement.done)
+		defer cancel()
+		refinement.response, refinement.err = e.SearchContext(refineCtx, query, mode, page, pageSize)
+	}()
+
+	// The preview is the full-text leg of the final ranking, so it is not relaxed
+	preview, err := e.WithRelaxation(nil).SearchContext(ctx, query, models.SearchModeFullText, page, pageSize)
+	if err != nil {
+		if ctx.Err() != nil {
+			refinement.Cancel()
+			return nil, nil, ctx.Err()
+		}
+		log.Printf("SearchProgressive: Full-text preview failed, waiting for %s results: %v", mode, err)
+		response, err := refinement.Wait(ctx)
+		if err != nil {
+			refinement.Cancel()
+		}
+		return response, nil, err
+	}
+	return preview, refinement, nil
+}
+
+// Continuations holds the refinements of progressive searches until their clients collect them.
+// Entries expire after ttl and the oldest are evicted beyond maxSize; expired and evicted searches
+// are cancelled. A nil *Continuations is valid and holds nothing.
+type Continuations struct {
+	mutex   sync.Mutex
+	maxSize int
+	ttl     time.Duration
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type continuationEntry struct {
+	token      string
+	refinement *Refinement
+	storedAt   time.Time
+}
+
+// NewContinuations creates a store holding up to maxSize refinements for at most ttl
+func NewContinuations(maxSize int, ttl time.Duration) *Continuations {
+	return &Continuations{
+		maxSize: maxSize,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Put stores a refinement and returns the token to collect it with
+func (c *Continuations) Put(refinement *Refinement) string {
+	if c == nil || refinement == nil {
+		return ""
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.expire()
+
+	token := newContinuationToken()
+	c.entries[token] = c.order.PushFront(continuationEntry{token: token, refinement: refinement, storedAt: time.Now()})
+	for c.order.Len() > c.maxSize {
+		c.remove(c.order.Back())
+	}
+	return token
+}
+
+// Get returns the refinement stored under token, if present and not expired
+func (c *Continuations) Get(token string) (*Refinement, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.expire()
+
+	element, ok := c.entries[token]
+	if !ok {
+		return nil, false
+	}
+	return element.Value.(continuationEntry).refinement, true
+}
+
+// Delete forgets a collected refinement
+func (c *Continuations) Delete(token string) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[token]; ok {
+		c.order.Remove(element)
+		delete(c.entries, token)
+	}
+}
+
+// EvictExpired cancels and removes the refinements older than the TTL, returning how many were
+// removed. Put and Get do so as well; this frees them while no progressive searches arrive.
+func (c *Continuations) EvictExpired() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	before := c.order.Len()
+	c.expire()
+	return before - c.order.Len()
+}
+
+// expire cancels and removes entries older than ttl (caller holds the lock)
+func (c *Continuations) expire() {
+	for element := c.order.Back(); element != nil; element = c.order.Back() {
+		if time.Since(element.Value.(continuationEntry).storedAt) <= c.ttl {
+			return
+		}
+		c.remove(element)
+	}
+}
+
+// remove cancels and removes an entry (caller holds the lock)
+func (c *Continuations) remove(element *list.Element) {
+	entry := element.Value.(continuationEntry)
+	entry.refinement.Cancel()
+	c.order.Remove(element)
+	delete(c.entries, entry.token)
+}
+
+// newContinuationToken returns an unguessable token, so clients cannot collect each other's results
+func newContinuationToken() string {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return hex.EncodeToString([]byte(time.Now().String()))
+	}
+	return hex.EncodeToString(token)
+}
@@ -0,0 +1,88 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestSearchProgressive(t *testing.T) {
+	aiResponse := &manticore.SearchResponse{Hits: manticore.SearchHits{
+		Total: 1,
+		Hits:  []manticore.SearchHit{{ID: 2, Score: 0.9, Source: map[string]interface{}{"title": "AI hit"}}},
+	}}
+	engine := NewSearchEngine(&streamClient{MockClient{aiSearchResponse: aiResponse}}, nil, &models.AISearchConfig{Model: "model", Enabled: true})
+
+	preview, refinement, err := engine.SearchProgressive(context.Background(), "query", models.SearchModeAI, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if refinement == nil {
+		t.Fatal("Expected a pending refinement for AI mode")
+	}
+	if preview.Mode != string(models.SearchModeFullText) || len(preview.Documents) != 1 || preview.Documents[0].Document.ID != 1 {
+		t.Errorf("Expected the full-text hit as preview, got %+v", preview)
+	}
+
+	final, err := refinement.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected refinement error: %v", err)
+	}
+	if !refinement.Done() || final.Mode != string(models.SearchModeAI) {
+		t.Errorf("Expected final AI results, got %+v", final)
+	}
+
+	response, refinement, err := engine.SearchProgressive(context.Background(), "query", models.SearchModeFullText, 1, 10)
+	if err != nil || refinement != nil || response == nil {
+		t.Errorf("Expected full-text mode to return final results directly, got %v, %v", refinement, err)
+	}
+}
+
+func TestContinuations(t *testing.T) {
+	newRefinement := func() (*Refinement, *bool) {
+		cancelled := false
+		return &Refinement{done: make(chan struct{}), cancel: func() { cancelled = true }}, &cancelled
+	}
+
+	c := NewContinuations(1, time.Minute)
+	first, firstCancelled := newRefinement()
+	token := c.Put(first)
+	if got, ok := c.Get(token); !ok || got != first {
+		t.Fatal("Expected the stored refinement")
+	}
+
+	second, _ := newRefinement()
+	c.Put(second)
+	if _, ok := c.Get(token); ok || !*firstCancelled {
+		t.Error("Expected the oldest refinement to be evicted and cancelled")
+	}
+
+	c = NewContinuations(10, time.Millisecond)
+	expired, expiredCancelled := newRefinement()
+	token = c.Put(expired)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get(token); ok || !*expiredCancelled {
+		t.Error("Expected the refinement to expire and be cancelled")
+	}
+
+	expired, expiredCancelled = newRefinement()
+	c.Put(expired)
+	time.Sleep(5 * time.Millisecond)
+	if evicted := c.EvictExpired(); evicted != 1 || !*expiredCancelled {
+		t.Errorf("Expected the expired refinement to be evicted and cancelled, got %d", evicted)
+	}
+
+	var nilContinuations *Continuations
+	if nilContinuations.EvictExpired() != 0 {
+		t.Error("Expected a nil store to evict nothing")
+	}
+	if nilContinuations.Put(first) != "" {
+		t.Error("Expected a nil store to hold nothing")
+	}
+	if _, ok := nilContinuations.Get(token); ok {
+		t.Error("Expected a nil store to hold nothing")
+	}
+}
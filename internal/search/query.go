@@ -0,0 +1,31 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// MaxQueryLength is the longest accepted search query in characters
+const MaxQueryLength = 1000
+
+// MaxPage is the deepest results page that can be requested
+const MaxPage = 1000
+
+// ValidateQuery checks a non-empty search query: it must fit in MaxQueryLength, contain no control
+// characters and have at least one word that can be searched for
+func ValidateQuery(query string) error {
+	if length := utf8.RuneCountInString(query); length > MaxQueryLength {
+		return validation.TooLong("query", MaxQueryLength)
+	}
+	if !utf8.ValidString(query) || strings.IndexFunc(query, unicode.IsControl) >= 0 {
+		return validation.InvalidCharacters("query")
+	}
+	if manticore.SanitizeQueryString(query) == "" {
+		return validation.NoTerms("query")
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{"words", "golang search", false},
+		{"query syntax", `"quick fox" | -slow`, false},
+		{"longest query", strings.Repeat("я", MaxQueryLength), false},
+		{"too long", strings.Repeat("a", MaxQueryLength+1), true},
+		{"control characters", "go\x00search", true},
+		{"invalid UTF-8", "go\xffsearch", true},
+		{"no words", `"()" | -`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateQuery(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// Fields of the sort parameter
+const (
+	SortRelevance = manticore.SortRelevance
+	SortUpdatedAt = manticore.SortUpdatedAt
+)
+
+// MaxSortKeys is the largest number of keys the sort parameter may list
+const MaxSortKeys = 4
+
+// ParseRecency parses the sort and since parameters. sort is a comma-separated list of
+// field[:asc|desc][:first|last] keys, see ParseSort; since is a Unix time, an RFC 3339 time or a
+// YYYY-MM-DD date.
+func ParseRecency(sortParam, sinceParam string) (manticore.RecencyOptions, error) {
+	var options manticore.RecencyOptions
+	var errs validation.Errors
+
+	order, err := ParseSort(sortParam)
+	errs.Add("sort", err)
+	options.Sort = order
+
+	since, err := parseSince(strings.TrimSpace(sinceParam))
+	errs.Add("since", err)
+	options.Since = since
+	return options, errs.Err()
+}
+
+// ParseSort parses the sort parameter, comma-separated keys each breaking the ties of the previous
+// ones. A key is a field of manticore.SortFields, optionally followed by :asc or :desc (the
+// default) and, for updated_at and indexed_at, :first or :last (the default) to place documents
+// without a value. "relevance" alone, or an empty parameter, keeps the relevance ranking.
+func ParseSort(param string) (manticore.SortOrder, error) {
+	var order manticore.SortOrder
+	seen := make(map[string]bool)
+	for _, value := range strings.Split(param, ",") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		parts := strings.Split(value, ":")
+		key := manticore.SortKey{Field: strings.TrimSpace(parts[0])}
+		if !isSortField(key.Field) {
+			return nil, validation.InvalidValue("sort", key.Field, manticore.SortFields...)
+		}
+		if seen[key.Field] {
+			return nil, validation.InvalidValue("sort", value)
+		}
+		seen[key.Field] = true
+
+		direction, missing := "", ""
+		for _, part := range parts[1:] {
+			switch part = strings.TrimSpace(part); part {
+			case "asc", "desc":
+				if direction != "" {
+					return nil, validation.InvalidFormat("sort", value, "field[:asc|desc][:first|last]")
+				}
+				direction = part
+			case "first", "last":
+				if missing != "" || !key.HasMissing() {
+					return nil, validation.InvalidFormat("sort", value, "field[:asc|desc][:first|last]")
+				}
+				missing = part
+			default:
+				return nil, validation.InvalidFormat("sort", value, "field[:asc|desc][:first|last]")
+			}
+		}
+		key.Ascending = direction == "asc"
+		key.MissingFirst = missing == "first"
+		order = append(order, key)
+	}
+
+	if len(order) > MaxSortKeys {
+		return nil, validation.OutOfRange("sort", strconv.Itoa(len(order)), 1, MaxSortKeys)
+	}
+	if len(order) == 1 && order[0] == (manticore.SortKey{Field: SortRelevance}) {
+		// Ranking by relevance is the default order
+		return nil, nil
+	}
+	return order, nil
+}
+
+func isSortField(field string) bool {
+	for _, name := range manticore.SortFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSince converts a since parameter to Unix seconds, zero when it is empty
+func parseSince(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+		return seconds, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Unix(), nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t.Unix(), nil
+	}
+	return 0, validation.InvalidFormat("since", value, "unix_time", "rfc3339", "YYYY-MM-DD")
+}
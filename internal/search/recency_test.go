@@ -0,0 +1,123 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+func TestParseRecency(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	tests := []struct {
+		sort, since string
+		expected    manticore.RecencyOptions
+		expectError bool
+	}{
+		{"", "", manticore.RecencyOptions{}, false},
+		{"relevance", "", manticore.RecencyOptions{}, false},
+		{"Updated_At", "", manticore.RecencyOptions{Sort: manticore.SortOrder{{Field: SortUpdatedAt}}}, false},
+		{"", "1709251200", manticore.RecencyOptions{Since: 1709251200}, false},
+		{"", "2024-03-01", manticore.RecencyOptions{Since: day}, false},
+		{"updated_at", "2024-03-01T00:00:00Z", manticore.RecencyOptions{Since: day, Sort: manticore.SortOrder{{Field: SortUpdatedAt}}}, false},
+		{"title", "", manticore.RecencyOptions{}, true},
+		{"", "yesterday", manticore.RecencyOptions{}, true},
+	}
+
+	for _, tt := range tests {
+		options, err := ParseRecency(tt.sort, tt.since)
+		if tt.expectError {
+			if err == nil {
+				t.Errorf("ParseRecency(%q, %q): expected error", tt.sort, tt.since)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(options, tt.expected) {
+			t.Errorf("ParseRecency(%q, %q) = %+v, %v; expected %+v", tt.sort, tt.since, options, err, tt.expected)
+		}
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		param    string
+		expected manticore.SortOrder
+	}{
+		{"", nil},
+		{"relevance:desc", nil},
+		{"updated_at:asc:first, relevance", manticore.SortOrder{{Field: "updated_at", Ascending: true, MissingFirst: true}, {Field: "relevance"}}},
+		{"indexed_at:last,id:asc", manticore.SortOrder{{Field: "indexed_at"}, {Field: "id", Ascending: true}}},
+		{"relevance:asc", manticore.SortOrder{{Field: "relevance", Ascending: true}}},
+	}
+	for _, tt := range tests {
+		order, err := ParseSort(tt.param)
+		if err != nil || !reflect.DeepEqual(order, tt.expected) {
+			t.Errorf("ParseSort(%q) = %v, %v; expected %v", tt.param, order, err, tt.expected)
+		}
+	}
+
+	for _, param := range []string{"title", "updated_at,updated_at:asc", "updated_at:up", "id:first", "updated_at:asc:desc", "updated_at,indexed_at,id,relevance,updated_at:asc"} {
+		if _, err := ParseSort(param); err == nil {
+			t.Errorf("ParseSort(%q): expected error", param)
+		}
+	}
+}
+
+func TestVectorSearchRecency(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, Title: "Old guide", Content: "guide guide guide", UpdatedAt: 100},
+		{ID: 2, Title: "New guide", Content: "guide for search", UpdatedAt: 300},
+		{ID: 3, Title: "Recent guide", Content: "guide for upgrades", UpdatedAt: 200},
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	engine := NewSearchEngine(&vectorMockClient{documents: documents, vectors: vec.FitTransform(documents)}, vec, nil)
+
+	response, err := engine.WithRecency(manticore.RecencyOptions{Since: 200, Sort: manticore.SortOrder{{Field: SortUpdatedAt}}}).VectorSearch("guide", 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Total != 2 {
+		t.Fatalf("Expected 2 documents updated since 200, got %d", response.Total)
+	}
+	if response.Documents[0].Document.ID != 2 || response.Documents[1].Document.ID != 3 {
+		t.Errorf("Expected newest first, got %d, %d", response.Documents[0].Document.ID, response.Documents[1].Document.ID)
+	}
+}
+
+func TestVectorSearchSortOrder(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, Title: "Old guide", Content: "guide guide guide", UpdatedAt: 100},
+		{ID: 2, Title: "Unknown guide", Content: "guide for search"},
+		{ID: 3, Title: "Recent guide", Content: "guide for upgrades", UpdatedAt: 200},
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	engine := NewSearchEngine(&vectorMockClient{documents: documents, vectors: vec.FitTransform(documents)}, vec, nil)
+
+	tests := map[string][]int{
+		"updated_at:asc":        {1, 3, 2},
+		"updated_at:asc:first":  {2, 1, 3},
+		"updated_at:desc":       {3, 1, 2},
+		"updated_at:desc:first": {2, 3, 1},
+	}
+	for param, expected := range tests {
+		order, err := ParseSort(param)
+		if err != nil {
+			t.Fatalf("ParseSort(%q): %v", param, err)
+		}
+		response, err := engine.WithRecency(manticore.RecencyOptions{Sort: order}).VectorSearch("guide", 1, 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids := make([]int, len(response.Documents))
+		for i, result := range response.Documents {
+			ids[i] = result.Document.ID
+		}
+		if !reflect.DeepEqual(ids, expected) {
+			t.Errorf("sort=%s: expected %v, got %v", param, expected, ids)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// MaxRegexLength is the longest pattern, in characters, the regex parameter may hold
+const MaxRegexLength = 200
+
+// ParseRegex parses the regex parameter, a field and an RE2 pattern separated by a colon such as
+// url:^https://go\.dev/, restricting results to documents whose field matches the pattern
+func ParseRegex(param string) (manticore.RegexFilter, error) {
+	if strings.TrimSpace(param) == "" {
+		return manticore.RegexFilter{}, nil
+	}
+	field, pattern, found := strings.Cut(param, ":")
+	if !found {
+		return manticore.RegexFilter{}, validation.InvalidFormat("regex", param, "field:pattern")
+	}
+	return NewRegexFilter("regex", field, pattern)
+}
+
+// NewRegexFilter validates a field and pattern given by the parameter named param and returns
+// their filter
+func NewRegexFilter(param, field, pattern string) (manticore.RegexFilter, error) {
+	field = strings.ToLower(strings.TrimSpace(field))
+	if _, ok := manticore.RegexFields[field]; !ok {
+		return manticore.RegexFilter{}, validation.InvalidValue(param, field, regexFieldNames()...)
+	}
+	if pattern == "" {
+		return manticore.RegexFilter{}, validation.Required(param)
+	}
+	if utf8.RuneCountInString(pattern) > MaxRegexLength {
+		return manticore.RegexFilter{}, validation.TooLong(param, MaxRegexLength)
+	}
+	filter, err := manticore.NewRegexFilter(field, pattern)
+	if err != nil {
+		return manticore.RegexFilter{}, validation.InvalidFormat(param, pattern, "re2")
+	}
+	return filter, nil
+}
+
+// regexFieldNames returns the fields regex filters may match, sorted
+func regexFieldNames() []string {
+	names := make([]string, 0, len(manticore.RegexFields))
+	for name := range manticore.RegexFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithRegex returns a copy of the engine whose searches only return documents matching filter
+func (e *SearchEngine) WithRegex(filter manticore.RegexFilter) *SearchEngine {
+	engine := *e
+	engine.regex = filter
+	engine.searchAdapter = e.searchAdapter.WithRegex(filter)
+	return &engine
+}
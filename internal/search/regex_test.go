@@ -0,0 +1,38 @@
+package search
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+func TestParseRegex(t *testing.T) {
+	filter, err := ParseRegex(`URL:^https://go\.dev/`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if filter.Field != "url" || filter.Pattern != `^https://go\.dev/` {
+		t.Errorf("Unexpected filter: %+v", filter)
+	}
+
+	if filter, err := ParseRegex(" "); err != nil || !filter.IsZero() {
+		t.Errorf("Expected an empty parameter to match every document, got %+v, %v", filter, err)
+	}
+
+	tests := map[string]string{
+		"^https":   validation.CodeInvalidFormat,
+		"title:go": validation.CodeInvalidValue,
+		"url:":     validation.CodeRequired,
+		"url:(go":  validation.CodeInvalidFormat,
+		"url:" + strings.Repeat("a", MaxRegexLength+1): validation.CodeTooLong,
+	}
+	for param, code := range tests {
+		_, err := ParseRegex(param)
+		var fieldErr *validation.FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Code != code || fieldErr.Field != "regex" {
+			t.Errorf("ParseRegex(%q) = %v, expected a %s error", param, err, code)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// Relaxation strategies, tried in the configured order when a basic or full-text search matches
+// nothing. Each builds on the previous ones.
+const (
+	RelaxOr         = "or"          // Match any of the words instead of all of them
+	RelaxDropRarest = "drop_rarest" // Drop the word the fewest documents contain
+	RelaxFuzzy      = "fuzzy"       // Also match indexed words one or two edits away from each word
+	RelaxVector     = "vector"      // Fall back to vector search
+)
+
+// DefaultRelaxation is the order of every strategy from the most to the least strict
+var DefaultRelaxation = []string{RelaxOr, RelaxDropRarest, RelaxFuzzy, RelaxVector}
+
+// maxFuzzyExpansions is the number of similar indexed words added for each query word
+const maxFuzzyExpansions = 3
+
+// ParseRelaxation parses a comma-separated list of relaxation strategies. "default" selects
+// DefaultRelaxation, and an empty value disables relaxation.
+func ParseRelaxation(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if value == "default" {
+		return append([]string(nil), DefaultRelaxation...), nil
+	}
+
+	var strategies []string
+	seen := make(map[string]bool)
+	for _, strategy := range strings.Split(value, ",") {
+		strategy = strings.TrimSpace(strategy)
+		switch strategy {
+		case RelaxOr, RelaxDropRarest, RelaxFuzzy, RelaxVector:
+		default:
+			return nil, fmt.Errorf("unknown relaxation strategy %q (use %s)", strategy, strings.Join(DefaultRelaxation, ", "))
+		}
+		if seen[strategy] {
+			return nil, fmt.Errorf("duplicate relaxation strategy %q", strategy)
+		}
+		seen[strategy] = true
+		strategies = append(strategies, strategy)
+	}
+	return strategies, nil
+}
+
+// RelaxationFromEnvironment reads SEARCH_RELAXATION, see ParseRelaxation
+func RelaxationFromEnvironment() ([]string, error) {
+	return ParseRelaxation(os.Getenv("SEARCH_RELAXATION"))
+}
+
+// WithRelaxation returns a copy of the engine whose basic and full-text searches retry with the
+// strategies, in order, when the query as written matches nothing
+func (e *SearchEngine) WithRelaxation(strategies []string) *SearchEngine {
+	engine := *e
+	engine.relaxation = strategies
+	return &engine
+}
+
+// relaxedQuery is the state of a query while strategies are applied to it
+type relaxedQuery struct {
+	words     []string
+	any       bool                // Words are alternatives
+	expansion map[string][]string // Similar indexed words added to a word
+}
+
+func (q *relaxedQuery) String() string {
+	terms := make([]string, len(q.words))
+	for i, word := range q.words {
+		if expansion := q.expansion[word]; len(expansion) > 0 {
+			terms[i] = "(" + strings.Join(append([]string{word}, expansion...), " | ") + ")"
+		} else {
+			terms[i] = word
+		}
+	}
+	if q.any {
+		return strings.Join(terms, " | ")
+	}
+	return strings.Join(terms, " ")
+}
+
+// relax retries a search that matched nothing with the configured strategies and returns the
+// first response with results, or nil when none has any. Only queries of plain words are relaxed;
+// phrases, negations, field limits and wildcards are taken as written.
+func (e *SearchEngine) relax(ctx context.Context, query string, mode models.SearchMode, page, pageSize int) *models.SearchResponse {
+	query = FullTextQuery(query, e.stopwords)
+	plain := manticore.QueryTerms(query)
+	if plain == "" || plain != manticore.SanitizeQueryString(query) {
+		return nil
+	}
+
+	relaxed := &relaxedQuery{words: strings.Fields(plain), expansion: make(map[string][]string)}
+	for _, strategy := range e.relaxation {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		var response *models.SearchResponse
+		var err error
+		switch strategy {
+		case RelaxVector:
+			if e.vectorizer == nil {
+				continue
+			}
+			response, err = e.vectorSearch(ctx, query, page, pageSize)
+			if response != nil {
+				response.Mode = string(mode)
+			}
+		default:
+			if !e.applyRelaxation(relaxed, strategy) {
+				continue
+			}
+			response, err = e.searchAdapter.FullTextSearchContext(ctx, relaxed.String(), page, pageSize)
+			if response != nil {
+				response.Mode = string(mode)
+				response.RelaxedQuery = relaxed.String()
+			}
+		}
+
+		if err != nil {
+			log.Printf("Relaxation: %s search for query='%s' failed: %v", strategy, query, err)
+			continue
+		}
+		log.Printf("Relaxation: %s search for query='%s' found %d results", strategy, query, response.Total)
+		if response.Total > 0 {
+			response.Relaxation = strategy
+			return response
+		}
+	}
+	return nil
+}
+
+// applyRelaxation applies a full-text strategy to q and reports whether it changed the query
+func (e *SearchEngine) applyRelaxation(q *relaxedQuery, strategy string) bool {
+	switch strategy {
+	case RelaxOr:
+		if q.any || len(q.words) < 2 {
+			return false
+		}
+		q.any = true
+		return true
+
+	case RelaxDropRarest:
+		if e.vectorizer == nil || len(q.words) < 2 {
+			return false
+		}
+		// Words outside the vocabulary count as the rarest, as most of them are in no document
+		rarest, rarestIDF := -1, 0.0
+		for i, word := range q.words {
+			idf, ok := e.vectorizer.IDF(word)
+			if !ok {
+				rarest = i
+				break
+			}
+			if rarest < 0 || idf > rarestIDF {
+				rarest, rarestIDF = i, idf
+			}
+		}
+		q.words = append(q.words[:rarest:rarest], q.words[rarest+1:]...)
+		return true
+
+	case RelaxFuzzy:
+		if e.vectorizer == nil {
+			return false
+		}
+		changed := false
+		for _, word := range q.words {
+			length := utf8.RuneCountInString(word)
+			if length < 3 || len(q.expansion[word]) > 0 {
+				continue
+			}
+			maxDistance := 1
+			if length > 5 {
+				maxDistance = 2
+			}
+			if similar := e.vectorizer.SimilarWords(word, maxDistance, maxFuzzyExpansions); len(similar) > 0 {
+				q.expansion[word] = similar
+				changed = true
+			}
+		}
+		return changed
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package search
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+// relaxMockClient records full-text queries and only matches the one in matches
+type relaxMockClient struct {
+	vectorMockClient
+	matches string
+	queries []string
+}
+
+func (c *relaxMockClient) SearchWithContext(ctx context.Context, request manticore.SearchRequest) (*manticore.SearchResponse, error) {
+	text := queryString(request.Query)
+	c.queries = append(c.queries, text)
+	if text != c.matches {
+		return &manticore.SearchResponse{}, nil
+	}
+	return &manticore.SearchResponse{Hits: manticore.SearchHits{Total: 1, Hits: []manticore.SearchHit{
+		{ID: 1, Score: 1, Source: map[string]interface{}{"title": "Golang search"}},
+	}}}, nil
+}
+
+// queryString finds the query_string of a search query, wherever filters nested it
+func queryString(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if text, ok := v["query_string"].(string); ok {
+			return text
+		}
+		for _, nested := range v {
+			if text := queryString(nested); text != "" {
+				return text
+			}
+		}
+	case []interface{}:
+		for _, nested := range v {
+			if text := queryString(nested); text != "" {
+				return text
+			}
+		}
+	case []map[string]interface{}:
+		for _, nested := range v {
+			if text := queryString(nested); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+func newRelaxTestEngine(matches string) (*SearchEngine, *relaxMockClient) {
+	documents := []*models.Document{
+		{ID: 1, Title: "Golang search", Content: "search engines in golang"},
+		{ID: 2, Title: "Manticore", Content: "manticore search indexing"},
+		{ID: 3, Title: "Python", Content: "python scripts"},
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	client := &relaxMockClient{vectorMockClient: vectorMockClient{documents: documents, vectors: vec.FitTransform(documents)}, matches: matches}
+	return NewSearchEngine(client, vec, nil), client
+}
+
+func TestParseRelaxation(t *testing.T) {
+	if strategies, err := ParseRelaxation(""); strategies != nil || err != nil {
+		t.Errorf("Expected relaxation to be disabled, got %v, %v", strategies, err)
+	}
+	if strategies, err := ParseRelaxation("default"); !reflect.DeepEqual(strategies, DefaultRelaxation) || err != nil {
+		t.Errorf("Expected the default strategies, got %v, %v", strategies, err)
+	}
+	if strategies, err := ParseRelaxation(" fuzzy, or "); !reflect.DeepEqual(strategies, []string{RelaxFuzzy, RelaxOr}) || err != nil {
+		t.Errorf("Unexpected strategies %v, %v", strategies, err)
+	}
+	for _, value := range []string{"or,or", "stem"} {
+		if _, err := ParseRelaxation(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestRelaxation(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		strategies []string
+		matches    string
+		relaxation string
+	}{
+		{"or", "golang rust", DefaultRelaxation, "golang | rust", RelaxOr},
+		{"drop rarest", "golang rust", []string{RelaxDropRarest}, "golang", RelaxDropRarest},
+		{"fuzzy", "manticor", []string{RelaxFuzzy}, "(manticor | manticore)", RelaxFuzzy},
+		{"vector", "golang search", []string{RelaxVector}, "", RelaxVector},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, _ := newRelaxTestEngine(tt.matches)
+			response, err := engine.WithRelaxation(tt.strategies).SearchContext(context.Background(), tt.query, models.SearchModeFullText, 1, 10)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if response.Relaxation != tt.relaxation || response.Total == 0 || response.Mode != string(models.SearchModeFullText) {
+				t.Errorf("Expected results of %s, got %+v", tt.relaxation, response)
+			}
+			if response.RelaxedQuery != tt.matches {
+				t.Errorf("Expected relaxed query %q, got %q", tt.matches, response.RelaxedQuery)
+			}
+		})
+	}
+}
+
+func TestRelaxationSkipsOperators(t *testing.T) {
+	engine, client := newRelaxTestEngine("golang | rust")
+	response, err := engine.WithRelaxation(DefaultRelaxation[:3]).SearchContext(context.Background(), `"golang rust"`, models.SearchModeFullText, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Total != 0 || response.Relaxation != "" || len(client.queries) != 1 {
+		t.Errorf("Expected the phrase to be searched as written only, got %+v after %v", response, client.queries)
+	}
+}
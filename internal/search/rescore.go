@@ -0,0 +1,60 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// MaxRescoreWindow is the largest number of candidates a hybrid search leg may contribute, which
+// is Manticore's default max_matches
+const MaxRescoreWindow = 1000
+
+// RescoreWindowFromEnvironment reads SEARCH_RESCORE_WINDOW, the default number of candidates each
+// leg of a hybrid search contributes before fusion. It returns 0, twice the results up to the
+// requested page, when the variable is unset.
+func RescoreWindowFromEnvironment() (int, error) {
+	valueStr := os.Getenv("SEARCH_RESCORE_WINDOW")
+	if valueStr == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil || value < 0 || value > MaxRescoreWindow {
+		return 0, fmt.Errorf("invalid SEARCH_RESCORE_WINDOW: %q (use 0 to %d)", valueStr, MaxRescoreWindow)
+	}
+	return value, nil
+}
+
+// ParseRescoreWindow parses the rescore_window parameter, returning fallback when it is empty
+func ParseRescoreWindow(param string, fallback int) (int, error) {
+	param = strings.TrimSpace(param)
+	if param == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(param)
+	if err != nil || value < 1 || value > MaxRescoreWindow {
+		return fallback, validation.OutOfRange("rescore_window", param, 1, MaxRescoreWindow)
+	}
+	return value, nil
+}
+
+// WithRescoreWindow returns a copy of the engine whose hybrid searches take window candidates
+// from each leg before fusion, or twice the results up to the requested page when window is 0
+func (e *SearchEngine) WithRescoreWindow(window int) *SearchEngine {
+	engine := *e
+	engine.rescoreWindow = window
+	return &engine
+}
+
+// candidateWindow returns the number of candidates each hybrid leg contributes. Both legs have to
+// supply candidates for every page up to the requested one, otherwise deep pages would be cut
+// from a window that only covers the first page.
+func (e *SearchEngine) candidateWindow(page, pageSize int) int {
+	if e.rescoreWindow <= 0 {
+		return page * pageSize * 2
+	}
+	return max(e.rescoreWindow, page*pageSize)
+}
@@ -0,0 +1,126 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/stopwords"
+)
+
+// Classes of queries, see ClassifyQuery
+const (
+	QueryClassKeyword         = "keyword"          // Keywords or a navigational query, matched literally
+	QueryClassNaturalLanguage = "natural_language" // A question or sentence, matched by meaning
+)
+
+// questionWords start questions in the languages with built-in stopwords
+var questionWords = map[string]bool{
+	"what": true, "how": true, "why": true, "when": true, "where": true, "who": true, "which": true,
+	"whose": true, "can": true, "could": true, "does": true, "do": true, "is": true, "are": true,
+	"should": true, "would": true,
+	"что": true, "как": true, "почему": true, "зачем": true, "где": true, "когда": true, "кто": true,
+	"какой": true, "какая": true, "какое": true, "какие": true, "сколько": true, "можно": true,
+	"чем": true, "куда": true, "откуда": true,
+}
+
+// functionWords are the stopwords of every built-in language, which sentences are full of and
+// keyword queries lack
+var functionWords, _ = stopwords.New(stopwords.Languages()...)
+
+// ClassifyQuery decides whether a query is keywords or a navigational query, which full-text
+// search matches best, or natural language, which AI search understands better. It returns the
+// class and the rule that decided it:
+//   - "operators": quotes or full-text operators make a keyword query
+//   - "navigational": a URL, path or file name makes a keyword query
+//   - "question": a question mark or a leading question word makes natural language
+//   - "sentence": four or more words, at least a quarter of them stopwords, make natural language
+//   - "keywords": any other query is keywords
+func ClassifyQuery(query string) (class, reason string) {
+	words := strings.Fields(query)
+	switch {
+	case strings.ContainsAny(query, `"*|@()`) || hasOperator(words):
+		return QueryClassKeyword, "operators"
+	case hasNavigational(words):
+		return QueryClassKeyword, "navigational"
+	case len(words) > 1 && (strings.HasSuffix(strings.TrimSpace(query), "?") || questionWords[strings.ToLower(trimPunctuation(words[0]))]):
+		return QueryClassNaturalLanguage, "question"
+	case len(words) >= 4 && functionWordShare(words) >= 0.25:
+		return QueryClassNaturalLanguage, "sentence"
+	}
+	return QueryClassKeyword, "keywords"
+}
+
+// hasOperator reports whether a word is required (+word) or excluded (-word)
+func hasOperator(words []string) bool {
+	for _, word := range words {
+		if len(word) > 1 && (word[0] == '+' || word[0] == '-') {
+			return true
+		}
+	}
+	return false
+}
+
+// hasNavigational reports whether a word is a URL, a path or a file name
+func hasNavigational(words []string) bool {
+	for _, word := range words {
+		word = trimPunctuation(word)
+		if strings.Contains(word, "://") || strings.HasPrefix(word, "www.") || strings.Contains(word, "/") || strings.Contains(word, `\`) {
+			return true
+		}
+		// A file name has a short extension after a dot, such as config.yaml
+		if dot := strings.LastIndex(word, "."); dot > 0 && dot < len(word)-1 && len(word)-dot-1 <= 4 && isLetters(word[dot+1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// functionWordShare returns the share of words that are stopwords
+func functionWordShare(words []string) float64 {
+	count := 0
+	for _, word := range words {
+		if functionWords.Contains(trimPunctuation(word)) {
+			count++
+		}
+	}
+	return float64(count) / float64(len(words))
+}
+
+// trimPunctuation removes the punctuation around a word
+func trimPunctuation(word string) string {
+	return strings.TrimFunc(word, unicode.IsPunct)
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// routeMode resolves mode=auto for query: full-text search for keywords, and AI hybrid search for
+// natural language, or hybrid search while AI search is unavailable. It also returns the class of
+// the query and the rule that decided it.
+func (e *SearchEngine) routeMode(query string) (mode models.SearchMode, class, reason string) {
+	class, reason = ClassifyQuery(query)
+	switch {
+	case class == QueryClassKeyword:
+		return models.SearchModeFullText, class, reason
+	case e.aiAvailable():
+		return models.SearchModeAIHybrid, class, reason
+	default:
+		return models.SearchModeHybrid, class, reason
+	}
+}
+
+// aiAvailable reports whether AI searches can run: AI search is enabled and the server, as far as
+// it is known, supports KNN search and Auto Embeddings
+func (e *SearchEngine) aiAvailable() bool {
+	if e.aiConfig == nil || !e.aiConfig.Enabled || e.client == nil {
+		return false
+	}
+	return e.client.GetCapabilities().RequireAISearch() == nil
+}
@@ -0,0 +1,83 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestClassifyQuery(t *testing.T) {
+	tests := []struct {
+		query  string
+		class  string
+		reason string
+	}{
+		{"golang http server", QueryClassKeyword, "keywords"},
+		{"timeout", QueryClassKeyword, "keywords"},
+		{`"exact phrase" search`, QueryClassKeyword, "operators"},
+		{"golang -deprecated", QueryClassKeyword, "operators"},
+		{"https://go.dev/doc", QueryClassKeyword, "navigational"},
+		{"docs/setup", QueryClassKeyword, "navigational"},
+		{"config.yaml", QueryClassKeyword, "navigational"},
+		{"how to configure the server", QueryClassNaturalLanguage, "question"},
+		{"server keeps restarting?", QueryClassNaturalLanguage, "question"},
+		{"Как настроить дизайн", QueryClassNaturalLanguage, "question"},
+		{"I want to change the colors of the form", QueryClassNaturalLanguage, "sentence"},
+		{"how", QueryClassKeyword, "keywords"},
+	}
+	for _, tt := range tests {
+		if class, reason := ClassifyQuery(tt.query); class != tt.class || reason != tt.reason {
+			t.Errorf("ClassifyQuery(%q) = %s (%s), expected %s (%s)", tt.query, class, reason, tt.class, tt.reason)
+		}
+	}
+}
+
+// capabilitiesClient reports the capabilities of a server
+type capabilitiesClient struct {
+	keywordMockClient
+	caps *manticore.Capabilities
+}
+
+func (c *capabilitiesClient) GetCapabilities() *manticore.Capabilities {
+	return c.caps
+}
+
+func TestRouteMode(t *testing.T) {
+	aiConfig := &models.AISearchConfig{Model: "sentence-transformers/all-MiniLM-L6-v2", Enabled: true}
+	tests := []struct {
+		name     string
+		engine   *SearchEngine
+		query    string
+		expected models.SearchMode
+	}{
+		{"keywords", NewSearchEngine(&MockClient{}, nil, aiConfig), "golang server", models.SearchModeFullText},
+		{"natural language", NewSearchEngine(&MockClient{}, nil, aiConfig), "how to configure the server", models.SearchModeAIHybrid},
+		{"AI disabled", NewSearchEngine(&MockClient{}, nil, nil), "how to configure the server", models.SearchModeHybrid},
+		{"no Auto Embeddings", NewSearchEngine(&capabilitiesClient{caps: &manticore.Capabilities{}}, nil, aiConfig), "how to configure the server", models.SearchModeHybrid},
+	}
+	for _, tt := range tests {
+		if mode, _, _ := tt.engine.routeMode(tt.query); mode != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, mode)
+		}
+	}
+}
+
+func TestSearchAuto(t *testing.T) {
+	engine := NewSearchEngine(&keywordMockClient{}, nil, nil).WithDebug(true)
+
+	response, err := engine.SearchContext(context.Background(), "keyword match", models.SearchModeAuto, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Mode != string(models.SearchModeFullText) || response.QueryClass != QueryClassKeyword || response.Debug.Routing != "keywords" {
+		t.Errorf("Expected a keyword query routed to full-text search, got mode %q, class %q, routing %q", response.Mode, response.QueryClass, response.Debug.Routing)
+	}
+
+	// Modes other than auto don't classify the query
+	response, err = engine.SearchContext(context.Background(), "keyword match", models.SearchModeFullText, 1, 10)
+	if err != nil || response.QueryClass != "" {
+		t.Errorf("Expected no query class, got %q, %v", response.QueryClass, err)
+	}
+}
@@ -0,0 +1,53 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// StatusAll selects documents of every status in ParseStatuses
+const StatusAll = "all"
+
+// ParseStatuses parses a comma-separated list of document statuses, or "all". An empty list
+// keeps the default of matching active documents only.
+func ParseStatuses(param string) ([]models.DocumentStatus, error) {
+	var statuses []models.DocumentStatus
+
+	for _, value := range strings.Split(param, ",") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if value == StatusAll {
+			return models.DocumentStatuses, nil
+		}
+
+		status, err := models.ParseDocumentStatus(value)
+		if err != nil {
+			allowed := []string{StatusAll}
+			for _, status := range models.DocumentStatuses {
+				allowed = append(allowed, string(status))
+			}
+			return nil, validation.InvalidValue("status", value, allowed...)
+		}
+		if !hasStatus(statuses, status) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+// hasStatus reports whether status is one of statuses. Documents without a status are active.
+func hasStatus(statuses []models.DocumentStatus, status models.DocumentStatus) bool {
+	if status == "" {
+		status = models.DocumentStatusActive
+	}
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+func TestParseStatuses(t *testing.T) {
+	tests := []struct {
+		param       string
+		expected    []models.DocumentStatus
+		expectError bool
+	}{
+		{"", nil, false},
+		{"archived", []models.DocumentStatus{models.DocumentStatusArchived}, false},
+		{"Active, deleted,active", []models.DocumentStatus{models.DocumentStatusActive, models.DocumentStatusDeleted}, false},
+		{"all", models.DocumentStatuses, false},
+		{"hidden", nil, true},
+	}
+
+	for _, tt := range tests {
+		statuses, err := ParseStatuses(tt.param)
+		if tt.expectError {
+			if err == nil {
+				t.Errorf("ParseStatuses(%q): expected error", tt.param)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(statuses, tt.expected) {
+			t.Errorf("ParseStatuses(%q) = %v, %v; expected %v", tt.param, statuses, err, tt.expected)
+		}
+	}
+}
+
+func TestVectorSearchStatusFilter(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, Title: "Archived guide", Content: "installation guide", Status: models.DocumentStatusArchived},
+		{ID: 2, Title: "Deleted guide", Content: "upgrade guide", Status: models.DocumentStatusDeleted},
+		{ID: 3, Title: "Active guide", Content: "search guide", Status: models.DocumentStatusActive},
+		{ID: 4, Title: "Pricing", Content: "plans and prices"}, // stored before statuses existed
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	engine := NewSearchEngine(&vectorMockClient{documents: documents, vectors: vec.FitTransform(documents)}, vec, nil)
+
+	response, err := engine.VectorSearch("guide", 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Total != 2 {
+		t.Errorf("Expected only the 2 active documents, got %d", response.Total)
+	}
+	for _, result := range response.Documents {
+		if result.Document.ID == 1 || result.Document.ID == 2 {
+			t.Errorf("Document %d should be hidden", result.Document.ID)
+		}
+	}
+
+	response, err = engine.WithStatuses([]models.DocumentStatus{models.DocumentStatusArchived}).VectorSearch("guide", 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Total != 1 || response.Documents[0].Document.ID != 1 {
+		t.Errorf("Expected only the archived document, got %+v", response.Documents)
+	}
+}
@@ -0,0 +1,32 @@
+package search
+
+import (
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/stopwords"
+)
+
+// WithStopwords returns a copy of the engine that removes the words of list from basic,
+// full-text and vector queries
+func (e *SearchEngine) WithStopwords(list *stopwords.List) *SearchEngine {
+	engine := *e
+	engine.stopwords = list
+	return &engine
+}
+
+// FullTextQuery removes the stopwords of list from a full-text query, keeping phrases as written.
+// A query made only of stopwords is kept, so it still finds documents containing all of them.
+func FullTextQuery(query string, list *stopwords.List) string {
+	if list == nil {
+		return query
+	}
+	return manticore.RemoveQueryWords(query, list.Contains)
+}
+
+// basicQuery removes the stopwords from the text of a basic search, which matches any of its
+// words, unless the text has no other words
+func (e *SearchEngine) basicQuery(query string) string {
+	if filtered := e.stopwords.Filter(query); filtered != "" {
+		return filtered
+	}
+	return query
+}
@@ -0,0 +1,94 @@
+package search
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/stopwords"
+)
+
+func TestFullTextQueryStopwords(t *testing.T) {
+	list, _ := stopwords.New("en")
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"what is the golang", "golang"},
+		{`"the golang" is fast`, `"the golang" fast`},
+		{"what is the", "what is the"},
+	}
+	for _, tt := range tests {
+		if got := FullTextQuery(tt.query, list); got != tt.expected {
+			t.Errorf("FullTextQuery(%q): expected %q, got %q", tt.query, tt.expected, got)
+		}
+	}
+	if got := FullTextQuery("what is the", nil); got != "what is the" {
+		t.Errorf("Expected no stopwords to keep the query, got %q", got)
+	}
+}
+
+func TestVectorSearchStopwords(t *testing.T) {
+	list, _ := stopwords.New("en")
+	engine, _ := newRelaxTestEngine("")
+	engine = engine.WithStopwords(list)
+
+	response, err := engine.SearchContext(context.Background(), "what is the", models.SearchModeVector, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Total != 0 {
+		t.Errorf("Expected a query of stopwords to match nothing, got %d results", response.Total)
+	}
+
+	response, err = engine.SearchContext(context.Background(), "what is golang", models.SearchModeVector, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Total == 0 || response.Documents[0].Document.ID != 1 {
+		t.Errorf("Expected the golang document first, got %+v", response)
+	}
+}
+
+func TestLanguageRouting(t *testing.T) {
+	list, _ := stopwords.New("en", "ru")
+	engine, client := newRelaxTestEngine("")
+	engine = engine.WithStopwords(list).WithDebug(true)
+
+	response, err := engine.SearchContext(context.Background(), "что это такое the golang", models.SearchModeFullText, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := models.SearchDebug{Language: "ru", LanguageConfidence: 0.625, LanguageSource: LanguageDetected, FullTextQuery: "такое the golang"}
+	if response.Debug == nil || !reflect.DeepEqual(*response.Debug, expected) {
+		t.Errorf("Expected debug %+v, got %+v", expected, response.Debug)
+	}
+	if client.queries[0] != "такое the golang" {
+		t.Errorf("Expected only Russian stopwords to be removed, got %q", client.queries[0])
+	}
+
+	response, err = engine.WithLanguage("en").SearchContext(context.Background(), "что это такое the golang", models.SearchModeFullText, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Debug.LanguageSource != LanguageRequested || response.Debug.FullTextQuery != "что это такое golang" {
+		t.Errorf("Expected the requested language's stopwords to be removed, got %+v", response.Debug)
+	}
+
+	if response, _ := engine.WithDebug(false).SearchContext(context.Background(), "golang", models.SearchModeFullText, 1, 10); response.Debug != nil {
+		t.Errorf("Expected no debug metadata, got %+v", response.Debug)
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	if language, err := ParseLanguage(" RU "); language != "ru" || err != nil {
+		t.Errorf("Expected ru, got %q, %v", language, err)
+	}
+	if language, err := ParseLanguage(""); language != "" || err != nil {
+		t.Errorf("Expected no language, got %q, %v", language, err)
+	}
+	if _, err := ParseLanguage("de"); err == nil {
+		t.Error("Expected an error for a language without stopwords")
+	}
+}
@@ -0,0 +1,39 @@
+package search
+
+import (
+	"context"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// Stages of a streamed search
+const (
+	StageFullText = "fulltext" // Preview from the full-text leg of a hybrid or AI search
+	StageFinal    = "final"    // Results of the requested mode
+)
+
+// StreamFunc receives the results of a stage; final is true for the last call
+type StreamFunc func(stage string, response *models.SearchResponse, final bool) error
+
+// SearchStream delivers the results of SearchProgressive as they become available: the
+// full-text preview of hybrid and AI searches first, then the final ranking. An error returned
+// by emit stops the search.
+func (e *SearchEngine) SearchStream(ctx context.Context, query string, mode models.SearchMode, page, pageSize int, emit StreamFunc) error {
+	response, refinement, err := e.SearchProgressive(ctx, query, mode, page, pageSize)
+	if err != nil {
+		return err
+	}
+	if refinement == nil {
+		return emit(StageFinal, response, true)
+	}
+	defer refinement.Cancel()
+
+	if err := emit(StageFullText, response, false); err != nil {
+		return err
+	}
+	final, err := refinement.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	return emit(StageFinal, final, true)
+}
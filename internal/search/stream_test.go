@@ -0,0 +1,69 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// streamClient answers full-text requests with a single hit
+type streamClient struct {
+	MockClient
+}
+
+func (c *streamClient) SearchWithContext(ctx context.Context, request manticore.SearchRequest) (*manticore.SearchResponse, error) {
+	return &manticore.SearchResponse{Hits: manticore.SearchHits{
+		Total: 1,
+		Hits:  []manticore.SearchHit{{ID: 1, Score: 2, Source: map[string]interface{}{"title": "Full-text hit"}}},
+	}}, nil
+}
+
+func TestSearchStream(t *testing.T) {
+	aiResponse := &manticore.SearchResponse{Hits: manticore.SearchHits{
+		Total: 1,
+		Hits:  []manticore.SearchHit{{ID: 2, Score: 0.9, Source: map[string]interface{}{"title": "AI hit"}}},
+	}}
+	client := &streamClient{MockClient{aiSearchResponse: aiResponse}}
+	engine := NewSearchEngine(client, nil, &models.AISearchConfig{Model: "model", Enabled: true})
+
+	tests := []struct {
+		mode   models.SearchMode
+		stages []string
+	}{
+		{models.SearchModeAI, []string{StageFullText, StageFinal}},
+		{models.SearchModeFullText, []string{StageFinal}},
+	}
+	for _, tt := range tests {
+		var stages []string
+		var finals []bool
+		err := engine.SearchStream(context.Background(), "query", tt.mode, 1, 10, func(stage string, response *models.SearchResponse, final bool) error {
+			stages = append(stages, stage)
+			finals = append(finals, final)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.mode, err)
+		}
+		if !reflect.DeepEqual(stages, tt.stages) || !finals[len(finals)-1] || (len(finals) > 1 && finals[0]) {
+			t.Errorf("%s: unexpected stages %v (final %v)", tt.mode, stages, finals)
+		}
+	}
+}
+
+func TestSearchStreamStopsOnEmitError(t *testing.T) {
+	engine := NewSearchEngine(&streamClient{MockClient{aiSearchResponse: &manticore.SearchResponse{}}}, nil, &models.AISearchConfig{Model: "model", Enabled: true})
+
+	stopped := errors.New("client went away")
+	calls := 0
+	err := engine.SearchStream(context.Background(), "query", models.SearchModeAI, 1, 10, func(stage string, response *models.SearchResponse, final bool) error {
+		calls++
+		return stopped
+	})
+	if !errors.Is(err, stopped) || calls != 1 {
+		t.Errorf("Expected the stream to stop after the preview, got %v after %d calls", err, calls)
+	}
+}
@@ -0,0 +1,60 @@
+package search
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// DefaultSuggestionLimit is the number of suggestions returned when the caller does not ask for a limit
+const DefaultSuggestionLimit = 5
+
+// MaxSuggestionLimit is the largest number of suggestions that can be requested
+const MaxSuggestionLimit = 50
+
+// Suggest returns document titles containing a word that starts with prefix, ignoring case.
+// Titles that start with the prefix come first, then the rest alphabetically.
+func Suggest(documents []*models.Document, prefix string, limit int) []string {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" || limit <= 0 {
+		return []string{}
+	}
+
+	type suggestion struct {
+		title   string
+		leading bool
+	}
+	var matches []suggestion
+	seen := make(map[string]bool)
+	for _, doc := range documents {
+		title := strings.TrimSpace(doc.Title)
+		lower := strings.ToLower(title)
+		if title == "" || seen[lower] {
+			continue
+		}
+		for _, word := range strings.Fields(lower) {
+			if strings.HasPrefix(word, prefix) {
+				seen[lower] = true
+				matches = append(matches, suggestion{title: title, leading: strings.HasPrefix(lower, prefix)})
+				break
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].leading != matches[j].leading {
+			return matches[i].leading
+		}
+		return strings.ToLower(matches[i].title) < strings.ToLower(matches[j].title)
+	})
+
+	result := make([]string, 0, limit)
+	for _, match := range matches {
+		if len(result) == limit {
+			break
+		}
+		result = append(result, match.title)
+	}
+	return result
+}
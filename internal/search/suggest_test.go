@@ -0,0 +1,36 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestSuggest(t *testing.T) {
+	documents := []*models.Document{
+		{Title: "Contact form"},
+		{Title: "Formatting guide"},
+		{Title: "Forms and validation"},
+		{Title: "forms and validation"},
+		{Title: "Search tips"},
+		{Title: ""},
+	}
+
+	tests := []struct {
+		prefix   string
+		limit    int
+		expected []string
+	}{
+		{"form", 5, []string{"Formatting guide", "Forms and validation", "Contact form"}},
+		{"FORM", 1, []string{"Formatting guide"}},
+		{"tip", 5, []string{"Search tips"}},
+		{"xyz", 5, []string{}},
+		{" ", 5, []string{}},
+	}
+	for _, tt := range tests {
+		if suggestions := Suggest(documents, tt.prefix, tt.limit); !reflect.DeepEqual(suggestions, tt.expected) {
+			t.Errorf("Suggest(%q, %d) = %v, expected %v", tt.prefix, tt.limit, suggestions, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// Values of the tags_mode parameter
+const (
+	TagsModeAny = "any"
+	TagsModeAll = "all"
+)
+
+// ParseTags parses the tags parameter, a comma-separated list of tags, and tags_mode, which is
+// "any" (the default) to match documents with at least one of the tags or "all" to require every tag
+func ParseTags(tagsParam, modeParam string) (manticore.TagFilter, error) {
+	var filter manticore.TagFilter
+
+	switch strings.ToLower(strings.TrimSpace(modeParam)) {
+	case "", TagsModeAny:
+	case TagsModeAll:
+		filter.MatchAll = true
+	default:
+		return filter, validation.InvalidValue("tags_mode", modeParam, TagsModeAny, TagsModeAll)
+	}
+
+	if tags := models.NormalizeTags(strings.Split(tagsParam, ",")); len(tags) > 0 {
+		filter.Tags = tags
+	}
+	return filter, nil
+}
+
+// tagFacets returns the tag facet of documents ranked by the service, nil when none are tagged
+func tagFacets(documents []*models.Document) map[string][]models.FacetValue {
+	values := manticore.TagFacet(documents)
+	if len(values) == 0 {
+		return nil
+	}
+	return map[string][]models.FacetValue{models.FacetTags: values}
+}
@@ -0,0 +1,64 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/manticore"
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		tags, mode  string
+		expected    manticore.TagFilter
+		expectError bool
+	}{
+		{"", "", manticore.TagFilter{}, false},
+		{"Go, search,go", "", manticore.TagFilter{Tags: []string{"go", "search"}}, false},
+		{"go,search", "ALL", manticore.TagFilter{Tags: []string{"go", "search"}, MatchAll: true}, false},
+		{"go", "any", manticore.TagFilter{Tags: []string{"go"}}, false},
+		{"go", "some", manticore.TagFilter{}, true},
+	}
+
+	for _, tt := range tests {
+		filter, err := ParseTags(tt.tags, tt.mode)
+		if tt.expectError {
+			if err == nil {
+				t.Errorf("ParseTags(%q, %q): expected error", tt.tags, tt.mode)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(filter, tt.expected) {
+			t.Errorf("ParseTags(%q, %q) = %+v, %v; expected %+v", tt.tags, tt.mode, filter, err, tt.expected)
+		}
+	}
+}
+
+func TestVectorSearchTags(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, Title: "Go guide", Content: "guide to go", Tags: []string{"go"}},
+		{ID: 2, Title: "Search guide", Content: "guide to search", Tags: []string{"go", "search"}},
+		{ID: 3, Title: "Pricing guide", Content: "guide to prices"},
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	engine := NewSearchEngine(&vectorMockClient{documents: documents, vectors: vec.FitTransform(documents)}, vec, nil)
+
+	response, err := engine.VectorSearch("guide", 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []models.FacetValue{{Value: "go", Count: 2}, {Value: "search", Count: 1}}
+	if !reflect.DeepEqual(response.Facets[models.FacetTags], expected) {
+		t.Errorf("Unexpected tag facet: %v", response.Facets)
+	}
+
+	response, err = engine.WithTags(manticore.TagFilter{Tags: []string{"go", "search"}, MatchAll: true}).VectorSearch("guide", 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Total != 1 || response.Documents[0].Document.ID != 2 {
+		t.Errorf("Expected only the document with both tags, got %+v", response.Documents)
+	}
+}
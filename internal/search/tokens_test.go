@@ -0,0 +1,39 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/vectorizer"
+)
+
+func TestCodeTokenVectorSearch(t *testing.T) {
+	documents := []*models.Document{
+		{ID: 1, Title: "HTTP servers", Content: "import net/http to serve requests"},
+		{ID: 2, Title: "Networking", Content: "the net package and http clients"},
+		{ID: 3, Title: "Release notes", Content: "go v1.21.3 fixes the linker"},
+		{ID: 4, Title: "Release notes", Content: "go v1.20.1 fixes the compiler"},
+	}
+	vec := vectorizer.NewTFIDFVectorizer()
+	client := &vectorMockClient{documents: documents, vectors: vec.FitTransform(documents)}
+	engine := NewSearchEngine(client, vec, nil)
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{"net/http", 1},
+		{"v1.21.3", 3},
+		{"v1.20.1", 4},
+	}
+	for _, tt := range tests {
+		response, err := engine.SearchContext(context.Background(), tt.query, models.SearchModeVector, 1, 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.Total == 0 || response.Documents[0].Document.ID != tt.expected {
+			t.Errorf("Expected %q to rank document %d first, got %+v", tt.query, tt.expected, response.Documents)
+		}
+	}
+}
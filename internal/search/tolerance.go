@@ -0,0 +1,47 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ad/manticoresearch-go/internal/validation"
+)
+
+// SpellToleranceFromEnvironment reads SEARCH_SPELL_TOLERANCE, whether basic searches also match
+// substrings and misspellings of indexed words by default. It returns false when unset.
+func SpellToleranceFromEnvironment() (bool, error) {
+	value := os.Getenv("SEARCH_SPELL_TOLERANCE")
+	if value == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid SEARCH_SPELL_TOLERANCE: %q (use true or false)", value)
+	}
+	return enabled, nil
+}
+
+// ParseSpellTolerance parses the tolerant parameter, returning fallback when it is empty
+func ParseSpellTolerance(param string, fallback bool) (bool, error) {
+	param = strings.TrimSpace(param)
+	if param == "" {
+		return fallback, nil
+	}
+	enabled, err := strconv.ParseBool(param)
+	if err != nil {
+		return fallback, validation.InvalidValue("tolerant", param, "true", "false")
+	}
+	return enabled, nil
+}
+
+// WithSpellTolerance returns a copy of the engine whose basic searches also match the indexed
+// words containing each query word, such as "search" for "earc", and on Manticore 7.0 or later
+// the words up to two edits away, such as "search" for "serch". Documents indexed before the
+// schema indexed infixes only match exactly until they are reindexed.
+func (e *SearchEngine) WithSpellTolerance(enabled bool) *SearchEngine {
+	engine := *e
+	engine.searchAdapter = e.searchAdapter.WithSpellTolerance(enabled)
+	return &engine
+}
@@ -0,0 +1,41 @@
+package search
+
+import "testing"
+
+func TestParseSpellTolerance(t *testing.T) {
+	tests := []struct {
+		param    string
+		fallback bool
+		expected bool
+		valid    bool
+	}{
+		{"", true, true, true},
+		{"", false, false, true},
+		{"true", false, true, true},
+		{" false ", true, false, true},
+		{"maybe", false, false, false},
+	}
+	for _, tt := range tests {
+		enabled, err := ParseSpellTolerance(tt.param, tt.fallback)
+		if (err == nil) != tt.valid || enabled != tt.expected {
+			t.Errorf("ParseSpellTolerance(%q, %v) = %v, %v", tt.param, tt.fallback, enabled, err)
+		}
+	}
+}
+
+func TestSpellToleranceFromEnvironment(t *testing.T) {
+	t.Setenv("SEARCH_SPELL_TOLERANCE", "")
+	if enabled, err := SpellToleranceFromEnvironment(); enabled || err != nil {
+		t.Errorf("Expected spell tolerance to be off by default, got %v, %v", enabled, err)
+	}
+
+	t.Setenv("SEARCH_SPELL_TOLERANCE", "true")
+	if enabled, err := SpellToleranceFromEnvironment(); !enabled || err != nil {
+		t.Errorf("Expected spell tolerance, got %v, %v", enabled, err)
+	}
+
+	t.Setenv("SEARCH_SPELL_TOLERANCE", "sometimes")
+	if _, err := SpellToleranceFromEnvironment(); err == nil {
+		t.Error("Expected an error for an invalid value")
+	}
+}
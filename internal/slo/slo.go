@@ -0,0 +1,388 @@
+package slo
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults of Config
+const (
+	DefaultWindow        = 5 * time.Minute
+	DefaultCheckInterval = time.Minute
+	DefaultAlertAfter    = 3
+)
+
+// maxSamples bounds the latencies kept per search mode; older ones give way within the window
+const maxSamples = 1000
+
+// Percentiles that objectives can set a budget for
+var percentiles = []int{50, 95, 99}
+
+// Objective is a latency budget of a search mode: the Percentile latency of its searches within
+// the window should stay under Threshold
+type Objective struct {
+	Mode       string
+	Percentile int // 50, 95 or 99
+	Threshold  time.Duration
+}
+
+// String returns the objective in the form of SEARCH_SLO
+func (o Objective) String() string {
+	return fmt.Sprintf("%s:p%d:%v", o.Mode, o.Percentile, o.Threshold)
+}
+
+// Config configures latency tracking. Every CheckInterval the latencies of the last Window are
+// compared to the objectives, and a mode over budget at AlertAfter checks in a row is burning.
+type Config struct {
+	Objectives    []Objective
+	Window        time.Duration
+	CheckInterval time.Duration
+	AlertAfter    int
+}
+
+// State is the burn state of an objective
+type State string
+
+// Objective states
+const (
+	StateOK         State = "ok"          // within budget, or no searches in the window
+	StateOverBudget State = "over_budget" // over budget at the last check, not yet long enough to alert
+	StateBurning    State = "burning"     // over budget at AlertAfter checks in a row
+)
+
+// Latencies are the latency percentiles of the searches of a mode within the window
+type Latencies struct {
+	Count int
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+// percentile returns the latency of percentile p
+func (l Latencies) percentile(p int) time.Duration {
+	switch p {
+	case 50:
+		return l.P50
+	case 95:
+		return l.P95
+	default:
+		return l.P99
+	}
+}
+
+// ObjectiveStatus is an objective and its state at the last check
+type ObjectiveStatus struct {
+	Objective
+	Latency         time.Duration // the percentile at the last check
+	State           State
+	ConsecutiveOver int       // checks in a row over budget
+	BurningSince    time.Time // zero unless burning
+}
+
+// ModeStatus is the latencies of a search mode and the state of its objectives
+type ModeStatus struct {
+	Mode       string
+	Latencies  Latencies
+	Objectives []ObjectiveStatus
+}
+
+// Alert reports an objective starting or ending to burn
+type Alert struct {
+	Mode       string    `json:"mode"`
+	Percentile int       `json:"percentile"`
+	Threshold  string    `json:"threshold"`
+	Latency    string    `json:"latency"`
+	Searches   int       `json:"searches"` // within the window
+	Window     string    `json:"window"`
+	Burning    bool      `json:"burning"`
+	Since      time.Time `json:"since"` // when the objective started burning
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// sample is the latency of a search
+type sample struct {
+	at      time.Time
+	latency time.Duration
+}
+
+// Tracker records the latencies of searches by mode and checks them against latency objectives.
+// Latencies are tracked for every mode, objectives or not. A nil *Tracker is valid and records
+// nothing.
+type Tracker struct {
+	config Config
+	now    func() time.Time
+	notify func(Alert)
+
+	mu         sync.Mutex
+	samples    map[string][]sample
+	objectives []ObjectiveStatus
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewTracker creates a tracker; notify, which may be nil, is called when an objective starts or
+// ends to burn
+func NewTracker(config Config, notify func(Alert)) *Tracker {
+	if config.Window <= 0 {
+		config.Window = DefaultWindow
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = DefaultCheckInterval
+	}
+	if config.AlertAfter <= 0 {
+		config.AlertAfter = DefaultAlertAfter
+	}
+	t := &Tracker{
+		config:  config,
+		now:     time.Now,
+		notify:  notify,
+		samples: make(map[string][]sample),
+		stop:    make(chan struct{}),
+	}
+	for _, objective := range config.Objectives {
+		t.objectives = append(t.objectives, ObjectiveStatus{Objective: objective, State: StateOK})
+	}
+	return t
+}
+
+// Record adds the latency of a search of mode
+func (t *Tracker) Record(mode string, latency time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	samples := append(t.samples[mode], sample{at: t.now(), latency: latency})
+	if len(samples) > maxSamples {
+		samples = samples[len(samples)-maxSamples:]
+	}
+	t.samples[mode] = samples
+}
+
+// latencies drops the samples of mode older than the window and returns the percentiles of the
+// rest. The caller holds t.mu.
+func (t *Tracker) latencies(mode string) Latencies {
+	cutoff := t.now().Add(-t.config.Window)
+	samples := t.samples[mode]
+	first := 0
+	for first < len(samples) && samples[first].at.Before(cutoff) {
+		first++
+	}
+	samples = samples[first:]
+	t.samples[mode] = samples
+	if len(samples) == 0 {
+		return Latencies{}
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	for i, s := range samples {
+		sorted[i] = s.latency
+	}
+	slices.Sort(sorted)
+	at := func(q float64) time.Duration {
+		return sorted[min(int(float64(len(sorted))*q), len(sorted)-1)]
+	}
+	return Latencies{Count: len(sorted), P50: at(0.5), P95: at(0.95), P99: at(0.99)}
+}
+
+// Check compares the latencies of the window to every objective, notifying the objectives that
+// start or end to burn. An objective of a mode without searches in the window is within budget.
+func (t *Tracker) Check() {
+	if t == nil {
+		return
+	}
+	var alerts []Alert
+
+	t.mu.Lock()
+	now := t.now()
+	for i := range t.objectives {
+		status := &t.objectives[i]
+		latencies := t.latencies(status.Mode)
+		status.Latency = latencies.percentile(status.Percentile)
+		wasBurning := status.State == StateBurning
+
+		if latencies.Count > 0 && status.Latency > status.Threshold {
+			status.ConsecutiveOver++
+			status.State = StateOverBudget
+			if status.ConsecutiveOver >= t.config.AlertAfter {
+				status.State = StateBurning
+				if !wasBurning {
+					status.BurningSince = now
+				}
+			}
+		} else {
+			status.ConsecutiveOver = 0
+			status.State = StateOK
+		}
+
+		if burning := status.State == StateBurning; burning != wasBurning {
+			alerts = append(alerts, Alert{
+				Mode:       status.Mode,
+				Percentile: status.Percentile,
+				Threshold:  status.Threshold.String(),
+				Latency:    status.Latency.String(),
+				Searches:   latencies.Count,
+				Window:     t.config.Window.String(),
+				Burning:    burning,
+				Since:      status.BurningSince,
+				Timestamp:  now,
+			})
+			if !burning {
+				status.BurningSince = time.Time{}
+			}
+		}
+	}
+	t.mu.Unlock()
+
+	for _, alert := range alerts {
+		if alert.Burning {
+			log.Printf("[SLO] %s searches over budget: p%d latency %s above %s for %d checks",
+				alert.Mode, alert.Percentile, alert.Latency, alert.Threshold, t.config.AlertAfter)
+		} else {
+			log.Printf("[SLO] %s searches back within budget: p%d latency %s", alert.Mode, alert.Percentile, alert.Latency)
+		}
+		if t.notify != nil {
+			t.notify(alert)
+		}
+	}
+}
+
+// Status returns the latencies of every mode searched within the window, and of every mode with
+// an objective, sorted by mode, with the state of its objectives at the last check
+func (t *Tracker) Status() []ModeStatus {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	modes := make(map[string]*ModeStatus)
+	for mode := range t.samples {
+		modes[mode] = &ModeStatus{Mode: mode}
+	}
+	for _, objective := range t.objectives {
+		if modes[objective.Mode] == nil {
+			modes[objective.Mode] = &ModeStatus{Mode: objective.Mode}
+		}
+		modes[objective.Mode].Objectives = append(modes[objective.Mode].Objectives, objective)
+	}
+
+	result := make([]ModeStatus, 0, len(modes))
+	for mode, status := range modes {
+		status.Latencies = t.latencies(mode)
+		if status.Latencies.Count == 0 && len(status.Objectives) == 0 {
+			delete(t.samples, mode)
+			continue
+		}
+		result = append(result, *status)
+	}
+	slices.SortFunc(result, func(a, b ModeStatus) int { return strings.Compare(a.Mode, b.Mode) })
+	return result
+}
+
+// Start checks the objectives every CheckInterval in the background; it does nothing without
+// objectives
+func (t *Tracker) Start() {
+	if t == nil || len(t.objectives) == 0 {
+		return
+	}
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		ticker := time.NewTicker(t.config.CheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				t.Check()
+			case <-t.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the background checks
+func (t *Tracker) Close() {
+	if t == nil {
+		return
+	}
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+	t.wg.Wait()
+}
+
+// ParseObjectives parses SEARCH_SLO, a comma-separated list of mode:percentile:threshold
+// objectives such as "hybrid:p95:300ms,ai:p99:2s"
+func ParseObjectives(value string) ([]Objective, error) {
+	var objectives []Objective
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid objective %q (expected mode:percentile:threshold, such as hybrid:p95:300ms)", entry)
+		}
+		percentile, err := strconv.Atoi(strings.TrimPrefix(parts[1], "p"))
+		if err != nil || !slices.Contains(percentiles, percentile) {
+			return nil, fmt.Errorf("invalid percentile %q of objective %q (expected p50, p95 or p99)", parts[1], entry)
+		}
+		threshold, err := time.ParseDuration(parts[2])
+		if err != nil || threshold <= 0 {
+			return nil, fmt.Errorf("invalid threshold %q of objective %q", parts[2], entry)
+		}
+		objectives = append(objectives, Objective{Mode: parts[0], Percentile: percentile, Threshold: threshold})
+	}
+	return objectives, nil
+}
+
+// LoadConfigFromEnvironment reads SEARCH_SLO, SEARCH_SLO_WINDOW, SEARCH_SLO_CHECK_INTERVAL and
+// SEARCH_SLO_ALERT_AFTER
+func LoadConfigFromEnvironment() (Config, error) {
+	config := Config{Window: DefaultWindow, CheckInterval: DefaultCheckInterval, AlertAfter: DefaultAlertAfter}
+
+	objectives, err := ParseObjectives(os.Getenv("SEARCH_SLO"))
+	if err != nil {
+		return config, fmt.Errorf("invalid SEARCH_SLO: %v", err)
+	}
+	config.Objectives = objectives
+
+	durations := []struct {
+		name   string
+		target *time.Duration
+	}{
+		{"SEARCH_SLO_WINDOW", &config.Window},
+		{"SEARCH_SLO_CHECK_INTERVAL", &config.CheckInterval},
+	}
+	for _, d := range durations {
+		if value := os.Getenv(d.name); value != "" {
+			duration, err := time.ParseDuration(value)
+			if err != nil || duration <= 0 {
+				return config, fmt.Errorf("invalid %s: %q", d.name, value)
+			}
+			*d.target = duration
+		}
+	}
+	if value := os.Getenv("SEARCH_SLO_ALERT_AFTER"); value != "" {
+		checks, err := strconv.Atoi(value)
+		if err != nil || checks <= 0 {
+			return config, fmt.Errorf("invalid SEARCH_SLO_ALERT_AFTER: %q", value)
+		}
+		config.AlertAfter = checks
+	}
+	return config, nil
+}
@@ -0,0 +1,133 @@
+package slo
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestTracker returns a tracker whose clock is advanced by the returned function
+func newTestTracker(config Config, notify func(Alert)) (*Tracker, func(time.Duration)) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(config, notify)
+	tracker.now = func() time.Time { return now }
+	return tracker, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestTrackerLatencies(t *testing.T) {
+	tracker, advance := newTestTracker(Config{Window: time.Minute}, nil)
+	for i := 1; i <= 100; i++ {
+		tracker.Record("hybrid", time.Duration(i)*time.Millisecond)
+	}
+	tracker.Record("basic", 5*time.Millisecond)
+
+	status := tracker.Status()
+	if len(status) != 2 || status[0].Mode != "basic" || status[1].Mode != "hybrid" {
+		t.Fatalf("Expected basic and hybrid sorted by mode, got %+v", status)
+	}
+	hybrid := status[1].Latencies
+	if hybrid.Count != 100 || hybrid.P50 != 51*time.Millisecond || hybrid.P95 != 96*time.Millisecond || hybrid.P99 != 100*time.Millisecond {
+		t.Errorf("Unexpected hybrid latencies: %+v", hybrid)
+	}
+
+	advance(2 * time.Minute)
+	if status := tracker.Status(); len(status) != 0 {
+		t.Errorf("Expected latencies older than the window to be dropped, got %+v", status)
+	}
+}
+
+func TestTrackerBurning(t *testing.T) {
+	var alerts []Alert
+	config := Config{
+		Objectives: []Objective{{Mode: "ai", Percentile: 95, Threshold: 100 * time.Millisecond}},
+		Window:     time.Minute,
+		AlertAfter: 2,
+	}
+	tracker, advance := newTestTracker(config, func(alert Alert) { alerts = append(alerts, alert) })
+
+	state := func() ObjectiveStatus { return tracker.Status()[0].Objectives[0] }
+
+	tracker.Check()
+	if state().State != StateOK {
+		t.Errorf("Expected a mode without searches to be within budget, got %s", state().State)
+	}
+
+	tracker.Record("ai", 300*time.Millisecond)
+	tracker.Check()
+	if state().State != StateOverBudget || len(alerts) != 0 {
+		t.Errorf("Expected over budget without an alert after one check, got %s and %d alerts", state().State, len(alerts))
+	}
+	tracker.Check()
+	if state().State != StateBurning || len(alerts) != 1 || !alerts[0].Burning || alerts[0].Latency != "300ms" {
+		t.Fatalf("Expected a burning alert after two checks, got %s and %+v", state().State, alerts)
+	}
+	tracker.Check()
+	if len(alerts) != 1 {
+		t.Errorf("Expected a single alert while burning, got %d", len(alerts))
+	}
+
+	advance(2 * time.Minute)
+	tracker.Record("ai", 20*time.Millisecond)
+	tracker.Check()
+	if state().State != StateOK || len(alerts) != 2 || alerts[1].Burning || alerts[1].Since.IsZero() {
+		t.Errorf("Expected a recovery alert, got %s and %+v", state().State, alerts)
+	}
+	if !state().BurningSince.IsZero() {
+		t.Error("Expected BurningSince to be cleared after recovery")
+	}
+}
+
+func TestNilTracker(t *testing.T) {
+	var tracker *Tracker
+	tracker.Record("basic", time.Second)
+	tracker.Check()
+	tracker.Start()
+	tracker.Close()
+	if status := tracker.Status(); status != nil {
+		t.Errorf("Expected no status, got %+v", status)
+	}
+}
+
+func TestParseObjectives(t *testing.T) {
+	objectives, err := ParseObjectives("hybrid:p95:300ms, ai:99:2s")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []Objective{{Mode: "hybrid", Percentile: 95, Threshold: 300 * time.Millisecond}, {Mode: "ai", Percentile: 99, Threshold: 2 * time.Second}}
+	if len(objectives) != 2 || objectives[0] != expected[0] || objectives[1] != expected[1] {
+		t.Errorf("Expected %+v, got %+v", expected, objectives)
+	}
+
+	for _, value := range []string{"hybrid:p95", "hybrid:p90:300ms", "hybrid:p95:fast", ":p95:1s", "hybrid:p95:-1s"} {
+		if _, err := ParseObjectives(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestLoadConfigFromEnvironment(t *testing.T) {
+	t.Setenv("SEARCH_SLO", "vector:p50:50ms")
+	t.Setenv("SEARCH_SLO_WINDOW", "10m")
+	t.Setenv("SEARCH_SLO_CHECK_INTERVAL", "30s")
+	t.Setenv("SEARCH_SLO_ALERT_AFTER", "5")
+
+	config, err := LoadConfigFromEnvironment()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(config.Objectives) != 1 || config.Window != 10*time.Minute || config.CheckInterval != 30*time.Second || config.AlertAfter != 5 {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+
+	for name, value := range map[string]string{
+		"SEARCH_SLO":             "vector:p42:50ms",
+		"SEARCH_SLO_WINDOW":      "0s",
+		"SEARCH_SLO_ALERT_AFTER": "never",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, err := LoadConfigFromEnvironment(); err == nil {
+				t.Errorf("Expected an error for %s=%s", name, value)
+			}
+		})
+	}
+}
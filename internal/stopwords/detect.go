@@ -0,0 +1,83 @@
+package stopwords
+
+import (
+	"strings"
+	"unicode"
+)
+
+// scripts holds the alphabet of each language with built-in stopwords
+var scripts = map[string]*unicode.RangeTable{
+	"en": unicode.Latin,
+	"ru": unicode.Cyrillic,
+}
+
+// Detection is the language detected for a text
+type Detection struct {
+	Language   string  // Empty when no language could be told apart
+	Confidence float64 // Share of the evidence for Language, from 0 to 1
+}
+
+// Detect guesses the language of text among those with built-in stopwords. Each word written in a
+// language's alphabet counts for it, and each of its stopwords counts twice, which tells apart
+// languages sharing an alphabet. Text without letters, or with as much evidence for two
+// languages, has no language.
+func Detect(text string) Detection {
+	scores := make(map[string]float64)
+	total := 0.0
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		for language, script := range scripts {
+			score := 0.0
+			if inScript(word, script) {
+				score++
+			}
+			if score > 0 && builtinWords(language)[word] {
+				score++
+			}
+			scores[language] += score
+			total += score
+		}
+	}
+
+	var detection Detection
+	best, tied := 0.0, false
+	for language, score := range scores {
+		switch {
+		case score > best:
+			detection.Language, best, tied = language, score, false
+		case score == best && score > 0:
+			tied = true
+		}
+	}
+	if best == 0 || tied {
+		return Detection{}
+	}
+	detection.Confidence = best / total
+	return detection
+}
+
+// inScript reports whether every letter of word belongs to script
+func inScript(word string, script *unicode.RangeTable) bool {
+	for _, r := range word {
+		if !unicode.Is(script, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// builtinLists holds the list of each language with built-in stopwords, used by Detect
+var builtinLists = func() map[string]*List {
+	lists := make(map[string]*List, len(builtin))
+	for language := range builtin {
+		lists[language], _ = New(language)
+	}
+	return lists
+}()
+
+// builtinWords returns the built-in stopwords of language
+func builtinWords(language string) map[string]bool {
+	if list := builtinLists[language]; list != nil {
+		return list.words
+	}
+	return nil
+}
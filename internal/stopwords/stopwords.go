@@ -0,0 +1,123 @@
+package stopwords
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultLanguages are the languages whose stopwords are removed unless SEARCH_STOPWORDS is set
+var DefaultLanguages = []string{"en", "ru"}
+
+// List is a set of stopwords, words so common that they say nothing about what a query is
+// looking for. A nil *List is valid and contains no words.
+type List struct {
+	languages  []string
+	words      map[string]bool
+	byLanguage map[string]*List // The stopwords of each language of the list
+}
+
+// New creates the list of the stopwords of languages, such as "en" and "ru"
+func New(languages ...string) (*List, error) {
+	l := &List{words: make(map[string]bool), byLanguage: make(map[string]*List)}
+	for _, language := range languages {
+		words, ok := builtin[language]
+		if !ok {
+			return nil, fmt.Errorf("no stopwords for language %q (use %s)", language, strings.Join(Languages(), ", "))
+		}
+		if l.byLanguage[language] != nil {
+			continue
+		}
+		single := &List{languages: []string{language}, words: make(map[string]bool)}
+		for _, word := range strings.Fields(words) {
+			l.words[word] = true
+			single.words[word] = true
+		}
+		single.byLanguage = map[string]*List{language: single}
+		l.languages = append(l.languages, language)
+		l.byLanguage[language] = single
+	}
+	return l, nil
+}
+
+// FromEnvironment creates the list of the comma-separated languages of SEARCH_STOPWORDS, or of
+// DefaultLanguages when it is unset. It returns nil, which removes no words, for "none".
+func FromEnvironment() (*List, error) {
+	value, ok := os.LookupEnv("SEARCH_STOPWORDS")
+	if !ok {
+		return New(DefaultLanguages...)
+	}
+	value = strings.TrimSpace(value)
+	if value == "" || value == "none" {
+		return nil, nil
+	}
+
+	var languages []string
+	for _, language := range strings.Split(value, ",") {
+		if language = strings.ToLower(strings.TrimSpace(language)); language != "" {
+			languages = append(languages, language)
+		}
+	}
+	return New(languages...)
+}
+
+// Languages returns the languages with built-in stopwords
+func Languages() []string {
+	languages := make([]string, 0, len(builtin))
+	for language := range builtin {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// ForLanguage returns the stopwords of language, or nil, which removes no words, when the list
+// doesn't cover it. An empty language, one that couldn't be detected, returns the whole list.
+func (l *List) ForLanguage(language string) *List {
+	if l == nil || language == "" {
+		return l
+	}
+	return l.byLanguage[language]
+}
+
+// Contains reports whether word is a stopword, ignoring case
+func (l *List) Contains(word string) bool {
+	if l == nil {
+		return false
+	}
+	return l.words[strings.ToLower(word)]
+}
+
+// Filter returns the words of text that are not stopwords, separated by spaces. Text made only
+// of stopwords gives an empty string.
+func (l *List) Filter(text string) string {
+	if l == nil {
+		return text
+	}
+	words := strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	kept := words[:0]
+	for _, word := range words {
+		if !l.Contains(word) {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// builtin holds the stopwords of each supported language
+var builtin = map[string]string{
+	"en": `a about above after again against all am an and any are as at be because been before
+		being below between both but by can could did do does doing down during each few for from
+		further had has have having he her here hers herself him himself his how i if in into is it
+		its itself just me more most my myself no nor not now of off on once only or other our ours
+		ourselves out over own same she should so some such than that the their theirs them
+		themselves then there these they this those through to too under until up very was we were
+		what when where which while who whom why will with would you your yours yourself yourselves`,
+	"ru": `а без более бы был была были было быть в вам вас весь во вот все всего всех вы где да даже
+		для до его ее её если есть еще ещё же за здесь и из или им их к как ко когда кто ли либо мне
+		может мы на над надо наш не него нее неё нет ни них но ну о об однако он она они оно от очень
+		по под при с со так также такой там те тем то того тоже той только том ты у уже хотя чего
+		чей чем что чтобы чье чья эта эти это я`,
+}
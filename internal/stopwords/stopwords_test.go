@@ -0,0 +1,85 @@
+package stopwords
+
+import (
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	list, err := New("en", "ru")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"what is the", ""},
+		{"What is the Go scheduler?", "Go scheduler"},
+		{"как работает поиск", "работает поиск"},
+		{"manticore", "manticore"},
+	}
+	for _, tt := range tests {
+		if got := list.Filter(tt.text); got != tt.expected {
+			t.Errorf("Filter(%q): expected %q, got %q", tt.text, tt.expected, got)
+		}
+	}
+
+	var none *List
+	if got := none.Filter("what is the"); got != "what is the" || none.Contains("the") {
+		t.Errorf("Expected a nil list to keep every word, got %q", got)
+	}
+}
+
+func TestFromEnvironment(t *testing.T) {
+	t.Setenv("SEARCH_STOPWORDS", " EN ")
+	list, err := FromEnvironment()
+	if err != nil || !list.Contains("the") || list.Contains("и") {
+		t.Errorf("Expected English stopwords only, got %+v, %v", list, err)
+	}
+
+	t.Setenv("SEARCH_STOPWORDS", "none")
+	if list, err := FromEnvironment(); list != nil || err != nil {
+		t.Errorf("Expected no stopwords, got %+v, %v", list, err)
+	}
+
+	t.Setenv("SEARCH_STOPWORDS", "en,xx")
+	if _, err := FromEnvironment(); err == nil {
+		t.Error("Expected an error for an unknown language")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text     string
+		language string
+	}{
+		{"what is the go scheduler", "en"},
+		{"как работает планировщик", "ru"},
+		{"что такое goroutine", "ru"},
+		{"2024 42", ""},
+		{"go поиск", ""},
+	}
+	for _, tt := range tests {
+		detection := Detect(tt.text)
+		if detection.Language != tt.language {
+			t.Errorf("Detect(%q): expected %q, got %+v", tt.text, tt.language, detection)
+		}
+		if tt.language != "" && (detection.Confidence <= 0.5 || detection.Confidence > 1) {
+			t.Errorf("Detect(%q): unexpected confidence %v", tt.text, detection.Confidence)
+		}
+	}
+}
+
+func TestForLanguage(t *testing.T) {
+	list, _ := New("en", "ru")
+	if en := list.ForLanguage("en"); !en.Contains("the") || en.Contains("и") {
+		t.Error("Expected English stopwords only")
+	}
+	if list.ForLanguage("") != list {
+		t.Error("Expected an undetected language to keep every stopword")
+	}
+	english, _ := New("en")
+	if ru := english.ForLanguage("ru"); ru != nil {
+		t.Errorf("Expected no stopwords for a language outside the list, got %+v", ru)
+	}
+}
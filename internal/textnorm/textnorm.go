@@ -0,0 +1,142 @@
+package textnorm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalization steps, applied in this order whatever order they are configured in
+const (
+	StepNFC        = "nfc"        // Compose characters, so "e" followed by a combining accent is "é"
+	StepNFKC       = "nfkc"       // Also replace compatibility characters, such as "ﬁ" with "fi" and "Ａ" with "A"
+	StepCaseFold   = "casefold"   // Lowercase
+	StepDiacritics = "diacritics" // Remove accents from Latin letters, so "café" is "cafe"
+)
+
+// DefaultSteps are the steps applied unless TEXT_NORMALIZATION is set
+var DefaultSteps = []string{StepNFKC, StepCaseFold, StepDiacritics}
+
+// Normalizer rewrites text into a canonical form, so differently written forms of a word match.
+// A nil *Normalizer is valid and leaves text unchanged.
+type Normalizer struct {
+	steps      []string
+	form       norm.Form
+	composes   bool
+	caseFold   bool
+	diacritics bool
+}
+
+// New creates a normalizer applying steps
+func New(steps ...string) (*Normalizer, error) {
+	n := &Normalizer{}
+	seen := make(map[string]bool)
+	for _, step := range steps {
+		if seen[step] {
+			return nil, fmt.Errorf("duplicate normalization step %q", step)
+		}
+		seen[step] = true
+		switch step {
+		case StepNFC, StepNFKC:
+			if n.composes {
+				return nil, fmt.Errorf("normalization steps %q and %q exclude each other", StepNFC, StepNFKC)
+			}
+			n.composes = true
+			n.form = norm.NFC
+			if step == StepNFKC {
+				n.form = norm.NFKC
+			}
+		case StepCaseFold:
+			n.caseFold = true
+		case StepDiacritics:
+			n.diacritics = true
+		default:
+			return nil, fmt.Errorf("unknown normalization step %q (use %s, %s, %s or %s)", step, StepNFC, StepNFKC, StepCaseFold, StepDiacritics)
+		}
+		n.steps = append(n.steps, step)
+	}
+	return n, nil
+}
+
+// Parse creates a normalizer from a comma-separated list of steps. "default" selects DefaultSteps,
+// and "none" or an empty value returns nil, which leaves text unchanged.
+func Parse(value string) (*Normalizer, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "", "none":
+		return nil, nil
+	case "default":
+		return New(DefaultSteps...)
+	}
+
+	var steps []string
+	for _, step := range strings.Split(value, ",") {
+		steps = append(steps, strings.TrimSpace(step))
+	}
+	return New(steps...)
+}
+
+// FromEnvironment reads TEXT_NORMALIZATION, see Parse, returning DefaultSteps when it is unset
+func FromEnvironment() (*Normalizer, error) {
+	value, ok := os.LookupEnv("TEXT_NORMALIZATION")
+	if !ok {
+		return New(DefaultSteps...)
+	}
+	return Parse(value)
+}
+
+// Steps returns the configured steps
+func (n *Normalizer) Steps() []string {
+	if n == nil {
+		return nil
+	}
+	return append([]string(nil), n.steps...)
+}
+
+// String applies every step to text
+func (n *Normalizer) String(text string) string {
+	if n == nil {
+		return text
+	}
+	if n.composes {
+		text = n.form.String(text)
+	}
+	if n.caseFold {
+		text = strings.ToLower(text)
+	}
+	if n.diacritics {
+		text = stripDiacritics(text)
+	}
+	return text
+}
+
+// Compose only applies the nfc or nfkc step, for text stored as written, such as documents sent
+// to Manticore, whose tokenizer lowercases and removes accents from Latin letters itself
+func (n *Normalizer) Compose(text string) string {
+	if n == nil || !n.composes {
+		return text
+	}
+	return n.form.String(text)
+}
+
+// stripDiacritics removes the combining marks that follow Latin letters and composes the rest
+// again. Marks of other scripts are kept, as they make distinct letters there, such as "й" and "и".
+func stripDiacritics(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	latin := false
+	for _, r := range norm.NFD.String(text) {
+		if unicode.Is(unicode.Mn, r) {
+			if latin {
+				continue
+			}
+		} else {
+			latin = unicode.Is(unicode.Latin, r)
+		}
+		b.WriteRune(r)
+	}
+	return norm.NFC.String(b.String())
+}
@@ -0,0 +1,68 @@
+package textnorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	n, err := New(DefaultSteps...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Café", "cafe"},
+		{"Cafe\u0301", "cafe"},
+		{"ＧＯ ﬁle", "go file"},
+		{"Ёлка и йогурт", "ёлка и йогурт"},
+		{"Straße Crème brûlée", "straße creme brulee"},
+	}
+	for _, tt := range tests {
+		if got := n.String(tt.text); got != tt.expected {
+			t.Errorf("String(%q): expected %q, got %q", tt.text, tt.expected, got)
+		}
+	}
+
+	var none *Normalizer
+	if got := none.String("Café"); got != "Café" {
+		t.Errorf("Expected a nil normalizer to keep the text, got %q", got)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	n, _ := New(StepNFC, StepCaseFold, StepDiacritics)
+	if got := n.Compose("Cafe\u0301"); got != "Café" {
+		t.Errorf("Expected only composition, got %q", got)
+	}
+	n, _ = New(StepCaseFold)
+	if got := n.Compose("Cafe\u0301"); got != "Cafe\u0301" {
+		t.Errorf("Expected the text as written without a form, got %q", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	if n, err := Parse("none"); n != nil || err != nil {
+		t.Errorf("Expected no normalization, got %+v, %v", n, err)
+	}
+	if n, err := Parse("default"); err != nil || !reflect.DeepEqual(n.Steps(), DefaultSteps) {
+		t.Errorf("Expected the default steps, got %v, %v", n.Steps(), err)
+	}
+	if n, err := Parse(" NFC, casefold "); err != nil || !reflect.DeepEqual(n.Steps(), []string{StepNFC, StepCaseFold}) {
+		t.Errorf("Unexpected steps %v, %v", n.Steps(), err)
+	}
+	for _, value := range []string{"nfc,nfkc", "casefold,casefold", "stem"} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestFromEnvironment(t *testing.T) {
+	t.Setenv("TEXT_NORMALIZATION", "nfkc")
+	if n, err := FromEnvironment(); err != nil || n.String("Café") != "Café" {
+		t.Errorf("Expected composition only, got %v", err)
+	}
+}
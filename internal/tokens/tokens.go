@@ -0,0 +1,90 @@
+package tokens
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// runesPerToken is the letters or digits of a word estimated to make up one token
+const runesPerToken = 4
+
+// Estimate returns the approximate number of tokens an embedding model splits text into. Subword
+// tokenizers split long and rare words into several tokens, so a word counts one token per four
+// letters or digits and every other symbol but spaces counts one. It errs on the high side for
+// English, so text estimated within a limit fits it.
+func Estimate(text string) int {
+	count := 0
+	for _, segment := range segments(text) {
+		count += segment.tokens
+	}
+	return count
+}
+
+// Truncate returns the beginning of text estimated at up to max tokens, cut between words, and
+// whether anything was cut. A first word longer than max is cut inside the word. A max of 0 or
+// less keeps text whole.
+func Truncate(text string, max int) (string, bool) {
+	if max <= 0 {
+		return text, false
+	}
+	count := 0
+	for _, segment := range segments(text) {
+		if count+segment.tokens <= max {
+			count += segment.tokens
+			continue
+		}
+		if count == 0 {
+			return cutRunes(text[segment.start:], max*runesPerToken), true
+		}
+		return strings.TrimRightFunc(text[:segment.start], unicode.IsSpace), true
+	}
+	return text, false
+}
+
+// segment is a word or a symbol of text
+type segment struct {
+	start  int // byte offset in text
+	tokens int
+}
+
+// segments splits text into words of letters and digits and single symbols, skipping spaces
+func segments(text string) []segment {
+	var result []segment
+	wordStart, wordRunes := -1, 0
+	endWord := func() {
+		if wordStart >= 0 {
+			result = append(result, segment{start: wordStart, tokens: (wordRunes + runesPerToken - 1) / runesPerToken})
+			wordStart, wordRunes = -1, 0
+		}
+	}
+	for i, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			if wordStart < 0 {
+				wordStart = i
+			}
+			wordRunes++
+		case unicode.IsSpace(r):
+			endWord()
+		default:
+			endWord()
+			result = append(result, segment{start: i, tokens: 1})
+		}
+	}
+	endWord()
+	return result
+}
+
+// cutRunes returns the first n runes of text
+func cutRunes(text string, n int) string {
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	i := 0
+	for ; n > 0; n-- {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	return text[:i]
+}
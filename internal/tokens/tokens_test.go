@@ -0,0 +1,42 @@
+package tokens
+
+import "testing"
+
+func TestEstimate(t *testing.T) {
+	tests := map[string]int{
+		"":                        0,
+		"   ":                     0,
+		"search":                  2,
+		"how to search":           4,
+		"what's new?":             5,
+		"поиск документов":        5,
+		"internationalization 42": 6,
+	}
+	for text, expected := range tests {
+		if count := Estimate(text); count != expected {
+			t.Errorf("Estimate(%q) = %d, expected %d", text, count, expected)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text      string
+		max       int
+		expected  string
+		truncated bool
+	}{
+		{"how to search documents", 0, "how to search documents", false},
+		{"how to search documents", 10, "how to search documents", false},
+		{"how to search documents", 5, "how to search", true},
+		{"how to, search", 2, "how to", true},
+		{"internationalization", 2, "internat", true},
+		{"поиск документов", 2, "поиск", true},
+	}
+	for _, test := range tests {
+		result, truncated := Truncate(test.text, test.max)
+		if result != test.expected || truncated != test.truncated {
+			t.Errorf("Truncate(%q, %d) = %q, %v, expected %q, %v", test.text, test.max, result, truncated, test.expected, test.truncated)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Metric is a kind of accounted usage
+type Metric string
+
+// Accounted metrics
+const (
+	Searches         Metric = "searches"
+	IndexedDocuments Metric = "indexed_documents"
+	EmbeddingCalls   Metric = "embedding_calls"
+)
+
+// Metrics lists every accounted metric
+var Metrics = []Metric{Searches, IndexedDocuments, EmbeddingCalls}
+
+// AnonymousKey accounts the usage of callers without an API key, when API keys are not configured
+const AnonymousKey = "anonymous"
+
+// Uses counts uses of each metric
+type Uses map[Metric]int64
+
+// Quotas are the daily limits of each metric per key; a metric without a positive quota is unlimited
+type Quotas map[Metric]int64
+
+// QuotaError is returned when a key has used up its daily quota of a metric
+type QuotaError struct {
+	Key      string
+	Metric   Metric
+	Limit    int64
+	Used     int64
+	ResetsAt time.Time
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("daily %s quota of %d exceeded for key %s (used %d, resets at %s)",
+		e.Metric, e.Limit, e.Key, e.Used, e.ResetsAt.Format(time.RFC3339))
+}
+
+// Report is the usage of a key on the current day
+type Report struct {
+	Key      string
+	Day      time.Time // Start of the UTC day
+	ResetsAt time.Time
+	Used     Uses
+	Quotas   Quotas
+}
+
+// Tracker accounts usage per key for the current UTC day and enforces daily quotas. Usage is kept
+// in memory and starts over at midnight UTC and when the service restarts.
+// A nil *Tracker is valid, records nothing and allows everything.
+type Tracker struct {
+	mutex  sync.Mutex
+	quotas Quotas
+	now    func() time.Time
+	day    time.Time
+	usage  map[string]Uses
+}
+
+// NewTracker creates a tracker enforcing quotas
+func NewTracker(quotas Quotas) *Tracker {
+	return &Tracker{quotas: quotas, now: time.Now, usage: make(map[string]Uses)}
+}
+
+// quotaVariables maps each metric to the environment variable of its quota
+var quotaVariables = map[Metric]string{
+	Searches:         "USAGE_QUOTA_SEARCHES",
+	IndexedDocuments: "USAGE_QUOTA_INDEXED_DOCUMENTS",
+	EmbeddingCalls:   "USAGE_QUOTA_EMBEDDING_CALLS",
+}
+
+// NewTrackerFromEnvironment creates a tracker with the daily quotas in USAGE_QUOTA_SEARCHES,
+// USAGE_QUOTA_INDEXED_DOCUMENTS and USAGE_QUOTA_EMBEDDING_CALLS, unlimited when unset or 0
+func NewTrackerFromEnvironment() (*Tracker, error) {
+	quotas := make(Quotas)
+	for _, metric := range Metrics {
+		name := quotaVariables[metric]
+		valueStr := os.Getenv(name)
+		if valueStr == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(valueStr, 10, 64)
+		if err != nil || value < 0 {
+			return NewTracker(nil), fmt.Errorf("invalid %s: %q", name, valueStr)
+		}
+		if value > 0 {
+			quotas[metric] = value
+		}
+	}
+	return NewTracker(quotas), nil
+}
+
+// Reserve records uses by key unless one of them would exceed its daily quota, in which case
+// nothing is recorded and a *QuotaError is returned
+func (t *Tracker) Reserve(key string, uses Uses) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.rollover()
+
+	used := t.usage[key]
+	for _, metric := range Metrics {
+		limit, n := t.quotas[metric], uses[metric]
+		if limit > 0 && n > 0 && used[metric]+n > limit {
+			return &QuotaError{Key: key, Metric: metric, Limit: limit, Used: used[metric], ResetsAt: t.day.AddDate(0, 0, 1)}
+		}
+	}
+
+	if used == nil {
+		used = make(Uses)
+		t.usage[key] = used
+	}
+	for metric, n := range uses {
+		used[metric] += n
+	}
+	return nil
+}
+
+// Usage reports the usage of key on the current day
+func (t *Tracker) Usage(key string) Report {
+	if t == nil {
+		return Report{Key: key, Used: Uses{}, Quotas: Quotas{}}
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.rollover()
+	return t.report(key)
+}
+
+// All reports the usage of every key with usage on the current day, ordered by key
+func (t *Tracker) All() []Report {
+	if t == nil {
+		return []Report{}
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.rollover()
+
+	keys := make([]string, 0, len(t.usage))
+	for key := range t.usage {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	reports := make([]Report, 0, len(keys))
+	for _, key := range keys {
+		reports = append(reports, t.report(key))
+	}
+	return reports
+}
+
+// report copies the usage of key; the mutex must be held
+func (t *Tracker) report(key string) Report {
+	used := make(Uses, len(Metrics))
+	for metric, n := range t.usage[key] {
+		used[metric] = n
+	}
+	quotas := make(Quotas, len(t.quotas))
+	for metric, limit := range t.quotas {
+		quotas[metric] = limit
+	}
+	return Report{Key: key, Day: t.day, ResetsAt: t.day.AddDate(0, 0, 1), Used: used, Quotas: quotas}
+}
+
+// rollover starts a new day of usage once the UTC date changes; the mutex must be held
+func (t *Tracker) rollover() {
+	now := t.now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !day.Equal(t.day) {
+		t.day = day
+		t.usage = make(map[string]Uses)
+	}
+}
@@ -0,0 +1,93 @@
+package usage
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestTracker(quotas Quotas, now *time.Time) *Tracker {
+	tracker := NewTracker(quotas)
+	tracker.now = func() time.Time { return *now }
+	return tracker
+}
+
+func TestReserveEnforcesQuotas(t *testing.T) {
+	now := time.Date(2024, 3, 1, 22, 30, 0, 0, time.UTC)
+	tracker := newTestTracker(Quotas{Searches: 2, EmbeddingCalls: 1}, &now)
+
+	if err := tracker.Reserve("team-a", Uses{Searches: 1, EmbeddingCalls: 1}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The embedding quota is used up, so nothing of this reservation is recorded
+	err := tracker.Reserve("team-a", Uses{Searches: 1, EmbeddingCalls: 1})
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) || quotaErr.Metric != EmbeddingCalls || quotaErr.Limit != 1 {
+		t.Fatalf("Expected an embedding quota error, got %v", err)
+	}
+	if want := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC); !quotaErr.ResetsAt.Equal(want) {
+		t.Errorf("Expected the quota to reset at %v, got %v", want, quotaErr.ResetsAt)
+	}
+
+	if err := tracker.Reserve("team-a", Uses{Searches: 1}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := tracker.Reserve("team-a", Uses{Searches: 1}); err == nil {
+		t.Error("Expected the search quota to be exceeded")
+	}
+
+	// Keys have their own quotas, and unlimited metrics are never rejected
+	if err := tracker.Reserve("team-b", Uses{Searches: 2, IndexedDocuments: 1000000}); err != nil {
+		t.Errorf("Unexpected error for another key: %v", err)
+	}
+
+	report := tracker.Usage("team-a")
+	if report.Used[Searches] != 2 || report.Used[EmbeddingCalls] != 1 || report.Quotas[Searches] != 2 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+}
+
+func TestUsageStartsOverEachDay(t *testing.T) {
+	now := time.Date(2024, 3, 1, 23, 59, 0, 0, time.UTC)
+	tracker := newTestTracker(Quotas{Searches: 1}, &now)
+
+	if err := tracker.Reserve("team-a", Uses{Searches: 1}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if err := tracker.Reserve("team-a", Uses{Searches: 1}); err != nil {
+		t.Errorf("Expected a new quota on the next day, got %v", err)
+	}
+	if reports := tracker.All(); len(reports) != 1 || !reports[0].Day.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected reports: %+v", reports)
+	}
+}
+
+func TestNilTracker(t *testing.T) {
+	var tracker *Tracker
+	if err := tracker.Reserve("team-a", Uses{Searches: 1}); err != nil {
+		t.Errorf("Expected a nil tracker to allow everything, got %v", err)
+	}
+	if reports := tracker.All(); len(reports) != 0 {
+		t.Errorf("Expected no reports, got %+v", reports)
+	}
+}
+
+func TestNewTrackerFromEnvironment(t *testing.T) {
+	t.Setenv("USAGE_QUOTA_SEARCHES", "100")
+	t.Setenv("USAGE_QUOTA_INDEXED_DOCUMENTS", "0")
+	t.Setenv("USAGE_QUOTA_EMBEDDING_CALLS", "")
+
+	tracker, err := NewTrackerFromEnvironment()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tracker.quotas) != 1 || tracker.quotas[Searches] != 100 {
+		t.Errorf("Unexpected quotas: %v", tracker.quotas)
+	}
+
+	t.Setenv("USAGE_QUOTA_SEARCHES", "many")
+	if _, err := NewTrackerFromEnvironment(); err == nil {
+		t.Error("Expected an error for an invalid quota")
+	}
+}
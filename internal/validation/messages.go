@@ -0,0 +1,103 @@
+package validation
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is the language of messages when the client accepts none of the supported ones
+const DefaultLanguage = "en"
+
+// catalog holds the message templates of each supported language. {field}, {value}, {min}, {max}
+// and {allowed} are replaced with the details of the error; the ".allowed" variants are used when
+// the allowed values are known.
+var catalog = map[string]map[string]string{
+	"en": {
+		"summary":                      "Invalid request parameters",
+		CodeRequired:                   "{field} is required",
+		CodeOutOfRange:                 "{field} must be between {min} and {max}, got {value}",
+		CodeTooSmall:                   "{field} must be at least {min}, got {value}",
+		CodeTooLong:                    "{field} must be at most {max} characters long",
+		CodeInvalidValue:               "{field} has an invalid value {value}",
+		CodeInvalidValue + ".allowed":  "{field} has an invalid value {value}, allowed values are: {allowed}",
+		CodeInvalidFormat:              "{field} has an invalid format: {value}",
+		CodeInvalidFormat + ".allowed": "{field} has an invalid format: {value}, expected: {allowed}",
+		CodeInvalidCharacters:          "{field} contains invalid characters",
+		CodeNoTerms:                    "{field} must contain at least one word",
+	},
+	"ru": {
+		"summary":                      "Недопустимые параметры запроса",
+		CodeRequired:                   "параметр {field} обязателен",
+		CodeOutOfRange:                 "{field} должен быть от {min} до {max}, получено {value}",
+		CodeTooSmall:                   "{field} должен быть не меньше {min}, получено {value}",
+		CodeTooLong:                    "{field} должен содержать не более {max} символов",
+		CodeInvalidValue:               "недопустимое значение {field}: {value}",
+		CodeInvalidValue + ".allowed":  "недопустимое значение {field}: {value}, допустимые значения: {allowed}",
+		CodeInvalidFormat:              "неверный формат {field}: {value}",
+		CodeInvalidFormat + ".allowed": "неверный формат {field}: {value}, ожидается: {allowed}",
+		CodeInvalidCharacters:          "{field} содержит недопустимые символы",
+		CodeNoTerms:                    "{field} должен содержать хотя бы одно слово",
+	},
+}
+
+// NegotiateLanguage picks the supported language with the highest quality in an Accept-Language
+// header, matching on the primary subtag (ru-RU selects ru), and DefaultLanguage when none is supported
+func NegotiateLanguage(acceptLanguage string) string {
+	language, best := DefaultLanguage, 0.0
+	for _, accepted := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(accepted), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if _, ok := catalog[primary]; !ok {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > best {
+			language, best = primary, quality
+		}
+	}
+	return language
+}
+
+// Summary is the message of a response listing field errors
+func Summary(language string) string {
+	return messages(language)["summary"]
+}
+
+// Message describes the error in language, or in DefaultLanguage if it isn't supported
+func (e *FieldError) Message(language string) string {
+	if e.detail != "" {
+		return e.detail
+	}
+
+	templates := messages(language)
+	template, ok := templates[e.Code+".allowed"]
+	if !ok || len(e.Allowed) == 0 {
+		template = templates[e.Code]
+	}
+	if template == "" {
+		template = templates[CodeInvalidValue]
+	}
+
+	replacements := []string{"{field}", e.Field, "{value}", strconv.Quote(e.Value), "{allowed}", strings.Join(e.Allowed, ", ")}
+	if e.Min != nil {
+		replacements = append(replacements, "{min}", strconv.Itoa(*e.Min))
+	}
+	if e.Max != nil {
+		replacements = append(replacements, "{max}", strconv.Itoa(*e.Max))
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+func messages(language string) map[string]string {
+	if templates, ok := catalog[language]; ok {
+		return templates
+	}
+	return catalog[DefaultLanguage]
+}
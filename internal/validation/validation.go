@@ -0,0 +1,113 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+)
+
+// Codes of field errors
+const (
+	CodeRequired          = "required"
+	CodeOutOfRange        = "out_of_range"
+	CodeTooSmall          = "too_small"
+	CodeTooLong           = "too_long"
+	CodeInvalidValue      = "invalid_value"
+	CodeInvalidFormat     = "invalid_format"
+	CodeInvalidCharacters = "invalid_characters"
+	CodeNoTerms           = "no_terms"
+)
+
+// FieldError is an invalid request parameter. Its Error method describes it in English; Message
+// describes it in another supported language.
+type FieldError struct {
+	Field   string
+	Code    string
+	Value   string   // Offending value, empty when it is missing or too long to repeat
+	Min     *int     // Smallest allowed value of out_of_range and too_small errors
+	Max     *int     // Largest allowed value of out_of_range errors, or length of too_long errors
+	Allowed []string // Allowed values of invalid_value errors, or accepted formats of invalid_format errors
+
+	detail string // Message of an error that isn't one of the codes, which is not translated
+}
+
+func (e *FieldError) Error() string {
+	return e.Message(DefaultLanguage)
+}
+
+// Required reports a missing parameter
+func Required(field string) error {
+	return &FieldError{Field: field, Code: CodeRequired}
+}
+
+// OutOfRange reports a number outside of min to max
+func OutOfRange(field, value string, min, max int) error {
+	return &FieldError{Field: field, Code: CodeOutOfRange, Value: value, Min: &min, Max: &max}
+}
+
+// TooSmall reports a number below min
+func TooSmall(field, value string, min int) error {
+	return &FieldError{Field: field, Code: CodeTooSmall, Value: value, Min: &min}
+}
+
+// TooLong reports a value longer than max characters
+func TooLong(field string, max int) error {
+	return &FieldError{Field: field, Code: CodeTooLong, Max: &max}
+}
+
+// InvalidValue reports a value that is not one of allowed, if they can be listed
+func InvalidValue(field, value string, allowed ...string) error {
+	return &FieldError{Field: field, Code: CodeInvalidValue, Value: value, Allowed: allowed}
+}
+
+// InvalidFormat reports a value that can't be parsed as any of the expected formats
+func InvalidFormat(field, value string, expected ...string) error {
+	return &FieldError{Field: field, Code: CodeInvalidFormat, Value: value, Allowed: expected}
+}
+
+// InvalidCharacters reports a value with control characters or invalid UTF-8
+func InvalidCharacters(field string) error {
+	return &FieldError{Field: field, Code: CodeInvalidCharacters}
+}
+
+// NoTerms reports a search query without a word to search for
+func NoTerms(field string) error {
+	return &FieldError{Field: field, Code: CodeNoTerms}
+}
+
+// Errors collects the invalid parameters of a request
+type Errors []*FieldError
+
+// Add records err if it is not nil. Errors other than field errors are recorded as invalid values
+// of field, keeping their message.
+func (e *Errors) Add(field string, err error) {
+	if err == nil {
+		return
+	}
+
+	var fieldErrors Errors
+	var fieldErr *FieldError
+	switch {
+	case errors.As(err, &fieldErrors):
+		*e = append(*e, fieldErrors...)
+	case errors.As(err, &fieldErr):
+		*e = append(*e, fieldErr)
+	default:
+		*e = append(*e, &FieldError{Field: field, Code: CodeInvalidValue, detail: err.Error()})
+	}
+}
+
+// Err returns the collected errors, nil when there are none
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFieldErrorMessages(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		language string
+		expected string
+	}{
+		{"required", Required("query"), "en", "query is required"},
+		{"out of range", OutOfRange("limit", "500", 1, 100), "en", `limit must be between 1 and 100, got "500"`},
+		{"too small", TooSmall("id", "0", 1), "en", `id must be at least 1, got "0"`},
+		{"too long", TooLong("query", 1000), "en", "query must be at most 1000 characters long"},
+		{"invalid value", InvalidValue("mode", "fast", "basic", "fulltext"), "en", `mode has an invalid value "fast", allowed values are: basic, fulltext`},
+		{"invalid value without allowed values", InvalidValue("id", "x"), "en", `id has an invalid value "x"`},
+		{"invalid format", InvalidFormat("since", "yesterday", "unix_time", "rfc3339"), "en", `since has an invalid format: "yesterday", expected: unix_time, rfc3339`},
+		{"russian", OutOfRange("limit", "500", 1, 100), "ru", `limit должен быть от 1 до 100, получено "500"`},
+		{"unsupported language", Required("query"), "fr", "query is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fieldErr *FieldError
+			if !errors.As(tt.err, &fieldErr) {
+				t.Fatalf("Expected a *FieldError, got %T", tt.err)
+			}
+			if got := fieldErr.Message(tt.language); got != tt.expected {
+				t.Errorf("Message(%q) = %q, want %q", tt.language, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestErrorsAdd(t *testing.T) {
+	var errs Errors
+	errs.Add("page", nil)
+	if errs.Err() != nil {
+		t.Fatal("Expected no error without failures")
+	}
+
+	errs.Add("limit", OutOfRange("limit", "0", 1, 100))
+	errs.Add("filter", errors.New("filter by status with the status parameter"))
+	errs.Add("", Errors{Required("query").(*FieldError)})
+
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 errors, got %d", len(errs))
+	}
+	if errs[1].Field != "filter" || errs[1].Code != CodeInvalidValue || errs[1].Message("ru") != "filter by status with the status parameter" {
+		t.Errorf("Unexpected wrapped error: %+v", errs[1])
+	}
+	if errs[2].Code != CodeRequired {
+		t.Errorf("Expected the nested errors to be flattened, got %+v", errs[2])
+	}
+	if got := errs.Err().Error(); got != `limit must be between 1 and 100, got "0"; filter by status with the status parameter; query is required` {
+		t.Errorf("Unexpected message: %s", got)
+	}
+}
+
+func TestNegotiateLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", "en"},
+		{"ru", "ru"},
+		{"ru-RU,ru;q=0.9,en;q=0.8", "ru"},
+		{"de-DE, en;q=0.5", "en"},
+		{"en;q=0.4, ru;q=0.7", "ru"},
+		{"fr, de", "en"},
+		{"ru;q=abc, en;q=0.1", "en"},
+	}
+
+	for _, tt := range tests {
+		if got := NegotiateLanguage(tt.header); got != tt.expected {
+			t.Errorf("NegotiateLanguage(%q) = %q, want %q", tt.header, got, tt.expected)
+		}
+	}
+}
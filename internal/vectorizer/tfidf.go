@@ -1,6 +1,7 @@
 package vectorizer
 
 import (
+	"fmt"
 	"log"
 	"math"
 	"regexp"
@@ -8,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/ad/manticoresearch-go/internal/models"
+	"github.com/ad/manticoresearch-go/internal/textnorm"
 )
 
 // TFIDFVectorizer implements a simple TF-IDF vectorization
@@ -15,6 +17,8 @@ type TFIDFVectorizer struct {
 	vocabulary map[string]int // word -> index mapping
 	idf        []float64      // inverse document frequency for each word
 	documents  []string       // preprocessed documents for IDF calculation
+	normalizer *textnorm.Normalizer
+	fitted     int // number of documents the IDF weights were calculated from
 }
 
 // NewTFIDFVectorizer creates a new TF-IDF vectorizer
@@ -25,29 +29,84 @@ func NewTFIDFVectorizer() *TFIDFVectorizer {
 	}
 }
 
-// preprocessText cleans and tokenizes text
-func (v *TFIDFVectorizer) preprocessText(text string) []string {
-	// Convert to lowercase
-	text = strings.ToLower(text)
+// Model is the fitted state of a TFIDFVectorizer, used to persist it in backups
+type Model struct {
+	Vocabulary map[string]int `json:"vocabulary"`
+	IDF        []float64      `json:"idf"`
+	Documents  int            `json:"documents,omitempty"` // 0 in models saved before it was recorded
+}
 
-	// Remove punctuation and special characters, keep only letters and numbers
-	reg := regexp.MustCompile(`[^a-zA-Zа-яА-Я0-9\s]+`)
-	text = reg.ReplaceAllString(text, " ")
+// Model returns a copy of the fitted vocabulary and IDF weights
+func (v *TFIDFVectorizer) Model() Model {
+	model := Model{
+		Vocabulary: make(map[string]int, len(v.vocabulary)),
+		IDF:        append([]float64(nil), v.idf...),
+		Documents:  v.fitted,
+	}
+	for word, index := range v.vocabulary {
+		model.Vocabulary[word] = index
+	}
+	return model
+}
 
-	// Split into words and filter out short words
-	words := strings.Fields(text)
-	var filteredWords []string
+// NewTFIDFVectorizerFromModel restores a fitted vectorizer, so queries are vectorized exactly like the indexed documents
+func NewTFIDFVectorizerFromModel(model Model) (*TFIDFVectorizer, error) {
+	v := NewTFIDFVectorizer()
+	v.idf = append([]float64(nil), model.IDF...)
+	v.fitted = model.Documents
+	for word, index := range model.Vocabulary {
+		if index < 0 || index >= len(v.idf) {
+			return nil, fmt.Errorf("vocabulary index %d for %q is out of range for %d IDF weights", index, word, len(v.idf))
+		}
+		v.vocabulary[word] = index
+	}
+	return v, nil
+}
 
-	for _, word := range words {
-		// Keep words that are at least 2 characters long
-		if len(word) >= 2 {
-			filteredWords = append(filteredWords, word)
+// SetNormalizer sets the normalization applied to documents and queries before tokenization. It
+// has to be set before FitTransform, so queries are normalized like the vocabulary.
+func (v *TFIDFVectorizer) SetNormalizer(normalizer *textnorm.Normalizer) {
+	v.normalizer = normalizer
+}
+
+// normalizeWord returns the form of a word in the vocabulary
+func (v *TFIDFVectorizer) normalizeWord(word string) string {
+	return strings.ToLower(v.normalizer.String(word))
+}
+
+// tokenPattern matches words and the code tokens they form when joined by . / _ - : or \, such as
+// net/http, v1.21.3 and snake_case
+var tokenPattern = regexp.MustCompile(`[a-zа-я0-9]+(?:[./_\-:\\]+[a-zа-я0-9]+)*`)
+
+// preprocessText cleans and tokenizes text. A code token yields its parts and itself, so
+// "net/http" matches documents mentioning http as well as, more strongly, net/http.
+func (v *TFIDFVectorizer) preprocessText(text string) []string {
+	// Normalize and convert to lowercase
+	text = strings.ToLower(v.normalizer.String(text))
+
+	// Keep only letters and numbers, and the connectors of code tokens, filtering out short words
+	var filteredWords []string
+	for _, token := range tokenPattern.FindAllString(text, -1) {
+		parts := strings.FieldsFunc(token, func(r rune) bool { return !isTokenRune(r) })
+		for _, word := range parts {
+			// Keep words that are at least 2 characters long
+			if len(word) >= 2 {
+				filteredWords = append(filteredWords, word)
+			}
+		}
+		if len(parts) > 1 {
+			filteredWords = append(filteredWords, token)
 		}
 	}
 
 	return filteredWords
 }
 
+// isTokenRune reports whether r is a letter or digit the vectorizer indexes
+func isTokenRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'а' && r <= 'я') || (r >= '0' && r <= '9')
+}
+
 // FitTransform builds vocabulary and calculates IDF from documents, then transforms them
 func (v *TFIDFVectorizer) FitTransform(documents []*models.Document) [][]float64 {
 	log.Printf("[TFIDF] Starting vectorization for %d documents", len(documents))
@@ -98,6 +157,7 @@ func (v *TFIDFVectorizer) FitTransform(documents []*models.Document) [][]float64
 	// Step 2: Calculate IDF for each word
 	v.idf = make([]float64, len(v.vocabulary))
 	totalDocs := float64(len(documents))
+	v.fitted = len(documents)
 
 	for word, index := range v.vocabulary {
 		docFreq := float64(wordCounts[word])
@@ -188,6 +248,117 @@ func (v *TFIDFVectorizer) TransformQuery(query string) []float64 {
 	return v.transformDocument(query)
 }
 
+// VocabularySize returns the number of distinct terms learned by FitTransform
+func (v *TFIDFVectorizer) VocabularySize() int {
+	return len(v.vocabulary)
+}
+
+// IDF returns the inverse document frequency of a word, and false when the word is not in the
+// vocabulary because no document contains it or nearly every document does
+func (v *TFIDFVectorizer) IDF(word string) (float64, bool) {
+	index, ok := v.vocabulary[v.normalizeWord(word)]
+	if !ok || index >= len(v.idf) {
+		return 0, false
+	}
+	return v.idf[index], true
+}
+
+// Term is a vocabulary word with the number of documents containing it and its IDF weight
+type Term struct {
+	Word              string
+	DocumentFrequency int // 0 when the vectorizer was restored from a model without document counts
+	IDF               float64
+}
+
+// Terms returns up to limit vocabulary words starting with prefix, the most common first, and the
+// number of words matching the prefix. An empty prefix matches the whole vocabulary.
+func (v *TFIDFVectorizer) Terms(prefix string, limit int) ([]Term, int) {
+	prefix = v.normalizeWord(prefix)
+
+	var terms []Term
+	for word, index := range v.vocabulary {
+		if !strings.HasPrefix(word, prefix) {
+			continue
+		}
+		term := Term{Word: word, IDF: v.idf[index]}
+		if v.fitted > 0 {
+			// IDF is log(documents / document frequency)
+			term.DocumentFrequency = int(math.Round(float64(v.fitted) / math.Exp(term.IDF)))
+		}
+		terms = append(terms, term)
+	}
+
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].IDF != terms[j].IDF {
+			return terms[i].IDF < terms[j].IDF
+		}
+		return terms[i].Word < terms[j].Word
+	})
+	return terms[:min(limit, len(terms))], len(terms)
+}
+
+// SimilarWords returns up to limit vocabulary words within maxDistance edits of word, closest
+// first and, at equal distance, the most common first
+func (v *TFIDFVectorizer) SimilarWords(word string, maxDistance, limit int) []string {
+	word = v.normalizeWord(word)
+	target := []rune(word)
+
+	type candidate struct {
+		word     string
+		distance int
+		idf      float64
+	}
+	var candidates []candidate
+	for vocabWord, index := range v.vocabulary {
+		if vocabWord == word {
+			continue
+		}
+		runes := []rune(vocabWord)
+		if diff := len(runes) - len(target); diff > maxDistance || -diff > maxDistance {
+			continue
+		}
+		if distance := editDistance(target, runes); distance <= maxDistance {
+			candidates = append(candidates, candidate{word: vocabWord, distance: distance, idf: v.idf[index]})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		if candidates[i].idf != candidates[j].idf {
+			return candidates[i].idf < candidates[j].idf
+		}
+		return candidates[i].word < candidates[j].word
+	})
+	words := make([]string, 0, min(limit, len(candidates)))
+	for _, c := range candidates[:min(limit, len(candidates))] {
+		words = append(words, c.word)
+	}
+	return words
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
 // CosineSimilarity calculates cosine similarity between two vectors
 func CosineSimilarity(vec1, vec2 []float64) float64 {
 	if len(vec1) != len(vec2) {
@@ -209,6 +380,30 @@ func CosineSimilarity(vec1, vec2 []float64) float64 {
 	return dotProduct / (math.Sqrt(norm1) * math.Sqrt(norm2))
 }
 
+// TextSimilarity returns the cosine similarity of a query vector from TransformQuery and the TF-IDF
+// vector of text. Only the words of text are weighted, so short texts such as sentences are
+// compared without building vectors of the whole vocabulary.
+func (v *TFIDFVectorizer) TextSimilarity(queryVector []float64, text string) float64 {
+	termFreq := make(map[int]float64)
+	for _, word := range v.preprocessText(text) {
+		if index, ok := v.vocabulary[word]; ok && index < len(v.idf) && index < len(queryVector) {
+			termFreq[index]++
+		}
+	}
+
+	// Query vectors are L2-normalized already
+	var dotProduct, norm float64
+	for index, tf := range termFreq {
+		weight := tf * v.idf[index]
+		dotProduct += weight * queryVector[index]
+		norm += weight * weight
+	}
+	if norm == 0 {
+		return 0.0
+	}
+	return dotProduct / math.Sqrt(norm)
+}
+
 // VectorSearchResult represents a document with its similarity score
 type VectorSearchResult struct {
 	Document   *models.Document
@@ -0,0 +1,110 @@
+package warmup
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+// DefaultTimeout bounds the whole warm-up when WARMUP_TIMEOUT is unset
+const DefaultTimeout = 30 * time.Second
+
+// DefaultModes are the search modes warmed up when WARMUP_MODES is unset. Vector and hybrid
+// queries scan the in-memory vectors, and AI queries make Manticore load its embedding model.
+var DefaultModes = []models.SearchMode{
+	models.SearchModeBasic,
+	models.SearchModeFullText,
+	models.SearchModeVector,
+	models.SearchModeHybrid,
+	models.SearchModeAI,
+}
+
+// Config selects the queries run after startup indexing, before the server reports ready
+type Config struct {
+	Queries []string
+	Modes   []models.SearchMode
+	Timeout time.Duration // bounds the whole warm-up; zero runs every query
+}
+
+// Result summarizes a warm-up
+type Result struct {
+	Queries  int // searches run, failed ones included
+	Failures int
+	Duration time.Duration
+}
+
+// FromEnvironment reads the comma-separated WARMUP_QUERIES, WARMUP_MODES (default DefaultModes)
+// and WARMUP_TIMEOUT (default DefaultTimeout; 0 disables it). It returns nil, which disables the
+// warm-up, when WARMUP_QUERIES is unset or empty.
+func FromEnvironment() (*Config, error) {
+	queries := splitList(os.Getenv("WARMUP_QUERIES"))
+	if len(queries) == 0 {
+		return nil, nil
+	}
+	config := &Config{Queries: queries, Modes: DefaultModes, Timeout: DefaultTimeout}
+
+	if value := os.Getenv("WARMUP_MODES"); value != "" {
+		config.Modes = nil
+		for _, mode := range splitList(value) {
+			if err := models.ValidateSearchMode(mode); err != nil {
+				return nil, fmt.Errorf("invalid WARMUP_MODES: %v", err)
+			}
+			config.Modes = append(config.Modes, models.SearchMode(mode))
+		}
+	}
+
+	if value := os.Getenv("WARMUP_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("invalid WARMUP_TIMEOUT: %q", value)
+		}
+		config.Timeout = timeout
+	}
+
+	return config, nil
+}
+
+// Run runs every query in every mode of config with search, in order, until ctx is done or the
+// timeout expires. Failed searches are logged and counted, they do not stop the warm-up.
+func Run(ctx context.Context, config *Config, search func(ctx context.Context, query string, mode models.SearchMode) error) Result {
+	start := time.Now()
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	var result Result
+	for _, mode := range config.Modes {
+		for _, query := range config.Queries {
+			if ctx.Err() != nil {
+				log.Printf("[WARMUP] Stopped after %d searches: %v", result.Queries, ctx.Err())
+				result.Duration = time.Since(start)
+				return result
+			}
+			result.Queries++
+			if err := search(ctx, query, mode); err != nil {
+				result.Failures++
+				log.Printf("[WARMUP] [WARNING] %s search for %q failed: %v", mode, query, err)
+			}
+		}
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// splitList splits a comma-separated list, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
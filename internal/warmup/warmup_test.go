@@ -0,0 +1,77 @@
+package warmup
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/internal/models"
+)
+
+func TestFromEnvironment(t *testing.T) {
+	tests := []struct {
+		name     string
+		queries  string
+		modes    string
+		timeout  string
+		expected *Config
+		wantErr  bool
+	}{
+		{"disabled", "", "", "", nil, false},
+		{"defaults", "форма, поиск ,", "", "", &Config{Queries: []string{"форма", "поиск"}, Modes: DefaultModes, Timeout: DefaultTimeout}, false},
+		{"modes and timeout", "форма", "basic,vector", "5s", &Config{Queries: []string{"форма"}, Modes: []models.SearchMode{models.SearchModeBasic, models.SearchModeVector}, Timeout: 5 * time.Second}, false},
+		{"no timeout", "форма", "", "0", &Config{Queries: []string{"форма"}, Modes: DefaultModes}, false},
+		{"invalid mode", "форма", "basic,semantic", "", nil, true},
+		{"invalid timeout", "форма", "", "soon", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WARMUP_QUERIES", tt.queries)
+			t.Setenv("WARMUP_MODES", tt.modes)
+			t.Setenv("WARMUP_TIMEOUT", tt.timeout)
+
+			config, err := FromEnvironment()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, config)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	config := &Config{Queries: []string{"a", "b"}, Modes: []models.SearchMode{models.SearchModeBasic, models.SearchModeVector}}
+
+	var searches []string
+	result := Run(context.Background(), config, func(ctx context.Context, query string, mode models.SearchMode) error {
+		searches = append(searches, string(mode)+":"+query)
+		if mode == models.SearchModeVector && query == "b" {
+			return errors.New("no vectors")
+		}
+		return nil
+	})
+
+	expected := []string{"basic:a", "basic:b", "vector:a", "vector:b"}
+	if !reflect.DeepEqual(searches, expected) {
+		t.Errorf("Expected searches %v, got %v", expected, searches)
+	}
+	if result.Queries != 4 || result.Failures != 1 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	config := &Config{Queries: []string{"a", "b", "c"}, Modes: []models.SearchMode{models.SearchModeBasic}, Timeout: 10 * time.Millisecond}
+
+	result := Run(context.Background(), config, func(ctx context.Context, query string, mode models.SearchMode) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if result.Queries != 1 || result.Failures != 1 {
+		t.Errorf("Expected the timeout to stop the warm-up after the first search, got %+v", result)
+	}
+}
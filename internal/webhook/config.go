@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadConfigFromEnvironment reads the webhook configuration. Endpoints come from WEBHOOK_URLS, a
+// comma-separated list sharing WEBHOOK_SECRET and WEBHOOK_EVENTS, and from WEBHOOK_ENDPOINTS_FILE,
+// a JSON array of endpoints with their own secret and events.
+func LoadConfigFromEnvironment() (Config, error) {
+	config := DefaultConfig()
+
+	var events []string
+	for _, event := range strings.Split(os.Getenv("WEBHOOK_EVENTS"), ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, event)
+		}
+	}
+	for _, rawURL := range strings.Split(os.Getenv("WEBHOOK_URLS"), ",") {
+		if rawURL = strings.TrimSpace(rawURL); rawURL != "" {
+			config.Endpoints = append(config.Endpoints, Endpoint{URL: rawURL, Secret: os.Getenv("WEBHOOK_SECRET"), Events: events})
+		}
+	}
+
+	if path := os.Getenv("WEBHOOK_ENDPOINTS_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return config, fmt.Errorf("failed to read WEBHOOK_ENDPOINTS_FILE: %v", err)
+		}
+		var endpoints []Endpoint
+		if err := json.Unmarshal(data, &endpoints); err != nil {
+			return config, fmt.Errorf("failed to parse WEBHOOK_ENDPOINTS_FILE: %v", err)
+		}
+		config.Endpoints = append(config.Endpoints, endpoints...)
+	}
+
+	for _, endpoint := range config.Endpoints {
+		parsed, err := url.Parse(endpoint.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return config, fmt.Errorf("invalid webhook URL: %q", endpoint.URL)
+		}
+	}
+
+	if value := os.Getenv("WEBHOOK_MAX_RETRIES"); value != "" {
+		retries, err := strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			return config, fmt.Errorf("invalid WEBHOOK_MAX_RETRIES: %s", value)
+		}
+		config.MaxRetries = retries
+	}
+	if value := os.Getenv("WEBHOOK_RETRY_DELAY"); value != "" {
+		delay, err := time.ParseDuration(value)
+		if err != nil || delay <= 0 {
+			return config, fmt.Errorf("invalid WEBHOOK_RETRY_DELAY: %s", value)
+		}
+		config.RetryDelay = delay
+	}
+	if value := os.Getenv("WEBHOOK_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return config, fmt.Errorf("invalid WEBHOOK_TIMEOUT: %s", value)
+		}
+		config.Timeout = timeout
+	}
+
+	return config, nil
+}
+
+// NewFromEnvironment creates a dispatcher configured by LoadConfigFromEnvironment
+func NewFromEnvironment() (*Dispatcher, error) {
+	config, err := LoadConfigFromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	return New(config), nil
+}
@@ -0,0 +1,296 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event types published by the service
+const (
+	EventReindexCompleted   = "reindex.completed"
+	EventIndexingFailed     = "indexing.failed"
+	EventSavedSearchMatched = "saved_search.matched"
+	EventSLOBurning         = "slo.burning"
+	EventSLORecovered       = "slo.recovered"
+)
+
+// Delivery headers. The signature is "sha256=" followed by the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the endpoint secret, see Sign.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderID        = "X-Webhook-ID"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// queueSize bounds the events waiting for delivery; newer events are dropped when it is full
+const queueSize = 100
+
+// Endpoint is a URL receiving events
+type Endpoint struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"` // HMAC key, deliveries are unsigned when empty
+	Events []string `json:"events,omitempty"` // Event types to deliver, empty for every event
+}
+
+// accepts reports whether the endpoint subscribes to an event type
+func (e Endpoint) accepts(eventType string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, event := range e.Events {
+		if event == eventType || event == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Config configures event delivery
+type Config struct {
+	Endpoints  []Endpoint
+	MaxRetries int           // Retries after a failed delivery attempt
+	RetryDelay time.Duration // Delay before the first retry, doubled for each following one
+	Timeout    time.Duration // Timeout of a single delivery attempt
+}
+
+// DefaultConfig returns a configuration without endpoints
+func DefaultConfig() Config {
+	return Config{
+		MaxRetries: 3,
+		RetryDelay: time.Second,
+		Timeout:    10 * time.Second,
+	}
+}
+
+// Event is the JSON body of a delivery
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// Stats counts deliveries since the dispatcher was created
+type Stats struct {
+	Endpoints int   `json:"endpoints"`
+	Delivered int64 `json:"delivered"`
+	Failed    int64 `json:"failed"`  // Deliveries that failed after every retry
+	Retries   int64 `json:"retries"` // Attempts after the first one
+	Dropped   int64 `json:"dropped"` // Events dropped because the queue was full
+}
+
+// Dispatcher delivers events to the configured endpoints from a background worker, so publishing
+// never blocks the caller. A nil *Dispatcher is valid and drops every event.
+type Dispatcher struct {
+	config     Config
+	httpClient *http.Client
+
+	delivered atomic.Int64
+	failed    atomic.Int64
+	retries   atomic.Int64
+	dropped   atomic.Int64
+
+	queue    chan Event
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// New creates a dispatcher and starts its worker
+func New(config Config) *Dispatcher {
+	defaults := DefaultConfig()
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = defaults.RetryDelay
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+
+	d := &Dispatcher{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		queue:      make(chan Event, queueSize),
+		stop:       make(chan struct{}),
+	}
+
+	d.wg.Add(1)
+	go d.worker()
+
+	return d
+}
+
+// Publish queues an event for every endpoint subscribed to its type
+func (d *Dispatcher) Publish(eventType string, data interface{}) {
+	if d == nil || len(d.config.Endpoints) == 0 {
+		return
+	}
+
+	event := Event{
+		ID:        newEventID(),
+		Type:      eventType,
+		Timestamp: time.Now().UTC(),
+		Data:      data,
+	}
+
+	select {
+	case d.queue <- event:
+	default:
+		d.dropped.Add(1)
+		log.Printf("[WEBHOOK] Queue full, dropping %s event", eventType)
+	}
+}
+
+// Stats returns delivery counters
+func (d *Dispatcher) Stats() Stats {
+	if d == nil {
+		return Stats{}
+	}
+	return Stats{
+		Endpoints: len(d.config.Endpoints),
+		Delivered: d.delivered.Load(),
+		Failed:    d.failed.Load(),
+		Retries:   d.retries.Load(),
+		Dropped:   d.dropped.Load(),
+	}
+}
+
+// Close stops the worker after queued events are delivered. Retries still pending at that point
+// are abandoned.
+func (d *Dispatcher) Close() {
+	if d == nil {
+		return
+	}
+	d.stopOnce.Do(func() {
+		close(d.stop)
+	})
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case event := <-d.queue:
+			d.process(event)
+		case <-d.stop:
+			for {
+				select {
+				case event := <-d.queue:
+					d.process(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) process(event Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[WEBHOOK] Failed to marshal %s event: %v", event.Type, err)
+		return
+	}
+
+	for _, endpoint := range d.config.Endpoints {
+		if !endpoint.accepts(event.Type) {
+			continue
+		}
+		if err := d.deliver(endpoint, event, body); err != nil {
+			d.failed.Add(1)
+			log.Printf("[WEBHOOK] Failed to deliver %s event %s to %s: %v", event.Type, event.ID, endpoint.URL, err)
+			continue
+		}
+		d.delivered.Add(1)
+	}
+}
+
+// deliver posts an event, retrying network errors, 429 and 5xx responses with exponential backoff
+func (d *Dispatcher) deliver(endpoint Endpoint, event Event, body []byte) error {
+	delay := d.config.RetryDelay
+
+	var lastErr error
+	for attempt := 0; attempt <= d.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			d.retries.Add(1)
+			select {
+			case <-time.After(delay):
+			case <-d.stop:
+				return fmt.Errorf("shutting down after %d attempts: %v", attempt, lastErr)
+			}
+			delay *= 2
+		}
+
+		retryable, err := d.send(endpoint, event, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retryable {
+			return err
+		}
+	}
+	return fmt.Errorf("giving up after %d attempts: %v", d.config.MaxRetries+1, lastErr)
+}
+
+// send makes a single delivery attempt and reports whether a failure is worth retrying
+func (d *Dispatcher) send(endpoint Endpoint, event Event, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, event.Type)
+	req.Header.Set(HeaderID, event.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	if endpoint.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, body))
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retryable, fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// Sign returns the signature header value of a delivery body sent at timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newEventID returns a random event identifier receivers can use to drop duplicate deliveries
+func newEventID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDispatcherDeliversSignedEvents(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		received <- r
+	}))
+	defer server.Close()
+
+	d := New(Config{Endpoints: []Endpoint{{URL: server.URL, Secret: "s3cret"}}})
+	d.Publish(EventReindexCompleted, map[string]int{"documents": 3})
+	d.Close()
+
+	r := <-received
+	timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("Invalid timestamp header: %v", err)
+	}
+	if r.Header.Get(HeaderSignature) != Sign("s3cret", timestamp, body) {
+		t.Errorf("Signature %s does not match the body", r.Header.Get(HeaderSignature))
+	}
+	if r.Header.Get(HeaderEvent) != EventReindexCompleted {
+		t.Errorf("Unexpected event header: %s", r.Header.Get(HeaderEvent))
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	if event.Type != EventReindexCompleted || event.ID == "" || event.ID != r.Header.Get(HeaderID) {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	if stats := d.Stats(); stats.Delivered != 1 || stats.Failed != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	d := New(Config{Endpoints: []Endpoint{{URL: server.URL}}, MaxRetries: 3, RetryDelay: time.Millisecond})
+	event := Event{ID: "1", Type: EventIndexingFailed}
+	if err := d.deliver(d.config.Endpoints[0], event, []byte(`{}`)); err != nil {
+		t.Fatalf("Expected delivery to succeed after retries, got %v", err)
+	}
+	d.Close()
+
+	if attempts.Load() != 3 || d.Stats().Retries != 2 {
+		t.Errorf("Expected 3 attempts and 2 retries, got %d and %d", attempts.Load(), d.Stats().Retries)
+	}
+}
+
+func TestDispatcherDoesNotRetryClientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	d := New(Config{Endpoints: []Endpoint{{URL: server.URL}}, MaxRetries: 3, RetryDelay: time.Millisecond})
+	defer d.Close()
+
+	if err := d.deliver(d.config.Endpoints[0], Event{}, []byte(`{}`)); err == nil {
+		t.Fatal("Expected a 400 response to fail")
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("Expected a single attempt, got %d", attempts.Load())
+	}
+}
+
+func TestEndpointEvents(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	d := New(Config{Endpoints: []Endpoint{{URL: server.URL, Events: []string{EventSavedSearchMatched}}}})
+	d.Publish(EventReindexCompleted, nil)
+	d.Publish(EventSavedSearchMatched, nil)
+	d.Close()
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected only the subscribed event to be delivered, got %d deliveries", calls.Load())
+	}
+}
+
+func TestNilDispatcher(t *testing.T) {
+	var d *Dispatcher
+	d.Publish(EventReindexCompleted, nil)
+	d.Close()
+	if stats := d.Stats(); stats != (Stats{}) {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+}
+
+func TestLoadConfigFromEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	if err := os.WriteFile(path, []byte(`[{"url":"https://b.example.com/hook","secret":"b","events":["indexing.failed"]}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("WEBHOOK_URLS", "https://a.example.com/hook")
+	t.Setenv("WEBHOOK_SECRET", "a")
+	t.Setenv("WEBHOOK_ENDPOINTS_FILE", path)
+	t.Setenv("WEBHOOK_MAX_RETRIES", "5")
+	t.Setenv("WEBHOOK_RETRY_DELAY", "2s")
+
+	config, err := LoadConfigFromEnvironment()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(config.Endpoints) != 2 || config.Endpoints[0].Secret != "a" || config.Endpoints[1].Events[0] != EventIndexingFailed {
+		t.Errorf("Unexpected endpoints: %+v", config.Endpoints)
+	}
+	if config.MaxRetries != 5 || config.RetryDelay != 2*time.Second {
+		t.Errorf("Unexpected retry configuration: %+v", config)
+	}
+
+	t.Setenv("WEBHOOK_URLS", "ftp://example.com")
+	if _, err := LoadConfigFromEnvironment(); err == nil {
+		t.Error("Expected an invalid URL to be rejected")
+	}
+}
@@ -0,0 +1,375 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message opcodes
+const (
+	OpContinuation = 0x0
+	OpText         = 0x1
+	OpBinary       = 0x2
+	OpClose        = 0x8
+	OpPing         = 0x9
+	OpPong         = 0xA
+)
+
+// Close status codes
+const (
+	CloseNormal          = 1000
+	CloseProtocolError   = 1002
+	CloseMessageTooLarge = 1009
+)
+
+// MaxMessageSize bounds a reassembled message
+const MaxMessageSize = 1 << 20
+
+// acceptGUID is appended to the client key to compute Sec-WebSocket-Accept
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// ErrClosed is returned by ReadMessage after the peer closed the connection
+var ErrClosed = errors.New("websocket: connection closed")
+
+// Conn is an RFC 6455 WebSocket connection supporting text and binary messages with fragmentation,
+// ping/pong and the closing handshake, but no extensions or subprotocols. Reads must come from a
+// single goroutine; writes are safe for concurrent use.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	client bool // Client connections mask the frames they send
+
+	writeMutex sync.Mutex
+	closeOnce  sync.Once
+}
+
+// Upgrade performs the server side of the opening handshake, sending a 400 response when the
+// request is not a valid WebSocket upgrade
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return nil, fmt.Errorf("websocket: method %s is not GET", r.Method)
+	}
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "Expected a WebSocket upgrade", http.StatusBadRequest)
+		return nil, errors.New("websocket: missing upgrade headers")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusBadRequest)
+		return nil, errors.New("websocket: unsupported version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "Missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("websocket: missing key")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket upgrade not supported", http.StatusInternalServerError)
+		return nil, errors.New("websocket: response does not support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("websocket: hijack failed: %v", err)
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("websocket: handshake failed: %v", err)
+	}
+
+	return &Conn{conn: conn, reader: rw.Reader}, nil
+}
+
+// Dial opens a client connection to a ws:// URL
+func Dial(rawURL string, timeout time.Duration) (*Conn, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("websocket: invalid URL: %v", err)
+	}
+	if parsed.Scheme != "ws" {
+		return nil, fmt.Errorf("websocket: unsupported scheme %q", parsed.Scheme)
+	}
+
+	conn, err := net.DialTimeout("tcp", parsed.Host, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("websocket: dial failed: %v", err)
+	}
+
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	request := "GET " + parsed.RequestURI() + " HTTP/1.1\r\n" +
+		"Host: " + parsed.Host + "\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write([]byte(request)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("websocket: handshake failed: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, &http.Request{Method: http.MethodGet})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("websocket: handshake failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		conn.Close()
+		return nil, fmt.Errorf("websocket: handshake rejected with HTTP %d", resp.StatusCode)
+	}
+	conn.SetDeadline(time.Time{})
+
+	return &Conn{conn: conn, reader: reader, client: true}, nil
+}
+
+// ReadMessage returns the next text or binary message, answering pings and close frames. It
+// returns ErrClosed once the peer has closed the connection.
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	var (
+		opcode  int
+		message []byte
+		started bool
+	)
+
+	for {
+		fin, frameOpcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch frameOpcode {
+		case OpPing:
+			if err := c.writeFrame(OpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case OpPong:
+			continue
+		case OpClose:
+			code := CloseNormal
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+			c.closeWith(code, "")
+			return 0, nil, ErrClosed
+		case OpText, OpBinary:
+			if started {
+				c.closeWith(CloseProtocolError, "expected continuation frame")
+				return 0, nil, errors.New("websocket: new message before the previous one finished")
+			}
+			started = true
+			opcode = frameOpcode
+		case OpContinuation:
+			if !started {
+				c.closeWith(CloseProtocolError, "unexpected continuation frame")
+				return 0, nil, errors.New("websocket: continuation frame without a message")
+			}
+		default:
+			c.closeWith(CloseProtocolError, "unknown opcode")
+			return 0, nil, fmt.Errorf("websocket: unknown opcode %d", frameOpcode)
+		}
+
+		if len(message)+len(payload) > MaxMessageSize {
+			c.closeWith(CloseMessageTooLarge, "message too large")
+			return 0, nil, errors.New("websocket: message too large")
+		}
+		message = append(message, payload...)
+		if fin {
+			return opcode, message, nil
+		}
+	}
+}
+
+// readFrame reads a single frame, unmasking client payloads
+func (c *Conn) readFrame() (bool, int, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	if header[0]&0x70 != 0 {
+		c.closeWith(CloseProtocolError, "reserved bits set")
+		return false, 0, nil, errors.New("websocket: reserved bits set")
+	}
+	opcode := int(header[0] & 0x0F)
+	masked := header[1]&0x80 != 0
+	if masked == c.client {
+		// Clients must mask every frame and servers must not
+		c.closeWith(CloseProtocolError, "invalid masking")
+		return false, 0, nil, errors.New("websocket: invalid frame masking")
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if opcode >= OpClose && (length > 125 || !fin) {
+		c.closeWith(CloseProtocolError, "invalid control frame")
+		return false, 0, nil, errors.New("websocket: invalid control frame")
+	}
+	if length > MaxMessageSize {
+		c.closeWith(CloseMessageTooLarge, "message too large")
+		return false, 0, nil, errors.New("websocket: frame too large")
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage sends a text or binary message in a single frame
+func (c *Conn) WriteMessage(opcode int, data []byte) error {
+	return c.writeFrame(opcode, data)
+}
+
+// WriteJSON sends v as a text message
+func (c *Conn) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("websocket: failed to encode message: %v", err)
+	}
+	return c.writeFrame(OpText, data)
+}
+
+// ReadJSON reads the next message into v
+func (c *Conn) ReadJSON(v interface{}) error {
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	return c.writeRawFrame(true, opcode, payload)
+}
+
+// writeRawFrame writes a single frame, fin marking the last frame of a message
+func (c *Conn) writeRawFrame(fin bool, opcode int, payload []byte) error {
+	frame := make([]byte, 0, len(payload)+14)
+	first := byte(opcode)
+	if fin {
+		first |= 0x80
+	}
+	frame = append(frame, first)
+
+	maskBit := byte(0)
+	if c.client {
+		maskBit = 0x80
+	}
+	switch {
+	case len(payload) <= 125:
+		frame = append(frame, maskBit|byte(len(payload)))
+	case len(payload) <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(len(payload)))
+	}
+
+	if c.client {
+		var mask [4]byte
+		rand.Read(mask[:])
+		frame = append(frame, mask[:]...)
+		start := len(frame)
+		frame = append(frame, payload...)
+		for i := range payload {
+			frame[start+i] ^= mask[i%4]
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// Close sends a normal close frame and closes the connection
+func (c *Conn) Close() error {
+	return c.closeWith(CloseNormal, "")
+}
+
+// closeWith sends a close frame with code, best effort, and closes the underlying connection once
+func (c *Conn) closeWith(code int, reason string) error {
+	var err error
+	c.closeOnce.Do(func() {
+		payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+		payload = append(payload, reason...)
+		c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+		c.writeFrame(OpClose, payload)
+		err = c.conn.Close()
+	})
+	return err
+}
+
+// acceptKey computes the Sec-WebSocket-Accept value for a client key
+func acceptKey(key string) string {
+	hash := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// headerContains reports whether a comma-separated header contains token, ignoring case
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
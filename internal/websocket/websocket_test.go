@@ -0,0 +1,131 @@
+package websocket
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// echoServer echoes every message back to the client
+func echoServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			opcode, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(opcode, data); err != nil {
+				t.Errorf("Write failed: %v", err)
+				return
+			}
+		}
+	}))
+}
+
+func wsURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestEcho(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+
+	conn, err := Dial(wsURL(server), time.Second)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	for _, size := range []int{0, 5, 125, 126, 70000} {
+		message := strings.Repeat("a", size)
+		if err := conn.WriteMessage(OpText, []byte(message)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		opcode, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		if opcode != OpText || string(data) != message {
+			t.Errorf("Expected %d byte text echo, got opcode %d with %d bytes", size, opcode, len(data))
+		}
+	}
+}
+
+func TestFragmentsAndPing(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+
+	conn, err := Dial(wsURL(server), time.Second)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	// A ping between the fragments of a message is answered without breaking the message
+	frames := []struct {
+		fin     bool
+		opcode  int
+		payload string
+	}{
+		{false, OpText, "hel"},
+		{true, OpPing, "p"},
+		{true, OpContinuation, "lo"},
+	}
+	for _, frame := range frames {
+		if err := conn.writeRawFrame(frame.fin, frame.opcode, []byte(frame.payload)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	fin, opcode, payload, err := conn.readFrame()
+	if err != nil || !fin || opcode != OpPong || string(payload) != "p" {
+		t.Fatalf("Expected a pong, got %d %q %v", opcode, payload, err)
+	}
+	if _, data, err := conn.ReadMessage(); err != nil || string(data) != "hello" {
+		t.Errorf("Expected the reassembled message, got %q %v", data, err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+
+	conn, err := Dial(wsURL(server), time.Second)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	conn.writeFrame(OpClose, []byte{0x03, 0xE8})
+
+	if _, _, err := conn.ReadMessage(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected the server to answer the close frame, got %v", err)
+	}
+}
+
+func TestUpgradeRejectsPlainRequests(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestAcceptKey(t *testing.T) {
+	// Example from RFC 6455 section 1.3
+	if key := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); key != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Unexpected accept key %s", key)
+	}
+}
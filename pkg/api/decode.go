@@ -0,0 +1,38 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Response is an APIResponse whose data has the concrete type T, such as SearchResponse for the
+// search endpoint or CircuitOpenData for its 503 responses
+type Response[T any] struct {
+	Success bool   `json:"success"`
+	Data    T      `json:"data,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Decode reads a response envelope, decoding its data into T
+func Decode[T any](r io.Reader) (*Response[T], error) {
+	var response Response[T]
+	if err := json.NewDecoder(r).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode API response: %v", err)
+	}
+	return &response, nil
+}
+
+// DecodeData converts the data of an already decoded APIResponse, which encoding/json leaves as
+// maps and slices, into T
+func DecodeData[T any](response APIResponse) (T, error) {
+	var data T
+	encoded, err := json.Marshal(response.Data)
+	if err != nil {
+		return data, fmt.Errorf("failed to encode API response data: %v", err)
+	}
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return data, fmt.Errorf("failed to decode API response data: %v", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	body := `{"success":false,"data":{"error_type":"circuit_open","retry_after_seconds":30},"error":"Search backend is temporarily unavailable"}`
+
+	response, err := Decode[CircuitOpenData](strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if response.Success || response.Error == "" {
+		t.Errorf("Expected an error response, got %+v", response)
+	}
+	if response.Data.ErrorType != ErrorTypeCircuitOpen || response.Data.RetryAfterSeconds != 30 {
+		t.Errorf("Unexpected data: %+v", response.Data)
+	}
+
+	if _, err := Decode[CircuitOpenData](strings.NewReader("not json")); err == nil {
+		t.Error("Expected an error for an invalid body")
+	}
+}
+
+func TestDecodeData(t *testing.T) {
+	// Data as encoding/json leaves it when decoding into interface{}
+	response := APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"documents": []interface{}{
+				map[string]interface{}{"document": map[string]interface{}{"id": float64(7), "title": "Contact form"}, "score": 0.5},
+			},
+			"total": float64(1),
+			"mode":  "hybrid",
+		},
+	}
+
+	data, err := DecodeData[SearchResponse](response)
+	if err != nil {
+		t.Fatalf("DecodeData failed: %v", err)
+	}
+	if data.Total != 1 || data.Mode != "hybrid" || len(data.Documents) != 1 {
+		t.Fatalf("Unexpected data: %+v", data)
+	}
+	if data.Documents[0].Document.ID != 7 || data.Documents[0].Document.Title != "Contact form" || data.Documents[0].Score != 0.5 {
+		t.Errorf("Unexpected document: %+v", data.Documents[0])
+	}
+
+	if _, err := DecodeData[SearchResponse](APIResponse{Data: "text"}); err == nil {
+		t.Error("Expected an error for data of another shape")
+	}
+}
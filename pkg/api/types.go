@@ -1,5 +1,7 @@
 package api
 
+import "time"
+
 // APIResponse represents a generic API response structure
 type APIResponse struct {
 	Success bool        `json:"success"`
@@ -7,15 +9,508 @@ type APIResponse struct {
 	Error   string      `json:"error,omitempty"`
 }
 
+// SearchResponse represents the response for the search endpoint and the final ranking returned
+// by the progressive search continue endpoint
+type SearchResponse struct {
+	Documents     []SearchResult `json:"documents"`
+	Total         int            `json:"total"`
+	TotalRelation string         `json:"total_relation,omitempty"` // "eq" or "gte"
+	Page          int            `json:"page"`
+	Mode          string         `json:"mode"`
+	Pagination    string         `json:"pagination,omitempty"` // "server" or "client"
+	Stale         bool           `json:"stale,omitempty"`      // Served from cache while the backend is unavailable
+
+	Facets map[string][]FacetValue `json:"facets,omitempty"`
+
+	// Partial marks the full-text preview of a progressive search, completed with Continuation
+	Partial      bool   `json:"partial,omitempty"`
+	Continuation string `json:"continuation,omitempty"`
+
+	// Relaxation names the strategy ("or", "drop_rarest", "fuzzy" or "vector") that produced the
+	// results after the query as written matched nothing; RelaxedQuery is the query it ran
+	Relaxation   string `json:"relaxation,omitempty"`
+	RelaxedQuery string `json:"relaxed_query,omitempty"`
+
+	// Curation names the curation that pinned or hid documents of the results
+	Curation string `json:"curation,omitempty"`
+
+	// Collapsed is the number of near-duplicate results removed from the page with dedup=true
+	Collapsed int `json:"collapsed,omitempty"`
+
+	// QueryClass is how mode=auto classified the query, "keyword" or "natural_language"; Mode is
+	// the mode it was routed to
+	QueryClass string `json:"query_class,omitempty"`
+
+	// IndexGeneration is the index generation the results were computed at
+	IndexGeneration uint64 `json:"index_generation,omitempty"`
+
+	// Debug explains how the query was processed, returned for debug=true
+	Debug *SearchDebug `json:"debug,omitempty"`
+}
+
+// SearchDebug explains how a search processed its query
+type SearchDebug struct {
+	Language           string  `json:"language,omitempty"` // Language whose stopwords were removed, empty when undetermined
+	LanguageConfidence float64 `json:"language_confidence"`
+	LanguageSource     string  `json:"language_source"`   // "detected" or "requested"
+	FullTextQuery      string  `json:"full_text_query"`   // Query sent to Manticore after stopword removal
+	Routing            string  `json:"routing,omitempty"` // Rule that classified the query for mode=auto
+	// Features lists the results of the page whose scores were weighted by scoring features
+	Features []FeatureMatch `json:"features,omitempty"`
+}
+
+// FeatureMatch is the scoring features a result has for the query, such as exact_title, and the
+// product of their weights
+type FeatureMatch struct {
+	ID       int      `json:"id"`
+	Features []string `json:"features"`
+	Factor   float64  `json:"factor"`
+}
+
+// SearchResult represents a matching document and its score
+type SearchResult struct {
+	Document Document `json:"document"`
+	Score    float64  `json:"score"`
+	Pinned   bool     `json:"pinned,omitempty"` // Placed first by a curation rather than by its score
+}
+
+// Document represents an indexed document; fields not selected with the fields parameter are empty
+type Document struct {
+	ID        int      `json:"id"`
+	Title     string   `json:"title,omitempty"`
+	URL       string   `json:"url,omitempty"`
+	Content   string   `json:"content,omitempty"`
+	Snippet   string   `json:"snippet,omitempty"`
+	Status    string   `json:"status,omitempty"`
+	IndexedAt int64    `json:"indexed_at,omitempty"` // Unix seconds
+	UpdatedAt int64    `json:"updated_at,omitempty"` // Unix seconds
+	Tags      []string `json:"tags,omitempty"`
+}
+
+// FacetValue is the number of matching documents with a facet value. For histogram facets the
+// value is the lower bound of a bucket.
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Values of the error_type field of error response data
+const (
+	ErrorTypeCircuitOpen         = "circuit_open"
+	ErrorTypeAISearchUnavailable = "ai_search_unavailable"
+	ErrorTypeAISearchFailure     = "ai_search_failure"
+	ErrorTypeSearchFailure       = "search_failure"
+	ErrorTypeValidation          = "validation_failed"
+	ErrorTypeQuotaExceeded       = "quota_exceeded"
+)
+
+// CircuitOpenData is the data of a 503 search response sent while the circuit breaker is open and
+// no cached results are available; the Retry-After header carries the same delay
+type CircuitOpenData struct {
+	ErrorType         string `json:"error_type"`
+	RetryAfterSeconds int    `json:"retry_after_seconds"`
+}
+
+// AISearchUnavailableData is the data of a 503 response to an AI search that cannot run
+type AISearchUnavailableData struct {
+	ErrorType      string   `json:"error_type"`
+	Reason         string   `json:"reason"`
+	SuggestedModes []string `json:"suggested_modes"`
+	AIEnabled      bool     `json:"ai_enabled"`
+}
+
+// AISearchFailureData is the data of a 500 response to an AI search whose fallback search failed too
+type AISearchFailureData struct {
+	ErrorType      string   `json:"error_type"`
+	ErrorCategory  string   `json:"error_category"` // timeout, network, embedding, model, client_error, server_error, schema, circuit_open, unsupported or unknown
+	AIError        string   `json:"ai_error"`
+	FallbackError  string   `json:"fallback_error"`
+	SuggestedModes []string `json:"suggested_modes"`
+	RetrySuggested bool     `json:"retry_suggested"`
+}
+
+// SearchFailureData is the data of a 500 response to a failed search
+type SearchFailureData struct {
+	ErrorType      string `json:"error_type"`
+	ErrorCategory  string `json:"error_category"` // like AISearchFailureData.ErrorCategory
+	RetrySuggested bool   `json:"retry_suggested"`
+}
+
+// ValidationErrorData is the data of a 400 response to a request with invalid parameters
+type ValidationErrorData struct {
+	ErrorType string       `json:"error_type"`
+	Errors    []FieldError `json:"errors"`
+}
+
+// FieldError is an invalid request parameter
+type FieldError struct {
+	Field   string   `json:"field"`
+	Code    string   `json:"code"`    // required, out_of_range, too_small, too_long, invalid_value, invalid_format, invalid_characters or no_terms
+	Message string   `json:"message"` // In the language negotiated from Accept-Language
+	Value   string   `json:"value,omitempty"`
+	Min     *int     `json:"min,omitempty"`
+	Max     *int     `json:"max,omitempty"`     // Largest value, or the longest length of too_long errors
+	Allowed []string `json:"allowed,omitempty"` // Allowed values, or the accepted formats of invalid_format errors
+}
+
+// QuotaExceededData is the data of a 429 response to a caller that used up a daily quota; the
+// Retry-After header carries the seconds until it resets
+type QuotaExceededData struct {
+	ErrorType string    `json:"error_type"`
+	Key       string    `json:"key"`
+	Metric    string    `json:"metric"` // searches, indexed_documents or embedding_calls
+	Limit     int64     `json:"limit"`
+	Used      int64     `json:"used"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
 // StatusResponse represents the response for the status endpoint
 type StatusResponse struct {
 	Status           string `json:"status"`
-	ManticoreHealthy bool   `json:"manticore_healthy"`
+	ManticoreHealthy bool   `json:"manticore_healthy"`         // Manticore answers, whether or not the schema exists
+	ManticoreState   string `json:"manticore_state,omitempty"` // down, up, schema_missing or ready
+	ManticoreVersion string `json:"manticore_version,omitempty"`
 	DocumentsLoaded  int    `json:"documents_loaded"`
 	VectorizerReady  bool   `json:"vectorizer_ready"`
 	AISearchEnabled  bool   `json:"ai_search_enabled"`
 	AIModel          string `json:"ai_model,omitempty"`
 	AISearchHealthy  bool   `json:"ai_search_healthy"`
+	ConnectionState  string `json:"connection_state,omitempty"`
+
+	SchemaPresent bool     `json:"schema_present"`
+	MissingTables []string `json:"missing_tables,omitempty"`
+
+	Tables         []TableStatus `json:"tables,omitempty"`
+	LastReindex    *time.Time    `json:"last_reindex,omitempty"`
+	VocabularySize int           `json:"vocabulary_size"`
+	// IndexGeneration advances with every reindex, restore and document change. It restarts at
+	// zero with the server, so compare generations for equality only.
+	IndexGeneration uint64 `json:"index_generation"`
+
+	CircuitBreakerTransitions []CircuitBreakerTransition `json:"circuit_breaker_transitions,omitempty"`
+	// LiveCircuitBreakers counts the circuit breakers of every Manticore client not yet closed,
+	// two per client; a number that keeps growing points at leaked clients
+	LiveCircuitBreakers int `json:"live_circuit_breakers"`
+
+	// Consistency is the outcome of the startup comparison of the data directory with the index;
+	// absent until it ran or when STARTUP_CONSISTENCY_CHECK is off
+	Consistency *ConsistencyReport `json:"consistency,omitempty"`
+
+	// Maintenance lists the tasks scheduled by MAINTENANCE_SCHEDULE and their last runs
+	Maintenance []MaintenanceTask `json:"maintenance,omitempty"`
+
+	// SearchLatency reports the latencies of each search mode within SEARCH_SLO_WINDOW and the
+	// state of the objectives of SEARCH_SLO
+	SearchLatency []SearchLatencyStatus `json:"search_latency,omitempty"`
+}
+
+// SearchLatencyStatus describes the latencies of the searches of a mode and its latency objectives
+type SearchLatencyStatus struct {
+	Mode       string               `json:"mode"`
+	Searches   int                  `json:"searches"` // within the window
+	P50        string               `json:"p50"`
+	P95        string               `json:"p95"`
+	P99        string               `json:"p99"`
+	Objectives []SLOObjectiveStatus `json:"objectives,omitempty"`
+}
+
+// SLOObjectiveStatus describes a latency objective of a search mode at its last check
+type SLOObjectiveStatus struct {
+	Percentile      int        `json:"percentile"`
+	Threshold       string     `json:"threshold"`
+	Latency         string     `json:"latency"`
+	State           string     `json:"state"`            // ok, over_budget or burning
+	ConsecutiveOver int        `json:"consecutive_over"` // checks in a row over budget
+	BurningSince    *time.Time `json:"burning_since,omitempty"`
+}
+
+// MaintenanceTask describes a scheduled maintenance task
+type MaintenanceTask struct {
+	Task         string     `json:"task"`
+	Schedule     string     `json:"schedule"`
+	NextRun      *time.Time `json:"next_run,omitempty"` // absent while running
+	LastRun      *time.Time `json:"last_run,omitempty"`
+	LastDuration string     `json:"last_duration,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	Runs         int        `json:"runs"`
+	Running      bool       `json:"running"`
+}
+
+// ConsistencyReport describes how the indexed corpus differs from the documents of the data directory
+type ConsistencyReport struct {
+	CheckedAt        time.Time `json:"checked_at"`
+	Consistent       bool      `json:"consistent"`
+	SourceDocuments  int       `json:"source_documents"`
+	IndexedDocuments int       `json:"indexed_documents"`
+	IndexedVectors   int       `json:"indexed_vectors"`
+
+	Missing        ConsistencyIDs `json:"missing"`         // source documents absent from the documents table
+	MissingVectors ConsistencyIDs `json:"missing_vectors"` // source documents without a vector row
+	Orphaned       ConsistencyIDs `json:"orphaned"`        // vector rows without a document
+	Extra          ConsistencyIDs `json:"extra"`           // indexed documents not in the data directory
+
+	Repaired    int    `json:"repaired,omitempty"`
+	RepairError string `json:"repair_error,omitempty"`
+}
+
+// OrphanCleanupResponse represents the response for the orphan cleanup endpoint
+type OrphanCleanupResponse struct {
+	CheckedAt          time.Time      `json:"checked_at"`
+	DryRun             bool           `json:"dry_run"`
+	OrphanedVectors    ConsistencyIDs `json:"orphaned_vectors"` // vector rows without a document
+	MissingVectors     ConsistencyIDs `json:"missing_vectors"`  // documents without a vector row
+	DeletedVectors     int            `json:"deleted_vectors"`
+	ReindexedDocuments int            `json:"reindexed_documents"`
+}
+
+// RetentionResponse represents the response for the retention endpoint
+type RetentionResponse struct {
+	CheckedAt time.Time      `json:"checked_at"`
+	DryRun    bool           `json:"dry_run"`
+	Policy    string         `json:"policy"`
+	Documents int            `json:"documents"` // indexed documents evaluated
+	Expired   ConsistencyIDs `json:"expired"`   // documents older than the maximum age
+	Excess    ConsistencyIDs `json:"excess"`    // documents beyond the maximum count
+	Deleted   int            `json:"deleted"`
+}
+
+// ReembedRequest starts re-embedding the documents with another embedding model
+type ReembedRequest struct {
+	Model      string `json:"model"`
+	Similarity string `json:"similarity,omitempty"` // keeps the current similarity metric when empty
+}
+
+// ReembedStatus reports the re-embedding of the documents with another embedding model
+type ReembedStatus struct {
+	State      string     `json:"state"` // idle, running, completed or failed
+	Model      string     `json:"model,omitempty"`
+	Copied     int64      `json:"copied"` // documents embedded into the new table so far
+	Total      int64      `json:"total"`  // documents to embed
+	Table      string     `json:"table,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// ConsistencyIDs counts a set of document ids and lists the lowest of them
+type ConsistencyIDs struct {
+	Count int     `json:"count"`
+	IDs   []int64 `json:"ids,omitempty"`
+}
+
+// TableStatus reports the size of a Manticore table
+type TableStatus struct {
+	Name      string `json:"name"`
+	Exists    bool   `json:"exists"`
+	Documents int64  `json:"documents"`
+	DiskBytes int64  `json:"disk_bytes"`
+	RAMBytes  int64  `json:"ram_bytes"`
+}
+
+// IndexStatsResponse represents the response for the index statistics endpoint
+type IndexStatsResponse struct {
+	Documents             int     `json:"documents"`               // Documents indexed by the service
+	AverageDocumentLength float64 `json:"average_document_length"` // Average characters of title and content
+	VocabularySize        int     `json:"vocabulary_size"`
+	VectorDimension       int     `json:"vector_dimension"` // Dimension of the TF-IDF vectors; 0 before indexing
+
+	DocumentStore DocumentStoreStats `json:"document_store"`
+	Tables        []IndexTableStats  `json:"tables"`
+}
+
+// DocumentStoreStats reports the documents kept in memory and how often they are loaded from Manticore
+type DocumentStoreStats struct {
+	Cached       int    `json:"cached"`
+	SizeBytes    int64  `json:"size_bytes"` // Approximate memory of the cached documents
+	MaxDocuments int    `json:"max_documents"`
+	MaxSizeBytes int64  `json:"max_size_bytes"` // 0 when unlimited, as max_documents
+	Hits         uint64 `json:"hits"`
+	Misses       uint64 `json:"misses"`
+	Evictions    uint64 `json:"evictions"`
+}
+
+// IndexTableStats reports SHOW INDEX STATUS of a Manticore table
+type IndexTableStats struct {
+	Name         string `json:"name"`
+	Exists       bool   `json:"exists"`
+	Documents    int64  `json:"documents"`
+	IndexedBytes int64  `json:"indexed_bytes"`
+	DiskBytes    int64  `json:"disk_bytes"`
+	RAMBytes     int64  `json:"ram_bytes"`
+	DiskChunks   int64  `json:"disk_chunks"`
+	Optimizing   bool   `json:"optimizing"`
+	// LastOptimize is when POST /api/admin/optimize last ran for the table; omitted when it has
+	// not since the server started
+	LastOptimize *time.Time `json:"last_optimize,omitempty"`
+}
+
+// TermStatsResponse represents the response for the vocabulary endpoint
+type TermStatsResponse struct {
+	Prefix         string      `json:"prefix,omitempty"`
+	VocabularySize int         `json:"vocabulary_size"`
+	Total          int         `json:"total"` // Vocabulary words matching the prefix
+	Terms          []TermStats `json:"terms"`
+}
+
+// PopularSuggestionsResponse represents the response for the popular query suggestions endpoint
+type PopularSuggestionsResponse struct {
+	Prefix      string         `json:"prefix,omitempty"`
+	Suggestions []PopularQuery `json:"suggestions"`
+	Count       int            `json:"count"`
+}
+
+// PopularQuery is a query searched with results, and how often
+type PopularQuery struct {
+	Query        string    `json:"query"`
+	Count        int64     `json:"count"`
+	LastSearched time.Time `json:"last_searched"`
+}
+
+// AIModelsResponse represents the response for the AI models endpoint
+type AIModelsResponse struct {
+	Provider        string    `json:"provider"`                  // Computes the embeddings: manticore_auto_embeddings
+	Enabled         bool      `json:"enabled"`                   // MANTICORE_AI_ENABLED
+	ConfiguredModel string    `json:"configured_model"`          // Model new documents tables are created with
+	AutoEmbeddings  *bool     `json:"auto_embeddings,omitempty"` // Server support, omitted until detected
+	Healthy         bool      `json:"healthy"`                   // Whether a test AI search succeeded
+	Error           string    `json:"error,omitempty"`           // Why AI search is not healthy
+	LatencyMS       int64     `json:"latency_ms,omitempty"`      // Duration of the test AI search
+	CircuitBreaker  string    `json:"circuit_breaker,omitempty"` // State of the embedding circuit breaker
+	Models          []AIModel `json:"models"`
+	Warnings        []string  `json:"warnings,omitempty"`
+}
+
+// AIModel is the embedding model of a vector column Manticore fills with Auto Embeddings
+type AIModel struct {
+	Model      string `json:"model"`
+	Table      string `json:"table"`
+	Column     string `json:"column"`
+	Dimension  int    `json:"dimension,omitempty"` // Omitted when Manticore doesn't report it
+	Source     string `json:"source,omitempty"`    // Fields the embedding is computed from
+	Similarity string `json:"similarity,omitempty"`
+	Configured bool   `json:"configured"` // Whether it is the configured model
+}
+
+// TermStats reports a vocabulary word of the TF-IDF vectorizer
+type TermStats struct {
+	Term              string  `json:"term"`
+	DocumentFrequency int     `json:"document_frequency,omitempty"` // Omitted for vocabularies restored from older backups
+	IDF               float64 `json:"idf"`
+}
+
+// CircuitBreakerTransition represents a recent circuit breaker state change
+type CircuitBreakerTransition struct {
+	From                string    `json:"from"`
+	To                  string    `json:"to"`
+	Reason              string    `json:"reason"`
+	Endpoint            string    `json:"endpoint"`
+	Timestamp           time.Time `json:"timestamp"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	FailureRate         float64   `json:"failure_rate"`
+}
+
+// ResilienceResponse represents the response for the resilience status endpoint
+type ResilienceResponse struct {
+	CircuitBreaker        CircuitBreakerStatus            `json:"circuit_breaker"`
+	CircuitBreakerByClass map[string]CircuitBreakerStatus `json:"circuit_breaker_by_class,omitempty"` // operation classes with their own breaker: embedding
+	Retry                 RetryStatus                     `json:"retry"`
+	RetryByClass          map[string]RetryStatus          `json:"retry_by_class,omitempty"` // per operation class: search, bulk, embedding
+	Transitions           []CircuitBreakerTransition      `json:"transitions"`
+	FaultInjection        *FaultInjectionStatus           `json:"fault_injection,omitempty"` // only while MANTICORE_FAULT_* inject faults
+	ConnectionPool        ConnectionPoolStatus            `json:"connection_pool"`
+}
+
+// FaultInjectionStatus counts the faults injected into Manticore requests
+type FaultInjectionStatus struct {
+	Requests  int64 `json:"requests"`
+	Latencies int64 `json:"latencies"`
+	Errors    int64 `json:"errors"`
+	Resets    int64 `json:"resets"`
+}
+
+// ConnectionPoolStatus describes the pool settings and connections of the Manticore HTTP client
+type ConnectionPoolStatus struct {
+	MaxIdleConnsPerHost int      `json:"max_idle_conns_per_host"`
+	MaxConnsPerHost     int      `json:"max_conns_per_host"` // 0 for no limit
+	IdleConnTimeout     string   `json:"idle_conn_timeout"`
+	Open                int64    `json:"open"`
+	Active              int64    `json:"active"`
+	Idle                int64    `json:"idle"`
+	Dials               int64    `json:"dials"`
+	Reused              int64    `json:"reused"`
+	Waits               int64    `json:"waits"` // requests that waited for one of max_conns_per_host connections
+	WaitTime            string   `json:"wait_time"`
+	Addresses           []string `json:"addresses,omitempty"` // addresses the Manticore host last resolved to, when re-resolved
+	DNSRefreshes        int64    `json:"dns_refreshes"`
+	AddressChanges      int64    `json:"address_changes"`
+	Rebuilds            int64    `json:"rebuilds"`
+}
+
+// CircuitBreakerStatus describes the current circuit breaker state and counters
+type CircuitBreakerStatus struct {
+	State                string    `json:"state"`
+	FailureRate          float64   `json:"failure_rate"`
+	ConsecutiveFailures  int       `json:"consecutive_failures"`
+	ConsecutiveSuccesses int       `json:"consecutive_successes"`
+	TotalRequests        int64     `json:"total_requests"`
+	TotalFailures        int64     `json:"total_failures"`
+	TotalSuccesses       int64     `json:"total_successes"`
+	TotalRejections      int64     `json:"total_rejections"`
+	StateChanges         int64     `json:"state_changes"`
+	LastStateChange      time.Time `json:"last_state_change"`
+	LastFailureTime      time.Time `json:"last_failure_time,omitempty"`
+}
+
+// RetryStatus describes the retry configuration and outcomes
+type RetryStatus struct {
+	MaxAttempts          int              `json:"max_attempts"`
+	BaseDelay            string           `json:"base_delay"`
+	MaxDelay             string           `json:"max_delay"`
+	RetryableErrors      []string         `json:"retryable_errors,omitempty"` // empty when the default classification applies
+	TotalOperations      int64            `json:"total_operations"`
+	TotalRetries         int64            `json:"total_retries"`
+	SucceededAfterRetry  int64            `json:"succeeded_after_retry"`
+	RetriesExhausted     int64            `json:"retries_exhausted"`
+	NonRetryableFailures int64            `json:"non_retryable_failures"`
+	RetriesByErrorType   map[string]int64 `json:"retries_by_error_type"`
+	LastRetryTime        time.Time        `json:"last_retry_time,omitempty"`
+	LastRetryError       string           `json:"last_retry_error,omitempty"`
+	Outcomes             []RetryOutcome   `json:"outcomes"`
+}
+
+// RetryOutcome counts the operations on an endpoint that ended with an outcome:
+// success_first_try, success_after_retry, exhausted or non_retryable. ErrorType is the type of
+// the last error, "none" for successes on the first try.
+type RetryOutcome struct {
+	Endpoint  string `json:"endpoint"`
+	ErrorType string `json:"error_type"`
+	Outcome   string `json:"outcome"`
+	Count     int64  `json:"count"`
+}
+
+// AuditLogResponse represents the response for the audit log endpoint
+type AuditLogResponse struct {
+	Entries []AuditEntry `json:"entries"`
+	Count   int          `json:"count"`
+}
+
+// AuditEntry represents a recorded admin operation
+type AuditEntry struct {
+	ID         int64                  `json:"id"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Actor      string                 `json:"actor"`
+	RemoteAddr string                 `json:"remote_addr,omitempty"`
+	Action     string                 `json:"action"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Outcome    string                 `json:"outcome"`
+	Error      string                 `json:"error,omitempty"`
+	Duration   string                 `json:"duration"`
+}
+
+// HealthResponse represents the response for the liveness and readiness probes
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
 }
 
 // ReindexResponse represents the response for the reindex endpoint
@@ -23,4 +518,286 @@ type ReindexResponse struct {
 	Message        string `json:"message"`
 	DocumentsCount int    `json:"documents_count"`
 	IndexingTime   string `json:"indexing_time"`
+	// RejectedCount is the number of documents that failed validation and were not indexed
+	RejectedCount int `json:"rejected_count"`
+	// Rejected lists up to 100 of the rejected documents, all of which are in the dead-letter store
+	Rejected []DocumentRejection `json:"rejected,omitempty"`
+	// ExpiredCount is the number of valid documents the retention policy kept from being indexed
+	ExpiredCount int `json:"expired_count,omitempty"`
+	// EmbeddingTruncatedCount is the number of indexed documents longer than the input limit of
+	// the AI model, which only embeds their beginning
+	EmbeddingTruncatedCount int `json:"embedding_truncated_count,omitempty"`
+	// EmbeddingTruncated lists up to 100 of them
+	EmbeddingTruncated []EmbeddingTruncation `json:"embedding_truncated,omitempty"`
+}
+
+// EmbeddingTruncation is a document the AI model only embeds the beginning of
+type EmbeddingTruncation struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Tokens int    `json:"tokens"` // Estimated tokens of the content
+}
+
+// DocumentRejection is a document that failed validation, with the reasons it failed
+type DocumentRejection struct {
+	ID      int      `json:"id"`
+	Title   string   `json:"title,omitempty"`
+	URL     string   `json:"url,omitempty"`
+	Reasons []string `json:"reasons"`
+}
+
+// DeadLetterResponse represents the response for the dead-letter endpoint
+type DeadLetterResponse struct {
+	Entries []DeadLetterEntry `json:"entries"`
+	Count   int               `json:"count"`
+}
+
+// DeadLetterEntry is a document rejected by ingestion
+type DeadLetterEntry struct {
+	ID        int64     `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source"`
+	Document  Document  `json:"document"`
+	Reasons   []string  `json:"reasons"`
+}
+
+// RecordingsResponse represents the response for the recordings endpoint
+type RecordingsResponse struct {
+	Enabled    bool               `json:"enabled"`
+	SampleRate float64            `json:"sample_rate"`
+	Capacity   int                `json:"capacity"`
+	Recorded   int64              `json:"recorded"` // since the server started, including those dropped from the buffer
+	Exchanges  []RecordedExchange `json:"exchanges"`
+	Count      int                `json:"count"`
+}
+
+// RecordedExchange is a recorded request to Manticore and its response
+type RecordedExchange struct {
+	ID                int64     `json:"id"`
+	Time              time.Time `json:"time"`
+	Method            string    `json:"method"`
+	URL               string    `json:"url"`
+	RequestBody       string    `json:"request_body"`
+	RequestTruncated  bool      `json:"request_truncated"`
+	StatusCode        int       `json:"status_code,omitempty"`
+	ResponseBody      string    `json:"response_body"`
+	ResponseTruncated bool      `json:"response_truncated"`
+	Duration          string    `json:"duration"`
+	Error             string    `json:"error,omitempty"`
+}
+
+// BackupResponse represents the response for the backup endpoint
+type BackupResponse struct {
+	Name            string    `json:"name"`
+	Path            string    `json:"path"`
+	CreatedAt       time.Time `json:"created_at"`
+	Documents       int       `json:"documents"`
+	SchemaVersion   int       `json:"schema_version"`
+	ManticoreBackup string    `json:"manticore_backup,omitempty"`
+	BackupTime      string    `json:"backup_time"`
+}
+
+// TableOperationResponse represents the response for the table reset and truncate endpoints
+type TableOperationResponse struct {
+	Action string           `json:"action"` // reset or truncate
+	DryRun bool             `json:"dry_run"`
+	Tables []TableOperation `json:"tables"`
+}
+
+// TableOperation reports a table affected by a reset or truncate
+type TableOperation struct {
+	Name      string `json:"name"`
+	Exists    bool   `json:"exists"`
+	Documents int64  `json:"documents"` // Documents removed, or that would be removed in a dry run
+}
+
+// RestoreResponse represents the response for the restore endpoint
+type RestoreResponse struct {
+	Name        string `json:"name"`
+	Documents   int    `json:"documents"`
+	RestoreTime string `json:"restore_time"`
+}
+
+// DocumentStatusResponse represents the response for the document archive, restore and delete endpoints
+type DocumentStatusResponse struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+// DocumentTagsResponse represents the response for the document tags endpoint
+type DocumentTagsResponse struct {
+	ID   int64    `json:"id"`
+	Tags []string `json:"tags"`
+}
+
+// CountResponse represents the response for the count endpoint
+type CountResponse struct {
+	Query   string            `json:"query"`
+	Filters map[string]string `json:"filters,omitempty"`
+	Count   int64             `json:"count"`
+}
+
+// SavedSearchListResponse represents the response for listing saved searches
+type SavedSearchListResponse struct {
+	SavedSearches []SavedSearch `json:"saved_searches"`
+	Count         int           `json:"count"`
+}
+
+// SavedSearch represents a saved search and the state of its last scheduled run
+type SavedSearch struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Query       string     `json:"query"`
+	Mode        string     `json:"mode"`
+	Status      string     `json:"status,omitempty"`
+	Tags        string     `json:"tags,omitempty"`
+	TagsMode    string     `json:"tags_mode,omitempty"`
+	Webhook     string     `json:"webhook,omitempty"`
+	Email       string     `json:"email,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastNew     int        `json:"last_new"`
+	KnownCount  int        `json:"known_results"`
+	AlertsTotal int64      `json:"alerts_total"`
+}
+
+// SavedSearchDeleteResponse represents the response for deleting a saved search
+type SavedSearchDeleteResponse struct {
+	ID      int64 `json:"id"`
+	Deleted bool  `json:"deleted"`
+}
+
+// QueryTemplate represents a named search whose values may contain {{name}} placeholders, run with
+// the template and params parameters of the search endpoint
+type QueryTemplate struct {
+	Name        string                        `json:"name"`
+	Description string                        `json:"description,omitempty"`
+	Query       string                        `json:"query"`
+	Mode        string                        `json:"mode,omitempty"`
+	Fields      string                        `json:"fields,omitempty"`
+	Status      string                        `json:"status,omitempty"`
+	Sort        string                        `json:"sort,omitempty"`
+	Since       string                        `json:"since,omitempty"`
+	Tags        string                        `json:"tags,omitempty"`
+	TagsMode    string                        `json:"tags_mode,omitempty"`
+	Limit       int                           `json:"limit,omitempty"`
+	Params      map[string]QueryTemplateParam `json:"params,omitempty"`
+	CreatedAt   time.Time                     `json:"created_at"`
+	UpdatedAt   time.Time                     `json:"updated_at"`
+}
+
+// QueryTemplateParam declares a placeholder of a query template; one without a default is required
+type QueryTemplateParam struct {
+	Description string  `json:"description,omitempty"`
+	Default     *string `json:"default,omitempty"`
+}
+
+// QueryTemplateListResponse represents the response for listing query templates
+type QueryTemplateListResponse struct {
+	Templates []QueryTemplate `json:"templates"`
+	Count     int             `json:"count"`
+}
+
+// QueryTemplateDeleteResponse represents the response for deleting a query template
+type QueryTemplateDeleteResponse struct {
+	Name    string `json:"name"`
+	Deleted bool   `json:"deleted"`
+}
+
+// Curation pins documents to the top of the first page of the queries matching its pattern and
+// hides others from their results ("best bets")
+type Curation struct {
+	Name      string    `json:"name"`
+	Pattern   string    `json:"pattern"`
+	Match     string    `json:"match,omitempty"` // "exact" (the default) or "phrase"
+	Pinned    []int     `json:"pinned,omitempty"`
+	Hidden    []int     `json:"hidden,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CurationListResponse represents the response for listing curations
+type CurationListResponse struct {
+	Curations []Curation `json:"curations"`
+	Count     int        `json:"count"`
+}
+
+// CurationDeleteResponse represents the response for deleting a curation
+type CurationDeleteResponse struct {
+	Name    string `json:"name"`
+	Deleted bool   `json:"deleted"`
+}
+
+// StreamRequest is a message sent by a client of the /api/ws endpoint
+type StreamRequest struct {
+	Type     string `json:"type"` // "search", "suggest" or "cancel"
+	ID       string `json:"id"`   // Chosen by the client, echoed in every reply to the request
+	Query    string `json:"query,omitempty"`
+	Mode     string `json:"mode,omitempty"`
+	Page     int    `json:"page,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Tags     string `json:"tags,omitempty"`
+	TagsMode string `json:"tags_mode,omitempty"`
+	// IDs and ExcludeIDs are comma-separated document ids to restrict results to and to hide
+	IDs        string `json:"ids,omitempty"`
+	ExcludeIDs string `json:"exclude_ids,omitempty"`
+	// Regex is a field and an RE2 pattern its value must match, such as url:^https://go\.dev/
+	Regex string `json:"regex,omitempty"`
+	// Histogram is a comma-separated list of field:interval facets, such as updated_at:day
+	Histogram string `json:"histogram,omitempty"`
+	// RescoreWindow is the number of candidates each hybrid leg contributes, 0 for the server default
+	RescoreWindow int `json:"rescore_window,omitempty"`
+	// KNNK and KNNEf tune the nearest neighbour search of AI searches, 0 for the server default
+	KNNK  int `json:"knn_k,omitempty"`
+	KNNEf int `json:"knn_ef,omitempty"`
+	// Language selects the stopwords of the query instead of detecting its language
+	Language string `json:"language,omitempty"`
+	// Debug adds how the query was processed to the results
+	Debug bool `json:"debug,omitempty"`
+	// Dedup collapses near-duplicate results
+	Dedup bool `json:"dedup,omitempty"`
+}
+
+// StreamMessage is a message sent to a client of the /api/ws endpoint
+type StreamMessage struct {
+	Type        string          `json:"type"` // "results", "suggestions", "cancelled" or "error"
+	ID          string          `json:"id,omitempty"`
+	Stage       string          `json:"stage,omitempty"` // "fulltext" for a preview, "final" for the requested mode
+	Final       bool            `json:"final,omitempty"` // No further results follow for the request
+	Results     *SearchResponse `json:"results,omitempty"`
+	Suggestions []string        `json:"suggestions,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// ContinuationPendingResponse represents the response for a progressive search whose final
+// ranking is not ready yet
+type ContinuationPendingResponse struct {
+	Continuation string `json:"continuation"`
+	Pending      bool   `json:"pending"`
+}
+
+// UsageResponse is the usage of an API key on the current UTC day
+type UsageResponse struct {
+	Key              string      `json:"key"`
+	Day              string      `json:"day"` // YYYY-MM-DD
+	ResetsAt         time.Time   `json:"resets_at"`
+	Searches         MetricUsage `json:"searches"`
+	IndexedDocuments MetricUsage `json:"indexed_documents"`
+	EmbeddingCalls   MetricUsage `json:"embedding_calls"`
+}
+
+// MetricUsage is the daily usage of a metric and its quota
+type MetricUsage struct {
+	Used      int64  `json:"used"`
+	Limit     int64  `json:"limit,omitempty"`     // Daily quota, omitted when unlimited
+	Remaining *int64 `json:"remaining,omitempty"` // Omitted when unlimited
+}
+
+// UsageListResponse lists the usage of every API key with usage on the current day
+type UsageListResponse struct {
+	Keys  []UsageResponse `json:"keys"`
+	Count int             `json:"count"`
 }
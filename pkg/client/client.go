@@ -0,0 +1,274 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// RetryConfig controls how failed requests are retried. Network errors and 429, 502, 503 and 504
+// responses are retried with exponential backoff and jitter, waiting at least as long as a
+// Retry-After header asks, up to MaxDelay. An exceeded daily usage quota is not retried. Requests that are not safe to repeat, such as creating a
+// saved search, are never retried.
+type RetryConfig struct {
+	MaxAttempts int           // Attempts including the first one; 1 disables retries
+	BaseDelay   time.Duration // Delay before the first retry, doubled for each following one
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryConfig returns the retry configuration used by New
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: 3,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+	}
+}
+
+// Client calls the REST API of a manticoresearch-go server. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	retry      RetryConfig
+	header     http.Header
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests (default: a client with a 30s timeout)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetry sets the retry configuration
+func WithRetry(retry RetryConfig) Option {
+	return func(c *Client) {
+		c.retry = retry
+	}
+}
+
+// WithAPIKey authenticates every request with an API key of the server's API_KEYS
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.header.Set("X-API-Key", key)
+	}
+}
+
+// WithTenant selects the tenant of every request, for API keys that are not bound to a tenant
+func WithTenant(tenant string) Option {
+	return func(c *Client) {
+		c.header.Set("X-Tenant", tenant)
+	}
+}
+
+// WithHeader adds a header to every request, such as X-Forwarded-User, which names the actor in
+// the audit log
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Add(key, value)
+	}
+}
+
+// New creates a client for the server at baseURL, such as "http://localhost:8080"
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("client: invalid base URL %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    parsed,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		retry:      DefaultRetryConfig(),
+		header:     make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c, nil
+}
+
+// Error is returned when the server answers with an error response
+type Error struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration // From the Retry-After header, zero when absent
+	ErrorType  string        // error_type of the response data, such as validation_failed or quota_exceeded
+
+	// FieldErrors lists the invalid parameters of a 400 validation_failed response
+	FieldErrors []api.FieldError
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("client: HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 response, such as for an unknown document or saved search
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsQuotaExceeded reports whether err is a 429 response for an API key that has used up a daily
+// quota; RetryAfter tells when the quota resets
+func IsQuotaExceeded(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.ErrorType == api.ErrorTypeQuotaExceeded
+}
+
+// request describes an API call
+type request struct {
+	method    string
+	path      string
+	params    url.Values
+	body      []byte // JSON request body, nil for none
+	retryable bool   // Safe to repeat after a failure
+}
+
+// do performs a request with retries and decodes the data of a successful response into out. It
+// returns the HTTP status code of the last response.
+func (c *Client) do(ctx context.Context, req request, out interface{}) (int, error) {
+	delay := c.retry.BaseDelay
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		statusCode, err := c.send(ctx, req, out)
+		if err == nil || !req.retryable || attempt >= c.retry.MaxAttempts || !isRetryable(ctx, err) {
+			return statusCode, err
+		}
+		lastErr = err
+
+		wait := delay
+		var apiErr *Error
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > wait {
+			wait = apiErr.RetryAfter
+		}
+		if wait > c.retry.MaxDelay {
+			wait = c.retry.MaxDelay
+		}
+		if wait > 0 {
+			wait = wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return statusCode, fmt.Errorf("client: %v (last error: %v)", ctx.Err(), lastErr)
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// send makes a single attempt
+func (c *Client) send(ctx context.Context, req request, out interface{}) (int, error) {
+	target := *c.baseURL
+	target.Path += req.path
+	target.RawQuery = req.params.Encode()
+
+	var reqBody io.Reader
+	if req.body != nil {
+		reqBody = bytes.NewReader(req.body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target.String(), reqBody)
+	if err != nil {
+		return 0, fmt.Errorf("client: failed to create request: %v", err)
+	}
+	for key, values := range c.header {
+		httpReq.Header[key] = values
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if req.body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("client: failed to read response: %v", err)
+	}
+
+	// Keep the data for decoding into out
+	var envelope api.Response[json.RawMessage]
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		if resp.StatusCode >= 300 {
+			return resp.StatusCode, &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body)), RetryAfter: retryAfter(resp)}
+		}
+		return resp.StatusCode, fmt.Errorf("client: failed to decode response: %v", err)
+	}
+	if resp.StatusCode >= 300 || !envelope.Success {
+		apiErr := &Error{StatusCode: resp.StatusCode, Message: envelope.Error, RetryAfter: retryAfter(resp)}
+		var validationData api.ValidationErrorData
+		if len(envelope.Data) > 0 && json.Unmarshal(envelope.Data, &validationData) == nil {
+			apiErr.ErrorType = validationData.ErrorType
+			if validationData.ErrorType == api.ErrorTypeValidation {
+				apiErr.FieldErrors = validationData.Errors
+			}
+		}
+		return resp.StatusCode, apiErr
+	}
+
+	if out != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("client: failed to decode response data: %v", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// isRetryable reports whether a failed attempt is worth repeating
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		if apiErr.ErrorType == api.ErrorTypeQuotaExceeded {
+			// Retrying won't help before the quota resets
+			return false
+		}
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	var urlErr *url.Error
+	return errors.As(err, &netErr) || errors.As(err, &urlErr)
+}
+
+// retryAfter parses a Retry-After header given in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
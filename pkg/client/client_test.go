@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	opts = append([]Option{WithRetry(RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})}, opts...)
+	c, err := New(server.URL, opts...)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return c
+}
+
+func TestSearch(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/search" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("query") != "form" || query.Get("mode") != "hybrid" || query.Get("tags") != "go,search" || query.Get("tags_mode") != "all" || query.Get("page") != "" {
+			t.Errorf("Unexpected parameters: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"success":true,"data":{"documents":[{"document":{"id":7,"title":"Form"},"score":1.5}],"total":1,"page":1,"mode":"hybrid"}}`))
+	})
+
+	response, err := c.Search(context.Background(), SearchRequest{Query: "form", Mode: "hybrid", Tags: []string{"go", "search"}, MatchAll: true})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if response.Total != 1 || response.Documents[0].Document.ID != 7 || response.Documents[0].Score != 1.5 {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+}
+
+func TestPutTemplate(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var template api.QueryTemplate
+		if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+			t.Errorf("Failed to decode body: %v", err)
+		}
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" || template.Name != "by-tag" || template.Params["tag"].Default != nil {
+			t.Errorf("Unexpected request %s %s: %+v", r.Method, r.Header.Get("Content-Type"), template)
+		}
+		w.Write([]byte(`{"success":true,"data":{"name":"by-tag","query":"go","tags":"{{tag}}"}}`))
+	})
+
+	response, err := c.PutTemplate(context.Background(), api.QueryTemplate{Name: "by-tag", Query: "go", Tags: "{{tag}}", Params: map[string]api.QueryTemplateParam{"tag": {}}})
+	if err != nil {
+		t.Fatalf("PutTemplate failed: %v", err)
+	}
+	if response.Name != "by-tag" || response.Tags != "{{tag}}" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	var attempts atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"success":false,"error":"Search backend is temporarily unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"data":{"status":"ok","manticore_healthy":true}}`))
+	})
+
+	response, err := c.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Expected the status request to succeed after retries, got %v", err)
+	}
+	if !response.ManticoreHealthy || attempts.Load() != 3 {
+		t.Errorf("Unexpected response %+v after %d attempts", response, attempts.Load())
+	}
+
+	// Creating a saved search is not safe to repeat
+	attempts.Store(0)
+	_, err = c.CreateSavedSearch(context.Background(), SavedSearchRequest{Query: "form"})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || attempts.Load() != 1 {
+		t.Errorf("Expected a single failed attempt, got %v after %d attempts", err, attempts.Load())
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var attempts atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"success":false,"error":"Document 9 not found"}`))
+	})
+
+	_, err := c.ArchiveDocument(context.Background(), 9)
+	if !IsNotFound(err) || attempts.Load() != 1 {
+		t.Errorf("Expected a single not found error, got %v after %d attempts", err, attempts.Load())
+	}
+	if err.Error() != "client: HTTP 404: Document 9 not found" {
+		t.Errorf("Unexpected message: %v", err)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"success":false,"error":"Invalid request parameters: limit must be between 1 and 100, got \"500\"","data":{"error_type":"validation_failed","errors":[{"field":"limit","code":"out_of_range","message":"limit must be between 1 and 100, got \"500\"","value":"500","min":1,"max":100}]}}`))
+	})
+
+	_, err := c.ArchiveDocument(context.Background(), 9)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || len(apiErr.FieldErrors) != 1 {
+		t.Fatalf("Expected an error with field errors, got %v", err)
+	}
+	if fieldErr := apiErr.FieldErrors[0]; fieldErr.Field != "limit" || fieldErr.Code != "out_of_range" || *fieldErr.Max != 100 {
+		t.Errorf("Unexpected field error: %+v", fieldErr)
+	}
+}
+
+func TestQuotaExceededIsNotRetried(t *testing.T) {
+	var attempts atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		if r.Header.Get("X-API-Key") != "secret" {
+			t.Errorf("Expected the API key header, got %q", r.Header.Get("X-API-Key"))
+		}
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"success":false,"error":"Daily searches quota exceeded","data":{"error_type":"quota_exceeded","key":"ci","metric":"searches","limit":10,"used":10,"resets_at":"2026-01-02T00:00:00Z"}}`))
+	}, WithAPIKey("secret"))
+
+	_, err := c.Search(context.Background(), SearchRequest{Query: "go"})
+	if !IsQuotaExceeded(err) {
+		t.Fatalf("Expected a quota exceeded error, got %v", err)
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("Expected a single attempt, got %d", attempts.Load())
+	}
+}
+
+func TestContinue(t *testing.T) {
+	var pending atomic.Bool
+	pending.Store(true)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("token") != "abc" || r.URL.Query().Get("wait") != "2s" {
+			t.Errorf("Unexpected parameters: %s", r.URL.RawQuery)
+		}
+		if pending.Load() {
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"success":true,"data":{"continuation":"abc","pending":true}}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"data":{"documents":[],"total":0,"page":1,"mode":"hybrid"}}`))
+	})
+
+	if _, err := c.Continue(context.Background(), "abc", 2*time.Second); !errors.Is(err, ErrPending) {
+		t.Fatalf("Expected ErrPending, got %v", err)
+	}
+	pending.Store(false)
+	response, err := c.Continue(context.Background(), "abc", 2*time.Second)
+	if err != nil || response.Mode != "hybrid" {
+		t.Errorf("Expected the final ranking, got %+v, %v", response, err)
+	}
+}
+
+func TestNewRejectsInvalidURL(t *testing.T) {
+	if _, err := New("localhost:8080"); err == nil {
+		t.Error("Expected a URL without scheme to be rejected")
+	}
+}
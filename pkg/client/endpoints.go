@@ -0,0 +1,423 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ad/manticoresearch-go/pkg/api"
+)
+
+// ErrPending is returned by Continue while the final ranking of a progressive search is not ready
+var ErrPending = errors.New("client: final ranking is still pending")
+
+// SearchRequest holds the parameters of GET /api/search. Zero values use the server defaults.
+type SearchRequest struct {
+	Query    string
+	Mode     string // basic, fulltext, vector, hybrid, ai, ai-hybrid or auto
+	Page     int
+	Limit    int
+	Fields   []string // Document fields to return, empty for full documents
+	Statuses []string // Document statuses to search, empty for active documents
+	Sort     string   // relevance or sort keys such as "updated_at:asc:first,relevance"
+	Since    time.Time
+	Tags     []string
+	MatchAll bool // Require every tag instead of any of them
+	// IDs restricts results to these documents, such as a candidate set to re-rank, and ExcludeIDs
+	// hides documents, such as ones already shown
+	IDs        []int64
+	ExcludeIDs []int64
+	// Regex is a field and an RE2 pattern its value must match, such as url:^https://go\.dev/
+	Regex string
+	// Histograms are field:interval facets counting results by numeric attribute, such as
+	// "updated_at:day"
+	Histograms []string
+	// Progressive makes hybrid and AI searches return a full-text preview with a continuation
+	// token for Continue
+	Progressive bool
+	// RescoreWindow is the number of candidates each hybrid leg contributes before fusion, 0 for
+	// the server default
+	RescoreWindow int
+	// KNNK is the least number of nearest neighbours AI searches find, 0 for the server default
+	KNNK int
+	// KNNEf is the HNSW candidate list size of AI searches, 0 for the server default
+	KNNEf int
+	// Language selects the stopwords of the query, such as "en", instead of detecting its language
+	Language string
+	// Debug adds how the query was processed to the response
+	Debug bool
+	// Dedup collapses near-duplicate results of a page, keeping the highest-scored one
+	Dedup bool
+}
+
+func (r SearchRequest) values() url.Values {
+	params := url.Values{}
+	params.Set("query", r.Query)
+	setNonEmpty(params, "mode", r.Mode)
+	setPositive(params, "page", r.Page)
+	setPositive(params, "limit", r.Limit)
+	setNonEmpty(params, "fields", strings.Join(r.Fields, ","))
+	setNonEmpty(params, "status", strings.Join(r.Statuses, ","))
+	setNonEmpty(params, "sort", r.Sort)
+	if !r.Since.IsZero() {
+		params.Set("since", strconv.FormatInt(r.Since.Unix(), 10))
+	}
+	setNonEmpty(params, "tags", strings.Join(r.Tags, ","))
+	if r.MatchAll {
+		params.Set("tags_mode", "all")
+	}
+	setNonEmpty(params, "ids", joinIDs(r.IDs))
+	setNonEmpty(params, "exclude_ids", joinIDs(r.ExcludeIDs))
+	setNonEmpty(params, "regex", r.Regex)
+	setNonEmpty(params, "histogram", strings.Join(r.Histograms, ","))
+	if r.Progressive {
+		params.Set("progressive", "true")
+	}
+	setPositive(params, "rescore_window", r.RescoreWindow)
+	setPositive(params, "knn_k", r.KNNK)
+	setPositive(params, "knn_ef", r.KNNEf)
+	setNonEmpty(params, "language", r.Language)
+	if r.Debug {
+		params.Set("debug", "true")
+	}
+	if r.Dedup {
+		params.Set("dedup", "true")
+	}
+	return params
+}
+
+// Search runs a search
+func (c *Client) Search(ctx context.Context, req SearchRequest) (*api.SearchResponse, error) {
+	var response api.SearchResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/search", params: req.values(), retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Continue collects the final ranking of a progressive search, waiting up to wait (at most 30s,
+// zero for the server default of 10s). It returns ErrPending when the ranking is not ready yet,
+// and an error for which IsNotFound is true once the token was collected or expired.
+func (c *Client) Continue(ctx context.Context, token string, wait time.Duration) (*api.SearchResponse, error) {
+	params := url.Values{"token": {token}}
+	if wait > 0 {
+		params.Set("wait", wait.String())
+	}
+
+	var data json.RawMessage
+	statusCode, err := c.do(ctx, request{method: http.MethodGet, path: "/api/search/continue", params: params, retryable: true}, &data)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode == http.StatusAccepted {
+		return nil, ErrPending
+	}
+
+	var response api.SearchResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("client: failed to decode response data: %v", err)
+	}
+	return &response, nil
+}
+
+// CountRequest holds the parameters of GET /api/count
+type CountRequest struct {
+	Query    string
+	Filters  map[string]string // Field values, such as {"title": "form"}
+	Statuses []string
+	Tags     []string
+	MatchAll bool
+}
+
+// Count returns the number of documents matching a query
+func (c *Client) Count(ctx context.Context, req CountRequest) (*api.CountResponse, error) {
+	params := url.Values{}
+	setNonEmpty(params, "query", req.Query)
+	for field, value := range req.Filters {
+		params.Add("filter", field+":"+value)
+	}
+	setNonEmpty(params, "status", strings.Join(req.Statuses, ","))
+	setNonEmpty(params, "tags", strings.Join(req.Tags, ","))
+	if req.MatchAll {
+		params.Set("tags_mode", "all")
+	}
+
+	var response api.CountResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/count", params: params, retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Status returns the health of the server and of Manticore
+func (c *Client) Status(ctx context.Context) (*api.StatusResponse, error) {
+	var response api.StatusResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/status", retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// IndexStats returns document, vocabulary and storage statistics of the index
+func (c *Client) IndexStats(ctx context.Context) (*api.IndexStatsResponse, error) {
+	var response api.IndexStatsResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/stats/index", retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AIModels returns the embedding models AI search uses and whether a test AI search succeeds
+func (c *Client) AIModels(ctx context.Context) (*api.AIModelsResponse, error) {
+	var response api.AIModelsResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/ai/models", retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Terms returns up to limit words of the server's TF-IDF vocabulary starting with prefix, the
+// most common first. An empty prefix lists the whole vocabulary and a limit of 0 the server's default.
+func (c *Client) Terms(ctx context.Context, prefix string, limit int) (*api.TermStatsResponse, error) {
+	params := url.Values{}
+	if prefix != "" {
+		params.Set("prefix", prefix)
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	var response api.TermStatsResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/stats/terms", params: params, retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// PopularQueries returns up to limit queries searched with results that start with prefix, the
+// most searched first. An empty prefix lists every query and a limit of 0 the server's default.
+func (c *Client) PopularQueries(ctx context.Context, prefix string, limit int) (*api.PopularSuggestionsResponse, error) {
+	params := url.Values{}
+	if prefix != "" {
+		params.Set("prefix", prefix)
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	var response api.PopularSuggestionsResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/suggest/popular", params: params, retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Reindex reloads the documents of the server's data directory and rebuilds the index
+func (c *Client) Reindex(ctx context.Context) (*api.ReindexResponse, error) {
+	var response api.ReindexResponse
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api/reindex", retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Usage returns the caller's usage of the day and the daily quotas
+func (c *Client) Usage(ctx context.Context) (*api.UsageResponse, error) {
+	var response api.UsageResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/usage", retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetDocument returns a document whatever its status
+func (c *Client) GetDocument(ctx context.Context, id int64) (*api.Document, error) {
+	var response api.Document
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/documents/" + strconv.FormatInt(id, 10), retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ArchiveDocument hides a document from searches while keeping it for history
+func (c *Client) ArchiveDocument(ctx context.Context, id int64) (*api.DocumentStatusResponse, error) {
+	return c.documentStatus(ctx, "/api/documents/archive", id)
+}
+
+// RestoreDocument makes an archived or deleted document searchable again
+func (c *Client) RestoreDocument(ctx context.Context, id int64) (*api.DocumentStatusResponse, error) {
+	return c.documentStatus(ctx, "/api/documents/restore", id)
+}
+
+// DeleteDocument soft deletes a document, which RestoreDocument can undo
+func (c *Client) DeleteDocument(ctx context.Context, id int64) (*api.DocumentStatusResponse, error) {
+	return c.documentStatus(ctx, "/api/documents/delete", id)
+}
+
+func (c *Client) documentStatus(ctx context.Context, path string, id int64) (*api.DocumentStatusResponse, error) {
+	params := url.Values{"id": {strconv.FormatInt(id, 10)}}
+
+	var response api.DocumentStatusResponse
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: path, params: params, retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SetDocumentTags replaces the tags of a document; no tags removes them
+func (c *Client) SetDocumentTags(ctx context.Context, id int64, tags []string) (*api.DocumentTagsResponse, error) {
+	params := url.Values{"id": {strconv.FormatInt(id, 10)}, "tags": {strings.Join(tags, ",")}}
+
+	var response api.DocumentTagsResponse
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api/documents/tags", params: params, retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SavedSearchRequest holds the parameters of a new saved search
+type SavedSearchRequest struct {
+	Name     string // Defaults to the query
+	Query    string
+	Mode     string
+	Statuses []string
+	Tags     []string
+	MatchAll bool
+	Webhook  string // Receives alerts about new results
+	Email    string
+}
+
+// ListSavedSearches returns every saved search
+func (c *Client) ListSavedSearches(ctx context.Context) (*api.SavedSearchListResponse, error) {
+	var response api.SavedSearchListResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/saved-searches", retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CreateSavedSearch saves a search that the server reruns to alert on new results. It is not
+// retried, so a failure never creates the saved search twice.
+func (c *Client) CreateSavedSearch(ctx context.Context, req SavedSearchRequest) (*api.SavedSearch, error) {
+	params := url.Values{}
+	params.Set("query", req.Query)
+	setNonEmpty(params, "name", req.Name)
+	setNonEmpty(params, "mode", req.Mode)
+	setNonEmpty(params, "status", strings.Join(req.Statuses, ","))
+	setNonEmpty(params, "tags", strings.Join(req.Tags, ","))
+	if req.MatchAll {
+		params.Set("tags_mode", "all")
+	}
+	setNonEmpty(params, "webhook", req.Webhook)
+	setNonEmpty(params, "email", req.Email)
+
+	var response api.SavedSearch
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api/saved-searches", params: params}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteSavedSearch deletes a saved search
+func (c *Client) DeleteSavedSearch(ctx context.Context, id int64) (*api.SavedSearchDeleteResponse, error) {
+	params := url.Values{"id": {strconv.FormatInt(id, 10)}}
+
+	var response api.SavedSearchDeleteResponse
+	if _, err := c.do(ctx, request{method: http.MethodDelete, path: "/api/saved-searches", params: params, retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListTemplates returns every query template
+func (c *Client) ListTemplates(ctx context.Context) (*api.QueryTemplateListResponse, error) {
+	var response api.QueryTemplateListResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/templates", retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// PutTemplate creates a query template or replaces the one with the same name. Only the name,
+// description, search parameters, limit and params of template are sent.
+func (c *Client) PutTemplate(ctx context.Context, template api.QueryTemplate) (*api.QueryTemplate, error) {
+	template.CreatedAt, template.UpdatedAt = time.Time{}, time.Time{}
+	body, err := json.Marshal(template)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to encode template: %v", err)
+	}
+
+	var response api.QueryTemplate
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api/templates", body: body, retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteTemplate deletes a query template
+func (c *Client) DeleteTemplate(ctx context.Context, name string) (*api.QueryTemplateDeleteResponse, error) {
+	params := url.Values{"name": {name}}
+
+	var response api.QueryTemplateDeleteResponse
+	if _, err := c.do(ctx, request{method: http.MethodDelete, path: "/api/templates", params: params, retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// TemplateSearchRequest holds the parameters of a search with a query template
+type TemplateSearchRequest struct {
+	Template    string
+	Params      map[string]string // Values of the template's placeholders
+	Page        int
+	Limit       int // Ignored when the template fixes the limit
+	Progressive bool
+}
+
+// SearchTemplate runs a search with a query template
+func (c *Client) SearchTemplate(ctx context.Context, req TemplateSearchRequest) (*api.SearchResponse, error) {
+	params := url.Values{"template": {req.Template}}
+	if len(req.Params) > 0 {
+		data, err := json.Marshal(req.Params)
+		if err != nil {
+			return nil, fmt.Errorf("client: failed to encode template params: %v", err)
+		}
+		params.Set("params", string(data))
+	}
+	setPositive(params, "page", req.Page)
+	setPositive(params, "limit", req.Limit)
+	if req.Progressive {
+		params.Set("progressive", "true")
+	}
+
+	var response api.SearchResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/search", params: params, retryable: true}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func setNonEmpty(params url.Values, key, value string) {
+	if value != "" {
+		params.Set(key, value)
+	}
+}
+
+// joinIDs formats document ids as a comma-separated list
+func joinIDs(ids []int64) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(values, ",")
+}
+
+func setPositive(params url.Values, key string, value int) {
+	if value > 0 {
+		params.Set(key, strconv.Itoa(value))
+	}
+}
@@ -74,7 +74,9 @@ const config = {
         fulltext: 'Полнотекстовый поиск', 
         vector: 'Векторный поиск',
         hybrid: 'Гибридный поиск',
-        ai: 'AI Search (Semantic)'
+        ai: 'AI Search (Semantic)',
+        'ai-hybrid': 'AI Hybrid Search',
+        auto: 'Автоматический выбор'
     }
 };
 
@@ -147,6 +149,77 @@ async function searchDocuments(query, mode = 'basic', page = 1, limit = 10) {
     return makeAPIRequest(`/search?${params}`);
 }
 
+// ===== Streaming Search (WebSocket) =====
+const stream = {
+    socket: null,
+    ready: null,
+    pending: new Map(),
+    nextId: 1
+};
+
+function openSearchStream() {
+    if (stream.ready) {
+        return stream.ready;
+    }
+
+    stream.ready = new Promise((resolve, reject) => {
+        const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+        const socket = new WebSocket(`${protocol}//${window.location.host}${config.API_BASE_URL}/ws`);
+
+        socket.onopen = () => {
+            stream.socket = socket;
+            resolve(socket);
+        };
+        socket.onerror = () => reject(new Error('WebSocket connection failed'));
+        socket.onclose = () => {
+            stream.socket = null;
+            stream.ready = null;
+            stream.pending.forEach(pending => pending.reject(new Error('WebSocket connection closed')));
+            stream.pending.clear();
+        };
+        socket.onmessage = (event) => handleStreamMessage(JSON.parse(event.data));
+    });
+    return stream.ready;
+}
+
+function handleStreamMessage(message) {
+    const pending = stream.pending.get(message.id);
+    if (!pending) {
+        return;
+    }
+
+    if (message.type === 'results') {
+        if (message.final) {
+            stream.pending.delete(message.id);
+            pending.resolve(message.results);
+        } else {
+            pending.onPartial(message.results);
+        }
+    } else if (message.type === 'error') {
+        stream.pending.delete(message.id);
+        pending.reject(new Error(message.error));
+    }
+}
+
+// Streams a search over /api/ws: onPartial receives the full-text preview of hybrid searches and the
+// promise resolves with the final results, or with null when a newer search replaced this one
+async function streamSearch(query, mode, page, limit, onPartial) {
+    const socket = await openSearchStream();
+
+    // Only the latest search is shown, so earlier ones are cancelled
+    stream.pending.forEach((pending, id) => {
+        socket.send(JSON.stringify({ type: 'cancel', id }));
+        pending.resolve(null);
+    });
+    stream.pending.clear();
+
+    const id = `search-${stream.nextId++}`;
+    return new Promise((resolve, reject) => {
+        stream.pending.set(id, { resolve, reject, onPartial });
+        socket.send(JSON.stringify({ type: 'search', id, query: query.trim(), mode, page, limit }));
+    });
+}
+
 async function getStatus() {
     return makeAPIRequest('/status');
 }
@@ -314,6 +387,11 @@ function updateAISearchUI() {
             aiOption.textContent = 'AI Search (Semantic)';
         }
     }
+    const aiHybridOption = elements.searchModeSelect.querySelector('option[value="ai-hybrid"]');
+    if (aiHybridOption) {
+        aiHybridOption.disabled = !state.aiSearchEnabled;
+        aiHybridOption.textContent = state.aiSearchEnabled ? 'AI Hybrid Search' : 'AI Hybrid Search (Недоступен)';
+    }
 }
 
 // ===== Results Rendering =====
@@ -396,28 +474,46 @@ async function performSearch(query = state.currentQuery, mode = state.currentMod
     }
     
     setLoadingState(true);
+    let superseded = false;
     
     try {
         state.lastSearchTime = now;
         state.lastSearchSignature = searchSignature;
         
-        const result = await searchDocuments(query, mode, page, state.currentLimit);
+        const showResult = (result) => {
+            // Update state
+            state.currentQuery = query;
+            state.currentMode = mode;
+            state.currentPage = page;
+            state.totalResults = result.total || 0;
+            state.totalPages = Math.max(1, Math.ceil(state.totalResults / state.currentLimit));
+            
+            // Render results with search response metadata
+            renderResults(result.documents || [], result);
+        };
         
-        // Update state
-        state.currentQuery = query;
-        state.currentMode = mode;
-        state.currentPage = page;
-        state.totalResults = result.total || 0;
-        state.totalPages = Math.max(1, Math.ceil(state.totalResults / state.currentLimit));
+        let result;
+        if (mode === 'hybrid' && 'WebSocket' in window) {
+            // Show full-text results while the vector leg is still running
+            result = await streamSearch(query, mode, page, state.currentLimit, showResult)
+                .catch(() => searchDocuments(query, mode, page, state.currentLimit));
+            if (result === null) {
+                superseded = true;
+                return; // Replaced by a newer search, which owns the loading state
+            }
+        } else {
+            result = await searchDocuments(query, mode, page, state.currentLimit);
+        }
         
-        // Render results with search response metadata
-        renderResults(result.documents || [], result);
+        showResult(result);
         
     } catch (error) {
         console.error('Search failed:', error);
         handleSearchError(error, mode, query);
     } finally {
-        setLoadingState(false);
+        if (!superseded) {
+            setLoadingState(false);
+        }
     }
 }
 